@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newBundleCommand returns the bundle command, which resolves the external $refs of a schema
+// and writes the resulting self-contained document
+func newBundleCommand() *cobra.Command {
+	var format, refCacheDir string
+	var offline bool
+	cmd := &cobra.Command{
+		Use:   "bundle [flags] <schema-file> <output-file>",
+		Short: "Resolve the $refs of a schema into a single self-contained document",
+		Long: `Resolves the file and HTTP(S) $refs of an OpenAPI or JSON Schema document into a single
+self-contained document, written as JSON or YAML following the output file extension or
+--format. Referenced schemas become definitions of the document; other referenced objects
+replace their $ref. Use - as the output file to write to stdout.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bundle(args[0], args[1], format, refCacheDir, offline)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Output format: json or yaml (default: from the output file extension)")
+	cmd.Flags().StringVar(&refCacheDir, "ref-cache-dir", "", "Directory used to cache remote $ref documents")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Resolve remote $refs from the cache only and fail if a document is missing")
+	return cmd
+}
+
+// bundle writes the bundled document of a schema to outputFile, or to stdout for -
+func bundle(schemaFile, outputFile, format, refCacheDir string, offline bool) error {
+	if format == "" {
+		format = "json"
+		if strings.HasSuffix(outputFile, ".yaml") || strings.HasSuffix(outputFile, ".yml") {
+			format = "yaml"
+		}
+	}
+
+	document, err := jrpc.Bundle(schemaFile, &jrpc.GeneratorOptions{
+		RefCacheDir: refCacheDir,
+		Offline:     offline,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bundle schema: %w", err)
+	}
+
+	var out bytes.Buffer
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(&out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(document)
+	case "yaml":
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		err = encoder.Encode(document)
+	default:
+		return fmt.Errorf("unsupported format %q: must be json or yaml", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode bundled schema: %w", err)
+	}
+
+	if outputFile == "-" {
+		if _, err := os.Stdout.Write(out.Bytes()); err != nil {
+			return fmt.Errorf("failed to write bundled schema: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(outputFile, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write bundled schema: %w", err)
+	}
+	slog.Info(fmt.Sprintf("Successfully bundled %s into %s", schemaFile, outputFile))
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/spf13/cobra"
+)
+
+// newRootCommand returns the generator command and its subcommands. Errors are logged by
+// main, with the location of those in schema files.
+func newRootCommand() *cobra.Command {
+	var verbose, quiet bool
+	var logFormat string
+	root := &cobra.Command{
+		Use:   "generator",
+		Short: "Generate code from JSON Schema, OpenAPI, OpenRPC, AsyncAPI and Avro schemas",
+		Long: `Generates Go types, servers and clients, and TypeScript, Python, GraphQL, protobuf, Avro, SQL
+and Mermaid definitions from JSON Schema, OpenAPI, OpenRPC, AsyncAPI and Avro schemas, and
+bundles, compares and lints schemas.`,
+		Version:       readBuildInfo().String(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return setupLogging(verbose, quiet, logFormat)
+		},
+	}
+	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Also log debug messages")
+	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log warnings and errors")
+	root.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of the messages written to stderr: text or json")
+	root.SetVersionTemplate("{{.Name}} {{.Version}}\n")
+	// Declared now rather than on execution, so that normalizeArgs finds it
+	root.InitDefaultVersionFlag()
+	root.Flags().Lookup("version").Usage = "Print the version, commit and build date of the generator"
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.AddCommand(
+		newGenerateCommand(),
+		newInitCommand(),
+		newValidateCommand(),
+		newListCommand(),
+		newLintCommand(),
+		newDiffCommand(),
+		newBundleCommand(),
+		newConvertCommand(),
+		newDocsCommand(),
+	)
+	return root
+}
+
+// isLegacyInvocation reports whether args invoke the generator without a command, as in
+// "generator [flags] <schema-file> <output-file>" or "generator -list", rather than asking for
+// the help or the version
+func isLegacyInvocation(root *cobra.Command, args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "-h", "--help", "help":
+		return false
+	}
+	if slices.Contains(args, "--version") || slices.Contains(args, "-version") {
+		return false
+	}
+	cmd, _, err := root.Find(args)
+	return err != nil || cmd == root
+}
+
+// normalizeArgs rewrites the flags of args given with a single dash, as the flag package
+// accepts them (-format json), into the double-dash form of the commands (--format json),
+// so that invocations written for the former commands keep working
+func normalizeArgs(root *cobra.Command, args []string) []string {
+	cmd, _, err := root.Find(args)
+	if err != nil {
+		return args
+	}
+	normalized := make([]string, len(args))
+	for i, arg := range args {
+		normalized[i] = arg
+		if arg == "--" {
+			copy(normalized[i:], args[i:])
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "--") || len(arg) <= 2 {
+			continue
+		}
+		name, _, _ := strings.Cut(arg[1:], "=")
+		if name == "help" || cmd.Flags().Lookup(name) != nil || cmd.InheritedFlags().Lookup(name) != nil {
+			normalized[i] = "-" + arg
+		}
+	}
+	return normalized
+}
+
+// newValidateCommand returns the validate command, which checks that generators accept
+// schemas
+func newValidateCommand() *cobra.Command {
+	var generatorName string
+	cmd := &cobra.Command{
+		Use:   "validate [flags] <schema-file>...",
+		Short: "Check that schemas can be generated from",
+		Long: `Checks that schemas are valid inputs of the generator given by --generator, or of the
+generators supporting their format, naming the generators accepting each of them, and exits
+with status 2 when some schema is accepted by none.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			invalid := 0
+			for _, schemaFile := range args {
+				names, err := validateSchema(generatorName, schemaFile)
+				if err != nil {
+					logError(schemaFileError(schemaFile, err))
+					invalid++
+					continue
+				}
+				fmt.Printf("%s: valid (%s)\n", schemaFile, strings.Join(names, ", "))
+			}
+			if invalid > 0 {
+				return withExitCode(exitValidation, fmt.Errorf("%d of %d schemas are invalid", invalid, len(args)))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&generatorName, "generator", "", "Generator to validate the schemas for (default: the generators supporting their format)")
+	return cmd
+}
+
+// validateSchema returns the names of the generators accepting a schema: the named generator,
+// or those supporting its format. The error is that of the first generator when none does.
+func validateSchema(generatorName, schemaFile string) ([]string, error) {
+	var generators []codegen.Generator
+	if generatorName != "" {
+		generator, err := codegen.Get(generatorName)
+		if err != nil {
+			return nil, err
+		}
+		generators = append(generators, generator)
+	} else {
+		generators = codegen.GetByFormat(schemaFile)
+		if len(generators) == 0 {
+			return nil, fmt.Errorf("no generators found that support file format of %s", schemaFile)
+		}
+	}
+	var names []string
+	var firstErr error
+	for _, g := range generators {
+		if err := g.ValidateSchema(schemaFile); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", g.Name(), err)
+			}
+			continue
+		}
+		names = append(names, g.Name())
+	}
+	if len(names) == 0 {
+		return nil, firstErr
+	}
+	return names, nil
+}
+
+// newListCommand returns the list command, which describes the registered generators
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the available generators",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listGenerators()
+		},
+	}
+}
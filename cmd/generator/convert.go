@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newConvertCommand returns the convert command, which translates schemas between JSON and
+// YAML and normalizes them
+func newConvertCommand() *cobra.Command {
+	var format string
+	var normalize bool
+	cmd := &cobra.Command{
+		Use:   "convert [flags] <schema-file> <output-file>",
+		Short: "Translate a schema between JSON and YAML, and normalize it",
+		Long: `Writes a JSON or YAML document, such as an OpenAPI or JSON Schema document, as JSON or YAML
+following the output file extension or --format, with its YAML anchors, aliases and merge
+keys resolved. With --normalize, the keys of every object are sorted and the numbers written
+in their canonical form (1.0 as 1, 0x1F as 31, 1.50e2 as 150), so that the diffs of a schema
+only show its changes whatever its source formatting. Comments are kept from YAML to YAML.
+Use - as the schema file to read from stdin, and as the output file to write to stdout; the
+output file may be the schema file.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return convert(args[0], args[1], format, normalize)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Output format: json or yaml (default: from the output file extension, or json)")
+	cmd.Flags().BoolVar(&normalize, "normalize", false, "Sort the keys of every object and write numbers in their canonical form")
+	return cmd
+}
+
+// convert writes the document of a file, or of stdin for -, to outputFile, or to stdout for -,
+// in the given format
+func convert(schemaFile, outputFile, format string, normalize bool) error {
+	if format == "" {
+		format = "json"
+		if strings.HasSuffix(outputFile, ".yaml") || strings.HasSuffix(outputFile, ".yml") {
+			format = "yaml"
+		}
+	}
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("unsupported format %q: must be json or yaml", format)
+	}
+
+	var data []byte
+	var err error
+	if schemaFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(schemaFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse schema: %w", codegen.DecodeError(schemaFile, data, err))
+	}
+	if len(document.Content) == 0 {
+		return fmt.Errorf("%s is empty", schemaFile)
+	}
+
+	// The styles of a YAML document are kept, unless it is normalized; JSON documents, whose
+	// strings are all quoted and collections in flow style, are written in the YAML style
+	converter := &documentConverter{
+		normalize:  normalize,
+		keepStyles: !normalize && !strings.HasPrefix(strings.TrimSpace(string(data)), "{") && !strings.HasPrefix(strings.TrimSpace(string(data)), "["),
+		resolving:  make(map[*yaml.Node]bool),
+	}
+	root, err := converter.convert(document.Content[0], "")
+	if err != nil {
+		return codegen.Locate(err, schemaFile)
+	}
+
+	var out bytes.Buffer
+	switch format {
+	case "json":
+		if err := writeJSONNode(&out, root, ""); err != nil {
+			return codegen.Locate(err, schemaFile)
+		}
+		out.WriteByte('\n')
+	case "yaml":
+		root.HeadComment = document.HeadComment + root.HeadComment
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(root); err != nil {
+			return fmt.Errorf("failed to encode schema: %w", err)
+		}
+	}
+
+	if outputFile == "-" {
+		if _, err := os.Stdout.Write(out.Bytes()); err != nil {
+			return fmt.Errorf("failed to write schema: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(outputFile, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	slog.Info(fmt.Sprintf("Successfully converted %s into %s", schemaFile, outputFile))
+	return nil
+}
+
+// documentConverter copies a YAML node tree without its anchors, aliases and merge keys
+type documentConverter struct {
+	normalize  bool                // Whether keys are sorted and numbers canonical
+	keepStyles bool                // Whether the styles of the scalars and collections are kept
+	resolving  map[*yaml.Node]bool // Anchored nodes being copied, to detect recursive aliases
+}
+
+// convert returns a copy of node, at the JSON pointer pointer, with its aliases replaced by
+// copies of the nodes they refer to
+func (c *documentConverter) convert(node *yaml.Node, pointer string) (*yaml.Node, error) {
+	converted := &yaml.Node{
+		Kind:        node.Kind,
+		Tag:         node.ShortTag(),
+		Value:       node.Value,
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+	}
+	if c.keepStyles {
+		converted.Style = node.Style &^ yaml.FlowStyle
+		if node.Kind == yaml.ScalarNode {
+			converted.Style = node.Style
+		}
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		if c.resolving[node.Alias] {
+			return nil, &codegen.SchemaError{Pointer: pointer, Err: fmt.Errorf("alias *%s refers to a node containing it", node.Value)}
+		}
+		c.resolving[node.Alias] = true
+		defer delete(c.resolving, node.Alias)
+		return c.convert(node.Alias, pointer)
+
+	case yaml.ScalarNode:
+		if c.normalize && (converted.Tag == "!!int" || converted.Tag == "!!float") {
+			if value, ok := canonicalNumber(converted.Tag, node.Value); ok {
+				converted.Value = value
+				// Whole floats lose their fraction, and would be tagged !!float in YAML otherwise;
+				// integers overflowing int64 are floats to YAML, and stay tagged so
+				if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+					converted.Tag = "!!int"
+				}
+			}
+		}
+
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			convertedItem, err := c.convert(item, fmt.Sprintf("%s/%d", pointer, i))
+			if err != nil {
+				return nil, err
+			}
+			converted.Content = append(converted.Content, convertedItem)
+		}
+
+	case yaml.MappingNode:
+		pairs, err := c.mappingPairs(node, pointer)
+		if err != nil {
+			return nil, err
+		}
+		if c.normalize && len(pairs) > 0 {
+			// The comment above the first key, such as that of the document, stays first
+			comment := pairs[0][0].HeadComment
+			pairs[0][0].HeadComment = ""
+			sort.SliceStable(pairs, func(i, j int) bool {
+				return pairs[i][0].Value < pairs[j][0].Value
+			})
+			pairs[0][0].HeadComment = strings.TrimPrefix(comment+"\n"+pairs[0][0].HeadComment, "\n")
+		}
+		for _, pair := range pairs {
+			converted.Content = append(converted.Content, pair[0], pair[1])
+		}
+	}
+	return converted, nil
+}
+
+// mappingPairs returns the converted keys and values of a mapping in order, with the
+// members of the mappings merged with << in place of the merge key, unless the mapping sets
+// them itself
+func (c *documentConverter) mappingPairs(node *yaml.Node, pointer string) ([][2]*yaml.Node, error) {
+	explicit := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].ShortTag() != "!!merge" {
+			explicit[node.Content[i].Value] = true
+		}
+	}
+
+	var pairs [][2]*yaml.Node
+	added := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			return nil, &codegen.SchemaError{Pointer: pointer, Err: fmt.Errorf("mapping keys must be strings")}
+		}
+		if key.ShortTag() != "!!merge" {
+			convertedKey, err := c.convert(key, pointer)
+			if err != nil {
+				return nil, err
+			}
+			convertedKey.Tag = "!!str"
+			convertedValue, err := c.convert(value, pointer+"/"+escapeJSONPointer(key.Value))
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, [2]*yaml.Node{convertedKey, convertedValue})
+			added[key.Value] = true
+			continue
+		}
+
+		merged, err := c.convert(value, pointer+"/<<")
+		if err != nil {
+			return nil, err
+		}
+		sources := []*yaml.Node{merged}
+		if merged.Kind == yaml.SequenceNode {
+			sources = merged.Content
+		}
+		for _, source := range sources {
+			if source.Kind != yaml.MappingNode {
+				return nil, &codegen.SchemaError{Pointer: pointer + "/<<", Err: fmt.Errorf("merge keys take a mapping or a list of mappings")}
+			}
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				name := source.Content[j].Value
+				if explicit[name] || added[name] {
+					continue
+				}
+				pairs = append(pairs, [2]*yaml.Node{source.Content[j], source.Content[j+1]})
+				added[name] = true
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// escapeJSONPointer escapes a member name as a JSON pointer token
+func escapeJSONPointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// jsonNumber matches the numbers JSON accepts
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// integerLiteral matches the integers YAML tags as floats because they overflow int64
+var integerLiteral = regexp.MustCompile(`^[-+]?[0-9][0-9_]*$`)
+
+// canonicalNumber returns a YAML integer or float in its canonical form: integers in
+// decimal, exactly, and floats in the shortest form reading back as the same float64,
+// without a fraction when they are whole, and with an exponent below 1e-6 and from 1e21, as
+// JavaScript and RFC 8785 write them. It reports false for infinities and NaN.
+func canonicalNumber(tag, value string) (string, bool) {
+	if tag == "!!int" || integerLiteral.MatchString(value) {
+		var i big.Int
+		if _, ok := i.SetString(strings.TrimPrefix(value, "+"), 0); ok {
+			return i.String(), true
+		}
+		return value, false
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return value, false
+	}
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+		sign := exponent[:1]
+		exponent = strings.TrimLeft(exponent[1:], "0")
+		return mantissa + "e" + sign + exponent, true
+	}
+	if f == 0 {
+		return "0", true
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), true
+}
+
+// writeJSONNode writes a converted node as indented JSON
+func writeJSONNode(out *bytes.Buffer, node *yaml.Node, indent string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			out.WriteString("{}")
+			return nil
+		}
+		out.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			out.WriteString(indent + "  ")
+			writeJSONString(out, node.Content[i].Value)
+			out.WriteString(": ")
+			if err := writeJSONNode(out, node.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+			if i+2 < len(node.Content) {
+				out.WriteByte(',')
+			}
+			out.WriteByte('\n')
+		}
+		out.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			out.WriteString("[]")
+			return nil
+		}
+		out.WriteString("[\n")
+		for i, item := range node.Content {
+			out.WriteString(indent + "  ")
+			if err := writeJSONNode(out, item, indent+"  "); err != nil {
+				return err
+			}
+			if i+1 < len(node.Content) {
+				out.WriteByte(',')
+			}
+			out.WriteByte('\n')
+		}
+		out.WriteString(indent + "]")
+	default:
+		switch node.Tag {
+		case "!!null":
+			out.WriteString("null")
+		case "!!bool":
+			var value bool
+			if err := node.Decode(&value); err != nil {
+				return err
+			}
+			out.WriteString(strconv.FormatBool(value))
+		case "!!int", "!!float":
+			value := node.Value
+			if !jsonNumber.MatchString(value) {
+				var ok bool
+				if value, ok = canonicalNumber(node.Tag, node.Value); !ok {
+					return fmt.Errorf("number %s cannot be written as JSON", node.Value)
+				}
+			}
+			out.WriteString(value)
+		default:
+			writeJSONString(out, node.Value)
+		}
+	}
+	return nil
+}
+
+// writeJSONString writes a JSON string, without escaping HTML characters
+func writeJSONString(out *bytes.Buffer, value string) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	out.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"github.com/inference-gateway/tools/codegen/openapi"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// diffReport is the JSON output of the diff command
+type diffReport struct {
+	Breaking int           `json:"breaking"` // Number of breaking changes
+	Changes  []jrpc.Change `json:"changes"`
+}
+
+// newDiffCommand returns the diff command, which reports the changes between two versions of
+// a schema and exits with status 1 when some of them are breaking
+func newDiffCommand() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "diff [flags] <old-schema-file> <new-schema-file>",
+		Short: "Report the changes between two versions of a schema",
+		Long: `Reports the changes between two versions of an OpenAPI or JSON Schema document, as text or
+as JSON with --format json, and exits with status 1 when some are breaking: removed
+definitions, properties, operations, parameters or responses, changed types or formats,
+narrowed enums and newly required properties, parameters or request bodies.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diff(args[0], args[1], format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	return cmd
+}
+
+// diff writes the changes from oldFile to newFile, exiting with status 1 when some of them are
+// breaking
+func diff(oldFile, newFile, format string) error {
+	var changes []jrpc.Change
+	if isOpenAPIFile(oldFile) || isOpenAPIFile(newFile) {
+		oldDocument, err := openapi.LoadDocument(oldFile)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", oldFile, err)
+		}
+		newDocument, err := openapi.LoadDocument(newFile)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", newFile, err)
+		}
+		changes = openapi.Diff(oldDocument, newDocument)
+	} else {
+		var err error
+		if changes, err = jrpc.DiffSchemaFiles(oldFile, newFile); err != nil {
+			return fmt.Errorf("failed to compare schemas: %w", err)
+		}
+	}
+
+	report := diffReport{Changes: changes}
+	if report.Changes == nil {
+		report.Changes = []jrpc.Change{}
+	}
+	for _, change := range changes {
+		if change.Breaking {
+			report.Breaking++
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode changes: %w", err)
+		}
+	case "text":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, change := range changes {
+			severity := ""
+			if change.Breaking {
+				severity = "BREAKING"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", severity, change.Kind, change.Path, change.Message)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write changes: %w", err)
+		}
+		fmt.Printf("%d breaking changes, %d other changes\n", report.Breaking, len(changes)-report.Breaking)
+	default:
+		return fmt.Errorf("unsupported format %q: must be text or json", format)
+	}
+
+	if report.Breaking > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// isOpenAPIFile reports whether a schema file is an OpenAPI or Swagger document
+func isOpenAPIFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return false
+	}
+	return document["openapi"] != nil || document["swagger"] != nil
+}
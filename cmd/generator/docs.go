@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newDocsCommand returns the docs command, which writes the reference documentation of the
+// commands as Markdown
+func newDocsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "docs <output-dir>",
+		Short: "Write the Markdown reference of the commands",
+		Long: `Writes a Markdown page per command into the output directory (generator.md,
+generator_generate.md, ...) with its description, usage, flags and subcommands.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(args[0], 0o755); err != nil {
+				return err
+			}
+			return writeCommandDocs(cmd.Root(), args[0])
+		},
+	}
+}
+
+// writeCommandDocs writes the page of a command and of its subcommands into dir
+func writeCommandDocs(cmd *cobra.Command, dir string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n", cmd.CommandPath(), cmd.Short)
+	if cmd.Long != "" {
+		fmt.Fprintf(&b, "\n%s\n", cmd.Long)
+	}
+	if cmd.Runnable() {
+		fmt.Fprintf(&b, "\n## Usage\n\n```\n%s\n```\n", cmd.UseLine())
+	}
+	if flags := cmd.NonInheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&b, "\n## Flags\n\n```\n%s```\n", flags.FlagUsages())
+	}
+	if flags := cmd.InheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&b, "\n## Global Flags\n\n```\n%s```\n", flags.FlagUsages())
+	}
+
+	var subcommands []*cobra.Command
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() && sub.Name() != "help" {
+			subcommands = append(subcommands, sub)
+		}
+	}
+	if len(subcommands) > 0 {
+		b.WriteString("\n## Commands\n\n")
+		for _, sub := range subcommands {
+			fmt.Fprintf(&b, "- [%s](%s) - %s\n", sub.CommandPath(), docsFile(sub), sub.Short)
+		}
+	}
+	if cmd.HasParent() {
+		fmt.Fprintf(&b, "\n## See Also\n\n- [%s](%s) - %s\n", cmd.Parent().CommandPath(), docsFile(cmd.Parent()), cmd.Parent().Short)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, docsFile(cmd)), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write the documentation of %s: %w", cmd.CommandPath(), err)
+	}
+	for _, sub := range subcommands {
+		if err := writeCommandDocs(sub, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docsFile returns the name of the page of a command: its path joined with underscores
+func docsFile(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".md"
+}
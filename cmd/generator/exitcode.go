@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// Exit statuses of the generator, telling CI why a run failed
+const (
+	exitFailure    = 1 // Other failures: usage, files, outdated code (--check), lint errors, breaking changes
+	exitValidation = 2 // Invalid schema: unreadable, or rejected by the generator
+	exitGeneration = 3 // The generator failed on a valid schema
+	exitFormatting = 4 // The generated code could not be formatted
+	exitWarnings   = 5 // Generation warnings with --strict-warnings
+)
+
+// exitError is an error with the exit status it causes
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error returns the message of the underlying error
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode returns err with the exit status it causes
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit status err causes: that of the formatting or schema error it wraps,
+// if any, whatever stage reported it, or else the status it was given, or exitFailure
+func exitCode(err error) int {
+	var formatErr *codegen.FormatError
+	var schemaErr *codegen.SchemaError
+	var exitErr *exitError
+	switch {
+	case errors.As(err, &formatErr):
+		return exitFormatting
+	case errors.As(err, &schemaErr):
+		return exitValidation
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	}
+	return exitFailure
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the configuration file the generate command reads by default
+const defaultConfigFile = "codegen.yaml"
+
+// repeatableFlags are the flags given once per value; list options of the other flags are
+// joined with commas
+var repeatableFlags = map[string]bool{
+	"tag-template": true, "import-mapping": true, "sql-type": true, "header": true,
+	"pre-parse-hook": true, "post-ir-hook": true, "post-emit-hook": true,
+}
+
+// allTargetsFlags are the generation flags that apply to every target of a configuration file
+// when given to the generate command
+var allTargetsFlags = map[string]bool{"check": true, "dry-run": true}
+
+// generateConfig is the configuration file of the generate command
+type generateConfig struct {
+	Targets []generateTarget `yaml:"targets"`
+}
+
+// generateTarget is a generator run: its schema, generator, output and package, and the
+// other flags of the run by name
+type generateTarget struct {
+	Name      string         `yaml:"name,omitempty"`
+	Schema    string         `yaml:"schema"`
+	Generator string         `yaml:"generator,omitempty"`
+	Output    string         `yaml:"output"`
+	Package   string         `yaml:"package,omitempty"`
+	Options   map[string]any `yaml:"options,omitempty"`
+}
+
+// newGenerateCommand returns the generate command, which generates code from a schema, or
+// runs the targets of a configuration file. Its flags are parsed with the flag package, as
+// the configuration targets are, so that both accept the same flags.
+func newGenerateCommand() *cobra.Command {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", "", "Configuration file listing the generation targets (default: "+defaultConfigFile+" when no schema is given)")
+	var targets []string
+	flags.Func("target", "Name or output of a target of the configuration file to run (repeatable, default: all)", func(value string) error {
+		targets = append(targets, value)
+		return nil
+	})
+	watch := flags.Bool("watch", false, "Regenerate whenever the schema, or the configuration file and the schemas of its targets, change")
+	merge := flags.Bool("merge", false, "Generate the definitions of several schemas into one output file instead of a file per schema in the output directory")
+	// The logging flags of the root command, which does not parse the flags of this one
+	var verbose, quiet bool
+	flags.BoolVar(&verbose, "verbose", false, "Also log debug messages")
+	flags.BoolVar(&verbose, "v", false, "Also log debug messages")
+	flags.BoolVar(&quiet, "quiet", false, "Only log warnings and errors")
+	flags.BoolVar(&quiet, "q", false, "Only log warnings and errors")
+	logFormat := flags.String("log-format", "text", "Format of the messages written to stderr: text or json")
+	run := declareGenerationFlags(flags)
+
+	cmd := &cobra.Command{
+		Use:   "generate [flags] [<schema-file>... <output>]",
+		Short: "Generate code from a schema, or the targets of a configuration file",
+		Long: `Generates code from a schema into an output file with the generator given by --generator
+or detected from the schema and output files. Several schemas, or glob patterns of schema
+files, are generated into an output directory with a file per schema, or into one output
+file with --merge. Without files, runs the targets of a configuration file (--config,
+default ` + defaultConfigFile + `), or those named with --target.`,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.Parse(args); err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					return cmd.Help()
+				}
+				return err
+			}
+			if err := setupLogging(verbose, quiet, *logFormat); err != nil {
+				return err
+			}
+			if *watch && (flags.Lookup("check").Value.String() == "true" || flags.Lookup("dry-run").Value.String() == "true") {
+				return fmt.Errorf("--watch regenerates the output and cannot be combined with --check or --dry-run")
+			}
+			if flags.NArg() == 0 {
+				var generationFlags, targetArgs []string
+				flags.Visit(func(f *flag.Flag) {
+					switch {
+					case allTargetsFlags[f.Name]:
+						targetArgs = append(targetArgs, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+					case !slices.Contains([]string{"config", "target", "watch", "verbose", "v", "quiet", "q", "log-format"}, f.Name):
+						generationFlags = append(generationFlags, "--"+f.Name)
+					}
+				})
+				if len(generationFlags) > 0 {
+					return fmt.Errorf("%s: generation flags apply to a schema given as argument, set them as options of the targets", strings.Join(generationFlags, ", "))
+				}
+				if *configFile == "" {
+					*configFile = defaultConfigFile
+				}
+				if *watch {
+					return watchTargets(*configFile, targets)
+				}
+				return runTargets(*configFile, targets, targetArgs)
+			}
+			if flags.NArg() < 2 {
+				return fmt.Errorf("expected <schema-file> <output-file>, or no arguments to run the targets of %s", defaultConfigFile)
+			}
+			if *configFile != "" || len(targets) > 0 {
+				return fmt.Errorf("--config and --target run the targets of a configuration file and take no schema")
+			}
+			schemaArgs, outputFile := flags.Args()[:flags.NArg()-1], flags.Arg(flags.NArg()-1)
+			if len(schemaArgs) > 1 || isSchemaGlob(schemaArgs[0]) || *merge {
+				schemas, err := expandSchemas(schemaArgs)
+				if err != nil {
+					return err
+				}
+				if !*watch {
+					return generateSchemas(flags, schemas, outputFile, *merge, run)
+				}
+				// The directories of the patterns are watched too, for the schema files added to them
+				return watchAndRegenerate(func() ([]string, []string, error) {
+					var inputs []string
+					for _, arg := range schemaArgs {
+						if dir := filepath.Dir(arg); isSchemaGlob(arg) && !isSchemaGlob(dir) {
+							inputs = append(inputs, dir)
+						}
+					}
+					schemas, err = expandSchemas(schemaArgs)
+					for _, schema := range schemas {
+						if !isSchemaURL(schema) {
+							inputs = append(inputs, schema)
+						}
+					}
+					return inputs, []string{outputFile}, err
+				}, func() error {
+					return generateSchemas(flags, schemas, outputFile, *merge, run)
+				})
+			}
+			schemaFile := schemaArgs[0]
+			if *watch {
+				if schemaFile == "-" || isSchemaURL(schemaFile) {
+					return fmt.Errorf("--watch follows schema files, not schemas read from stdin or downloaded")
+				}
+				return watchAndRegenerate(func() ([]string, []string, error) {
+					return []string{schemaFile}, []string{outputFile}, nil
+				}, func() error {
+					return run(schemaFile, outputFile, "")
+				})
+			}
+			return run(schemaFile, outputFile, "")
+		},
+	}
+	cmd.Flags().AddGoFlagSet(flags)
+	cmd.SetHelpFunc(func(*cobra.Command, []string) {
+		showDetailedHelp()
+	})
+	return cmd
+}
+
+// runTargets runs the targets of a configuration file with the given names or outputs (all
+// of them when there is none) in order, with the extra generation arguments, stopping at the
+// first that fails. Outdated targets of check mode are all reported before failing.
+func runTargets(configFile string, names []string, extraArgs []string) error {
+	config, err := loadGenerateConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", configFile, err)
+	}
+	targets, err := config.selectTargets(names)
+	if err != nil {
+		return err
+	}
+
+	// Running from the directory of the configuration file resolves the schema, output and
+	// option paths, and finds the naming rules file next to it
+	if err := os.Chdir(filepath.Dir(configFile)); err != nil {
+		return err
+	}
+	var outdated []string
+	for _, target := range targets {
+		targetArgs, err := target.args()
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target.label(), err)
+		}
+		slog.Debug("Running target "+target.label(), "args", strings.Join(targetArgs, " "))
+		targetFlags := flag.NewFlagSet(target.label(), flag.ContinueOnError)
+		targetFlags.SetOutput(io.Discard)
+		if err := generate(targetFlags, append(slices.Clone(extraArgs), targetArgs...)); err != nil {
+			if errors.Is(err, errOutdated) {
+				outdated = append(outdated, target.label())
+				continue
+			}
+			return fmt.Errorf("target %s: %w", target.label(), err)
+		}
+	}
+	if len(outdated) > 0 {
+		return fmt.Errorf("%w: targets %s", errOutdated, strings.Join(outdated, ", "))
+	}
+	return nil
+}
+
+// watchTargets runs the targets of a configuration file like runTargets, then again whenever
+// the configuration file or the schema of a target changes
+func watchTargets(configFile string, names []string) error {
+	if err := os.Chdir(filepath.Dir(configFile)); err != nil {
+		return err
+	}
+	configFile = filepath.Base(configFile)
+	return watchAndRegenerate(func() ([]string, []string, error) {
+		inputs := []string{configFile}
+		config, err := loadGenerateConfig(configFile)
+		if err != nil {
+			return inputs, nil, fmt.Errorf("failed to load %s: %w", configFile, err)
+		}
+		targets, err := config.selectTargets(names)
+		if err != nil {
+			return inputs, nil, err
+		}
+		var outputs []string
+		for _, target := range targets {
+			if !isSchemaURL(target.Schema) {
+				inputs = append(inputs, target.Schema)
+			}
+			outputs = append(outputs, target.Output)
+		}
+		return inputs, outputs, nil
+	}, func() error {
+		return runTargets(configFile, names, nil)
+	})
+}
+
+// loadGenerateConfig reads a configuration file, rejecting unknown keys and targets without
+// a schema or output
+func loadGenerateConfig(path string) (*generateConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var config generateConfig
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("no targets")
+	}
+	names := map[string]bool{}
+	for i, target := range config.Targets {
+		if target.Schema == "" || target.Output == "" {
+			return nil, fmt.Errorf("target %d (%s) needs a schema and an output", i+1, target.label())
+		}
+		if target.Name != "" {
+			if names[target.Name] {
+				return nil, fmt.Errorf("duplicate target name %q", target.Name)
+			}
+			names[target.Name] = true
+		}
+	}
+	return &config, nil
+}
+
+// selectTargets returns the targets with the given names or outputs, in file order, or every
+// target when no name is given
+func (c *generateConfig) selectTargets(names []string) ([]generateTarget, error) {
+	if len(names) == 0 {
+		return c.Targets, nil
+	}
+	var targets []generateTarget
+	for _, name := range names {
+		found := false
+		for _, target := range c.Targets {
+			found = found || target.Name == name || target.Output == name
+		}
+		if !found {
+			return nil, fmt.Errorf("no target named %q", name)
+		}
+	}
+	for _, target := range c.Targets {
+		if slices.Contains(names, target.Name) || slices.Contains(names, target.Output) {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// label returns the name of the target in messages: its name, or else its output
+func (t generateTarget) label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Output
+}
+
+// args returns the command-line arguments of the target: a flag per option, then the schema
+// and output files. Lists are given as repeated flags or joined with commas, and maps of the
+// repeatable flags as key=value flags.
+func (t generateTarget) args() ([]string, error) {
+	var args []string
+	if t.Generator != "" {
+		args = append(args, "-generator", t.Generator)
+	}
+	if t.Package != "" {
+		args = append(args, "-package", t.Package)
+	}
+	names := make([]string, 0, len(t.Options))
+	for name := range t.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch value := t.Options[name].(type) {
+		case nil:
+			return nil, fmt.Errorf("option %s has no value", name)
+		case []any:
+			values := make([]string, len(value))
+			for i, v := range value {
+				values[i] = fmt.Sprint(v)
+			}
+			if repeatableFlags[name] {
+				for _, v := range values {
+					args = append(args, "-"+name, v)
+				}
+			} else {
+				args = append(args, "-"+name, strings.Join(values, ","))
+			}
+		case map[string]any:
+			if !repeatableFlags[name] {
+				return nil, fmt.Errorf("option %s does not take a map", name)
+			}
+			keys := make([]string, 0, len(value))
+			for key := range value {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				args = append(args, "-"+name, fmt.Sprintf("%s=%v", key, value[key]))
+			}
+		default:
+			args = append(args, fmt.Sprintf("-%s=%v", name, value))
+		}
+	}
+	return append(args, t.Schema, t.Output), nil
+}
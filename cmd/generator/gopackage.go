@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// modulePackage is the package of a Go module generated code is written to, with -go-package-dir
+// or -module-path
+type modulePackage struct {
+	dir        string   // Directory of the package
+	name       string   // Name of the package: that of its Go files, or derived from the directory
+	importPath string   // Import path of the package
+	moduleRoot string   // Directory of the go.mod of the module
+	modulePath string   // Import path of the module
+	requires   []string // Module paths the go.mod requires
+}
+
+// outputPackageDir returns the directory of the package an output is written to: the output
+// directory with -split, the current directory for stdout, or else the directory of the file
+func outputPackageDir(outputFile string, split bool) string {
+	switch {
+	case outputFile == "-":
+		return "."
+	case split:
+		return outputFile
+	}
+	return filepath.Dir(outputFile)
+}
+
+// locateGoPackage returns the package of a directory within the module of the closest go.mod
+// above it, checking that the module is modulePath when given
+func locateGoPackage(dir, modulePath string) (*modulePackage, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &modulePackage{dir: dir}
+
+	for root := absDir; ; root = filepath.Dir(root) {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			file, err := modfile.ParseLax(filepath.Join(root, "go.mod"), data, nil)
+			if err != nil {
+				return nil, err
+			}
+			if file.Module == nil {
+				return nil, fmt.Errorf("%s declares no module", filepath.Join(root, "go.mod"))
+			}
+			pkg.moduleRoot, pkg.modulePath = root, file.Module.Mod.Path
+			for _, require := range file.Require {
+				pkg.requires = append(pkg.requires, require.Mod.Path)
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if filepath.Dir(root) == root {
+			return nil, fmt.Errorf("%s is not inside a Go module: no go.mod found above it", dir)
+		}
+	}
+	if modulePath != "" && modulePath != pkg.modulePath {
+		return nil, fmt.Errorf("%s is in module %s (%s), not %s", dir, pkg.modulePath, filepath.Join(pkg.moduleRoot, "go.mod"), modulePath)
+	}
+
+	rel, err := filepath.Rel(pkg.moduleRoot, absDir)
+	if err != nil {
+		return nil, err
+	}
+	pkg.importPath = path.Join(pkg.modulePath, filepath.ToSlash(rel))
+
+	if pkg.name, err = packageClause(dir); err != nil {
+		return nil, err
+	}
+	if pkg.name == "" {
+		pkg.name = goPackageName(path.Base(pkg.importPath))
+	}
+	return pkg, nil
+}
+
+// packageClause returns the package name of the first Go file of a directory other than the
+// tests, or "" when it has none
+func packageClause(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		return file.Name.Name, nil
+	}
+	return "", nil
+}
+
+// samePath reports whether two paths name the same directory
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// checkImports checks that the imports of generated Go files resolve in the module: the
+// standard library, packages of the module, and packages of the modules its go.mod requires.
+// The output directory of -split is checked file by file.
+func (p *modulePackage) checkImports(output string) error {
+	files := []string{output}
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(output, "*.go"))
+		if err != nil {
+			return err
+		}
+		files = matches
+	}
+
+	var unresolved []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range parsed.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || seen[importPath] {
+				continue
+			}
+			seen[importPath] = true
+			if reason := p.resolveImport(importPath); reason != "" {
+				unresolved = append(unresolved, fmt.Sprintf("%s (%s)", importPath, reason))
+			}
+		}
+	}
+	if len(unresolved) > 0 {
+		return fmt.Errorf("the imports of the generated code do not resolve in module %s: %s", p.modulePath, strings.Join(unresolved, ", "))
+	}
+	return nil
+}
+
+// resolveImport returns why an import path does not resolve in the module, or ""
+func (p *modulePackage) resolveImport(importPath string) string {
+	first, _, _ := strings.Cut(importPath, "/")
+	switch {
+	case !strings.Contains(first, "."):
+		return ""
+	case importPath == p.importPath:
+		return "the package imports itself"
+	case importPath == p.modulePath || strings.HasPrefix(importPath, p.modulePath+"/"):
+		dir := filepath.Join(p.moduleRoot, filepath.FromSlash(strings.TrimPrefix(importPath, p.modulePath)))
+		if matches, _ := filepath.Glob(filepath.Join(dir, "*.go")); len(matches) == 0 {
+			return "no Go files in " + dir
+		}
+		return ""
+	}
+	for _, require := range p.requires {
+		if importPath == require || strings.HasPrefix(importPath, require+"/") {
+			return ""
+		}
+	}
+	return "not required by go.mod, run go get"
+}
+
+// writeDoc writes the doc.go file of the package, naming the schema its code is generated
+// from, unless the package has one. It returns the path of the file written, or "".
+func (p *modulePackage) writeDoc(schemaName string) (string, error) {
+	docFile := filepath.Join(p.dir, "doc.go")
+	if _, err := os.Stat(docFile); err == nil {
+		return "", nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	doc := fmt.Sprintf("// Package %s contains the types generated from %s.\npackage %s\n", p.name, schemaName, p.name)
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return "", err
+	}
+	return docFile, os.WriteFile(docFile, []byte(doc), 0o644)
+}
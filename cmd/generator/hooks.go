@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// runHookCommand runs a hook command with sh, writing input to its stdin and returning its
+// stdout. Its stderr goes to the generator's.
+func runHookCommand(command string, input []byte, env ...string) ([]byte, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", command, err)
+	}
+	return output, nil
+}
+
+// preParseCommand returns a pre-parse hook piping the schema through a command
+func preParseCommand(command string) codegen.PreParseHook {
+	return func(schema []byte) ([]byte, error) {
+		return runHookCommand(command, schema)
+	}
+}
+
+// postIRCommand returns a post-IR hook piping the definitions, as a JSON object, through a
+// command writing them back
+func postIRCommand(command string) jrpc.PostIRHook {
+	return func(definitions map[string]any) error {
+		input, err := json.Marshal(definitions)
+		if err != nil {
+			return fmt.Errorf("failed to encode the definitions: %w", err)
+		}
+		output, err := runHookCommand(command, input)
+		if err != nil {
+			return err
+		}
+		var edited map[string]any
+		if err := json.Unmarshal(output, &edited); err != nil {
+			return fmt.Errorf("%s: the output is not a JSON object of definitions: %w", command, err)
+		}
+		clear(definitions)
+		maps.Copy(definitions, edited)
+		return nil
+	}
+}
+
+// postEmitCommand returns a post-emit hook piping every file written through a command, which
+// finds its path in $CODEGEN_FILE
+func postEmitCommand(command string) codegen.PostEmitHook {
+	return func(path string, content []byte) ([]byte, error) {
+		return runHookCommand(command, content, "CODEGEN_FILE="+path)
+	}
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// initOptions are the answers of the init command given as flags, which are not asked
+type initOptions struct {
+	configFile string
+	name       string
+	generator  string
+	pkg        string
+	layout     string
+	output     string
+	options    string
+	yes        bool
+}
+
+// newInitCommand returns the init command, which asks how to generate code from a schema
+// and writes the answers as a target of a configuration file
+func newInitCommand() *cobra.Command {
+	var opts initOptions
+	cmd := &cobra.Command{
+		Use:   "init [flags] [<schema-file>]",
+		Short: "Write a codegen.yaml target for a schema by answering a few questions",
+		Long: `Inspects a schema, asks for the generator (among those accepting it), the Go package, the
+output layout (a file, or a directory of files per kind or per type), the output and the
+generation options, and writes them as a target of codegen.yaml (or --config), added to the
+targets of an existing file. It then prints the //go:generate line running the target.
+Answers given as flags are not asked; with --yes, the defaults are taken for the others.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaFile := ""
+			if len(args) > 0 {
+				schemaFile = args[0]
+			}
+			prompt := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr(), defaults: opts.yes}
+			return initConfig(prompt, schemaFile, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.configFile, "config", defaultConfigFile, "Configuration file the target is written to")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Name of the target (default: from the schema file name)")
+	cmd.Flags().StringVar(&opts.generator, "generator", "", "Generator of the target (default: the first accepting the schema)")
+	cmd.Flags().StringVar(&opts.pkg, "package", "", "Go package of the generated code (default: from the target name)")
+	cmd.Flags().StringVar(&opts.layout, "layout", "", "Output layout: file, kind (a file per kind) or type (a file per type) (default: file)")
+	cmd.Flags().StringVar(&opts.output, "output", "", "Output file or directory, relative to the configuration file (default: from the package and layout)")
+	cmd.Flags().StringVar(&opts.options, "options", "", "Comma-separated generation flags without the dash, with =value unless boolean (e.g. 'enum-helpers,omit=omitzero')")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Take the default answers instead of asking")
+	return cmd
+}
+
+// initConfig asks for the target of a schema, writes it to the configuration file and prints
+// the //go:generate line running it
+func initConfig(prompt *prompter, schemaFile string, opts initOptions) error {
+	if schemaFile == "" {
+		var err error
+		if schemaFile, err = prompt.ask("Schema file", "", checkSchemaFile); err != nil {
+			return err
+		}
+	} else if err := checkSchemaFile(schemaFile); err != nil {
+		return err
+	}
+
+	var config *generateConfig
+	if _, err := os.Stat(opts.configFile); err == nil {
+		if config, err = loadGenerateConfig(opts.configFile); err != nil {
+			return fmt.Errorf("%s: %w", opts.configFile, err)
+		}
+	}
+
+	accepting, err := validateSchema("", schemaFile)
+	if err != nil {
+		return schemaFileError(schemaFile, err)
+	}
+	if definitions, err := jrpc.LoadDefinitions(schemaFile, &jrpc.GeneratorOptions{}); err == nil {
+		slog.Info(fmt.Sprintf("%s has %d definitions and is accepted by the %s generators", schemaFile, len(definitions), strings.Join(accepting, ", ")))
+	} else {
+		slog.Info(fmt.Sprintf("%s is accepted by the %s generators", schemaFile, strings.Join(accepting, ", ")))
+	}
+
+	var target generateTarget
+	base := strings.TrimSuffix(path.Base(filepath.ToSlash(schemaFile)), path.Ext(schemaFile))
+	target.Name, err = prompt.answer(opts.name, "Target name", goPackageName(base), func(name string) error {
+		if config != nil && config.hasTarget(name) {
+			return fmt.Errorf("%s already has a target named %q", opts.configFile, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	target.Generator, err = prompt.answer(opts.generator, "Generator ("+strings.Join(accepting, ", ")+")", defaultInitGenerator(schemaFile, accepting), func(name string) error {
+		generator, err := codegen.Get(name)
+		if err != nil {
+			return err
+		}
+		return generator.ValidateSchema(schemaFile)
+	})
+	if err != nil {
+		return err
+	}
+	generator, _ := codegen.Get(target.Generator)
+	extension := outputExtension(generator)
+
+	output := target.Name + extension
+	if extension == ".go" {
+		target.Package, err = prompt.answer(opts.pkg, "Go package", goPackageName(target.Name), func(name string) error {
+			if name != goPackageName(name) {
+				return fmt.Errorf("%q is not a Go package name", name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		layout, err := prompt.answer(opts.layout, "Output layout (file, kind, type)", "file", func(layout string) error {
+			switch layout {
+			case "file", "kind", "type":
+				return nil
+			}
+			return fmt.Errorf("unknown layout %q: must be file, kind or type", layout)
+		})
+		if err != nil {
+			return err
+		}
+		output = path.Join(target.Package, target.Name+extension)
+		if layout != "file" {
+			target.Options = map[string]any{"split": layout}
+			output = target.Package + "/"
+		}
+	}
+	target.Output, err = prompt.answer(opts.output, "Output, relative to "+opts.configFile, output, nil)
+	if err != nil {
+		return err
+	}
+
+	options, err := prompt.answer(opts.options, "Options (comma-separated flags, e.g. "+suggestedOptions(target.Generator)+")", "none", func(value string) error {
+		_, err := parseInitOptions(value)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	parsed, _ := parseInitOptions(options)
+	for name, value := range parsed {
+		if target.Options == nil {
+			target.Options = map[string]any{}
+		}
+		target.Options[name] = value
+	}
+
+	target.Schema = schemaFile
+	if !isSchemaURL(schemaFile) {
+		if target.Schema, err = relativePath(filepath.Dir(opts.configFile), schemaFile); err != nil {
+			return err
+		}
+	}
+	if err := writeInitTarget(opts.configFile, target); err != nil {
+		return err
+	}
+	slog.Info(fmt.Sprintf("Added the %s target to %s; run it with '%s generate --config %s --target %s'", target.Name, opts.configFile, os.Args[0], opts.configFile, target.Name))
+
+	if extension == ".go" {
+		packageDir := filepath.Join(filepath.Dir(opts.configFile), filepath.FromSlash(target.Output))
+		if !strings.HasSuffix(target.Output, "/") {
+			packageDir = filepath.Dir(packageDir)
+		}
+		configPath, err := relativePath(packageDir, opts.configFile)
+		if err != nil {
+			return err
+		}
+		slog.Info(fmt.Sprintf("Add this line to a Go file of %s to run the target with go generate:", packageDir))
+		fmt.Printf("//go:generate go run %s generate --config %s --target %s\n", generatorModule, configPath, target.Name)
+	}
+	return nil
+}
+
+// checkSchemaFile checks that a schema file exists, unless it is a URL
+func checkSchemaFile(schemaFile string) error {
+	if schemaFile == "" {
+		return errors.New("a schema file is required")
+	}
+	if isSchemaURL(schemaFile) {
+		return nil
+	}
+	_, err := os.Stat(schemaFile)
+	return err
+}
+
+// defaultInitGenerator returns the generator suggested for a schema among those accepting
+// it: the one of its document kind (openapi, asyncapi, openrpc), or else the first generating
+// Go code
+func defaultInitGenerator(schemaFile string, accepting []string) string {
+	var document map[string]any
+	if data, err := os.ReadFile(schemaFile); err == nil && yaml.Unmarshal(data, &document) == nil {
+		for key, name := range map[string]string{"openapi": "openapi", "swagger": "openapi", "asyncapi": "asyncapi", "openrpc": "jsonrpc"} {
+			if document[key] != nil && slices.Contains(accepting, name) {
+				return name
+			}
+		}
+	}
+	for _, name := range accepting {
+		if generator, err := codegen.Get(name); err == nil && outputExtension(generator) == ".go" {
+			return name
+		}
+	}
+	return accepting[0]
+}
+
+// hasTarget reports whether the configuration has a target with the given name
+func (c *generateConfig) hasTarget(name string) bool {
+	for _, target := range c.Targets {
+		if target.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// goPackageName returns name as a Go package name: its lowercase letters and digits, after a
+// letter, or "types" when it has none
+func goPackageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) && r < unicode.MaxASCII || b.Len() > 0 && unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "types"
+	}
+	return b.String()
+}
+
+// suggestedOptions returns the options shown as examples for a generator
+func suggestedOptions(generator string) string {
+	switch generator {
+	case "openapi":
+		return "client,server"
+	case "jsonrpc", "a2a", "mcp", "asyncapi", "avro":
+		return "enum-helpers,validate"
+	}
+	return "tags=yaml"
+}
+
+// parseInitOptions parses the comma-separated generation flags of the init command, or "none", into the options of a target: true for boolean flags, the value for the others.
+// Flags that select the schema, output or package are answered separately.
+func parseInitOptions(value string) (map[string]any, error) {
+	options := map[string]any{}
+	if value == "none" || value == "" {
+		return options, nil
+	}
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	declareGenerationFlags(flags)
+	for _, option := range strings.Split(value, ",") {
+		name, optionValue, hasValue := strings.Cut(strings.TrimLeft(strings.TrimSpace(option), "-"), "=")
+		switch name {
+		case "":
+			continue
+		case "generator", "package", "check", "dry-run":
+			return nil, fmt.Errorf("option %s cannot be set here", name)
+		}
+		f := flags.Lookup(name)
+		if f == nil {
+			return nil, fmt.Errorf("unknown option %q", name)
+		}
+		boolFlag, isBool := f.Value.(interface{ IsBoolFlag() bool })
+		switch {
+		case isBool && boolFlag.IsBoolFlag() && !hasValue:
+			options[name] = true
+		case !hasValue:
+			return nil, fmt.Errorf("option %s needs a value (%s=...)", name, name)
+		default:
+			if err := f.Value.Set(optionValue); err != nil {
+				return nil, fmt.Errorf("invalid value %q of option %s: %w", optionValue, name, err)
+			}
+			options[name] = optionValue
+		}
+	}
+	return options, nil
+}
+
+// relativePath returns file relative to dir, with forward slashes, as the paths of
+// configuration files are
+func relativePath(dir, file string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, absFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// writeInitTarget adds a target to the configuration file, keeping the comments and targets
+// of an existing file, or creates the file
+func writeInitTarget(configFile string, target generateTarget) error {
+	var targetNode yaml.Node
+	if err := targetNode.Encode(target); err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	data, err := os.ReadFile(configFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err := document.Encode(generateConfig{Targets: []generateTarget{target}}); err != nil {
+			return err
+		}
+		document.HeadComment = "Generation targets, run with: generator generate"
+	case err != nil:
+		return err
+	default:
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("%s: %w", configFile, err)
+		}
+		var targets *yaml.Node
+		if len(document.Content) > 0 {
+			targets = mappingValue(document.Content[0], "targets")
+		}
+		if targets == nil || targets.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s: targets is not a list", configFile)
+		}
+		targets.Style = 0
+		targets.Content = append(targets.Content, &targetNode)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(configFile); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(configFile, out.Bytes(), 0o644)
+}
+
+// mappingValue returns the value of a key of a YAML mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// prompter asks the questions of the init command on out and reads the answers from in
+type prompter struct {
+	in       *bufio.Reader
+	out      io.Writer
+	defaults bool // Take the default answers instead of asking
+}
+
+// answer returns the answer given as a flag, after checking it, or else asks the question
+func (p *prompter) answer(given, question, defaultValue string, check func(string) error) (string, error) {
+	if given == "" {
+		return p.ask(question, defaultValue, check)
+	}
+	if check != nil {
+		if err := check(given); err != nil {
+			return "", err
+		}
+	}
+	return given, nil
+}
+
+// ask asks a question until the answer passes check, and returns it, or the default value
+// for an empty answer. The default value is taken without asking with --yes, and once the
+// input is exhausted.
+func (p *prompter) ask(question, defaultValue string, check func(string) error) (string, error) {
+	for {
+		answer := ""
+		if !p.defaults {
+			if defaultValue != "" {
+				fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+			} else {
+				fmt.Fprintf(p.out, "%s: ", question)
+			}
+			line, err := p.in.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return "", err
+			}
+			if err == io.EOF {
+				p.defaults = true
+				fmt.Fprintln(p.out)
+			}
+			answer = strings.TrimSpace(line)
+		}
+		if answer == "" {
+			if defaultValue == "" {
+				if p.defaults {
+					return "", fmt.Errorf("no answer to %q", question)
+				}
+				continue
+			}
+			answer = defaultValue
+		}
+		if check == nil {
+			return answer, nil
+		}
+		err := check(answer)
+		if err == nil {
+			return answer, nil
+		}
+		if p.defaults {
+			return "", err
+		}
+		fmt.Fprintf(p.out, "%v\n", err)
+	}
+}
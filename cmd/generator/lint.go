@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"github.com/inference-gateway/tools/codegen/openapi"
+	"github.com/spf13/cobra"
+)
+
+// lintSeverities are the severities the --rule flag accepts, "off" disabling the rule
+var lintSeverities = []string{jrpc.SeverityError, jrpc.SeverityWarning, jrpc.SeverityInfo, "off"}
+
+// lintReport is the JSON output of the lint command
+type lintReport struct {
+	Errors   int            `json:"errors"` // Number of findings with the error severity
+	Findings []jrpc.Finding `json:"findings"`
+}
+
+// newLintCommand returns the lint command, which reports the issues of a schema that degrade
+// the generated code and exits with status 1 when some have the error severity
+func newLintCommand() *cobra.Command {
+	var format string
+	severities := make(map[string]string)
+	rules := make([]string, 0, len(jrpc.LintSeverities))
+	for rule := range jrpc.LintSeverities {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	var ruleList strings.Builder
+	for _, rule := range rules {
+		fmt.Fprintf(&ruleList, "\n  - %s: %s", rule, jrpc.LintSeverities[rule])
+	}
+
+	cmd := &cobra.Command{
+		Use:   "lint [flags] <schema-file>",
+		Short: "Report the issues of a schema that degrade the generated code",
+		Long: `Reports the issues of an OpenAPI or JSON Schema document that degrade the generated code,
+with their location, JSON pointer and severity, and exits with status 1 when some have the error
+severity. --rule changes the severity of a rule, or turns it off. Rules and their default
+severity:` + ruleList.String(),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lint(args[0], format, severities)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.Flags().Func("rule", "Set the severity of a rule: error, warning, info or off (repeatable, e.g. 'missing-description=off')", func(value string) error {
+		rule, severity, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("expected <rule>=<severity>, got %q", value)
+		}
+		if _, known := jrpc.LintSeverities[rule]; !known {
+			return fmt.Errorf("unknown rule %q", rule)
+		}
+		if !slices.Contains(lintSeverities, severity) {
+			return fmt.Errorf("unknown severity %q: must be %s", severity, strings.Join(lintSeverities, ", "))
+		}
+		severities[rule] = severity
+		return nil
+	})
+	return cmd
+}
+
+// lint writes the findings of a schema with the severities overridden, exiting with status 1
+// when some have the error severity
+func lint(schemaFile, format string, severities map[string]string) error {
+	findings, err := lintFindings(schemaFile)
+	if err != nil {
+		return err
+	}
+	report := lintReport{Findings: []jrpc.Finding{}}
+	for _, finding := range findings {
+		if severity, ok := severities[finding.Rule]; ok {
+			finding.Severity = severity
+		}
+		if finding.Severity == "off" {
+			continue
+		}
+		if finding.Severity == jrpc.SeverityError {
+			report.Errors++
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+	case "text":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, finding := range report.Findings {
+			fmt.Fprintf(writer, "%s:%d:%d\t%s\t%s\t%s\t%s\n", schemaFile, finding.Line, finding.Column, finding.Severity, finding.Rule, finding.Path, finding.Message)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write findings: %w", err)
+		}
+		fmt.Printf("%d findings, %d errors\n", len(report.Findings), report.Errors)
+	default:
+		return fmt.Errorf("unsupported format %q: must be text or json", format)
+	}
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// lintFindings returns the findings of a schema with their default severity, located in the
+// file from their pointer, for editors and CI annotations
+func lintFindings(schemaFile string) ([]jrpc.Finding, error) {
+	var findings []jrpc.Finding
+	if isOpenAPIFile(schemaFile) {
+		document, err := openapi.LoadDocument(schemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", schemaFile, err)
+		}
+		findings = openapi.Lint(document)
+	} else {
+		var err error
+		if findings, err = jrpc.LintSchemaFile(schemaFile); err != nil {
+			return nil, fmt.Errorf("failed to lint schema: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	for i := range findings {
+		findings[i].Line, findings[i].Column = codegen.PointerPosition(data, findings[i].Path)
+	}
+	return findings, nil
+}
+
+// generationWarnings logs the findings of the rules about what the generated code drops or
+// renames (jrpc.GenerationWarningRules) as warnings of the generation of a JSON Schema,
+// OpenAPI or AsyncAPI document, followed by those of generators implementing
+// jrpc.WarningReporter, and returns them. schemaName is the name of a schema read from stdin,
+// a URL or merged schemas, whose findings are not located by line.
+func generationWarnings(generator codegen.Generator, schemaFile, schemaName string) []jrpc.Finding {
+	switch filepath.Ext(schemaFile) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return nil
+	}
+	if generator.Name() == "go2schema" {
+		return nil
+	}
+	findings, err := lintFindings(schemaFile)
+	if err != nil {
+		slog.Debug("Skipped the generation warnings", "error", err)
+		return nil
+	}
+	findings = slices.DeleteFunc(findings, func(finding jrpc.Finding) bool {
+		return !slices.Contains(jrpc.GenerationWarningRules, finding.Rule)
+	})
+	if reporter, ok := generator.(jrpc.WarningReporter); ok {
+		reported, err := reporter.GenerationWarnings(schemaFile)
+		if err != nil {
+			slog.Debug("Skipped the warnings of the generator", "generator", generator.Name(), "error", err)
+		} else if data, err := os.ReadFile(schemaFile); err == nil {
+			for i := range reported {
+				if reported[i].Path != "" {
+					reported[i].Line, reported[i].Column = codegen.PointerPosition(data, reported[i].Path)
+				}
+			}
+			findings = append(findings, reported...)
+		}
+	}
+
+	var warnings []jrpc.Finding
+	for _, finding := range findings {
+		finding.Severity = jrpc.SeverityWarning
+		attrs := []any{"file", schemaFile, "pointer", finding.Path}
+		if schemaName != "" {
+			attrs[1] = schemaName
+		} else if finding.Line > 0 {
+			attrs = append(attrs, "line", finding.Line, "column", finding.Column)
+		}
+		message := finding.Message
+		if finding.Path != "" {
+			message = finding.Path + ": " + message
+		}
+		slog.Warn(message, append(attrs, "rule", finding.Rule)...)
+		warnings = append(warnings, finding)
+	}
+	return warnings
+}
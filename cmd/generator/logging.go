@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// setupLogging makes the default logger write the messages at the debug level and above when
+// verbose, at the warning level and above when quiet, and at the info level and above
+// otherwise, to stderr in the given format: text or json
+func setupLogging(verbose, quiet bool, format string) error {
+	if verbose && quiet {
+		return fmt.Errorf("--verbose and --quiet cannot be combined")
+	}
+	level := slog.LevelInfo
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelWarn
+	}
+
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = &textHandler{mu: &sync.Mutex{}, w: os.Stderr, level: level}
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	default:
+		return fmt.Errorf("unsupported log format %q: must be text or json", format)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// logError logs an error, with the location of the schema error it wraps, if any, as the
+// file, pointer, line and column attributes
+func logError(err error) {
+	var attrs []any
+	var schemaErr *codegen.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.File != "" {
+		attrs = append(attrs, "file", schemaErr.File)
+		if schemaErr.Pointer != "" {
+			attrs = append(attrs, "pointer", schemaErr.Pointer)
+		}
+		if schemaErr.Line > 0 {
+			attrs = append(attrs, "line", schemaErr.Line, "column", schemaErr.Column)
+		}
+	}
+	slog.Error(err.Error(), attrs...)
+}
+
+// schemaFileError returns err preceded by the schema file it is about, unless it is located in
+// a schema file already, which logError reports
+func schemaFileError(schemaFile string, err error) error {
+	var schemaErr *codegen.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.File != "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", schemaFile, err)
+}
+
+// textHandler writes log records as lines for people: info messages as they are, the others
+// after their level (Warning: ...), errors located in a schema file after the location
+// (Error: schema.json:12:5: ...), and the other attributes after the message as key=value
+type textHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Level
+	attrs []slog.Attr
+}
+
+// Enabled reports whether records of level are written
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes a record
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString("Error: ")
+	case record.Level >= slog.LevelWarn:
+		line.WriteString("Warning: ")
+	case record.Level < slog.LevelInfo:
+		line.WriteString("Debug: ")
+	}
+
+	attrs := slices.Clone(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	// The pointer is named by the message of schema errors already
+	var file string
+	var position, others []string
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "file":
+			file = attr.Value.String()
+		case "line", "column":
+			position = append(position, attr.Value.String())
+		case "pointer":
+		default:
+			value := attr.Value.String()
+			if value == "" || strings.ContainsAny(value, " \"=") {
+				value = strconv.Quote(value)
+			}
+			others = append(others, attr.Key+"="+value)
+		}
+	}
+	if file != "" {
+		line.WriteString(strings.Join(append([]string{file}, position...), ":") + ": ")
+	}
+	line.WriteString(record.Message)
+	for _, other := range others {
+		line.WriteString(" " + other)
+	}
+	line.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+// WithAttrs returns a handler writing attrs with the attributes of every record
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append(slices.Clone(h.attrs), attrs...)
+	return &handler
+}
+
+// WithGroup returns the handler itself, since the generator does not group attributes
+func (h *textHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -1,141 +1,829 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/inference-gateway/tools/codegen"
 
+	"github.com/inference-gateway/tools/codegen/a2a"
+	"github.com/inference-gateway/tools/codegen/asyncapi"
+	"github.com/inference-gateway/tools/codegen/avro"
+	"github.com/inference-gateway/tools/codegen/go2schema"
+	"github.com/inference-gateway/tools/codegen/graphql"
 	"github.com/inference-gateway/tools/codegen/jrpc"
-	_ "github.com/inference-gateway/tools/codegen/openapi"
+	"github.com/inference-gateway/tools/codegen/mcp"
+	"github.com/inference-gateway/tools/codegen/mermaid"
+	"github.com/inference-gateway/tools/codegen/mocks"
+	"github.com/inference-gateway/tools/codegen/openapi"
+	"github.com/inference-gateway/tools/codegen/proto"
+	"github.com/inference-gateway/tools/codegen/python"
+	"github.com/inference-gateway/tools/codegen/sql"
+	"github.com/inference-gateway/tools/codegen/typescript"
 )
 
 func main() {
-	var (
-		generatorName  = flag.String("generator", "", "Specific generator to use (optional, auto-detected if not specified)")
-		packageName    = flag.String("package", "types", "Target Go package name")
-		listGens       = flag.Bool("list", false, "List available generators")
-		showHelp       = flag.Bool("help", false, "Show detailed help")
-		customAcronyms = flag.String("acronyms", "", "JSON object of custom acronyms (e.g., '{\"api\":true,\"jwt\":true}')")
-		noComments     = flag.Bool("no-comments", false, "Disable generation of comments from descriptions")
-		noFormat       = flag.Bool("no-format", false, "Disable automatic go fmt on output")
-	)
+	_ = setupLogging(false, false, "text")
+	root := newRootCommand()
+	if isLegacyInvocation(root, os.Args[1:]) {
+		runLegacy(os.Args[1:])
+		return
+	}
+	root.SetArgs(normalizeArgs(root, os.Args[1:]))
+	if err := root.Execute(); err != nil {
+		logError(err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// errMissingArguments is returned by generate when the schema or output file is missing
+var errMissingArguments = errors.New("missing <schema-file> or <output-file>")
 
-	flag.Parse()
+// runLegacy runs the invocation without a command, "generator [flags] <schema-file>
+// <output-file>", deprecated in favor of the generate command, as well as -help and -list
+func runLegacy(args []string) {
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	listGens := flags.Bool("list", false, "List available generators")
+	showHelp := flags.Bool("help", false, "Show detailed help")
+	run := declareGenerationFlags(flags)
+	_ = flags.Parse(args)
 
 	if *showHelp {
 		showDetailedHelp()
 		return
 	}
-
 	if *listGens {
 		listGenerators()
 		return
 	}
-
-	args := flag.Args()
-	if len(args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <schema-file> <output-file>\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Use -help for detailed usage information\n")
+	if flags.NArg() < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s generate [flags] <schema-file> <output-file>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Use %s --help for the commands and their flags\n", os.Args[0])
 		os.Exit(1)
 	}
+	slog.Warn(fmt.Sprintf("'%s [flags] <schema-file> <output-file>' is deprecated and will be removed, use '%s generate [flags] <schema-file> <output-file>'", os.Args[0], os.Args[0]))
+	if err := run(flags.Arg(0), flags.Arg(1), ""); err != nil {
+		logError(err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// generate parses the generation flags and the schema and output files of args and runs the
+// generator
+func generate(flags *flag.FlagSet, args []string) error {
+	run := declareGenerationFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 {
+		return errMissingArguments
+	}
+	return run(flags.Arg(0), flags.Arg(1), "")
+}
+
+// generationRun generates code from a schema into an output file. The generated code names
+// the schema by schemaName when given, and by its path otherwise.
+type generationRun func(schemaFile, outputFile, schemaName string) error
+
+// declareGenerationFlags declares the generation flags on flags and returns the function
+// generating from a schema into an output file with their values, once parsed
+func declareGenerationFlags(flags *flag.FlagSet) generationRun {
+	var (
+		generatorName  = flags.String("generator", "", "Specific generator to use (optional, auto-detected if not specified)")
+		packageName    = flags.String("package", "types", "Target Go package name")
+		check          = flags.Bool("check", false, "Generate into memory and exit with an error, printing a unified diff, when the output files differ")
+		dryRun         = flags.Bool("dry-run", false, "Generate into memory and print the changes to the output files as a unified diff instead of writing them")
+		inputFormat    = flags.String("input-format", "", "Format of a schema read from stdin (-): json, yaml, avsc, ... (default: json or yaml, from its first character)")
+		customAcronyms = flags.String("acronyms", "", "JSON object of custom acronyms (e.g., '{\"api\":true,\"jwt\":true}')")
+		namingFile     = flags.String("naming", "", "YAML file of acronyms, forced names and word spellings (default: "+jrpc.NamingConfigFile+" next to the schema or in the working directory)")
+		noComments     = flags.Bool("no-comments", false, "Disable generation of comments from descriptions")
+		noFormat       = flags.Bool("no-format", false, "Disable automatic gofmt formatting of the output")
+		commentWidth   = flags.Int("comment-width", 80, "Line width field comments are wrapped to (negative disables wrapping)")
+		extComments    = flags.Bool("extension-comments", false, "Write the vendor extensions (x-*) the generator does not interpret as comments (e.g. '// x-rate-limit: 100')")
+		resolveRemote  = flags.Bool("resolve-remote-refs", false, "Fetch and inline remote HTTP(S) $ref targets")
+		refCacheDir    = flags.String("ref-cache-dir", "", "Directory used to cache remote $ref documents")
+		offline        = flags.Bool("offline", false, "Resolve remote $refs from the cache only and fail if a document is missing")
+		decimalType    = flags.String("decimal-type", "", "Go type for 'format: decimal' (e.g. 'decimal.Decimal')")
+		decimalImport  = flags.String("decimal-import", "", "Import path of the -decimal-type package (e.g. 'github.com/shopspring/decimal')")
+		withDefaults   = flags.Bool("defaults", false, "Generate ApplyDefaults methods that populate schema defaults")
+		rwVariants     = flags.Bool("read-write-variants", false, "Generate XCreate/XRead variants of models that use readOnly/writeOnly")
+		validateTags   = flags.Bool("validate-tags", false, "Add go-playground/validator 'validate' struct tags derived from schema constraints")
+		withValidate   = flags.Bool("validate", false, "Generate Validate() methods that enforce schema constraints using only the standard library")
+		enumHelpers    = flags.Bool("enum-helpers", false, "Generate String/IsValid methods and Values/Parse functions for enum types")
+		strictEnums    = flags.Bool("strict-enums", false, "Generate UnmarshalJSON methods that reject values outside the enum")
+		iotaEnums      = flags.Bool("iota-enums", false, "Generate iota constants and a name lookup table for contiguous integer enums")
+		constructors   = flags.Bool("constructors", false, "Generate NewX constructors that take the required fields as arguments")
+		getters        = flags.Bool("getters", false, "Generate nil-safe GetX accessors for optional pointer fields")
+		factories      = flags.Bool("example-factories", false, "Generate ExampleX and FakeX functions returning models populated with sample data")
+		contractTests  = flags.Bool("contract-tests", false, "Also write a _contract_test.go suite checking the models against the examples, required properties, enums and formats of their schemas")
+		fuzzTests      = flags.Bool("fuzz-tests", false, "Also write a _fuzz_test.go file with a FuzzXUnmarshal target per type with a custom UnmarshalJSON")
+		benchmarks     = flags.String("benchmarks", "", "Comma-separated glob patterns of the models to write BenchmarkMarshalX/BenchmarkUnmarshalX benchmarks of in a _bench_test.go file")
+		withMocks      = flags.Bool("mocks", false, "Also write a _mock.go file with a MockX implementation of every interface of the generated Go code")
+		omitMode       = flags.String("omit", "omitempty", "JSON tag option for optional fields: omitempty, omitzero or both")
+		extraTags      = flags.String("tags", "", "Comma-separated struct tag keys written next to json (e.g., 'yaml,mapstructure')")
+		preserveOrder  = flags.Bool("preserve-order", false, "Keep struct fields in schema property order instead of sorting them alphabetically")
+		splitMode      = flags.String("split", "", "Write a directory of files instead of one file: 'kind' (enums/models/helpers) or 'type' (one file per type)")
+		goimports      = flags.Bool("goimports", false, "Format the output with goimports instead of gofmt")
+		buildTag       = flags.String("build-constraint", "", "Build constraint expression written as a //go:build line (e.g., '!codeanalysis')")
+		licenseFile    = flags.String("license-file", "", "File whose contents are written as a comment at the top of the generated code")
+		provenance     = flags.Bool("provenance", false, "Name the generator version, schema file and schema SHA-256 hash in the generated code notice")
+		timestamp      = flags.Bool("timestamp", false, "Include the generation time in the generated code notice")
+		definedTypes   = flags.Bool("defined-types", false, "Generate primitive definitions as defined types (type ID string) instead of aliases (type ID = string)")
+		includeTypes   = flags.String("include-types", "", "Comma-separated glob patterns of the definitions to generate, plus the definitions they reference")
+		excludeTypes   = flags.String("exclude-types", "", "Comma-separated glob patterns of definitions to skip unless a generated definition references them")
+		client         = flags.Bool("client", false, "Generate a Client with one method per operation and options for the security schemes (openapi generator), or per method of an OpenRPC document (jsonrpc generator)")
+		server         = flags.Bool("server", false, "Generate a ServerInterface and net/http handlers for the operations (openapi generator), or a dispatcher for the methods of an OpenRPC document (jsonrpc generator)")
+		framework      = flags.String("server-framework", "", "Router to also register the server operations on: stdlib (default), chi, gin or echo (implies -server)")
+		strict         = flags.Bool("strict-server", false, "Generate a StrictServerInterface whose handlers return the typed responses of their operation (implies -server)")
+		validation     = flags.Bool("validation-middleware", false, "Generate a net/http middleware validating requests against the OpenAPI document (implies -server)")
+		mock           = flags.Bool("mock-server", false, "Generate a MockServer answering the operations with their examples, for integration tests (implies -server)")
+		includeTags    = flags.String("include-tags", "", "Comma-separated tags of the operations to generate (openapi generator)")
+		includeOps     = flags.String("include-operations", "", "Comma-separated glob patterns of the operationIds of the operations to generate (openapi generator)")
+		excludePaths   = flags.String("exclude-paths", "", "Comma-separated glob patterns of the paths whose operations are not generated (openapi generator)")
+		agentCard      = flags.Bool("agent-card", false, "Generate AgentCardPath, AgentCardHandler and FetchAgentCard (a2a generator)")
+		agentCardFile  = flags.String("agent-card-file", "", "Agent card JSON file checked against the schema and embedded as EmbeddedAgentCard (a2a generator, implies -agent-card)")
+		taskStates     = flags.Bool("task-state-helpers", false, "Generate the IsTerminal and IsInterrupted methods of TaskState (a2a generator)")
+		toolFormat     = flags.String("tool-format", "", "Write the LLM tool definitions of the functions of the Go package instead of JSON Schema: generic, openai or anthropic (go2schema generator)")
+		protoNumbering = flags.String("proto-numbering", "declaration", "Field numbering of the messages: declaration, alphabetical or hash (proto generator)")
+		protoReport    = flags.String("proto-report", "", "File the mapping of the schemas to messages, enums and fields is written to, as JSON (.json) or Markdown (proto generator)")
+		goPackage      = flags.String("go-package", "", "go_package option of the generated .proto file (proto generator)")
+		tsEnumStyle    = flags.String("ts-enum-style", "union", "Declaration of string enums: union types or enums (typescript generator)")
+		gqlNullability = flags.String("graphql-nullability", "required", "Non-null fields of the object types: required, non-null or nullable (graphql generator)")
+		gqlNoInputs    = flags.Bool("graphql-no-inputs", false, "Do not declare an input type per object type (graphql generator)")
+		avroNamespace  = flags.String("avro-namespace", "", "Namespace of the records and enums of the Avro schemas written (avro generator)")
+		mermaidChart   = flags.String("mermaid-diagram", "class", "Diagram of the definitions: class or er (mermaid generator)")
+		sqlDialect     = flags.String("sql-dialect", "postgres", "SQL dialect of the tables: postgres (sql generator)")
+		reportRenames  = flags.Bool("report-renames", false, "Print the definitions and properties renamed to avoid Go keywords and identifier collisions")
+		goPackageDir   = flags.String("go-package-dir", "", "Directory of the Go package of the output, inside a Go module: -package defaults to its name and the generated imports must resolve in the module")
+		modulePath     = flags.String("module-path", "", "Import path of the Go module the output must belong to (implies -go-package-dir, default: the output's directory)")
+		docFile        = flags.Bool("doc-file", false, "With -go-package-dir or -module-path, also write a doc.go for the package unless it has one")
+		strictWarnings = flags.Bool("strict-warnings", false, "Fail, with exit status 5, when the schema has constructs the generated code drops or renames")
+		coverageReport = flags.String("coverage-report", "", "File a JSON report of the types generated, the keywords of the schema and the constructs generated as any or skipped is written to")
+		templateDir    = flags.String("template-dir", "", "Directory of header/enum/struct/alias .tmpl files overriding the built-in templates")
+		tagTemplates   []string
+		preParseHooks  []string
+		postIRHooks    []string
+		postEmitHooks  []string
+		importMappings = make(map[string]string)
+		sqlTypes       = make(map[string]string)
+	)
 
-	schemaFile := args[0]
-	outputFile := args[1]
+	flags.Func("pre-parse-hook", "Shell command rewriting the schema, from its stdin to its stdout, before it is read (repeatable)", func(value string) error {
+		preParseHooks = append(preParseHooks, value)
+		return nil
+	})
+	flags.Func("post-ir-hook", "Shell command editing the definitions, a JSON object from its stdin to its stdout, before the code is written (repeatable)", func(value string) error {
+		postIRHooks = append(postIRHooks, value)
+		return nil
+	})
+	flags.Func("post-emit-hook", "Shell command rewriting every file written, named by $CODEGEN_FILE, from its stdin to its stdout (repeatable)", func(value string) error {
+		postEmitHooks = append(postEmitHooks, value)
+		return nil
+	})
 
-	var generator codegen.Generator
-	var err error
+	flags.Func("tag-template", "Go template rendering an extra struct tag per field (repeatable, e.g. 'db:\"{{ .SnakeName }}\"')", func(value string) error {
+		tagTemplates = append(tagTemplates, value)
+		return nil
+	})
 
-	if *generatorName != "" {
-		generator, err = codegen.Get(*generatorName)
+	flags.Func("import-mapping", "Map a definition to an existing Go type instead of generating it (repeatable, e.g. '#/components/schemas/Message=github.com/org/core/types.Message')", func(value string) error {
+		ref, target, ok := strings.Cut(value, "=")
+		if !ok || ref == "" || target == "" {
+			return fmt.Errorf("expected <ref>=<import/path.Type>, got %q", value)
+		}
+		importMappings[ref] = target
+		return nil
+	})
+
+	headers := http.Header{}
+	flags.Func("header", "Request header of a schema downloaded from a URL (repeatable, e.g. 'Authorization: Bearer $TOKEN')", func(value string) error {
+		name, headerValue, err := parseHeader(value)
 		if err != nil {
-			log.Fatalf("Generator not found: %v", err)
+			return err
+		}
+		headers.Add(name, headerValue)
+		return nil
+	})
+
+	flags.Func("sql-type", "Column type of a JSON Schema format or type (repeatable, e.g. 'date-time=TIMESTAMP', 'string=VARCHAR(255)') (sql generator)", func(value string) error {
+		key, typ, ok := strings.Cut(value, "=")
+		if !ok || key == "" || typ == "" {
+			return fmt.Errorf("expected <format or type>=<column type>, got %q", value)
+		}
+		sqlTypes[key] = typ
+		return nil
+	})
+
+	return func(schemaFile, outputFile, schemaName string) error {
+
+		var generator codegen.Generator
+		var err error
+
+		// A schema read from stdin, or code written to stdout, goes through a temporary file, since
+		// the generators read and write files and their formats follow the file extensions
+		status := slog.Default()
+		if *inputFormat != "" && schemaFile != "-" && !isSchemaURL(schemaFile) {
+			return fmt.Errorf("-input-format applies to a schema read from stdin (-) or downloaded")
+		}
+		if len(headers) > 0 && !isSchemaURL(schemaFile) {
+			return fmt.Errorf("-header applies to a schema downloaded from a URL")
+		}
+		var tempDir string
+		if schemaFile == "-" || outputFile == "-" || isSchemaURL(schemaFile) {
+			tempDir, err = os.MkdirTemp("", "generator")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tempDir)
+		}
+		if schemaFile == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read the schema from stdin: %w", err)
+			}
+			schemaName = "stdin"
+			if schemaFile, err = writeTempSchema(tempDir, "stdin", data, *inputFormat); err != nil {
+				return err
+			}
+		} else if isSchemaURL(schemaFile) {
+			cacheDir, err := schemaCacheDir(*refCacheDir)
+			if err != nil {
+				return err
+			}
+			data, err := fetchSchema(schemaFile, headers, cacheDir, *offline)
+			if err != nil {
+				return err
+			}
+			// The file is named after the URL, and after its extension when that is a known format
+			schemaName = schemaFile
+			location, _ := url.Parse(schemaFile)
+			name, format := path.Base(location.Path), *inputFormat
+			if ext := path.Ext(name); format == "" && ext != "" && len(codegen.GetByFormat(name)) > 0 {
+				format = ext
+			}
+			name = strings.TrimSuffix(name, path.Ext(name))
+			if name == "" || name == "." || name == "/" {
+				name = "schema"
+			}
+			if schemaFile, err = writeTempSchema(tempDir, name, data, format); err != nil {
+				return err
+			}
 		}
-	} else {
-		generators := codegen.GetByFormat(schemaFile)
-		if len(generators) == 0 {
-			log.Fatalf("No generators found that support file format of %s", schemaFile)
+		toStdout := outputFile == "-"
+		if toStdout && (*splitMode != "" || *contractTests || *fuzzTests || *benchmarks != "" || *withMocks) {
+			return fmt.Errorf("-split, -contract-tests, -fuzz-tests, -benchmarks and -mocks write files next to the output and cannot write to stdout")
 		}
-		if len(generators) > 1 {
-			var names []string
+
+		if *generatorName != "" {
+			generator, err = codegen.Get(*generatorName)
+			if err != nil {
+				return fmt.Errorf("generator not found: %w", err)
+			}
+		} else {
+			generators := codegen.GetByFormat(schemaFile)
+			if len(generators) == 0 {
+				return fmt.Errorf("no generators found that support file format of %s", schemaFile)
+			}
+			// Generators rejecting the schema, such as a2a for other schemas, are left out unless
+			// all of them do, in which case the validation below reports why
+			var valid []codegen.Generator
+			for _, g := range generators {
+				if g.ValidateSchema(schemaFile) == nil {
+					valid = append(valid, g)
+				}
+			}
+			if len(valid) > 0 {
+				generators = valid
+			}
+			// Likewise for the generators writing other files than the output, such as proto for
+			// Go files
+			var writing []codegen.Generator
 			for _, g := range generators {
-				names = append(names, g.Name())
+				if codegen.AcceptsOutput(g, outputFile) {
+					writing = append(writing, g)
+				}
+			}
+			if len(writing) > 0 {
+				generators = writing
+			}
+			if len(generators) > 1 {
+				var names []string
+				for _, g := range generators {
+					names = append(names, g.Name())
+				}
+				slog.Warn(fmt.Sprintf("Multiple generators support this format: %s. Using '%s'. Use -generator flag to specify.",
+					strings.Join(names, ", "), generators[0].Name()))
 			}
-			log.Printf("Multiple generators support this format: %s. Using '%s'. Use -generator flag to specify.",
-				strings.Join(names, ", "), generators[0].Name())
+			generator = generators[0]
 		}
-		generator = generators[0]
-	}
 
-	if err := generator.ValidateSchema(schemaFile); err != nil {
-		log.Fatalf("Schema validation failed: %v", err)
-	}
+		slog.Debug("Generating", "schema", schemaFile, "generator", generator.Name(), "output", outputFile)
+		if err := generator.ValidateSchema(schemaFile); err != nil {
+			return withExitCode(exitValidation, fmt.Errorf("schema validation failed: %w", err))
+		}
+
+		// The constructs of the schema the generated code drops or renames are warned about, or
+		// fail the generation with -strict-warnings
+		warnings := generationWarnings(generator, schemaFile, schemaName)
+		if len(warnings) > 0 && *strictWarnings {
+			return withExitCode(exitWarnings, fmt.Errorf("%d generation warnings with -strict-warnings", len(warnings)))
+		}
+		if *coverageReport != "" && generator.Name() == "go2schema" {
+			return fmt.Errorf("-coverage-report applies to schema documents, not to the Go packages of the go2schema generator")
+		}
+		switch generator.Name() {
+		case "openapi", "proto", "go2schema":
+			if len(postIRHooks) > 0 {
+				return fmt.Errorf("-post-ir-hook does not apply to the %s generator, which does not generate from the jrpc definitions", generator.Name())
+			}
+		}
+
+		// With -go-package-dir or -module-path, the output belongs to a package of a Go module,
+		// whose name -package defaults to, and whose imports the generated code must resolve
+		var goPkg *modulePackage
+		if *goPackageDir != "" || *modulePath != "" {
+			dir := outputPackageDir(outputFile, *splitMode != "")
+			if *goPackageDir != "" {
+				if !toStdout && !samePath(dir, *goPackageDir) {
+					return fmt.Errorf("output %s is not in the package directory %s", outputFile, *goPackageDir)
+				}
+				dir = *goPackageDir
+			}
+			if goPkg, err = locateGoPackage(dir, *modulePath); err != nil {
+				return err
+			}
+			packageSet := false
+			flags.Visit(func(f *flag.Flag) {
+				packageSet = packageSet || f.Name == "package"
+			})
+			if !packageSet {
+				*packageName = goPkg.name
+			}
+			goPkg.name = *packageName
+			slog.Debug("Generating into a Go package", "import_path", goPkg.importPath, "package", *packageName)
+		}
+
+		if toStdout {
+			outputFile = filepath.Join(tempDir, "stdout"+outputExtension(generator))
+		}
+
+		// In check and dry-run modes, the output and the files written next to it are generated
+		// into a temporary directory standing for the directory of the output, and compared
+		// with it
+		var previewDir, previewedOutput string
+		reportFile, coverageFile := *protoReport, *coverageReport
+		if *check && *dryRun {
+			return fmt.Errorf("-check and -dry-run cannot be combined")
+		}
+		if *check || *dryRun {
+			if toStdout {
+				return fmt.Errorf("-check and -dry-run compare the output files and cannot write to stdout")
+			}
+			previewDir, err = os.MkdirTemp("", "generator-preview")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(previewDir)
+			previewedOutput = outputFile
+			outputFile = filepath.Join(previewDir, "output", filepath.Base(outputFile))
+			if err := os.MkdirAll(filepath.Dir(outputFile), 0o755); err != nil {
+				return err
+			}
+			if reportFile != "" {
+				reportFile = filepath.Join(previewDir, "report", filepath.Base(reportFile))
+				if err := os.MkdirAll(filepath.Dir(reportFile), 0o755); err != nil {
+					return err
+				}
+			}
+			if coverageFile != "" {
+				coverageFile = filepath.Join(previewDir, "coverage", filepath.Base(coverageFile))
+			}
+			status = slog.New(slog.DiscardHandler)
+		} else if !toStdout {
+			if err := os.MkdirAll(filepath.Dir(outputFile), 0o755); err != nil {
+				return err
+			}
+		}
+
+		var options any
+		var contractSuite, fuzzSuite, benchmarkSuite *bytes.Buffer
+		var benchmarkTypes []string
+		if *contractTests {
+			contractSuite = new(bytes.Buffer)
+		}
+		if *fuzzTests {
+			fuzzSuite = new(bytes.Buffer)
+		}
+		if *benchmarks != "" {
+			benchmarkSuite = new(bytes.Buffer)
+			for _, pattern := range strings.Split(*benchmarks, ",") {
+				benchmarkTypes = append(benchmarkTypes, strings.TrimSpace(pattern))
+			}
+		}
 
-	var options any
+		switch generator.Name() {
+		case "jsonrpc", "a2a", "mcp", "asyncapi", "typescript", "python", "graphql", "avro", "sql", "mermaid":
+			jrpcOptions := &jrpc.GeneratorOptions{
+				PackageName:     *packageName,
+				IncludeComments: !*noComments,
+				FormatOutput:    !*noFormat,
+				CommentWidth:    *commentWidth,
 
-	switch generator.Name() {
-	case "jsonrpc":
-		jrpcOptions := &jrpc.GeneratorOptions{
-			PackageName:     *packageName,
-			IncludeComments: !*noComments,
-			FormatOutput:    !*noFormat,
+				ExtensionComments: *extComments,
+
+				ResolveRemoteRefs: *resolveRemote,
+				RefCacheDir:       *refCacheDir,
+				Offline:           *offline,
+
+				DecimalType:   *decimalType,
+				DecimalImport: *decimalImport,
+
+				GenerateDefaults:  *withDefaults,
+				ReadWriteVariants: *rwVariants,
+				ValidateTags:      *validateTags,
+				GenerateValidate:  *withValidate,
+				EnumHelpers:       *enumHelpers,
+				StrictEnums:       *strictEnums,
+				IotaEnums:         *iotaEnums,
+				Constructors:      *constructors,
+				Getters:           *getters,
+				ExampleFactories:  *factories,
+				RPCClient:         *client,
+				RPCServer:         *server,
+				OmitMode:          *omitMode,
+				TagTemplates:      tagTemplates,
+				PreserveOrder:     *preserveOrder,
+				SplitMode:         *splitMode,
+				Goimports:         *goimports,
+
+				BuildConstraint: *buildTag,
+				Provenance:      *provenance,
+				SchemaName:      schemaName,
+				GeneratedBy:     generatorVersion(),
+				Timestamp:       *timestamp,
+
+				TemplateDir: *templateDir,
+
+				ImportMappings: importMappings,
+				DefinedTypes:   *definedTypes,
+			}
+
+			if *reportRenames {
+				jrpcOptions.RenameReport = os.Stderr
+			}
+
+			if contractSuite != nil {
+				jrpcOptions.ContractTests = contractSuite
+			}
+			if fuzzSuite != nil {
+				jrpcOptions.FuzzTests = fuzzSuite
+			}
+			if benchmarkSuite != nil {
+				jrpcOptions.BenchmarkTypes = benchmarkTypes
+				jrpcOptions.BenchmarkTests = benchmarkSuite
+			}
+
+			if *licenseFile != "" {
+				license, err := os.ReadFile(*licenseFile)
+				if err != nil {
+					return fmt.Errorf("failed to read license file: %w", err)
+				}
+				jrpcOptions.LicenseHeader = string(license)
+			}
+
+			if *includeTypes != "" {
+				for _, pattern := range strings.Split(*includeTypes, ",") {
+					jrpcOptions.IncludeTypes = append(jrpcOptions.IncludeTypes, strings.TrimSpace(pattern))
+				}
+			}
+
+			if *excludeTypes != "" {
+				for _, pattern := range strings.Split(*excludeTypes, ",") {
+					jrpcOptions.ExcludeTypes = append(jrpcOptions.ExcludeTypes, strings.TrimSpace(pattern))
+				}
+			}
+
+			if *extraTags != "" {
+				for _, key := range strings.Split(*extraTags, ",") {
+					jrpcOptions.Tags = append(jrpcOptions.Tags, strings.TrimSpace(key))
+				}
+			}
+
+			if *customAcronyms != "" {
+				var acronyms map[string]bool
+				if err := json.Unmarshal([]byte(*customAcronyms), &acronyms); err != nil {
+					return fmt.Errorf("failed to parse custom acronyms JSON: %w", err)
+				}
+				jrpcOptions.CustomAcronyms = acronyms
+			}
+
+			if path := namingRulesPath(*namingFile, schemaFile); path != "" {
+				rules, err := jrpc.LoadNamingRules(path)
+				if err != nil {
+					return fmt.Errorf("failed to load naming rules: %w", err)
+				}
+				jrpcOptions.Naming = rules
+			}
+
+			for _, command := range postIRHooks {
+				jrpcOptions.PostIR = append(jrpcOptions.PostIR, postIRCommand(command))
+			}
+			// The schema rewritten by the pre-parse hooks is a copy, which the notice should not name
+			if len(preParseHooks) > 0 && jrpcOptions.SchemaName == "" {
+				jrpcOptions.SchemaName = filepath.ToSlash(schemaFile)
+			}
+
+			switch generator.Name() {
+			case "a2a":
+				options = &a2a.Options{
+					GeneratorOptions: jrpcOptions,
+					AgentCard:        *agentCard,
+					AgentCardFile:    *agentCardFile,
+					TaskStateHelpers: *taskStates,
+				}
+			case "mcp":
+				options = &mcp.Options{GeneratorOptions: jrpcOptions}
+			case "asyncapi":
+				options = &asyncapi.Options{GeneratorOptions: jrpcOptions}
+			case "typescript":
+				options = &typescript.Options{GeneratorOptions: jrpcOptions, EnumStyle: *tsEnumStyle}
+			case "python":
+				options = &python.Options{GeneratorOptions: jrpcOptions}
+			case "graphql":
+				options = &graphql.Options{
+					GeneratorOptions: jrpcOptions,
+					Nullability:      *gqlNullability,
+					NoInputs:         *gqlNoInputs,
+				}
+			case "avro":
+				options = &avro.Options{GeneratorOptions: jrpcOptions, Namespace: *avroNamespace}
+			case "sql":
+				options = &sql.Options{GeneratorOptions: jrpcOptions, Dialect: *sqlDialect, TypeMappings: sqlTypes}
+			case "mermaid":
+				options = &mermaid.Options{GeneratorOptions: jrpcOptions, Diagram: *mermaidChart}
+			default:
+				options = &jrpc.Options{GeneratorOptions: jrpcOptions}
+			}
+
+		case "go2schema":
+			go2schemaOptions := &go2schema.Options{ToolFormat: *toolFormat}
+			if *includeTypes != "" {
+				for _, pattern := range strings.Split(*includeTypes, ",") {
+					go2schemaOptions.IncludeTypes = append(go2schemaOptions.IncludeTypes, strings.TrimSpace(pattern))
+				}
+			}
+			options = go2schemaOptions
+
+		case "proto":
+			options = &proto.Options{
+				IncludeComments: !*noComments,
+				GoPackage:       *goPackage,
+				Numbering:       *protoNumbering,
+				Report:          reportFile,
+			}
+
+		case "openapi":
+			openapiOptions := &openapi.Options{
+				PackageName:          *packageName,
+				IncludeComments:      !*noComments,
+				FormatOutput:         !*noFormat,
+				ExtensionComments:    *extComments,
+				GenerateModels:       true,
+				ExampleFactories:     *factories,
+				GenerateClient:       *client,
+				GenerateServer:       *server,
+				ServerFramework:      *framework,
+				StrictServer:         *strict,
+				ValidationMiddleware: *validation,
+				MockServer:           *mock,
+			}
+
+			if contractSuite != nil {
+				openapiOptions.ContractTests = contractSuite
+			}
+			if fuzzSuite != nil {
+				openapiOptions.FuzzTests = fuzzSuite
+			}
+			if benchmarkSuite != nil {
+				openapiOptions.BenchmarkTypes = benchmarkTypes
+				openapiOptions.BenchmarkTests = benchmarkSuite
+			}
+
+			if *includeTags != "" {
+				for _, tag := range strings.Split(*includeTags, ",") {
+					openapiOptions.IncludeTags = append(openapiOptions.IncludeTags, strings.TrimSpace(tag))
+				}
+			}
+
+			if *includeOps != "" {
+				for _, pattern := range strings.Split(*includeOps, ",") {
+					openapiOptions.IncludeOperations = append(openapiOptions.IncludeOperations, strings.TrimSpace(pattern))
+				}
+			}
+
+			if *excludePaths != "" {
+				for _, pattern := range strings.Split(*excludePaths, ",") {
+					openapiOptions.ExcludePaths = append(openapiOptions.ExcludePaths, strings.TrimSpace(pattern))
+				}
+			}
+
+			options = openapiOptions
 		}
 
-		if *customAcronyms != "" {
-			var acronyms map[string]bool
-			if err := json.Unmarshal([]byte(*customAcronyms), &acronyms); err != nil {
-				log.Fatalf("Failed to parse custom acronyms JSON: %v", err)
+		config := codegen.GenerateConfig{
+			SchemaPath:  schemaFile,
+			OutputPath:  outputFile,
+			PackageName: *packageName,
+			Options:     options,
+		}
+
+		// The protocol generators name the package after the protocol unless -package is given
+		if generator.Name() == "a2a" || generator.Name() == "mcp" {
+			config.PackageName = ""
+			if goPkg != nil {
+				config.PackageName = *packageName
 			}
-			jrpcOptions.CustomAcronyms = acronyms
+			flags.Visit(func(f *flag.Flag) {
+				if f.Name == "package" {
+					config.PackageName = *packageName
+				}
+			})
 		}
 
-		options = &jrpc.Options{GeneratorOptions: jrpcOptions}
+		hooks := codegen.Hooks{}
+		for _, command := range preParseHooks {
+			hooks.PreParse = append(hooks.PreParse, preParseCommand(command))
+		}
+		for _, command := range postEmitHooks {
+			hooks.PostEmit = append(hooks.PostEmit, postEmitCommand(command))
+		}
+		if err := codegen.GenerateWithHooks(generator, config, hooks); err != nil {
+			return withExitCode(exitGeneration, fmt.Errorf("failed to generate code: %w", err))
+		}
+		if goPkg != nil {
+			if err := goPkg.checkImports(outputFile); err != nil {
+				return err
+			}
+		}
 
-	case "openapi":
-		openapiOptions := &struct {
-			PackageName     string
-			IncludeComments bool
-			FormatOutput    bool
-			GenerateModels  bool
-			GenerateClient  bool
-		}{
-			PackageName:     *packageName,
-			IncludeComments: !*noComments,
-			FormatOutput:    !*noFormat,
-			GenerateModels:  true,
-			GenerateClient:  false,
+		if coverageFile != "" {
+			if err := writeCoverageReport(coverageFile, generator, schemaFile, schemaName, outputFile, warnings, *provenance); err != nil {
+				return fmt.Errorf("failed to write the coverage report: %w", err)
+			}
 		}
 
-		options = openapiOptions
-	}
+		if toStdout {
+			output, err := os.ReadFile(outputFile)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stdout.Write(output); err != nil {
+				return fmt.Errorf("failed to write generated code: %w", err)
+			}
+			outputFile = "stdout"
+		}
 
-	config := codegen.GenerateConfig{
-		SchemaPath:  schemaFile,
-		OutputPath:  outputFile,
-		PackageName: *packageName,
-		Options:     options,
-	}
+		status.Info(fmt.Sprintf("Successfully generated Go types using '%s' generator in %s", generator.Name(), outputFile))
 
-	if err := generator.Generate(config); err != nil {
-		log.Fatalf("Failed to generate code: %v", err)
-	}
+		if goPkg != nil && *docFile && !*check && !*dryRun {
+			displayName := schemaName
+			if displayName == "" {
+				displayName = filepath.ToSlash(filepath.Base(schemaFile))
+			}
+			path, err := goPkg.writeDoc(displayName)
+			if err != nil {
+				return fmt.Errorf("failed to write the package documentation: %w", err)
+			}
+			if path != "" {
+				status.Info(fmt.Sprintf("Package documentation written to %s", path))
+			}
+		}
+
+		if coverageFile != "" {
+			status.Info(fmt.Sprintf("Coverage report written to %s", coverageFile))
+		}
 
-	fmt.Printf("Successfully generated Go types using '%s' generator in %s\n", generator.Name(), outputFile)
+		if contractSuite != nil {
+			path := testFilePath(outputFile, *splitMode != "", "contract")
+			if err := os.WriteFile(path, contractSuite.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("failed to write contract tests: %w", err)
+			}
+			status.Info(fmt.Sprintf("Contract tests written to %s", path))
+		}
+
+		if fuzzSuite != nil {
+			if fuzzSuite.Len() == 0 {
+				status.Info("No custom decoders to fuzz in the generated code")
+			} else {
+				path := testFilePath(outputFile, *splitMode != "", "fuzz")
+				if err := os.WriteFile(path, fuzzSuite.Bytes(), 0o644); err != nil {
+					return fmt.Errorf("failed to write fuzz tests: %w", err)
+				}
+				status.Info(fmt.Sprintf("Fuzz tests written to %s", path))
+			}
+		}
+
+		if benchmarkSuite != nil {
+			path := testFilePath(outputFile, *splitMode != "", "bench")
+			if err := os.WriteFile(path, benchmarkSuite.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("failed to write benchmarks: %w", err)
+			}
+			status.Info(fmt.Sprintf("Benchmarks written to %s", path))
+		}
+
+		if *withMocks {
+			path, err := writeMocks(outputFile, *splitMode != "")
+			if err != nil {
+				return fmt.Errorf("failed to write mocks: %w", err)
+			}
+			if path == "" {
+				status.Info("No interfaces to mock in the generated code")
+			} else {
+				status.Info(fmt.Sprintf("Mocks written to %s", path))
+			}
+		}
+
+		if *check || *dryRun {
+			generated := map[string]string{}
+			outputDir := filepath.Dir(outputFile)
+			err := filepath.WalkDir(outputDir, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil || entry.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(outputDir, path)
+				generated[path] = filepath.Join(filepath.Dir(previewedOutput), rel)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if reportFile != "" {
+				generated[reportFile] = *protoReport
+			}
+			if coverageFile != "" {
+				generated[coverageFile] = *coverageReport
+			}
+			changed, err := diffGenerated(generated)
+			switch {
+			case err != nil:
+				return err
+			case *check && len(changed) > 0:
+				return fmt.Errorf("%w: %s", errOutdated, strings.Join(changed, ", "))
+			case *check:
+				slog.Info(fmt.Sprintf("Generated code in %s is up to date", previewedOutput))
+			case len(changed) > 0:
+				slog.Info(fmt.Sprintf("Would change %s", strings.Join(changed, ", ")))
+			default:
+				slog.Info(fmt.Sprintf("No changes to %s", previewedOutput))
+			}
+		}
+
+		return nil
+	}
 }
 
 func showDetailedHelp() {
 	fmt.Printf(`Code Generator Tool
 
 USAGE:
-    %s [flags] <schema-file> <output-file>
+    %s generate [flags] <schema-file> <output-file>
+    %s generate [flags] <schema-file-or-glob>... <output-dir>
+    %s generate [--config codegen.yaml] [--target name]...
+    %s init [--config codegen.yaml] [--yes] [<schema-file>]
+    %s validate [--generator name] <schema-file>...
+    %s list
+    %s lint [--format text|json] [--rule name=severity]... <schema-file>
+    %s diff [--format text|json] <old-schema-file> <new-schema-file>
+    %s bundle [--format json|yaml] [--ref-cache-dir dir] [--offline] <schema-file> <output-file>
+    %s convert [--format json|yaml] [--normalize] <schema-file> <output-file>
+    %s docs <output-dir>
+    %s --version
+
+    Flags are accepted with one dash or two (-package or --package). The former
+    invocation without a command, %s [flags] <schema-file> <output-file>, still
+    runs the generation with a deprecation warning.
 
 ARGUMENTS:
-    <schema-file>   Path to the input schema file (JSON, YAML, or YML)
-    <output-file>   Path where the generated Go code will be written
+    <schema-file>   Path to the input schema file (JSON, YAML, or YML), - to
+                    read it from stdin, or an HTTP(S) URL to download it from
+    <output-file>   Path where the generated Go code will be written, or - to
+                    write it to stdout
+    <output-dir>    Directory the code of several schemas, or of the files
+                    matching glob patterns (quoted, e.g. 'schemas/*.json'), is
+                    generated into, one file per schema named after it
 
 FLAGS:
     -generator string
@@ -145,36 +833,664 @@ FLAGS:
     -package string
         Target Go package name for the generated code (default: "types")
         
+    -go-package-dir string
+        Directory of the Go package the output belongs to, inside a Go
+        module, e.g. '.' in a //go:generate line: -package defaults to the
+        package name of its Go files, or else to the directory name, the
+        output must be in it, and the imports of the generated code must
+        resolve in the module (standard library, packages of the module,
+        modules required by go.mod)
+        
+    -module-path string
+        Import path of the Go module the output must belong to, checked
+        against the closest go.mod; implies -go-package-dir with the
+        directory of the output
+        
+    -doc-file
+        With -go-package-dir or -module-path, also write a doc.go with the
+        package documentation, unless the package has one
+        
+    -input-format string
+        Format of a schema read from stdin (-) or downloaded, as a file
+        extension: json, yaml, avsc, ... (default: the extension of the URL,
+        or json when the schema starts with a brace and yaml otherwise)
+
+    -header string
+        Request header of a schema downloaded from a URL, as 'Name: value'
+        (repeatable, e.g. 'Authorization: Bearer $TOKEN')
+
     -acronyms string
         JSON object defining custom acronyms that should be capitalized in 
         generated Go field names. Example: '{"api":true,"jwt":true}'
         
+    -naming string
+        YAML file with naming rules: 'acronyms' (list of words written in upper
+        case), 'names' (schema name to forced Go identifier) and 'words' (word
+        to spelling, e.g. oauth2: OAuth2). Defaults to .codegen-naming.yaml next
+        to the schema file or in the working directory, when present
+        
     -no-comments
         Disable generation of Go comments from schema descriptions
         
     -no-format
-        Disable automatic 'go fmt' formatting of the output file
+        Disable automatic gofmt formatting of the output file
+        
+    -comment-width int
+        Line width property description comments are wrapped to (default: 80);
+        use a negative value to keep descriptions on a single line
+        
+    -extension-comments
+        Write the vendor extensions (x-*) of definitions, properties and
+        operations that the generator does not interpret as comments, e.g.
+        // x-rate-limit: 100. Templates see them as .Extensions either way
+        
+    -resolve-remote-refs
+        Fetch remote HTTP(S) $ref targets and generate them as local types
+        
+    -ref-cache-dir string
+        Directory used to cache fetched remote documents and downloaded
+        schemas (default: <user cache dir>/inference-gateway/codegen/refs,
+        and .../codegen/schemas for schemas)
+        
+    -offline
+        Resolve remote $refs, and read downloaded schemas, from the cache only;
+        fail fast when a remote document has not been cached yet
+        
+    -decimal-type string
+        Go type generated for 'format: decimal' properties, e.g. 'decimal.Decimal'
+        (default: string or float64, following the property type)
+        
+    -decimal-import string
+        Import path of the package providing -decimal-type,
+        e.g. 'github.com/shopspring/decimal'
+        
+    -defaults
+        Generate an ApplyDefaults() method on structs whose properties declare
+        a 'default', setting zero-valued fields to their schema defaults.
+        These fields are not pointers, so an explicit false, 0 or "" is
+        replaced too: call it before decoding JSON into the value
+        
+    -read-write-variants
+        For models with readOnly/writeOnly properties, also generate XCreate
+        (without readOnly properties) and XRead (without writeOnly properties)
+        
+    -validate-tags
+        Add 'validate' struct tags compatible with go-playground/validator from
+        minLength/maxLength, minimum/maximum, minItems/maxItems, uniqueItems,
+        enum and email/uri/uuid/ipv4/ipv6/hostname formats
+        
+    -validate
+        Generate a Validate() error method on every struct that checks required
+        fields, enums, numeric bounds, string lengths, patterns and array sizes
+        using only the standard library
+        
+    -enum-helpers
+        Generate String() and IsValid() methods plus XValues() and ParseX()
+        functions for every string and integer enum type X; integer enums
+        parse and print their numbers (and the names of iota values)
+        
+    -strict-enums
+        Generate an UnmarshalJSON method for every string enum type that fails
+        with a descriptive error when the value is not one of the allowed values
+        
+    -iota-enums
+        Generate integer enums with contiguous values as iota constants with a
+        String() method and name lookup table; values stay JSON numbers
+        
+    -constructors
+        Generate a NewX constructor for every struct type that takes the
+        schema-required fields as arguments and leaves optional fields unset
+        
+    -getters
+        Generate protobuf-style GetX accessors for optional pointer fields that
+        return the zero value when the field (or the receiver) is nil
+        
+    -example-factories
+        Generate ExampleX and FakeX functions returning a struct populated with
+        sample data: ExampleX uses the schema examples, both fall back to
+        defaults, first enum values and format-aware values (emails, UUIDs,
+        timestamps, ...). Unions hold their first variant. Also for the models
+        of the openapi generator
+        
+    -omit string
+        JSON tag option written for optional fields: omitempty, omitzero (Go 1.24+)
+        or both (default: omitempty). With omitzero, optional struct fields are
+        generated as values instead of pointers
+        
+    -tags string
+        Comma-separated struct tag keys written next to the json tag with the
+        same name (e.g., 'yaml,xml,bson,mapstructure'); optional fields get
+        omitempty, except for mapstructure
         
-    -list
-        List all available generators and their descriptions
+    -tag-template string
+        Go text/template rendering one extra struct tag per field; repeat the
+        flag for several tags. Fields: .Name, .GoName, .SnakeName, .CamelName,
+        .KebabName, .Type and .Required (e.g., 'db:"{{ .SnakeName }}"')
         
+    -preserve-order
+        Generate struct fields in the order their properties are declared in
+        the schema instead of alphabetical order
+        
+    -split string
+        Treat the output argument as a directory and split the generated code
+        into several files: 'kind' writes enums.go, models.go and helpers.go;
+        'type' writes one file per type plus helpers.go
+        
+    -goimports
+        Format the output with goimports instead of gofmt, which also fixes
+        up the import declarations
+        
+    -build-constraint string
+        Build constraint expression written as a //go:build line above the
+        package clause (e.g., '!codeanalysis')
+        
+    -license-file string
+        File whose contents are written as a comment block at the top of the
+        generated code; lines not starting with // are prefixed with it
+        
+    -provenance
+        Extend the "Code generated ... DO NOT EDIT." notice with the generator
+        version (or commit, for development builds, see --version), the schema
+        file and the SHA-256 hash of its contents
+        
+    -timestamp
+        Include the generation time (UTC) in the generated code notice; note
+        that this makes the output change on every run
+        
+    -template-dir string
+        Directory of text/template files overriding the built-in templates:
+        header.tmpl, enum.tmpl, struct.tmpl and alias.tmpl. Other .tmpl files
+        in the directory may {{ define }} templates shared by the overrides
+        
+    -import-mapping string
+        Map a definition, given as $ref pointer or name, to an existing Go type
+        instead of generating it; the import is added where the type is used.
+        Repeatable, e.g. '#/components/schemas/Message=github.com/org/core/types.Message'
+        
+    -defined-types
+        Generate primitive definitions as defined types (type TaskID string)
+        instead of aliases (type TaskID = string), so that different kinds of
+        IDs cannot be mixed up; 'x-go-alias: true|false' overrides it per type
+        
+    -include-types string
+        Comma-separated glob patterns (e.g., 'Task*,Message') of the definitions
+        to generate; definitions they reference are generated as well. With the
+        go2schema generator, the Go types (or with -tool-format, functions)
+        
+    -exclude-types string
+        Comma-separated glob patterns of definitions not to generate; excluded
+        definitions referenced by generated ones are still generated so that
+        the output compiles (use -import-mapping to reuse them instead)
+        
+    -contract-tests
+        Also write a _contract_test.go suite next to the output file (or
+        contract_test.go in the -split directory) with a TestContract case per
+        model: it decodes the schema examples and a generated sample, checks
+        that encoding and decoding them again gives the same JSON, and that the
+        encoding has the required properties, enum values and string formats
+        (date-time, email, uuid, uri, ipv4, ipv6) of the schema. The samples of
+        oneOf and anyOf unions are checked for their round trip. For OpenRPC
+        documents, TestMethodExamples also checks the params and result of the
+        example pairings of every method against their types
+        
+    -fuzz-tests
+        Also write a _fuzz_test.go file next to the output file (or
+        fuzz_test.go in the -split directory) with a FuzzXUnmarshal target per
+        type with a custom UnmarshalJSON (unions, strict enums, overflow maps,
+        tuples, OpenRPC envelopes and params), seeded with the schema examples
+        and a generated sample. The targets check that every decoded value
+        encodes and survives a second decoding and encoding unchanged:
+        go test -fuzz FuzzTaskUnmarshal
+        
+    -benchmarks string
+        Comma-separated glob patterns (e.g., 'ChatCompletion*,Message') of the
+        models to benchmark: a _bench_test.go file next to the output file (or
+        bench_test.go in the -split directory) gets a BenchmarkMarshalX and a
+        BenchmarkUnmarshalX per matching model, encoding and decoding its first
+        schema example (or a generated sample) and reporting allocations and
+        throughput, to compare serialization cost across generator versions
+        
+    -mocks
+        Also write a _mock.go file next to the Go output file (or mocks.go in
+        the -split directory) with a MockX struct per interface of the
+        generated code: server interfaces, JSON-RPC transports, MCP handlers,
+        AsyncAPI publishers and subscribers. Each method counts its calls and
+        calls the XFunc field of the mock, or returns zero values (and a "not
+        implemented" error) when it is nil, so tests need no mocking library
+        
+    -report-renames
+        Print every definition or property renamed to keep the output valid:
+        definitions named after Go keywords, predeclared identifiers or
+        imported packages get an exported name with a trailing underscore
+        (type → Type_), those with diacritics are transliterated (Ärger →
+        Arger), properties named after generated methods a "Field" suffix
+        (ValidateField) and duplicate field names a number (UserID2)
+        
+    -strict-warnings
+        Fail with exit status 5, before generating, when the schema has
+        constructs the generated code drops or renames: keywords it does not
+        reflect, Go type names claimed twice and schemas generated as any
+        (the unsupported-keyword, duplicate-type-name and any-fallback lint
+        rules), and definitions declared as GraphQL custom scalars
+        (scalar-fallback). Without it, they are logged as warnings
+        
+    -coverage-report string
+        Also write a JSON report of the generation: the types of the generated
+        code with the JSON pointer of their schema, the keywords the schemas
+        use and whether the generated types reflect them, and the schemas
+        generated as any or keywords skipped. The report names the generator
+        version with -provenance
+        
+    -pre-parse-hook command
+        Pipe the schema through a shell command before generating, e.g. to add
+        vendor extensions or drop internal definitions (repeatable; the
+        commands run in order)
+        
+    -post-ir-hook command
+        Pipe the definitions the types are generated from, as a JSON object
+        with inline objects, enums and unions hoisted, through a shell command
+        writing them back, e.g. to inject fields or rename types (repeatable;
+        not for the openapi, proto and go2schema generators)
+        
+    -post-emit-hook command
+        Pipe every file written through a shell command, which finds its path
+        in $CODEGEN_FILE, e.g. to add a license header (repeatable)
+        
+    -client
+        Generate the client side of an OpenAPI document next to the models
+        (openapi generator): a Client with one method per operation,
+        <Operation>Stream iterators for text/event-stream and
+        application/x-ndjson responses, New<Operation>Request builders,
+        RequestEditorFn and ResponseHook options for the client or a single
+        call, and a With<Scheme>Auth option per security scheme (API keys,
+        HTTP basic and bearer, OAuth 2.0 tokens and client credentials).
+        With the jsonrpc generator and an OpenRPC document: a Client with one
+        method per OpenRPC method, taking its Params and returning its Result,
+        over a Transport (HTTPTransport, or ConnTransport for WebSockets)
+        
+    -server
+        Generate the server side of an OpenAPI document next to the models
+        (openapi generator): a ServerInterface with one method per operation,
+        Params structs for path and query parameters, and RegisterHandlers
+        routing requests with a Go 1.22 http.ServeMux. With the jsonrpc
+        generator and an OpenRPC document: a ServerInterface with one handler
+        per OpenRPC method and a Dispatcher answering single and batched
+        requests, over HTTP or with HandleMessage
+        
+    -server-framework string
+        Also generate router registration for chi, gin or echo
+        (RegisterChiHandlers, RegisterGinHandlers, RegisterEchoHandlers) next to
+        the http.ServeMux one; implies -server (default: stdlib)
+        
+    -strict-server
+        Also generate a StrictServerInterface whose handlers take a request
+        object and return one response type per declared status code and
+        media type (GetTask200JSONResponse, ...), and NewStrictHandler writing
+        those responses; implies -server
+
+    -validation-middleware
+        Also generate ValidateRequests, a net/http middleware checking the
+        parameters and JSON request bodies of the operations against their
+        schemas and answering violations with 400 application/problem+json
+        (RFC 7807) responses; implies -server
+
+    -mock-server
+        Also generate MockServer, answering every operation with the example of
+        its first success response (or data generated from its schema) unless
+        its <Operation>Func hook is set, and StartMockServer serving it on a
+        local httptest server; implies -server
+
+    -agent-card
+        Also generate AgentCardPath (/.well-known/agent-card.json),
+        AgentCardHandler serving an agent card as JSON, and FetchAgentCard
+        getting the card of a remote agent (a2a generator)
+
+    -agent-card-file string
+        Agent card JSON file checked for the required properties of the
+        AgentCard schema and embedded as the EmbeddedAgentCard variable (a2a
+        generator); implies -agent-card
+
+    -task-state-helpers
+        Also generate the IsTerminal (completed, canceled, failed, rejected)
+        and IsInterrupted (input-required, auth-required) methods of TaskState
+        (a2a generator)
+
+    -tool-format string
+        Write the tool definitions of the functions of the Go package marked
+        with //go2schema:tool (or, when none is, of the exported functions
+        taking a struct of the package after an optional context.Context)
+        instead of JSON Schema: generic, openai or anthropic (go2schema
+        generator). With a .go output file, the generator writes CallTool, a
+        dispatcher calling them, and ToolDefinitions in this format (default:
+        generic)
+
+    -proto-numbering string
+        Field numbering of the messages (proto generator): declaration numbers
+        the fields in property order, alphabetical in the alphabetical order of
+        the properties, and hash after a hash of the property name, so that
+        adding or reordering properties keeps the other numbers (at the cost of
+        larger field tags). 'x-proto-number: n' pins the number of a property
+        (default: declaration)
+
+    -proto-report string
+        Also write a mapping report of the schemas to the messages, enums and
+        inlined types, with the field and number of every property and the
+        conversions losing information (proto generator); JSON when the file
+        ends in .json, Markdown otherwise
+
+    -go-package string
+        go_package option of the generated .proto file, e.g.
+        'github.com/org/api/gen;apiv1' (proto generator, whose proto package
+        is -package)
+
+    -ts-enum-style string
+        Declaration of string enums (typescript generator): union declares
+        them as union types of their values (type Status = "active" |
+        "archived"), enum as TypeScript enums, const enums in .d.ts files.
+        Inline enums are always union types (default: union)
+
+    -graphql-nullability string
+        Fields of the object types declared non-null (graphql generator):
+        required declares the required properties that are not nullable
+        non-null, non-null every property that is not nullable, and nullable
+        none, list items included. Input fields are non-null when required
+        (default: required)
+
+    -graphql-no-inputs
+        Do not declare an input type (<Type>Input) with the writable fields
+        of each object type (graphql generator)
+
+    -avro-namespace string
+        Namespace of the records and enums of the Avro schemas written from
+        JSON Schema, OpenAPI and OpenRPC documents (avro generator)
+
+    -sql-dialect string
+        SQL dialect of the CREATE TABLE statements (sql generator): only
+        postgres for now (default: postgres)
+
+    -sql-type format=type
+        Column type of the properties of a JSON Schema format or type, the
+        format taking precedence (sql generator, repeatable), e.g.
+        -sql-type date-time=TIMESTAMP -sql-type string=VARCHAR(255)
+
+    -mermaid-diagram string
+        Diagram of the definitions (mermaid generator): class draws a
+        classDiagram of the objects, enums and unions, er an erDiagram of
+        the objects and their relationships (default: class)
+
+    -include-tags string
+        Comma-separated tags of the operations to generate (openapi generator);
+        with -include-operations, operations matching either are generated
+
+    -include-operations string
+        Comma-separated glob patterns (e.g., 'createChatCompletion,list*') of
+        the operationIds of the operations to generate (openapi generator)
+
+    -exclude-paths string
+        Comma-separated glob patterns (e.g., '/admin,/v1/*/internal') of the
+        paths whose operations are skipped, together with the paths below them
+        (openapi generator); with any of the three options, only the models
+        the generated operations use are generated
+
+    -config string
+        Configuration file listing the generation targets, run when no schema
+        is given (default: codegen.yaml)
+
+    -target string
+        Name or output of a target of the configuration file to run
+        (repeatable, default: all targets)
+
+    -check
+        Generate into memory and compare with the output files instead of
+        writing them: print a unified diff of the files that differ or are
+        missing and exit with status 1 (applies to every target of a
+        configuration file)
+
+    -dry-run
+        Generate into memory and print the changes to the output files as a
+        unified diff, against /dev/null for missing files, instead of writing
+        them (applies to every target of a configuration file)
+
+    -watch
+        Regenerate whenever the schema, or the configuration file and the
+        schemas of its targets, change, printing the generated files created,
+        changed or removed, until interrupted
+
+    -merge
+        Generate the definitions of several schemas into one output file
+        instead of a file per schema. Cross-schema $refs point at the merged
+        definitions; identical definitions are kept once and differing ones
+        are an error. Methods and operations are not merged
+
+    -v, -verbose
+        Also log debug messages: the generator and files of each generation,
+        the targets run and the schemas downloaded (every command)
+
+    -q, -quiet
+        Only log warnings and errors (every command)
+
+    -log-format string
+        Format of the messages written to stderr: text (default) or json, one
+        object per line with the file, pointer, line and column of the errors
+        located in a schema file (every command)
+
     -help
         Show this detailed help message
 
 EXAMPLES:
     # Auto-detect generator and use default settings
-    %s schema.json types.go
+    %s generate schema.json types.go
     
     # Specify generator explicitly and custom package name
-    %s -generator jsonrpc -package models schema.yaml models.go
+    %s generate -generator jsonrpc -package models schema.yaml models.go
     
     # Use custom acronyms and disable comments
-    %s -acronyms '{"api":true,"http":true}' -no-comments schema.json types.go
+    %s generate -acronyms '{"api":true,"http":true}' -no-comments schema.json types.go
+    
+    # Resolve remote $refs, caching documents in a project directory
+    %s generate -resolve-remote-refs -ref-cache-dir .refcache openrpc.json types.go
+    
+    # Map 'format: decimal' to shopspring/decimal
+    %s generate -decimal-type decimal.Decimal -decimal-import github.com/shopspring/decimal schema.json types.go
+    
+    # Convert OpenAPI schemas to proto3 messages with hash-based field numbers
+    %s generate -package api.v1 -proto-numbering hash -proto-report mapping.md openapi.yaml api.proto
+    
+    # Generate TypeScript declarations with the same type names as the Go types
+    %s generate openapi.yaml web/src/api.d.ts
     
     # List available generators
-    %s -list
+    %s list
+
+    # Add a target for a schema to codegen.yaml, answering a few questions
+    %s init schemas/openapi.yaml
+
+    # Normalize a schema in place, with sorted keys and canonical numbers
+    %s convert --normalize openapi.yaml openapi.yaml
+
+    # Print the version, commit and build date of the generator
+    %s --version
+
+    # Generate from a published schema, downloaded again only when it changed
+    %s generate -generator openapi -client https://example.com/openapi.yaml client.go
+
+    # Generate from a schema piped in and print the code
+    curl -s https://example.com/openapi.yaml | %s generate -generator openapi - -
+
+    # Run the targets of codegen.yaml, or only the one named api
+    %s generate
+    %s generate --target api
+
+    # Fail in CI when the generated code is stale
+    %s generate --check
+
+    # Review the changes a schema update makes to the generated code
+    %s generate --dry-run openapi.yaml api.go | less
+
+    # Regenerate on every change of the schema while editing it
+    %s generate --watch openapi.yaml api.go
+
+    # Log the errors as JSON for a CI annotation step
+    %s generate --check --log-format json
+
+    # Generate a file per schema into one package, or merge them into one file
+    %s generate 'schemas/*.json' types
+    %s generate --merge 'schemas/*.json' types/types.go
+
+COMMANDS:
+    generate    Generate code from a schema, or the targets of a configuration file
+    init        Write a codegen.yaml target for a schema by answering a few questions
+    validate    Check that schemas can be generated from
+    list        List the available generators
+    lint        Report the issues of a schema that degrade the generated code
+    diff        Report the changes between two versions of a schema
+    bundle      Resolve the $refs of a schema into a single self-contained document
+    convert     Translate a schema between JSON and YAML, and normalize it
+    docs        Write the Markdown reference of the commands
+
+    Run %s <command> --help for the flags of a command.
+
+CONFIGURATION FILE:
+    Each target of the configuration file has a schema, an output, and
+    optionally a name, generator, package and options: the flags of the
+    generator by name (e.g. enum-helpers: true, tags: [yaml, mapstructure]).
+    Targets run in order, stopping at the first that fails, and their paths
+    are relative to the directory of the configuration file.
+
+EXIT STATUS:
+    0    Success
+    1    Other failures: usage, files, outdated code with -check, lint
+         errors, breaking changes
+    2    Invalid schema: unreadable, or rejected by the generator
+    3    The generator failed on a valid schema
+    4    The generated code could not be formatted
+    5    Generation warnings with -strict-warnings
+
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+}
+
+// errOutdated is returned in check mode when the output files differ from the generated ones
+var errOutdated = errors.New("generated code is out of date")
 
-`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+// diffGenerated compares generated files with the existing files they stand for, printing a
+// unified diff of those that differ or are missing, against /dev/null for the latter. It
+// returns the names of the existing files that differ.
+func diffGenerated(generated map[string]string) ([]string, error) {
+	paths := make([]string, 0, len(generated))
+	for path := range generated {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changed []string
+	for _, path := range paths {
+		existing := generated[path]
+		want, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		have, err := os.ReadFile(existing)
+		beforeName := existing
+		if errors.Is(err, fs.ErrNotExist) {
+			beforeName = "/dev/null"
+		} else if err != nil {
+			return nil, err
+		}
+		if diff := unifiedDiff(beforeName, existing, have, want); diff != "" {
+			fmt.Print(diff)
+			changed = append(changed, existing)
+		}
+	}
+	return changed, nil
+}
+
+// writeTempSchema writes a schema read from stdin or downloaded into dir, in a file with the
+// given name and named after its format: the given one, or else json when it starts with a
+// brace and yaml otherwise
+func writeTempSchema(dir, name string, data []byte, format string) (string, error) {
+	if format == "" {
+		format = "yaml"
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			format = "json"
+		}
+	}
+	path := filepath.Join(dir, name+"."+strings.TrimPrefix(format, "."))
+	if len(codegen.GetByFormat(path)) == 0 {
+		return "", fmt.Errorf("unsupported input format %q", format)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// outputExtension returns the extension of the files a generator writes by default: its first
+// output format, or .go
+func outputExtension(generator codegen.Generator) string {
+	if formatter, ok := generator.(codegen.OutputFormatter); ok {
+		return formatter.OutputFormats()[0]
+	}
+	return ".go"
+}
+
+// namingRulesPath returns the naming rules file to load: the one given with -naming, or else
+// the first existing default file next to the schema or in the working directory
+func namingRulesPath(flagValue string, schemaFile string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	for _, path := range []string{filepath.Join(filepath.Dir(schemaFile), jrpc.NamingConfigFile), jrpc.NamingConfigFile} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// testFilePath returns the file the contract tests, fuzz tests or benchmarks are written
+// to: next to the output file, or in the output directory in split mode
+func testFilePath(outputFile string, split bool, kind string) string {
+	if split {
+		return filepath.Join(outputFile, kind+"_test.go")
+	}
+	return strings.TrimSuffix(outputFile, ".go") + "_" + kind + "_test.go"
+}
+
+// mockPath returns the file the mocks are written to: next to the output file, or in the
+// output directory in split mode
+func mockPath(outputFile string, split bool) string {
+	if split {
+		return filepath.Join(outputFile, "mocks.go")
+	}
+	return strings.TrimSuffix(outputFile, ".go") + "_mock.go"
+}
+
+// writeMocks writes the mocks of the interfaces of the generated Go code, returning the path
+// written, or "" when the code declares no interfaces to mock
+func writeMocks(outputFile string, split bool) (string, error) {
+	path := mockPath(outputFile, split)
+	files := []string{outputFile}
+	if split {
+		files, _ = filepath.Glob(filepath.Join(outputFile, "*.go"))
+	} else if filepath.Ext(outputFile) != ".go" {
+		return "", fmt.Errorf("-mocks applies to Go output files, not %s", outputFile)
+	}
+	var sources [][]byte
+	for _, file := range files {
+		if file == path || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		sources = append(sources, source)
+	}
+	source, err := mocks.Generate(sources...)
+	if err != nil || source == nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, source, 0o644)
 }
 
 func listGenerators() {
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// taskSchema is a JSON-RPC schema with a single type definition
+const taskSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"definitions": {
+		"Task": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {"id": {"type": "string"}}
+		}
+	}
+}`
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"plain error", errors.New("failed"), exitFailure},
+		{"outdated", errOutdated, exitFailure},
+		{"schema error", &codegen.SchemaError{Err: errors.New("invalid")}, exitValidation},
+		{"generation error", withExitCode(exitGeneration, errors.New("failed")), exitGeneration},
+		{"format error", &codegen.FormatError{Err: errors.New("syntax error")}, exitFormatting},
+		{"wrapped schema error", withExitCode(exitGeneration, &codegen.SchemaError{Err: errors.New("invalid")}), exitValidation},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := exitCode(test.err); got != test.code {
+				t.Errorf("exitCode(%v) = %d, want %d", test.err, got, test.code)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.json")
+	if err := os.WriteFile(schema, []byte(taskSchema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"definitions": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "types.go")
+	run := func(args ...string) error {
+		return generate(flag.NewFlagSet("generate", flag.ContinueOnError), args)
+	}
+
+	if err := run(schema, output); err != nil {
+		t.Fatal(err)
+	}
+	generated, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdated := filepath.Join(dir, "outdated.go")
+	if err := os.WriteFile(outdated, []byte("package types\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code int // the exit status, 0 for success
+		file string
+		want string // the content of file after the run
+	}{
+		{"check up to date", []string{"-check", schema, output}, 0, output, string(generated)},
+		{"check outdated", []string{"-check", schema, outdated}, exitFailure, outdated, "package types\n"},
+		{"check missing", []string{"-check", schema, filepath.Join(dir, "missing.go")}, exitFailure, "", ""},
+		{"dry run", []string{"-dry-run", schema, outdated}, 0, outdated, "package types\n"},
+		{"check and dry run", []string{"-check", "-dry-run", schema, output}, exitFailure, output, string(generated)},
+		{"invalid schema", []string{invalid, filepath.Join(dir, "invalid.go")}, exitValidation, "", ""},
+		{"missing arguments", []string{schema}, exitFailure, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := run(test.args...)
+			code := 0
+			if err != nil {
+				code = exitCode(err)
+			}
+			if code != test.code {
+				t.Fatalf("exit status = %d (%v), want %d", code, err, test.code)
+			}
+			if test.file == "" {
+				return
+			}
+			content, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != test.want {
+				t.Errorf("%s was rewritten", filepath.Base(test.file))
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// isSchemaGlob reports whether a schema argument is a glob pattern of schema files
+func isSchemaGlob(schemaFile string) bool {
+	return !isSchemaURL(schemaFile) && strings.ContainsAny(schemaFile, "*?[")
+}
+
+// expandSchemas returns the schemas of the arguments, with the glob patterns replaced by the
+// files they match, in order and without duplicates
+func expandSchemas(args []string) ([]string, error) {
+	var schemas []string
+	for _, arg := range args {
+		if arg == "-" {
+			return nil, fmt.Errorf("a schema read from stdin (-) cannot be generated with other schemas")
+		}
+		if !isSchemaGlob(arg) {
+			schemas = append(schemas, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no schema file matches %s", arg)
+		}
+		schemas = append(schemas, matches...)
+	}
+	var unique []string
+	for _, schema := range schemas {
+		if !slices.Contains(unique, schema) {
+			unique = append(unique, schema)
+		}
+	}
+	return unique, nil
+}
+
+// generateSchemas generates several schemas: each into a file of the output directory named
+// after it, or, when merging, their definitions together into the output file. Outdated
+// files of check mode are all reported before failing.
+func generateSchemas(flags *flag.FlagSet, schemas []string, output string, merge bool, run generationRun) error {
+	if merge {
+		return generateMerged(schemas, output, run)
+	}
+	if output == "-" {
+		return fmt.Errorf("several schemas are generated into an output directory, or merged into stdout with --merge")
+	}
+	if flags.Lookup("split").Value.String() != "" {
+		return fmt.Errorf("--split writes a directory per schema and cannot generate several schemas into one package; use --merge")
+	}
+
+	extension := ".go"
+	if name := flags.Lookup("generator").Value.String(); name != "" {
+		generator, err := codegen.Get(name)
+		if err != nil {
+			return fmt.Errorf("generator not found: %w", err)
+		}
+		extension = outputExtension(generator)
+	}
+
+	// Each schema is generated into a file named after it, so that schemas with the same name
+	// in different directories are rejected rather than overwritten
+	outputs := make([]string, len(schemas))
+	generatedFrom := map[string]string{}
+	for i, schema := range schemas {
+		name := filepath.Base(schema)
+		if isSchemaURL(schema) {
+			name = path.Base(schema)
+		}
+		outputs[i] = filepath.Join(output, strings.TrimSuffix(name, filepath.Ext(name))+extension)
+		if other, ok := generatedFrom[outputs[i]]; ok {
+			return fmt.Errorf("%s and %s would both be generated into %s", other, schema, outputs[i])
+		}
+		generatedFrom[outputs[i]] = schema
+	}
+
+	slog.Debug(fmt.Sprintf("Generating %d schemas", len(schemas)), "output", output)
+	var outdated []string
+	for i, schema := range schemas {
+		if err := run(schema, outputs[i], ""); err != nil {
+			if errors.Is(err, errOutdated) {
+				outdated = append(outdated, schema)
+				continue
+			}
+			return schemaFileError(schema, err)
+		}
+	}
+	if len(outdated) > 0 {
+		return fmt.Errorf("%w: schemas %s", errOutdated, strings.Join(outdated, ", "))
+	}
+
+	// Check and dry-run modes leave the output files as they are, and those were checked when
+	// written
+	if extension != ".go" || flags.Lookup("check").Value.String() == "true" || flags.Lookup("dry-run").Value.String() == "true" {
+		return nil
+	}
+	return duplicateDeclarations(outputs, schemas)
+}
+
+// generateMerged generates the merged definitions of several schema files into the output
+func generateMerged(schemas []string, output string, run generationRun) error {
+	for _, schema := range schemas {
+		if isSchemaURL(schema) {
+			return fmt.Errorf("--merge reads schema files and cannot merge %s", schema)
+		}
+	}
+	document, err := jrpc.MergeSchemas(schemas)
+	if err != nil {
+		return fmt.Errorf("failed to merge schemas: %w", err)
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode merged schema: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "generator-merge")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	mergedFile := filepath.Join(tempDir, "merged.json")
+	if err := os.WriteFile(mergedFile, data, 0o644); err != nil {
+		return err
+	}
+	return run(mergedFile, output, strings.Join(schemas, ", "))
+}
+
+// duplicateDeclarations returns an error naming the top-level declarations, and methods,
+// that several of the generated Go files of a package declare, with the schemas of the files
+func duplicateDeclarations(files, schemas []string) error {
+	declaredBy := map[string]string{}
+	var duplicates []string
+	fileSet := token.NewFileSet()
+	for i, file := range files {
+		parsed, err := parser.ParseFile(fileSet, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for _, name := range declaredNames(parsed) {
+			if first, ok := declaredBy[name]; ok {
+				duplicates = append(duplicates, fmt.Sprintf("%s (%s and %s)", name, first, schemas[i]))
+				continue
+			}
+			declaredBy[name] = schemas[i]
+		}
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("the generated files declare %s more than once: merge identical definitions with --merge, or leave them out of a schema with --exclude-types",
+			strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// declaredNames returns the names of the top-level declarations of a Go file, with the
+// methods named after their receiver type (Type.Method)
+func declaredNames(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				if decl.Name.Name != "init" {
+					names = append(names, decl.Name.Name)
+				}
+				continue
+			}
+			receiver := decl.Recv.List[0].Type
+			if star, ok := receiver.(*ast.StarExpr); ok {
+				receiver = star.X
+			}
+			switch generic := receiver.(type) {
+			case *ast.IndexExpr:
+				receiver = generic.X
+			case *ast.IndexListExpr:
+				receiver = generic.X
+			}
+			if ident, ok := receiver.(*ast.Ident); ok {
+				names = append(names, ident.Name+"."+decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							names = append(names, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// isSchemaURL reports whether a schema argument is an HTTP(S) URL to download
+func isSchemaURL(schemaFile string) bool {
+	return strings.HasPrefix(schemaFile, "https://") || strings.HasPrefix(schemaFile, "http://")
+}
+
+// schemaCacheDir returns the directory downloaded schemas are cached in: the given one, or
+// else a directory of the user cache
+func schemaCacheDir(cacheDir string) (string, error) {
+	if cacheDir != "" {
+		return cacheDir, nil
+	}
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory for schemas: %w", err)
+	}
+	return filepath.Join(userCacheDir, "inference-gateway", "codegen", "schemas"), nil
+}
+
+// fetchSchema downloads a schema with the given request headers. The schema and its ETag are
+// kept in cacheDir, so that it is only downloaded again when the server reports a change. In
+// offline mode, or when the server cannot be reached, the cached schema is used.
+func fetchSchema(schemaURL string, headers http.Header, cacheDir string, offline bool) ([]byte, error) {
+	sum := sha256.Sum256([]byte(schemaURL))
+	cachePath := filepath.Join(cacheDir, hex.EncodeToString(sum[:]))
+	etagPath := cachePath + ".etag"
+	cached, cacheErr := os.ReadFile(cachePath)
+
+	if offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("schema %s is not cached and offline mode is enabled", schemaURL)
+		}
+		slog.Debug("Using the cached schema in offline mode", "url", schemaURL, "cache", cachePath)
+		return cached, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema URL %s: %w", schemaURL, err)
+	}
+	request.Header = headers.Clone()
+	if cacheErr == nil {
+		if etag, err := os.ReadFile(etagPath); err == nil {
+			request.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	slog.Debug("Fetching schema", "url", schemaURL, "etag", request.Header.Get("If-None-Match"))
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(request)
+	if err != nil {
+		if cacheErr == nil {
+			slog.Warn(fmt.Sprintf("Failed to fetch %s, using the cached schema: %v", schemaURL, err))
+			return cached, nil
+		}
+		return nil, fmt.Errorf("failed to fetch schema %s: %w", schemaURL, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn(fmt.Sprintf("Failed to close response body: %v", closeErr))
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cacheErr == nil:
+		slog.Debug("Using the cached schema, not modified", "url", schemaURL, "cache", cachePath)
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch schema %s: unexpected status %s", schemaURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema %s: %w", schemaURL, err)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create schema cache directory: %w", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write schema cache: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		err = os.WriteFile(etagPath, []byte(etag), 0o644)
+	} else if err = os.Remove(etagPath); os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write schema cache: %w", err)
+	}
+
+	return data, nil
+}
+
+// parseHeader parses a request header given as "Name: value"
+func parseHeader(value string) (name, headerValue string, err error) {
+	name, headerValue, ok := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("expected 'Name: value', got %q", value)
+	}
+	return name, strings.TrimSpace(headerValue), nil
+}
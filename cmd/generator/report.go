@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// coverageReport is the JSON report -coverage-report writes: what a generation made of the
+// schemas of a document, to quantify its coverage and compare it across generator versions
+type coverageReport struct {
+	Schema     string              `json:"schema"`
+	Generator  string              `json:"generator"`
+	Version    string              `json:"version,omitempty"` // Generator module and version, with -provenance
+	Summary    coverageSummary     `json:"summary"`
+	Types      []reportedType      `json:"types"`
+	Keywords   []jrpc.KeywordUsage `json:"keywords"`
+	Downgraded []jrpc.Finding      `json:"downgraded"` // Schemas generated as any
+	Skipped    []jrpc.Finding      `json:"skipped"`    // Keywords not reflected, and definitions dropped or renamed
+}
+
+// coverageSummary counts the entries of a coverage report
+type coverageSummary struct {
+	Types           int     `json:"types"`
+	Keywords        int     `json:"keywords"`
+	KeywordCoverage float64 `json:"keyword_coverage"` // Share of the keyword uses the generated types reflect, from 0 to 1
+	Downgraded      int     `json:"downgraded"`
+	Skipped         int     `json:"skipped"`
+}
+
+// reportedType is a type of the generated code, with the schema it is generated from when it
+// has one: helpers, such as the client of an API, have none
+type reportedType struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"` // struct, interface, alias or defined for Go code
+	Path string `json:"path,omitempty"` // JSON pointer to the schema of the type
+}
+
+// writeCoverageReport writes the coverage report of the generation of schemaFile into output
+// with generator to reportFile. warnings are the generation warnings of the schema (see
+// generationWarnings); schemaName is the name the report gives a schema read from stdin, a
+// URL or merged schemas.
+func writeCoverageReport(reportFile string, generator codegen.Generator, schemaFile, schemaName, output string, warnings []jrpc.Finding, withVersion bool) error {
+	coverage, err := jrpc.SchemaFileCoverage(schemaFile)
+	if err != nil {
+		return err
+	}
+	if schemaName == "" {
+		schemaName = filepath.ToSlash(schemaFile)
+	}
+	report := coverageReport{
+		Schema:     schemaName,
+		Generator:  generator.Name(),
+		Keywords:   coverage.Keywords,
+		Types:      []reportedType{},
+		Downgraded: []jrpc.Finding{},
+		Skipped:    []jrpc.Finding{},
+	}
+	if withVersion {
+		report.Version = generatorVersion()
+	}
+	if report.Keywords == nil {
+		report.Keywords = []jrpc.KeywordUsage{}
+	}
+	for _, warning := range warnings {
+		if warning.Rule == jrpc.RuleAnyFallback || warning.Rule == jrpc.RuleScalarFallback {
+			report.Downgraded = append(report.Downgraded, warning)
+		} else {
+			report.Skipped = append(report.Skipped, warning)
+		}
+	}
+
+	// The types of Go code are those it declares; other outputs are reported with the types the
+	// schemas would be generated as in Go
+	declared, err := declaredTypes(output)
+	if err != nil {
+		return err
+	}
+	if declared == nil {
+		for name := range coverage.Types {
+			declared = append(declared, reportedType{Name: name})
+		}
+	}
+	for _, declaredType := range declared {
+		declaredType.Path = coverage.Types[declaredType.Name]
+		report.Types = append(report.Types, declaredType)
+	}
+	sort.Slice(report.Types, func(i, j int) bool {
+		return report.Types[i].Name < report.Types[j].Name
+	})
+
+	report.Summary = coverageSummary{
+		Types:           len(report.Types),
+		Keywords:        len(report.Keywords),
+		KeywordCoverage: math.Round(coverage.KeywordCoverage()*10000) / 10000,
+		Downgraded:      len(report.Downgraded),
+		Skipped:         len(report.Skipped),
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode coverage report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(reportFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(reportFile, append(data, '\n'), 0o644)
+}
+
+// declaredTypes returns the types the generated Go code of output declares, a file or the
+// directory of -split, or nil when the output is not Go code
+func declaredTypes(output string) ([]reportedType, error) {
+	files := []string{output}
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(output, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		files = matches
+	} else if filepath.Ext(output) != ".go" {
+		return nil, nil
+	}
+
+	types := []reportedType{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range parsed.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				kind := "defined"
+				switch typeSpec.Type.(type) {
+				case *ast.StructType:
+					kind = "struct"
+				case *ast.InterfaceType:
+					kind = "interface"
+				}
+				if typeSpec.Assign.IsValid() {
+					kind = "alias"
+				}
+				types = append(types, reportedType{Name: typeSpec.Name.Name, Kind: kind})
+			}
+		}
+	}
+	return types, nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a unified diff hunk
+const diffContext = 3
+
+// maxDiffEdits bounds the number of edits searched for the shortest edit script: beyond it,
+// the lines between the common prefix and suffix are shown as removed and added as a whole
+const maxDiffEdits = 2000
+
+// lineEdit is a line of an edit script: kept (' '), removed ('-') or added ('+')
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff turning before into after, with the file names given
+// in its header, or "" when they are equal
+func unifiedDiff(beforeName, afterName string, before, after []byte) string {
+	edits := diffLines(splitLines(before), splitLines(after))
+	if !slices.ContainsFunc(edits, func(edit lineEdit) bool { return edit.op != ' ' }) {
+		return ""
+	}
+
+	// Lines of before and after preceding each edit, for the hunk headers
+	beforeLines := make([]int, len(edits)+1)
+	afterLines := make([]int, len(edits)+1)
+	for i, edit := range edits {
+		beforeLines[i+1], afterLines[i+1] = beforeLines[i], afterLines[i]
+		if edit.op != '+' {
+			beforeLines[i+1]++
+		}
+		if edit.op != '-' {
+			afterLines[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", beforeName, afterName)
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		// A hunk goes on while the next change is close enough for their contexts to meet
+		last := i
+		for {
+			next := last + 1
+			for next < len(edits) && edits[next].op == ' ' {
+				next++
+			}
+			if next == len(edits) || next-last-1 > 2*diffContext {
+				break
+			}
+			last = next
+		}
+		start, end := max(0, i-diffContext), min(len(edits), last+1+diffContext)
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(beforeLines[start], beforeLines[end]-beforeLines[start]),
+			hunkRange(afterLines[start], afterLines[end]-afterLines[start]))
+		for _, edit := range edits[start:end] {
+			out.WriteByte(edit.op)
+			out.WriteString(edit.line)
+			out.WriteByte('\n')
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange returns the range of a hunk header from the number of lines before the hunk and
+// the number of lines in it, which starts at the preceding line when empty
+func hunkRange(preceding, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", preceding)
+	}
+	if count == 1 {
+		return fmt.Sprint(preceding + 1)
+	}
+	return fmt.Sprintf("%d,%d", preceding+1, count)
+}
+
+// diffLines returns the shortest edit script turning a into b, found with Myers' algorithm
+// between their common prefix and suffix
+func diffLines(a, b []string) []lineEdit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]lineEdit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	edits = append(edits, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	return edits
+}
+
+// myersDiff returns the shortest edit script turning a into b, or the removal of a and the
+// addition of b when it takes more than maxDiffEdits edits
+func myersDiff(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	// v[k] is the furthest x reached on diagonal k = x - y; trace[d] keeps diagonals -d..d of v
+	// before the d-th edit, to walk the script back
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		if d > maxDiffEdits {
+			return replaceLines(a, b)
+		}
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackEdits(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackEdits walks back the edits recorded by myersDiff from the end of a and b
+func backtrackEdits(a, b []string, trace [][]int) []lineEdit {
+	var edits []lineEdit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var previous int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			previous = k + 1
+		} else {
+			previous = k - 1
+		}
+		previousX := v[previous+d]
+		previousY := previousX - previous
+		for x > previousX && y > previousY {
+			edits = append(edits, lineEdit{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == previousX {
+			edits = append(edits, lineEdit{'+', b[y-1]})
+			y--
+		} else {
+			edits = append(edits, lineEdit{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, lineEdit{' ', a[x-1]})
+		x--
+		y--
+	}
+	slices.Reverse(edits)
+	return edits
+}
+
+// replaceLines returns the edit script removing a and adding b
+func replaceLines(a, b []string) []lineEdit {
+	edits := make([]lineEdit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, lineEdit{'-', line})
+	}
+	for _, line := range b {
+		edits = append(edits, lineEdit{'+', line})
+	}
+	return edits
+}
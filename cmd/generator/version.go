@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// The version, commit and build date of the generator, set when building releases with
+// -ldflags "-X main.version=v1.2.3 -X main.commit=... -X main.date=...". Those left empty are
+// read from the build information Go embeds: the module version with go install, and the VCS
+// revision and time when building from a checkout.
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+// generatorModule names the generator in the generated code notice
+const generatorModule = "github.com/inference-gateway/tools/cmd/generator"
+
+// buildInfo describes the build of the running generator
+type buildInfo struct {
+	Version  string // Release version, e.g. v1.2.3, or empty for development builds
+	Commit   string // VCS revision the generator was built from, or empty when unknown
+	Date     string // Build date, or the time of Commit, or empty when unknown
+	Modified bool   // Whether the checkout had uncommitted changes
+}
+
+// readBuildInfo returns the build information of the running generator, from the -ldflags
+// variables and else from debug.ReadBuildInfo
+func readBuildInfo() buildInfo {
+	build := buildInfo{
+		Version: strings.TrimSpace(version),
+		Commit:  strings.TrimSpace(commit),
+		Date:    strings.TrimSpace(date),
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	if build.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		build.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if build.Commit == "" {
+				build.Commit = setting.Value
+			}
+		case "vcs.time":
+			if build.Date == "" {
+				build.Date = setting.Value
+			}
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// shortCommit returns the first 12 characters of the commit, followed by -dirty when the
+// checkout was modified
+func (b buildInfo) shortCommit() string {
+	short := b.Commit
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	if short != "" && b.Modified {
+		short += "-dirty"
+	}
+	return short
+}
+
+// String returns the version printed by --version, e.g.
+// "v1.2.3 (commit 0123456789ab, built 2025-06-01T12:00:00Z, go1.24.3 linux/amd64)"
+func (b buildInfo) String() string {
+	v := b.Version
+	if v == "" {
+		v = "devel"
+	}
+	details := []string{}
+	if commit := b.shortCommit(); commit != "" {
+		details = append(details, "commit "+commit)
+	}
+	if b.Date != "" {
+		details = append(details, "built "+b.Date)
+	}
+	details = append(details, runtime.Version()+" "+runtime.GOOS+"/"+runtime.GOARCH)
+	return fmt.Sprintf("%s (%s)", v, strings.Join(details, ", "))
+}
+
+// generatorVersion returns the module path and version of the running generator, named in
+// the generated code notice with -provenance: the release version, or else the commit of a
+// development build, so that generated files can be traced back to the generator
+func generatorVersion() string {
+	build := readBuildInfo()
+	switch {
+	case build.Version != "":
+		return generatorModule + "@" + build.Version
+	case build.Commit != "":
+		return generatorModule + "@" + build.shortCommit()
+	}
+	return generatorModule
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the watch mode waits for the changes of a file to settle before
+// regenerating, since editors write files in several steps
+const watchDebounce = 200 * time.Millisecond
+
+// watchInputs returns the files and directories a generation reads, and the files and
+// directories it writes. The inputs returned with an error are still watched.
+type watchInputs func() (inputs []string, outputs []string, err error)
+
+// watchAndRegenerate runs the generation, then runs it again whenever one of its inputs
+// changes, printing the generated files it created, changed or removed, until interrupted.
+// The inputs are listed again after every run, since a configuration file may change them.
+// Generation errors are printed and the watch goes on.
+func watchAndRegenerate(list watchInputs, run func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch the inputs: %w", err)
+	}
+	defer watcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// The parent directories are watched rather than the files, so that files replaced by a
+	// rename, as editors save them, are still followed
+	watchedDirs := map[string]bool{}
+	inputs := map[string]bool{}
+	regenerate := func() {
+		files, outputs, err := list()
+		inputs = map[string]bool{}
+		dirs := map[string]bool{}
+		for _, file := range files {
+			file, _ = filepath.Abs(file)
+			inputs[file] = true
+			dirs[filepath.Dir(file)] = true
+			if info, err := os.Stat(file); err == nil && info.IsDir() {
+				dirs[file] = true
+			}
+		}
+		for dir := range watchedDirs {
+			if !dirs[dir] {
+				_ = watcher.Remove(dir)
+				delete(watchedDirs, dir)
+			}
+		}
+		for dir := range dirs {
+			if !watchedDirs[dir] {
+				if err := watcher.Add(dir); err != nil {
+					slog.Error(fmt.Sprintf("Failed to watch %s: %v", dir, err))
+					continue
+				}
+				watchedDirs[dir] = true
+			}
+		}
+
+		start := time.Now()
+		before := readOutputs(outputs)
+		if err == nil {
+			err = run()
+		}
+		if err != nil {
+			logError(err)
+		} else {
+			logOutputChanges(before, readOutputs(outputs))
+			slog.Info(fmt.Sprintf("Regenerated in %s", time.Since(start).Round(time.Millisecond)))
+		}
+		slog.Info("Watching for changes (press Ctrl+C to stop)")
+	}
+
+	cwd, _ := os.Getwd()
+	regenerate()
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	var changed []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) && !event.Has(fsnotify.Remove) {
+				continue
+			}
+			name, _ := filepath.Abs(event.Name)
+			if !inputs[name] && !inputs[filepath.Dir(name)] {
+				continue
+			}
+			if rel, err := filepath.Rel(cwd, name); err == nil && !strings.HasPrefix(rel, "..") {
+				name = rel
+			}
+			changed = append(changed, name)
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logError(err)
+		case <-debounce.C:
+			slices.Sort(changed)
+			slog.Info(fmt.Sprintf("Changed: %s", strings.Join(slices.Compact(changed), ", ")))
+			changed = nil
+			regenerate()
+		}
+	}
+}
+
+// readOutputs returns the contents of the generated files by path: the output files, and the
+// files of the output directories
+func readOutputs(outputs []string) map[string][]byte {
+	files := map[string][]byte{}
+	for _, output := range outputs {
+		entries, err := os.ReadDir(output)
+		if err != nil {
+			if data, err := os.ReadFile(output); err == nil {
+				files[output] = data
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(output, entry.Name())
+			if data, err := os.ReadFile(path); err == nil {
+				files[path] = data
+			}
+		}
+	}
+	return files
+}
+
+// logOutputChanges logs a line per generated file that was created (A), changed (M) or
+// removed (D), with the number of lines added and removed
+func logOutputChanges(before, after map[string][]byte) {
+	paths := make([]string, 0, len(after))
+	for path := range after {
+		paths = append(paths, path)
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := 0
+	for _, path := range paths {
+		old, existed := before[path]
+		current, exists := after[path]
+		added, removed := lineChanges(old, current)
+		switch {
+		case !existed:
+			slog.Info(fmt.Sprintf("  A %s (+%d)", path, added))
+		case !exists:
+			slog.Info(fmt.Sprintf("  D %s (-%d)", path, removed))
+		case string(old) != string(current):
+			slog.Info(fmt.Sprintf("  M %s (+%d -%d)", path, added, removed))
+		default:
+			continue
+		}
+		changes++
+	}
+	if changes == 0 {
+		slog.Info("  No changes to the generated files")
+	}
+}
+
+// lineChanges returns the number of lines of after missing from before, and of before
+// missing from after, regardless of their order
+func lineChanges(before, after []byte) (added, removed int) {
+	counts := map[string]int{}
+	for _, line := range splitLines(before) {
+		counts[line]++
+	}
+	for _, line := range splitLines(after) {
+		counts[line]--
+	}
+	for _, n := range counts {
+		if n > 0 {
+			removed += n
+		} else {
+			added -= n
+		}
+	}
+	return added, removed
+}
+
+// splitLines returns the lines of data, without the empty line after a final newline
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
@@ -0,0 +1,264 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// terminalTaskStates are the task states after which a task no longer changes
+var terminalTaskStates = []string{"completed", "canceled", "failed", "rejected"}
+
+// interruptedTaskStates are the task states in which a task waits for the client
+var interruptedTaskStates = []string{"input-required", "auth-required"}
+
+// streamEventTypes are the definitions of the events of streaming responses, in the order
+// DecodeStreamEvent tries their kinds
+var streamEventTypes = []string{"Task", "Message", "TaskStatusUpdateEvent", "TaskArtifactUpdateEvent"}
+
+// protocol holds the definitions of an A2A schema the protocol helpers are generated from
+type protocol struct {
+	definitions map[string]any
+	options     *jrpc.GeneratorOptions
+}
+
+// newProtocol returns the protocol of a bundled A2A schema document
+func newProtocol(document map[string]any, options *jrpc.GeneratorOptions) *protocol {
+	return &protocol{definitions: schemaDefinitions(document), options: options}
+}
+
+// schemaDefinitions returns the definitions of a JSON Schema document, under definitions or
+// $defs
+func schemaDefinitions(document map[string]any) map[string]any {
+	for _, key := range []string{"definitions", "$defs"} {
+		if definitions, ok := document[key].(map[string]any); ok {
+			return definitions
+		}
+	}
+	return nil
+}
+
+// typeName returns the Go name of a definition, or an empty string when types does not
+// declare it, e.g. because it was filtered out
+func (p *protocol) typeName(definition string, types []byte) string {
+	defMap, ok := p.definitions[definition].(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := defMap["x-go-name"].(string)
+	if name == "" {
+		name = jrpc.GoIdentifier(definition, p.options)
+	}
+	if !regexp.MustCompile(`(?m)^type ` + regexp.QuoteMeta(name) + `\b`).Match(types) {
+		return ""
+	}
+	return name
+}
+
+// generate writes the protocol helpers of the schema after types, the generated types, and
+// reports whether it wrote any
+func (p *protocol) generate(out *bytes.Buffer, types []byte, options *Options, card []byte) bool {
+	length := out.Len()
+	if options.TaskStateHelpers {
+		p.generateTaskStateHelpers(out, types)
+	}
+	p.generateStreamEvents(out, types)
+	if options.AgentCard {
+		p.generateAgentCard(out, types, card)
+	}
+	return out.Len() > length
+}
+
+// generateTaskStateHelpers writes the IsTerminal and IsInterrupted methods of TaskState,
+// matching the states its enum declares
+func (p *protocol) generateTaskStateHelpers(out *bytes.Buffer, types []byte) {
+	typeName := p.typeName("TaskState", types)
+	if typeName == "" {
+		return
+	}
+	defMap, _ := p.definitions["TaskState"].(map[string]any)
+	enum, _ := defMap["enum"].([]any)
+	var states []string
+	for _, value := range enum {
+		if state, ok := value.(string); ok {
+			states = append(states, state)
+		}
+	}
+
+	write := func(method string, doc string, matching []string) {
+		var cases []string
+		for _, state := range matching {
+			if slices.Contains(states, state) {
+				cases = append(cases, strconv.Quote(state))
+			}
+		}
+		if len(cases) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "// %s reports whether %s\n", method, doc)
+		fmt.Fprintf(out, "func (s %s) %s() bool {\n", typeName, method)
+		fmt.Fprintf(out, "\tswitch s {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n\n", strings.Join(cases, ", "))
+	}
+	write("IsTerminal", "the task is over: completed, canceled, failed or rejected", terminalTaskStates)
+	write("IsInterrupted", "the task waits for input or authentication from the client", interruptedTaskStates)
+}
+
+// generateStreamEvents writes the StreamEvent interface implemented by the events of
+// streaming responses, and DecodeStreamEvent decoding them by the const of their kind
+// property. Nothing is written when the schema does not tell the events apart by kind.
+func (p *protocol) generateStreamEvents(out *bytes.Buffer, types []byte) {
+	type event struct{ typeName, kind string }
+	var events []event
+	for _, definition := range streamEventTypes {
+		typeName := p.typeName(definition, types)
+		defMap, _ := p.definitions[definition].(map[string]any)
+		properties, _ := defMap["properties"].(map[string]any)
+		kindMap, _ := properties["kind"].(map[string]any)
+		kind, _ := kindMap["const"].(string)
+		if enum, _ := kindMap["enum"].([]any); kind == "" && len(enum) == 1 {
+			kind, _ = enum[0].(string)
+		}
+		if typeName != "" && kind != "" {
+			events = append(events, event{typeName: typeName, kind: kind})
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	pointers := make([]string, len(events))
+	for i, e := range events {
+		pointers[i] = "*" + e.typeName
+	}
+	out.WriteString("// StreamEvent is an event of a streaming response, such as the results of message/stream\n")
+	fmt.Fprintf(out, "// and tasks/resubscribe: %s\n", joinAlternatives(pointers))
+	out.WriteString("type StreamEvent interface {\n\tstreamEvent()\n}\n\n")
+	for _, e := range events {
+		fmt.Fprintf(out, "func (*%s) streamEvent() {}\n\n", e.typeName)
+	}
+
+	out.WriteString("// DecodeStreamEvent decodes a streaming event into the type of its kind\n")
+	out.WriteString("func DecodeStreamEvent(data []byte) (StreamEvent, error) {\n")
+	out.WriteString("\tvar header struct {\n\t\tKind string `json:\"kind\"`\n\t}\n")
+	out.WriteString("\tif err := json.Unmarshal(data, &header); err != nil {\n\t\treturn nil, err\n\t}\n")
+	out.WriteString("\tvar event StreamEvent\n\tswitch header.Kind {\n")
+	for _, e := range events {
+		fmt.Fprintf(out, "\tcase %q:\n\t\tevent = new(%s)\n", e.kind, e.typeName)
+	}
+	out.WriteString("\tdefault:\n\t\treturn nil, fmt.Errorf(\"unknown stream event kind %q\", header.Kind)\n\t}\n")
+	out.WriteString("\tif err := json.Unmarshal(data, event); err != nil {\n\t\treturn nil, err\n\t}\n")
+	out.WriteString("\treturn event, nil\n}\n\n")
+}
+
+// agentCardHelpers are the well-known path of agent cards, and the functions serving and
+// fetching them. %[1]s is the name of the AgentCard type.
+const agentCardHelpers = `// AgentCardPath is the well-known path agents serve their agent card at
+const AgentCardPath = "/.well-known/agent-card.json"
+
+// AgentCardHandler returns a handler serving card as JSON, to be registered at AgentCardPath
+func AgentCardHandler(card *%[1]s) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(card)
+	})
+}
+
+// FetchAgentCard gets the agent card of the agent at baseURL from its well-known path, with
+// client (default: http.DefaultClient)
+func FetchAgentCard(ctx context.Context, client *http.Client, baseURL string) (*%[1]s, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+AgentCardPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %%s", resp.Status)
+	}
+	var card %[1]s
+	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+		return nil, fmt.Errorf("failed to decode the agent card: %%w", err)
+	}
+	return &card, nil
+}
+
+`
+
+// generateAgentCard writes the agent card helpers and, when an agent card file was given,
+// EmbeddedAgentCard holding it
+func (p *protocol) generateAgentCard(out *bytes.Buffer, types []byte, card []byte) {
+	typeName := p.typeName("AgentCard", types)
+	if typeName == "" {
+		return
+	}
+	fmt.Fprintf(out, agentCardHelpers, typeName)
+	if card == nil {
+		return
+	}
+
+	literal := "`" + string(card) + "`"
+	if bytes.ContainsRune(card, '`') {
+		literal = strconv.Quote(string(card))
+	}
+	out.WriteString("// EmbeddedAgentCard is the agent card the code was generated with\n")
+	fmt.Fprintf(out, "var EmbeddedAgentCard = decodeEmbeddedAgentCard(%s)\n\n", literal)
+	out.WriteString("// decodeEmbeddedAgentCard decodes the embedded agent card, checked by the generator\n")
+	fmt.Fprintf(out, "func decodeEmbeddedAgentCard(data string) %s {\n", typeName)
+	fmt.Fprintf(out, "\tvar card %s\n", typeName)
+	out.WriteString("\tif err := json.Unmarshal([]byte(data), &card); err != nil {\n")
+	out.WriteString("\t\tpanic(fmt.Sprintf(\"invalid embedded agent card: %v\", err))\n\t}\n\treturn card\n}\n\n")
+}
+
+// loadAgentCard reads an agent card JSON file and checks that it is an object with the
+// required properties of the AgentCard schema, returning it indented
+func loadAgentCard(path string, p *protocol) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent card: %w", err)
+	}
+	var card map[string]any
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("invalid agent card %s: %w", path, err)
+	}
+
+	defMap, _ := p.definitions["AgentCard"].(map[string]any)
+	required, _ := defMap["required"].([]any)
+	var missing []string
+	for _, name := range required {
+		if name, ok := name.(string); ok && card[name] == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("invalid agent card %s: missing required properties %s", path, strings.Join(missing, ", "))
+	}
+
+	return json.MarshalIndent(card, "", "  ")
+}
+
+// joinAlternatives joins values as an English list of alternatives ("a, b or c")
+func joinAlternatives(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
@@ -0,0 +1,185 @@
+// Package a2a provides a Go code generator for the JSON Schema of the Agent2Agent (A2A)
+// protocol
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"golang.org/x/tools/imports"
+)
+
+// A2AGenerator implements the Generator interface for the A2A protocol schema
+type A2AGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *A2AGenerator) Name() string {
+	return "a2a"
+}
+
+// Description returns a human-readable description
+func (g *A2AGenerator) Description() string {
+	return "Generates Go types and protocol helpers from the A2A (Agent2Agent) JSON Schema"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *A2AGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// Options for the A2A generator
+type Options struct {
+	// GeneratorOptions are the options of the types, generated by the JSON-RPC generator
+	*jrpc.GeneratorOptions
+
+	// AgentCard determines whether to generate AgentCardPath, AgentCardHandler serving an
+	// agent card and FetchAgentCard getting the card of a remote agent
+	AgentCard bool
+
+	// AgentCardFile is an agent card JSON file checked against the AgentCard schema and
+	// embedded as EmbeddedAgentCard. Setting it implies AgentCard.
+	AgentCardFile string
+
+	// TaskStateHelpers determines whether to generate the IsTerminal and IsInterrupted methods
+	// of TaskState, telling the states that end a task from those waiting for the client
+	TaskStateHelpers bool
+}
+
+// Generate processes the A2A schema and generates Go code
+func (g *A2AGenerator) Generate(config codegen.GenerateConfig) error {
+	var options *Options
+
+	if config.Options != nil {
+		if opts, ok := config.Options.(*Options); ok {
+			options = opts
+		}
+	}
+
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{
+			IncludeComments: true,
+			FormatOutput:    true,
+		}
+	}
+	if options.AgentCardFile != "" {
+		options.AgentCard = true
+	}
+
+	typesOptions := *options.GeneratorOptions
+	typesOptions.PackageName = config.PackageName
+	if typesOptions.PackageName == "" {
+		typesOptions.PackageName = "a2a"
+	}
+	typesOptions.CustomAcronyms = map[string]bool{"a2a": true}
+	maps.Copy(typesOptions.CustomAcronyms, options.CustomAcronyms)
+
+	document, err := jrpc.Bundle(config.SchemaPath, &typesOptions)
+	if err != nil {
+		return err
+	}
+	protocol := newProtocol(document, &typesOptions)
+
+	var card []byte
+	if options.AgentCardFile != "" {
+		if card, err = loadAgentCard(options.AgentCardFile, protocol); err != nil {
+			return err
+		}
+	}
+
+	// Split mode writes the types into a directory, which the protocol helpers join as a file
+	// of their own
+	if typesOptions.SplitMode != "" {
+		if err := jrpc.GenerateTypes(config.OutputPath, config.SchemaPath, &typesOptions); err != nil {
+			return err
+		}
+		files, err := filepath.Glob(filepath.Join(config.OutputPath, "*.go"))
+		if err != nil {
+			return err
+		}
+		var types []byte
+		for _, file := range files {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read generated file: %w", err)
+			}
+			types = append(types, source...)
+		}
+
+		out := new(bytes.Buffer)
+		fmt.Fprintf(out, "// Code generated from JSON schema. DO NOT EDIT.\n\npackage %s\n\n", typesOptions.PackageName)
+		if !protocol.generate(out, types, options, card) {
+			return nil
+		}
+		return writeSource(filepath.Join(config.OutputPath, "a2a.go"), out.Bytes())
+	}
+
+	schema, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schema, &typesOptions); err != nil {
+		return err
+	}
+	types := bytes.Clone(out.Bytes())
+	if !protocol.generate(out, types, options, card) {
+		if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+	return writeSource(config.OutputPath, out.Bytes())
+}
+
+// writeSource adds the imports of the protocol helpers to source, formats it and writes it
+// to path
+func writeSource(path string, source []byte) error {
+	source, err := imports.Process(path, source, nil)
+	if err != nil {
+		return &codegen.FormatError{Name: "generated code", Err: err}
+	}
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema defines the AgentCard and Task types of the protocol
+func (g *A2AGenerator) ValidateSchema(schemaPath string) error {
+	if err := jrpc.ValidateSchema(schemaPath); err != nil {
+		return err
+	}
+	document, err := jrpc.Bundle(schemaPath, nil)
+	if err != nil {
+		return err
+	}
+	definitions := schemaDefinitions(document)
+	for _, name := range []string{"AgentCard", "Task"} {
+		if _, ok := definitions[name]; !ok {
+			return fmt.Errorf("not an A2A schema: the %s definition is missing", name)
+		}
+	}
+	return nil
+}
+
+// NewA2AGenerator creates a new instance of the A2A generator
+func NewA2AGenerator() *A2AGenerator {
+	return &A2AGenerator{}
+}
+
+// Register automatically registers the A2A generator with the default registry
+func init() {
+	generator := NewA2AGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register A2A generator: %v", err))
+	}
+}
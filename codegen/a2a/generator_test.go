@@ -0,0 +1,84 @@
+package a2a
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+	}{
+		{"types", nil},
+		{"task_state_helpers", &Options{TaskStateHelpers: true}},
+		{"agent_card", &Options{AgentCard: true}},
+		{"agent_card_file", &Options{AgentCardFile: filepath.Join("testdata", "card.json")}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "a2a.go")
+			err := NewA2AGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", "a2a.json"),
+				OutputPath:  output,
+				PackageName: "generated",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".go.golden"), source)
+			gentest.Vet(t, map[string]string{"a2a.go": string(source)})
+		})
+	}
+}
+
+func TestGenerateSplit(t *testing.T) {
+	output := t.TempDir()
+	err := NewA2AGenerator().Generate(codegen.GenerateConfig{
+		SchemaPath:  filepath.Join("testdata", "a2a.json"),
+		OutputPath:  output,
+		PackageName: "generated",
+		Options: &Options{
+			GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, SplitMode: "kind"},
+			TaskStateHelpers: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := gentest.Files(t, output)
+	for name, source := range files {
+		gentest.Golden(t, filepath.Join("testdata", "split", name+".golden"), []byte(source))
+	}
+	gentest.Vet(t, files)
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"a2a.json", true},
+		{"card.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewA2AGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package asyncapi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// typeName returns the Go name of a definition, or an empty string when types does not
+// declare it, e.g. because it was filtered out
+func (d *document) typeName(definition string, types []byte) string {
+	defMap, ok := d.definitions[definition].(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := defMap["x-go-name"].(string)
+	if name == "" {
+		name = jrpc.GoIdentifier(definition, d.options)
+	}
+	if !regexp.MustCompile(`(?m)^type ` + regexp.QuoteMeta(name) + `\b`).Match(types) {
+		return ""
+	}
+	return name
+}
+
+// generate writes the addresses, parameters and publisher and subscriber interfaces of the
+// channels after types, the generated types, and reports whether it wrote them. Nothing is
+// written when no channel carries messages.
+func (d *document) generate(out *bytes.Buffer, types []byte) bool {
+	var channels []*channel
+	for _, c := range d.channels {
+		if len(c.messages) > 0 {
+			channels = append(channels, c)
+		}
+	}
+	if len(channels) == 0 {
+		return false
+	}
+
+	out.WriteString("// Addresses of the channels\nconst (\n")
+	for _, c := range channels {
+		fmt.Fprintf(out, "\t// %sChannel is the address of the %s channel\n", c.name, c.address)
+		fmt.Fprintf(out, "\t%sChannel = %q\n", c.name, c.address)
+	}
+	out.WriteString(")\n\n")
+
+	for _, c := range channels {
+		if len(c.parameters) > 0 {
+			d.generateParameters(out, c)
+		}
+		d.generateInterface(out, c, types, "Publisher", "Publish", "publishes")
+		d.generateInterface(out, c, types, "Subscriber", "Handle", "handles")
+	}
+	return true
+}
+
+// generateParameters writes the parameters of the address of a channel, and their Address
+// method
+func (d *document) generateParameters(out *bytes.Buffer, c *channel) {
+	fmt.Fprintf(out, "// %sParameters are the parameters of the %s channel\n", c.name, c.address)
+	fmt.Fprintf(out, "type %sParameters struct {\n", c.name)
+	for _, parameter := range c.parameters {
+		field := jrpc.GoIdentifier(parameter, d.options)
+		if description := firstLine(c.parameterDescriptions[parameter]); description != "" {
+			fmt.Fprintf(out, "\t// %s is the %s parameter: %s\n", field, parameter, description)
+		} else {
+			fmt.Fprintf(out, "\t// %s is the %s parameter\n", field, parameter)
+		}
+		fmt.Fprintf(out, "\t%s string\n", field)
+	}
+	out.WriteString("}\n\n")
+
+	var parts []string
+	rest := c.address
+	for _, parameter := range c.parameters {
+		before, after, _ := strings.Cut(rest, "{"+parameter+"}")
+		if before != "" {
+			parts = append(parts, fmt.Sprintf("%q", before))
+		}
+		parts = append(parts, "p."+jrpc.GoIdentifier(parameter, d.options))
+		rest = after
+	}
+	if rest != "" {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	fmt.Fprintf(out, "// Address returns the address of the %s channel with the parameters\n", c.address)
+	fmt.Fprintf(out, "func (p %sParameters) Address() string {\n\treturn %s\n}\n\n", c.name, strings.Join(parts, " + "))
+}
+
+// generateInterface writes the publisher or subscriber interface of a channel, with a method
+// per message taking its parameters, payload and headers
+func (d *document) generateInterface(out *bytes.Buffer, c *channel, types []byte, suffix, verb, does string) {
+	fmt.Fprintf(out, "// %s%s %s the messages of the %s channel", c.name, suffix, does, c.address)
+	if description := firstLine(c.description); description != "" {
+		fmt.Fprintf(out, ": %s", strings.TrimSuffix(description, "."))
+	}
+	fmt.Fprintf(out, "\ntype %s%s interface {\n", c.name, suffix)
+	for _, m := range c.messages {
+		args := []string{"ctx context.Context"}
+		if len(c.parameters) > 0 {
+			args = append(args, "params "+c.name+"Parameters")
+		}
+		if m.payload != "" {
+			args = append(args, "payload "+d.argumentType(m.payload, types))
+		}
+		if m.headers != "" {
+			args = append(args, "headers "+d.argumentType(m.headers, types))
+		}
+		fmt.Fprintf(out, "\t// %s%s %s a %s message", verb, m.name, does, m.name)
+		if summary := firstLine(m.summary); summary != "" {
+			fmt.Fprintf(out, ": %s", strings.TrimSuffix(summary, "."))
+		}
+		fmt.Fprintf(out, "\n\t%s%s(%s) error\n", verb, m.name, strings.Join(args, ", "))
+	}
+	out.WriteString("}\n\n")
+}
+
+// argumentType returns the type of the payload or headers argument of a method: a pointer to
+// the type of their definition, or json.RawMessage when it was filtered out
+func (d *document) argumentType(definition string, types []byte) string {
+	if name := d.typeName(definition, types); name != "" {
+		return "*" + name
+	}
+	return "json.RawMessage"
+}
+
+// firstLine returns the first line of a description
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
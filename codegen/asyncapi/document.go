@@ -0,0 +1,253 @@
+package asyncapi
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// parameterPattern matches the parameters of a channel address, e.g. {userId}
+var parameterPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// document holds the channels and messages of an AsyncAPI document, and the definitions the
+// types are generated from: the schemas of the components and the payloads and headers of
+// the messages declared inline
+type document struct {
+	raw         map[string]any
+	version     int // major version, 2 or 3
+	options     *jrpc.GeneratorOptions
+	definitions map[string]any
+	channels    []*channel
+	messages    map[string]*message // messages by Go name
+}
+
+// channel is a channel of the document and the messages it carries
+type channel struct {
+	name                  string   // Go name, e.g. UserSignedup
+	address               string   // address, e.g. user/{userId}/signedup
+	description           string   // description of the channel
+	parameters            []string // parameters of the address, in their order
+	parameterDescriptions map[string]string
+	messages              []*message
+}
+
+// message is a message of the document
+type message struct {
+	ref     string // $ref the message was declared by, if any
+	name    string // Go name, e.g. UserSignedUp
+	summary string
+	payload string // definition of the payload, if any
+	headers string // definition of the headers, if any
+}
+
+// parseDocument reads the channels and messages of a bundled AsyncAPI 2.x or 3.x document
+func parseDocument(raw map[string]any, options *jrpc.GeneratorOptions) (*document, error) {
+	version, _ := raw["asyncapi"].(string)
+	d := &document{raw: raw, options: options, definitions: map[string]any{}, messages: map[string]*message{}}
+	switch {
+	case strings.HasPrefix(version, "2."):
+		d.version = 2
+	case strings.HasPrefix(version, "3."):
+		d.version = 3
+	case version == "":
+		return nil, fmt.Errorf("not an AsyncAPI document: the asyncapi version is missing")
+	default:
+		return nil, fmt.Errorf("unsupported AsyncAPI version %q: must be 2.x or 3.x", version)
+	}
+
+	components, _ := raw["components"].(map[string]any)
+	if schemas, ok := components["schemas"].(map[string]any); ok {
+		maps.Copy(d.definitions, schemas)
+	}
+
+	channels, _ := raw["channels"].(map[string]any)
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("the AsyncAPI document has no channels")
+	}
+	keys := make([]string, 0, len(channels))
+	for key := range channels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c, err := d.channel(key, d.resolve(channels[key]))
+		if err != nil {
+			return nil, err
+		}
+		d.channels = append(d.channels, c)
+	}
+	return d, nil
+}
+
+// channel reads a channel: in 2.x, the messages of its publish and subscribe operations, keyed
+// by their address; in 3.x, its messages, keyed by an id and with their address
+func (d *document) channel(key string, channelMap map[string]any) (*channel, error) {
+	c := &channel{address: key}
+	c.description, _ = channelMap["description"].(string)
+	if d.version == 3 {
+		c.name = jrpc.GoIdentifier(key, d.options)
+		if address, ok := channelMap["address"].(string); ok {
+			c.address = address
+		}
+	} else {
+		c.name = jrpc.GoIdentifier(strings.NewReplacer("/", "_", "{", "_", "}", "_").Replace(key), d.options)
+	}
+	for _, match := range parameterPattern.FindAllStringSubmatch(c.address, -1) {
+		c.parameters = append(c.parameters, match[1])
+	}
+	c.parameterDescriptions = map[string]string{}
+	parameters, _ := channelMap["parameters"].(map[string]any)
+	for name, parameter := range parameters {
+		c.parameterDescriptions[name], _ = d.resolve(parameter)["description"].(string)
+	}
+
+	type entry struct {
+		key  string
+		node any
+	}
+	var entries []entry
+	if d.version == 3 {
+		messages, _ := channelMap["messages"].(map[string]any)
+		keys := make([]string, 0, len(messages))
+		for key := range messages {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			entries = append(entries, entry{key, messages[key]})
+		}
+	} else {
+		for _, operation := range []string{"subscribe", "publish"} {
+			operationMap := d.resolve(channelMap[operation])
+			messageMap := d.resolve(operationMap["message"])
+			if oneOf, ok := messageMap["oneOf"].([]any); ok {
+				for _, node := range oneOf {
+					entries = append(entries, entry{"", node})
+				}
+			} else if messageMap != nil {
+				entries = append(entries, entry{"", operationMap["message"]})
+			}
+		}
+	}
+
+	for _, e := range entries {
+		m, err := d.message(e.node, e.key, c)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(c.messages, m) {
+			c.messages = append(c.messages, m)
+		}
+	}
+	return c, nil
+}
+
+// message reads a message, once per $ref, adding the definitions of its payload and headers.
+// Messages are named after their component, messageId, key in the channel (3.x) or name.
+func (d *document) message(node any, key string, c *channel) (*message, error) {
+	nodeMap, _ := node.(map[string]any)
+	ref, _ := nodeMap["$ref"].(string)
+	if ref != "" {
+		for _, m := range d.messages {
+			if m.ref == ref {
+				return m, nil
+			}
+		}
+	}
+	messageMap := d.resolve(node)
+	if messageMap == nil {
+		return nil, fmt.Errorf("channel %s: unresolvable message %s", c.address, ref)
+	}
+
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	for _, candidate := range []any{messageMap["messageId"], key, messageMap["name"], c.name + "Message"} {
+		if name != "" {
+			break
+		}
+		name, _ = candidate.(string)
+	}
+	goName := jrpc.GoIdentifier(name, d.options)
+	unique := goName
+	for i := 2; d.messages[unique] != nil; i++ {
+		unique = goName + strconv.Itoa(i)
+	}
+
+	m := &message{ref: ref, name: unique}
+	m.summary, _ = messageMap["summary"].(string)
+	format, _ := messageMap["schemaFormat"].(string)
+	var err error
+	if m.payload, err = d.schemaDefinition(messageMap["payload"], format, m.name, "Payload"); err != nil {
+		return nil, fmt.Errorf("message %s: %w", name, err)
+	}
+	if m.headers, err = d.schemaDefinition(messageMap["headers"], "", m.name+"Headers", ""); err != nil {
+		return nil, fmt.Errorf("message %s: %w", name, err)
+	}
+	d.messages[m.name] = m
+	return m, nil
+}
+
+// schemaDefinition returns the definition of the payload or headers of a message: the schema
+// component it refers to, or a definition added for the inline schema under name, or name
+// with suffix when a definition already has it
+func (d *document) schemaDefinition(node any, format, name, suffix string) (string, error) {
+	schema, _ := node.(map[string]any)
+	if schema == nil {
+		return "", nil
+	}
+	// 3.x Multi Format Schema Objects carry the format of their schema
+	if inner, ok := schema["schema"].(map[string]any); ok && schema["schemaFormat"] != nil {
+		format, _ = schema["schemaFormat"].(string)
+		schema = inner
+	}
+	if format != "" && !strings.Contains(format, "application/schema+") && !strings.Contains(format, "application/vnd.aai.asyncapi") {
+		return "", fmt.Errorf("unsupported schemaFormat %q: must be JSON Schema or AsyncAPI schema", format)
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok {
+			return definition, nil
+		}
+		if schema = d.resolve(schema); schema == nil {
+			return "", fmt.Errorf("unresolvable schema %s", ref)
+		}
+	}
+
+	definition := name
+	if _, taken := d.definitions[definition]; taken {
+		definition = name + suffix
+	}
+	unique := definition
+	for i := 2; d.definitions[unique] != nil; i++ {
+		unique = definition + strconv.Itoa(i)
+	}
+	d.definitions[unique] = schema
+	return unique, nil
+}
+
+// resolve returns the object a node is, following its local $refs
+func (d *document) resolve(node any) map[string]any {
+	for range 16 {
+		nodeMap, _ := node.(map[string]any)
+		ref, ok := nodeMap["$ref"].(string)
+		if !ok {
+			return nodeMap
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return nil
+		}
+		node = d.raw
+		for _, token := range strings.Split(pointer, "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			parent, _ := node.(map[string]any)
+			node = parent[token]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Package asyncapi provides a Go code generator for AsyncAPI 2.x and 3.x documents: the types
+// of the messages, and typed publisher and subscriber interfaces per channel
+package asyncapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"golang.org/x/tools/imports"
+)
+
+// AsyncAPIGenerator implements the Generator interface for AsyncAPI documents
+type AsyncAPIGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *AsyncAPIGenerator) Name() string {
+	return "asyncapi"
+}
+
+// Description returns a human-readable description
+func (g *AsyncAPIGenerator) Description() string {
+	return "Generates Go types for the messages and publisher/subscriber interfaces per channel from AsyncAPI 2.x/3.x documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *AsyncAPIGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// Options for the AsyncAPI generator
+type Options struct {
+	// GeneratorOptions are the options of the types, generated by the JSON-RPC generator
+	*jrpc.GeneratorOptions
+}
+
+// Generate processes the AsyncAPI document and generates the types of its schemas and
+// messages followed by the interfaces of its channels
+func (g *AsyncAPIGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{
+			IncludeComments: true,
+			FormatOutput:    true,
+		}
+	}
+
+	typesOptions := *options.GeneratorOptions
+	typesOptions.PackageName = config.PackageName
+	if typesOptions.PackageName == "" {
+		typesOptions.PackageName = "types"
+	}
+
+	raw, err := jrpc.Bundle(config.SchemaPath, &typesOptions)
+	if err != nil {
+		return err
+	}
+	d, err := parseDocument(raw, &typesOptions)
+	if err != nil {
+		return err
+	}
+	schema, err := json.Marshal(map[string]any{"definitions": d.definitions})
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	// Split mode writes the types into a directory, which the channel interfaces join as a
+	// file of their own
+	if typesOptions.SplitMode != "" {
+		file, err := os.CreateTemp("", "asyncapi-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to create schema file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		if _, err := file.Write(schema); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write schema file: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write schema file: %w", err)
+		}
+		if err := jrpc.GenerateTypes(config.OutputPath, file.Name(), &typesOptions); err != nil {
+			return err
+		}
+		files, err := filepath.Glob(filepath.Join(config.OutputPath, "*.go"))
+		if err != nil {
+			return err
+		}
+		var types []byte
+		for _, file := range files {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read generated file: %w", err)
+			}
+			types = append(types, source...)
+		}
+
+		out := new(bytes.Buffer)
+		fmt.Fprintf(out, "// Code generated from AsyncAPI document. DO NOT EDIT.\n\npackage %s\n\n", typesOptions.PackageName)
+		if !d.generate(out, types) {
+			return nil
+		}
+		return writeSource(filepath.Join(config.OutputPath, "channels.go"), out.Bytes())
+	}
+
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schema, &typesOptions); err != nil {
+		return err
+	}
+	types := bytes.Clone(out.Bytes())
+	if !d.generate(out, types) {
+		if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+	return writeSource(config.OutputPath, out.Bytes())
+}
+
+// writeSource adds the imports of the channel interfaces to source, formats it and writes it
+// to path
+func writeSource(path string, source []byte) error {
+	source, err := imports.Process(path, source, nil)
+	if err != nil {
+		return &codegen.FormatError{Name: "generated code", Err: err}
+	}
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the document is an AsyncAPI 2.x or 3.x document with channels
+func (g *AsyncAPIGenerator) ValidateSchema(schemaPath string) error {
+	raw, err := jrpc.Bundle(schemaPath, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := parseDocument(raw, &jrpc.GeneratorOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewAsyncAPIGenerator creates a new instance of the AsyncAPI generator
+func NewAsyncAPIGenerator() *AsyncAPIGenerator {
+	return &AsyncAPIGenerator{}
+}
+
+// Register automatically registers the AsyncAPI generator with the default registry
+func init() {
+	generator := NewAsyncAPIGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register AsyncAPI generator: %v", err))
+	}
+}
@@ -0,0 +1,82 @@
+package asyncapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		options *Options
+	}{
+		{"v2", "v2.yaml", nil},
+		{"v3", "v3.yaml", nil},
+		{"v3_validate", "v3.yaml", &Options{GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, GenerateValidate: true}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "events.go")
+			err := NewAsyncAPIGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", test.schema),
+				OutputPath:  output,
+				PackageName: "generated",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".go.golden"), source)
+			gentest.Vet(t, map[string]string{"events.go": string(source)})
+		})
+	}
+}
+
+func TestGenerateSplit(t *testing.T) {
+	output := t.TempDir()
+	err := NewAsyncAPIGenerator().Generate(codegen.GenerateConfig{
+		SchemaPath:  filepath.Join("testdata", "v3.yaml"),
+		OutputPath:  output,
+		PackageName: "generated",
+		Options:     &Options{GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, SplitMode: "kind"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := gentest.Files(t, output)
+	for name, source := range files {
+		gentest.Golden(t, filepath.Join("testdata", "split", name+".golden"), []byte(source))
+	}
+	gentest.Vet(t, files)
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"v2.yaml", true},
+		{"v3.yaml", true},
+		{"jsonschema.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewAsyncAPIGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,530 @@
+package avro
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// invalidNameChars matches the characters Avro names may not contain
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// primitiveKinds are the Avro primitive types and the JSON values of their defaults
+var primitiveKinds = map[string]func(any) bool{
+	"string":  func(v any) bool { _, ok := v.(string); return ok },
+	"boolean": func(v any) bool { _, ok := v.(bool); return ok },
+	"int":     isWholeNumber,
+	"long":    isWholeNumber,
+	"float":   func(v any) bool { _, ok := v.(float64); return ok },
+	"double":  func(v any) bool { _, ok := v.(float64); return ok },
+}
+
+// record is an Avro record, its keys in the order of the specification
+type record struct {
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Doc       string  `json:"doc,omitempty"`
+	Fields    []field `json:"fields"`
+}
+
+// field is a field of an Avro record. Default is raw so that null defaults are written.
+type field struct {
+	Name    string          `json:"name"`
+	Doc     string          `json:"doc,omitempty"`
+	Type    any             `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// enum is an Avro enum
+type enum struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Doc       string   `json:"doc,omitempty"`
+	Symbols   []string `json:"symbols"`
+}
+
+// array is an Avro array
+type array struct {
+	Type  string `json:"type"`
+	Items any    `json:"items"`
+}
+
+// mapping is an Avro map
+type mapping struct {
+	Type   string `json:"type"`
+	Values any    `json:"values"`
+}
+
+// logical is a primitive Avro type annotated with a logical type
+type logical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+// writer converts JSON Schema definitions into Avro named types, declaring each of them once:
+// in full where it is first used, and by name afterwards
+type writer struct {
+	definitions map[string]any
+	options     *Options
+	names       map[string]string // Avro name of each record and enum definition
+	taken       map[string]bool   // Avro names declared
+	declared    map[string]bool   // definitions declared
+	aliasing    map[string]bool   // alias definitions being inlined, against cycles
+}
+
+// newWriter names the definitions declared as Avro records and enums after their Go types
+func newWriter(definitions map[string]any, options *Options) *writer {
+	w := &writer{
+		definitions: definitions,
+		options:     options,
+		names:       map[string]string{},
+		taken:       map[string]bool{},
+		declared:    map[string]bool{},
+		aliasing:    map[string]bool{},
+	}
+	for _, name := range sortedKeys(definitions) {
+		schema, _ := definitions[name].(map[string]any)
+		if isRecord(schema) || isStringEnum(schema) {
+			w.names[name] = w.uniqueName(avroName(jrpc.GoIdentifier(name, options.GeneratorOptions)))
+		}
+	}
+	return w
+}
+
+// schemas returns the top-level Avro schemas: the records and enums of the definitions in
+// alphabetical order, leaving out those declared by a previous one
+func (w *writer) schemas() []any {
+	var schemas []any
+	for _, name := range sortedKeys(w.definitions) {
+		if _, ok := w.names[name]; !ok || w.declared[name] {
+			continue
+		}
+		schema := w.definition(name)
+		switch schema := schema.(type) {
+		case *record:
+			schema.Namespace = w.options.Namespace
+		case *enum:
+			schema.Namespace = w.options.Namespace
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+// definition returns the Avro schema of a definition: its declaration, the first time a record
+// or enum is used, its name afterwards, and the schema of aliases
+func (w *writer) definition(name string) any {
+	schema, _ := w.definitions[name].(map[string]any)
+	avroName, named := w.names[name]
+	if !named {
+		if w.aliasing[name] {
+			return "string"
+		}
+		w.aliasing[name] = true
+		defer delete(w.aliasing, name)
+		return w.typeOf(schema, jrpc.GoIdentifier(name, w.options.GeneratorOptions))
+	}
+	if w.declared[name] {
+		return avroName
+	}
+	w.declared[name] = true
+	if isStringEnum(schema) {
+		return w.enum(avroName, schema)
+	}
+	return w.record(avroName, schema)
+}
+
+// record declares a record with a field per property of the schema and of its allOf members
+func (w *writer) record(name string, schema map[string]any) *record {
+	r := &record{Type: "record", Name: name, Doc: w.doc(schema), Fields: []field{}}
+	properties, required := w.properties(schema)
+	taken := map[string]bool{}
+	for _, p := range properties {
+		fieldName := avroName(p.name)
+		for i := 2; taken[fieldName]; i++ {
+			fieldName = avroName(p.name) + strconv.Itoa(i)
+		}
+		taken[fieldName] = true
+
+		f := field{Name: fieldName, Doc: w.doc(p.schema)}
+		t := w.typeOf(p.schema, name+jrpc.GoIdentifier(p.name, w.options.GeneratorOptions))
+		value, hasDefault := p.schema["default"]
+		hasDefault = hasDefault && value != nil && validDefault(t, value)
+		switch {
+		case required[p.name] && !isNullable(p.schema):
+			f.Type = t
+		case hasDefault:
+			// The default of a union is a value of its first member
+			f.Type = union(t, "null")
+		default:
+			f.Type = union("null", t)
+			f.Default = json.RawMessage("null")
+		}
+		if hasDefault {
+			f.Default, _ = json.Marshal(value)
+		}
+		r.Fields = append(r.Fields, f)
+	}
+	return r
+}
+
+// property is a property of a record
+type property struct {
+	name   string
+	schema map[string]any
+}
+
+// properties returns the properties of a schema, after those of its allOf members, and the
+// names of the required ones
+func (w *writer) properties(schema map[string]any) ([]property, map[string]bool) {
+	var properties []property
+	required := map[string]bool{}
+	seen := map[string]bool{}
+	var collect func(schema map[string]any, depth int)
+	collect = func(schema map[string]any, depth int) {
+		if schema == nil || depth > 16 {
+			return
+		}
+		allOf, _ := schema["allOf"].([]any)
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				target, _ := w.definitions[refName(ref)].(map[string]any)
+				collect(target, depth+1)
+			} else {
+				collect(member, depth+1)
+			}
+		}
+		for _, name := range stringList(schema["required"]) {
+			required[name] = true
+		}
+		all, _ := schema["properties"].(map[string]any)
+		for _, name := range jrpc.PropertyNames(schema) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			propertySchema, _ := all[name].(map[string]any)
+			properties = append(properties, property{name, propertySchema})
+		}
+	}
+	collect(schema, 0)
+	return properties, required
+}
+
+// enum declares an enum of the string values of the schema
+func (w *writer) enum(name string, schema map[string]any) *enum {
+	e := &enum{Type: "enum", Name: name, Doc: w.doc(schema)}
+	for _, value := range stringList(schema["enum"]) {
+		symbol := avroName(value)
+		if !slices.Contains(e.Symbols, symbol) {
+			e.Symbols = append(e.Symbols, symbol)
+		}
+	}
+	return e
+}
+
+// typeOf returns the Avro type of a schema, without its null. Records and enums declared inline
+// are named name.
+func (w *writer) typeOf(schema map[string]any, name string) any {
+	if schema == nil {
+		return "string"
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		if _, ok := w.definitions[refName(ref)]; ok {
+			return w.definition(refName(ref))
+		}
+		return "string"
+	}
+	if isStringEnum(schema) {
+		return w.enum(w.uniqueName(avroName(name)), schema)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok {
+			var types []any
+			for i, member := range members {
+				member, _ := member.(map[string]any)
+				if member["type"] == "null" {
+					continue
+				}
+				types = append(types, w.typeOf(member, name+strconv.Itoa(i+1)))
+			}
+			return union(types...)
+		}
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		if member, _ := allOf[0].(map[string]any); len(allOf) == 1 {
+			return w.typeOf(member, name)
+		}
+		return w.record(w.uniqueName(avroName(name)), schema)
+	}
+
+	var types []any
+	for _, kind := range schemaTypes(schema) {
+		types = append(types, w.typeOfKind(kind, schema, name))
+	}
+	if len(types) == 0 {
+		if _, ok := schema["properties"]; ok {
+			return w.record(w.uniqueName(avroName(name)), schema)
+		}
+		return "string"
+	}
+	return union(types...)
+}
+
+// typeOfKind returns the Avro type of a schema of a JSON Schema type
+func (w *writer) typeOfKind(kind string, schema map[string]any, name string) any {
+	format, _ := schema["format"].(string)
+	switch kind {
+	case "string":
+		switch format {
+		case "date-time":
+			return logical{"long", "timestamp-millis"}
+		case "date":
+			return logical{"int", "date"}
+		case "time":
+			return logical{"int", "time-millis"}
+		case "uuid":
+			return logical{"string", "uuid"}
+		case "byte", "binary":
+			return "bytes"
+		}
+		return "string"
+	case "integer":
+		switch format {
+		case "int8", "int16", "int32", "uint8", "uint16":
+			return "int"
+		}
+		return "long"
+	case "number":
+		if format == "float" {
+			return "float"
+		}
+		return "double"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		if items, ok := tupleItems(schema); ok {
+			var types []any
+			for i, item := range items {
+				item, _ := item.(map[string]any)
+				types = append(types, w.typeOf(item, name+"Item"+strconv.Itoa(i+1)))
+			}
+			return array{"array", union(types...)}
+		}
+		items, _ := schema["items"].(map[string]any)
+		return array{"array", w.typeOf(items, name+"Item")}
+	case "object":
+		if _, ok := schema["properties"]; ok {
+			return w.record(w.uniqueName(avroName(name)), schema)
+		}
+		if values, ok := schema["additionalProperties"].(map[string]any); ok && len(values) > 0 {
+			return mapping{"map", w.typeOf(values, name+"Value")}
+		}
+		return mapping{"map", "string"}
+	}
+	return "string"
+}
+
+// doc returns the description of a schema, unless comments are left out
+func (w *writer) doc(schema map[string]any) string {
+	if !w.options.IncludeComments {
+		return ""
+	}
+	description, _ := schema["description"].(string)
+	return strings.TrimSpace(description)
+}
+
+// uniqueName returns name, or name with a number when another named type has it
+func (w *writer) uniqueName(name string) string {
+	unique := name
+	for i := 2; w.taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	w.taken[unique] = true
+	return unique
+}
+
+// union returns the union of types, flattening the unions among them and leaving out the
+// types already in it, or the single type of unions of one
+func union(types ...any) any {
+	var members []any
+	seen := map[string]bool{}
+	for _, t := range types {
+		nested, ok := t.([]any)
+		if !ok {
+			nested = []any{t}
+		}
+		for _, member := range nested {
+			key := unionKey(member)
+			if !seen[key] {
+				seen[key] = true
+				members = append(members, member)
+			}
+		}
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return members
+}
+
+// unionKey identifies the members of a union that Avro would consider the same: named types
+// by name, and other types by their type
+func unionKey(t any) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case *record:
+		return t.Name
+	case *enum:
+		return t.Name
+	case array:
+		return "array"
+	case mapping:
+		return "map"
+	case logical:
+		return t.Type
+	}
+	return ""
+}
+
+// validDefault reports whether value is a valid default of a field of type t, which defaults
+// are only written for primitive types and enums
+func validDefault(t any, value any) bool {
+	switch t := t.(type) {
+	case string:
+		valid, ok := primitiveKinds[t]
+		return ok && valid(value)
+	case *enum:
+		symbol, ok := value.(string)
+		return ok && slices.Contains(t.Symbols, symbol)
+	}
+	return false
+}
+
+// isWholeNumber reports whether a JSON value is an integer
+func isWholeNumber(value any) bool {
+	number, ok := value.(float64)
+	return ok && number == float64(int64(number))
+}
+
+// avroName returns name with the characters Avro names may not contain replaced by
+// underscores, and an underscore before a leading digit
+func avroName(name string) string {
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// isRecord reports whether a definition is declared as a record: an object with properties,
+// or an allOf with several members
+func isRecord(schema map[string]any) bool {
+	if isNullable(schema) || schema["oneOf"] != nil || schema["anyOf"] != nil {
+		return false
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		return len(allOf) > 1 || schema["properties"] != nil
+	}
+	_, ok := schema["properties"]
+	return ok && len(schemaTypes(schema)) <= 1
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// isStringEnum reports whether a schema is an enum of strings
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tupleItems returns the positional items of a tuple schema: 2020-12 prefixItems or an
+// items array
+func tupleItems(schema map[string]any) ([]any, bool) {
+	if items, ok := schema["prefixItems"].([]any); ok {
+		return items, true
+	}
+	items, ok := schema["items"].([]any)
+	return items, ok
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
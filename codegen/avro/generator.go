@@ -0,0 +1,179 @@
+// Package avro provides a generator converting between Avro schemas and the other schemas:
+// Go structs with avro tags from .avsc files, and .avsc files from JSON Schema, OpenAPI and
+// OpenRPC documents
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// AvroGenerator implements the Generator interface for Avro schemas
+type AvroGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *AvroGenerator) Name() string {
+	return "avro"
+}
+
+// Description returns a human-readable description
+func (g *AvroGenerator) Description() string {
+	return "Generates Go structs with avro tags from Avro schemas, and Avro schemas from JSON Schema, OpenAPI and OpenRPC documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *AvroGenerator) SupportedFormats() []string {
+	return []string{".avsc", ".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes from other schemas. The Go
+// structs of .avsc files are not listed, since no other generator reads them.
+func (g *AvroGenerator) OutputFormats() []string {
+	return []string{".avsc"}
+}
+
+// Options for the Avro generator
+type Options struct {
+	// GeneratorOptions are the options of the Go structs of .avsc files, generated by the
+	// JSON-RPC generator. Avro schemas are written from the definitions they select and name,
+	// as for the Go types, with their descriptions unless IncludeComments is false.
+	*jrpc.GeneratorOptions
+
+	// Namespace is the namespace of the records and enums of the Avro schemas written
+	Namespace string
+}
+
+// Generate writes the Go structs of an .avsc file, or the Avro schema of the definitions of
+// any other schema
+func (g *AvroGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{
+			IncludeComments: true,
+			FormatOutput:    true,
+		}
+	}
+	if isAvro(config.SchemaPath) {
+		return generateTypes(config, options)
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported when writing Avro schemas", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	schemas := newWriter(definitions, options).schemas()
+	if len(schemas) == 0 {
+		return fmt.Errorf("the schema declares no objects or string enums to write as Avro records and enums")
+	}
+	var document any = schemas
+	if len(schemas) == 1 {
+		document = schemas[0]
+	}
+
+	out := new(bytes.Buffer)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode Avro schema: %w", err)
+	}
+	if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// generateTypes writes the Go structs of the named types of an .avsc file, with avro tags
+func generateTypes(config codegen.GenerateConfig, options *Options) error {
+	definitions, err := loadAvro(config.SchemaPath)
+	if err != nil {
+		return err
+	}
+	schema, err := json.Marshal(map[string]any{"definitions": definitions})
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	typesOptions := *options.GeneratorOptions
+	typesOptions.PackageName = config.PackageName
+	if typesOptions.PackageName == "" {
+		typesOptions.PackageName = "types"
+	}
+	if !slices.Contains(typesOptions.Tags, "avro") {
+		typesOptions.Tags = append(slices.Clone(typesOptions.Tags), "avro")
+	}
+	// The notice names the .avsc file rather than the JSON schema converted from it
+	typesOptions.SchemaSource = "Avro schema " + filepath.Base(config.SchemaPath)
+	if typesOptions.SchemaName == "" {
+		typesOptions.SchemaName = config.SchemaPath
+	}
+
+	// Split mode writes a directory, which only GenerateTypes does, from a schema file
+	if typesOptions.SplitMode != "" {
+		file, err := os.CreateTemp("", "avro-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to create schema file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		if _, err := file.Write(schema); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write schema file: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write schema file: %w", err)
+		}
+		return jrpc.GenerateTypes(config.OutputPath, file.Name(), &typesOptions)
+	}
+
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schema, &typesOptions); err != nil {
+		return err
+	}
+	if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that an .avsc file declares named types, and that other schemas
+// declare definitions
+func (g *AvroGenerator) ValidateSchema(schemaPath string) error {
+	if isAvro(schemaPath) {
+		_, err := loadAvro(schemaPath)
+		return err
+	}
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// isAvro reports whether a schema is an Avro schema, by its extension
+func isAvro(schemaPath string) bool {
+	return strings.EqualFold(filepath.Ext(schemaPath), ".avsc")
+}
+
+// NewAvroGenerator creates a new instance of the Avro generator
+func NewAvroGenerator() *AvroGenerator {
+	return &AvroGenerator{}
+}
+
+// Register automatically registers the Avro generator with the default registry
+func init() {
+	generator := NewAvroGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register Avro generator: %v", err))
+	}
+}
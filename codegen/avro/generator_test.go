@@ -0,0 +1,68 @@
+package avro
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		schema  string
+		output  string
+		options *Options
+	}{
+		{"user.avsc", "user.go", nil},
+		{"point.avsc", "point.go", nil},
+		{"point.avsc", "point_provenance.go", &Options{GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, Provenance: true}}},
+		{"pets.json", "pets.avsc", nil},
+		{"pets.json", "pets_namespace.avsc", &Options{Namespace: "com.example.pets"}},
+	}
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), test.output)
+			err := NewAvroGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", test.schema),
+				OutputPath:  output,
+				PackageName: "generated",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.output+".golden"), source)
+			if strings.HasSuffix(test.output, ".go") {
+				gentest.Vet(t, map[string]string{test.output: string(source)})
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"user.avsc", true},
+		{"pets.json", true},
+		{"invalid.avsc", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewAvroGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ingester converts the named types of an Avro schema into JSON Schema definitions
+type ingester struct {
+	definitions map[string]any
+	names       map[string]string // definition of each named type, by full name and name
+}
+
+// loadAvro reads an .avsc file and returns the JSON Schema definitions of its named types
+func loadAvro(schemaPath string) (map[string]any, error) {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	var schema any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	in := &ingester{definitions: map[string]any{}, names: map[string]string{}}
+	if _, err := in.schemaOf(schema, ""); err != nil {
+		return nil, err
+	}
+	if len(in.definitions) == 0 {
+		return nil, fmt.Errorf("the Avro schema declares no records, enums or fixed types")
+	}
+	return in.definitions, nil
+}
+
+// schemaOf returns the JSON Schema of an Avro schema, declared in namespace, adding the
+// definitions of the named types it declares
+func (in *ingester) schemaOf(schema any, namespace string) (map[string]any, error) {
+	switch schema := schema.(type) {
+	case string:
+		return in.typeReference(schema, namespace)
+	case []any:
+		return in.union(schema, namespace)
+	case map[string]any:
+		kind, _ := schema["type"].(string)
+		switch kind {
+		case "record", "error":
+			return in.record(schema, namespace)
+		case "enum":
+			return in.enum(schema, namespace)
+		case "fixed":
+			return in.fixed(schema, namespace)
+		case "array":
+			items, err := in.schemaOf(schema["items"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"type": "array", "items": items}, nil
+		case "map":
+			values, err := in.schemaOf(schema["values"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"type": "object", "additionalProperties": values}, nil
+		}
+		if logical, ok := schema["logicalType"].(string); ok {
+			if s := logicalSchema(logical, kind); s != nil {
+				return s, nil
+			}
+		}
+		// Primitive types may be written as {"type": "string"}, or wrap another schema
+		return in.schemaOf(schema["type"], namespace)
+	}
+	return nil, fmt.Errorf("invalid Avro schema %v", schema)
+}
+
+// typeReference returns the JSON Schema of a primitive type, or a $ref to a named type
+func (in *ingester) typeReference(name, namespace string) (map[string]any, error) {
+	switch name {
+	case "null":
+		return map[string]any{"type": "null"}, nil
+	case "boolean":
+		return map[string]any{"type": "boolean"}, nil
+	case "int":
+		return map[string]any{"type": "integer", "format": "int32"}, nil
+	case "long":
+		return map[string]any{"type": "integer", "format": "int64"}, nil
+	case "float":
+		return map[string]any{"type": "number", "format": "float"}, nil
+	case "double":
+		return map[string]any{"type": "number", "format": "double"}, nil
+	case "bytes":
+		return map[string]any{"type": "string", "format": "byte"}, nil
+	case "string":
+		return map[string]any{"type": "string"}, nil
+	}
+	definition, ok := in.names[fullName(name, namespace)]
+	if !ok {
+		definition, ok = in.names[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown Avro type %q", name)
+	}
+	return map[string]any{"$ref": "#/definitions/" + definition}, nil
+}
+
+// union returns the JSON Schema of a union: the schema of its other member, nullable, for
+// the unions of null and a type, and a oneOf otherwise
+func (in *ingester) union(members []any, namespace string) (map[string]any, error) {
+	var schemas []any
+	nullable := false
+	for _, member := range members {
+		if member == "null" {
+			nullable = true
+			continue
+		}
+		schema, err := in.schemaOf(member, namespace)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	switch {
+	case len(schemas) == 0:
+		return map[string]any{"type": "null"}, nil
+	case len(schemas) == 1 && !nullable:
+		return schemas[0].(map[string]any), nil
+	case len(schemas) == 1:
+		return map[string]any{"oneOf": []any{schemas[0], map[string]any{"type": "null"}}}, nil
+	}
+	if nullable {
+		schemas = append(schemas, map[string]any{"type": "null"})
+	}
+	return map[string]any{"oneOf": schemas}, nil
+}
+
+// record adds the definition of a record: an object whose fields are its properties, required
+// unless their type is a union with null
+func (in *ingester) record(schema map[string]any, namespace string) (map[string]any, error) {
+	ref, namespace, err := in.declare(schema, namespace)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]any{}
+	var required, order []any
+	fields, _ := schema["fields"].([]any)
+	for _, field := range fields {
+		fieldMap, _ := field.(map[string]any)
+		name, _ := fieldMap["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("record %s: a field has no name", schema["name"])
+		}
+		property, err := in.schemaOf(fieldMap["type"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("record %s, field %s: %w", schema["name"], name, err)
+		}
+		if doc, ok := fieldMap["doc"].(string); ok {
+			property["description"] = doc
+		}
+		if value, ok := fieldMap["default"]; ok && value != nil {
+			property["default"] = value
+		}
+		properties[name] = property
+		order = append(order, name)
+		if !isNullUnion(fieldMap["type"]) {
+			required = append(required, name)
+		}
+	}
+
+	definition := in.definitions[refName(ref)].(map[string]any)
+	definition["type"] = "object"
+	definition["properties"] = properties
+	definition["x-go-property-order"] = order
+	if len(required) > 0 {
+		definition["required"] = required
+	}
+	return map[string]any{"$ref": ref}, nil
+}
+
+// enum adds the definition of an enum: a string enum of its symbols
+func (in *ingester) enum(schema map[string]any, namespace string) (map[string]any, error) {
+	ref, _, err := in.declare(schema, namespace)
+	if err != nil {
+		return nil, err
+	}
+	definition := in.definitions[refName(ref)].(map[string]any)
+	definition["type"] = "string"
+	definition["enum"] = schema["symbols"]
+	return map[string]any{"$ref": ref}, nil
+}
+
+// fixed adds the definition of a fixed type: bytes, or a decimal
+func (in *ingester) fixed(schema map[string]any, namespace string) (map[string]any, error) {
+	ref, _, err := in.declare(schema, namespace)
+	if err != nil {
+		return nil, err
+	}
+	definition := in.definitions[refName(ref)].(map[string]any)
+	definition["type"] = "string"
+	definition["format"] = "byte"
+	if schema["logicalType"] == "decimal" {
+		definition["format"] = "decimal"
+	}
+	return map[string]any{"$ref": ref}, nil
+}
+
+// declare adds the definition of a named type, named after its name unless another namespace
+// declares it too, and returns its $ref and namespace
+func (in *ingester) declare(schema map[string]any, namespace string) (string, string, error) {
+	name, _ := schema["name"].(string)
+	if name == "" {
+		return "", "", fmt.Errorf("a %s has no name", schema["type"])
+	}
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+	full := fullName(name, namespace)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		namespace, name = name[:i], name[i+1:]
+	}
+	if _, ok := in.names[full]; ok {
+		return "", "", fmt.Errorf("Avro type %s is declared twice", full)
+	}
+
+	definition := name
+	if _, taken := in.definitions[definition]; taken {
+		definition = strings.ReplaceAll(full, ".", "_")
+	}
+	declared := map[string]any{}
+	if doc, ok := schema["doc"].(string); ok {
+		declared["description"] = doc
+	}
+	in.definitions[definition] = declared
+	in.names[full] = definition
+	if _, ok := in.names[name]; !ok {
+		in.names[name] = definition
+	}
+	return "#/definitions/" + definition, namespace, nil
+}
+
+// logicalSchema returns the JSON Schema of a logical type, or nil for those read as their
+// underlying type
+func logicalSchema(logical, kind string) map[string]any {
+	switch logical {
+	case "timestamp-millis", "timestamp-micros", "local-timestamp-millis", "local-timestamp-micros":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "date":
+		return map[string]any{"type": "string", "format": "date"}
+	case "uuid":
+		return map[string]any{"type": "string", "format": "uuid"}
+	case "decimal":
+		if kind == "bytes" {
+			return map[string]any{"type": "string", "format": "decimal"}
+		}
+	}
+	return nil
+}
+
+// isNullUnion reports whether an Avro type is a union with null
+func isNullUnion(schema any) bool {
+	members, ok := schema.([]any)
+	if !ok {
+		return false
+	}
+	for _, member := range members {
+		if member == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// fullName returns the full name of a named type declared in namespace
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
@@ -0,0 +1,154 @@
+package codegen
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaError is an error about an element of a schema file, located by a JSON pointer and,
+// once the file is known, by a line and column, so that editors and CI can annotate the
+// element. Its message names the pointer; the file and position are left to the reporter,
+// since they are often attached after the error was wrapped (see Locate).
+type SchemaError struct {
+	File    string // Schema file, once known
+	Pointer string // JSON pointer to the element, e.g. /definitions/Pet/properties/id
+	Line    int    // Line of the element in File, or 0 when unknown
+	Column  int    // Column of the element in File, or 0 when unknown
+	Err     error
+}
+
+// Error returns the message of the error, preceded by its pointer
+func (e *SchemaError) Error() string {
+	if e.Pointer == "" {
+		return e.Err.Error()
+	}
+	return e.Pointer + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// FormatError is an error formatting generated code with gofmt or goimports, which is a bug of
+// the generator or of a template rather than of the schema
+type FormatError struct {
+	Name string // File or part of the generated code that failed to format, e.g. types.go or "fuzz tests"
+	Err  error
+}
+
+// Error returns the message of the error, naming what failed to format
+func (e *FormatError) Error() string {
+	return "failed to format " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// Locate attributes the schema error err wraps, if any, to a schema file, and finds its line
+// and column in the file from its pointer. Errors already attributed to a file are left as
+// they are. It returns err.
+func Locate(err error, file string) error {
+	data, _ := os.ReadFile(file)
+	return LocateData(err, file, data)
+}
+
+// LocateData is Locate for a schema file whose contents are data, such as a file of an fs.FS
+// the operating system cannot read
+func LocateData(err error, file string, data []byte) error {
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || schemaErr.File != "" {
+		return err
+	}
+	schemaErr.File = file
+	if schemaErr.Line == 0 && schemaErr.Pointer != "" && data != nil {
+		schemaErr.Line, schemaErr.Column = PointerPosition(data, schemaErr.Pointer)
+	}
+	return err
+}
+
+// yamlErrorLine matches the line yaml.v3 names in its errors
+var yamlErrorLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// DecodeError returns the error decoding a JSON or YAML schema file as a SchemaError at the
+// position the decoder reported, or err itself when it reported none
+func DecodeError(file string, data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	offset := int64(-1)
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+	if offset >= 0 {
+		line, column := offsetPosition(data, int(offset))
+		return &SchemaError{File: file, Line: line, Column: column, Err: err}
+	}
+	if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &SchemaError{File: file, Line: line, Err: err}
+	}
+	return err
+}
+
+// offsetPosition returns the line and column of a byte offset of data, from 1
+func offsetPosition(data []byte, offset int) (line, column int) {
+	offset = min(offset, len(data))
+	before := data[:offset]
+	line = 1 + strings.Count(string(before), "\n")
+	column = offset - strings.LastIndexByte(string(before), '\n')
+	return line, column
+}
+
+// PointerPosition returns the line and column, from 1, of the element of a JSON or YAML
+// document a JSON pointer addresses: the position of its key for a member of an object. It
+// returns the position of the deepest element found when the pointer goes further, and 0, 0
+// when the document cannot be parsed.
+func PointerPosition(data []byte, pointer string) (line, column int) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil || len(document.Content) == 0 {
+		return 0, 0
+	}
+	node := document.Content[0]
+	line, column = node.Line, node.Column
+	if pointer == "" || pointer == "/" {
+		return line, column
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		for node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					line, column = node.Content[i].Line, node.Content[i].Column
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				line, column = next.Line, next.Column
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line, column
+}
@@ -3,6 +3,8 @@ package codegen
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -25,6 +27,25 @@ type Generator interface {
 	ValidateSchema(schemaPath string) error
 }
 
+// OutputFormatter is implemented by generators whose output is not Go source, so that
+// auto-detection only picks them for output files of their formats
+type OutputFormatter interface {
+	// OutputFormats returns the file extensions this generator writes (e.g., [".proto"])
+	OutputFormats() []string
+}
+
+// AcceptsOutput reports whether a generator writes files like outputPath: files with one of
+// its OutputFormats or, for generators of Go source, .go files and directories (paths without
+// extension, as in split mode)
+func AcceptsOutput(generator Generator, outputPath string) bool {
+	ext := strings.ToLower(filepath.Ext(outputPath))
+	formatter, ok := generator.(OutputFormatter)
+	if !ok {
+		return ext == ".go" || ext == ""
+	}
+	return slices.Contains(formatter.OutputFormats(), ext)
+}
+
 // GenerateConfig contains all the configuration needed for code generation
 type GenerateConfig struct {
 	// Input schema file path
@@ -85,7 +106,8 @@ func (r *Registry) List() []string {
 	return names
 }
 
-// GetByFormat finds generators that support the given file format
+// GetByFormat finds generators that support the given file format, in name order so that
+// auto-detection picks the same generator on every run
 func (r *Registry) GetByFormat(filePath string) []Generator {
 	var matches []Generator
 
@@ -98,6 +120,9 @@ func (r *Registry) GetByFormat(filePath string) []Generator {
 		}
 	}
 
+	slices.SortFunc(matches, func(a, b Generator) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
 	return matches
 }
 
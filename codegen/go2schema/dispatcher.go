@@ -0,0 +1,113 @@
+package go2schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"golang.org/x/tools/imports"
+)
+
+// dispatcherRuntime are the declarations of the dispatcher that do not depend on the tools
+const dispatcherRuntime = `// ErrUnknownTool is returned by CallTool for a name that is not a tool of the package
+var ErrUnknownTool = errors.New("unknown tool")
+
+// decodeToolArguments decodes the JSON object of the arguments of a tool call into args,
+// leaving it empty when the model sent no arguments
+func decodeToolArguments(data json.RawMessage, args any) error {
+	if data = bytes.TrimSpace(data); len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, args)
+}
+`
+
+// Dispatcher returns the Go source, in the package at path, of the tool dispatcher of the
+// functions Tools describes: ToolDefinitions, their JSON in options.ToolFormat (default:
+// ToolFormatGeneric), and CallTool, which decodes the arguments of a tool call into the
+// struct of the function of the tool and calls it
+func Dispatcher(path string, options *Options) ([]byte, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	format := options.ToolFormat
+	if format == "" {
+		format = ToolFormatGeneric
+	}
+	pkg, err := loadPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	c := newConverter(pkg)
+	functions, err := c.selectTools(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []map[string]any
+	for _, function := range functions {
+		tool, err := c.tool(function, format)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, tool)
+	}
+	definitions, err := json.MarshalIndent(tools, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool definitions: %w", err)
+	}
+	literal := "`" + string(definitions) + "`"
+	if bytes.ContainsRune(definitions, '`') {
+		literal = strconv.Quote(string(definitions))
+	}
+
+	out := new(bytes.Buffer)
+	fmt.Fprintf(out, "// Code generated by go2schema. DO NOT EDIT.\n\npackage %s\n\n", pkg.name)
+	out.WriteString("// ToolDefinitions are the definitions of the tools CallTool calls, as a JSON array\n")
+	fmt.Fprintf(out, "const ToolDefinitions = %s\n\n", literal)
+	out.WriteString(dispatcherRuntime)
+
+	out.WriteString("\n// CallTool calls the function of the tool name with the arguments of a tool call, a JSON\n")
+	out.WriteString("// object, and returns its result\n")
+	out.WriteString("func CallTool(ctx context.Context, name string, arguments json.RawMessage) (any, error) {\n")
+	out.WriteString("\tswitch name {\n")
+	for _, function := range functions {
+		fmt.Fprintf(out, "\tcase %q:\n", function.name)
+		var args []string
+		if function.context {
+			args = append(args, "ctx")
+		}
+		if function.params != "" {
+			fmt.Fprintf(out, "\t\tvar args %s\n", function.params)
+			out.WriteString("\t\tif err := decodeToolArguments(arguments, &args); err != nil {\n")
+			out.WriteString("\t\t\treturn nil, fmt.Errorf(\"invalid arguments of tool %s: %w\", name, err)\n\t\t}\n")
+			if function.pointer {
+				args = append(args, "&args")
+			} else {
+				args = append(args, "args")
+			}
+		}
+		call := fmt.Sprintf("%s(%s)", function.decl.Name.Name, strings.Join(args, ", "))
+		switch {
+		case function.result && function.err:
+			fmt.Fprintf(out, "\t\treturn %s\n", call)
+		case function.result:
+			fmt.Fprintf(out, "\t\treturn %s, nil\n", call)
+		case function.err:
+			fmt.Fprintf(out, "\t\treturn nil, %s\n", call)
+		default:
+			fmt.Fprintf(out, "\t\t%s\n\t\treturn nil, nil\n", call)
+		}
+	}
+	out.WriteString("\t}\n")
+	out.WriteString("\treturn nil, fmt.Errorf(\"%w: %s\", ErrUnknownTool, name)\n}\n")
+
+	source, err := imports.Process(pkg.name+"_tools.go", out.Bytes(), nil)
+	if err != nil {
+		return nil, &codegen.FormatError{Name: "generated code", Err: err}
+	}
+	return source, nil
+}
@@ -0,0 +1,230 @@
+// Package go2schema provides a reverse generator writing JSON Schema, and the tool definitions
+// of LLM function calling, from the structs and functions of a Go package
+package go2schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// Go2SchemaGenerator implements the Generator interface for Go packages
+type Go2SchemaGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *Go2SchemaGenerator) Name() string {
+	return "go2schema"
+}
+
+// Description returns a human-readable description
+func (g *Go2SchemaGenerator) Description() string {
+	return "Generates JSON Schema or LLM tool definitions from the structs and functions of a Go package"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *Go2SchemaGenerator) SupportedFormats() []string {
+	return []string{".go"}
+}
+
+// OutputFormats returns the file extensions this generator writes: schemas and tool
+// definitions, or the Go source of the tool dispatcher
+func (g *Go2SchemaGenerator) OutputFormats() []string {
+	return []string{".json", ".yaml", ".yml", ".go"}
+}
+
+// Options for the go2schema generator
+type Options struct {
+	// IncludeTypes are glob patterns of the types written as definitions, plus the types they
+	// refer to (default: the exported types). For tools, they select the functions.
+	IncludeTypes []string
+
+	// ToolFormat writes the tool definitions of the functions of the package (see Tools)
+	// instead of a JSON Schema document: ToolFormatGeneric, ToolFormatOpenAI or
+	// ToolFormatAnthropic. It is the format of the definitions embedded by Dispatcher
+	// (default: ToolFormatGeneric).
+	ToolFormat string
+}
+
+// Generate reads the Go package at the schema path, a directory or a file, and writes its
+// JSON Schema or tool definitions as JSON, or as YAML when the output path ends in .yaml or
+// .yml. An output path ending in .go gets the tool dispatcher of the package instead.
+func (g *Go2SchemaGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+
+	if strings.HasSuffix(config.OutputPath, ".go") {
+		source, err := Dispatcher(config.SchemaPath, options)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(config.OutputPath, source, 0o644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+
+	var document any
+	var err error
+	if options.ToolFormat != "" {
+		document, err = Tools(config.SchemaPath, options)
+	} else {
+		document, err = Schema(config.SchemaPath, options)
+	}
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if strings.HasSuffix(config.OutputPath, ".yaml") || strings.HasSuffix(config.OutputPath, ".yml") {
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		err = encoder.Encode(document)
+	} else {
+		encoder := json.NewEncoder(&out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(document)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// Schema returns the JSON Schema document of the types of the Go package at path, a
+// directory or a file, with a definition per type
+func Schema(path string, options *Options) (map[string]any, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	pkg, err := loadPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	c := newConverter(pkg)
+
+	selected := map[string]bool{}
+	var queue []string
+	for _, name := range pkg.order {
+		if matchesAny(name, options.IncludeTypes) {
+			selected[name] = true
+			queue = append(queue, name)
+		}
+	}
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("no types to generate in %s", path)
+	}
+	for len(queue) > 0 {
+		refs := map[string]bool{}
+		references(c.definitions[queue[0]], refs)
+		queue = queue[1:]
+		for name := range refs {
+			if !selected[name] {
+				selected[name] = true
+				queue = append(queue, name)
+			}
+		}
+	}
+
+	definitions := map[string]any{}
+	for name := range selected {
+		definitions[name] = c.definitions[name]
+	}
+	return map[string]any{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"definitions": definitions,
+	}, nil
+}
+
+// Tools returns the tool definitions, in options.ToolFormat, of the functions of the Go
+// package at path marked with the //go2schema:tool directive or, when none is, of its exported
+// functions whose parameters are a struct of the package, or nothing, after an optional
+// context.Context. A tool is named after the directive argument or the function in
+// snake_case, described by the doc comment of the function, and takes the schema of the
+// struct as parameters.
+func Tools(path string, options *Options) ([]map[string]any, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	pkg, err := loadPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	c := newConverter(pkg)
+	functions, err := c.selectTools(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []map[string]any
+	for _, function := range functions {
+		tool, err := c.tool(function, options.ToolFormat)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// selectTools returns the tool functions of the package matching options.IncludeTypes
+func (c *converter) selectTools(options *Options) ([]toolFunction, error) {
+	functions, err := c.toolFunctions()
+	if err != nil {
+		return nil, err
+	}
+	var selected []toolFunction
+	for _, function := range functions {
+		if len(options.IncludeTypes) == 0 || matchesAny(function.decl.Name.Name, options.IncludeTypes) {
+			selected = append(selected, function)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no tool functions in package %s", c.pkg.name)
+	}
+	return selected, nil
+}
+
+// matchesAny reports whether a name matches one of the glob patterns, or whether it is
+// exported when there are none
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return ast.IsExported(name)
+	}
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// ValidateSchema checks that the path is a Go package that parses
+func (g *Go2SchemaGenerator) ValidateSchema(schemaPath string) error {
+	_, err := loadPackage(schemaPath)
+	return err
+}
+
+// NewGo2SchemaGenerator creates a new instance of the go2schema generator
+func NewGo2SchemaGenerator() *Go2SchemaGenerator {
+	return &Go2SchemaGenerator{}
+}
+
+// Register automatically registers the go2schema generator with the default registry
+func init() {
+	generator := NewGo2SchemaGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register go2schema generator: %v", err))
+	}
+}
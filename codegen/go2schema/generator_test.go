@@ -0,0 +1,70 @@
+package go2schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		output  string
+		options *Options
+	}{
+		{"schema.json", nil},
+		{"schema.yaml", nil},
+		{"selected.json", &Options{IncludeTypes: []string{"GetForecastArgs"}}},
+		{"tools.json", &Options{ToolFormat: ToolFormatGeneric}},
+		{"tools_openai.json", &Options{ToolFormat: ToolFormatOpenAI}},
+		{"tools_anthropic.json", &Options{ToolFormat: ToolFormatAnthropic}},
+		{"dispatcher.go", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), test.output)
+			err := NewGo2SchemaGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath: filepath.Join("testdata", "weather"),
+				OutputPath: output,
+				Options:    test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.output+".golden"), source)
+			if strings.HasSuffix(test.output, ".go") {
+				weather, err := os.ReadFile(filepath.Join("testdata", "weather", "weather.go"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				gentest.Vet(t, map[string]string{"weather.go": string(weather), test.output: string(source)})
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{"weather", true},
+		{"missing", false},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			err := NewGo2SchemaGenerator().ValidateSchema(filepath.Join("testdata", test.path))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package go2schema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goPackage holds the declarations of a Go package the schemas are generated from
+type goPackage struct {
+	name  string
+	types map[string]*typeDecl
+	order []string // type names in declaration order
+	funcs []*ast.FuncDecl
+	enums map[string][]any // values of the constants declared with each named type
+}
+
+// typeDecl is a type declaration of the package and its doc comment
+type typeDecl struct {
+	spec *ast.TypeSpec
+	doc  string
+}
+
+// loadPackage parses the Go files of the package at path, a directory or one of its files.
+// Test files are skipped.
+func loadPackage(path string) (*goPackage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Go package: %w", err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.go")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	pkg := &goPackage{types: map[string]*typeDecl{}, enums: map[string][]any{}}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Go file: %w", err)
+		}
+		if pkg.name == "" {
+			pkg.name = parsed.Name.Name
+		} else if pkg.name != parsed.Name.Name {
+			return nil, fmt.Errorf("found packages %s and %s in %s", pkg.name, parsed.Name.Name, path)
+		}
+		pkg.addFile(parsed)
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", path)
+	}
+	return pkg, nil
+}
+
+// addFile records the type, constant and function declarations of a file
+func (p *goPackage) addFile(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				p.funcs = append(p.funcs, decl)
+			}
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.TYPE:
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					p.types[spec.Name.Name] = &typeDecl{spec: spec, doc: doc.Text()}
+					p.order = append(p.order, spec.Name.Name)
+				}
+			case token.CONST:
+				p.addConstants(decl)
+			}
+		}
+	}
+}
+
+// addConstants records the values of the constants declared with a named type, following
+// the implicit repetition of the previous type and iota in const blocks
+func (p *goPackage) addConstants(decl *ast.GenDecl) {
+	var typeName string
+	var values []ast.Expr
+	for iota, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		if spec.Type != nil || len(spec.Values) > 0 {
+			typeName, values = "", spec.Values
+			if ident, ok := spec.Type.(*ast.Ident); ok {
+				typeName = ident.Name
+			}
+		}
+		if typeName == "" {
+			continue
+		}
+		for i := range spec.Names {
+			if i >= len(values) {
+				break
+			}
+			if value, ok := constantValue(values[i], iota); ok {
+				p.enums[typeName] = append(p.enums[typeName], value)
+			}
+		}
+	}
+}
+
+// constantValue returns the value of a literal or iota constant expression
+func constantValue(expr ast.Expr, iota int) (any, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.STRING:
+			value, err := strconv.Unquote(expr.Value)
+			return value, err == nil
+		case token.INT:
+			value, err := strconv.ParseInt(expr.Value, 0, 64)
+			return value, err == nil
+		case token.FLOAT:
+			value, err := strconv.ParseFloat(expr.Value, 64)
+			return value, err == nil
+		}
+	case *ast.Ident:
+		if expr.Name == "iota" {
+			return int64(iota), true
+		}
+	case *ast.UnaryExpr:
+		if expr.Op == token.SUB {
+			switch value, ok := constantValue(expr.X, iota); value := value.(type) {
+			case int64:
+				return -value, ok
+			case float64:
+				return -value, ok
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,430 @@
+package go2schema
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// definitionsRef is the prefix of the $refs to the definitions of the schema document
+const definitionsRef = "#/definitions/"
+
+// basicTypes are the JSON Schema types of the predeclared Go types
+var basicTypes = map[string]string{
+	"string": "string", "bool": "boolean",
+	"int": "integer", "int8": "integer", "int16": "integer", "int32": "integer", "int64": "integer",
+	"uint": "integer", "uint8": "integer", "uint16": "integer", "uint32": "integer", "uint64": "integer",
+	"uintptr": "integer", "byte": "integer", "rune": "integer",
+	"float32": "number", "float64": "number",
+}
+
+// externalTypes are the schemas of the types of other packages, by package and type name
+var externalTypes = map[string]map[string]any{
+	"time.Time":       {"type": "string", "format": "date-time"},
+	"time.Duration":   {"type": "integer", "description": "Duration in nanoseconds"},
+	"json.RawMessage": {},
+	"json.Number":     {"type": "number"},
+	"url.URL":         {"type": "string", "format": "uri"},
+	"uuid.UUID":       {"type": "string", "format": "uuid"},
+	"decimal.Decimal": {"type": "string", "format": "decimal"},
+}
+
+// validatorFormats maps go-playground/validator tags to string formats, the reverse of the
+// mapping the jsonrpc generator writes validate tags with
+var validatorFormats = map[string]string{
+	"email":            "email",
+	"uri":              "uri",
+	"url":              "uri",
+	"uuid":             "uuid",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"datetime":         "date-time",
+}
+
+// generatedParagraphs start the paragraphs of doc comments the jsonrpc generator derives from
+// the schema, which are not part of the description
+var generatedParagraphs = []string{"Allowed values: ", "Constraints: ", "Examples: "}
+
+// converter turns the type declarations of a package into JSON Schema definitions
+type converter struct {
+	pkg         *goPackage
+	definitions map[string]map[string]any
+}
+
+// newConverter returns a converter holding the definition of every type of pkg
+func newConverter(pkg *goPackage) *converter {
+	c := &converter{pkg: pkg, definitions: map[string]map[string]any{}}
+	for _, name := range pkg.order {
+		c.definitions[name] = c.typeSchema(name)
+	}
+	return c
+}
+
+// typeSchema returns the definition of a named type of the package: its underlying type,
+// described by its doc comment, with the values of its constants as enum
+func (c *converter) typeSchema(name string) map[string]any {
+	decl := c.pkg.types[name]
+	if decl.spec.TypeParams != nil {
+		return map[string]any{}
+	}
+	schema := c.exprSchema(decl.spec.Type)
+	if schema["$ref"] != nil {
+		// A definition defined as another keeps the other's keywords next to the description
+		schema = map[string]any{"allOf": []any{schema}}
+	}
+	applyDoc(schema, decl.doc)
+	if values := c.pkg.enums[name]; len(values) > 0 && schema["type"] != "object" {
+		var enum []any
+		for _, value := range values {
+			if !slices.Contains(enum, value) {
+				enum = append(enum, value)
+			}
+		}
+		schema["enum"] = enum
+	}
+	return schema
+}
+
+// applyDoc sets the description of schema to a doc comment, leaving out the paragraphs the
+// jsonrpc generator derives from the schema, and marks it deprecated when the comment has a
+// Deprecated paragraph
+func applyDoc(schema map[string]any, doc string) {
+	var paragraphs []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(doc), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if strings.HasPrefix(paragraph, "Deprecated: ") {
+			schema["deprecated"] = true
+		}
+		generated := slices.ContainsFunc(generatedParagraphs, func(prefix string) bool {
+			return strings.HasPrefix(paragraph, prefix)
+		})
+		if paragraph != "" && !generated {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	if len(paragraphs) > 0 {
+		schema["description"] = strings.Join(paragraphs, "\n\n")
+	}
+}
+
+// exprSchema returns the schema of a type expression
+func (c *converter) exprSchema(expr ast.Expr) map[string]any {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if schemaType, ok := basicTypes[expr.Name]; ok {
+			return map[string]any{"type": schemaType}
+		}
+		if _, ok := c.pkg.types[expr.Name]; ok {
+			return map[string]any{"$ref": definitionsRef + expr.Name}
+		}
+		// any, interface{} and the types the package does not declare accept any value
+		return map[string]any{}
+	case *ast.StarExpr:
+		return c.exprSchema(expr.X)
+	case *ast.ParenExpr:
+		return c.exprSchema(expr.X)
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			if schema, ok := externalTypes[pkg.Name+"."+expr.Sel.Name]; ok {
+				return cloneSchema(schema)
+			}
+		}
+		return map[string]any{}
+	case *ast.ArrayType:
+		if ident, ok := expr.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") && expr.Len == nil {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		schema := map[string]any{"type": "array", "items": c.exprSchema(expr.Elt)}
+		if literal, ok := expr.Len.(*ast.BasicLit); ok && literal.Kind == token.INT {
+			if length, err := strconv.Atoi(literal.Value); err == nil {
+				schema["minItems"], schema["maxItems"] = length, length
+			}
+		}
+		return schema
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": c.exprSchema(expr.Value)}
+	case *ast.StructType:
+		return c.structSchema(expr, map[string]bool{})
+	}
+	return map[string]any{}
+}
+
+// cloneSchema returns a deep copy of a schema
+func cloneSchema(schema map[string]any) map[string]any {
+	clone, _ := cloneValue(schema).(map[string]any)
+	return clone
+}
+
+// cloneValue returns a deep copy of a decoded JSON value
+func cloneValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(value))
+		for key, item := range value {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(value))
+		for i, item := range value {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	}
+	return value
+}
+
+// structSchema returns the object schema of a struct type, whose properties are the fields
+// encoding/json encodes: the exported fields named by their json tags, and the fields of
+// embedded structs. A field is required unless its json tag has omitempty or omitzero and its
+// validate tag does not have required.
+func (c *converter) structSchema(st *ast.StructType, embedding map[string]bool) map[string]any {
+	properties := map[string]any{}
+	var required []any
+	c.addFields(st, properties, &required, embedding)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the properties of the fields of st to properties, leaving the properties
+// already set by an embedding struct
+func (c *converter) addFields(st *ast.StructType, properties map[string]any, required *[]any, embedding map[string]bool) {
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		name, options, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			// The fields of embedded structs of other packages are unknown, so they are only
+			// described when the json tag names them
+			embedded := embeddedName(field.Type)
+			decl := c.pkg.types[embedded.Name]
+			if decl == nil && name == "" {
+				continue
+			}
+			if st, ok := declStruct(decl); ok && name == "" {
+				if !embedding[embedded.Name] {
+					embedding[embedded.Name] = true
+					c.addFields(st, properties, required, embedding)
+					delete(embedding, embedded.Name)
+				}
+				continue
+			}
+			if name == "" && !embedded.IsExported() {
+				continue
+			}
+			names = []*ast.Ident{embedded}
+		}
+
+		for _, ident := range names {
+			if !ident.IsExported() && len(field.Names) > 0 {
+				continue
+			}
+			property := name
+			if property == "" {
+				property = ident.Name
+			}
+			if _, ok := properties[property]; ok {
+				continue
+			}
+
+			schema := c.exprSchema(field.Type)
+			if slices.Contains(strings.Split(options, ","), "string") {
+				schema = map[string]any{"type": "string"}
+			}
+			applyDoc(schema, fieldDoc(field))
+			optional := slices.ContainsFunc(strings.Split(options, ","), func(option string) bool {
+				return option == "omitempty" || option == "omitzero"
+			})
+			if rules, ok := tag.Lookup("validate"); ok {
+				if c.applyValidateRules(schema, rules) {
+					optional = false
+				}
+			}
+			properties[property] = schema
+			if !optional {
+				*required = append(*required, property)
+			}
+		}
+	}
+}
+
+// embeddedName returns the type name of an embedded field
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel
+	case *ast.IndexExpr:
+		return embeddedName(expr.X)
+	case *ast.Ident:
+		return expr
+	}
+	return ast.NewIdent("_")
+}
+
+// declStruct returns the struct type of a type declaration
+func declStruct(decl *typeDecl) (*ast.StructType, bool) {
+	if decl == nil {
+		return nil, false
+	}
+	st, ok := decl.spec.Type.(*ast.StructType)
+	return st, ok
+}
+
+// fieldDoc returns the doc comment of a struct field, or its line comment when it has none
+func fieldDoc(field *ast.Field) string {
+	if text := field.Doc.Text(); text != "" {
+		return text
+	}
+	return field.Comment.Text()
+}
+
+// applyValidateRules adds the constraints of the go-playground/validator rules of a validate
+// tag to schema, and reports whether they require the field. Rules after dive apply to the
+// items, and rules without a JSON Schema equivalent are ignored.
+func (c *converter) applyValidateRules(schema map[string]any, rules string) bool {
+	required := false
+	kind := c.schemaKind(schema)
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			break
+		}
+		key, value, _ := strings.Cut(rule, "=")
+		number, isNumber := parseNumber(value)
+		switch key {
+		case "required":
+			required = true
+		case "min", "max", "len":
+			if !isNumber {
+				continue
+			}
+			keywords := map[string][2]string{
+				"string":  {"minLength", "maxLength"},
+				"array":   {"minItems", "maxItems"},
+				"object":  {"minProperties", "maxProperties"},
+				"integer": {"minimum", "maximum"},
+				"number":  {"minimum", "maximum"},
+			}[kind]
+			if keywords[0] == "" {
+				continue
+			}
+			if key != "max" {
+				schema[keywords[0]] = number
+			}
+			if key != "min" {
+				schema[keywords[1]] = number
+			}
+		case "gt", "gte", "lt", "lte":
+			if !isNumber || kind != "integer" && kind != "number" {
+				continue
+			}
+			schema[map[string]string{
+				"gt": "exclusiveMinimum", "gte": "minimum", "lt": "exclusiveMaximum", "lte": "maximum",
+			}[key]] = number
+		case "oneof":
+			var enum []any
+			for _, option := range oneOfOptions(value) {
+				if number, ok := parseNumber(option); ok && (kind == "integer" || kind == "number") {
+					enum = append(enum, number)
+				} else {
+					enum = append(enum, option)
+				}
+			}
+			schema["enum"] = enum
+		case "unique":
+			if kind == "array" {
+				schema["uniqueItems"] = true
+			}
+		default:
+			if format, ok := validatorFormats[key]; ok && kind == "string" {
+				schema["format"] = format
+			}
+		}
+	}
+	return required
+}
+
+// schemaKind returns the JSON Schema type of schema, following a $ref to a definition
+func (c *converter) schemaKind(schema map[string]any) string {
+	for depth := 0; depth <= len(c.pkg.types); depth++ {
+		if kind, ok := schema["type"].(string); ok {
+			return kind
+		}
+		ref, _ := schema["$ref"].(string)
+		if ref == "" {
+			if allOf, ok := schema["allOf"].([]any); ok && len(allOf) == 1 {
+				schema, _ = allOf[0].(map[string]any)
+				continue
+			}
+			return ""
+		}
+		decl := c.pkg.types[strings.TrimPrefix(ref, definitionsRef)]
+		if decl == nil {
+			return ""
+		}
+		schema = c.exprSchema(decl.spec.Type)
+	}
+	return ""
+}
+
+// parseNumber parses a number of a validate rule
+func parseNumber(value string) (any, bool) {
+	if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return integer, true
+	}
+	if float, err := strconv.ParseFloat(value, 64); err == nil {
+		return float, true
+	}
+	return nil, false
+}
+
+// oneOfOptions splits the space separated options of a oneof rule, which are quoted with
+// single quotes when they contain spaces
+func oneOfOptions(value string) []string {
+	var options []string
+	for value = strings.TrimSpace(value); value != ""; value = strings.TrimSpace(value) {
+		if quoted, ok := strings.CutPrefix(value, "'"); ok {
+			option, rest, _ := strings.Cut(quoted, "'")
+			options, value = append(options, option), rest
+			continue
+		}
+		option, rest, _ := strings.Cut(value, " ")
+		options, value = append(options, option), rest
+	}
+	return options
+}
+
+// references adds the names of the definitions value refers to to refs
+func references(value any, refs map[string]bool) {
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			refs[strings.TrimPrefix(ref, definitionsRef)] = true
+		}
+		for _, item := range value {
+			references(item, refs)
+		}
+	case []any:
+		for _, item := range value {
+			references(item, refs)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package weather
+
+import (
+	"context"
+	"time"
+)
+
+// Unit is a temperature unit
+type Unit string
+
+const (
+	Celsius    Unit = "celsius"
+	Fahrenheit Unit = "fahrenheit"
+)
+
+type Level int
+
+const (
+	Low Level = iota
+	Medium
+	High
+)
+
+// Location is a place on earth
+type Location struct {
+	// City name
+	City    string `json:"city" validate:"required,min=1,max=100"`
+	Country string `json:"country,omitempty" validate:"omitempty,len=2"`
+}
+
+// Base holds common fields
+type Base struct {
+	RequestID string `json:"request_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// GetForecastArgs are the arguments of GetForecast
+type GetForecastArgs struct {
+	Base
+	Location Location         `json:"location"`
+	Unit     *Unit            `json:"unit,omitempty"`
+	Days     int              `json:"days" validate:"gte=1,lte=14"`
+	Kinds    []string         `json:"kinds,omitempty" validate:"omitempty,unique,dive,oneof=rain snow"`
+	At       time.Time        `json:"at,omitempty"`
+	Level    Level            `json:"level"`
+	Mode     string           `json:"mode" validate:"oneof=fast 'very slow'"`
+	Next     *GetForecastArgs `json:"next,omitempty"`
+	Count    int64            `json:"count,string"`
+	internal string
+	Ignored  string `json:"-"`
+	// Deprecated: use Location
+	City string `json:"city,omitempty"`
+}
+
+// GetForecast returns the weather forecast of a location.
+func GetForecast(ctx context.Context, args GetForecastArgs) (string, error) { return "", nil }
+
+// Ping checks the service
+func Ping(ctx context.Context) error { return nil }
+
+func GetURLInfo(args *Location) {}
+
+func notExported(args Location) {}
+
+func Multi(a, b int) {}
+
+// Tree is a recursive node
+type Tree struct {
+	Children []Tree `json:"children"`
+}
+
+type WalkArgs struct {
+	Root Tree `json:"root"`
+}
+
+func Walk(args WalkArgs) {}
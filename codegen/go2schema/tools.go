@@ -0,0 +1,257 @@
+package go2schema
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Tool definition formats of ToolFormat
+const (
+	// ToolFormatGeneric writes provider-agnostic tools: {"name", "description", "parameters"}
+	ToolFormatGeneric = "generic"
+
+	// ToolFormatOpenAI writes OpenAI function-calling tools:
+	// {"type": "function", "function": {"name", "description", "parameters"}}
+	ToolFormatOpenAI = "openai"
+
+	// ToolFormatAnthropic writes Anthropic tools: {"name", "description", "input_schema"}
+	ToolFormatAnthropic = "anthropic"
+)
+
+// toolDirective marks the functions exposed as tools, optionally followed by the tool name
+// (default: the function name in snake_case)
+const toolDirective = "//go2schema:tool"
+
+// toolNamePattern matches the tool names LLM providers accept
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// toolFunction is a function of the package described as a tool
+type toolFunction struct {
+	decl    *ast.FuncDecl
+	name    string // tool name
+	context bool   // whether the function takes a context.Context first
+	params  string // struct type of the arguments, empty when the function takes none
+	pointer bool   // whether the function takes a pointer to the arguments
+	result  bool   // whether the function returns a value
+	err     bool   // whether the function returns an error last
+}
+
+// toolFunctions returns the functions of the package marked with the tool directive or, when
+// none is, the exported functions whose signature a tool can call. A marked function with
+// another signature is an error.
+func (c *converter) toolFunctions() ([]toolFunction, error) {
+	var marked, exported []toolFunction
+	for _, decl := range c.pkg.funcs {
+		name, isMarked := toolName(decl)
+		function, err := c.toolFunction(decl, name)
+		switch {
+		case isMarked && err != nil:
+			return nil, fmt.Errorf("function %s: %w", decl.Name.Name, err)
+		case isMarked:
+			if !toolNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("function %s: invalid tool name %q", decl.Name.Name, name)
+			}
+			marked = append(marked, function)
+		case err == nil && decl.Name.IsExported():
+			exported = append(exported, function)
+		}
+	}
+	if len(marked) > 0 {
+		return marked, nil
+	}
+	return exported, nil
+}
+
+// toolName returns the tool name of a function and whether its doc comment has the tool
+// directive
+func toolName(decl *ast.FuncDecl) (string, bool) {
+	if decl.Doc != nil {
+		for _, comment := range decl.Doc.List {
+			if rest, ok := strings.CutPrefix(comment.Text, toolDirective); ok && (rest == "" || rest[0] == ' ') {
+				if name := strings.TrimSpace(rest); name != "" {
+					return name, true
+				}
+				return snakeCase(decl.Name.Name), true
+			}
+		}
+	}
+	return snakeCase(decl.Name.Name), false
+}
+
+// toolFunction returns the tool calling a function whose parameters are a struct of the
+// package, or nothing, after an optional context.Context, and whose results are a value, an
+// error, both or nothing
+func (c *converter) toolFunction(decl *ast.FuncDecl, name string) (toolFunction, error) {
+	function := toolFunction{decl: decl, name: name}
+	if decl.Type.TypeParams != nil {
+		return function, fmt.Errorf("generic functions cannot be tools")
+	}
+
+	params := fieldTypes(decl.Type.Params)
+	if len(params) > 0 && isContext(params[0]) {
+		function.context = true
+		params = params[1:]
+	}
+	switch len(params) {
+	case 0:
+	case 1:
+		argument := params[0]
+		if star, ok := argument.(*ast.StarExpr); ok {
+			function.pointer, argument = true, star.X
+		}
+		ident, ok := argument.(*ast.Ident)
+		if _, isStruct := declStruct(c.pkg.types[identName(ident)]); !ok || !isStruct {
+			return function, fmt.Errorf("the arguments must be a struct of the package")
+		}
+		function.params = ident.Name
+	default:
+		return function, fmt.Errorf("a tool takes a single struct of arguments, after an optional context.Context")
+	}
+
+	results := fieldTypes(decl.Type.Results)
+	if len(results) > 0 {
+		if ident, ok := results[len(results)-1].(*ast.Ident); ok && ident.Name == "error" {
+			function.err = true
+			results = results[:len(results)-1]
+		}
+	}
+	if len(results) > 1 {
+		return function, fmt.Errorf("a tool returns a single value, optionally followed by an error")
+	}
+	function.result = len(results) == 1
+	return function, nil
+}
+
+// fieldTypes returns the type of every parameter or result of a list
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, field := range fields.List {
+		for range max(len(field.Names), 1) {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// identName returns the name of an identifier, or an empty string for nil
+func identName(ident *ast.Ident) string {
+	if ident == nil {
+		return ""
+	}
+	return ident.Name
+}
+
+// isContext reports whether a parameter type is context.Context
+func isContext(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && selector.Sel.Name == "Context"
+}
+
+// tool returns the definition of a tool function in format
+func (c *converter) tool(function toolFunction, format string) (map[string]any, error) {
+	parameters := map[string]any{"type": "object", "properties": map[string]any{}}
+	if function.params != "" {
+		parameters = c.parameters(function.params)
+	}
+
+	tool := map[string]any{"name": function.name}
+	if description := strings.TrimSpace(function.decl.Doc.Text()); description != "" {
+		tool["description"] = description
+	}
+	switch format {
+	case ToolFormatGeneric:
+		tool["parameters"] = parameters
+		return tool, nil
+	case ToolFormatOpenAI:
+		tool["parameters"] = parameters
+		return map[string]any{"type": "function", "function": tool}, nil
+	case ToolFormatAnthropic:
+		tool["input_schema"] = parameters
+		return tool, nil
+	}
+	return nil, fmt.Errorf("unsupported tool format %q: must be %s, %s or %s", format, ToolFormatGeneric, ToolFormatOpenAI, ToolFormatAnthropic)
+}
+
+// parameters returns the schema of the arguments struct of a tool. The definitions it refers
+// to are inlined, since tool parameters are self-contained schemas; recursive references
+// point at the parameters (#) or, for other definitions, at a copy under $defs.
+func (c *converter) parameters(typeName string) map[string]any {
+	defs := map[string]any{}
+	parameters, _ := c.inline(c.definitions[typeName], typeName, []string{typeName}, defs).(map[string]any)
+	if len(defs) > 0 {
+		parameters["$defs"] = defs
+	}
+	return parameters
+}
+
+// inline returns a copy of value with the $refs to definitions replaced by the definitions,
+// except those to root and the definitions of stack, being inlined, which refer to # and
+// $defs instead
+func (c *converter) inline(value any, root string, stack []string, defs map[string]any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, definitionsRef) {
+			name := strings.TrimPrefix(ref, definitionsRef)
+			var resolved map[string]any
+			switch {
+			case name == root:
+				resolved = map[string]any{"$ref": "#"}
+			case slices.Contains(stack, name):
+				if _, ok := defs[name]; !ok {
+					defs[name] = map[string]any{}
+					defs[name] = c.inline(c.definitions[name], root, []string{name}, defs)
+				}
+				resolved = map[string]any{"$ref": "#/$defs/" + name}
+			default:
+				resolved, _ = c.inline(c.definitions[name], root, append(slices.Clone(stack), name), defs).(map[string]any)
+			}
+			for key, item := range value {
+				if key != "$ref" {
+					resolved[key] = c.inline(item, root, stack, defs)
+				}
+			}
+			return resolved
+		}
+		inlined := make(map[string]any, len(value))
+		for key, item := range value {
+			inlined[key] = c.inline(item, root, stack, defs)
+		}
+		return inlined
+	case []any:
+		inlined := make([]any, len(value))
+		for i, item := range value {
+			inlined[i] = c.inline(item, root, stack, defs)
+		}
+		return inlined
+	}
+	return value
+}
+
+// snakeCase returns the snake_case tool name of a Go function name, keeping acronyms
+// together (GetURLInfo: get_url_info)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
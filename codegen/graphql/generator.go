@@ -0,0 +1,122 @@
+// Package graphql provides a generator of GraphQL schemas (SDL), declaring the types, enums,
+// unions and input types of the definitions of JSON Schema, OpenAPI and OpenRPC documents
+package graphql
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// GraphQLGenerator implements the Generator interface for GraphQL schemas
+type GraphQLGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *GraphQLGenerator) Name() string {
+	return "graphql"
+}
+
+// Description returns a human-readable description
+func (g *GraphQLGenerator) Description() string {
+	return "Generates GraphQL types, enums, unions and inputs (SDL) from JSON Schema, OpenAPI and OpenRPC documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *GraphQLGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes
+func (g *GraphQLGenerator) OutputFormats() []string {
+	return []string{".graphql", ".graphqls", ".gql"}
+}
+
+// Options for the GraphQL generator
+type Options struct {
+	// GeneratorOptions select and name the definitions as for the Go types: IncludeTypes,
+	// ExcludeTypes, CustomAcronyms, Naming, PreserveOrder, ReadWriteVariants, the remote
+	// refs options and IncludeComments. The options of the Go code do not apply.
+	*jrpc.GeneratorOptions
+
+	// Nullability selects the fields of object types declared non-null:
+	// NullabilityRequired (default), NullabilityNonNull or NullabilityNullable
+	Nullability string
+
+	// NoInputs leaves out the input types declared for the object types
+	NoInputs bool
+}
+
+// Generate writes the GraphQL schema of the definitions of the schema: object types for
+// objects, enums for string enums, unions for unions of objects, and an input type per object
+// type. The other enums and unions are declared as custom scalars (see GenerationWarnings),
+// and the other definitions are inlined where they are used.
+func (g *GraphQLGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{IncludeComments: true}
+	}
+	switch options.Nullability {
+	case "":
+		options.Nullability = NullabilityRequired
+	case NullabilityRequired, NullabilityNonNull, NullabilityNullable:
+	default:
+		return fmt.Errorf("unsupported nullability %q: must be %s, %s or %s",
+			options.Nullability, NullabilityRequired, NullabilityNonNull, NullabilityNullable)
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported by the graphql generator", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	s := newSchema(definitions, options)
+	s.convert()
+
+	if err := os.WriteFile(config.OutputPath, []byte(s.render()), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// GenerationWarnings returns the definitions the GraphQL schema of a schema file declares as
+// custom scalars, for lack of a GraphQL type: the enums of other values than strings and the
+// unions of other members than object types
+func (g *GraphQLGenerator) GenerationWarnings(schemaPath string) ([]jrpc.Finding, error) {
+	definitions, err := jrpc.LoadDefinitions(schemaPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	pointers, err := jrpc.DefinitionPointers(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	s := newSchema(definitions, &Options{GeneratorOptions: &jrpc.GeneratorOptions{}, Nullability: NullabilityRequired})
+	s.convert()
+	return s.warnings(pointers), nil
+}
+
+// ValidateSchema checks that the schema declares definitions
+func (g *GraphQLGenerator) ValidateSchema(schemaPath string) error {
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// NewGraphQLGenerator creates a new instance of the GraphQL generator
+func NewGraphQLGenerator() *GraphQLGenerator {
+	return &GraphQLGenerator{}
+}
+
+// Register automatically registers the GraphQL generator with the default registry
+func init() {
+	generator := NewGraphQLGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register graphql generator: %v", err))
+	}
+}
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		options *Options
+	}{
+		{"store", "store.json", nil},
+		{"store_non_null", "store.json", &Options{Nullability: NullabilityNonNull}},
+		{"store_no_inputs", "store.json", &Options{NoInputs: true}},
+		{"tasks", "tasks.yaml", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), test.name+".graphql")
+			err := NewGraphQLGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", test.schema),
+				OutputPath:  output,
+				PackageName: "store",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".graphql.golden"), source)
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"store.json", true},
+		{"tasks.yaml", true},
+		{"missing.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewGraphQLGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestGenerationWarnings(t *testing.T) {
+	tests := []struct {
+		schema string
+		paths  []string
+	}{
+		{"store.json", []string{"/definitions/Identifier", "/definitions/Priority"}},
+		{"tasks.yaml", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			findings, err := NewGraphQLGenerator().GenerationWarnings(filepath.Join("testdata", test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, finding := range findings {
+				if finding.Rule != jrpc.RuleScalarFallback {
+					t.Errorf("rule = %s, want %s", finding.Rule, jrpc.RuleScalarFallback)
+				}
+				paths = append(paths, finding.Path)
+			}
+			if !slices.Equal(paths, test.paths) {
+				t.Errorf("paths = %v, want %v", paths, test.paths)
+			}
+		})
+	}
+}
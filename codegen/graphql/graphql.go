@@ -0,0 +1,687 @@
+package graphql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Nullability mappings of Options.Nullability
+const (
+	// NullabilityRequired declares the required properties that are not nullable as non-null
+	// fields
+	NullabilityRequired = "required"
+
+	// NullabilityNonNull declares every property that is not nullable as a non-null field, for
+	// APIs that always return every field
+	NullabilityNonNull = "non-null"
+
+	// NullabilityNullable declares every field and list item nullable, so that a failing
+	// resolver nulls its field rather than its parent
+	NullabilityNullable = "nullable"
+)
+
+// namePattern matches the names GraphQL accepts
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// scalars are the built-in scalars of GraphQL and the custom scalars the schema may declare,
+// which definitions must not be named after
+var scalars = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+	"JSON": true, "DateTime": true, "Date": true, "Time": true, "Long": true,
+}
+
+// Kinds of definitions
+const (
+	kindType   = "type"
+	kindEnum   = "enum"
+	kindUnion  = "union"
+	kindScalar = "scalar" // declared as a custom scalar, for lack of a GraphQL type
+	kindAlias  = "alias"  // inlined where it is used
+)
+
+// declaration is a type, input, enum or union of the schema
+type declaration struct {
+	keyword     string // type, input, enum or union
+	name        string
+	description string
+	fields      []field
+	values      []string
+	members     []string
+}
+
+// field is a field of a type or input
+type field struct {
+	name        string
+	typ         string
+	description string
+	deprecated  bool
+}
+
+// schema converts the definitions of a document into GraphQL declarations
+type schema struct {
+	definitions map[string]any
+	options     *Options
+
+	names        map[string]string // GraphQL name of each declared definition
+	kinds        map[string]string // kind of each definition
+	inputs       map[string]string // input name of the object types with writable fields
+	inlineNames  map[string]string // name of the enums and unions declared for properties
+	taken        map[string]bool   // declared names
+	resolving    map[string]bool   // aliases being inlined
+	customs      map[string]bool   // custom scalars used
+	declarations map[string]*declaration
+}
+
+// newSchema returns the converter of the definitions
+func newSchema(definitions map[string]any, options *Options) *schema {
+	return &schema{
+		definitions:  definitions,
+		options:      options,
+		names:        map[string]string{},
+		kinds:        map[string]string{},
+		inputs:       map[string]string{},
+		inlineNames:  map[string]string{},
+		taken:        map[string]bool{},
+		resolving:    map[string]bool{},
+		customs:      map[string]bool{},
+		declarations: map[string]*declaration{},
+	}
+}
+
+// convert declares the definitions, in alphabetical order
+func (s *schema) convert() {
+	var names []string
+	for name, definition := range s.definitions {
+		if _, ok := definition.(map[string]any); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if kind := s.kind(name); kind != kindAlias {
+			s.names[name] = s.unique(typeName(name, s.options))
+		}
+	}
+	if !s.options.NoInputs {
+		for _, name := range names {
+			if s.kind(name) == kindType && len(s.properties(name, true)) > 0 {
+				s.inputs[name] = s.unique(s.names[name] + "Input")
+			}
+		}
+	}
+
+	for _, name := range names {
+		definition := s.definitions[name].(map[string]any)
+		d := &declaration{name: s.names[name], description: s.description(definition)}
+		switch s.kind(name) {
+		case kindType:
+			d.keyword = "type"
+			d.fields = s.fields(name, false)
+		case kindEnum:
+			d.keyword = "enum"
+			d.values = s.enumValues(definition)
+		case kindUnion:
+			d.keyword = "union"
+			d.members = s.unionMembers(definition)
+		case kindScalar:
+			d.keyword = "scalar"
+		default:
+			continue
+		}
+		s.declarations[d.name] = d
+
+		if input, ok := s.inputs[name]; ok {
+			s.declarations[input] = &declaration{
+				keyword:     "input",
+				name:        input,
+				description: d.description,
+				fields:      s.fields(name, true),
+			}
+		}
+	}
+}
+
+// warnings returns a scalar-fallback finding per definition declared as a custom scalar,
+// located by the pointers of the definitions when they have one
+func (s *schema) warnings(pointers map[string]string) []jrpc.Finding {
+	var names []string
+	for name, kind := range s.kinds {
+		if kind == kindScalar {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var findings []jrpc.Finding
+	for _, name := range names {
+		findings = append(findings, jrpc.Finding{
+			Rule:     jrpc.RuleScalarFallback,
+			Severity: jrpc.SeverityWarning,
+			Path:     pointers[name],
+			Message:  fmt.Sprintf("definition %s has no GraphQL type and is declared as the custom scalar %s", name, s.names[name]),
+		})
+	}
+	return findings
+}
+
+// kind returns how a definition is declared: an object type for objects with properties and
+// allOf of such objects, an enum for string enums, a union for unions of object types, a
+// custom scalar for the other enums and unions, and inlined otherwise
+func (s *schema) kind(name string) string {
+	if kind, ok := s.kinds[name]; ok {
+		return kind
+	}
+	s.kinds[name] = kindAlias
+	definition, _ := s.definitions[name].(map[string]any)
+	kind := kindAlias
+	switch {
+	case definition == nil:
+	case isStringEnum(definition):
+		kind = kindEnum
+	case definition["enum"] != nil:
+		kind = kindScalar
+	case definition["oneOf"] != nil || definition["anyOf"] != nil:
+		if len(s.unionMembers(definition)) > 1 {
+			kind = kindUnion
+		} else if len(nonNullMembers(definition)) > 1 {
+			kind = kindScalar
+		}
+	case len(s.properties(name, false)) > 0:
+		kind = kindType
+	}
+	s.kinds[name] = kind
+	return kind
+}
+
+// unionMembers returns the object types of a union, or nil unless all its members but null
+// are object types
+func (s *schema) unionMembers(definition map[string]any) []string {
+	var members []string
+	for _, key := range []string{"oneOf", "anyOf"} {
+		list, _ := definition[key].([]any)
+		for _, member := range list {
+			member, _ := member.(map[string]any)
+			if member["type"] == "null" {
+				continue
+			}
+			ref, ok := member["$ref"].(string)
+			if !ok || s.kind(refName(ref)) != kindType {
+				return nil
+			}
+			if name := s.names[refName(ref)]; name != "" && !slices.Contains(members, name) {
+				members = append(members, name)
+			} else if name == "" {
+				members = append(members, refName(ref))
+			}
+		}
+	}
+	return members
+}
+
+// property is a property of an object type, with the properties of its allOf flattened in
+type property struct {
+	name     string
+	schema   map[string]any
+	required bool
+	owner    string // definition declaring the property
+}
+
+// properties returns the properties of a definition and of its allOf, the writable ones for
+// an input
+func (s *schema) properties(name string, writable bool) []property {
+	var list []property
+	seen := map[string]bool{}
+	visited := map[string]bool{}
+	var collect func(owner string, definition map[string]any)
+	collect = func(owner string, definition map[string]any) {
+		allOf, _ := definition["allOf"].([]any)
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				if target, ok := s.definitions[refName(ref)].(map[string]any); ok && !visited[refName(ref)] {
+					visited[refName(ref)] = true
+					collect(refName(ref), target)
+				}
+			} else if member != nil {
+				collect(owner, member)
+			}
+		}
+		properties, _ := definition["properties"].(map[string]any)
+		required := stringList(definition["required"])
+		for _, propertyName := range jrpc.PropertyNames(definition) {
+			propertySchema, _ := properties[propertyName].(map[string]any)
+			if seen[propertyName] || writable && propertySchema["readOnly"] == true {
+				continue
+			}
+			seen[propertyName] = true
+			list = append(list, property{
+				name:     propertyName,
+				schema:   propertySchema,
+				required: slices.Contains(required, propertyName),
+				owner:    owner,
+			})
+		}
+	}
+	visited[name] = true
+	if definition, ok := s.definitions[name].(map[string]any); ok {
+		collect(name, definition)
+	}
+	return list
+}
+
+// fields returns the fields of the type or input of a definition
+func (s *schema) fields(name string, input bool) []field {
+	var fields []field
+	taken := map[string]bool{}
+	for _, p := range s.properties(name, input) {
+		var t string
+		var nullable bool
+		if p.name == "id" && isIdentifier(p.schema) {
+			t, nullable = "ID", isNullable(p.schema)
+		} else {
+			inlineName := typeName(p.owner, s.options) + jrpc.GoIdentifier(p.name, s.options.GeneratorOptions)
+			t, nullable = s.typeOf(p.schema, input, inlineName)
+		}
+		if s.nonNull(p.required, nullable, input) {
+			t += "!"
+		}
+		f := field{name: fieldName(p.name, taken, s.options), typ: t}
+		if p.schema != nil {
+			f.description = s.description(p.schema)
+			f.deprecated = !input && p.schema["deprecated"] == true
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// nonNull reports whether a field is declared non-null: inputs when they are required, types
+// following Options.Nullability
+func (s *schema) nonNull(required, nullable, input bool) bool {
+	switch {
+	case nullable:
+		return false
+	case input:
+		return required
+	case s.options.Nullability == NullabilityNonNull:
+		return true
+	case s.options.Nullability == NullabilityNullable:
+		return false
+	}
+	return required
+}
+
+// typeOf returns the GraphQL type of a schema, without its non-null marker, and whether the
+// schema is nullable. Inline string enums and unions of object types are declared under
+// inlineName.
+func (s *schema) typeOf(schema map[string]any, input bool, inlineName string) (string, bool) {
+	if schema == nil {
+		return s.custom("JSON"), true
+	}
+	nullable := isNullable(schema)
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		definition, ok := s.definitions[name].(map[string]any)
+		if !ok {
+			return s.custom("JSON"), true
+		}
+		nullable = nullable || isNullable(definition)
+		switch s.kind(name) {
+		case kindType:
+			if !input {
+				return s.names[name], nullable
+			}
+			if inputName, ok := s.inputs[name]; ok {
+				return inputName, nullable
+			}
+			return s.custom("JSON"), nullable
+		case kindEnum:
+			return s.names[name], nullable
+		case kindUnion:
+			if !input {
+				return s.names[name], nullable
+			}
+			return s.custom("JSON"), nullable
+		case kindScalar:
+			return s.names[name], nullable
+		}
+		if s.resolving[name] {
+			return s.custom("JSON"), true
+		}
+		s.resolving[name] = true
+		defer delete(s.resolving, name)
+		t, aliasNullable := s.typeOf(definition, input, inlineName)
+		return t, nullable || aliasNullable
+	}
+
+	if value, ok := schema["const"]; ok {
+		return s.scalarOf(value), nullable
+	}
+	if isStringEnum(schema) {
+		return s.inlineEnum(schema, inlineName), nullable
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		list, ok := schema[key].([]any)
+		if !ok {
+			continue
+		}
+		members := nonNullMembers(map[string]any{key: list})
+		if len(members) == 1 {
+			t, memberNullable := s.typeOf(members[0], input, inlineName)
+			return t, nullable || memberNullable
+		}
+		if union := s.unionMembers(schema); len(union) > 1 && !input {
+			return s.inlineUnion(union, schema, inlineName), nullable
+		}
+		return s.custom("JSON"), nullable
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		if member, _ := allOf[0].(map[string]any); len(allOf) == 1 {
+			t, memberNullable := s.typeOf(member, input, inlineName)
+			return t, nullable || memberNullable
+		}
+		return s.custom("JSON"), nullable
+	}
+
+	types := schemaTypes(schema)
+	if len(types) != 1 {
+		return s.custom("JSON"), true
+	}
+	switch types[0] {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return s.custom("DateTime"), nullable
+		case "date":
+			return s.custom("Date"), nullable
+		case "time":
+			return s.custom("Time"), nullable
+		}
+		return "String", nullable
+	case "integer":
+		switch schema["format"] {
+		case "int64", "uint32", "uint64":
+			return s.custom("Long"), nullable
+		}
+		return "Int", nullable
+	case "number":
+		return "Float", nullable
+	case "boolean":
+		return "Boolean", nullable
+	case "array":
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return s.custom("JSON"), nullable
+		}
+		item, itemNullable := s.typeOf(items, input, inlineName)
+		if !itemNullable && (input || s.options.Nullability != NullabilityNullable) {
+			item += "!"
+		}
+		return "[" + item + "]", nullable
+	}
+	return s.custom("JSON"), nullable
+}
+
+// scalarOf returns the scalar of a constant
+func (s *schema) scalarOf(value any) string {
+	switch value := value.(type) {
+	case string:
+		return "String"
+	case bool:
+		return "Boolean"
+	case float64:
+		if value == float64(int32(value)) {
+			return "Int"
+		}
+		return "Float"
+	}
+	return s.custom("JSON")
+}
+
+// inlineEnum declares the enum of an inline string enum, once per property
+func (s *schema) inlineEnum(schema map[string]any, inlineName string) string {
+	if name, ok := s.inlineNames[inlineName]; ok {
+		return name
+	}
+	name := s.unique(typeName(inlineName, s.options))
+	s.inlineNames[inlineName] = name
+	s.declarations[name] = &declaration{keyword: "enum", name: name, values: s.enumValues(schema)}
+	return name
+}
+
+// inlineUnion declares the union of an inline union of object types, once per property
+func (s *schema) inlineUnion(members []string, schema map[string]any, inlineName string) string {
+	if name, ok := s.inlineNames[inlineName]; ok {
+		return name
+	}
+	name := s.unique(typeName(inlineName, s.options))
+	s.inlineNames[inlineName] = name
+	s.declarations[name] = &declaration{keyword: "union", name: name, members: members}
+	return name
+}
+
+// enumValues returns the values of a string enum, named in UPPER_SNAKE_CASE
+func (s *schema) enumValues(schema map[string]any) []string {
+	var values []string
+	taken := map[string]bool{}
+	for _, value := range schema["enum"].([]any) {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		name := strings.ToUpper(snakeCase(jrpc.GoIdentifier(text, s.options.GeneratorOptions)))
+		if name == "" {
+			name = "VALUE"
+		}
+		unique := name
+		for i := 2; taken[unique]; i++ {
+			unique = name + "_" + strconv.Itoa(i)
+		}
+		taken[unique] = true
+		values = append(values, unique)
+	}
+	return values
+}
+
+// custom returns a custom scalar, declaring it in the schema
+func (s *schema) custom(name string) string {
+	s.customs[name] = true
+	return name
+}
+
+// unique returns name, or name with a number, so that no two declarations share it
+func (s *schema) unique(name string) string {
+	unique := name
+	for i := 2; s.taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	s.taken[unique] = true
+	return unique
+}
+
+// description returns the description of a schema, unless comments are disabled
+func (s *schema) description(schema map[string]any) string {
+	if !s.options.IncludeComments {
+		return ""
+	}
+	description, _ := schema["description"].(string)
+	return strings.TrimSpace(description)
+}
+
+// typeName returns the GraphQL name of a definition: its name, as for its Go type, or the Go
+// identifier of names that GraphQL does not accept. Names of scalars get a trailing underscore,
+// as Go keywords do.
+func typeName(name string, options *Options) string {
+	if !namePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+		name = jrpc.GoIdentifier(name, options.GeneratorOptions)
+	}
+	if scalars[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// fieldName returns the GraphQL name of a property, unique among taken: the property name, so
+// that the default resolvers read it, or the lowerCamelCase Go field name of names GraphQL does
+// not accept
+func fieldName(property string, taken map[string]bool, options *Options) string {
+	name := property
+	if !namePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+		name = jrpc.GoIdentifier(property, options.GeneratorOptions)
+		runes := []rune(name)
+		for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+			if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				break
+			}
+			runes[i] = unicode.ToLower(runes[i])
+		}
+		name = string(runes)
+		if name == "" {
+			name = "field"
+		}
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping acronyms whole (UserID is user_id)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '_' {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && !strings.HasSuffix(b.String(), "_") {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// isIdentifier reports whether the schema of an id property is a string or an integer, declared
+// as an ID
+func isIdentifier(schema map[string]any) bool {
+	if schema == nil || schema["$ref"] != nil || schema["enum"] != nil {
+		return false
+	}
+	switch schema["format"] {
+	case nil, "int32", "int64", "uuid":
+	default:
+		return false
+	}
+	types := schemaTypes(schema)
+	return len(types) == 1 && (types[0] == "string" || types[0] == "integer")
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// isStringEnum reports whether a schema is an enum of strings, but for null
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok {
+		return false
+	}
+	count := 0
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if _, ok := value.(string); !ok {
+			return false
+		}
+		count++
+	}
+	return count > 0
+}
+
+// nonNullMembers returns the oneOf and anyOf members of a schema but null
+func nonNullMembers(schema map[string]any) []map[string]any {
+	var members []map[string]any
+	for _, key := range []string{"oneOf", "anyOf"} {
+		list, _ := schema[key].([]any)
+		for _, member := range list {
+			if member, ok := member.(map[string]any); ok && member["type"] != "null" {
+				members = append(members, member)
+			}
+		}
+	}
+	return members
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// render returns the SDL of the declarations: the custom scalars, then the declarations in
+// alphabetical order
+func (s *schema) render() string {
+	var b strings.Builder
+	b.WriteString("# Code generated from JSON schema. DO NOT EDIT.\n")
+
+	customs := make([]string, 0, len(s.customs))
+	for name := range s.customs {
+		customs = append(customs, name)
+	}
+	sort.Strings(customs)
+	if len(customs) > 0 {
+		b.WriteString("\n")
+	}
+	for _, name := range customs {
+		fmt.Fprintf(&b, "scalar %s\n", name)
+	}
+
+	names := make([]string, 0, len(s.declarations))
+	for name := range s.declarations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString("\n")
+		s.declarations[name].write(&b)
+	}
+	return b.String()
+}
+
+// write writes a declaration
+func (d *declaration) write(b *strings.Builder) {
+	writeDescription(b, "", d.description)
+	switch d.keyword {
+	case "scalar":
+		fmt.Fprintf(b, "scalar %s\n", d.name)
+	case "union":
+		fmt.Fprintf(b, "union %s = %s\n", d.name, strings.Join(d.members, " | "))
+	case "enum":
+		fmt.Fprintf(b, "enum %s {\n", d.name)
+		for _, value := range d.values {
+			fmt.Fprintf(b, "  %s\n", value)
+		}
+		b.WriteString("}\n")
+	default:
+		fmt.Fprintf(b, "%s %s {\n", d.keyword, d.name)
+		for _, f := range d.fields {
+			writeDescription(b, "  ", f.description)
+			fmt.Fprintf(b, "  %s: %s%s\n", f.name, f.typ, deprecation(f.deprecated))
+		}
+		b.WriteString("}\n")
+	}
+}
+
+// deprecation returns the @deprecated directive of a deprecated field
+func deprecation(deprecated bool) string {
+	if deprecated {
+		return " @deprecated"
+	}
+	return ""
+}
+
+// writeDescription writes a description as a block string
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	description = strings.ReplaceAll(description, `"""`, `\"""`)
+	lines := strings.Split(description, "\n")
+	if len(lines) == 1 && !strings.HasSuffix(description, `"`) {
+		fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, description)
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range lines {
+		if line = strings.TrimRight(line, " \t\r"); line == "" {
+			b.WriteString("\n")
+		} else {
+			fmt.Fprintf(b, "%s%s\n", indent, line)
+		}
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
@@ -0,0 +1,154 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PreParseHook rewrites the contents of a schema before the generator reads it, e.g. to add
+// the vendor extensions of an organization or drop internal definitions
+type PreParseHook func(schema []byte) ([]byte, error)
+
+// PostEmitHook rewrites a file the generator wrote, given its path, e.g. to add a license or
+// replace an import
+type PostEmitHook func(path string, content []byte) ([]byte, error)
+
+// Hooks customize the generation of any generator before it reads the schema and after it
+// writes its files, so that organizations do not need to fork the templates. The hooks of a
+// point run in order, each on the result of the previous one. The generators built on the
+// definitions of jrpc also take hooks editing them in between (jrpc.GeneratorOptions.PostIR).
+type Hooks struct {
+	PreParse []PreParseHook
+	PostEmit []PostEmitHook
+}
+
+// fileState is what tells whether a generator wrote a file of the output directory
+type fileState struct {
+	modTime time.Time
+	content []byte
+}
+
+// equal reports whether the file is unchanged: the content is compared as well as the
+// modification time, which file systems may record too coarsely to tell two writes apart
+func (s fileState) equal(other fileState) bool {
+	return s.modTime.Equal(other.modTime) && bytes.Equal(s.content, other.content)
+}
+
+// readFileStates returns the states of the regular files of an output directory by name, or
+// none when the output is not a directory (yet)
+func readFileStates(dir string) (map[string]fileState, error) {
+	if info, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) || err == nil && !info.IsDir() {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]fileState, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		states[entry.Name()] = fileState{modTime: info.ModTime(), content: content}
+	}
+	return states, nil
+}
+
+// GenerateWithHooks runs a generator with hooks. The schema rewritten by the PreParse hooks is
+// written next to the schema, so that its relative $refs resolve, for the generator to read,
+// and removed afterwards; schema errors name the schema rather than the rewritten copy. The
+// PostEmit hooks rewrite the output file, or the files of the output directory the generator
+// wrote, such as those of split mode: those the directory did not have before the generation,
+// or whose content or modification time changed.
+func GenerateWithHooks(generator Generator, config GenerateConfig, hooks Hooks) error {
+	schemaPath := config.SchemaPath
+	if len(hooks.PreParse) > 0 {
+		data, err := os.ReadFile(schemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to read schema file: %w", err)
+		}
+		for i, hook := range hooks.PreParse {
+			if data, err = hook(data); err != nil {
+				return fmt.Errorf("pre-parse hook %d: %w", i+1, err)
+			}
+		}
+
+		ext := filepath.Ext(schemaPath)
+		rewritten, err := os.CreateTemp(filepath.Dir(schemaPath), "."+strings.TrimSuffix(filepath.Base(schemaPath), ext)+".*"+ext)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(rewritten.Name())
+		if _, err := rewritten.Write(data); err != nil {
+			rewritten.Close()
+			return err
+		}
+		if err := rewritten.Close(); err != nil {
+			return err
+		}
+		config.SchemaPath = rewritten.Name()
+	}
+
+	var before map[string]fileState
+	if len(hooks.PostEmit) > 0 {
+		var err error
+		if before, err = readFileStates(config.OutputPath); err != nil {
+			return err
+		}
+	}
+	if err := generator.Generate(config); err != nil {
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) && schemaErr.File == config.SchemaPath {
+			schemaErr.File = schemaPath
+		}
+		return err
+	}
+	if len(hooks.PostEmit) == 0 {
+		return nil
+	}
+
+	files := []string{config.OutputPath}
+	if info, err := os.Stat(config.OutputPath); err == nil && info.IsDir() {
+		after, err := readFileStates(config.OutputPath)
+		if err != nil {
+			return err
+		}
+		files = nil
+		for _, name := range slices.Sorted(maps.Keys(after)) {
+			if state, existed := before[name]; !existed || !state.equal(after[name]) {
+				files = append(files, filepath.Join(config.OutputPath, name))
+			}
+		}
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		for i, hook := range hooks.PostEmit {
+			if content, err = hook(file, content); err != nil {
+				return fmt.Errorf("post-emit hook %d on %s: %w", i+1, file, err)
+			}
+		}
+		if err := os.WriteFile(file, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
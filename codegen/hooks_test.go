@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writingGenerator writes files into the output directory, keeping the modification time of
+// those it overwrites when sameModTime is set, as file systems with a coarse one do
+type writingGenerator struct {
+	files       map[string]string
+	sameModTime bool
+}
+
+func (writingGenerator) Name() string                { return "writing" }
+func (writingGenerator) Description() string         { return "Writes files" }
+func (writingGenerator) SupportedFormats() []string  { return []string{".json"} }
+func (writingGenerator) ValidateSchema(string) error { return nil }
+
+func (g writingGenerator) Generate(config GenerateConfig) error {
+	if err := os.MkdirAll(config.OutputPath, 0o755); err != nil {
+		return err
+	}
+	for name, content := range g.files {
+		path := filepath.Join(config.OutputPath, name)
+		info, statErr := os.Stat(path)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+		if g.sameModTime && statErr == nil {
+			if err := os.Chtimes(path, time.Time{}, info.ModTime()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestGenerateWithHooksPostEmit(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    map[string]string // Files of the output directory before the generation
+		written     map[string]string
+		sameModTime bool
+		want        map[string]string
+	}{
+		{
+			name:    "new directory",
+			written: map[string]string{"a.go": "a", "b.go": "b"},
+			want:    map[string]string{"a.go": "hooked a", "b.go": "hooked b"},
+		},
+		{
+			name:     "files left alone",
+			existing: map[string]string{"a.go": "old a", "notes.txt": "notes"},
+			written:  map[string]string{"a.go": "a"},
+			want:     map[string]string{"a.go": "hooked a", "notes.txt": "notes"},
+		},
+		{
+			name:        "overwritten within the same modification time",
+			existing:    map[string]string{"a.go": "old a", "notes.txt": "notes"},
+			written:     map[string]string{"a.go": "a"},
+			sameModTime: true,
+			want:        map[string]string{"a.go": "hooked a", "notes.txt": "notes"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "generated")
+			if test.existing != nil {
+				if err := os.Mkdir(output, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				for name, content := range test.existing {
+					if err := os.WriteFile(filepath.Join(output, name), []byte(content), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			hooks := Hooks{PostEmit: []PostEmitHook{func(path string, content []byte) ([]byte, error) {
+				return append([]byte("hooked "), content...), nil
+			}}}
+			generator := writingGenerator{files: test.written, sameModTime: test.sameModTime}
+			if err := GenerateWithHooks(generator, GenerateConfig{SchemaPath: "schema.json", OutputPath: output}, hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := os.ReadDir(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				content, err := os.ReadFile(filepath.Join(output, entry.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[entry.Name()] = string(content)
+			}
+			if len(got) != len(test.want) {
+				t.Errorf("files = %v, want %v", got, test.want)
+			}
+			for name, content := range test.want {
+				if got[name] != content {
+					t.Errorf("%s = %q, want %q", name, got[name], content)
+				}
+			}
+		})
+	}
+}
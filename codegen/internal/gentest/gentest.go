@@ -0,0 +1,118 @@
+// Package gentest compiles and runs generated Go code in the tests of the generators
+package gentest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites the golden files with the output of the generators instead of comparing it
+var update = flag.Bool("update", false, "rewrite the golden files of the generator tests")
+
+// goMod is the go.mod of the modules generated code is checked in, which only use the
+// standard library
+const goMod = "module generated\n\ngo 1.25\n"
+
+// Run writes files (names relative to the module root, e.g. "types.go") to a new module and
+// runs the go command with args in it, failing t with the output of the command when it
+// fails. It skips t when the go command is not available or in -short mode.
+func Run(t testing.TB, files map[string]string, args ...string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compiling generated code is skipped in -short mode")
+	}
+	goCommand, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	dir := t.TempDir()
+	files["go.mod"] = goMod
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goCommand, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %v: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+// Vet type-checks and vets the generated files of a package, with Run
+func Vet(t testing.TB, files map[string]string) {
+	t.Helper()
+	Run(t, files, "vet", "./...")
+}
+
+// Golden compares got with the golden file at path, or rewrites the file with got when the
+// tests run with -update
+func Golden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run the tests with -update to create it)", err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	gotLines, wantLines := bytes.Split(got, []byte("\n")), bytes.Split(want, []byte("\n"))
+	line := 0
+	for line < len(gotLines) && line < len(wantLines) && bytes.Equal(gotLines[line], wantLines[line]) {
+		line++
+	}
+	t.Errorf("output differs from %s at line %d (run the tests with -update to accept it):\ngot:  %s\nwant: %s",
+		path, line+1, lineAt(gotLines, line), lineAt(wantLines, line))
+}
+
+// lineAt returns the line at index, or a marker past the end of lines
+func lineAt(lines [][]byte, index int) string {
+	if index >= len(lines) {
+		return "<end of file>"
+	}
+	return string(lines[index])
+}
+
+// Files returns the contents of the files of a directory written by a generator in split mode,
+// by name
+func Files(t testing.TB, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,128 @@
+package jrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"text/template"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// generateBenchmarks writes to options.BenchmarkTests a _test.go file with a
+// BenchmarkMarshalX and a BenchmarkUnmarshalX per generated type matching one of the
+// options.BenchmarkTypes patterns. The benchmarks encode and decode the first example of the
+// type's schema, or a value generated from it, and report their allocations and throughput.
+func generateBenchmarks(templates *template.Template, schemaName string, data []byte, source []byte, definitions map[string]any, options *GeneratorOptions) error {
+	declared, err := declaredTypes(source)
+	if err != nil {
+		return err
+	}
+	var types []string
+	for _, pattern := range options.BenchmarkTypes {
+		matched := false
+		for _, name := range declared {
+			if _, ok := definitions[name]; ok && matchesAny(name, []string{pattern}) {
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("benchmark pattern %q matches no generated type", pattern)
+		}
+	}
+	for _, name := range declared {
+		if _, ok := definitions[name]; ok && matchesAny(name, options.BenchmarkTypes) {
+			types = append(types, name)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	imports := newImportManager()
+	imports.add("encoding/json", "testing")
+	if err := executeTemplate(out, templates, headerTemplate, headerData(schemaName, data, imports.render(), options)); err != nil {
+		return err
+	}
+	out.WriteString(benchmarkHelpers)
+	for _, typeName := range types {
+		sample, err := json.Marshal(SampleValue(map[string]any{"$ref": typeName}, definitions, SampleOptions{Examples: true}))
+		if err != nil {
+			return fmt.Errorf("failed to encode the sample of %s: %w", typeName, err)
+		}
+		fmt.Fprintf(out, "\n// BenchmarkMarshal%s measures the encoding of %s\n", typeName, typeName)
+		fmt.Fprintf(out, "func BenchmarkMarshal%s(b *testing.B) {\n\tbenchmarkMarshal[%s](b, %s)\n}\n", typeName, typeName, strconv.Quote(string(sample)))
+		fmt.Fprintf(out, "\n// BenchmarkUnmarshal%s measures the decoding of %s\n", typeName, typeName)
+		fmt.Fprintf(out, "func BenchmarkUnmarshal%s(b *testing.B) {\n\tbenchmarkUnmarshal[%s](b, %s)\n}\n", typeName, typeName, strconv.Quote(string(sample)))
+	}
+
+	result := out.Bytes()
+	if options.FormatOutput {
+		formatted, err := format.Source(result)
+		if err != nil {
+			return &codegen.FormatError{Name: "benchmarks", Err: err}
+		}
+		result = formatted
+	}
+	if _, err := options.BenchmarkTests.Write(result); err != nil {
+		return fmt.Errorf("failed to write benchmarks: %w", err)
+	}
+	return nil
+}
+
+// declaredTypes returns the exported types declared by the source, in declaration order
+func declaredTypes(source []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	var types []string
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			if spec := spec.(*ast.TypeSpec); spec.Name.IsExported() {
+				types = append(types, spec.Name.Name)
+			}
+		}
+	}
+	return types, nil
+}
+
+// benchmarkHelpers are the benchmark functions shared by the BenchmarkMarshalX and
+// BenchmarkUnmarshalX benchmarks
+const benchmarkHelpers = `// benchmarkMarshal measures the encoding of the sample decoded into a T
+func benchmarkMarshal[T any](b *testing.B, sample string) {
+	var value T
+	if err := json.Unmarshal([]byte(sample), &value); err != nil {
+		b.Fatalf("decoding %s: %v", sample, err)
+	}
+	b.SetBytes(int64(len(sample)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(&value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkUnmarshal measures the decoding of the sample into a T
+func benchmarkUnmarshal[T any](b *testing.B, sample string) {
+	data := []byte(sample)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+`
@@ -0,0 +1,231 @@
+package jrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaContainers are the pointer segments under which documents keep their schemas. A ref
+// into one of them is a schema ref, whose target is bundled as a definition.
+var schemaContainers = []string{"/schemas/", "/definitions/", "/$defs/"}
+
+// schemaKeywords are keywords identifying a whole referenced document as a schema
+var schemaKeywords = []string{"$schema", "type", "properties", "items", "allOf", "anyOf", "oneOf", "enum", "const"}
+
+// Bundle reads a JSON Schema or OpenAPI document and resolves its external $refs, relative
+// file refs and HTTP(S) refs alike, into a single self-contained document. The targets of
+// schema refs are added to the definitions of the document (components.schemas, $defs or
+// definitions) and their refs rewritten to point at them; other targets, such as path items
+// or parameters, replace their ref. RefCacheDir and Offline apply to the remote documents.
+// With PreserveOrder, the declaration order of the properties of the document is recorded
+// for PropertyNames.
+func Bundle(schemaPath string, options *GeneratorOptions) (map[string]any, error) {
+	options, err := prepareOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := loadSchemaFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	if options.PreserveOrder {
+		data, err := os.ReadFile(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file: %w", err)
+		}
+		if err := recordPropertyOrder(data, document); err != nil {
+			return nil, fmt.Errorf("failed to read property order: %w", err)
+		}
+	}
+
+	absolute, err := filepath.Abs(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate schema file: %w", err)
+	}
+
+	resolver, err := newRemoteRefResolver(options)
+	if err != nil {
+		return nil, err
+	}
+	resolver.root = (&url.URL{Scheme: "file", Path: filepath.ToSlash(absolute)}).String()
+
+	definitions, attach := bundleDefinitions(document)
+	resolver.prefix = attach
+	if err := resolver.walk(document, "", definitions); err != nil {
+		return nil, err
+	}
+	if len(definitions) > 0 {
+		setPointer(document, strings.TrimPrefix(strings.TrimSuffix(attach, "/"), "#/"), definitions)
+	}
+
+	return document, nil
+}
+
+// loadSchemaFile reads and decodes a .json, .yaml or .yml schema file, with the keys YAML
+// decodes as other types converted to strings
+func loadSchemaFile(schemaPath string) (map[string]any, error) {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	var document map[string]any
+	switch {
+	case strings.HasSuffix(schemaPath, ".json"):
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON schema: %w", codegen.DecodeError(schemaPath, data, err))
+		}
+	case strings.HasSuffix(schemaPath, ".yaml"), strings.HasSuffix(schemaPath, ".yml"):
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML schema: %w", codegen.DecodeError(schemaPath, data, err))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported schema format: must be .json, .yaml, or .yml")
+	}
+	return stringKeys(document).(map[string]any), nil
+}
+
+// bundleDefinitions returns the definitions of a document that bundled schemas are added to,
+// and the ref prefix pointing at them: components.schemas for OpenAPI, definitions for
+// Swagger 2.0 and draft-07 or older schemas, $defs for newer schemas
+func bundleDefinitions(document map[string]any) (map[string]any, string) {
+	prefix := "#/definitions/"
+	switch dialect, _ := document["$schema"].(string); {
+	case document["openapi"] != nil || document["components"] != nil:
+		prefix = "#/components/schemas/"
+	case document["swagger"] != nil || document["definitions"] != nil:
+	case document["$defs"] != nil || strings.Contains(dialect, "2019-09") || strings.Contains(dialect, "2020-12"):
+		prefix = "#/$defs/"
+	}
+
+	target, err := resolvePointer(document, strings.TrimSuffix(strings.TrimPrefix(prefix, "#"), "/"))
+	if definitions, ok := target.(map[string]any); err == nil && ok {
+		return definitions, prefix
+	}
+	return map[string]any{}, prefix
+}
+
+// setPointer sets the member a slash-separated path of object keys addresses, creating the
+// objects on the way
+func setPointer(document map[string]any, path string, value any) {
+	keys := strings.Split(path, "/")
+	current := document
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// isSchemaTarget reports whether an absolute ref addresses a schema: a member of a schema
+// container, or a whole document that looks like a schema
+func (r *remoteRefResolver) isSchemaTarget(ref string) (bool, error) {
+	documentURL, fragment, _ := strings.Cut(ref, "#")
+	if fragment != "" && fragment != "/" {
+		for _, container := range schemaContainers {
+			if index := strings.LastIndex(fragment, container); index >= 0 && !strings.Contains(fragment[index+len(container):], "/") {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	document, err := r.document(documentURL)
+	if err != nil {
+		return false, err
+	}
+	for _, keyword := range schemaKeywords {
+		if _, ok := document[keyword]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// embed replaces a ref by a copy of its target, keeping the members set next to the ref, and
+// resolves the refs of the copy against the target's document
+func (r *remoteRefResolver) embed(node map[string]any, ref string, definitions map[string]any) error {
+	documentURL, fragment, _ := strings.Cut(ref, "#")
+	if r.embedding[ref] {
+		return fmt.Errorf("circular $ref %s", ref)
+	}
+
+	document, err := r.document(documentURL)
+	if err != nil {
+		return err
+	}
+	target, err := resolvePointer(document, fragment)
+	if err != nil {
+		return fmt.Errorf("failed to resolve $ref %s: %w", ref, err)
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		return fmt.Errorf("$ref %s does not address an object", ref)
+	}
+
+	delete(node, "$ref")
+	for key, value := range copyValue(object).(map[string]any) {
+		if _, set := node[key]; !set {
+			node[key] = value
+		}
+	}
+
+	r.embedding[ref] = true
+	defer delete(r.embedding, ref)
+	return r.walk(node, documentURL, definitions)
+}
+
+// copyValue returns a deep copy of a decoded JSON value
+func copyValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, child := range value {
+			copied[key] = copyValue(child)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, child := range value {
+			copied[i] = copyValue(child)
+		}
+		return copied
+	}
+	return value
+}
+
+// stringKeys converts the maps YAML decodes with non-string keys (such as the status codes
+// of OpenAPI responses) into maps with string keys
+func stringKeys(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			value[key] = stringKeys(child)
+		}
+		return value
+	case map[any]any:
+		converted := make(map[string]any, len(value))
+		for key, child := range value {
+			converted[fmt.Sprint(key)] = stringKeys(child)
+		}
+		return converted
+	case []any:
+		for i, child := range value {
+			value[i] = stringKeys(child)
+		}
+		return value
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package jrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// constraintKeywords are the keywords summarized in field comments, in display order
+var constraintKeywords = []string{
+	"minLength", "maxLength", "pattern", "format",
+	"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf",
+	"minItems", "maxItems", "uniqueItems",
+}
+
+// defaultCommentWidth is the line width field comments are wrapped to by default
+const defaultCommentWidth = 80
+
+// fieldComment returns the doc comment lines written above a struct field: the property
+// description wrapped to options.CommentWidth, allowed enum values, a constraint summary and
+// examples (when comments are enabled) and the vendor extensions (when extension comments are
+// enabled), followed by a deprecation paragraph for deprecated properties
+func fieldComment(propMap map[string]any, definitions map[string]any, options *GeneratorOptions) string {
+	var lines []string
+
+	if options.IncludeComments {
+		if description, ok := propMap["description"].(string); ok && strings.TrimSpace(description) != "" {
+			for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+				lines = append(lines, wrapCommentLine(strings.TrimSpace(line), options.CommentWidth)...)
+			}
+		}
+
+		var details []string
+		schema := constraintSchema(propMap, definitions)
+
+		if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+			details = append(details, "Allowed values: "+renderValues(enum)+".")
+		}
+
+		var constraints []string
+		for _, keyword := range constraintKeywords {
+			if value, ok := schema[keyword]; ok {
+				if keyword == "format" {
+					if _, isString := value.(string); !isString {
+						continue
+					}
+				}
+				constraints = append(constraints, keyword+": "+renderValues([]any{value}))
+			}
+		}
+		if len(constraints) > 0 {
+			details = append(details, "Constraints: "+strings.Join(constraints, ", ")+".")
+		}
+
+		if examples := schemaExamples(propMap, schema); len(examples) > 0 {
+			details = append(details, "Examples: "+renderValues(examples)+".")
+		}
+
+		if len(lines) > 0 && len(details) > 0 {
+			lines = append(lines, "")
+		}
+		for _, detail := range details {
+			lines = append(lines, wrapCommentLine(detail, options.CommentWidth)...)
+		}
+	}
+
+	if options.ExtensionComments {
+		if extensions := ExtensionComments(VendorExtensions(propMap)); len(extensions) > 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, extensions...)
+		}
+	}
+
+	if isDeprecated(propMap) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, deprecationNotice)
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		if line == "" {
+			b.WriteString("\t//\n")
+			continue
+		}
+		b.WriteString("\t// " + line + "\n")
+	}
+	return b.String()
+}
+
+// schemaExamples returns the examples of a property (JSON Schema `examples` or OpenAPI
+// `example`), falling back to those of the schema holding its constraints
+func schemaExamples(schemas ...map[string]any) []any {
+	for _, schema := range schemas {
+		if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+			return examples
+		}
+		if example, ok := schema["example"]; ok {
+			return []any{example}
+		}
+	}
+	return nil
+}
+
+// renderValues renders schema values as comma-separated compact JSON
+func renderValues(values []any) string {
+	rendered := make([]string, 0, len(values))
+	for _, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(value))
+		}
+		rendered = append(rendered, string(encoded))
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// wrapCommentLine splits a line of comment text into lines that fit within width, counting
+// the "// " prefix. Words longer than the width are kept whole; a width below 0 disables
+// wrapping and 0 selects the default width.
+func wrapCommentLine(text string, width int) []string {
+	if width == 0 {
+		width = defaultCommentWidth
+	}
+	if width < 0 || text == "" {
+		return []string{text}
+	}
+
+	var lines []string
+	var current strings.Builder
+	for _, word := range strings.Fields(text) {
+		if current.Len() > 0 && len("// ")+current.Len()+1+len(word) > width {
+			lines = append(lines, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString(" ")
+		}
+		current.WriteString(word)
+	}
+	return append(lines, current.String())
+}
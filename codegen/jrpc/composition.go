@@ -0,0 +1,175 @@
+package jrpc
+
+// mergedAllOf is the result of flattening an allOf composition into a single struct
+type mergedAllOf struct {
+	embedded   []string
+	properties map[string]any
+	order      []any // Property names in merge order
+	ordered    bool  // Whether a merged schema recorded its property order
+	required   []any
+	aliasOf    string
+}
+
+// mergeAllOf flattens the allOf members of a definition. Members that $ref a definition
+// generated as a struct become embedded structs; inline members (and the definition's own
+// properties) are merged into a single property set with their required lists combined.
+// It returns false when a member cannot be represented structurally.
+func mergeAllOf(defMap map[string]any, definitions map[string]any) (mergedAllOf, bool) {
+	return mergeAllOfVisiting(defMap, definitions, map[string]bool{})
+}
+
+// mergeAllOfVisiting implements mergeAllOf, tracking the definitions being merged so that
+// allOf cycles are rejected instead of recursing forever
+func mergeAllOfVisiting(defMap map[string]any, definitions map[string]any, visiting map[string]bool) (mergedAllOf, bool) {
+	merged := mergedAllOf{properties: make(map[string]any)}
+	requiredSeen := make(map[string]bool)
+	var refAliases []string
+
+	var merge func(schema map[string]any) bool
+	merge = func(schema map[string]any) bool {
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			if _, recorded := schema[propertyOrderKey]; recorded {
+				merged.ordered = true
+			}
+			for _, propName := range propertyNames(schema, properties) {
+				if _, exists := merged.properties[propName]; !exists {
+					merged.order = append(merged.order, propName)
+				}
+				merged.properties[propName] = properties[propName]
+			}
+		}
+
+		if required, ok := schema["required"].([]any); ok {
+			for _, field := range required {
+				if fieldName, ok := field.(string); ok && !requiredSeen[fieldName] {
+					requiredSeen[fieldName] = true
+					merged.required = append(merged.required, fieldName)
+				}
+			}
+		}
+
+		allOf, ok := schema["allOf"].([]any)
+		if !ok {
+			return true
+		}
+
+		for _, member := range allOf {
+			memberMap, ok := member.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if ref, ok := memberMap["$ref"].(string); ok {
+				refType := refName(ref)
+				if visiting[refType] {
+					return false
+				}
+
+				refDef, _ := definitions[refType].(map[string]any)
+				visiting[refType] = true
+				isStruct := refDef != nil && isStructDefinitionVisiting(refDef, definitions, visiting)
+				delete(visiting, refType)
+
+				if _, hasOverflow := refDef["additionalProperties"].(map[string]any); isStruct && (hasOverflow || hasConstFields(refDef, definitions)) {
+					// Embedding would promote the member's custom marshalers to the
+					// composed type, so its fields are merged instead
+					if !merge(refDef) {
+						return false
+					}
+				} else if isStruct {
+					merged.embedded = append(merged.embedded, refType)
+				} else {
+					refAliases = append(refAliases, refType)
+				}
+				continue
+			}
+
+			for _, key := range []string{"oneOf", "anyOf"} {
+				if _, ok := memberMap[key]; ok {
+					return false
+				}
+			}
+
+			if !merge(memberMap) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !merge(defMap) {
+		return merged, false
+	}
+
+	if len(refAliases) > 0 {
+		if len(refAliases) == 1 && len(merged.embedded) == 0 && len(merged.properties) == 0 {
+			merged.aliasOf = refAliases[0]
+			return merged, true
+		}
+		return merged, false
+	}
+
+	return merged, true
+}
+
+// isStructDefinition reports whether a definition is generated as a Go struct,
+// which is required for it to be embedded by an allOf composition
+func isStructDefinition(defMap map[string]any, definitions map[string]any) bool {
+	return isStructDefinitionVisiting(defMap, definitions, map[string]bool{})
+}
+
+// isStructDefinitionVisiting implements isStructDefinition while tracking allOf cycles
+func isStructDefinitionVisiting(defMap map[string]any, definitions map[string]any, visiting map[string]bool) bool {
+	if enum, ok := defMap["enum"].([]any); ok && len(enum) > 0 {
+		return false
+	}
+
+	if isConstDefinition(defMap) || isOverriddenDefinition(defMap) || isTupleSchema(defMap) {
+		return false
+	}
+
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, ok := mergeAllOfVisiting(defMap, definitions, visiting)
+		return ok && merged.aliasOf == ""
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if members, ok := defMap[key].([]any); ok && !isConstraintOnly(members) {
+			return false
+		}
+	}
+
+	if _, hasProperties := defMap["properties"]; hasProperties {
+		return true
+	}
+
+	return schemaType(defMap) == ""
+}
+
+// collectProperties returns the properties declared directly on a definition together with
+// the properties of its inline allOf members
+func collectProperties(defMap map[string]any) map[string]any {
+	properties := make(map[string]any)
+
+	if props, ok := defMap["properties"].(map[string]any); ok {
+		for propName, propDef := range props {
+			properties[propName] = propDef
+		}
+	}
+
+	if allOf, ok := defMap["allOf"].([]any); ok {
+		for _, member := range allOf {
+			if memberMap, ok := member.(map[string]any); ok {
+				if _, isRef := memberMap["$ref"]; isRef {
+					continue
+				}
+				for propName, propDef := range collectProperties(memberMap) {
+					properties[propName] = propDef
+				}
+			}
+		}
+	}
+
+	return properties
+}
@@ -0,0 +1,149 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// constField describes a struct field whose value is fixed by a `const` keyword
+type constField struct {
+	fieldName string // Go field name
+	constName string // Go constant assigned to the field when marshaling
+}
+
+// constLiteral returns the Go type and literal for a const value, or false when the value
+// cannot be expressed as a Go constant (null, objects and arrays)
+func constLiteral(value any) (string, string, bool) {
+	switch v := value.(type) {
+	case string:
+		return "string", strconv.Quote(v), true
+	case bool:
+		return "bool", strconv.FormatBool(v), true
+	case int:
+		return "int", strconv.Itoa(v), true
+	case float64:
+		if v == float64(int64(v)) {
+			return "int", strconv.FormatInt(int64(v), 10), true
+		}
+		return "float64", strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return "", "", false
+}
+
+// isConstDefinition reports whether a definition only describes a constant value
+func isConstDefinition(defMap map[string]any) bool {
+	if _, hasProperties := defMap["properties"]; hasProperties {
+		return false
+	}
+	_, _, ok := constLiteral(defMap["const"])
+	return ok
+}
+
+// constPropertyName returns the constant that holds the value of a const property: a new
+// constant derived from the struct and field names for inline consts, or the constant of a
+// referenced const definition
+func constPropertyName(typeName string, fieldName string, propMap map[string]any, definitions map[string]any) (string, bool) {
+	if _, _, ok := constLiteral(propMap["const"]); ok {
+		return typeName + fieldName, true
+	}
+
+	if ref, ok := propMap["$ref"].(string); ok {
+		if target, ok := definitions[refName(ref)].(map[string]any); ok && isConstDefinition(target) {
+			return refName(ref) + "Value", true
+		}
+	}
+
+	return "", false
+}
+
+// structProperties returns the properties a struct definition is generated with, taking
+// allOf merging into account
+func structProperties(defMap map[string]any, definitions map[string]any) map[string]any {
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, ok := mergeAllOf(defMap, definitions)
+		if !ok {
+			return nil
+		}
+		return merged.properties
+	}
+
+	properties, _ := defMap["properties"].(map[string]any)
+	return properties
+}
+
+// hasConstFields reports whether a struct definition has fields fixed by const keywords,
+// which are set by a generated MarshalJSON method
+func hasConstFields(defMap map[string]any, definitions map[string]any) bool {
+	for _, propDef := range structProperties(defMap, definitions) {
+		if propMap, ok := propDef.(map[string]any); ok {
+			if _, ok := constPropertyName("", "", propMap, definitions); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsConstStruct reports whether any struct definition has const fields
+func containsConstStruct(definitions map[string]any) bool {
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok {
+			if isStructDefinition(defMap, definitions) && hasConstFields(defMap, definitions) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateConstType generates a defined type and its only allowed value for a const definition
+func generateConstType(out *bytes.Buffer, typeName string, defMap map[string]any) error {
+	goType, literal, _ := constLiteral(defMap["const"])
+
+	typeDecl := fmt.Sprintf("type %s %s\n\n// %sValue is the only value allowed for %s\nconst %sValue %s = %s\n\n",
+		typeName, goType, typeName, typeName, typeName, typeName, literal)
+	_, err := out.WriteString(typeDecl)
+	return err
+}
+
+// generateConstDeclarations writes the constants holding the values of inline const properties
+func generateConstDeclarations(out *bytes.Buffer, typeName string, declarations []string) error {
+	if len(declarations) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s constant field values\nconst (\n", typeName)
+	for _, declaration := range declarations {
+		b.WriteString("\t" + declaration + "\n")
+	}
+	b.WriteString(")\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateConstMarshaler writes a MarshalJSON method that sets every const field before encoding
+func generateConstMarshaler(out *bytes.Buffer, typeName string, consts []constField) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// MarshalJSON encodes %s with its constant fields set\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) MarshalJSON() ([]byte, error) {\n", typeName)
+	fmt.Fprintf(&b, "\ttype alias %s\n", typeName)
+	b.WriteString(constAssignments(consts))
+	b.WriteString("\treturn json.Marshal(alias(x))\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// constAssignments renders the statements that set const fields on the receiver x
+func constAssignments(consts []constField) string {
+	var b strings.Builder
+	for _, field := range consts {
+		fmt.Fprintf(&b, "\tx.%s = %s\n", field.fieldName, field.constName)
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// constructorParam is a required struct field passed to a generated constructor
+type constructorParam struct {
+	fieldName string // Go field name
+	fieldType string // Go type of the field and parameter
+}
+
+// parameterName converts a Go field name into a parameter name, lowercasing a leading
+// acronym as a whole (ID → id, HTTPServer → httpServer) and avoiding Go keywords and the
+// constructor's local variable
+func parameterName(fieldName string) string {
+	runes := []rune(fieldName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLetter(runes[upper]) {
+		upper--
+	}
+	if upper == 0 {
+		upper = 1
+	}
+	name := strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+
+	if token.IsKeyword(name) || name == "x" {
+		name += "Value"
+	}
+	return name
+}
+
+// generateConstructor writes a NewX function that takes the embedded types and required
+// fields of a struct as arguments, sets its const fields and applies its defaults. Optional
+// fields are left unset.
+func generateConstructor(out *bytes.Buffer, typeName string, params []constructorParam, consts []constField, withDefaults bool) error {
+	var b strings.Builder
+
+	args := make([]string, len(params))
+	names := make(map[string]bool, len(params))
+	paramNames := make([]string, len(params))
+	for i, param := range params {
+		name := parameterName(param.fieldName)
+		for suffix := 2; names[name]; suffix++ {
+			name = fmt.Sprintf("%s%d", parameterName(param.fieldName), suffix)
+		}
+		names[name] = true
+		paramNames[i] = name
+		args[i] = name + " " + param.fieldType
+	}
+
+	if len(params) > 0 {
+		fmt.Fprintf(&b, "// New%s returns a %s with its required fields set\n", typeName, typeName)
+	} else {
+		fmt.Fprintf(&b, "// New%s returns a new %s\n", typeName, typeName)
+	}
+	fmt.Fprintf(&b, "func New%s(%s) *%s {\n", typeName, strings.Join(args, ", "), typeName)
+
+	literal := typeName + "{"
+	if len(params) > 0 {
+		literal += "\n"
+		for i, param := range params {
+			literal += fmt.Sprintf("\t\t%s: %s,\n", param.fieldName, paramNames[i])
+		}
+		literal += "\t"
+	}
+	literal += "}"
+
+	if len(consts) == 0 && !withDefaults {
+		fmt.Fprintf(&b, "\treturn &%s\n}\n\n", literal)
+	} else {
+		fmt.Fprintf(&b, "\tx := &%s\n", literal)
+		b.WriteString(constAssignments(consts))
+		if withDefaults {
+			b.WriteString("\tx.ApplyDefaults()\n")
+		}
+		b.WriteString("\treturn x\n}\n\n")
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
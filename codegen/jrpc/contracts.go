@@ -0,0 +1,387 @@
+package jrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// contractFormats are the string formats the contract tests check the encoded properties of.
+// The other formats generated as time.Time are left out, since time.Time encodes them as
+// RFC 3339 timestamps.
+var contractFormats = map[string]bool{
+	"date-time": true,
+	"email":     true,
+	"uuid":      true,
+	"uri":       true,
+	"ipv4":      true,
+	"ipv6":      true,
+}
+
+// contractCase holds what the contract test of a struct model checks
+type contractCase struct {
+	model    string
+	samples  []string            // Encoded examples of the schema, followed by a generated value
+	required []string            // Properties the encoded model must have
+	enums    map[string][]string // Encoded values allowed, by property
+	formats  map[string]string   // String formats, by property
+}
+
+// generateContractTests writes to options.ContractTests a _test.go file with TestContract,
+// which decodes the examples of every struct model's schema (and a value generated from it),
+// checks that encoding and decoding them again gives the same JSON, and that the encoding has
+// the required properties, enum values and string formats of the schema. The samples of the
+// oneOf and anyOf unions are only checked for their round trip, the variants being checked
+// as models of their own. For OpenRPC documents, TestMethodExamples checks the example
+// pairings of the methods the same way.
+func generateContractTests(templates *template.Template, schemaName string, data []byte, definitions map[string]any, structs []string, unions []string, methods []rpcMethod, options *GeneratorOptions) error {
+	out := new(bytes.Buffer)
+	imports := newImportManager()
+	imports.add("bytes", "encoding/json", "fmt", "net/mail", "net/netip", "net/url", "regexp", "slices", "testing", "time")
+	if err := executeTemplate(out, templates, headerTemplate, headerData(schemaName, data, imports.render(), options)); err != nil {
+		return err
+	}
+
+	out.WriteString(contractTestHelpers)
+	out.WriteString("// contractCases are the models checked by TestContract\n")
+	out.WriteString("var contractCases = []contractCase{\n")
+	for _, typeName := range structs {
+		defMap, _ := definitions[typeName].(map[string]any)
+		c, err := newContractCase(typeName, defMap, definitions)
+		if err != nil {
+			return err
+		}
+		writeContractCase(out, c)
+	}
+	for _, typeName := range unions {
+		defMap, _ := definitions[typeName].(map[string]any)
+		c, err := newUnionContractCase(typeName, defMap, definitions)
+		if err != nil {
+			return err
+		}
+		writeContractCase(out, c)
+	}
+	out.WriteString("}\n")
+	writeMethodExamples(out, methods, definitions)
+
+	source := out.Bytes()
+	if options.FormatOutput {
+		formatted, err := format.Source(source)
+		if err != nil {
+			return &codegen.FormatError{Name: "contract tests", Err: err}
+		}
+		source = formatted
+	}
+	if _, err := options.ContractTests.Write(source); err != nil {
+		return fmt.Errorf("failed to write contract tests: %w", err)
+	}
+	return nil
+}
+
+// newContractCase collects the samples, required properties, enums and formats of a struct
+// definition
+func newContractCase(typeName string, defMap map[string]any, definitions map[string]any) (contractCase, error) {
+	c, err := newUnionContractCase(typeName, defMap, definitions)
+	if err != nil {
+		return c, err
+	}
+
+	properties := structProperties(defMap, definitions)
+	required, _ := defMap["required"].([]any)
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, _ := mergeAllOf(defMap, definitions)
+		required = merged.required
+	}
+	for _, name := range required {
+		if name, ok := name.(string); ok && properties[name] != nil && !slices.Contains(c.required, name) {
+			c.required = append(c.required, name)
+		}
+	}
+	sort.Strings(c.required)
+
+	for name, property := range properties {
+		propMap, _ := property.(map[string]any)
+		if ref, ok := propMap["$ref"].(string); ok {
+			if target, ok := definitions[refName(ref)].(map[string]any); ok {
+				propMap = target
+			}
+		}
+		if enum, ok := propMap["enum"].([]any); ok {
+			for _, value := range enum {
+				if value == nil {
+					continue
+				}
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					return c, fmt.Errorf("failed to encode an enum value of %s.%s: %w", typeName, name, err)
+				}
+				c.enums[name] = append(c.enums[name], string(encoded))
+			}
+		}
+		if format, _ := propMap["format"].(string); contractFormats[format] && schemaType(propMap) == "string" {
+			c.formats[name] = format
+		}
+	}
+	return c, nil
+}
+
+// newUnionContractCase collects the samples of a definition: its examples, followed by a value
+// generated from it. They are all a union is checked with.
+func newUnionContractCase(typeName string, defMap map[string]any, definitions map[string]any) (contractCase, error) {
+	c := contractCase{model: typeName, enums: map[string][]string{}, formats: map[string]string{}}
+
+	var samples []any
+	if example, ok := defMap["example"]; ok {
+		samples = append(samples, example)
+	}
+	if examples, ok := defMap["examples"].([]any); ok {
+		samples = append(samples, examples...)
+	}
+	// Sampling a reference to the definition leaves its recursive references out
+	samples = append(samples, SampleValue(map[string]any{"$ref": typeName}, definitions, SampleOptions{}))
+	for _, sample := range samples {
+		encoded, err := json.Marshal(sample)
+		if err != nil {
+			return c, fmt.Errorf("failed to encode the sample of %s: %w", typeName, err)
+		}
+		c.samples = append(c.samples, string(encoded))
+	}
+	return c, nil
+}
+
+// writeContractCase writes the contractCase literal of a model
+func writeContractCase(out *bytes.Buffer, c contractCase) {
+	fmt.Fprintf(out, "\t{\n\t\tmodel:  %q,\n", c.model)
+	fmt.Fprintf(out, "\t\tdecode: decodeContract[%s],\n", c.model)
+	out.WriteString("\t\tsamples: []string{\n")
+	for _, sample := range c.samples {
+		fmt.Fprintf(out, "\t\t\t%s,\n", strconv.Quote(sample))
+	}
+	out.WriteString("\t\t},\n")
+	if len(c.required) > 0 {
+		quoted := make([]string, len(c.required))
+		for i, name := range c.required {
+			quoted[i] = strconv.Quote(name)
+		}
+		fmt.Fprintf(out, "\t\trequired: []string{%s},\n", strings.Join(quoted, ", "))
+	}
+	if len(c.enums) > 0 {
+		out.WriteString("\t\tenums: map[string][]string{\n")
+		for _, name := range slices.Sorted(maps.Keys(c.enums)) {
+			quoted := make([]string, len(c.enums[name]))
+			for i, value := range c.enums[name] {
+				quoted[i] = strconv.Quote(value)
+			}
+			fmt.Fprintf(out, "\t\t\t%q: {%s},\n", name, strings.Join(quoted, ", "))
+		}
+		out.WriteString("\t\t},\n")
+	}
+	if len(c.formats) > 0 {
+		out.WriteString("\t\tformats: map[string]string{\n")
+		for _, name := range slices.Sorted(maps.Keys(c.formats)) {
+			fmt.Fprintf(out, "\t\t\t%q: %q,\n", name, c.formats[name])
+		}
+		out.WriteString("\t\t},\n")
+	}
+	out.WriteString("\t},\n")
+}
+
+// writeMethodExamples writes TestMethodExamples and its table of the example pairings of the
+// generated methods, if any
+func writeMethodExamples(out *bytes.Buffer, methods []rpcMethod, definitions map[string]any) {
+	var cases strings.Builder
+	for _, method := range methods {
+		if !method.generated(definitions) {
+			continue
+		}
+		for _, example := range method.examples {
+			if method.params == "" && example.result == "" {
+				continue
+			}
+			fmt.Fprintf(&cases, "\t{\n\t\tmethod: Method%s,\n\t\tname:   %q,\n", method.goName, example.name)
+			if method.params != "" {
+				fmt.Fprintf(&cases, "\t\tparams:       %s,\n", strconv.Quote(example.params))
+				fmt.Fprintf(&cases, "\t\tdecodeParams: decodeContract[%s],\n", method.params)
+			}
+			if method.result != "" && example.result != "" {
+				fmt.Fprintf(&cases, "\t\tresult:       %s,\n", strconv.Quote(example.result))
+				fmt.Fprintf(&cases, "\t\tdecodeResult: decodeContract[%s],\n", method.result)
+			}
+			cases.WriteString("\t},\n")
+		}
+	}
+	if cases.Len() == 0 {
+		return
+	}
+
+	out.WriteString(methodExampleHelpers)
+	out.WriteString("// methodExamples are the example pairings checked by TestMethodExamples\n")
+	out.WriteString("var methodExamples = []methodExample{\n" + cases.String() + "}\n")
+}
+
+// methodExampleHelpers is the test function of the example pairings of OpenRPC methods
+const methodExampleHelpers = `
+// methodExample is an example pairing of a method TestMethodExamples checks
+type methodExample struct {
+	method       string
+	name         string
+	params       string // Encoded by name, empty when the method takes no parameters
+	decodeParams func(data []byte) (any, error)
+	result       string // Empty when the example has no result
+	decodeResult func(data []byte) (any, error)
+}
+
+// TestMethodExamples decodes the params and result of the example pairings of every method
+// into their types, checks that they are valid and that encoding and decoding them again gives
+// the same JSON
+func TestMethodExamples(t *testing.T) {
+	for _, example := range methodExamples {
+		t.Run(example.method+"/"+example.name, func(t *testing.T) {
+			if example.decodeParams != nil {
+				checkMethodExample(t, "params", example.params, example.decodeParams)
+			}
+			if example.decodeResult != nil {
+				checkMethodExample(t, "result", example.result, example.decodeResult)
+			}
+		})
+	}
+}
+
+// checkMethodExample decodes the sample of the params or result of a method, validates it
+// when its type has a Validate method, and checks its round trip
+func checkMethodExample(t *testing.T, part string, sample string, decode func(data []byte) (any, error)) {
+	t.Helper()
+	value, err := decode([]byte(sample))
+	if err != nil {
+		t.Fatalf("decoding the %s %s: %v", part, sample, err)
+	}
+	if validator, ok := value.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			t.Errorf("invalid %s %s: %v", part, sample, err)
+		}
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("encoding the %s: %v", part, err)
+	}
+	again, err := decode(encoded)
+	if err != nil {
+		t.Fatalf("decoding the %s %s: %v", part, encoded, err)
+	}
+	reencoded, err := json.Marshal(again)
+	if err != nil {
+		t.Fatalf("encoding the %s again: %v", part, err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("round trip changed the %s %s into %s", part, encoded, reencoded)
+	}
+}
+`
+
+// contractTestHelpers is the test function and helpers of the contract tests
+const contractTestHelpers = `// contractCase is a model TestContract checks against its schema
+type contractCase struct {
+	model    string
+	decode   func(data []byte) (any, error)
+	samples  []string            // Encoded examples of the schema, followed by a generated value
+	required []string            // Properties the encoded model must have
+	enums    map[string][]string // Encoded values allowed, by property
+	formats  map[string]string   // String formats, by property
+}
+
+// TestContract decodes the samples of every model, checks that encoding and decoding them
+// again gives the same JSON, and that the encoding has the required properties, enum values
+// and string formats of the schema
+func TestContract(t *testing.T) {
+	for _, c := range contractCases {
+		for i, sample := range c.samples {
+			t.Run(fmt.Sprintf("%s/%d", c.model, i), func(t *testing.T) {
+				value, err := c.decode([]byte(sample))
+				if err != nil {
+					t.Fatalf("decoding %s: %v", sample, err)
+				}
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					t.Fatalf("encoding: %v", err)
+				}
+				again, err := c.decode(encoded)
+				if err != nil {
+					t.Fatalf("decoding %s: %v", encoded, err)
+				}
+				reencoded, err := json.Marshal(again)
+				if err != nil {
+					t.Fatalf("encoding again: %v", err)
+				}
+				if !bytes.Equal(encoded, reencoded) {
+					t.Errorf("round trip changed %s into %s", encoded, reencoded)
+				}
+				if len(c.required) == 0 && len(c.enums) == 0 && len(c.formats) == 0 {
+					return
+				}
+
+				var properties map[string]json.RawMessage
+				if err := json.Unmarshal(encoded, &properties); err != nil {
+					t.Fatalf("decoding the properties of %s: %v", encoded, err)
+				}
+				for _, name := range c.required {
+					if _, ok := properties[name]; !ok {
+						t.Errorf("required property %q is missing from %s", name, encoded)
+					}
+				}
+				for name, values := range c.enums {
+					if value, ok := properties[name]; ok && string(value) != "null" && !slices.Contains(values, string(value)) {
+						t.Errorf("property %q is %s, not one of %v", name, value, values)
+					}
+				}
+				for name, format := range c.formats {
+					var text string
+					if value, ok := properties[name]; ok && json.Unmarshal(value, &text) == nil && !validContractFormat(format, text) {
+						t.Errorf("property %q is %q, not a valid %s", name, text, format)
+					}
+				}
+			})
+		}
+	}
+}
+
+// decodeContract decodes data into a new T
+func decodeContract[T any](data []byte) (any, error) {
+	x := new(T)
+	err := json.Unmarshal(data, x)
+	return x, err
+}
+
+// contractUUID matches the textual representation of UUIDs
+var contractUUID = regexp.MustCompile(` + "`" + `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$` + "`" + `)
+
+// validContractFormat reports whether text is a valid value of a string format
+func validContractFormat(format string, text string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, text)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(text)
+		return err == nil
+	case "uuid":
+		return contractUUID.MatchString(text)
+	case "uri":
+		u, err := url.Parse(text)
+		return err == nil && u.IsAbs()
+	case "ipv4", "ipv6":
+		addr, err := netip.ParseAddr(text)
+		return err == nil && addr.Is4() == (format == "ipv4")
+	}
+	return true
+}
+
+`
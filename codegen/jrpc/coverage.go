@@ -0,0 +1,157 @@
+package jrpc
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// subschemaKeywords are the keywords whose value is a schema, or an array of schemas
+var subschemaKeywords = []string{
+	"items", "additionalItems", "additionalProperties", "not", "if", "then", "else", "contains",
+	"propertyNames", "unevaluatedProperties", "unevaluatedItems",
+	"allOf", "anyOf", "oneOf", "prefixItems",
+}
+
+// schemaMapKeywords are the keywords whose value maps names to schemas
+var schemaMapKeywords = []string{"properties", "patternProperties", "dependentSchemas", "definitions", "$defs"}
+
+// KeywordUsage is the number of schemas of a document using a keyword
+type KeywordUsage struct {
+	Keyword   string `json:"keyword"`
+	Count     int    `json:"count"`
+	Reflected bool   `json:"reflected"` // Whether the generated Go types reflect the keyword (see RuleUnsupportedKeyword)
+}
+
+// Coverage is what the generated Go types make of the schemas of a document: the keywords
+// the schemas use, and the schemas generated as named types
+type Coverage struct {
+	Keywords []KeywordUsage    // Keywords of the schemas, in alphabetical order
+	Types    map[string]string // JSON pointers of the schemas generated as named types, by Go type name
+}
+
+// SchemaFileCoverage returns the coverage of the definitions of a JSON Schema, OpenRPC or
+// OpenAPI file and of the schemas nested in them. The types of the definitions are named after
+// them, or their x-go-name, and those of inline objects after their parent, as the generator
+// names them unless the name is taken.
+func SchemaFileCoverage(schemaPath string) (*Coverage, error) {
+	document, err := loadSchemaFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &Coverage{Types: make(map[string]string)}
+	counts := make(map[string]int)
+	for _, container := range definitionContainers {
+		target, err := resolvePointer(document, container)
+		definitions, ok := target.(map[string]any)
+		if err != nil || !ok {
+			continue
+		}
+		for _, name := range sortedNames(definitions) {
+			defMap, ok := definitions[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			typeName := GoIdentifier(name, nil)
+			if pinned, ok := goNameOverride(defMap); ok {
+				typeName = pinned
+			}
+			pointer := container + "/" + escapePointerToken(name)
+			if _, taken := coverage.Types[typeName]; !taken {
+				coverage.Types[typeName] = pointer
+			}
+			coverSchema(pointer, typeName, defMap, coverage.Types, counts)
+		}
+	}
+
+	for keyword, count := range counts {
+		coverage.Keywords = append(coverage.Keywords, KeywordUsage{
+			Keyword:   keyword,
+			Count:     count,
+			Reflected: !slices.Contains(unsupportedKeywords, keyword),
+		})
+	}
+	sort.Slice(coverage.Keywords, func(i, j int) bool {
+		return coverage.Keywords[i].Keyword < coverage.Keywords[j].Keyword
+	})
+	return coverage, nil
+}
+
+// coverSchema counts the keywords of a schema generated as, or as part of, the type typeName,
+// and of the schemas nested in it, recording the inline objects among them in types
+func coverSchema(pointer string, typeName string, schema map[string]any, types map[string]string, counts map[string]int) {
+	for keyword := range schema {
+		counts[keyword]++
+	}
+
+	for _, keyword := range schemaMapKeywords {
+		members, _ := schema[keyword].(map[string]any)
+		for _, name := range sortedNames(members) {
+			member, ok := members[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			memberPointer := pointer + "/" + keyword + "/" + escapePointerToken(name)
+			memberName := typeName + GoIdentifier(name, nil)
+			if pinned, ok := goNameOverride(member); ok {
+				memberName = pinned
+			}
+			if keyword == "properties" {
+				coverNested(memberPointer, memberName, member, types, counts)
+			} else {
+				coverSchema(memberPointer, memberName, member, types, counts)
+			}
+		}
+	}
+
+	for _, keyword := range subschemaKeywords {
+		switch value := schema[keyword].(type) {
+		case map[string]any:
+			switch keyword {
+			case "items":
+				coverNested(pointer+"/items", typeName+"Item", value, types, counts)
+			case "additionalProperties":
+				coverNested(pointer+"/additionalProperties", typeName+"Value", value, types, counts)
+			default:
+				coverSchema(pointer+"/"+keyword, typeName, value, types, counts)
+			}
+		case []any:
+			for i, member := range value {
+				if memberMap, ok := member.(map[string]any); ok {
+					coverSchema(fmt.Sprintf("%s/%s/%d", pointer, keyword, i), typeName, memberMap, types, counts)
+				}
+			}
+		}
+	}
+}
+
+// coverNested counts the keywords of a schema nested in another one, recording it as the type
+// name when it is an inline object
+func coverNested(pointer string, name string, schema map[string]any, types map[string]string, counts map[string]int) {
+	if isNestedObject(schema) {
+		if pinned, ok := goNameOverride(schema); ok {
+			name = pinned
+		}
+		if _, taken := types[name]; !taken {
+			types[name] = pointer
+		}
+	}
+	coverSchema(pointer, name, schema, types, counts)
+}
+
+// KeywordCoverage returns the share of the keyword uses of the schemas that the generated Go
+// types reflect, from 0 to 1, or 1 when the schemas use no keywords
+func (c *Coverage) KeywordCoverage() float64 {
+	var total, reflected int
+	for _, usage := range c.Keywords {
+		total += usage.Count
+		if usage.Reflected {
+			reflected += usage.Count
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(reflected) / float64(total)
+}
@@ -0,0 +1,209 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// scalarKind returns the JSON type of a schema whose Go type can hold a constant ("string",
+// "integer", "number" or "boolean"), following $refs, or an empty string for other schemas
+func scalarKind(schema map[string]any, definitions map[string]any, visiting map[string]bool) string {
+	if _, overridden := goTypeOverride(schema); overridden {
+		return ""
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		target, ok := definitions[name].(map[string]any)
+		if !ok || visiting[name] {
+			return ""
+		}
+		visiting[name] = true
+		return scalarKind(target, definitions, visiting)
+	}
+
+	kind := schemaType(schema)
+	if kind == "" {
+		if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+			switch enum[0].(type) {
+			case string:
+				kind = "string"
+			case bool:
+				kind = "boolean"
+			case float64, int:
+				kind = "number"
+			}
+		}
+	}
+
+	switch kind {
+	case "string":
+		if format, _ := schema["format"].(string); format != "" {
+			if _, ok := formatPackages[format]; ok || format == "byte" || format == "binary" {
+				return ""
+			}
+		}
+		return kind
+	case "integer", "number", "boolean":
+		return kind
+	}
+	return ""
+}
+
+// defaultLiteral returns the Go constant of a property's default value, or false when the
+// default cannot be assigned to the property's Go type as an untyped constant
+func defaultLiteral(propMap map[string]any, definitions map[string]any) (string, bool) {
+	valueType, literal, ok := constLiteral(propMap["default"])
+	if !ok {
+		return "", false
+	}
+
+	switch kind := scalarKind(propMap, definitions, map[string]bool{}); {
+	case kind == "string" && valueType == "string":
+	case kind == "boolean" && valueType == "bool":
+	case kind == "integer" && valueType == "int":
+	case kind == "number" && (valueType == "int" || valueType == "float64"):
+	default:
+		return "", false
+	}
+	return literal, true
+}
+
+// zeroValue returns the zero value literal compared against before applying a default
+func zeroValue(kind string) string {
+	if kind == "string" {
+		return `""`
+	}
+	return "0"
+}
+
+// defaultStatement renders the statement that applies the default of a field on the receiver
+// x: zero-valued fields get the default, nil pointer fields point at a copy of it
+func defaultStatement(fieldName string, fieldType string, propMap map[string]any, definitions map[string]any) (string, bool) {
+	literal, ok := defaultLiteral(propMap, definitions)
+	if !ok {
+		return "", false
+	}
+
+	if goType, isPointer := strings.CutPrefix(fieldType, "*"); isPointer {
+		value := literal
+		if goType != "string" && goType != "bool" {
+			value = fmt.Sprintf("%s(%s)", goType, literal)
+		}
+		return fmt.Sprintf("\tif x.%s == nil {\n\t\tvalue := %s\n\t\tx.%s = &value\n\t}\n", fieldName, value, fieldName), true
+	}
+
+	condition := fmt.Sprintf("x.%s == %s", fieldName, zeroValue(scalarKind(propMap, definitions, map[string]bool{})))
+	if scalarKind(propMap, definitions, map[string]bool{}) == "boolean" {
+		condition = "!x." + fieldName
+	}
+	return fmt.Sprintf("\tif %s {\n\t\tx.%s = %s\n\t}\n", condition, fieldName, literal), true
+}
+
+// nestedDefaultStatement renders the statement that applies the defaults of a struct field
+// (by value, through a pointer or as slice elements) whose type has an ApplyDefaults method
+func nestedDefaultStatement(fieldName string, fieldType string, propMap map[string]any, definitions map[string]any) (string, bool) {
+	target := propMap
+	if items, ok := propMap["items"].(map[string]any); ok && strings.HasPrefix(fieldType, "[]") {
+		target = items
+	}
+
+	ref, ok := target["$ref"].(string)
+	if !ok {
+		return "", false
+	}
+	if _, overridden := goTypeOverride(target); overridden {
+		return "", false
+	}
+	refDef, ok := definitions[refName(ref)].(map[string]any)
+	if !ok || !hasDefaults(refDef, definitions, map[string]bool{}) {
+		return "", false
+	}
+
+	switch {
+	case strings.HasPrefix(fieldType, "[]*"):
+		return fmt.Sprintf("\tfor _, item := range x.%s {\n\t\tif item != nil {\n\t\t\titem.ApplyDefaults()\n\t\t}\n\t}\n", fieldName), true
+	case strings.HasPrefix(fieldType, "[]"):
+		return fmt.Sprintf("\tfor i := range x.%s {\n\t\tx.%s[i].ApplyDefaults()\n\t}\n", fieldName, fieldName), true
+	case strings.HasPrefix(fieldType, "*"):
+		return fmt.Sprintf("\tif x.%s != nil {\n\t\tx.%s.ApplyDefaults()\n\t}\n", fieldName, fieldName), true
+	}
+	return fmt.Sprintf("\tx.%s.ApplyDefaults()\n", fieldName), true
+}
+
+// hasDefaults reports whether a struct definition gets an ApplyDefaults method: one of its
+// properties has a default that can be expressed as a Go constant, or one of its embedded or
+// nested struct types has defaults
+func hasDefaults(defMap map[string]any, definitions map[string]any, visiting map[string]bool) bool {
+	if !isStructDefinition(defMap, definitions) {
+		return false
+	}
+
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		if merged, ok := mergeAllOf(defMap, definitions); ok {
+			for _, embedded := range merged.embedded {
+				if embeddedDef, ok := definitions[embedded].(map[string]any); ok && !visiting[embedded] {
+					visiting[embedded] = true
+					if hasDefaults(embeddedDef, definitions, visiting) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	for _, propDef := range structProperties(defMap, definitions) {
+		propMap, ok := propDef.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, isConst := constPropertyName("", "", propMap, definitions); isConst {
+			continue
+		}
+		if _, ok := defaultLiteral(propMap, definitions); ok {
+			return true
+		}
+
+		target := propMap
+		if items, ok := propMap["items"].(map[string]any); ok {
+			target = items
+		}
+		if ref, ok := target["$ref"].(string); ok && !visiting[refName(ref)] {
+			visiting[refName(ref)] = true
+			if refDef, ok := definitions[refName(ref)].(map[string]any); ok && hasDefaults(refDef, definitions, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// generateApplyDefaults writes an ApplyDefaults method that fills zero-valued fields with
+// their schema defaults, after applying the defaults of embedded types. Properties with a
+// default are stored by value, so the method cannot tell an explicit zero value from a
+// missing one; overwritesZero adds this to its doc comment when one of statements sets such a
+// field.
+func generateApplyDefaults(out *bytes.Buffer, typeName string, embedded []string, definitions map[string]any, statements []string, overwritesZero bool) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// ApplyDefaults sets zero-valued fields of %s to their schema defaults", typeName)
+	if overwritesZero {
+		b.WriteString(". Fields with a\n// default are not pointers, so an explicit false, 0 or \"\" is replaced by the default\n// too: to keep those of decoded JSON, call it before decoding into the value.")
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "func (x *%s) ApplyDefaults() {\n", typeName)
+	for _, embeddedType := range embedded {
+		if embeddedDef, ok := definitions[embeddedType].(map[string]any); ok && hasDefaults(embeddedDef, definitions, map[string]bool{}) {
+			fmt.Fprintf(&b, "\tx.%s.ApplyDefaults()\n", embeddedType)
+		}
+	}
+	for _, statement := range statements {
+		b.WriteString(statement)
+	}
+	b.WriteString("}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,95 @@
+package jrpc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+// defaultsSchema has a struct with defaults stored by value and a struct whose only default
+// is that of an optional nested struct
+const defaultsSchema = `{
+	"definitions": {
+		"Config": {
+			"type": "object",
+			"properties": {
+				"enabled": {"type": "boolean", "default": true},
+				"retries": {"type": "integer", "default": 3},
+				"name": {"type": "string", "default": "main"}
+			}
+		},
+		"Service": {
+			"type": "object",
+			"properties": {"config": {"$ref": "#/definitions/Config"}}
+		}
+	}
+}`
+
+// defaultsTest checks the values ApplyDefaults gives to decoded JSON, depending on whether it
+// is called before or after decoding
+const defaultsTest = `package generated
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		before bool
+		want   Config
+	}{
+		{"missing after decoding", "{}", false, Config{Enabled: true, Retries: 3, Name: "main"}},
+		{"missing before decoding", "{}", true, Config{Enabled: true, Retries: 3, Name: "main"}},
+		{"zero values after decoding", ` + "`" + `{"enabled": false, "retries": 0, "name": ""}` + "`" + `, false, Config{Enabled: true, Retries: 3, Name: "main"}},
+		{"zero values before decoding", ` + "`" + `{"enabled": false, "retries": 0, "name": ""}` + "`" + `, true, Config{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config Config
+			if test.before {
+				config.ApplyDefaults()
+			}
+			if err := json.Unmarshal([]byte(test.json), &config); err != nil {
+				t.Fatal(err)
+			}
+			if !test.before {
+				config.ApplyDefaults()
+			}
+			if config != test.want {
+				t.Errorf("config = %+v, want %+v", config, test.want)
+			}
+		})
+	}
+}
+`
+
+func TestApplyDefaults(t *testing.T) {
+	var source bytes.Buffer
+	if err := GenerateTypesTo(&source, []byte(defaultsSchema), &GeneratorOptions{PackageName: "generated", GenerateDefaults: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		typeName       string
+		overwritesZero bool
+	}{
+		{"Config", true},
+		{"Service", false},
+	}
+	for _, test := range tests {
+		comment := "// ApplyDefaults sets zero-valued fields of " + test.typeName + " to their schema defaults. Fields with a\n// default are not pointers"
+		if got := strings.Contains(source.String(), comment); got != test.overwritesZero {
+			t.Errorf("%s: doc comment mentions the replaced zero values: %t, want %t", test.typeName, got, test.overwritesZero)
+		}
+	}
+
+	gentest.Run(t, map[string]string{
+		"types.go":      source.String(),
+		"types_test.go": defaultsTest,
+	}, "test", "./...")
+}
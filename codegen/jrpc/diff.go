@@ -0,0 +1,236 @@
+package jrpc
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Change kinds reported by DiffSchemaFiles, DiffDefinitions and DiffSchema
+const (
+	ChangeRemoved         = "removed"          // A definition, property, operation, parameter, media type or response was removed
+	ChangeAdded           = "added"            // A definition, property, operation, optional parameter, media type or response was added
+	ChangeTypeChanged     = "type-changed"     // The type or $ref of a schema changed
+	ChangeFormatChanged   = "format-changed"   // The format of a schema changed
+	ChangeEnumNarrowed    = "enum-narrowed"    // Values were removed from an enum, or an enum was added
+	ChangeEnumWidened     = "enum-widened"     // Values were added to an enum, or an enum was removed
+	ChangeRequiredAdded   = "required-added"   // A property, parameter or request body became required
+	ChangeRequiredRemoved = "required-removed" // A property, parameter or request body became optional
+)
+
+// Change is a difference between two versions of a schema or API document
+type Change struct {
+	Kind     string `json:"kind"`
+	Path     string `json:"path"` // JSON pointer to the changed element: in the old document for removals, in the new one otherwise
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// DiffSchemaFiles compares two versions of a JSON Schema file: their root schemas and their
+// definitions
+func DiffSchemaFiles(oldPath string, newPath string) ([]Change, error) {
+	oldDocument, err := loadSchemaFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newDocument, err := loadSchemaFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := DiffSchema("", oldDocument, newDocument)
+	_, prefix := bundleDefinitions(newDocument)
+	changes = append(changes, DiffDefinitions(strings.TrimPrefix(prefix, "#"), extractDefinitions(oldDocument), extractDefinitions(newDocument))...)
+	return changes, nil
+}
+
+// DiffDefinitions compares two versions of a set of definitions, whose JSON pointers start
+// with prefix (e.g. "/components/schemas/"). Removed definitions are breaking changes, added
+// ones are not; the definitions of both are compared with DiffSchema.
+func DiffDefinitions(prefix string, oldDefinitions map[string]any, newDefinitions map[string]any) []Change {
+	var changes []Change
+	for _, name := range sortedNames(oldDefinitions) {
+		pointer := prefix + escapePointerToken(name)
+		newDefinition, ok := newDefinitions[name]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeRemoved, Path: pointer, Breaking: true, Message: fmt.Sprintf("definition %s was removed", name)})
+			continue
+		}
+		oldMap, _ := oldDefinitions[name].(map[string]any)
+		newMap, _ := newDefinition.(map[string]any)
+		changes = append(changes, DiffSchema(pointer, oldMap, newMap)...)
+	}
+	for _, name := range sortedNames(newDefinitions) {
+		if _, ok := oldDefinitions[name]; !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Path: prefix + escapePointerToken(name), Message: fmt.Sprintf("definition %s was added", name)})
+		}
+	}
+	return changes
+}
+
+// DiffSchema compares two versions of a schema located at pointer. Changes of types, $refs
+// and formats, removed properties, narrowed enums and newly required properties are breaking,
+// since payloads valid before may no longer be, or the generated Go types change
+// incompatibly. Referenced definitions are not followed: DiffDefinitions compares them.
+func DiffSchema(pointer string, oldSchema map[string]any, newSchema map[string]any) []Change {
+	var changes []Change
+	diffSchema(pointer, oldSchema, newSchema, &changes)
+	return changes
+}
+
+// diffSchema implements DiffSchema, appending to changes
+func diffSchema(pointer string, oldSchema map[string]any, newSchema map[string]any, changes *[]Change) {
+	report := func(kind string, breaking bool, format string, args ...any) {
+		*changes = append(*changes, Change{Kind: kind, Path: pointer, Breaking: breaking, Message: fmt.Sprintf(format, args...)})
+	}
+
+	oldRef, _ := oldSchema["$ref"].(string)
+	newRef, _ := newSchema["$ref"].(string)
+	if oldRef != "" || newRef != "" {
+		if refName(oldRef) != refName(newRef) {
+			report(ChangeTypeChanged, true, "type changed from %s to %s", schemaLabel(oldSchema), schemaLabel(newSchema))
+		}
+		return
+	}
+
+	if oldTypes, newTypes := nonNullTypes(oldSchema), nonNullTypes(newSchema); !slices.Equal(oldTypes, newTypes) {
+		report(ChangeTypeChanged, true, "type changed from %s to %s", schemaLabel(oldSchema), schemaLabel(newSchema))
+		return
+	}
+
+	oldFormat, _ := oldSchema["format"].(string)
+	newFormat, _ := newSchema["format"].(string)
+	if oldFormat != newFormat {
+		report(ChangeFormatChanged, true, "format changed from %q to %q", oldFormat, newFormat)
+	}
+
+	oldEnum, hasOldEnum := oldSchema["enum"].([]any)
+	newEnum, hasNewEnum := newSchema["enum"].([]any)
+	switch {
+	case !hasOldEnum && hasNewEnum:
+		report(ChangeEnumNarrowed, true, "values were restricted to an enum")
+	case hasOldEnum && !hasNewEnum:
+		report(ChangeEnumWidened, false, "values are no longer restricted to an enum")
+	case hasOldEnum:
+		if removed := missingValues(oldEnum, newEnum); len(removed) > 0 {
+			report(ChangeEnumNarrowed, true, "enum values %s were removed", strings.Join(removed, ", "))
+		}
+		if added := missingValues(newEnum, oldEnum); len(added) > 0 {
+			report(ChangeEnumWidened, false, "enum values %s were added", strings.Join(added, ", "))
+		}
+	}
+
+	oldProperties, _ := oldSchema["properties"].(map[string]any)
+	newProperties, _ := newSchema["properties"].(map[string]any)
+	oldRequired, _ := oldSchema["required"].([]any)
+	newRequired, _ := newSchema["required"].([]any)
+	for _, name := range sortedNames(oldProperties) {
+		propertyPointer := pointer + "/properties/" + escapePointerToken(name)
+		newProperty, ok := newProperties[name]
+		if !ok {
+			*changes = append(*changes, Change{Kind: ChangeRemoved, Path: propertyPointer, Breaking: true, Message: fmt.Sprintf("property %s was removed", name)})
+			continue
+		}
+		oldMap, _ := oldProperties[name].(map[string]any)
+		newMap, _ := newProperty.(map[string]any)
+		diffSchema(propertyPointer, oldMap, newMap, changes)
+	}
+	for _, name := range sortedNames(newProperties) {
+		if _, ok := oldProperties[name]; !ok && !slices.Contains(newRequired, any(name)) {
+			*changes = append(*changes, Change{Kind: ChangeAdded, Path: pointer + "/properties/" + escapePointerToken(name), Message: fmt.Sprintf("optional property %s was added", name)})
+		}
+	}
+	for _, name := range newRequired {
+		if !slices.Contains(oldRequired, name) {
+			report(ChangeRequiredAdded, true, "property %v became required", name)
+		}
+	}
+	for _, name := range oldRequired {
+		if !slices.Contains(newRequired, name) {
+			report(ChangeRequiredRemoved, false, "property %v is no longer required", name)
+		}
+	}
+
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		oldMap, oldOK := oldSchema[keyword].(map[string]any)
+		newMap, newOK := newSchema[keyword].(map[string]any)
+		if oldOK && newOK {
+			diffSchema(pointer+"/"+keyword, oldMap, newMap, changes)
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		oldMembers, _ := oldSchema[keyword].([]any)
+		newMembers, _ := newSchema[keyword].([]any)
+		for i, oldMember := range oldMembers {
+			memberPointer := fmt.Sprintf("%s/%s/%d", pointer, keyword, i)
+			if i >= len(newMembers) {
+				*changes = append(*changes, Change{Kind: ChangeRemoved, Path: memberPointer, Breaking: true, Message: fmt.Sprintf("%s member %d was removed", keyword, i)})
+				continue
+			}
+			oldMap, _ := oldMember.(map[string]any)
+			newMap, _ := newMembers[i].(map[string]any)
+			diffSchema(memberPointer, oldMap, newMap, changes)
+		}
+		for i := len(oldMembers); i < len(newMembers); i++ {
+			*changes = append(*changes, Change{Kind: ChangeAdded, Path: fmt.Sprintf("%s/%s/%d", pointer, keyword, i), Breaking: keyword == "allOf", Message: fmt.Sprintf("%s member %d was added", keyword, i)})
+		}
+	}
+}
+
+// nonNullTypes returns the sorted types of a schema other than null
+func nonNullTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = append(types, value)
+	case []any:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+	types = slices.DeleteFunc(types, func(name string) bool { return name == "null" })
+	sort.Strings(types)
+	return types
+}
+
+// schemaLabel describes the type of a schema in change messages
+func schemaLabel(schema map[string]any) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return refName(ref)
+	}
+	if types := nonNullTypes(schema); len(types) > 0 {
+		return strings.Join(types, "|")
+	}
+	return "any"
+}
+
+// missingValues returns the values of from that are not in to, formatted for messages
+func missingValues(from []any, to []any) []string {
+	var missing []string
+	for _, value := range from {
+		if !slices.ContainsFunc(to, func(other any) bool { return reflect.DeepEqual(value, other) }) {
+			missing = append(missing, fmt.Sprintf("%#v", value))
+		}
+	}
+	return missing
+}
+
+// sortedNames returns the keys of an object in alphabetical order
+func sortedNames(object map[string]any) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// escapePointerToken escapes a key for use as a JSON pointer token
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
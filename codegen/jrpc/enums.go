@@ -0,0 +1,273 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// enumImports returns the packages used by the optional enum helpers, based on the kinds of
+// the enum types that are generated from enum definitions and inline enum properties
+func enumImports(definitions map[string]any, inlineEnums map[string]inlineEnumDef, options *GeneratorOptions) []string {
+	var enums []map[string]any
+	for _, enumDef := range inlineEnums {
+		enums = append(enums, map[string]any{"type": enumDef.typeInfo["type"], "enum": enumDef.values})
+	}
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && !isOverriddenDefinition(defMap) {
+			if enum, ok := defMap["enum"].([]any); ok && len(enum) > 0 {
+				enums = append(enums, defMap)
+			}
+		}
+	}
+
+	hasString, hasInteger, hasIota := false, false, false
+	for _, schema := range enums {
+		values, _ := schema["enum"].([]any)
+		if ints, ok := integerEnumValues(values); ok && schemaType(schema) != "number" {
+			hasInteger = true
+			hasIota = hasIota || isContiguous(ints)
+		} else if hasStringValue(values) {
+			hasString = true
+		}
+	}
+
+	var imports []string
+	if (options.EnumHelpers || options.StrictEnums) && (hasString || hasInteger) {
+		imports = append(imports, "fmt")
+	}
+	if options.StrictEnums && (hasString || hasInteger) {
+		imports = append(imports, "encoding/json")
+	}
+	if (options.IotaEnums && hasIota) || (options.EnumHelpers && hasInteger) {
+		imports = append(imports, "strconv")
+	}
+	return imports
+}
+
+// hasStringValue reports whether any enum value is a string
+func hasStringValue(values []any) bool {
+	for _, value := range values {
+		if _, ok := value.(string); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEnumHelpers writes the String and IsValid methods and the Values and Parse
+// functions of a string enum type
+func generateEnumHelpers(out *bytes.Buffer, typeName string, constNames []string) error {
+	values := strings.Join(constNames, ", ")
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// %sValues returns all allowed %s values\n", typeName, typeName)
+	fmt.Fprintf(&b, "func %sValues() []%s {\n\treturn []%s{%s}\n}\n\n", typeName, typeName, typeName, values)
+
+	fmt.Fprintf(&b, "// String returns the wire value of %s\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) String() string {\n\treturn string(x)\n}\n\n", typeName)
+
+	fmt.Fprintf(&b, "// IsValid reports whether x is one of the allowed %s values\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) IsValid() bool {\n\tswitch x {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n\n", typeName, values)
+
+	fmt.Fprintf(&b, "// Parse%s converts a string to a %s, rejecting values outside the enum\n", typeName, typeName)
+	fmt.Fprintf(&b, "func Parse%s(value string) (%s, error) {\n", typeName, typeName)
+	fmt.Fprintf(&b, "\tx := %s(value)\n", typeName)
+	b.WriteString("\tif !x.IsValid() {\n")
+	fmt.Fprintf(&b, "\t\treturn \"\", fmt.Errorf(\"invalid %s %%q, expected one of %%v\", value, %sValues())\n", typeName, typeName)
+	b.WriteString("\t}\n\treturn x, nil\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateStrictEnumUnmarshaler writes an UnmarshalJSON method that rejects values outside
+// the enum with an error listing the allowed values
+func generateStrictEnumUnmarshaler(out *bytes.Buffer, typeName string, constNames []string, values []string) error {
+	message := fmt.Sprintf("invalid %s %%q, expected one of: %s", typeName, strings.ReplaceAll(strings.Join(values, ", "), "%", "%%"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// UnmarshalJSON decodes a %s, rejecting values outside the enum\n", typeName)
+	fmt.Fprintf(&b, "func (x *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+	b.WriteString("\tvar value string\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &value); err != nil {\n\t\treturn err\n\t}\n\n")
+	fmt.Fprintf(&b, "\tswitch %s(value) {\n\tcase %s:\n", typeName, strings.Join(constNames, ", "))
+	fmt.Fprintf(&b, "\t\t*x = %s(value)\n\t\treturn nil\n\t}\n", typeName)
+	fmt.Fprintf(&b, "\treturn fmt.Errorf(%s, value)\n}\n\n", strconv.Quote(message))
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// integerEnumValues returns the sorted, distinct values of an enum whose non-null values are
+// all integers
+func integerEnumValues(enumValues []any) ([]int, bool) {
+	seen := make(map[int]bool)
+	var values []int
+	for _, value := range enumValues {
+		var number int
+		switch v := value.(type) {
+		case nil:
+			continue
+		case int:
+			number = v
+		case float64:
+			if v != float64(int(v)) {
+				return nil, false
+			}
+			number = int(v)
+		default:
+			return nil, false
+		}
+		if !seen[number] {
+			seen[number] = true
+			values = append(values, number)
+		}
+	}
+	sort.Ints(values)
+	return values, len(values) > 0
+}
+
+// isContiguous reports whether sorted integer values form a gapless range
+func isContiguous(values []int) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i] != values[i-1]+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// integerEnumNames returns the names of integer enum values, taken from the `x-enum-varnames`
+// extension (in the order of the enum keyword) or derived from the values themselves
+func integerEnumNames(defMap map[string]any, values []int, spellings map[string]string) []string {
+	declared := make(map[int]string)
+	if varNames, ok := defMap["x-enum-varnames"].([]any); ok {
+		if enum, ok := defMap["enum"].([]any); ok && len(enum) == len(varNames) {
+			for i, value := range enum {
+				name, isString := varNames[i].(string)
+				number, isInt := integerEnumValues([]any{value})
+				if isString && isInt && convertToGoFieldName(name, spellings) != "" {
+					declared[number[0]] = convertToGoFieldName(name, spellings)
+				}
+			}
+		}
+	}
+
+	names := make([]string, len(values))
+	for i, value := range values {
+		switch name, ok := declared[value]; {
+		case ok:
+			names[i] = name
+		case value < 0:
+			names[i] = "Minus" + strconv.Itoa(-value)
+		default:
+			names[i] = strconv.Itoa(value)
+		}
+	}
+	return names
+}
+
+// generateIntegerEnum generates an int-based enum type. With IotaEnums, contiguous values are
+// declared with iota and get a String method backed by a name lookup table; values are
+// encoded as JSON numbers either way. With EnumHelpers, the other integer enums get a String
+// method returning the number, and ParseX accepts the names of iota values and numbers.
+func generateIntegerEnum(out *bytes.Buffer, templates *template.Template, data EnumTemplateData, defMap map[string]any, values []int, spellings map[string]string, options *GeneratorOptions) error {
+	typeName := data.Name
+	names := integerEnumNames(defMap, values, spellings)
+	constNames := make([]string, len(values))
+	for i, name := range names {
+		constNames[i] = typeName + name
+	}
+	useIota := options.IotaEnums && isContiguous(values)
+
+	for i, constName := range constNames {
+		value := EnumTemplateValue{Name: constName}
+		switch {
+		case !useIota:
+			value.Value = strconv.Itoa(values[i])
+		case i > 0:
+		case values[0] > 0:
+			value.Value = fmt.Sprintf("iota + %d", values[0])
+		case values[0] < 0:
+			value.Value = fmt.Sprintf("iota - %d", -values[0])
+		default:
+			value.Value = "iota"
+		}
+		data.Values = append(data.Values, value)
+	}
+	if err := executeTemplate(out, templates, enumTemplate, data); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if useIota {
+		tableName := strings.ToLower(typeName[:1]) + typeName[1:] + "Names"
+		fmt.Fprintf(&b, "// %s maps %s values to their names\nvar %s = map[%s]string{\n", tableName, typeName, tableName, typeName)
+		for i, constName := range constNames {
+			fmt.Fprintf(&b, "\t%s: %q,\n", constName, names[i])
+		}
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(&b, "// String returns the name of x, or its number when x is not a known %s value\n", typeName)
+		fmt.Fprintf(&b, "func (x %s) String() string {\n", typeName)
+		fmt.Fprintf(&b, "\tif name, ok := %s[x]; ok {\n\t\treturn name\n\t}\n", tableName)
+		b.WriteString("\treturn strconv.Itoa(int(x))\n}\n\n")
+
+		fmt.Fprintf(&b, "// %sFromName returns the %s value with the given name\n", typeName, typeName)
+		fmt.Fprintf(&b, "func %sFromName(name string) (%s, bool) {\n", typeName, typeName)
+		fmt.Fprintf(&b, "\tfor value, valueName := range %s {\n", tableName)
+		b.WriteString("\t\tif valueName == name {\n\t\t\treturn value, true\n\t\t}\n\t}\n")
+		b.WriteString("\treturn 0, false\n}\n\n")
+	}
+
+	joined := strings.Join(constNames, ", ")
+	if options.EnumHelpers {
+		fmt.Fprintf(&b, "// %sValues returns all allowed %s values\n", typeName, typeName)
+		fmt.Fprintf(&b, "func %sValues() []%s {\n\treturn []%s{%s}\n}\n\n", typeName, typeName, typeName, joined)
+
+		if !useIota {
+			fmt.Fprintf(&b, "// String returns the wire value of %s\n", typeName)
+			fmt.Fprintf(&b, "func (x %s) String() string {\n\treturn strconv.Itoa(int(x))\n}\n\n", typeName)
+		}
+
+		fmt.Fprintf(&b, "// IsValid reports whether x is one of the allowed %s values\n", typeName)
+		fmt.Fprintf(&b, "func (x %s) IsValid() bool {\n\tswitch x {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n\n", typeName, joined)
+
+		if useIota {
+			fmt.Fprintf(&b, "// Parse%s converts the name or number of a %s to a %s, rejecting values outside the enum\n", typeName, typeName, typeName)
+		} else {
+			fmt.Fprintf(&b, "// Parse%s converts a number to a %s, rejecting values outside the enum\n", typeName, typeName)
+		}
+		fmt.Fprintf(&b, "func Parse%s(value string) (%s, error) {\n", typeName, typeName)
+		if useIota {
+			fmt.Fprintf(&b, "\tif x, ok := %sFromName(value); ok {\n\t\treturn x, nil\n\t}\n", typeName)
+		}
+		b.WriteString("\tnumber, err := strconv.Atoi(value)\n")
+		fmt.Fprintf(&b, "\tx := %s(number)\n", typeName)
+		b.WriteString("\tif err != nil || !x.IsValid() {\n")
+		fmt.Fprintf(&b, "\t\treturn 0, fmt.Errorf(\"invalid %s %%q, expected one of %%v\", value, %sValues())\n", typeName, typeName)
+		b.WriteString("\t}\n\treturn x, nil\n}\n\n")
+	}
+
+	if options.StrictEnums {
+		allowed := make([]string, len(values))
+		for i, value := range values {
+			allowed[i] = strconv.Itoa(value)
+		}
+		fmt.Fprintf(&b, "// UnmarshalJSON decodes a %s, rejecting values outside the enum\n", typeName)
+		fmt.Fprintf(&b, "func (x *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+		b.WriteString("\tvar value int\n")
+		b.WriteString("\tif err := json.Unmarshal(data, &value); err != nil {\n\t\treturn err\n\t}\n\n")
+		fmt.Fprintf(&b, "\tswitch %s(value) {\n\tcase %s:\n", typeName, joined)
+		fmt.Fprintf(&b, "\t\t*x = %s(value)\n\t\treturn nil\n\t}\n", typeName)
+		fmt.Fprintf(&b, "\treturn fmt.Errorf(\"invalid %s %%d, expected one of: %s\", value)\n}\n\n", typeName, strings.Join(allowed, ", "))
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
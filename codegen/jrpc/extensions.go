@@ -0,0 +1,220 @@
+package jrpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// goTypeOverride returns the Go type pinned by an `x-go-type` extension
+func goTypeOverride(schema map[string]any) (string, bool) {
+	goType, ok := schema["x-go-type"].(string)
+	return goType, ok && goType != ""
+}
+
+// isOverriddenDefinition reports whether a definition is mapped to an existing Go type via
+// x-go-type, in which case no local type is generated for it
+func isOverriddenDefinition(defMap map[string]any) bool {
+	_, ok := goTypeOverride(defMap)
+	return ok
+}
+
+// collectGoImports walks a schema and returns the packages declared with `x-go-import`.
+// The extension is either an import path or an object with `path` and optional `name`
+// (e.g. {"path": "github.com/shopspring/decimal", "name": "dec"}).
+func collectGoImports(node any, imports map[string]bool) {
+	switch value := node.(type) {
+	case map[string]any:
+		switch imp := value["x-go-import"].(type) {
+		case string:
+			if imp != "" {
+				imports[imp] = true
+			}
+		case map[string]any:
+			if path, ok := imp["path"].(string); ok && path != "" {
+				if name, ok := imp["name"].(string); ok && name != "" {
+					imports[name+" "+path] = true
+				} else {
+					imports[path] = true
+				}
+			}
+		}
+
+		for _, child := range value {
+			collectGoImports(child, imports)
+		}
+	case []any:
+		for _, child := range value {
+			collectGoImports(child, imports)
+		}
+	}
+}
+
+// extensionImports returns the sorted x-go-import packages used by the definitions
+func extensionImports(definitions map[string]any) []string {
+	imports := make(map[string]bool)
+	collectGoImports(definitions, imports)
+
+	result := make([]string, 0, len(imports))
+	for imp := range imports {
+		result = append(result, imp)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return importPath(result[i]) < importPath(result[j])
+	})
+	return result
+}
+
+// importPath returns the path of an import spec of the form "path" or "name path"
+func importPath(spec string) string {
+	if _, path, ok := strings.Cut(spec, " "); ok {
+		return path
+	}
+	return spec
+}
+
+// goNameOverride returns the Go identifier pinned by an `x-go-name` extension
+func goNameOverride(schema map[string]any) (string, bool) {
+	name, ok := schema["x-go-name"].(string)
+	return name, ok && name != ""
+}
+
+// goFieldName returns the Go field name of a property, honoring x-go-name
+func goFieldName(propName string, propMap map[string]any, spellings map[string]string) string {
+	if name, ok := goNameOverride(propMap); ok {
+		return name
+	}
+	return convertToGoFieldName(propName, spellings)
+}
+
+// applyDefinitionNames renames the definitions of a schema document that declare x-go-name
+// and rewrites every $ref pointing at them, so the rest of the generator sees the pinned names
+// as definition names
+func applyDefinitionNames(schema map[string]any, definitions map[string]any) error {
+	renames := make(map[string]string)
+	for _, defName := range sortedDefinitionNames(definitions) {
+		defMap, ok := definitions[defName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, ok := goNameOverride(defMap)
+		if !ok || name == defName {
+			continue
+		}
+
+		if _, exists := definitions[name]; exists {
+			pointer := definitionPointer(schema, defName)
+			if pointer != "" {
+				pointer += "/x-go-name"
+			}
+			return &codegen.SchemaError{
+				Pointer: pointer,
+				Err:     fmt.Errorf("x-go-name %q of definition %q conflicts with an existing definition", name, defName),
+			}
+		}
+
+		definitions[name] = defMap
+		delete(definitions, defName)
+		renames[defName] = name
+	}
+
+	if len(renames) > 0 {
+		rewriteRefs(definitions, renames)
+	}
+
+	return nil
+}
+
+// rewriteRefs points every $ref whose definition name appears in renames at the new name
+func rewriteRefs(node any, renames map[string]string) {
+	switch value := node.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			if name, renamed := renames[refName(ref)]; renamed {
+				value["$ref"] = "#/definitions/" + name
+			}
+		}
+		for _, child := range value {
+			rewriteRefs(child, renames)
+		}
+	case []any:
+		for _, child := range value {
+			rewriteRefs(child, renames)
+		}
+	}
+}
+
+// definedPrimitiveTypes are the Go types primitive definitions can be declared as defined
+// types of. Other types, such as time.Time, stay aliases so that their methods are kept.
+var definedPrimitiveTypes = map[string]bool{
+	"string":  true,
+	"bool":    true,
+	"int":     true,
+	"int32":   true,
+	"int64":   true,
+	"float32": true,
+	"float64": true,
+}
+
+// primitiveAlias reports whether a primitive definition of goType is declared as an alias
+// (type X = string) rather than a defined type (type X string): as set by an `x-go-alias`
+// extension, or else unless GeneratorOptions.DefinedTypes is set
+func primitiveAlias(defMap map[string]any, goType string, options *GeneratorOptions) bool {
+	if !definedPrimitiveTypes[goType] {
+		return true
+	}
+	if alias, ok := defMap["x-go-alias"].(bool); ok {
+		return alias
+	}
+	return !options.DefinedTypes
+}
+
+// generatorExtensions are the vendor extensions the generator interprets. The others are
+// passed through by VendorExtensions.
+var generatorExtensions = map[string]bool{
+	"x-go-type":            true,
+	"x-go-import":          true,
+	"x-go-name":            true,
+	"x-go-alias":           true,
+	"x-go-property-order":  true,
+	"x-go-param-order":     true,
+	"x-go-param-structure": true,
+	"x-enum-varnames":      true,
+	"x-nullable":           true,
+	"x-pagination":         true,
+}
+
+// VendorExtensions returns the `x-*` extensions of a schema or OpenAPI object that the
+// generator does not interpret, or nil when it has none
+func VendorExtensions(object map[string]any) map[string]any {
+	var extensions map[string]any
+	for key, value := range object {
+		if !strings.HasPrefix(key, "x-") || generatorExtensions[key] {
+			continue
+		}
+		if extensions == nil {
+			extensions = make(map[string]any)
+		}
+		extensions[key] = value
+	}
+	return extensions
+}
+
+// ExtensionComments returns the lines commenting vendor extensions, without the "//" prefix:
+// one "x-name: value" line per extension, sorted by name, with the value as compact JSON
+func ExtensionComments(extensions map[string]any) []string {
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, name+": "+renderValues([]any{extensions[name]}))
+	}
+	return lines
+}
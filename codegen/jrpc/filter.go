@@ -0,0 +1,83 @@
+package jrpc
+
+import (
+	"fmt"
+	"path"
+)
+
+// validateTypePatterns checks that the glob patterns of GeneratorOptions.IncludeTypes and
+// ExcludeTypes are well-formed
+func validateTypePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid type pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDefinitions removes the definitions that are not selected by
+// GeneratorOptions.IncludeTypes and ExcludeTypes. The selected definitions are those matching
+// an include pattern (all definitions when there is none) and no exclude pattern; every
+// definition they reference, directly or transitively, is kept so that the output compiles.
+func filterDefinitions(definitions map[string]any, options *GeneratorOptions) error {
+	if len(options.IncludeTypes) == 0 && len(options.ExcludeTypes) == 0 {
+		return nil
+	}
+
+	for _, pattern := range options.IncludeTypes {
+		matched := false
+		for name := range definitions {
+			matched = matched || matchesAny(name, []string{pattern})
+		}
+		if !matched {
+			return fmt.Errorf("include pattern %q matches no definition", pattern)
+		}
+	}
+
+	var pending []string
+	for _, name := range sortedDefinitionNames(definitions) {
+		included := len(options.IncludeTypes) == 0 || matchesAny(name, options.IncludeTypes)
+		if included && !matchesAny(name, options.ExcludeTypes) {
+			pending = append(pending, name)
+		}
+	}
+
+	kept := make(map[string]bool, len(pending))
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if kept[name] {
+			continue
+		}
+		kept[name] = true
+
+		refs := make(map[string]bool)
+		collectRefNames(definitions[name], refs)
+		for ref := range refs {
+			if _, exists := definitions[ref]; exists && !kept[ref] {
+				pending = append(pending, ref)
+			}
+		}
+	}
+
+	for name := range definitions {
+		if !kept[name] {
+			delete(definitions, name)
+		}
+	}
+	if len(definitions) == 0 {
+		return fmt.Errorf("no definitions left to generate after applying the type filters")
+	}
+	return nil
+}
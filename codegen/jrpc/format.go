@@ -0,0 +1,29 @@
+package jrpc
+
+import (
+	"go/format"
+
+	"github.com/inference-gateway/tools/codegen"
+	"golang.org/x/tools/imports"
+)
+
+// formatSource formats generated code in-process: with goimports when
+// GeneratorOptions.Goimports is set, otherwise with gofmt when FormatOutput is set. fileName is
+// only used in error messages and by goimports to resolve the surrounding package.
+func formatSource(fileName string, source []byte, options *GeneratorOptions) ([]byte, error) {
+	switch {
+	case options.Goimports:
+		formatted, err := imports.Process(fileName, source, nil)
+		if err != nil {
+			return nil, &codegen.FormatError{Name: fileName, Err: err}
+		}
+		return formatted, nil
+	case options.FormatOutput:
+		formatted, err := format.Source(source)
+		if err != nil {
+			return nil, &codegen.FormatError{Name: fileName, Err: err}
+		}
+		return formatted, nil
+	}
+	return source, nil
+}
@@ -0,0 +1,178 @@
+package jrpc
+
+import "bytes"
+
+// durationHelperImports are the packages used by the generated Duration helper type
+var durationHelperImports = []string{"encoding/json", "fmt", "strconv", "strings", "time"}
+
+// applyFormatMappings pins the Go types of string formats that map to types outside the
+// generated file's defaults, by setting x-go-type (and x-go-import) on the matching schemas:
+// `format: duration` maps to a generated ISO 8601 Duration type, and `format: decimal` to
+// options.DecimalType when configured. It returns the name of the Duration type, or an
+// empty string when no schema uses the duration format.
+func applyFormatMappings(definitions map[string]any, options *GeneratorOptions) string {
+	durationType := "Duration"
+	if existing, ok := definitions[durationType].(map[string]any); ok && !isDurationSchema(existing) {
+		durationType = uniqueDefinitionName(definitions, durationType)
+	}
+
+	usesDuration := false
+	var visit func(schema map[string]any)
+	visit = func(schema map[string]any) {
+		if _, overridden := goTypeOverride(schema); overridden {
+			return
+		}
+
+		switch {
+		case isDurationSchema(schema):
+			schema["x-go-type"] = durationType
+			usesDuration = true
+		case isDecimalSchema(schema) && options.DecimalType != "":
+			schema["x-go-type"] = options.DecimalType
+			if options.DecimalImport != "" {
+				schema["x-go-import"] = options.DecimalImport
+			}
+		}
+
+		for _, subschema := range subschemas(schema) {
+			visit(subschema)
+		}
+	}
+
+	for _, defName := range sortedDefinitionNames(definitions) {
+		if defMap, ok := definitions[defName].(map[string]any); ok {
+			visit(defMap)
+		}
+	}
+
+	if !usesDuration {
+		return ""
+	}
+	return durationType
+}
+
+// isDurationSchema reports whether a schema is a string with `format: duration`
+func isDurationSchema(schema map[string]any) bool {
+	format, _ := schema["format"].(string)
+	return format == "duration" && schemaType(schema) == "string"
+}
+
+// isDecimalSchema reports whether a schema is a string or number with `format: decimal`
+func isDecimalSchema(schema map[string]any) bool {
+	format, _ := schema["format"].(string)
+	if format != "decimal" {
+		return false
+	}
+	schemaKind := schemaType(schema)
+	return schemaKind == "string" || schemaKind == "number"
+}
+
+// generateDurationHelpers writes the Duration type used for `format: duration`, a
+// time.Duration that is encoded as an ISO 8601 duration string
+func generateDurationHelpers(out *bytes.Buffer, typeName string) error {
+	helpers := `// ` + typeName + ` is a time.Duration encoded as an ISO 8601 duration string (e.g. "PT1H30M")
+type ` + typeName + ` time.Duration
+
+// MarshalJSON encodes the duration as an ISO 8601 duration string
+func (d ` + typeName + `) MarshalJSON() ([]byte, error) {
+	return json.Marshal(formatISODuration(time.Duration(d)))
+}
+
+// UnmarshalJSON decodes the duration from an ISO 8601 duration string
+func (d *` + typeName + `) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := parseISODuration(value)
+	if err != nil {
+		return err
+	}
+	*d = ` + typeName + `(parsed)
+	return nil
+}
+
+// parseISODuration parses an ISO 8601 duration such as "PT1H30M" or "P1DT12H". Years and
+// months have no fixed length and are rejected.
+func parseISODuration(value string) (time.Duration, error) {
+	rest := strings.TrimPrefix(value, "-")
+	negative := len(rest) != len(value)
+	if len(rest) < 2 || rest[0] != 'P' {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+	}
+	rest = rest[1:]
+
+	var total time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		end := strings.IndexAny(rest, "YMWDHS")
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+		}
+		number, err := strconv.ParseFloat(rest[:end], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", value, err)
+		}
+
+		var unit time.Duration
+		switch designator := rest[end]; {
+		case designator == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case designator == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case designator == 'H' && inTime:
+			unit = time.Hour
+		case designator == 'M' && inTime:
+			unit = time.Minute
+		case designator == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported ISO 8601 duration %q", value)
+		}
+
+		total += time.Duration(number * float64(unit))
+		rest = rest[end+1:]
+	}
+
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// formatISODuration formats a duration as an ISO 8601 duration using hours, minutes and seconds
+func formatISODuration(duration time.Duration) string {
+	if duration == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	if duration < 0 {
+		b.WriteString("-")
+		duration = -duration
+	}
+	b.WriteString("PT")
+	if hours := duration / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+		duration -= hours * time.Hour
+	}
+	if minutes := duration / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+		duration -= minutes * time.Minute
+	}
+	if duration > 0 {
+		b.WriteString(strconv.FormatFloat(duration.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+`
+	_, err := out.WriteString(helpers)
+	return err
+}
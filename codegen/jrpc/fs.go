@@ -0,0 +1,78 @@
+package jrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/inference-gateway/tools/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateTypesFS generates Go types from a JSON/YAML schema file of fsys, such as the
+// embed.FS of go:embed'ed schemas, and writes them to w, so that services can generate code
+// at build time without touching the filesystem. The format follows the extension of name,
+// which Provenance names unless SchemaName is set. SplitMode is not supported, since it
+// produces several files.
+func GenerateTypesFS(w io.Writer, fsys fs.FS, name string, options *GeneratorOptions) error {
+	options, err := prepareOptions(options)
+	if err != nil {
+		return err
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q writes several files and requires GenerateTypes", options.SplitMode)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+	schema, err := decodeSchema(name, data)
+	if err != nil {
+		return err
+	}
+
+	schemaName := name
+	if options.SchemaName != "" {
+		schemaName = options.SchemaName
+	}
+	source, err := generateSource(schemaName, data, schema, options)
+	if err != nil {
+		return codegen.LocateData(err, name, data)
+	}
+
+	source, err = formatSource(options.PackageName+".go", source, options)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(source); err != nil {
+		return fmt.Errorf("failed to write generated code: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchemaFS is ValidateSchema for a JSON/YAML schema file of fsys
+func ValidateSchemaFS(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+	return validateSchema(name, data)
+}
+
+// ValidateSchemaBytes is ValidateSchema for the contents of a schema, parsed as JSON falling
+// back to YAML like GenerateTypesTo does
+func ValidateSchemaBytes(schema []byte) error {
+	var document map[string]any
+	if err := json.Unmarshal(schema, &document); err != nil {
+		if err := yaml.Unmarshal(schema, &document); err != nil {
+			return fmt.Errorf("invalid schema: %w", codegen.DecodeError("", schema, err))
+		}
+	}
+	if len(extractDefinitions(document)) == 0 {
+		return &codegen.SchemaError{Err: errors.New("schema does not contain any type definitions")}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package jrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"text/template"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// generateFuzzTests writes to options.FuzzTests a _test.go file with a FuzzXUnmarshal target
+// per type of the generated source declaring an UnmarshalJSON method (unions, strict enums,
+// overflow maps, tuples, OpenRPC envelopes and params), seeded with the examples of its
+// schema and a generated value. Each target checks that the values it decodes encode, and
+// that their encoding decodes and encodes again to the same JSON. Nothing is written when no
+// type has an UnmarshalJSON method.
+func generateFuzzTests(templates *template.Template, schemaName string, data []byte, source []byte, definitions map[string]any, options *GeneratorOptions) error {
+	decoders, err := unmarshalerTypes(source)
+	if err != nil {
+		return err
+	}
+	if len(decoders) == 0 {
+		return nil
+	}
+
+	out := new(bytes.Buffer)
+	imports := newImportManager()
+	imports.add("bytes", "encoding/json", "testing")
+	if err := executeTemplate(out, templates, headerTemplate, headerData(schemaName, data, imports.render(), options)); err != nil {
+		return err
+	}
+	out.WriteString(fuzzTestHelpers)
+	for _, typeName := range decoders {
+		fmt.Fprintf(out, "\n// Fuzz%sUnmarshal fuzzes the decoding of %s\n", typeName, typeName)
+		fmt.Fprintf(out, "func Fuzz%sUnmarshal(f *testing.F) {\n", typeName)
+		seeds, err := fuzzSeeds(typeName, definitions)
+		if err != nil {
+			return err
+		}
+		for _, seed := range seeds {
+			fmt.Fprintf(out, "\tf.Add([]byte(%s))\n", strconv.Quote(seed))
+		}
+		fmt.Fprintf(out, "\tf.Fuzz(fuzzUnmarshal[%s])\n}\n", typeName)
+	}
+
+	result := out.Bytes()
+	if options.FormatOutput {
+		formatted, err := format.Source(result)
+		if err != nil {
+			return &codegen.FormatError{Name: "fuzz tests", Err: err}
+		}
+		result = formatted
+	}
+	if _, err := options.FuzzTests.Write(result); err != nil {
+		return fmt.Errorf("failed to write fuzz tests: %w", err)
+	}
+	return nil
+}
+
+// unmarshalerTypes returns the exported types of the source with an UnmarshalJSON method, in
+// declaration order
+func unmarshalerTypes(source []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	var types []string
+	seen := map[string]bool{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Name.Name != "UnmarshalJSON" || len(fn.Recv.List) != 1 {
+			continue
+		}
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		ident, ok := recv.(*ast.Ident)
+		if !ok || !ident.IsExported() || seen[ident.Name] {
+			continue
+		}
+		seen[ident.Name] = true
+		types = append(types, ident.Name)
+	}
+	return types, nil
+}
+
+// fuzzSeeds returns the encoded examples of the definition of a type and a value generated
+// from it, or nothing for types without a definition (generated helpers and hoisted enums)
+func fuzzSeeds(typeName string, definitions map[string]any) ([]string, error) {
+	defMap, ok := definitions[typeName].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	var samples []any
+	if example, ok := defMap["example"]; ok {
+		samples = append(samples, example)
+	}
+	if examples, ok := defMap["examples"].([]any); ok {
+		samples = append(samples, examples...)
+	}
+	samples = append(samples, SampleValue(map[string]any{"$ref": typeName}, definitions, SampleOptions{}))
+	var seeds []string
+	for _, sample := range samples {
+		encoded, err := json.Marshal(sample)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the sample of %s: %w", typeName, err)
+		}
+		seeds = append(seeds, string(encoded))
+	}
+	return seeds, nil
+}
+
+// fuzzTestHelpers is the fuzz function shared by the FuzzXUnmarshal targets
+const fuzzTestHelpers = `// fuzzUnmarshal decodes data into a T and, when it decodes, checks that the value encodes,
+// and that its encoding decodes and encodes again to the same JSON
+func fuzzUnmarshal[T any](t *testing.T, data []byte) {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return
+	}
+	encoded, err := json.Marshal(&value)
+	if err != nil {
+		t.Fatalf("encoding the decoding of %s: %v", data, err)
+	}
+	var again T
+	if err := json.Unmarshal(encoded, &again); err != nil {
+		t.Fatalf("decoding %s, the encoding of %s: %v", encoded, data, err)
+	}
+	reencoded, err := json.Marshal(&again)
+	if err != nil {
+		t.Fatalf("encoding again: %v", err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("round trip changed %s into %s", encoded, reencoded)
+	}
+}
+`
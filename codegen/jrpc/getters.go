@@ -0,0 +1,57 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// getterField is an optional pointer field that gets a nil-safe accessor
+type getterField struct {
+	fieldName string // Go field name
+	fieldType string // Go type of the field, including the pointer
+}
+
+// basicZeroValues are the zero value literals of the predeclared types used by generated fields
+var basicZeroValues = map[string]string{
+	"string":  `""`,
+	"bool":    "false",
+	"int":     "0",
+	"int32":   "0",
+	"int64":   "0",
+	"float32": "0",
+	"float64": "0",
+	"any":     "nil",
+}
+
+// generateGetters writes protobuf-style GetX accessors for optional pointer fields. Scalar
+// fields are dereferenced and return their zero value when unset; fields pointing at
+// generated structs return the pointer, so that getters can be chained through nil values.
+func generateGetters(out *bytes.Buffer, typeName string, fields []getterField, definitions map[string]any) error {
+	var b strings.Builder
+
+	for _, field := range fields {
+		elemType := strings.TrimPrefix(field.fieldType, "*")
+		getter := "Get" + field.fieldName
+
+		if elemDef, ok := definitions[elemType].(map[string]any); ok && isStructDefinition(elemDef, definitions) {
+			fmt.Fprintf(&b, "// %s returns %s, or nil when x is nil\n", getter, field.fieldName)
+			fmt.Fprintf(&b, "func (x *%s) %s() %s {\n", typeName, getter, field.fieldType)
+			b.WriteString("\tif x == nil {\n\t\treturn nil\n\t}\n")
+			fmt.Fprintf(&b, "\treturn x.%s\n}\n\n", field.fieldName)
+			continue
+		}
+
+		fmt.Fprintf(&b, "// %s returns the value of %s, or its zero value when it is unset\n", getter, field.fieldName)
+		fmt.Fprintf(&b, "func (x *%s) %s() %s {\n", typeName, getter, elemType)
+		fmt.Fprintf(&b, "\tif x != nil && x.%s != nil {\n\t\treturn *x.%s\n\t}\n", field.fieldName, field.fieldName)
+		if zero, ok := basicZeroValues[elemType]; ok {
+			fmt.Fprintf(&b, "\treturn %s\n}\n\n", zero)
+		} else {
+			fmt.Fprintf(&b, "\tvar zero %s\n\treturn zero\n}\n\n", elemType)
+		}
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
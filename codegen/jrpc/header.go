@@ -0,0 +1,78 @@
+package jrpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/build/constraint"
+	"strings"
+	"time"
+)
+
+// defaultGeneratedBy names the generator in the generated code notice when
+// GeneratorOptions.GeneratedBy is not set
+const defaultGeneratedBy = "github.com/inference-gateway/tools"
+
+// validateBuildConstraint checks that GeneratorOptions.BuildConstraint is a valid //go:build
+// expression
+func validateBuildConstraint(expr string) error {
+	if expr == "" {
+		return nil
+	}
+	if _, err := constraint.Parse("//go:build " + expr); err != nil {
+		return fmt.Errorf("invalid build constraint %q: %w", expr, err)
+	}
+	return nil
+}
+
+// headerData returns the data of the header template: the license header, the generated code
+// notice, the build constraint, the package clause and the rendered imports. schemaName is the
+// schema path named in the notice, empty when the schema was not read from a file.
+func headerData(schemaName string, data []byte, imports string, options *GeneratorOptions) HeaderTemplateData {
+	sum := sha256.Sum256(data)
+	schemaHash := hex.EncodeToString(sum[:])
+	return HeaderTemplateData{
+		PackageName:     options.PackageName,
+		License:         strings.TrimRight(options.LicenseHeader, "\n"),
+		Notice:          generatedNotice(schemaName, schemaHash, options),
+		BuildConstraint: options.BuildConstraint,
+		Schema:          schemaName,
+		SchemaHash:      schemaHash,
+		Imports:         imports,
+	}
+}
+
+// generatedNotice returns the "Code generated ... DO NOT EDIT." notice recognized by Go
+// tooling, without the comment marker. With GeneratorOptions.Provenance it names the
+// generator, the schema and its SHA-256 hash, and with GeneratorOptions.Timestamp the time of
+// generation.
+func generatedNotice(schemaName string, schemaHash string, options *GeneratorOptions) string {
+	source := options.SchemaSource
+	if source == "" {
+		source = "JSON schema"
+	}
+	if !options.Provenance && !options.Timestamp {
+		return "Code generated from " + source + ". DO NOT EDIT."
+	}
+
+	notice := "Code generated"
+	if options.Provenance {
+		generatedBy := options.GeneratedBy
+		if generatedBy == "" {
+			generatedBy = defaultGeneratedBy
+		}
+		notice += " by " + generatedBy + " from"
+		if schemaName != "" {
+			notice += " " + schemaName
+		} else {
+			notice += " " + source
+		}
+		notice += " (sha256 " + schemaHash + ")"
+	} else {
+		notice += " from " + source
+	}
+	if options.Timestamp {
+		notice += " at " + time.Now().UTC().Format(time.RFC3339)
+	}
+	return notice + "; DO NOT EDIT."
+}
@@ -0,0 +1,35 @@
+package jrpc
+
+import "fmt"
+
+// PostIRHook edits the definitions the Go types are generated from, once the inline objects,
+// enums and unions are hoisted into definitions named like their types and the refs name
+// their definition in their last segment, e.g. to add properties to every model or rename a
+// type with RenameDefinition
+type PostIRHook func(definitions map[string]any) error
+
+// RenameDefinition renames a definition, for a PostIRHook, and points the $refs to it at the
+// new name
+func RenameDefinition(definitions map[string]any, from, to string) error {
+	definition, ok := definitions[from]
+	if !ok {
+		return fmt.Errorf("no definition %s to rename", from)
+	}
+	if _, taken := definitions[to]; taken {
+		return fmt.Errorf("cannot rename definition %s to %s: the name is taken", from, to)
+	}
+	delete(definitions, from)
+	definitions[to] = definition
+	rewriteRefs(definitions, map[string]string{from: to})
+	return nil
+}
+
+// runPostIR runs the PostIR hooks of options on definitions
+func runPostIR(definitions map[string]any, options *GeneratorOptions) error {
+	for i, hook := range options.PostIR {
+		if err := hook(definitions); err != nil {
+			return fmt.Errorf("post-IR hook %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package jrpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// importManager collects the packages a generated file needs and renders them as a single
+// import declaration, with standard library packages grouped before third-party ones
+type importManager struct {
+	specs map[string]bool
+}
+
+// newImportManager returns an empty import manager
+func newImportManager() *importManager {
+	return &importManager{specs: make(map[string]bool)}
+}
+
+// add records import specs of the form "path" or "name path", ignoring duplicates
+func (m *importManager) add(specs ...string) {
+	for _, spec := range specs {
+		if spec != "" {
+			m.specs[spec] = true
+		}
+	}
+}
+
+// render returns the import declaration for the collected packages, or an empty string
+// when no package is needed
+func (m *importManager) render() string {
+	var stdlib, thirdParty []string
+	for spec := range m.specs {
+		if isStandardLibrary(importPath(spec)) {
+			stdlib = append(stdlib, spec)
+		} else {
+			thirdParty = append(thirdParty, spec)
+		}
+	}
+	byPath := func(specs []string) func(i, j int) bool {
+		return func(i, j int) bool { return importPath(specs[i]) < importPath(specs[j]) }
+	}
+	sort.Slice(stdlib, byPath(stdlib))
+	sort.Slice(thirdParty, byPath(thirdParty))
+
+	switch {
+	case len(stdlib)+len(thirdParty) == 0:
+		return ""
+	case len(stdlib)+len(thirdParty) == 1:
+		return fmt.Sprintf("import %s\n\n", formatImportSpec(append(stdlib, thirdParty...)[0]))
+	}
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, spec := range stdlib {
+		b.WriteString("\t" + formatImportSpec(spec) + "\n")
+	}
+	if len(stdlib) > 0 && len(thirdParty) > 0 {
+		b.WriteString("\n")
+	}
+	for _, spec := range thirdParty {
+		b.WriteString("\t" + formatImportSpec(spec) + "\n")
+	}
+	b.WriteString(")\n\n")
+	return b.String()
+}
+
+// isStandardLibrary reports whether an import path belongs to the standard library, whose
+// first path element never contains a dot
+func isStandardLibrary(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// formatImportSpec renders an import spec of the form "path" or "name path"
+func formatImportSpec(spec string) string {
+	if name, path, ok := strings.Cut(spec, " "); ok {
+		return fmt.Sprintf("%s %q", name, path)
+	}
+	return fmt.Sprintf("%q", spec)
+}
+
+// formatPackages maps string formats to the package their generated Go type lives in
+var formatPackages = map[string]string{
+	"date-time": "time",
+	"date":      "time",
+	"time":      "time",
+}
+
+// collectFormatImports walks a schema and records the packages required by the Go types of
+// its formats. Schemas pinned with x-go-type are skipped, since their imports come from
+// x-go-import instead.
+func collectFormatImports(schema map[string]any, imports *importManager) {
+	if _, overridden := goTypeOverride(schema); overridden {
+		return
+	}
+
+	if format, ok := schema["format"].(string); ok {
+		imports.add(formatPackages[format])
+	}
+
+	for _, subschema := range subschemas(schema) {
+		collectFormatImports(subschema, imports)
+	}
+}
+
+// subschemas returns the schemas nested directly inside a schema: properties, array and
+// tuple items, additionalProperties and composition members
+func subschemas(schema map[string]any) []map[string]any {
+	var result []map[string]any
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		for _, prop := range properties {
+			if propMap, ok := prop.(map[string]any); ok {
+				result = append(result, propMap)
+			}
+		}
+	}
+
+	for _, key := range []string{"items", "additionalProperties"} {
+		if child, ok := schema[key].(map[string]any); ok {
+			result = append(result, child)
+		}
+	}
+
+	for _, key := range []string{"items", "prefixItems", "anyOf", "oneOf", "allOf"} {
+		if members, ok := schema[key].([]any); ok {
+			for _, member := range members {
+				if memberMap, ok := member.(map[string]any); ok {
+					result = append(result, memberMap)
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -1,13 +1,19 @@
 package jrpc
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"sort"
 	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/inference-gateway/tools/codegen"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"gopkg.in/yaml.v3"
@@ -17,8 +23,64 @@ import (
 type GeneratorOptions struct {
 	PackageName     string          // Target Go package name (default: "types")
 	CustomAcronyms  map[string]bool // Additional acronyms to handle specially
+	Naming          *NamingRules    // Additional acronyms, forced names and word spellings (see LoadNamingRules)
 	IncludeComments bool            // Whether to include descriptions as comments (default: true)
-	FormatOutput    bool            // Whether to run go fmt on output (default: true)
+	FormatOutput    bool            // Whether to gofmt the output in-process (default: true)
+	CommentWidth    int             // Line width field comments are wrapped to (default: 80, negative disables wrapping)
+
+	ExtensionComments bool // Whether to write the vendor extensions (x-*) the generator does not interpret as comments (// x-rate-limit: 100)
+
+	ResolveRemoteRefs bool   // Whether to fetch and inline HTTP(S) $ref targets
+	RefCacheDir       string // Directory for cached remote documents (default: user cache dir)
+	Offline           bool   // Whether remote refs must be served from the cache without network access
+
+	DecimalType   string // Go type for `format: decimal` (e.g. "decimal.Decimal"; default: string or float64)
+	DecimalImport string // Import path of DecimalType (e.g. "github.com/shopspring/decimal")
+
+	GenerateDefaults  bool     // Whether to generate ApplyDefaults methods that populate schema defaults
+	ReadWriteVariants bool     // Whether to generate XCreate/XRead variants of models using readOnly/writeOnly
+	ValidateTags      bool     // Whether to add go-playground/validator `validate` tags derived from constraints
+	GenerateValidate  bool     // Whether to generate dependency-free Validate methods enforcing schema constraints
+	EnumHelpers       bool     // Whether to generate String/IsValid/Values/Parse helpers for enum types
+	StrictEnums       bool     // Whether enum types reject values outside the enum when unmarshaling JSON
+	IotaEnums         bool     // Whether contiguous integer enums use iota constants with a name lookup table
+	Constructors      bool     // Whether to generate NewX constructors taking the required fields as arguments
+	Getters           bool     // Whether to generate nil-safe GetX accessors for optional pointer fields
+	ExampleFactories  bool     // Whether to generate ExampleX and FakeX functions returning models populated with sample data
+	RPCClient         bool     // Whether to generate a Client with one method per OpenRPC method over a pluggable Transport
+	RPCServer         bool     // Whether to generate a ServerInterface with one handler per OpenRPC method and a Dispatcher routing requests to it
+	OmitMode          string   // How optional fields are omitted from JSON: "omitempty" (default), "omitzero" or "both"
+	Tags              []string // Extra struct tag keys written next to the json tag (e.g. "yaml", "bson")
+	TagTemplates      []string // text/template sources rendering one extra struct tag per field (e.g. `db:"{{ .SnakeName }}"`)
+	PreserveOrder     bool     // Whether struct fields follow the schema's property order instead of alphabetical order
+	SplitMode         string   // Split output into a directory: "kind" (enums.go, models.go, helpers.go) or "type" (one file per type)
+	Goimports         bool     // Whether to format the output with goimports instead of gofmt
+
+	BuildConstraint string // Build constraint expression written as a //go:build line (e.g. "!codeanalysis")
+	LicenseHeader   string // License text written as a comment above the generated code notice
+	Provenance      bool   // Whether the generated code notice names the generator, the schema and its SHA-256 hash
+	SchemaName      string // Schema named by Provenance instead of its path (e.g. the URL it was downloaded from)
+	SchemaSource    string // What the generated code notice says the code is generated from, without Provenance (default: "JSON schema"; e.g. "Avro schema user.avsc")
+	GeneratedBy     string // Generator name and version named by Provenance (default: "github.com/inference-gateway/tools")
+	Timestamp       bool   // Whether the generated code notice includes the generation time (makes output non-reproducible)
+
+	TemplateDir string // Directory of header.tmpl, enum.tmpl, struct.tmpl and alias.tmpl files overriding the default templates
+
+	ImportMappings map[string]string // Definitions ($ref pointer or name) mapped to existing types, as "import/path.Type", instead of being generated
+	DefinedTypes   bool              // Whether primitive definitions become defined types (type ID string) instead of aliases (type ID = string)
+
+	IncludeTypes []string // Glob patterns of the definitions to generate, together with the definitions they reference (default: all)
+	ExcludeTypes []string // Glob patterns of definitions not to generate unless a generated definition references them
+
+	ReservedNames []string  // Unexported package-level identifiers of code written next to the models, which definitions are renamed away from
+	RenameReport  io.Writer // Receives a line for every definition or property renamed to avoid a Go keyword or an identifier collision
+	ContractTests io.Writer // Receives a _test.go file checking every struct model against the examples, required properties, enums and formats of its schema, and the example pairings of OpenRPC methods against their types
+	FuzzTests     io.Writer // Receives a _test.go file with a FuzzXUnmarshal target per type with an UnmarshalJSON method, unless there is none
+
+	BenchmarkTypes []string  // Glob patterns of the generated models to benchmark the encoding and decoding of
+	BenchmarkTests io.Writer // Receives a _test.go file with the BenchmarkMarshalX and BenchmarkUnmarshalX benchmarks of the BenchmarkTypes
+
+	PostIR []PostIRHook // Edit the definitions before the code is written, in order, e.g. to add properties or rename types
 }
 
 // DefaultAcronyms returns the default set of acronyms that should be capitalized
@@ -53,6 +115,103 @@ func DefaultAcronyms() map[string]bool {
 // GenerateTypes generates Go types from JSON/YAML schema files
 // Supports JSON Schema Draft 4/6/7 and OpenRPC schemas
 func GenerateTypes(destination string, schemaPath string, options *GeneratorOptions) error {
+	options, err := prepareOptions(options)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	schema, err := decodeSchema(schemaPath, data)
+	if err != nil {
+		return err
+	}
+
+	schemaName := schemaPath
+	if options.SchemaName != "" {
+		schemaName = options.SchemaName
+	}
+	source, err := generateSource(schemaName, data, schema, options)
+	if err != nil {
+		return codegen.Locate(err, schemaPath)
+	}
+
+	if options.SplitMode != "" {
+		return splitOutput(source, destination, options)
+	}
+
+	source, err = formatSource(destination, source, options)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(destination, source, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTypesTo generates Go types from the contents of a JSON/YAML schema and writes them
+// to w, so that code can be generated in memory or to destinations other than files. The
+// schema is parsed as JSON, falling back to YAML, and the generated code notice names
+// GeneratorOptions.SchemaName, if set. SplitMode is not supported, since it produces several
+// files.
+func GenerateTypesTo(w io.Writer, schema []byte, options *GeneratorOptions) error {
+	options, err := prepareOptions(options)
+	if err != nil {
+		return err
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q writes several files and requires GenerateTypes", options.SplitMode)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(schema, &document); err != nil {
+		if err := yaml.Unmarshal(schema, &document); err != nil {
+			return fmt.Errorf("failed to parse schema: %w", err)
+		}
+	}
+
+	source, err := generateSource(options.SchemaName, schema, document, options)
+	if err != nil {
+		return err
+	}
+
+	source, err = formatSource(options.PackageName+".go", source, options)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(source); err != nil {
+		return fmt.Errorf("failed to write generated code: %w", err)
+	}
+
+	return nil
+}
+
+// decodeSchema decodes the contents of a .json, .yaml or .yml schema file
+func decodeSchema(schemaPath string, data []byte) (map[string]any, error) {
+	var schema map[string]any
+	switch {
+	case strings.HasSuffix(schemaPath, ".json"):
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON schema: %w", codegen.DecodeError(schemaPath, data, err))
+		}
+	case strings.HasSuffix(schemaPath, ".yaml"), strings.HasSuffix(schemaPath, ".yml"):
+		if err := yaml.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML schema: %w", codegen.DecodeError(schemaPath, data, err))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported schema format: must be .json, .yaml, or .yml")
+	}
+	return schema, nil
+}
+
+// prepareOptions fills in the defaults of options and validates them. A nil options yields
+// the default configuration.
+func prepareOptions(options *GeneratorOptions) (*GeneratorOptions, error) {
 	if options == nil {
 		options = &GeneratorOptions{
 			PackageName:     "types",
@@ -65,74 +224,221 @@ func GenerateTypes(destination string, schemaPath string, options *GeneratorOpti
 		options.PackageName = "types"
 	}
 
-	acronyms := DefaultAcronyms()
-	for k, v := range options.CustomAcronyms {
-		acronyms[k] = v
+	switch options.OmitMode {
+	case "":
+		options.OmitMode = omitEmpty
+	case omitEmpty, omitZero, omitBoth:
+	default:
+		return nil, fmt.Errorf("unsupported omit mode %q: must be %s, %s or %s", options.OmitMode, omitEmpty, omitZero, omitBoth)
 	}
-	data, err := os.ReadFile(schemaPath)
-	if err != nil {
-		return fmt.Errorf("failed to read schema file: %w", err)
+
+	if err := validateTagKeys(options.Tags); err != nil {
+		return nil, err
+	}
+	if _, err := parseTagTemplates(options.TagTemplates); err != nil {
+		return nil, err
 	}
 
-	var schema map[string]any
+	switch options.SplitMode {
+	case "", splitByKind, splitByType:
+	default:
+		return nil, fmt.Errorf("unsupported split mode %q: must be %s or %s", options.SplitMode, splitByKind, splitByType)
+	}
 
-	switch {
-	case strings.HasSuffix(schemaPath, ".json"):
-		if err := json.Unmarshal(data, &schema); err != nil {
-			return fmt.Errorf("failed to parse JSON schema: %w", err)
+	if err := validateBuildConstraint(options.BuildConstraint); err != nil {
+		return nil, err
+	}
+
+	if err := validateTypePatterns(options.IncludeTypes); err != nil {
+		return nil, err
+	}
+	if err := validateTypePatterns(options.ExcludeTypes); err != nil {
+		return nil, err
+	}
+	if err := validateTypePatterns(options.BenchmarkTypes); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// LoadDefinitions reads a JSON/YAML schema file and returns its definitions as the generator
+// sees them before writing Go code: with the Params and Result definitions of OpenRPC methods,
+// x-go-name and NamingRules.Names renames applied, filtered by IncludeTypes and ExcludeTypes,
+// and inline objects and unions hoisted into definitions named like their Go types. Refs name
+// their definition in their last segment. Generators of other languages use it to declare the
+// same types as the Go code, under the same names.
+func LoadDefinitions(schemaPath string, options *GeneratorOptions) (map[string]any, error) {
+	options, err := prepareOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loadSchemaFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	if options.PreserveOrder {
+		data, err := os.ReadFile(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file: %w", err)
 		}
-	case strings.HasSuffix(schemaPath, ".yaml"), strings.HasSuffix(schemaPath, ".yml"):
-		if err := yaml.Unmarshal(data, &schema); err != nil {
-			return fmt.Errorf("failed to parse YAML schema: %w", err)
+		if err := recordPropertyOrder(data, schema); err != nil {
+			return nil, fmt.Errorf("failed to read property order: %w", err)
 		}
-	default:
-		return fmt.Errorf("unsupported schema format: must be .json, .yaml, or .yml")
 	}
 
+	spellings := wordSpellings(options)
+	definitions, _, _, err := prepareDefinitions(schema, options, spellings)
+	if err != nil {
+		return nil, codegen.Locate(err, schemaPath)
+	}
+	hoistInlineSchemas(definitions, spellings)
+	if options.ReadWriteVariants {
+		addAccessVariants(definitions)
+	}
+	if err := runPostIR(definitions, options); err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// prepareDefinitions extracts the definitions of a schema document, adding those of the
+// OpenRPC methods, and resolves, renames and filters them, as every generator built on the
+// definitions needs
+func prepareDefinitions(schema map[string]any, options *GeneratorOptions, spellings map[string]string) (map[string]any, []rpcMethod, []rpcError, error) {
 	definitions := extractDefinitions(schema)
-	if len(definitions) == 0 {
-		return fmt.Errorf("schema does not contain any type definitions")
+	var methods []rpcMethod
+	var rpcErrors []rpcError
+	if isOpenRPCDocument(schema) {
+		methods = addMethodDefinitions(schema, definitions, spellings)
+		rpcErrors = documentErrors(schema, definitions, methods, spellings)
+	}
+	if len(definitions) == 0 && len(methods) == 0 {
+		return nil, nil, nil, &codegen.SchemaError{Err: errors.New("schema does not contain any type definitions")}
+	}
+
+	if options.ResolveRemoteRefs {
+		if err := resolveRemoteRefs(schema, definitions, options); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
-	outputFile, err := os.Create(destination)
+	if err := applyImportMappings(definitions, options.ImportMappings); err != nil {
+		return nil, nil, nil, err
+	}
+
+	applyForcedNames(definitions, options)
+
+	if err := applyDefinitionNames(schema, definitions); err != nil {
+		return nil, nil, nil, err
+	}
+	transliterateDefinitionNames(definitions, options)
+
+	if err := filterDefinitions(definitions, options); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return definitions, methods, rpcErrors, nil
+}
+
+// generateSource generates the unformatted Go source of a decoded schema document. data is
+// the document's source, used to recover the property order when PreserveOrder is set and
+// hashed into the generated code notice; schemaName is the path the notice names, if any.
+func generateSource(schemaName string, data []byte, schema map[string]any, options *GeneratorOptions) ([]byte, error) {
+	spellings := wordSpellings(options)
+
+	templates, err := loadTemplates(options.TemplateDir)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return nil, err
 	}
-	defer func() {
-		if closeErr := outputFile.Close(); closeErr != nil {
-			fmt.Printf("Warning: Failed to close output file: %v\n", closeErr)
+
+	if options.PreserveOrder {
+		if err := recordPropertyOrder(data, schema); err != nil {
+			return nil, fmt.Errorf("failed to read property order: %w", err)
 		}
-	}()
+	}
+
+	definitions, methods, rpcErrors, err := prepareDefinitions(schema, options, spellings)
+	if err != nil {
+		return nil, err
+	}
+
+	renameReservedDefinitions(definitions, options)
+
+	hoistInlineSchemas(definitions, spellings)
 
-	needsTime := false
+	if options.ReadWriteVariants {
+		addAccessVariants(definitions)
+	}
+
+	if err := runPostIR(definitions, options); err != nil {
+		return nil, err
+	}
+
+	durationType := applyFormatMappings(definitions, options)
+
+	needsUnions := containsUnionType(definitions, spellings)
+	helpers := usedUnionHelpers(definitions, spellings)
+
+	needsOverflow := containsOverflowStruct(definitions)
+	needsConstMarshaler := containsConstStruct(definitions)
+	needsTuples := containsTupleType(definitions)
+
+	imports := newImportManager()
 	for _, definition := range definitions {
 		if defMap, ok := definition.(map[string]any); ok {
-			if containsTimeType(defMap) {
-				needsTime = true
-				break
-			}
+			collectFormatImports(defMap, imports)
 		}
 	}
+	if helpers.variants {
+		imports.add("bytes")
+	}
+	if needsUnions || needsOverflow || needsConstMarshaler || needsTuples {
+		imports.add("encoding/json")
+	}
+	if needsUnions || needsOverflow || needsTuples {
+		imports.add("fmt")
+	}
+	if durationType != "" {
+		imports.add(durationHelperImports...)
+	}
+	inlineEnums := extractInlineEnums(definitions, spellings)
+	imports.add(enumImports(definitions, inlineEnums, options)...)
 
-	header := fmt.Sprintf(`// Code generated from JSON schema. DO NOT EDIT.
-package %s
+	imports.add(zeroGuardImports(definitions, options)...)
 
-`, options.PackageName)
+	needsValidation := options.GenerateValidate && containsStruct(definitions)
+	if needsValidation {
+		imports.add(validationImports(definitions)...)
+	}
+	imports.add(extensionImports(definitions)...)
 
-	if needsTime {
-		header += `import "time"
+	if len(methods) > 0 {
+		imports.add("encoding/json", "errors", "fmt")
+		if containsPositionalParams(definitions) {
+			imports.add("bytes")
+		}
+		if options.RPCClient {
+			imports.add(rpcClientImports...)
+		}
+		if options.RPCServer {
+			imports.add(rpcServerImports...)
+		}
+	}
 
-`
+	needsSamples := options.ExampleFactories && (containsStruct(definitions) || needsUnions)
+	if needsSamples {
+		imports.add("encoding/json", "fmt")
 	}
 
-	if _, err := outputFile.WriteString(header); err != nil {
-		return fmt.Errorf("failed to write file header: %w", err)
+	out := new(bytes.Buffer)
+	if err := executeTemplate(out, templates, headerTemplate, headerData(schemaName, data, imports.render(), options)); err != nil {
+		return nil, err
 	}
 
 	processedTypes := map[string]bool{}
 
-	inlineEnums := extractInlineEnums(definitions, acronyms)
-
 	inlineEnumNames := make([]string, 0, len(inlineEnums))
 	for enumName := range inlineEnums {
 		inlineEnumNames = append(inlineEnumNames, enumName)
@@ -141,8 +447,8 @@ package %s
 
 	for _, enumName := range inlineEnumNames {
 		enumDef := inlineEnums[enumName]
-		if err := generateEnumType(outputFile, enumName, enumDef.typeInfo, enumDef.values, acronyms, options); err != nil {
-			return err
+		if err := generateEnumType(out, templates, enumName, enumDef.typeInfo, enumDef.values, spellings, options); err != nil {
+			return nil, err
 		}
 		processedTypes[enumName] = true
 	}
@@ -161,6 +467,10 @@ package %s
 			continue
 		}
 
+		if isOverriddenDefinition(defMap) {
+			continue
+		}
+
 		isEnum := false
 		var enumValues []any
 		if enum, ok := defMap["enum"].([]any); ok && len(enum) > 0 {
@@ -172,13 +482,14 @@ package %s
 			continue
 		}
 
-		if err := generateEnumType(outputFile, typeName, defMap, enumValues, acronyms, options); err != nil {
-			return err
+		if err := generateEnumType(out, templates, typeName, defMap, enumValues, spellings, options); err != nil {
+			return nil, err
 		}
 
 		processedTypes[typeName] = true
 	}
 
+	var structs, unions []string
 	for _, typeName := range typeNames {
 		definition := definitions[typeName]
 
@@ -187,23 +498,77 @@ package %s
 			continue
 		}
 
-		if processedTypes[typeName] {
+		if processedTypes[typeName] || isOverriddenDefinition(defMap) {
 			continue
 		}
 
-		if err := generateComplexType(outputFile, typeName, defMap, definitions, acronyms, options); err != nil {
-			return err
+		if err := generateComplexType(out, templates, typeName, defMap, definitions, spellings, options); err != nil {
+			return nil, err
+		}
+		if isStructDefinition(defMap, definitions) && paramStructure(defMap) != paramsByPosition {
+			structs = append(structs, typeName)
+		} else if isUnionDefinition(typeName, defMap, definitions, spellings) {
+			unions = append(unions, typeName)
+		}
+	}
+
+	if len(methods) > 0 {
+		if err := generateRPCEnvelopes(out, methods, rpcErrors, definitions, spellings); err != nil {
+			return nil, err
+		}
+		if options.RPCClient {
+			if err := generateRPCClient(out, methods, definitions); err != nil {
+				return nil, err
+			}
+		}
+		if options.RPCServer {
+			if err := generateRPCServer(out, methods, definitions); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	if options.FormatOutput {
-		cmd := exec.Command("go", "fmt", destination)
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("Warning: Failed to format %s: %v\n", destination, err)
+	if err := generateUnionHelpers(out, helpers); err != nil {
+		return nil, err
+	}
+
+	if durationType != "" {
+		if err := generateDurationHelpers(out, durationType); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	if needsValidation {
+		if err := generateValidationHelpers(out); err != nil {
+			return nil, err
+		}
+	}
+
+	if needsSamples {
+		if err := generateSampleHelpers(out); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.ContractTests != nil {
+		if err := generateContractTests(templates, schemaName, data, definitions, structs, unions, methods, options); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.FuzzTests != nil {
+		if err := generateFuzzTests(templates, schemaName, data, out.Bytes(), definitions, options); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.BenchmarkTests != nil && len(options.BenchmarkTypes) > 0 {
+		if err := generateBenchmarks(templates, schemaName, data, out.Bytes(), definitions, options); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
 }
 
 // inlineEnumDef holds information about an inline enum extracted from a struct property
@@ -214,7 +579,7 @@ type inlineEnumDef struct {
 
 // extractInlineEnums scans all definitions for inline enums in struct properties
 // and extracts them as separate enum types
-func extractInlineEnums(definitions map[string]any, acronyms map[string]bool) map[string]inlineEnumDef {
+func extractInlineEnums(definitions map[string]any, spellings map[string]string) map[string]inlineEnumDef {
 	inlineEnums := make(map[string]inlineEnumDef)
 
 	defNames := make([]string, 0, len(definitions))
@@ -230,10 +595,7 @@ func extractInlineEnums(definitions map[string]any, acronyms map[string]bool) ma
 			continue
 		}
 
-		properties, ok := defMap["properties"].(map[string]any)
-		if !ok {
-			continue
-		}
+		properties := collectProperties(defMap)
 
 		propNames := make([]string, 0, len(properties))
 		for propName := range properties {
@@ -248,16 +610,28 @@ func extractInlineEnums(definitions map[string]any, acronyms map[string]bool) ma
 				continue
 			}
 
+			if _, overridden := goTypeOverride(propMap); overridden {
+				continue
+			}
+
 			if enumValues, ok := propMap["enum"].([]any); ok && len(enumValues) > 0 {
-				enumTypeName := deriveEnumTypeName(enumValues, propName, acronyms)
+				enumTypeName := deriveEnumTypeName(enumValues, propName, spellings)
 
 				if _, exists := inlineEnums[enumTypeName]; !exists {
+					enumType := schemaType(propMap)
+					if enumType == "" {
+						enumType = "string"
+					}
+					typeInfo := map[string]any{
+						"description": propMap["description"],
+						"type":        enumType,
+					}
+					if varNames, ok := propMap["x-enum-varnames"]; ok {
+						typeInfo["x-enum-varnames"] = varNames
+					}
 					inlineEnums[enumTypeName] = inlineEnumDef{
-						values: enumValues,
-						typeInfo: map[string]any{
-							"description": propMap["description"],
-							"type":        "string",
-						},
+						values:   enumValues,
+						typeInfo: typeInfo,
 					}
 				}
 			}
@@ -270,7 +644,7 @@ func extractInlineEnums(definitions map[string]any, acronyms map[string]bool) ma
 // deriveEnumTypeName derives a meaningful enum type name from enum values or property name
 // It tries to extract a common prefix from enum values (e.g., "TASK_STATE_XXX" -> "TaskState")
 // If no common prefix is found, it uses the property name
-func deriveEnumTypeName(enumValues []any, propName string, acronyms map[string]bool) string {
+func deriveEnumTypeName(enumValues []any, propName string, spellings map[string]string) string {
 	var stringValues []string
 	for _, val := range enumValues {
 		if strVal, ok := val.(string); ok {
@@ -279,19 +653,19 @@ func deriveEnumTypeName(enumValues []any, propName string, acronyms map[string]b
 	}
 
 	if len(stringValues) == 0 {
-		return convertToGoFieldName(propName, acronyms)
+		return convertToGoFieldName(propName, spellings)
 	}
 
 	commonPrefix := findCommonPrefix(stringValues)
 	if commonPrefix != "" {
 		commonPrefix = strings.TrimSuffix(commonPrefix, "_")
-		typeName := convertToGoFieldName(commonPrefix, acronyms)
+		typeName := convertToGoFieldName(commonPrefix, spellings)
 		if typeName != "" && typeName != "Field" {
 			return typeName
 		}
 	}
 
-	return convertToGoFieldName(propName, acronyms)
+	return convertToGoFieldName(propName, spellings)
 }
 
 // findCommonPrefix finds the common prefix of all strings
@@ -325,6 +699,20 @@ func findCommonPrefix(strs []string) string {
 	return ""
 }
 
+// definitionPointer returns the JSON pointer to a definition of a schema document, in the
+// container extractDefinitions takes it from, or "" for the definitions the document does not
+// declare, such as those of OpenRPC methods
+func definitionPointer(schema map[string]any, name string) string {
+	pointer := ""
+	for _, container := range definitionContainers {
+		target, err := resolvePointer(schema, container)
+		if definitions, ok := target.(map[string]any); err == nil && ok && definitions[name] != nil {
+			pointer = container + "/" + escapePointerToken(name)
+		}
+	}
+	return pointer
+}
+
 // extractDefinitions extracts type definitions from various schema structures
 func extractDefinitions(schema map[string]any) map[string]any {
 	definitions := make(map[string]any)
@@ -372,32 +760,26 @@ func extractDefinitions(schema map[string]any) map[string]any {
 }
 
 // generateEnumType generates an enum type definition
-func generateEnumType(outputFile *os.File, typeName string, defMap map[string]any, enumValues []any, acronyms map[string]bool, options *GeneratorOptions) error {
-	description := ""
-	if desc, ok := defMap["description"].(string); ok {
-		description = desc
-	}
-
-	if description != "" && options.IncludeComments {
-		formattedDescription := formatDescription(description)
-		if _, err := outputFile.WriteString(formattedDescription + "\n"); err != nil {
-			return err
-		}
-	}
-
-	typeStr := "string"
-	if t, ok := defMap["type"].(string); ok {
-		typeStr = t
+func generateEnumType(out *bytes.Buffer, templates *template.Template, typeName string, defMap map[string]any, enumValues []any, spellings map[string]string, options *GeneratorOptions) error {
+	data := EnumTemplateData{
+		Name:        typeName,
+		Type:        "string",
+		Comment:     typeComment(defMap, options),
+		Description: schemaDescription(defMap),
+		Deprecated:  isDeprecated(defMap),
+		Extensions:  VendorExtensions(defMap),
 	}
 
-	typeDecl := fmt.Sprintf("type %s %s\n\n", typeName, typeStr)
-	if _, err := outputFile.WriteString(typeDecl); err != nil {
-		return err
+	if values, ok := integerEnumValues(enumValues); ok && schemaType(defMap) != "number" {
+		data.Type = "int"
+		return generateIntegerEnum(out, templates, data, defMap, values, spellings, options)
 	}
 
-	constDecl := fmt.Sprintf("// %s enum values\nconst (\n", typeName)
-	if _, err := outputFile.WriteString(constDecl); err != nil {
-		return err
+	switch schemaType(defMap) {
+	case "number":
+		data.Type = "float64"
+	case "boolean":
+		data.Type = "bool"
 	}
 
 	enumStrings := make([]string, 0, len(enumValues))
@@ -413,87 +795,178 @@ func generateEnumType(outputFile *os.File, typeName string, defMap map[string]an
 		commonPrefix = strings.TrimSuffix(commonPrefix, "_")
 	}
 
+	constNames := make([]string, 0, len(enumStrings))
 	for _, val := range enumStrings {
 		constName := val
 		if commonPrefix != "" && strings.HasPrefix(val, commonPrefix+"_") {
 			constName = strings.TrimPrefix(val, commonPrefix+"_")
 		}
+		constName = typeName + convertToGoFieldName(constName, spellings)
+		constNames = append(constNames, constName)
+		data.Values = append(data.Values, EnumTemplateValue{Name: constName, Value: `"` + val + `"`})
+	}
+
+	if err := executeTemplate(out, templates, enumTemplate, data); err != nil {
+		return err
+	}
 
-		enumVal := fmt.Sprintf("\t%s%s %s = \"%s\"\n", typeName, convertToGoFieldName(constName, acronyms), typeName, val)
-		if _, err := outputFile.WriteString(enumVal); err != nil {
+	if options.EnumHelpers && len(constNames) > 0 {
+		if err := generateEnumHelpers(out, typeName, constNames); err != nil {
 			return err
 		}
 	}
 
-	if _, err := outputFile.WriteString(")\n\n"); err != nil {
-		return err
+	if options.StrictEnums && len(constNames) > 0 {
+		return generateStrictEnumUnmarshaler(out, typeName, constNames, enumStrings)
 	}
 
 	return nil
 }
 
 // generateComplexType generates struct, interface, or other complex type definitions
-func generateComplexType(outputFile *os.File, typeName string, defMap map[string]any, definitions map[string]any, acronyms map[string]bool, options *GeneratorOptions) error {
-	description := ""
-	if desc, ok := defMap["description"].(string); ok {
-		description = desc
+func generateComplexType(out *bytes.Buffer, templates *template.Template, typeName string, defMap map[string]any, definitions map[string]any, spellings map[string]string, options *GeneratorOptions) error {
+	alias := AliasTemplateData{
+		Name:        typeName,
+		Comment:     typeComment(defMap, options),
+		Description: schemaDescription(defMap),
+		Deprecated:  isDeprecated(defMap),
+		Extensions:  VendorExtensions(defMap),
 	}
 
-	if description != "" && options.IncludeComments {
-		formattedDescription := formatDescription(description)
-		if _, err := outputFile.WriteString(formattedDescription + "\n"); err != nil {
-			return err
+	overflowType, hasOverflow := overflowValueType(defMap, definitions)
+	var declared []string
+	if hasOverflow {
+		declared = declaredPropertyNames(defMap, definitions, map[string]bool{})
+	}
+
+	withDefaults := options.GenerateDefaults && hasDefaults(defMap, definitions, map[string]bool{})
+
+	var embedded []string
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, ok := mergeAllOf(defMap, definitions)
+		if !ok || (merged.aliasOf != "" && isRecursiveField(typeName, merged.aliasOf, definitions)) {
+			alias.Type = "any"
+			return executeTemplate(out, templates, aliasTemplate, alias)
+		}
+
+		if merged.aliasOf != "" {
+			alias.Type, alias.Alias = merged.aliasOf, true
+			return executeTemplate(out, templates, aliasTemplate, alias)
+		}
+
+		embedded = merged.embedded
+		defMap = map[string]any{
+			"properties": merged.properties,
+			"required":   merged.required,
+		}
+		if merged.ordered {
+			defMap[propertyOrderKey] = merged.order
 		}
 	}
 
-	if _, hasType := defMap["type"].(string); hasType {
-		if _, hasProperties := defMap["properties"]; !hasProperties {
-			goType := determineGoType(defMap, definitions)
-			typeDecl := fmt.Sprintf("type %s = %s\n\n", typeName, goType)
-			if _, err := outputFile.WriteString(typeDecl); err != nil {
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := defMap[key].([]any); ok && !isConstraintOnly(members) {
+			if nonNull := nonNullMembers(members); len(nonNull) == 1 {
+				if memberMap, ok := nonNull[0].(map[string]any); ok {
+					alias.Type, alias.Alias = determineGoType(memberMap, definitions), true
+					return executeTemplate(out, templates, aliasTemplate, alias)
+				}
+			}
+		}
+	}
+
+	if members, hasOneOf := defMap["oneOf"].([]any); hasOneOf && !isConstraintOnly(members) {
+		if plan, ok := planOneOf(typeName, defMap, definitions, spellings, map[string]bool{}); ok {
+			if _, err := out.WriteString(alias.Comment); err != nil {
 				return err
 			}
+			if err := generateOneOfType(out, typeName, plan); err != nil {
+				return err
+			}
+			if options.ExampleFactories {
+				return generateExampleFactories(out, typeName, definitions)
+			}
 			return nil
 		}
+
+		alias.Type = "any"
+		return executeTemplate(out, templates, aliasTemplate, alias)
 	}
 
-	if _, hasAnyOf := defMap["anyOf"]; hasAnyOf {
-		typeDecl := fmt.Sprintf("type %s any\n\n", typeName)
-		if _, err := outputFile.WriteString(typeDecl); err != nil {
-			return err
+	if members, hasAnyOf := defMap["anyOf"].([]any); hasAnyOf && !isConstraintOnly(members) {
+		if fields, ok := planAnyOf(defMap, definitions, spellings); ok {
+			if _, err := out.WriteString(alias.Comment); err != nil {
+				return err
+			}
+			if err := generateAnyOfType(out, typeName, fields); err != nil {
+				return err
+			}
+			if options.ExampleFactories {
+				return generateExampleFactories(out, typeName, definitions)
+			}
+			return nil
 		}
-		return nil
+
+		alias.Type = "any"
+		return executeTemplate(out, templates, aliasTemplate, alias)
 	}
 
-	if _, hasOneOf := defMap["oneOf"]; hasOneOf {
-		typeDecl := fmt.Sprintf("type %s any\n\n", typeName)
-		if _, err := outputFile.WriteString(typeDecl); err != nil {
+	if isConstDefinition(defMap) {
+		if _, err := out.WriteString(alias.Comment); err != nil {
 			return err
 		}
-		return nil
+		return generateConstType(out, typeName, defMap)
 	}
 
-	if _, hasAllOf := defMap["allOf"]; hasAllOf {
-		typeDecl := fmt.Sprintf("type %s any\n\n", typeName)
-		if _, err := outputFile.WriteString(typeDecl); err != nil {
+	if isTupleSchema(defMap) {
+		if _, err := out.WriteString(alias.Comment); err != nil {
 			return err
 		}
-		return nil
+		return generateTupleType(out, typeName, defMap, definitions, spellings)
 	}
 
-	structDef := fmt.Sprintf("type %s struct {\n", typeName)
-	if _, err := outputFile.WriteString(structDef); err != nil {
-		return err
+	if schemaType(defMap) != "" {
+		if _, hasProperties := defMap["properties"]; !hasProperties {
+			alias.Type = determineGoType(defMap, definitions)
+			alias.Alias = primitiveAlias(defMap, alias.Type, options)
+			return executeTemplate(out, templates, aliasTemplate, alias)
+		}
 	}
 
-	properties, ok := defMap["properties"].(map[string]any)
-	if ok {
-		propNames := make([]string, 0, len(properties))
-		for propName := range properties {
-			propNames = append(propNames, propName)
+	structData := StructTemplateData{
+		Name:        typeName,
+		Comment:     alias.Comment,
+		Description: alias.Description,
+		Deprecated:  alias.Deprecated,
+		Extensions:  alias.Extensions,
+		Embedded:    embedded,
+	}
+
+	var consts []constField
+	var constDecls []string
+	var defaultStmts []string
+	overwritesZero := false // A default is applied to a field stored by value
+	var validationStmts []string
+	var validationDecls []string
+	var params []constructorParam
+	var getters []getterField
+	fieldNames := make(map[string]bool)
+	if options.Constructors {
+		for _, embeddedType := range embedded {
+			params = append(params, constructorParam{fieldName: embeddedType, fieldType: embeddedType})
 		}
-		sort.Strings(propNames)
+	}
 
+	takenNames := make(map[string]bool)
+	for _, embeddedType := range embedded {
+		takenNames[embeddedType[strings.LastIndex(embeddedType, ".")+1:]] = true
+	}
+	if hasOverflow {
+		takenNames["AdditionalProperties"] = true
+	}
+
+	properties, ok := defMap["properties"].(map[string]any)
+	if ok {
 		requiredFields := make(map[string]bool)
 		if required, ok := defMap["required"].([]any); ok {
 			for _, field := range required {
@@ -503,45 +976,186 @@ func generateComplexType(outputFile *os.File, typeName string, defMap map[string
 			}
 		}
 
-		for _, propName := range propNames {
+		for _, propName := range propertyNames(defMap, properties) {
 			propDef := properties[propName]
 			propMap, ok := propDef.(map[string]any)
 			if !ok {
 				continue
 			}
 
-			fieldName := convertToGoFieldName(propName, acronyms)
+			fieldName, reason := goFieldName(propName, propMap, spellings), ""
+			if reservedFieldNames[fieldName] {
+				fieldName, reason = fieldName+"Field", "generated method name"
+			}
+			if unique := uniqueFieldName(fieldName, takenNames); unique != fieldName {
+				fieldName, reason = unique, "duplicate field name"
+			}
+			if reason != "" {
+				reportRename(options.RenameReport, "property", typeName+"."+propName, fieldName, reason)
+			}
 
 			var propType string
-			if enumValues, hasEnum := propMap["enum"].([]any); hasEnum && len(enumValues) > 0 {
-				propType = deriveEnumTypeName(enumValues, propName, acronyms)
+			if _, overridden := goTypeOverride(propMap); overridden {
+				propType = determineGoType(propMap, definitions)
+			} else if enumValues, hasEnum := propMap["enum"].([]any); hasEnum && len(enumValues) > 0 {
+				propType = deriveEnumTypeName(enumValues, propName, spellings)
+				if isNullable(propMap) {
+					propType = "*" + propType
+				}
 			} else {
 				propType = determineGoType(propMap, definitions)
 			}
 
-			if !requiredFields[propName] && !hasDefaultValue(propMap) {
-				if !strings.HasPrefix(propType, "*") && !strings.HasPrefix(propType, "[]") && !strings.HasPrefix(propType, "map[") {
+			constName, isConst := constPropertyName(typeName, fieldName, propMap, definitions)
+			if isConst {
+				consts = append(consts, constField{fieldName: fieldName, constName: constName})
+				if goType, literal, ok := constLiteral(propMap["const"]); ok {
+					if _, hasType := propMap["type"]; hasType {
+						goType = propType
+					}
+					constDecls = append(constDecls, fmt.Sprintf("%s %s = %s", constName, goType, literal))
+				}
+			}
+
+			optional := !requiredFields[propName] && !hasDefaultValue(propMap) && !isConst
+			valueStruct := optional && optionalValueStruct(typeName, propMap, definitions, options)
+			if optional {
+				if !valueStruct && !strings.HasPrefix(propType, "*") && !strings.HasPrefix(propType, "[]") && !strings.HasPrefix(propType, "map[") {
 					propType = "*" + propType
 				}
+			} else if _, isRef := propMap["$ref"]; isRef && isRecursiveField(typeName, propType, definitions) {
+				propType = "*" + propType
 			}
 
-			jsonTag := fmt.Sprintf("`json:\"%s", propName)
-			if !requiredFields[propName] {
-				jsonTag += ",omitempty"
+			if withDefaults && !isConst {
+				if statement, ok := defaultStatement(fieldName, propType, propMap, definitions); ok {
+					defaultStmts = append(defaultStmts, statement)
+					overwritesZero = overwritesZero || !strings.HasPrefix(propType, "*")
+				} else if statement, ok := nestedDefaultStatement(fieldName, propType, propMap, definitions); ok {
+					if valueStruct {
+						statement = zeroGuard(fieldName, statement)
+					}
+					defaultStmts = append(defaultStmts, statement)
+				}
 			}
-			jsonTag += "\"`"
 
-			propDefStr := fmt.Sprintf("\t%s %s %s\n", fieldName, propType, jsonTag)
-			if _, err := outputFile.WriteString(propDefStr); err != nil {
+			fieldNames[fieldName] = true
+			if options.Getters && strings.HasPrefix(propType, "*") {
+				getters = append(getters, getterField{fieldName: fieldName, fieldType: propType})
+			}
+
+			if options.Constructors && requiredFields[propName] && !isConst {
+				params = append(params, constructorParam{fieldName: fieldName, fieldType: propType})
+			}
+
+			tags, err := fieldTags(tagTemplateData{
+				Name:     propName,
+				GoName:   fieldName,
+				Type:     propType,
+				Required: requiredFields[propName],
+			}, !requiredFields[propName], options)
+			if err != nil {
 				return err
 			}
+			if options.ValidateTags {
+				if rules := validateRules(propMap, propType, requiredFields[propName], definitions); rules != "" {
+					tags = append(tags, fmt.Sprintf("validate:%q", rules))
+				}
+			}
+
+			if options.GenerateValidate && !isConst {
+				field := fieldValidation{
+					path:      propName,
+					fieldName: fieldName,
+					fieldType: propType,
+					nullable:  isNullable(propMap),
+					required:  requiredFields[propName],
+				}
+				statements, declarations := validationStatements(typeName, field, propMap, definitions)
+				if statements != "" && valueStruct {
+					statements = zeroGuard(fieldName, statements)
+				}
+				if statements != "" {
+					validationStmts = append(validationStmts, statements)
+				}
+				if declarations != "" {
+					validationDecls = append(validationDecls, declarations)
+				}
+			}
+
+			structData.Fields = append(structData.Fields, StructTemplateField{
+				Name:        fieldName,
+				Type:        propType,
+				Tag:         strings.Join(tags, " "),
+				JSONName:    propName,
+				Required:    requiredFields[propName],
+				Comment:     fieldComment(propMap, definitions, options),
+				Description: schemaDescription(propMap),
+				Extensions:  VendorExtensions(propMap),
+			})
 		}
 	}
 
-	if _, err := outputFile.WriteString("}\n\n"); err != nil {
+	if hasOverflow {
+		structData.Fields = append(structData.Fields, StructTemplateField{
+			Name: "AdditionalProperties",
+			Type: "map[string]" + overflowType,
+			Tag:  strings.Join(ignoredFieldTags(options), " "),
+		})
+	}
+
+	if err := executeTemplate(out, templates, structTemplate, structData); err != nil {
+		return err
+	}
+
+	if err := generateConstDeclarations(out, typeName, constDecls); err != nil {
 		return err
 	}
 
+	if withDefaults {
+		if err := generateApplyDefaults(out, typeName, embedded, definitions, defaultStmts, overwritesZero); err != nil {
+			return err
+		}
+	}
+
+	if options.GenerateValidate {
+		if err := generateValidateMethod(out, typeName, embedded, validationStmts, validationDecls); err != nil {
+			return err
+		}
+	}
+
+	if _, taken := definitions["New"+typeName]; options.Constructors && !taken {
+		if err := generateConstructor(out, typeName, params, consts, withDefaults); err != nil {
+			return err
+		}
+	}
+
+	if options.Getters {
+		var safe []getterField
+		for _, getter := range getters {
+			if !fieldNames["Get"+getter.fieldName] {
+				safe = append(safe, getter)
+			}
+		}
+		if err := generateGetters(out, typeName, safe, definitions); err != nil {
+			return err
+		}
+	}
+
+	if options.ExampleFactories {
+		if err := generateExampleFactories(out, typeName, definitions); err != nil {
+			return err
+		}
+	}
+
+	if hasOverflow {
+		return generateOverflowMethods(out, typeName, overflowType, declared, consts)
+	}
+
+	if len(consts) > 0 {
+		return generateConstMarshaler(out, typeName, consts)
+	}
+
 	return nil
 }
 
@@ -551,6 +1165,45 @@ func hasDefaultValue(propMap map[string]any) bool {
 	return hasDefault
 }
 
+// deprecationNotice is the Go deprecation paragraph emitted for schemas marked deprecated
+const deprecationNotice = "Deprecated: marked as deprecated in the schema."
+
+// isDeprecated reports whether a schema is marked with `deprecated: true`
+func isDeprecated(schema map[string]any) bool {
+	deprecated, _ := schema["deprecated"].(bool)
+	return deprecated
+}
+
+// typeComment returns the doc comment of a generated type: its description (when comments
+// are enabled), its vendor extensions (when extension comments are enabled) and a deprecation
+// paragraph if the schema is deprecated
+func typeComment(defMap map[string]any, options *GeneratorOptions) string {
+	var lines []string
+	if description, ok := defMap["description"].(string); ok && description != "" && options.IncludeComments {
+		lines = append(lines, formatDescription(description))
+	}
+	if options.ExtensionComments {
+		if extensions := ExtensionComments(VendorExtensions(defMap)); len(extensions) > 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "//")
+			}
+			for _, extension := range extensions {
+				lines = append(lines, "// "+extension)
+			}
+		}
+	}
+	if isDeprecated(defMap) {
+		if len(lines) > 0 {
+			lines = append(lines, "//")
+		}
+		lines = append(lines, "// "+deprecationNotice)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
 // formatDescription formats a description string as proper Go comments
 // with each line prefixed by "// "
 func formatDescription(description string) string {
@@ -572,7 +1225,7 @@ func formatDescription(description string) string {
 }
 
 // convertToGoFieldName converts a JSON property name to a properly capitalized Go field name
-func convertToGoFieldName(name string, acronyms map[string]bool) string {
+func convertToGoFieldName(name string, spellings map[string]string) string {
 	if name == "" {
 		return ""
 	}
@@ -591,10 +1244,11 @@ func convertToGoFieldName(name string, acronyms map[string]bool) string {
 	name = strings.ReplaceAll(name, "-", "_")
 	name = strings.ReplaceAll(name, ".", "_")
 	name = strings.ReplaceAll(name, " ", "_")
+	name = strings.ReplaceAll(name, "/", "_")
 
 	var cleanName strings.Builder
-	for _, r := range name {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+	for _, r := range Transliterate(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
 			cleanName.WriteRune(r)
 		}
 	}
@@ -603,15 +1257,14 @@ func convertToGoFieldName(name string, acronyms map[string]bool) string {
 	var parts []string
 	var current strings.Builder
 
-	for i, r := range name {
-		if i > 0 && (r >= 'A' && r <= 'Z') && current.Len() > 0 {
-			prevRune := rune(name[i-1])
-			if prevRune >= 'a' && prevRune <= 'z' {
-				parts = append(parts, current.String())
-				current.Reset()
-			}
+	var prevRune rune
+	for _, r := range name {
+		if unicode.IsUpper(r) && current.Len() > 0 && unicode.IsLower(prevRune) {
+			parts = append(parts, current.String())
+			current.Reset()
 		}
 		current.WriteRune(r)
+		prevRune = r
 	}
 
 	if current.Len() > 0 {
@@ -630,8 +1283,8 @@ func convertToGoFieldName(name string, acronyms map[string]bool) string {
 
 	for i, part := range finalParts {
 		lowerPart := strings.ToLower(part)
-		if acronyms[lowerPart] {
-			finalParts[i] = strings.ToUpper(lowerPart)
+		if spelling, ok := spellings[lowerPart]; ok {
+			finalParts[i] = spelling
 		} else {
 			finalParts[i] = cases.Title(language.English).String(lowerPart)
 		}
@@ -639,26 +1292,103 @@ func convertToGoFieldName(name string, acronyms map[string]bool) string {
 
 	result := strings.Join(finalParts, "")
 
-	if len(result) > 0 && result[0] >= 'a' && result[0] <= 'z' {
-		result = strings.ToUpper(string(result[0])) + result[1:]
+	if first, size := utf8.DecodeRuneInString(result); unicode.IsLower(first) {
+		result = string(unicode.ToUpper(first)) + result[size:]
 	}
 
-	if result == "" || (len(result) > 0 && result[0] >= '0' && result[0] <= '9') {
+	// Identifiers starting with a digit or a caseless letter (e.g. CJK) cannot be exported
+	if first, _ := utf8.DecodeRuneInString(result); result == "" || !unicode.IsUpper(first) {
 		result = "Field" + result
 	}
 
 	return result
 }
 
-// determineGoType determines the Go type for a JSON schema property
+// schemaType returns the JSON type of a schema, ignoring "null" in type arrays
+// (e.g. ["string", "null"] -> "string"). It returns an empty string when the schema
+// has no type or allows several non-null types.
+func schemaType(propMap map[string]any) string {
+	switch t := propMap["type"].(type) {
+	case string:
+		return t
+	case []any:
+		nonNull := ""
+		for _, item := range t {
+			itemType, ok := item.(string)
+			if !ok || itemType == "null" {
+				continue
+			}
+			if nonNull != "" {
+				return ""
+			}
+			nonNull = itemType
+		}
+		return nonNull
+	}
+	return ""
+}
+
+// isNullable reports whether a schema allows null: a type array containing "null", the
+// OpenAPI 3.0 `nullable: true` keyword, or a oneOf/anyOf with a single non-null member
+func isNullable(propMap map[string]any) bool {
+	if nullable, ok := propMap["nullable"].(bool); ok && nullable {
+		return true
+	}
+
+	if types, ok := propMap["type"].([]any); ok {
+		for _, item := range types {
+			if item == "null" {
+				return true
+			}
+		}
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := propMap[key].([]any); ok {
+			nonNull := nonNullMembers(members)
+			if len(nonNull) == 1 && len(members) > 1 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SchemaGoType returns the Go type a struct field generated for a property schema has,
+// resolving $refs against definitions (e.g. "string", "[]Task" or "*time.Time"). Other
+// generators use it to type what they declare next to the generated types.
+func SchemaGoType(schema map[string]any, definitions map[string]any) string {
+	return determineGoType(schema, definitions)
+}
+
+// determineGoType determines the Go type for a JSON schema property. Nullable scalar and
+// struct types are returned as pointers; slices and maps already represent null as nil.
 func determineGoType(propMap map[string]any, definitions map[string]any) string {
+	goType := determineNonNullGoType(propMap, definitions)
+	if isNullable(propMap) && goType != "any" && !strings.HasPrefix(goType, "*") && !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") {
+		return "*" + goType
+	}
+	return goType
+}
+
+// determineNonNullGoType determines the Go type for a JSON schema property without
+// accounting for nullability
+func determineNonNullGoType(propMap map[string]any, definitions map[string]any) string {
+	if goType, ok := goTypeOverride(propMap); ok {
+		return goType
+	}
+
 	if ref, ok := propMap["$ref"].(string); ok {
-		parts := strings.Split(ref, "/")
-		refType := parts[len(parts)-1]
-		return refType
+		if target, ok := definitions[refName(ref)].(map[string]any); ok {
+			if goType, ok := goTypeOverride(target); ok {
+				return goType
+			}
+		}
+		return refName(ref)
 	}
 
-	if propType, ok := propMap["type"].(string); ok && propType == "array" {
+	if propType := schemaType(propMap); propType == "array" {
 		if items, ok := propMap["items"].(map[string]any); ok {
 			itemType := determineGoType(items, definitions)
 			return "[]" + itemType
@@ -666,7 +1396,7 @@ func determineGoType(propMap map[string]any, definitions map[string]any) string
 		return "[]any"
 	}
 
-	if propType, ok := propMap["type"].(string); ok {
+	if propType := schemaType(propMap); propType != "" {
 		format := ""
 		if fmt, ok := propMap["format"].(string); ok {
 			format = fmt
@@ -740,19 +1470,25 @@ func determineGoType(propMap map[string]any, definitions map[string]any) string
 		}
 	}
 
-	if oneOf, ok := propMap["oneOf"].([]any); ok && len(oneOf) > 0 {
-		return "any"
-	}
-
-	if anyOf, ok := propMap["anyOf"].([]any); ok && len(anyOf) > 0 {
-		return "any"
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := propMap[key].([]any); ok && len(members) > 0 {
+			if nonNull := nonNullMembers(members); len(nonNull) == 1 {
+				if memberMap, ok := nonNull[0].(map[string]any); ok {
+					return determineNonNullGoType(memberMap, definitions)
+				}
+			}
+			return "any"
+		}
 	}
 
 	if allOf, ok := propMap["allOf"].([]any); ok && len(allOf) > 0 {
 		return "any"
 	}
 
-	if _, ok := propMap["const"]; ok {
+	if value, ok := propMap["const"]; ok {
+		if goType, _, ok := constLiteral(value); ok {
+			return goType
+		}
 		return "any"
 	}
 
@@ -776,7 +1512,8 @@ func determineGoType(propMap map[string]any, definitions map[string]any) string
 	return "any"
 }
 
-// GenerateA2ATypes provides backward compatibility with the original function
+// GenerateA2ATypes provides backward compatibility with the original function. The a2a
+// generator (codegen/a2a) also generates the protocol helpers.
 func GenerateA2ATypes(destination string, schemaPath string) error {
 	options := &GeneratorOptions{
 		PackageName:     "a2a",
@@ -827,17 +1564,21 @@ func ValidateSchema(schemaPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
+	return validateSchema(schemaPath, data)
+}
 
+// validateSchema implements ValidateSchema for the contents of a schema file
+func validateSchema(schemaPath string, data []byte) error {
 	var schema map[string]any
 
 	switch {
 	case strings.HasSuffix(schemaPath, ".json"):
 		if err := json.Unmarshal(data, &schema); err != nil {
-			return fmt.Errorf("invalid JSON schema: %w", err)
+			return fmt.Errorf("invalid JSON schema: %w", codegen.DecodeError(schemaPath, data, err))
 		}
 	case strings.HasSuffix(schemaPath, ".yaml"), strings.HasSuffix(schemaPath, ".yml"):
 		if err := yaml.Unmarshal(data, &schema); err != nil {
-			return fmt.Errorf("invalid YAML schema: %w", err)
+			return fmt.Errorf("invalid YAML schema: %w", codegen.DecodeError(schemaPath, data, err))
 		}
 	default:
 		return fmt.Errorf("unsupported schema format: must be .json, .yaml, or .yml")
@@ -845,48 +1586,8 @@ func ValidateSchema(schemaPath string) error {
 
 	definitions := extractDefinitions(schema)
 	if len(definitions) == 0 {
-		return fmt.Errorf("schema does not contain any type definitions")
+		return &codegen.SchemaError{File: schemaPath, Err: errors.New("schema does not contain any type definitions")}
 	}
 
 	return nil
 }
-
-// containsTimeType recursively checks if a schema definition contains time-related types
-func containsTimeType(defMap map[string]any) bool {
-	if format, ok := defMap["format"].(string); ok {
-		switch format {
-		case "date-time", "date", "time":
-			return true
-		}
-	}
-
-	if properties, ok := defMap["properties"].(map[string]any); ok {
-		for _, prop := range properties {
-			if propMap, ok := prop.(map[string]any); ok {
-				if containsTimeType(propMap) {
-					return true
-				}
-			}
-		}
-	}
-
-	if items, ok := defMap["items"].(map[string]any); ok {
-		if containsTimeType(items) {
-			return true
-		}
-	}
-
-	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
-		if schemas, ok := defMap[key].([]any); ok {
-			for _, schema := range schemas {
-				if schemaMap, ok := schema.(map[string]any); ok {
-					if containsTimeType(schemaMap) {
-						return true
-					}
-				}
-			}
-		}
-	}
-
-	return false
-}
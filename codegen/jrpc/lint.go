@@ -0,0 +1,272 @@
+package jrpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint rules
+const (
+	RuleMissingOperationID = "missing-operation-id" // Operations without operationId are named after their method and path
+	RuleAnonymousObject    = "anonymous-object"     // Inline objects get a type name derived from their parent
+	RuleMissingDescription = "missing-description"  // Definitions, properties and operations without description get no doc comment
+	RuleDuplicateTypeName  = "duplicate-type-name"  // Several schemas claim the same Go type name
+	RuleUnsupportedKeyword = "unsupported-keyword"  // Keywords the generated Go types do not reflect
+	RuleAnyFallback        = "any-fallback"         // Schemas with several types, or constants of objects or arrays, generated as any
+)
+
+// Lint severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// LintSeverities are the severities the lint rules report their findings with by default
+var LintSeverities = map[string]string{
+	RuleMissingOperationID: SeverityWarning,
+	RuleAnonymousObject:    SeverityWarning,
+	RuleMissingDescription: SeverityInfo,
+	RuleDuplicateTypeName:  SeverityError,
+	RuleUnsupportedKeyword: SeverityWarning,
+	RuleAnyFallback:        SeverityWarning,
+}
+
+// GenerationWarningRules are the lint rules about what the generated code drops or renames,
+// whose findings the generate command reports as warnings of the generation
+var GenerationWarningRules = []string{RuleUnsupportedKeyword, RuleDuplicateTypeName, RuleAnyFallback}
+
+// RuleScalarFallback is the rule of the generation warnings about definitions that a
+// generator of another language than Go declares as custom scalars, for lack of a type
+// matching them in that language
+const RuleScalarFallback = "scalar-fallback"
+
+// WarningReporter is implemented by the generators that replace constructs the Go types
+// reflect, whose warnings the generate command reports next to those of
+// GenerationWarningRules
+type WarningReporter interface {
+	// GenerationWarnings returns the warnings of the generation from a schema file
+	GenerationWarnings(schemaPath string) ([]Finding, error)
+}
+
+// unsupportedKeywords are the schema keywords the generated Go types do not reflect
+var unsupportedKeywords = []string{
+	"not", "if", "then", "else", "dependentRequired", "dependentSchemas", "dependencies",
+	"patternProperties", "propertyNames", "unevaluatedProperties", "unevaluatedItems",
+	"contains", "$dynamicRef", "$recursiveRef",
+}
+
+// definitionContainers are the members of a schema document holding definitions, as JSON
+// pointers
+var definitionContainers = []string{"/definitions", "/$defs", "/components/schemas", "/components/contentDescriptors", "/schemas"}
+
+// Finding is an issue found in a schema by the linter
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"` // JSON pointer to the element the finding is about
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`   // Line of the element in the schema file, when located
+	Column   int    `json:"column,omitempty"` // Column of the element in the schema file, when located
+}
+
+// newFinding returns a finding of a rule, with the rule's default severity
+func newFinding(rule string, path string, format string, args ...any) Finding {
+	return Finding{Rule: rule, Severity: LintSeverities[rule], Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+// LintSchemaFile checks the definitions of a JSON Schema file for issues that degrade the
+// generated code (see LintDefinitions), and reports the names defined in several of its
+// definition containers, of which only one is generated
+func LintSchemaFile(schemaPath string) ([]Finding, error) {
+	document, err := loadSchemaFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	declared := make(map[string]string)
+	for _, container := range definitionContainers {
+		target, err := resolvePointer(document, container)
+		definitions, ok := target.(map[string]any)
+		if err != nil || !ok {
+			continue
+		}
+		for _, name := range sortedNames(definitions) {
+			pointer := container + "/" + escapePointerToken(name)
+			if previous, ok := declared[name]; ok {
+				findings = append(findings, newFinding(RuleDuplicateTypeName, pointer, "definition %s is also declared at %s", name, previous))
+				continue
+			}
+			declared[name] = pointer
+		}
+		findings = append(findings, LintDefinitions(container+"/", definitions)...)
+	}
+	return findings, nil
+}
+
+// DefinitionPointers returns the JSON pointers of the definitions of a schema file, by name,
+// for the generators locating their findings. When several containers declare a name, the
+// pointer is that of the definition generated.
+func DefinitionPointers(schemaPath string) (map[string]string, error) {
+	document, err := loadSchemaFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make(map[string]string)
+	for _, container := range definitionContainers {
+		target, err := resolvePointer(document, container)
+		definitions, ok := target.(map[string]any)
+		if err != nil || !ok {
+			continue
+		}
+		for name := range definitions {
+			pointers[name] = container + "/" + escapePointerToken(name)
+		}
+	}
+	return pointers, nil
+}
+
+// LintDefinitions checks definitions, whose JSON pointers start with prefix, for issues
+// that degrade the generated code: anonymous inline objects, missing descriptions, Go type
+// names claimed twice (through x-go-name, or by an inline object named after its parent) and
+// unsupported keywords
+func LintDefinitions(prefix string, definitions map[string]any) []Finding {
+	var findings []Finding
+
+	names := make(map[string]string)
+	for _, name := range sortedNames(definitions) {
+		typeName := name
+		if defMap, ok := definitions[name].(map[string]any); ok {
+			if pinned, ok := goNameOverride(defMap); ok {
+				typeName = pinned
+			}
+		}
+		if previous, ok := names[typeName]; ok {
+			findings = append(findings, newFinding(RuleDuplicateTypeName, prefix+escapePointerToken(name), "definitions %s and %s are both generated as %s", previous, name, typeName))
+			continue
+		}
+		names[typeName] = name
+	}
+
+	for _, name := range sortedNames(definitions) {
+		defMap, ok := definitions[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		pointer := prefix + escapePointerToken(name)
+		if _, isRef := defMap["$ref"]; !isRef && defMap["description"] == nil {
+			findings = append(findings, newFinding(RuleMissingDescription, pointer, "definition %s has no description", name))
+		}
+		typeName := name
+		if pinned, ok := goNameOverride(defMap); ok {
+			typeName = pinned
+		}
+		lintSchema(pointer, typeName, defMap, names, &findings)
+	}
+	return findings
+}
+
+// LintSchema checks a schema that is generated as the type typeName, such as an inline
+// request body, for anonymous inline objects, missing property descriptions and unsupported
+// keywords
+func LintSchema(pointer string, typeName string, schema map[string]any) []Finding {
+	var findings []Finding
+	lintSchema(pointer, typeName, schema, map[string]string{}, &findings)
+	return findings
+}
+
+// lintSchema implements LintSchema. names maps the Go type names of the definitions to the
+// definitions, to report inline objects whose derived name is taken.
+func lintSchema(pointer string, typeName string, schema map[string]any, names map[string]string, findings *[]Finding) {
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := schema[keyword]; ok {
+			*findings = append(*findings, newFinding(RuleUnsupportedKeyword, pointer+"/"+escapePointerToken(keyword), "keyword %s is not reflected in the generated Go types", keyword))
+		}
+	}
+
+	if construct := anyFallback(schema); construct != "" {
+		*findings = append(*findings, newFinding(RuleAnyFallback, pointer, "%s is generated as any", construct))
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	for _, name := range sortedNames(properties) {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		propertyPointer := pointer + "/properties/" + escapePointerToken(name)
+		if _, isRef := property["$ref"]; !isRef && property["description"] == nil {
+			*findings = append(*findings, newFinding(RuleMissingDescription, propertyPointer, "property %s of %s has no description", name, typeName))
+		}
+		fieldName := GoIdentifier(name, nil)
+		if pinned, ok := goNameOverride(property); ok {
+			fieldName = pinned
+		}
+		lintNested(propertyPointer, typeName+fieldName, property, names, findings)
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		lintNested(pointer+"/items", typeName+"Item", items, names, findings)
+	}
+	if values, ok := schema["additionalProperties"].(map[string]any); ok {
+		lintNested(pointer+"/additionalProperties", typeName+"Value", values, names, findings)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		members, _ := schema[keyword].([]any)
+		for i, member := range members {
+			if memberMap, ok := member.(map[string]any); ok {
+				lintSchema(fmt.Sprintf("%s/%s/%d", pointer, keyword, i), typeName, memberMap, names, findings)
+			}
+		}
+	}
+}
+
+// lintNested checks a schema nested in another one, which is generated as a type named name
+// when it is an inline object
+func lintNested(pointer string, name string, schema map[string]any, names map[string]string, findings *[]Finding) {
+	if isNestedObject(schema) {
+		if pinned, ok := goNameOverride(schema); ok {
+			name = pinned
+		}
+		*findings = append(*findings, newFinding(RuleAnonymousObject, pointer, "inline object is generated as %s; declare it as a definition to name it", name))
+		if definition, taken := names[name]; taken {
+			*findings = append(*findings, newFinding(RuleDuplicateTypeName, pointer, "inline object is named %s like definition %s, and gets a numbered name instead", name, definition))
+		}
+	}
+	lintSchema(pointer, name, schema, names, findings)
+}
+
+// anyFallback describes the construct of a schema the generated Go types represent as any for
+// lack of a better type, or returns "" when there is none: several non-null types, the null
+// type alone, or a constant object or array
+func anyFallback(schema map[string]any) string {
+	if _, overridden := goTypeOverride(schema); overridden {
+		return ""
+	}
+	switch t := schema["type"].(type) {
+	case string:
+		if t == "null" {
+			return "type null"
+		}
+	case []any:
+		var types []string
+		for _, item := range t {
+			if itemType, ok := item.(string); ok && itemType != "null" {
+				types = append(types, itemType)
+			}
+		}
+		switch {
+		case len(types) > 1:
+			return "type [" + strings.Join(types, ", ") + "]"
+		case len(types) == 0 && len(t) > 0:
+			return "type null"
+		}
+	}
+	if value, ok := schema["const"]; ok {
+		if _, _, literal := constLiteral(value); !literal {
+			return "const of an object or array"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package jrpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mappedType is an existing Go type a definition is mapped to through
+// GeneratorOptions.ImportMappings
+type mappedType struct {
+	goType     string // Qualified Go type (e.g. "core.Message")
+	importPath string // Import path of the package declaring the type
+}
+
+// parseMappedType splits a fully qualified type of the form "import/path.Type" into the
+// qualified Go type and the import path
+func parseMappedType(target string) (mappedType, error) {
+	slash := strings.LastIndex(target, "/")
+	dot := strings.LastIndex(target, ".")
+	if dot <= slash+1 || dot == len(target)-1 {
+		return mappedType{}, fmt.Errorf("invalid import mapping target %q: must be of the form import/path.Type", target)
+	}
+
+	path, typeName := target[:dot], target[dot+1:]
+	return mappedType{goType: packageNameOf(path) + "." + typeName, importPath: path}, nil
+}
+
+// applyImportMappings maps the definitions named in GeneratorOptions.ImportMappings (by $ref
+// pointer or definition name) to existing types of other packages. Mapped definitions are
+// pinned with x-go-type so that they are not generated, and every $ref to them is pinned to
+// the type together with its import, so packages are only imported where they are used.
+func applyImportMappings(definitions map[string]any, mappings map[string]string) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(mappings))
+	for key := range mappings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mapped := make(map[string]mappedType, len(mappings))
+	packages := make(map[string]string)
+	for _, key := range keys {
+		defName := refName(key)
+		defMap, ok := definitions[defName].(map[string]any)
+		if !ok {
+			return fmt.Errorf("import mapping %q does not match any definition", key)
+		}
+
+		target, err := parseMappedType(mappings[key])
+		if err != nil {
+			return err
+		}
+		packageName := strings.SplitN(target.goType, ".", 2)[0]
+		if path, taken := packages[packageName]; taken && path != target.importPath {
+			return fmt.Errorf("import mappings %q and %q use the same package name %s", path, target.importPath, packageName)
+		}
+		packages[packageName] = target.importPath
+
+		defMap["x-go-type"] = target.goType
+		mapped[defName] = target
+	}
+
+	pinMappedRefs(definitions, mapped)
+	return nil
+}
+
+// pinMappedRefs sets x-go-type and x-go-import on every $ref pointing at a mapped definition
+func pinMappedRefs(node any, mapped map[string]mappedType) {
+	switch value := node.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			if target, ok := mapped[refName(ref)]; ok {
+				if _, overridden := goTypeOverride(value); !overridden {
+					value["x-go-type"] = target.goType
+					value["x-go-import"] = target.importPath
+				}
+			}
+		}
+		for _, child := range value {
+			pinMappedRefs(child, mapped)
+		}
+	case []any:
+		for _, child := range value {
+			pinMappedRefs(child, mapped)
+		}
+	}
+}
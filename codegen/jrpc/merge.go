@@ -0,0 +1,128 @@
+package jrpc
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// MergeSchemas reads JSON Schema, OpenAPI or OpenRPC documents and merges their definitions
+// into a single document of definitions, so that they are generated as one package. The
+// $refs between the documents, and within each of them, are rewritten to point at the merged
+// definitions, while HTTP(S) refs are kept. A definition declared by several documents is
+// kept once when identical, and is an error otherwise. Only the definitions are merged: the
+// methods and operations of the documents are not.
+func MergeSchemas(schemaPaths []string) (map[string]any, error) {
+	documents := map[string]bool{}
+	for _, schemaPath := range schemaPaths {
+		absolute, err := filepath.Abs(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate schema file: %w", err)
+		}
+		documents[absolute] = true
+	}
+
+	merged := map[string]any{}
+	origins := map[string]string{}
+	for _, schemaPath := range schemaPaths {
+		document, err := loadSchemaFile(schemaPath)
+		if err != nil {
+			return nil, codegen.Locate(err, schemaPath)
+		}
+		absolute, _ := filepath.Abs(schemaPath)
+		definitions := extractDefinitions(document)
+		if len(definitions) == 0 {
+			return nil, &codegen.SchemaError{File: schemaPath, Err: errors.New("schema does not contain any type definitions")}
+		}
+
+		names := make([]string, 0, len(definitions))
+		for name := range definitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			definition, err := mergeRefs(definitions[name], filepath.Dir(absolute), documents)
+			if err != nil {
+				err = &codegen.SchemaError{Pointer: definitionPointer(document, name), Err: err}
+				return nil, codegen.Locate(err, schemaPath)
+			}
+			if existing, ok := merged[name]; ok {
+				if !reflect.DeepEqual(existing, definition) {
+					err := &codegen.SchemaError{
+						Pointer: definitionPointer(document, name),
+						Err:     fmt.Errorf("definition %q of %s differs from the one of %s", name, schemaPath, origins[name]),
+					}
+					return nil, codegen.Locate(err, schemaPath)
+				}
+				continue
+			}
+			merged[name] = definition
+			origins[name] = schemaPath
+		}
+	}
+
+	return map[string]any{"definitions": merged}, nil
+}
+
+// mergeRefs returns a copy of a schema with its $refs into the given documents, or into its
+// own document, rewritten to point at the merged definitions. File refs are relative to dir.
+func mergeRefs(schema any, dir string, documents map[string]bool) (any, error) {
+	switch value := schema.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				merged, err := mergedRef(ref, dir, documents)
+				if err != nil {
+					return nil, err
+				}
+				copied[key] = merged
+				continue
+			}
+			merged, err := mergeRefs(child, dir, documents)
+			if err != nil {
+				return nil, err
+			}
+			copied[key] = merged
+		}
+		return copied, nil
+	case []any:
+		copied := make([]any, len(value))
+		for i, child := range value {
+			merged, err := mergeRefs(child, dir, documents)
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = merged
+		}
+		return copied, nil
+	default:
+		return schema, nil
+	}
+}
+
+// mergedRef returns the ref to the merged definition a ref points at
+func mergedRef(ref, dir string, documents map[string]bool) (string, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref, nil
+	}
+	file, pointer, _ := strings.Cut(ref, "#")
+	file = filepath.FromSlash(file)
+	if file != "" && !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	if file != "" && !documents[file] {
+		return "", fmt.Errorf("$ref %q points at a document that is not merged", ref)
+	}
+	for _, container := range definitionContainers {
+		if name, ok := strings.CutPrefix(pointer, container+"/"); ok && name != "" {
+			return "#/definitions/" + name, nil
+		}
+	}
+	return "", fmt.Errorf("$ref %q does not point at a definition", ref)
+}
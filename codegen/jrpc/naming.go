@@ -0,0 +1,224 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
+)
+
+// NamingConfigFile is the naming rules file the generator CLI looks for next to the schema
+// and in the working directory when no file is given explicitly
+const NamingConfigFile = ".codegen-naming.yaml"
+
+// NamingRules customizes how schema names are converted to Go identifiers. It is typically
+// loaded from a YAML file with LoadNamingRules:
+//
+//	acronyms: [a2a, grpc]
+//	names:
+//	  _links: Links
+//	words:
+//	  oauth2: OAuth2
+//	  github: GitHub
+type NamingRules struct {
+	Acronyms []string          `yaml:"acronyms"` // Words written in upper case, in addition to DefaultAcronyms
+	Names    map[string]string `yaml:"names"`    // Go identifiers forced for definitions and properties, by schema name
+	Words    map[string]string `yaml:"words"`    // Spellings of single words inside identifiers (oauth2 → OAuth2), overriding acronyms
+}
+
+// LoadNamingRules reads naming rules from a YAML file
+func LoadNamingRules(path string) (*NamingRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read naming rules: %w", err)
+	}
+
+	var rules NamingRules
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse naming rules %s: %w", path, err)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid naming rules %s: %w", path, err)
+	}
+	return &rules, nil
+}
+
+// validate checks that forced names are exported Go identifiers and that word spellings only
+// change the case of their word
+func (r *NamingRules) validate() error {
+	for schemaName, goName := range r.Names {
+		if !token.IsIdentifier(goName) || !token.IsExported(goName) {
+			return fmt.Errorf("name %q of %q is not an exported Go identifier", goName, schemaName)
+		}
+	}
+	for word, spelling := range r.Words {
+		if !strings.EqualFold(word, spelling) {
+			return fmt.Errorf("spelling %q of word %q must only change its case", spelling, word)
+		}
+	}
+	return nil
+}
+
+// wordSpellings returns the Go spelling of the words that are not simply title-cased in
+// identifiers: the default and custom acronyms in upper case, then the NamingRules words.
+// Custom acronyms set to false are dropped from the defaults.
+func wordSpellings(options *GeneratorOptions) map[string]string {
+	acronyms := DefaultAcronyms()
+	for k, v := range options.CustomAcronyms {
+		acronyms[k] = v
+	}
+	if options.Naming != nil {
+		for _, acronym := range options.Naming.Acronyms {
+			acronyms[strings.ToLower(acronym)] = true
+		}
+	}
+
+	spellings := make(map[string]string, len(acronyms))
+	for word, isAcronym := range acronyms {
+		if isAcronym {
+			spellings[strings.ToLower(word)] = strings.ToUpper(word)
+		}
+	}
+	if options.Naming != nil {
+		for word, spelling := range options.Naming.Words {
+			spellings[strings.ToLower(word)] = spelling
+		}
+	}
+	return spellings
+}
+
+// GoIdentifier converts a schema name (snake_case, kebab-case, camelCase, ...) to the exported
+// Go identifier the generator would use for it, with the acronyms and word spellings of
+// options. Latin letters with diacritics are transliterated (ürl → URL), as in the names of
+// the definitions, so that every target spells a name alike. Other generators use it to name
+// what they declare next to the generated types.
+func GoIdentifier(name string, options *GeneratorOptions) string {
+	if options == nil {
+		options = &GeneratorOptions{}
+	}
+	return convertToGoFieldName(name, wordSpellings(options))
+}
+
+// applyForcedNames pins the Go names of NamingRules.Names onto the matching definitions and
+// properties as x-go-name, unless the schema already sets one
+func applyForcedNames(definitions map[string]any, options *GeneratorOptions) {
+	if options.Naming == nil || len(options.Naming.Names) == 0 {
+		return
+	}
+	names := options.Naming.Names
+
+	for defName, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok {
+			if goName, forced := names[defName]; forced {
+				if _, named := goNameOverride(defMap); !named {
+					defMap["x-go-name"] = goName
+				}
+			}
+		}
+	}
+
+	var visit func(node any)
+	visit = func(node any) {
+		switch value := node.(type) {
+		case map[string]any:
+			if properties, ok := value["properties"].(map[string]any); ok {
+				for propName, propDef := range properties {
+					propMap, ok := propDef.(map[string]any)
+					if !ok {
+						continue
+					}
+					if goName, forced := names[propName]; forced {
+						if _, named := goNameOverride(propMap); !named {
+							propMap["x-go-name"] = goName
+						}
+					}
+				}
+			}
+			for _, child := range value {
+				visit(child)
+			}
+		case []any:
+			for _, child := range value {
+				visit(child)
+			}
+		}
+	}
+	visit(definitions)
+}
+
+// transliterations are the Latin letters that Unicode normalization does not decompose into
+// an ASCII base letter and diacritics
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O", 'đ': "d",
+	'Đ': "D", 'ł': "l", 'Ł': "L", 'þ': "th", 'Þ': "TH", 'ð': "d", 'Ð': "D", 'ı': "i",
+}
+
+// Transliterate spells Latin letters with diacritics as their ASCII base letters (ürl → url,
+// straße → strasse). Letters of other scripts are kept as they are.
+func Transliterate(name string) string {
+	var b strings.Builder
+	latin := false
+	for _, r := range norm.NFD.String(name) {
+		if unicode.Is(unicode.Mn, r) {
+			if !latin {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		latin = unicode.Is(unicode.Latin, r)
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+// transliterateDefinitionNames renames the definitions whose names have Latin letters with
+// diacritics after Transliterate (Ärger → Arger), as GoIdentifier spells them for the other
+// targets, and rewrites every $ref pointing at them. Names set with x-go-name are kept, and so
+// is a name whose transliteration another definition already has.
+func transliterateDefinitionNames(definitions map[string]any, options *GeneratorOptions) {
+	renames := make(map[string]string)
+	for _, defName := range sortedDefinitionNames(definitions) {
+		name := Transliterate(defName)
+		if name == defName {
+			continue
+		}
+		if defMap, ok := definitions[defName].(map[string]any); ok {
+			if _, named := goNameOverride(defMap); named {
+				continue
+			}
+		}
+		if _, exists := definitions[name]; exists {
+			continue
+		}
+		definitions[name] = definitions[defName]
+		delete(definitions, defName)
+		renames[defName] = name
+		reportRename(options.RenameReport, "definition", defName, name, "transliterated")
+	}
+
+	if len(renames) > 0 {
+		rewriteRefs(definitions, renames)
+	}
+}
+
+// uniqueFieldName returns name, suffixed with 2, 3, ... when a field of the struct already
+// uses it, as happens for properties differing only in punctuation or case (user-id, user_id)
+func uniqueFieldName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
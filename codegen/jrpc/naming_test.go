@@ -0,0 +1,71 @@
+package jrpc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGoIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"user_id", "UserID"},
+		{"ürl", "URL"},
+		{"straße", "Strasse"},
+		{"Ærø", "Aero"},
+		{"привет", "Привет"},
+		{"名前", "Field名前"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := GoIdentifier(test.name, nil); got != test.want {
+				t.Errorf("GoIdentifier(%q) = %s, want %s", test.name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTransliterateDefinitionNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions map[string]any
+		schemaName  string // Name of the definition the holder definition references
+		renamed     string
+	}{
+		{"diacritics", map[string]any{"Ärger": map[string]any{"type": "object"}}, "Ärger", "Arger"},
+		{"other scripts", map[string]any{"Статус": map[string]any{"type": "string"}}, "Статус", "Статус"},
+		{"x-go-name", map[string]any{"Größe": map[string]any{"type": "string", "x-go-name": "Größe"}}, "Größe", "Größe"},
+		{"transliteration taken", map[string]any{"Café": map[string]any{"type": "string"}, "Cafe": map[string]any{"type": "object"}}, "Café", "Café"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			definitions := map[string]any{"holder": map[string]any{"$ref": "#/definitions/" + test.schemaName}}
+			for name, definition := range test.definitions {
+				definitions[name] = definition
+			}
+			transliterateDefinitionNames(definitions, &GeneratorOptions{})
+
+			if _, ok := definitions[test.renamed]; !ok {
+				t.Errorf("definitions = %v, want %s", definitions, test.renamed)
+			}
+			if got := definitions["holder"].(map[string]any)["$ref"]; got != "#/definitions/"+test.renamed {
+				t.Errorf("$ref = %v, want #/definitions/%s", got, test.renamed)
+			}
+		})
+	}
+}
+
+// TestTransliteratedTypeNames checks that the Go type of a definition is named as GoIdentifier
+// names it for the other targets
+func TestTransliteratedTypeNames(t *testing.T) {
+	schema := `{"definitions": {"Ärger": {"type": "object", "properties": {"ürl": {"type": "string"}}}}}`
+	var source bytes.Buffer
+	if err := GenerateTypesTo(&source, []byte(schema), &GeneratorOptions{PackageName: "generated"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "type " + GoIdentifier("Ärger", nil) + " struct"; !strings.Contains(source.String(), want) {
+		t.Errorf("generated code lacks %q:\n%s", want, source.String())
+	}
+}
@@ -0,0 +1,155 @@
+package jrpc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// hoistInlineSchemas moves inline object and union schemas into named definitions until no
+// inline schema is left, so that nested structures produce real Go types
+func hoistInlineSchemas(definitions map[string]any, spellings map[string]string) {
+	for {
+		count := len(definitions)
+		hoistInlineObjects(definitions, spellings)
+		hoistInlineUnionMembers(definitions, spellings)
+		if len(definitions) == count {
+			return
+		}
+	}
+}
+
+// hoistInlineObjects moves object properties declared inline (including array items, map
+// values, tuples and tuple items) into named definitions derived from the parent and field
+// names, e.g. the `config` property of `Agent` becomes `AgentConfig`, and replaces them with a $ref
+func hoistInlineObjects(definitions map[string]any, spellings map[string]string) {
+	pending := sortedDefinitionNames(definitions)
+
+	for len(pending) > 0 {
+		defName := pending[0]
+		pending = pending[1:]
+
+		defMap, ok := definitions[defName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if items, ok := tupleItems(defMap); ok {
+			for i, item := range items {
+				itemMap, ok := item.(map[string]any)
+				if !ok || !isNestedObject(itemMap) {
+					continue
+				}
+
+				nestedName, pinned := goNameOverride(itemMap)
+				if !pinned {
+					nestedName = fmt.Sprintf("%sItem%d", defName, i)
+				}
+				nestedName = uniqueDefinitionName(definitions, nestedName)
+				definitions[nestedName] = itemMap
+				items[i] = map[string]any{"$ref": "#/definitions/" + nestedName}
+				pending = append(pending, nestedName)
+			}
+		}
+
+		for _, properties := range propertyMaps(defMap) {
+			propNames := make([]string, 0, len(properties))
+			for propName := range properties {
+				propNames = append(propNames, propName)
+			}
+			sort.Strings(propNames)
+
+			for _, propName := range propNames {
+				propMap, ok := properties[propName].(map[string]any)
+				if !ok {
+					continue
+				}
+
+				target, suffix := propMap, ""
+				if !isTupleSchema(propMap) {
+					if items, ok := propMap["items"].(map[string]any); ok {
+						target, suffix = items, "Item"
+					} else if values, ok := propMap["additionalProperties"].(map[string]any); ok {
+						if _, hasProperties := propMap["properties"]; !hasProperties {
+							target, suffix = values, "Value"
+						}
+					}
+				}
+
+				if !isNestedObject(target) && !isTupleSchema(target) {
+					continue
+				}
+
+				nestedName, pinned := goNameOverride(target)
+				if !pinned {
+					nestedName = defName + goFieldName(propName, propMap, spellings) + suffix
+				}
+				nestedName = uniqueDefinitionName(definitions, nestedName)
+				definitions[nestedName] = copySchema(target)
+				for key := range target {
+					if key != "description" {
+						delete(target, key)
+					}
+				}
+				target["$ref"] = "#/definitions/" + nestedName
+
+				pending = append(pending, nestedName)
+			}
+		}
+	}
+}
+
+// isNestedObject reports whether an inline schema is an object with its own properties
+func isNestedObject(schema map[string]any) bool {
+	if _, isRef := schema["$ref"]; isRef {
+		return false
+	}
+	if _, overridden := goTypeOverride(schema); overridden {
+		return false
+	}
+	if objectType := schemaType(schema); objectType != "" && objectType != "object" {
+		return false
+	}
+	if properties, ok := schema["properties"].(map[string]any); ok && len(properties) > 0 {
+		return true
+	}
+	_, hasAllOf := schema["allOf"]
+	return hasAllOf
+}
+
+// propertyMaps returns the properties map of a definition together with the properties maps
+// of its inline allOf members, so that callers can rewrite properties in place
+func propertyMaps(defMap map[string]any) []map[string]any {
+	var maps []map[string]any
+
+	if properties, ok := defMap["properties"].(map[string]any); ok {
+		maps = append(maps, properties)
+	}
+
+	if allOf, ok := defMap["allOf"].([]any); ok {
+		for _, member := range allOf {
+			if memberMap, ok := member.(map[string]any); ok {
+				if _, isRef := memberMap["$ref"]; !isRef {
+					maps = append(maps, propertyMaps(memberMap)...)
+				}
+			}
+		}
+	}
+
+	return maps
+}
+
+// uniqueDefinitionName returns name, or name with a numeric suffix if a definition with
+// that name already exists
+func uniqueDefinitionName(definitions map[string]any, name string) string {
+	if _, exists := definitions[name]; !exists {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if _, exists := definitions[candidate]; !exists {
+			return candidate
+		}
+	}
+}
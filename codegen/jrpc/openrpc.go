@@ -0,0 +1,717 @@
+package jrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// paramOrderKey is the extension under which the parameter names of a method accepting them by
+// position are recorded on its Params definition, in order
+const paramOrderKey = "x-go-param-order"
+
+// paramStructureKey is the extension under which the paramStructure of a method is recorded on
+// its Params definition
+const paramStructureKey = "x-go-param-structure"
+
+// Values of the paramStructure of OpenRPC methods, "either" when it is not given
+const (
+	paramsByName     = "by-name"     // Params are encoded and accepted as a JSON object only
+	paramsByPosition = "by-position" // Params are encoded and accepted as a JSON array only
+	paramsEither     = "either"      // Params are encoded as a JSON object, and accepted as both
+)
+
+// rpcMethod is a method of an OpenRPC document, with the names of the definitions generated
+// for its parameters and result
+type rpcMethod struct {
+	name       string // Method name sent on the wire (e.g. "pet_get")
+	goName     string // Go identifier of the method (e.g. "PetGet")
+	summary    string
+	deprecated bool
+	params     string       // Params definition, empty when the method takes no parameters
+	result     string       // Result definition, empty for notifications
+	errors     []rpcError   // Application errors the method declares
+	examples   []rpcExample // Example pairings of the method
+}
+
+// generated reports whether the Params and Result definitions of the method are generated,
+// which they are not when filtered out
+func (m rpcMethod) generated(definitions map[string]any) bool {
+	_, hasParams := definitions[m.params]
+	_, hasResult := definitions[m.result]
+	return (m.params == "" || hasParams) && (m.result == "" || hasResult)
+}
+
+// signature returns the parameter list of the Go methods calling or handling the method: a
+// context, followed by the Params when the method takes parameters
+func (m rpcMethod) signature() string {
+	if m.params == "" {
+		return "ctx context.Context"
+	}
+	return "ctx context.Context, params " + m.params
+}
+
+// rpcExample is an example pairing of an OpenRPC method, with its params encoded by name and
+// its result encoded, empty when the example has none
+type rpcExample struct {
+	name   string
+	params string
+	result string
+}
+
+// rpcError is an application error declared by an OpenRPC method
+type rpcError struct {
+	name    string // Go name of the error code constant, without the Error type prefix
+	code    int
+	message string
+	data    map[string]any // Schema of the data of the error, a $ref once documentErrors defined it; nil when undeclared
+}
+
+// typeName returns the Go name of the typed error, prefixed with RPC when the schema defines
+// a type of the same name
+func (e rpcError) typeName(definitions map[string]any, errType string) string {
+	name := e.name + "Error"
+	if _, taken := definitions[name]; taken || name == errType {
+		return "RPC" + name
+	}
+	return name
+}
+
+// rpcEnvelope are the Go names of the JSON-RPC 2.0 envelope types, prefixed with RPC when the
+// schema defines a type of the same name
+type rpcEnvelope struct {
+	request  string
+	response string
+	err      string
+}
+
+// isOpenRPCDocument reports whether a schema document is an OpenRPC document declaring methods
+func isOpenRPCDocument(schema map[string]any) bool {
+	_, isOpenRPC := schema["openrpc"].(string)
+	methods, _ := schema["methods"].([]any)
+	return isOpenRPC && len(methods) > 0
+}
+
+// addMethodDefinitions adds a <Method>Params definition, an object with one property per
+// parameter, and a <Method>Result definition standing for the result schema, for each method
+// of an OpenRPC document, so that they are generated like the component schemas. The Params
+// record the paramStructure of the method under paramStructureKey, and their parameter order
+// under paramOrderKey unless they are given by name.
+func addMethodDefinitions(schema map[string]any, definitions map[string]any, spellings map[string]string) []rpcMethod {
+	rawMethods, _ := schema["methods"].([]any)
+
+	var methods []rpcMethod
+	for _, rawMethod := range rawMethods {
+		methodMap, ok := rawMethod.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := methodMap["name"].(string)
+		if name == "" {
+			continue
+		}
+		method := rpcMethod{name: name, goName: convertToGoFieldName(name, spellings)}
+		method.summary, _ = methodMap["summary"].(string)
+		if method.summary == "" {
+			method.summary, _ = methodMap["description"].(string)
+		}
+		method.deprecated = isDeprecated(methodMap)
+
+		params, _ := methodMap["params"].([]any)
+		if len(params) > 0 {
+			properties := make(map[string]any)
+			var required []any
+			var order []any
+			for _, param := range params {
+				descriptor := contentDescriptor(schema, param)
+				paramName, _ := descriptor["name"].(string)
+				if paramName == "" {
+					continue
+				}
+				properties[paramName] = descriptorSchema(descriptor)
+				order = append(order, paramName)
+				if isRequired, _ := descriptor["required"].(bool); isRequired {
+					required = append(required, paramName)
+				}
+			}
+
+			method.params = uniqueDefinitionName(definitions, method.goName+"Params")
+			paramsDef := map[string]any{
+				"type":        "object",
+				"description": fmt.Sprintf("%s are the parameters of the %s method", method.params, name),
+				"properties":  properties,
+			}
+			if len(required) > 0 {
+				paramsDef["required"] = required
+			}
+			structure, _ := methodMap["paramStructure"].(string)
+			if structure != paramsByName && structure != paramsByPosition {
+				structure = paramsEither
+			}
+			paramsDef[paramStructureKey] = structure
+			if structure != paramsByName {
+				paramsDef[paramOrderKey] = order
+			}
+			definitions[method.params] = paramsDef
+		}
+
+		if rawResult, ok := methodMap["result"]; ok {
+			descriptor := contentDescriptor(schema, rawResult)
+			if resultSchema := descriptorSchema(descriptor); len(resultSchema) > 0 {
+				method.result = uniqueDefinitionName(definitions, method.goName+"Result")
+				if ref, isRef := resultSchema["$ref"]; isRef {
+					// A definition that is only a $ref is generated as an empty struct, while
+					// a single allOf member is embedded, or aliased when it is not an object
+					delete(resultSchema, "$ref")
+					resultSchema["allOf"] = []any{map[string]any{"$ref": ref}}
+				}
+				if _, described := resultSchema["description"]; !described {
+					resultSchema["description"] = fmt.Sprintf("%s is the result of the %s method", method.result, name)
+				}
+				definitions[method.result] = resultSchema
+			}
+		}
+
+		rawErrors, _ := methodMap["errors"].([]any)
+		for _, rawError := range rawErrors {
+			if failure, ok := parseRPCError(schema, rawError, spellings); ok {
+				method.errors = append(method.errors, failure)
+			}
+		}
+
+		rawExamples, _ := methodMap["examples"].([]any)
+		for i, rawExample := range rawExamples {
+			example, err := parseRPCExample(schema, rawExample)
+			if err != nil {
+				continue
+			}
+			if example.name == "" {
+				example.name = strconv.Itoa(i)
+			}
+			method.examples = append(method.examples, example)
+		}
+
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// parseRPCExample reads an OpenRPC example pairing, following the $refs to the components of
+// the pairing and of its params and result examples
+func parseRPCExample(schema map[string]any, value any) (rpcExample, error) {
+	pairing := contentDescriptor(schema, value)
+	example := rpcExample{}
+	example.name, _ = pairing["name"].(string)
+
+	params := make(map[string]any)
+	rawParams, _ := pairing["params"].([]any)
+	for _, rawParam := range rawParams {
+		param := contentDescriptor(schema, rawParam)
+		name, _ := param["name"].(string)
+		if paramValue, ok := param["value"]; ok && name != "" {
+			params[name] = paramValue
+		}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return example, err
+	}
+	example.params = string(encoded)
+
+	if result := contentDescriptor(schema, pairing["result"]); result != nil {
+		if resultValue, ok := result["value"]; ok {
+			encoded, err := json.Marshal(resultValue)
+			if err != nil {
+				return example, err
+			}
+			example.result = string(encoded)
+		}
+	}
+	return example, nil
+}
+
+// errorDataKeywords are the keywords telling that the data of an OpenRPC error object is a
+// schema declaring the type of the data rather than a value
+var errorDataKeywords = []string{"$ref", "type", "properties", "items", "enum", "oneOf", "anyOf", "allOf"}
+
+// parseRPCError reads an OpenRPC error object, following a $ref into the components. Errors
+// of the components are named after their key, others after their message. The data of the
+// error is kept as its schema when it is one.
+func parseRPCError(schema map[string]any, value any, spellings map[string]string) (rpcError, bool) {
+	errorMap := contentDescriptor(schema, value)
+	code, isNumber := errorMap["code"].(float64)
+	message, _ := errorMap["message"].(string)
+	if !isNumber || message == "" {
+		return rpcError{}, false
+	}
+	name := message
+	if valueMap, _ := value.(map[string]any); valueMap != nil {
+		if ref, _ := valueMap["$ref"].(string); strings.HasPrefix(ref, "#/components/errors/") {
+			name = strings.TrimPrefix(ref, "#/components/errors/")
+		}
+	}
+	failure := rpcError{name: convertToGoFieldName(name, spellings), code: int(code), message: message}
+	if data, ok := errorMap["data"].(map[string]any); ok {
+		for _, keyword := range errorDataKeywords {
+			if _, ok := data[keyword]; ok {
+				failure.data = data
+				break
+			}
+		}
+	}
+	return failure, true
+}
+
+// documentErrors returns the application errors of an OpenRPC document: those of its
+// components, by key, followed by those the methods declare inline. Errors are unique by name.
+// A data schema other than a $ref is added as an <Error>ErrorData definition, so that the
+// typed error can carry the data as the generated type.
+func documentErrors(schema map[string]any, definitions map[string]any, methods []rpcMethod, spellings map[string]string) []rpcError {
+	var failures []rpcError
+	declared := make(map[string]bool)
+	add := func(failure rpcError) {
+		if declared[failure.name] {
+			return
+		}
+		declared[failure.name] = true
+		if _, isRef := failure.data["$ref"]; failure.data != nil && !isRef {
+			name := uniqueDefinitionName(definitions, failure.name+"ErrorData")
+			dataDef := copySchema(failure.data)
+			if _, described := dataDef["description"]; !described {
+				dataDef["description"] = fmt.Sprintf("%s is the data of the error with code %d", name, failure.code)
+			}
+			definitions[name] = dataDef
+			failure.data = map[string]any{"$ref": "#/definitions/" + name}
+		}
+		failures = append(failures, failure)
+	}
+
+	components, _ := schema["components"].(map[string]any)
+	componentErrors, _ := components["errors"].(map[string]any)
+	for _, key := range sortedNames(componentErrors) {
+		if failure, ok := parseRPCError(schema, map[string]any{"$ref": "#/components/errors/" + escapePointerToken(key)}, spellings); ok {
+			add(failure)
+		}
+	}
+	for _, method := range methods {
+		for _, failure := range method.errors {
+			add(failure)
+		}
+	}
+	return failures
+}
+
+// contentDescriptor returns the content descriptor of a method parameter or result, or the
+// error object of a method, following a $ref into the components
+func contentDescriptor(schema map[string]any, value any) map[string]any {
+	descriptor, _ := value.(map[string]any)
+	if ref, ok := descriptor["$ref"].(string); ok {
+		if target, err := resolvePointer(schema, strings.TrimPrefix(ref, "#")); err == nil {
+			descriptor, _ = target.(map[string]any)
+		}
+	}
+	return descriptor
+}
+
+// descriptorSchema returns a copy of the schema of a content descriptor carrying its
+// description and deprecation, or nil when it has no schema
+func descriptorSchema(descriptor map[string]any) map[string]any {
+	schema, ok := descriptor["schema"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	copied := make(map[string]any, len(schema)+2)
+	for key, value := range schema {
+		copied[key] = value
+	}
+	for _, key := range []string{"summary", "description"} {
+		if text, ok := descriptor[key].(string); ok && text != "" {
+			copied["description"] = text
+		}
+	}
+	if isDeprecated(descriptor) {
+		copied["deprecated"] = true
+	}
+	return copied
+}
+
+// paramStructure returns the paramStructure recorded on a Params definition, or an empty
+// string for other definitions
+func paramStructure(definition any) string {
+	defMap, _ := definition.(map[string]any)
+	structure, _ := defMap[paramStructureKey].(string)
+	return structure
+}
+
+// containsPositionalParams reports whether some definition is the Params of a method
+// accepting its parameters by position
+func containsPositionalParams(definitions map[string]any) bool {
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && defMap[paramOrderKey] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rpcEnvelopeNames returns the Go names of the envelope types, avoiding the definitions
+func rpcEnvelopeNames(definitions map[string]any) rpcEnvelope {
+	name := func(base string) string {
+		if _, taken := definitions[base]; taken {
+			return "RPC" + base
+		}
+		return base
+	}
+	return rpcEnvelope{request: name("Request"), response: name("Response"), err: name("Error")}
+}
+
+// generateRPCEnvelopes writes the method name constants of an OpenRPC document, the JSON-RPC
+// 2.0 Request, Response and Error envelope types with their helpers, the typed application
+// errors, the method table, and the JSON array encoding of the Params of by-position methods
+func generateRPCEnvelopes(out *bytes.Buffer, methods []rpcMethod, failures []rpcError, definitions map[string]any, spellings map[string]string) error {
+	names := rpcEnvelopeNames(definitions)
+	var b strings.Builder
+
+	b.WriteString("// Names of the JSON-RPC methods\nconst (\n")
+	for _, method := range methods {
+		if method.summary != "" {
+			fmt.Fprintf(&b, "\t// Method%s: %s\n", method.goName, strings.Join(strings.Fields(method.summary), " "))
+		}
+		if method.deprecated {
+			if method.summary != "" {
+				b.WriteString("\t//\n")
+			}
+			b.WriteString("\t// Deprecated: the method is deprecated.\n")
+		}
+		fmt.Fprintf(&b, "\tMethod%s = %s\n", method.goName, strconv.Quote(method.name))
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, `// %[1]s is a JSON-RPC 2.0 request, or a notification when ID is nil
+type %[1]s struct {
+	JSONRPC string          `+"`json:\"jsonrpc\"`"+`
+	ID      any             `+"`json:\"id,omitempty\"`"+` // String or number identifying the request
+	Method  string          `+"`json:\"method\"`"+`
+	Params  json.RawMessage `+"`json:\"params,omitempty\"`"+`
+}
+
+// New%[1]s returns a request calling method with params, which are encoded as JSON unless
+// nil. A nil id makes the request a notification.
+func New%[1]s(id any, method string, params any) (*%[1]s, error) {
+	request := &%[1]s{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the params of %%s: %%w", method, err)
+		}
+		request.Params = data
+	}
+	return request, nil
+}
+
+// DecodeParams decodes the params of the request into params
+func (r *%[1]s) DecodeParams(params any) error {
+	if len(r.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Params, params); err != nil {
+		return &%[3]s{Code: %[3]sCodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// %[2]s is a JSON-RPC 2.0 response, carrying either a Result or an Error
+type %[2]s struct {
+	JSONRPC string          `+"`json:\"jsonrpc\"`"+`
+	ID      any             `+"`json:\"id\"`"+`
+	Result  json.RawMessage `+"`json:\"result,omitempty\"`"+`
+	Error   *%[3]s          `+"`json:\"error,omitempty\"`"+`
+}
+
+// New%[2]s returns the response to the request id carrying result, encoded as JSON
+func New%[2]s(id any, result any) (*%[2]s, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result: %%w", err)
+	}
+	return &%[2]s{JSONRPC: "2.0", ID: id, Result: data}, nil
+}
+
+// New%[3]s%[2]s returns the response to the request id carrying err
+func New%[3]s%[2]s(id any, err *%[3]s) *%[2]s {
+	return &%[2]s{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+// DecodeResult decodes the result of the response into result, or returns its error, typed
+// with CodeTo%[3]s
+func (r *%[2]s) DecodeResult(result any) error {
+	if r.Error != nil {
+		return CodeTo%[3]s(r.Error)
+	}
+	if len(r.Result) == 0 || result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.Result, result); err != nil {
+		return fmt.Errorf("failed to decode the result: %%w", err)
+	}
+	return nil
+}
+
+// %[3]s is a JSON-RPC 2.0 error object
+type %[3]s struct {
+	Code    int    `+"`json:\"code\"`"+`
+	Message string `+"`json:\"message\"`"+`
+	Data    any    `+"`json:\"data,omitempty\"`"+`
+}
+
+// Error implements the error interface
+func (e *%[3]s) Error() string {
+	return fmt.Sprintf("jsonrpc error %%d: %%s", e.Code, e.Message)
+}
+
+// Error codes defined by the JSON-RPC 2.0 specification
+const (
+	%[3]sCodeParseError     = -32700 // Invalid JSON was received
+	%[3]sCodeInvalidRequest = -32600 // The JSON sent is not a valid request object
+	%[3]sCodeMethodNotFound = -32601 // The method does not exist or is not available
+	%[3]sCodeInvalidParams  = -32602 // Invalid method parameters
+	%[3]sCodeInternalError  = -32603 // Internal JSON-RPC error
+)
+
+// Is%[3]sCode reports whether err is, or wraps, an *%[3]s with the given code
+func Is%[3]sCode(err error, code int) bool {
+	var rpcErr *%[3]s
+	return errors.As(err, &rpcErr) && rpcErr.Code == code
+}
+
+`, names.request, names.response, names.err)
+
+	generateRPCErrors(&b, failures, definitions, names.err)
+	generateRPCMethodTable(&b, methods, definitions, names.err)
+
+	for _, method := range methods {
+		paramsDef, _ := definitions[method.params].(map[string]any)
+		order, ok := paramsDef[paramOrderKey].([]any)
+		if !ok {
+			continue
+		}
+		properties, _ := paramsDef["properties"].(map[string]any)
+		var fields []string
+		for _, name := range order {
+			propName, _ := name.(string)
+			propMap, _ := properties[propName].(map[string]any)
+			fields = append(fields, goFieldName(propName, propMap, spellings))
+		}
+		writePositionalParams(&b, method.params, fields, paramStructure(paramsDef) == paramsByPosition)
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateRPCErrors writes the code constants of the application errors of an OpenRPC
+// document, a typed error per code converting itself into the Error envelope type for
+// errors.As, and the CodeTo<Error> lookup returning the typed error of an envelope
+func generateRPCErrors(b *strings.Builder, failures []rpcError, definitions map[string]any, errType string) {
+	if len(failures) > 0 {
+		b.WriteString("// Error codes declared by the document\nconst (\n")
+		for _, failure := range failures {
+			fmt.Fprintf(b, "\t%sCode%s = %d // %s\n", errType, failure.name, failure.code, strings.Join(strings.Fields(failure.message), " "))
+		}
+		b.WriteString(")\n\n")
+	}
+
+	typeNames := make([]string, len(failures))
+	dataTypes := make([]string, len(failures))
+	for i, failure := range failures {
+		typeNames[i] = failure.typeName(definitions, errType)
+		dataTypes[i] = errorDataType(failure, definitions)
+		code, message := errType+"Code"+failure.name, strconv.Quote(failure.message)
+		fmt.Fprintf(b, "// %s is the error with code %s: %s\n", typeNames[i], code, strings.Join(strings.Fields(failure.message), " "))
+		if dataTypes[i] != "" {
+			fmt.Fprintf(b, "type %s struct {\n\tData *%s // Additional information about the error\n}\n\n", typeNames[i], dataTypes[i])
+		} else {
+			fmt.Fprintf(b, "type %s struct {\n\tData any // Additional information about the error\n}\n\n", typeNames[i])
+		}
+		b.WriteString("// Error implements the error interface\n")
+		fmt.Fprintf(b, "func (e *%s) Error() string {\n", typeNames[i])
+		fmt.Fprintf(b, "\treturn %s\n}\n\n", strconv.Quote(fmt.Sprintf("jsonrpc error %d: %s", failure.code, failure.message)))
+		fmt.Fprintf(b, "// As converts the error into an *%s carrying its code, for errors.As\n", errType)
+		fmt.Fprintf(b, "func (e *%s) As(target any) bool {\n", typeNames[i])
+		fmt.Fprintf(b, "\trpcErr, ok := target.(**%s)\n\tif ok {\n", errType)
+		if dataTypes[i] != "" {
+			// A nil *T stored in the any field would be encoded as a null data
+			fmt.Fprintf(b, "\t\t*rpcErr = &%s{Code: %s, Message: %s}\n", errType, code, message)
+			b.WriteString("\t\tif e.Data != nil {\n\t\t\t(*rpcErr).Data = e.Data\n\t\t}\n\t}\n\treturn ok\n}\n\n")
+		} else {
+			fmt.Fprintf(b, "\t\t*rpcErr = &%s{Code: %s, Message: %s, Data: e.Data}\n\t}\n\treturn ok\n}\n\n", errType, code, message)
+		}
+	}
+
+	fmt.Fprintf(b, "// CodeTo%[1]s returns the typed error declared for the code of err, carrying its data, or\n// err itself for other codes\n", errType)
+	fmt.Fprintf(b, "func CodeTo%[1]s(err *%[1]s) error {\n\tif err == nil {\n\t\treturn nil\n\t}\n", errType)
+	if len(failures) > 0 {
+		b.WriteString("\tswitch err.Code {\n")
+		for i, failure := range failures {
+			fmt.Fprintf(b, "\tcase %sCode%s:\n", errType, failure.name)
+			if dataTypes[i] != "" {
+				fmt.Fprintf(b, "\t\ttyped := &%s{}\n", typeNames[i])
+				b.WriteString("\t\tif decodeErrorData(err.Data, &typed.Data) != nil {\n\t\t\treturn err\n\t\t}\n\t\treturn typed\n")
+			} else {
+				fmt.Fprintf(b, "\t\treturn &%s{Data: err.Data}\n", typeNames[i])
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("\treturn err\n}\n\n")
+
+	if slices.ContainsFunc(dataTypes, func(dataType string) bool { return dataType != "" }) {
+		b.WriteString(errorDataHelper)
+	}
+}
+
+// errorDataType returns the Go type of the data of an application error, or an empty string
+// when its data has no declared schema or a type that is not named
+func errorDataType(failure rpcError, definitions map[string]any) string {
+	if failure.data == nil {
+		return ""
+	}
+	goType := determineGoType(failure.data, definitions)
+	if goType == "any" || !token.IsIdentifier(goType) {
+		return ""
+	}
+	return goType
+}
+
+// errorDataHelper converts the data of a decoded error envelope, held as a generic JSON value,
+// into the data type of the typed error CodeTo<Error> returns
+const errorDataHelper = `// decodeErrorData converts the data of an error envelope into the type its code declares
+func decodeErrorData(data any, target any) error {
+	if data == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, target)
+}
+
+`
+
+// rpcMethodTable is the RPCMethod type describing the methods in the method table, and its
+// lookup function
+const rpcMethodTable = `// RPCMethod describes a JSON-RPC method, so that middleware (logging, authorization,
+// metrics) can handle calls generically
+type RPCMethod struct {
+	Name           string     // Method name sent on the wire
+	Params         string     // Go type of the params, empty when the method takes none
+	Result         string     // Go type of the result, empty for notifications
+	ParamStructure string     // How the params are given: by-name, by-position or either
+	Deprecated     bool       // Whether the method is deprecated
+	Errors         []int      // Codes of the application errors the method declares
+	NewParams      func() any // Returns a pointer to new params, nil when the method takes none
+	NewResult      func() any // Returns a pointer to a new result, nil for notifications
+}
+
+// Notification reports whether the calls of the method are notifications, without result
+func (m *RPCMethod) Notification() bool {
+	return m.NewResult == nil
+}
+
+// LookupRPCMethod returns the method of RPCMethods with the given name
+func LookupRPCMethod(name string) (*RPCMethod, bool) {
+	i, ok := rpcMethodIndex[name]
+	if !ok {
+		return nil, false
+	}
+	return &RPCMethods[i], true
+}
+
+// rpcMethodIndex are the indexes in RPCMethods, by method name
+var rpcMethodIndex = func() map[string]int {
+	index := make(map[string]int, len(RPCMethods))
+	for i, method := range RPCMethods {
+		index[method.Name] = i
+	}
+	return index
+}()
+
+`
+
+// generateRPCMethodTable writes RPCMethods, the table describing the generated methods with
+// the constructors of their Params and Result, so that middleware can decode and inspect calls
+// without reflection
+func generateRPCMethodTable(b *strings.Builder, methods []rpcMethod, definitions map[string]any, errType string) {
+	b.WriteString(rpcMethodTable)
+	b.WriteString("// RPCMethods are the JSON-RPC methods, in the order of the document\n")
+	b.WriteString("var RPCMethods = []RPCMethod{\n")
+	for _, method := range methods {
+		if !method.generated(definitions) {
+			continue
+		}
+		fmt.Fprintf(b, "\t{\n\t\tName: Method%s,\n", method.goName)
+		if method.params != "" {
+			fmt.Fprintf(b, "\t\tParams: %q,\n", method.params)
+		}
+		if method.result != "" {
+			fmt.Fprintf(b, "\t\tResult: %q,\n", method.result)
+		}
+		if structure := paramStructure(definitions[method.params]); structure != "" {
+			fmt.Fprintf(b, "\t\tParamStructure: %q,\n", structure)
+		}
+		if method.deprecated {
+			b.WriteString("\t\tDeprecated: true,\n")
+		}
+		if len(method.errors) > 0 {
+			codes := make([]string, len(method.errors))
+			for i, failure := range method.errors {
+				codes[i] = errType + "Code" + failure.name
+			}
+			fmt.Fprintf(b, "\t\tErrors: []int{%s},\n", strings.Join(codes, ", "))
+		}
+		if method.params != "" {
+			fmt.Fprintf(b, "\t\tNewParams: func() any { return new(%s) },\n", method.params)
+		}
+		if method.result != "" {
+			fmt.Fprintf(b, "\t\tNewResult: func() any { return new(%s) },\n", method.result)
+		}
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+// writePositionalParams writes the method decoding the Params of a method accepting its
+// parameters by position from a JSON array of its fields in parameter order, or by name from
+// a JSON object. When byPosition, it also writes the method encoding them as an array, in
+// which unset optional parameters are sent as null.
+func writePositionalParams(b *strings.Builder, typeName string, fields []string, byPosition bool) {
+	if byPosition {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = "p." + field
+		}
+		fmt.Fprintf(b, "// MarshalJSON encodes the parameters by position, as the method expects them\n")
+		fmt.Fprintf(b, "func (p %s) MarshalJSON() ([]byte, error) {\n", typeName)
+		fmt.Fprintf(b, "\treturn json.Marshal([]any{%s})\n}\n\n", strings.Join(values, ", "))
+	}
+
+	fmt.Fprintf(b, "// UnmarshalJSON decodes the parameters by position, or by name from a JSON object\n")
+	fmt.Fprintf(b, "func (p *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+	b.WriteString("\tif trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {\n")
+	fmt.Fprintf(b, "\t\ttype plain %s\n\t\treturn json.Unmarshal(data, (*plain)(p))\n\t}\n", typeName)
+	b.WriteString("\tvar values []json.RawMessage\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &values); err != nil {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(b, "\tif len(values) > %d {\n", len(fields))
+	fmt.Fprintf(b, "\t\treturn fmt.Errorf(\"expected at most %d parameters, got %%d\", len(values))\n\t}\n", len(fields))
+	b.WriteString("\tfor i, value := range values {\n\t\tvar err error\n\t\tswitch i {\n")
+	for i, field := range fields {
+		fmt.Fprintf(b, "\t\tcase %d:\n\t\t\terr = json.Unmarshal(value, &p.%s)\n", i, field)
+	}
+	b.WriteString("\t\t}\n\t\tif err != nil {\n\t\t\treturn fmt.Errorf(\"parameter %d: %w\", i, err)\n\t\t}\n\t}\n")
+	b.WriteString("\treturn nil\n}\n\n")
+}
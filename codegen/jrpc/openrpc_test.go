@@ -0,0 +1,292 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+// paramStructureDocument has a method per paramStructure: add by position, greet by name and
+// echo either way
+const paramStructureDocument = `{
+	"openrpc": "1.2.6",
+	"info": {"title": "Calculator", "version": "1"},
+	"methods": [
+		{
+			"name": "add",
+			"paramStructure": "by-position",
+			"params": [
+				{"name": "a", "required": true, "schema": {"type": "integer"}},
+				{"name": "b", "required": true, "schema": {"type": "integer"}}
+			],
+			"result": {"name": "sum", "schema": {"type": "integer"}}
+		},
+		{
+			"name": "greet",
+			"paramStructure": "by-name",
+			"params": [{"name": "name", "required": true, "schema": {"type": "string"}}],
+			"result": {"name": "greeting", "schema": {"type": "string"}}
+		},
+		{
+			"name": "echo",
+			"params": [
+				{"name": "text", "required": true, "schema": {"type": "string"}},
+				{"name": "times", "schema": {"type": "integer"}}
+			],
+			"result": {"name": "echoed", "schema": {"type": "string"}}
+		}
+	],
+	"components": {"schemas": {"Note": {"type": "string"}}}
+}`
+
+// paramStructureHandlers are the handlers of the methods of paramStructureDocument, and a
+// Transport recording the params of the requests it dispatches to them
+const paramStructureHandlers = `package generated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+type handlers struct{}
+
+func (handlers) Add(ctx context.Context, params AddParams) (*AddResult, error) {
+	sum := params.A + params.B
+	return &sum, nil
+}
+
+func (handlers) Greet(ctx context.Context, params GreetParams) (*GreetResult, error) {
+	greeting := "hello " + params.Name
+	return &greeting, nil
+}
+
+func (handlers) Echo(ctx context.Context, params EchoParams) (*EchoResult, error) {
+	return &params.Text, nil
+}
+
+type recorder struct {
+	params json.RawMessage
+}
+
+func (r *recorder) Call(ctx context.Context, request *Request) (*Response, error) {
+	r.params = request.Params
+	return NewDispatcher(handlers{}).Handle(ctx, request), nil
+}
+`
+
+// clientParamsTest is the template of a test of the params a generated client method sends:
+// %[1]s is the name of the test, %[2]s the call, %[3]q the encoded params and %[4]q the result
+const clientParamsTest = `
+func TestClient%[1]s(t *testing.T) {
+	transport := new(recorder)
+	result, err := NewClient(transport).%[2]s
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(transport.params); got != %[3]q {
+		t.Errorf("params = %%s, want %%s", got, %[3]q)
+	}
+	if got := fmt.Sprint(*result); got != %[4]q {
+		t.Errorf("result = %%s, want %%s", got, %[4]q)
+	}
+}
+`
+
+// dispatchTest is the template of a table-driven test of the answers of the generated
+// Dispatcher to requests: %[1]s are the rows of the table
+const dispatchTest = `
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		result  string
+		code    int
+	}{
+%[1]s	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var response Response
+			if err := json.Unmarshal(NewDispatcher(handlers{}).HandleMessage(context.Background(), []byte(test.request)), &response); err != nil {
+				t.Fatal(err)
+			}
+			if test.code != 0 {
+				if response.Error == nil || response.Error.Code != test.code {
+					t.Errorf("error = %%v, want code %%d", response.Error, test.code)
+				}
+				return
+			}
+			if response.Error != nil {
+				t.Fatalf("unexpected error %%v", response.Error)
+			}
+			if got := string(response.Result); got != test.result {
+				t.Errorf("result = %%s, want %%s", got, test.result)
+			}
+		})
+	}
+}
+`
+
+func TestParamStructure(t *testing.T) {
+	clients := []struct {
+		name   string
+		call   string
+		params string
+		result string
+	}{
+		{"ByPosition", "Add(context.Background(), AddParams{A: 1, B: 2})", `[1,2]`, "3"},
+		{"ByName", `Greet(context.Background(), GreetParams{Name: "Ada"})`, `{"name":"Ada"}`, "hello Ada"},
+		{"Either", `Echo(context.Background(), EchoParams{Text: "hi"})`, `{"text":"hi"}`, "hi"},
+	}
+	requests := []struct {
+		name    string
+		request string
+		result  string
+		code    int
+	}{
+		{"by-position as array", `{"jsonrpc": "2.0", "id": 1, "method": "add", "params": [1, 2]}`, `3`, 0},
+		{"by-position as object", `{"jsonrpc": "2.0", "id": 1, "method": "add", "params": {"a": 1, "b": 2}}`, "", -32602},
+		{"by-position missing required", `{"jsonrpc": "2.0", "id": 1, "method": "add", "params": [1]}`, "", -32602},
+		{"by-name as object", `{"jsonrpc": "2.0", "id": 1, "method": "greet", "params": {"name": "Ada"}}`, `"hello Ada"`, 0},
+		{"by-name as array", `{"jsonrpc": "2.0", "id": 1, "method": "greet", "params": ["Ada"]}`, "", -32602},
+		{"either as object", `{"jsonrpc": "2.0", "id": 1, "method": "echo", "params": {"text": "hi"}}`, `"hi"`, 0},
+		{"either as array", `{"jsonrpc": "2.0", "id": 1, "method": "echo", "params": ["hi", 2]}`, `"hi"`, 0},
+		{"either missing required", `{"jsonrpc": "2.0", "id": 1, "method": "echo", "params": {"times": 2}}`, "", -32602},
+	}
+
+	var source bytes.Buffer
+	options := &GeneratorOptions{PackageName: "generated", RPCClient: true, RPCServer: true}
+	if err := GenerateTypesTo(&source, []byte(paramStructureDocument), options); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests strings.Builder
+	tests.WriteString(paramStructureHandlers)
+	for _, client := range clients {
+		fmt.Fprintf(&tests, clientParamsTest, client.name, client.call, client.params, client.result)
+	}
+	var rows strings.Builder
+	for _, request := range requests {
+		fmt.Fprintf(&rows, "\t\t{%q, %q, %q, %d},\n", request.name, request.request, request.result, request.code)
+	}
+	fmt.Fprintf(&tests, dispatchTest, rows.String())
+
+	gentest.Run(t, map[string]string{
+		"rpc.go":      source.String(),
+		"rpc_test.go": tests.String(),
+	}, "test", "./...")
+}
+
+// errorDataDocument declares an error per way of declaring its data: an inline schema, a
+// $ref to a component schema, and no data
+const errorDataDocument = `{
+	"openrpc": "1.2.6",
+	"info": {"title": "Theater", "version": "1"},
+	"methods": [
+		{
+			"name": "reserve",
+			"params": [{"name": "seat", "required": true, "schema": {"type": "string"}}],
+			"result": {"name": "ticket", "schema": {"type": "string"}},
+			"errors": [
+				{"$ref": "#/components/errors/RateLimited"},
+				{"$ref": "#/components/errors/SeatTaken"},
+				{"$ref": "#/components/errors/Closed"}
+			]
+		}
+	],
+	"components": {
+		"schemas": {
+			"Seat": {"type": "object", "properties": {"row": {"type": "string"}, "number": {"type": "integer"}}, "required": ["row", "number"]}
+		},
+		"errors": {
+			"RateLimited": {"code": 429, "message": "Too many reservations", "data": {"type": "object", "properties": {"retryAfter": {"type": "integer"}}, "required": ["retryAfter"]}},
+			"SeatTaken": {"code": 409, "message": "Seat taken", "data": {"$ref": "#/components/schemas/Seat"}},
+			"Closed": {"code": 503, "message": "Closed"}
+		}
+	}
+}`
+
+// errorDataTest checks that the typed errors of errorDataDocument carry their data through
+// the JSON encoding of a response
+const errorDataTest = `package generated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type handlers struct{}
+
+func (handlers) Reserve(ctx context.Context, params ReserveParams) (*ReserveResult, error) {
+	switch params.Seat {
+	case "busy":
+		return nil, &RateLimitedError{Data: &RateLimitedErrorData{RetryAfter: 30}}
+	case "taken":
+		return nil, &SeatTakenError{Data: &Seat{Row: "F", Number: 12}}
+	}
+	return nil, &ClosedError{}
+}
+
+type transport struct{}
+
+func (transport) Call(ctx context.Context, request *Request) (*Response, error) {
+	message, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	var response Response
+	err = json.Unmarshal(NewDispatcher(handlers{}).HandleMessage(ctx, message), &response)
+	return &response, err
+}
+
+func TestErrorData(t *testing.T) {
+	client := NewClient(transport{})
+
+	_, err := client.Reserve(context.Background(), ReserveParams{Seat: "busy"})
+	var limited *RateLimitedError
+	if !errors.As(err, &limited) || limited.Data == nil || limited.Data.RetryAfter != 30 {
+		t.Errorf("error = %#v, want a RateLimitedError retrying after 30", err)
+	}
+
+	_, err = client.Reserve(context.Background(), ReserveParams{Seat: "taken"})
+	var taken *SeatTakenError
+	if !errors.As(err, &taken) || taken.Data == nil || *taken.Data != (Seat{Row: "F", Number: 12}) {
+		t.Errorf("error = %#v, want a SeatTakenError for F12", err)
+	}
+
+	_, err = client.Reserve(context.Background(), ReserveParams{Seat: "front"})
+	var closed *ClosedError
+	if !errors.As(err, &closed) {
+		t.Errorf("error = %#v, want a ClosedError", err)
+	}
+	response := NewDispatcher(handlers{}).HandleMessage(context.Background(), []byte(` + "`" + `{"jsonrpc": "2.0", "id": 1, "method": "reserve", "params": {"seat": "front"}}` + "`" + `))
+	if strings.Contains(string(response), "data") {
+		t.Errorf("response = %s, want no data", response)
+	}
+}
+`
+
+func TestErrorData(t *testing.T) {
+	var source bytes.Buffer
+	options := &GeneratorOptions{PackageName: "generated", RPCClient: true, RPCServer: true}
+	if err := GenerateTypesTo(&source, []byte(errorDataDocument), options); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"Data *RateLimitedErrorData", "Data *Seat", "Data any"} {
+		if !strings.Contains(source.String(), field) {
+			t.Errorf("generated code does not declare %s", field)
+		}
+	}
+
+	gentest.Run(t, map[string]string{
+		"rpc.go":      source.String(),
+		"rpc_test.go": errorDataTest,
+	}, "test", "./...")
+}
@@ -0,0 +1,104 @@
+package jrpc
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// propertyOrderKey is the extension under which the declaration order of a schema's properties
+// is recorded when GeneratorOptions.PreserveOrder is set
+const propertyOrderKey = "x-go-property-order"
+
+// namedSchemaKeys are keywords whose values map names to schemas rather than being schemas
+// themselves, so no property order is recorded on them
+var namedSchemaKeys = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"definitions":       true,
+	"$defs":             true,
+	"dependentSchemas":  true,
+	"schemas":           true,
+}
+
+// recordPropertyOrder parses the source of a schema document (JSON being a subset of YAML)
+// and stores the declaration order of every `properties` keyword in the decoded document
+func recordPropertyOrder(data []byte, document map[string]any) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		annotatePropertyOrder(root.Content[0], document, false)
+	}
+	return nil
+}
+
+// annotatePropertyOrder walks a YAML node alongside its decoded value, recording the order of
+// `properties` keys on the schemas holding them
+func annotatePropertyOrder(node *yaml.Node, value any, named bool) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, child := node.Content[i].Value, node.Content[i+1]
+			if !named && key == "properties" && child.Kind == yaml.MappingNode {
+				order := make([]any, 0, len(child.Content)/2)
+				for j := 0; j+1 < len(child.Content); j += 2 {
+					order = append(order, child.Content[j].Value)
+				}
+				v[propertyOrderKey] = order
+			}
+			annotatePropertyOrder(child, v[key], !named && namedSchemaKeys[key])
+		}
+	case []any:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range v {
+			if i < len(node.Content) {
+				annotatePropertyOrder(node.Content[i], item, false)
+			}
+		}
+	}
+}
+
+// PropertyNames returns the names of the properties of a schema in their declaration order,
+// when it was recorded by Bundle with PreserveOrder, or else in alphabetical order
+func PropertyNames(schema map[string]any) []string {
+	properties, _ := schema["properties"].(map[string]any)
+	return propertyNames(schema, properties)
+}
+
+// propertyNames returns the names of a schema's properties in their recorded declaration
+// order, or sorted alphabetically when no order was recorded. Properties missing from the
+// recorded order follow in alphabetical order.
+func propertyNames(schema map[string]any, properties map[string]any) []string {
+	names := make([]string, 0, len(properties))
+	seen := make(map[string]bool, len(properties))
+	if order, ok := schema[propertyOrderKey].([]any); ok {
+		for _, entry := range order {
+			if name, ok := entry.(string); ok && !seen[name] {
+				if _, exists := properties[name]; exists {
+					names = append(names, name)
+					seen[name] = true
+				}
+			}
+		}
+	}
+
+	var rest []string
+	for name := range properties {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
@@ -0,0 +1,112 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// overflowValueType returns the Go value type of the AdditionalProperties map generated for a
+// struct that declares both properties and a typed additionalProperties schema
+func overflowValueType(defMap map[string]any, definitions map[string]any) (string, bool) {
+	additionalProps, ok := defMap["additionalProperties"].(map[string]any)
+	if !ok || !isStructDefinition(defMap, definitions) {
+		return "", false
+	}
+	return determineGoType(additionalProps, definitions), true
+}
+
+// containsOverflowStruct reports whether any definition is generated with an AdditionalProperties map
+func containsOverflowStruct(definitions map[string]any) bool {
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok {
+			if _, ok := overflowValueType(defMap, definitions); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declaredPropertyNames returns the JSON names of all properties a struct definition declares,
+// including those of embedded allOf members
+func declaredPropertyNames(defMap map[string]any, definitions map[string]any, visited map[string]bool) []string {
+	properties, _ := defMap["properties"].(map[string]any)
+
+	var names []string
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, ok := mergeAllOf(defMap, definitions)
+		if !ok {
+			return nil
+		}
+		properties = merged.properties
+		for _, embedded := range merged.embedded {
+			if visited[embedded] {
+				continue
+			}
+			visited[embedded] = true
+			if embeddedDef, ok := definitions[embedded].(map[string]any); ok {
+				names = append(names, declaredPropertyNames(embeddedDef, definitions, visited)...)
+			}
+		}
+	}
+
+	for propName := range properties {
+		names = append(names, propName)
+	}
+	return names
+}
+
+// generateOverflowMethods writes MarshalJSON and UnmarshalJSON methods that round-trip keys
+// not declared in properties through the struct's AdditionalProperties map
+func generateOverflowMethods(out *bytes.Buffer, typeName string, valueType string, declared []string, consts []constField) error {
+	sortedDeclared := append([]string(nil), declared...)
+	sort.Strings(sortedDeclared)
+
+	quoted := make([]string, 0, len(sortedDeclared))
+	for _, name := range sortedDeclared {
+		quoted = append(quoted, strconv.Quote(name))
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// MarshalJSON encodes %s, writing AdditionalProperties as top-level keys\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) MarshalJSON() ([]byte, error) {\n", typeName)
+	fmt.Fprintf(&b, "\ttype alias %s\n", typeName)
+	b.WriteString(constAssignments(consts))
+	b.WriteString("\tdata, err := json.Marshal(alias(x))\n")
+	b.WriteString("\tif err != nil || len(x.AdditionalProperties) == 0 {\n\t\treturn data, err\n\t}\n\n")
+	b.WriteString("\tfields := make(map[string]json.RawMessage)\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &fields); err != nil {\n\t\treturn nil, err\n\t}\n")
+	b.WriteString("\tfor key, value := range x.AdditionalProperties {\n")
+	b.WriteString("\t\tif _, declared := fields[key]; declared {\n\t\t\tcontinue\n\t\t}\n")
+	b.WriteString("\t\tencoded, err := json.Marshal(value)\n")
+	b.WriteString("\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+	b.WriteString("\t\tfields[key] = encoded\n\t}\n\n")
+	b.WriteString("\treturn json.Marshal(fields)\n}\n\n")
+
+	fmt.Fprintf(&b, "// UnmarshalJSON decodes %s, collecting undeclared keys into AdditionalProperties\n", typeName)
+	fmt.Fprintf(&b, "func (x *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+	fmt.Fprintf(&b, "\ttype alias %s\n", typeName)
+	b.WriteString("\tvar decoded alias\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &decoded); err != nil {\n\t\treturn err\n\t}\n\n")
+	b.WriteString("\tvar fields map[string]json.RawMessage\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &fields); err != nil {\n\t\treturn err\n\t}\n")
+	if len(quoted) > 0 {
+		fmt.Fprintf(&b, "\tfor _, key := range []string{%s} {\n\t\tdelete(fields, key)\n\t}\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("\n\tdecoded.AdditionalProperties = nil\n")
+	b.WriteString("\tif len(fields) > 0 {\n")
+	fmt.Fprintf(&b, "\t\tdecoded.AdditionalProperties = make(map[string]%s, len(fields))\n", valueType)
+	b.WriteString("\t\tfor key, raw := range fields {\n")
+	fmt.Fprintf(&b, "\t\t\tvar value %s\n", valueType)
+	b.WriteString("\t\t\tif err := json.Unmarshal(raw, &value); err != nil {\n")
+	b.WriteString("\t\t\t\treturn fmt.Errorf(\"additional property %q: %w\", key, err)\n\t\t\t}\n")
+	b.WriteString("\t\t\tdecoded.AdditionalProperties[key] = value\n\t\t}\n\t}\n\n")
+	fmt.Fprintf(&b, "\t*x = %s(decoded)\n\treturn nil\n}\n\n", typeName)
+
+	_, err := out.WriteString(b.String())
+	return err
+}
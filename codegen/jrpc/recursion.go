@@ -0,0 +1,84 @@
+package jrpc
+
+import "sort"
+
+// isRecursiveField reports whether a struct field of type fieldType, stored by value inside
+// typeName, would make typeName contain itself. Such fields must be pointers, since Go does
+// not allow a struct to contain itself by value (e.g. a required `parent: {$ref: Node}`).
+// Slices, maps and pointers already provide the indirection recursive types need.
+func isRecursiveField(typeName string, fieldType string, definitions map[string]any) bool {
+	return reachesByValue(fieldType, typeName, definitions, map[string]bool{})
+}
+
+// reachesByValue reports whether definition from holds definition to by value, directly or
+// through other definitions it holds by value
+func reachesByValue(from string, to string, definitions map[string]any, visited map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	defMap, ok := definitions[from].(map[string]any)
+	if !ok || isOverriddenDefinition(defMap) {
+		return false
+	}
+
+	for _, ref := range valueReferences(defMap, definitions) {
+		if reachesByValue(ref, to, definitions, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueReferences returns the definitions a definition holds by value: the target of an
+// alias, embedded allOf members, and non-pointer fields whose type is another definition
+func valueReferences(defMap map[string]any, definitions map[string]any) []string {
+	properties, _ := defMap["properties"].(map[string]any)
+	required, _ := defMap["required"].([]any)
+
+	var refs []string
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		merged, ok := mergeAllOf(defMap, definitions)
+		if !ok {
+			return nil
+		}
+		if merged.aliasOf != "" {
+			return []string{merged.aliasOf}
+		}
+		refs = append(refs, merged.embedded...)
+		properties, required = merged.properties, merged.required
+	}
+
+	requiredFields := make(map[string]bool)
+	for _, field := range required {
+		if fieldName, ok := field.(string); ok {
+			requiredFields[fieldName] = true
+		}
+	}
+
+	propNames := make([]string, 0, len(properties))
+	for propName := range properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		propMap, ok := properties[propName].(map[string]any)
+		if !ok {
+			continue
+		}
+		if !requiredFields[propName] && !hasDefaultValue(propMap) {
+			continue
+		}
+		if ref, ok := propMap["$ref"].(string); ok {
+			refs = append(refs, refName(ref))
+		}
+	}
+
+	return refs
+}
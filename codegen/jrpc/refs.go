@@ -0,0 +1,334 @@
+package jrpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inference-gateway/tools/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// remoteRefResolver fetches remote $ref targets and inlines them as local definitions
+type remoteRefResolver struct {
+	cacheDir  string
+	offline   bool
+	ordered   bool
+	client    *http.Client
+	documents map[string]map[string]any
+	sources   map[string]string
+
+	root      string          // URL of the document being bundled, which relative refs resolve against (see Bundle)
+	prefix    string          // Pointer to the definitions inlined targets are added to
+	embedding map[string]bool // Refs whose targets are being embedded, to reject cycles
+}
+
+// newRemoteRefResolver creates a resolver using the cache and offline settings from options
+func newRemoteRefResolver(options *GeneratorOptions) (*remoteRefResolver, error) {
+	cacheDir := options.RefCacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine cache directory for remote refs: %w", err)
+		}
+		cacheDir = filepath.Join(userCacheDir, "inference-gateway", "codegen", "refs")
+	}
+
+	return &remoteRefResolver{
+		cacheDir:  cacheDir,
+		offline:   options.Offline,
+		ordered:   options.PreserveOrder,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		documents: make(map[string]map[string]any),
+		sources:   make(map[string]string),
+		prefix:    "#/definitions/",
+		embedding: make(map[string]bool),
+	}, nil
+}
+
+// isRemoteRef reports whether a $ref points to an HTTP(S) document
+func isRemoteRef(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+// resolveRemoteRefs walks all definitions of a schema document, fetches every remote $ref
+// target and adds it to definitions under the last segment of its JSON pointer. The $ref is
+// rewritten to point at the local definition, so the rest of the generator never sees remote
+// references.
+func resolveRemoteRefs(schema map[string]any, definitions map[string]any, options *GeneratorOptions) error {
+	resolver, err := newRemoteRefResolver(options)
+	if err != nil {
+		return err
+	}
+
+	defNames := make([]string, 0, len(definitions))
+	for defName := range definitions {
+		defNames = append(defNames, defName)
+	}
+	sort.Strings(defNames)
+
+	for _, defName := range defNames {
+		if err := resolver.walk(definitions[defName], "", definitions); err != nil {
+			return &codegen.SchemaError{Pointer: definitionPointer(schema, defName), Err: err}
+		}
+	}
+
+	return nil
+}
+
+// walk visits a schema node and resolves remote refs, interpreting relative refs against base
+// when the node itself was fetched from a remote document
+func (r *remoteRefResolver) walk(node any, base string, definitions map[string]any) error {
+	switch value := node.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			absolute, err := r.absoluteRef(ref, base)
+			if err != nil {
+				return err
+			}
+			if absolute != "" && r.root != "" {
+				schema, err := r.isSchemaTarget(absolute)
+				if err != nil {
+					return err
+				}
+				if !schema {
+					return r.embed(value, absolute, definitions)
+				}
+			}
+			if absolute != "" {
+				localRef, err := r.inline(absolute, definitions)
+				if err != nil {
+					return err
+				}
+				value["$ref"] = localRef
+			}
+		}
+
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if key == "$ref" {
+				continue
+			}
+			if err := r.walk(value[key], base, definitions); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if err := r.walk(item, base, definitions); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// absoluteRef returns the absolute URL of a ref, or an empty string when the ref is local
+// to the schema being generated
+func (r *remoteRefResolver) absoluteRef(ref string, base string) (string, error) {
+	if isRemoteRef(ref) {
+		return ref, nil
+	}
+
+	if base == "" {
+		if r.root == "" || strings.HasPrefix(ref, "#") {
+			return "", nil
+		}
+		base = r.root
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid remote ref base %q: %w", base, err)
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid $ref %q in %s: %w", ref, base, err)
+	}
+
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+// inline fetches the target of an absolute ref, registers it as a definition and returns
+// the local ref that replaces it
+func (r *remoteRefResolver) inline(ref string, definitions map[string]any) (string, error) {
+	documentURL, fragment, _ := strings.Cut(ref, "#")
+
+	document, err := r.document(documentURL)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := resolvePointer(document, fragment)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve remote $ref %s: %w", ref, err)
+	}
+
+	name := refName(fragment)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(documentURL), filepath.Ext(documentURL))
+	}
+
+	localRef := r.prefix + name
+
+	if source, seen := r.sources[name]; seen {
+		if source != ref {
+			return "", fmt.Errorf("remote $ref %s conflicts with definition %q resolved from %s", ref, name, source)
+		}
+		return localRef, nil
+	}
+
+	if _, exists := definitions[name]; exists {
+		return "", fmt.Errorf("remote $ref %s conflicts with local definition %q", ref, name)
+	}
+
+	r.sources[name] = ref
+	definitions[name] = target
+
+	if err := r.walk(target, documentURL, definitions); err != nil {
+		return "", err
+	}
+
+	return localRef, nil
+}
+
+// document returns the parsed remote document, reading it from the cache when possible
+func (r *remoteRefResolver) document(documentURL string) (map[string]any, error) {
+	if document, ok := r.documents[documentURL]; ok {
+		return document, nil
+	}
+
+	data, err := r.fetch(documentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	if strings.HasSuffix(documentURL, ".yaml") || strings.HasSuffix(documentURL, ".yml") {
+		err = yaml.Unmarshal(data, &document)
+	} else if err = json.Unmarshal(data, &document); err != nil {
+		err = yaml.Unmarshal(data, &document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse remote schema %s: %w", documentURL, err)
+	}
+	document = stringKeys(document).(map[string]any)
+	if r.ordered {
+		if err := recordPropertyOrder(data, document); err != nil {
+			return nil, fmt.Errorf("failed to parse remote schema %s: %w", documentURL, err)
+		}
+	}
+
+	r.documents[documentURL] = document
+	return document, nil
+}
+
+// fetch downloads a remote document, storing it in the on-disk cache. file:// documents are
+// read directly.
+func (r *remoteRefResolver) fetch(documentURL string) ([]byte, error) {
+	if strings.HasPrefix(documentURL, "file://") {
+		location, err := url.Parse(documentURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema location %s: %w", documentURL, err)
+		}
+		data, err := os.ReadFile(filepath.FromSlash(location.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read referenced schema: %w", err)
+		}
+		return data, nil
+	}
+
+	sum := sha256.Sum256([]byte(documentURL))
+	cachePath := filepath.Join(r.cacheDir, hex.EncodeToString(sum[:]))
+
+	if data, err := os.ReadFile(cachePath); err == nil {
+		return data, nil
+	}
+
+	if r.offline {
+		return nil, fmt.Errorf("remote $ref %s is not cached and offline mode is enabled", documentURL)
+	}
+
+	resp, err := r.client.Get(documentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote schema %s: %w", documentURL, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn(fmt.Sprintf("Failed to close response body: %v", closeErr))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch remote schema %s: unexpected status %s", documentURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote schema %s: %w", documentURL, err)
+	}
+
+	if err := os.MkdirAll(r.cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create ref cache directory: %w", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write ref cache: %w", err)
+	}
+
+	return data, nil
+}
+
+// resolvePointer resolves a JSON pointer fragment (e.g. "/components/schemas/Task") in a document
+func resolvePointer(document map[string]any, fragment string) (any, error) {
+	if fragment == "" || fragment == "/" {
+		return document, nil
+	}
+
+	var current any = document
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("pointer segment %q not found", token)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("pointer segment %q does not address an object or array", token)
+		}
+	}
+
+	return current, nil
+}
+
+// refName returns the last segment of a JSON pointer, which is used as the Go type name
+func refName(ref string) string {
+	parts := strings.Split(ref, "/")
+	return parts[len(parts)-1]
+}
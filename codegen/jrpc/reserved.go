@@ -0,0 +1,191 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// predeclaredIdentifiers are the types, constants, zero value and builtin functions of the Go
+// universe block. A type named after one of them would shadow it in the whole package.
+var predeclaredIdentifiers = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true,
+	"new": true, "panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// generatedPackageNames are the names of the packages generated code may import
+var generatedPackageNames = map[string]bool{
+	"bytes": true, "errors": true, "fmt": true, "json": true, "reflect": true, "regexp": true,
+	"strconv": true, "strings": true, "time": true, "utf8": true,
+}
+
+// generatedHelperNames are the unexported package-level helpers of generated code, read from
+// the helper sources the generator writes so that new helpers are reserved as they are added
+var generatedHelperNames = func() map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range DeclaredNames(helperSources()) {
+		names[name] = true
+	}
+	return names
+}()
+
+// helperSources returns the helpers generated code may declare, whatever the options
+func helperSources() string {
+	var b bytes.Buffer
+	_ = generateUnionHelpers(&b, unionHelpers{variants: true, anyOf: true})
+	_ = generateValidationHelpers(&b)
+	_ = generateSampleHelpers(&b)
+	_ = generateDurationHelpers(&b, "Duration")
+	b.WriteString("var contractCases, methodExamples any\n")
+	b.WriteString(contractTestHelpers)
+	b.WriteString(methodExampleHelpers)
+	b.WriteString(fuzzTestHelpers)
+	b.WriteString(benchmarkHelpers)
+	b.WriteString(rpcMethodTable)
+	b.WriteString(errorDataHelper)
+	fmt.Fprintf(&b, rpcTransports, "Request", "Response")
+	fmt.Fprintf(&b, rpcDispatcher, "Request", "Response", "Error")
+	return b.String()
+}
+
+// DeclaredNames returns the unexported package-level identifiers declared by Go source
+// without a package clause, such as the helpers a generator writes next to the models
+func DeclaredNames(source string) []string {
+	file, _ := parser.ParseFile(token.NewFileSet(), "", "package helpers\n"+source, parser.SkipObjectResolution)
+	if file == nil {
+		return nil
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	unexported := names[:0]
+	for _, name := range names {
+		if !ast.IsExported(name) && name != "_" {
+			unexported = append(unexported, name)
+		}
+	}
+	return unexported
+}
+
+// reservedFieldNames are the methods generated on structs. A field of the same name would not
+// compile, so such properties get a "Field" suffix (validate → ValidateField) whether or not
+// the method is generated, keeping field names stable across options.
+var reservedFieldNames = map[string]bool{
+	"ApplyDefaults": true,
+	"MarshalJSON":   true,
+	"UnmarshalJSON": true,
+	"Validate":      true,
+}
+
+// reservedDefinitionName reports why a definition name cannot be used as a Go type name as
+// is: because it is a Go keyword, a predeclared identifier, the name of an imported package
+// or of a generated helper
+func reservedDefinitionName(name string, packages map[string]bool, helpers map[string]bool) (string, bool) {
+	switch {
+	case token.IsKeyword(name):
+		return "Go keyword", true
+	case predeclaredIdentifiers[name]:
+		return "predeclared Go identifier", true
+	case generatedPackageNames[name] || packages[name]:
+		return "imported package name", true
+	case generatedHelperNames[name] || helpers[name]:
+		return "generated helper name", true
+	}
+	return "", false
+}
+
+// renameReservedDefinitions renames the definitions named after a Go keyword, a predeclared
+// identifier, an imported package or a generated helper to an exported name with a trailing
+// underscore (type → Type_, string → String_) and rewrites every $ref pointing at them
+func renameReservedDefinitions(definitions map[string]any, options *GeneratorOptions) {
+	packages := make(map[string]bool)
+	if options.DecimalImport != "" {
+		packages[packageNameOf(options.DecimalImport)] = true
+	}
+	for _, target := range options.ImportMappings {
+		if mapped, err := parseMappedType(target); err == nil {
+			packages[packageNameOf(mapped.importPath)] = true
+		}
+	}
+	for _, spec := range extensionImports(definitions) {
+		if name, _, ok := strings.Cut(spec, " "); ok {
+			packages[name] = true
+		} else {
+			packages[packageNameOf(spec)] = true
+		}
+	}
+	helpers := make(map[string]bool)
+	for _, name := range options.ReservedNames {
+		helpers[name] = true
+	}
+
+	renames := make(map[string]string)
+	for _, defName := range sortedDefinitionNames(definitions) {
+		reason, reserved := reservedDefinitionName(defName, packages, helpers)
+		if !reserved {
+			continue
+		}
+
+		name := exportedName(defName) + "_"
+		for {
+			if _, exists := definitions[name]; !exists {
+				break
+			}
+			name += "_"
+		}
+		definitions[name] = definitions[defName]
+		delete(definitions, defName)
+		renames[defName] = name
+		reportRename(options.RenameReport, "definition", defName, name, reason)
+	}
+
+	if len(renames) > 0 {
+		rewriteRefs(definitions, renames)
+	}
+}
+
+// reportRename writes a line describing an identifier renamed to keep the generated code
+// valid to GeneratorOptions.RenameReport, if set
+func reportRename(report io.Writer, kind string, schemaName string, goName string, reason string) {
+	if report == nil {
+		return
+	}
+	fmt.Fprintf(report, "%s %s renamed to %s (%s)\n", kind, schemaName, goName, reason)
+}
+
+// exportedName returns name with its first letter in upper case
+func exportedName(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
@@ -0,0 +1,42 @@
+package jrpc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenameReservedDefinitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions map[string]any
+		schemaName  string // Name of the definition the holder definition references
+		renamed     string
+		report      string
+	}{
+		{"keyword", map[string]any{"type": map[string]any{"type": "string"}}, "type", "Type_", "definition type renamed to Type_ (Go keyword)\n"},
+		{"predeclared type", map[string]any{"string": map[string]any{"type": "object"}}, "string", "String_", "definition string renamed to String_ (predeclared Go identifier)\n"},
+		{"imported package", map[string]any{"json": map[string]any{"type": "object"}}, "json", "Json_", "definition json renamed to Json_ (imported package name)\n"},
+		{"exported name taken", map[string]any{"error": map[string]any{"type": "object"}, "Error_": map[string]any{"type": "string"}}, "error", "Error__", "definition error renamed to Error__ (predeclared Go identifier)\n"},
+		{"not reserved", map[string]any{"pet": map[string]any{"type": "object"}}, "pet", "pet", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var report bytes.Buffer
+			definitions := map[string]any{"holder": map[string]any{"$ref": "#/definitions/" + test.schemaName}}
+			for name, definition := range test.definitions {
+				definitions[name] = definition
+			}
+			renameReservedDefinitions(definitions, &GeneratorOptions{RenameReport: &report})
+
+			if _, ok := definitions[test.renamed]; !ok {
+				t.Errorf("definitions = %v, want %s", definitions, test.renamed)
+			}
+			if got := definitions["holder"].(map[string]any)["$ref"]; got != "#/definitions/"+test.renamed {
+				t.Errorf("$ref = %v, want #/definitions/%s", got, test.renamed)
+			}
+			if report.String() != test.report {
+				t.Errorf("report = %q, want %q", report.String(), test.report)
+			}
+		})
+	}
+}
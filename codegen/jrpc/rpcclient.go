@@ -0,0 +1,266 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// rpcClientImports are the packages used by the client of an OpenRPC document
+var rpcClientImports = []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "sync", "sync/atomic"}
+
+// rpcTransports are the Transport interface and its HTTP and message connection
+// implementations, written with the client of an OpenRPC document. %[1]s and %[2]s are the
+// names of the Request and Response envelope types.
+const rpcTransports = `// Transport sends JSON-RPC requests. Call returns the response to a request, or nil for a
+// notification (a request without ID).
+type Transport interface {
+	Call(ctx context.Context, request *%[1]s) (*%[2]s, error)
+}
+
+// HTTPTransport sends each request as the body of an HTTP POST
+type HTTPTransport struct {
+	URL    string       // Endpoint of the API
+	Client *http.Client // Client sending the requests (default: http.DefaultClient)
+	Header http.Header  // Headers added to every request, e.g. for authentication
+}
+
+// Call implements Transport
+func (t *HTTPTransport) Call(ctx context.Context, request *%[1]s) (*%[2]s, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the request: %%w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range t.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if request.ID == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, nil
+	}
+	var response %[2]s
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, fmt.Errorf("unexpected response status %%s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to decode the response: %%w", err)
+	}
+	return &response, nil
+}
+
+// MessageConn is a message-oriented connection carrying one JSON-RPC message per message,
+// such as a WebSocket connection adapted by the caller
+type MessageConn interface {
+	WriteMessage(ctx context.Context, data []byte) error
+	ReadMessage(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+// ConnTransport sends requests over a MessageConn and matches the responses to their requests
+// by ID, so that calls can be made concurrently. Messages other than responses to pending
+// calls are ignored.
+type ConnTransport struct {
+	conn MessageConn
+	done chan struct{} // Closed when reading the connection failed
+
+	mu      sync.Mutex
+	pending map[string]chan *%[2]s // By request ID
+	err     error                  // Error that ended reading
+}
+
+// NewConnTransport returns a transport over conn, which it reads until reading fails
+func NewConnTransport(conn MessageConn) *ConnTransport {
+	t := &ConnTransport{conn: conn, done: make(chan struct{}), pending: make(map[string]chan *%[2]s)}
+	go t.read()
+	return t
+}
+
+// Call implements Transport
+func (t *ConnTransport) Call(ctx context.Context, request *%[1]s) (*%[2]s, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the request: %%w", err)
+	}
+	if request.ID == nil {
+		return nil, t.conn.WriteMessage(ctx, data)
+	}
+
+	key := fmt.Sprint(request.ID)
+	responses := make(chan *%[2]s, 1)
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+		return nil, t.err
+	}
+	t.pending[key] = responses
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}()
+
+	if err := t.conn.WriteMessage(ctx, data); err != nil {
+		return nil, err
+	}
+	select {
+	case response := <-responses:
+		return response, nil
+	case <-t.done:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return nil, t.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the connection, failing the pending calls
+func (t *ConnTransport) Close() error {
+	return t.conn.Close()
+}
+
+// read hands the responses read from the connection to the pending calls until reading fails
+func (t *ConnTransport) read() {
+	for {
+		data, err := t.conn.ReadMessage(context.Background())
+		if err != nil {
+			t.mu.Lock()
+			t.err = fmt.Errorf("connection closed: %%w", err)
+			t.mu.Unlock()
+			close(t.done)
+			return
+		}
+		var response %[2]s
+		if err := json.Unmarshal(data, &response); err != nil || response.ID == nil {
+			continue
+		}
+		t.mu.Lock()
+		responses, ok := t.pending[fmt.Sprint(response.ID)]
+		t.mu.Unlock()
+		if ok {
+			select {
+			case responses <- &response:
+			default:
+			}
+		}
+	}
+}
+
+// Client calls the methods of the API over a Transport
+type Client struct {
+	transport Transport
+	lastID    atomic.Int64
+}
+
+// NewClient returns a client sending its calls over transport
+func NewClient(transport Transport) *Client {
+	return &Client{transport: transport}
+}
+
+// call calls method with params, which are omitted when nil, and decodes the result of the
+// response into result
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	request, err := New%[1]s(c.lastID.Add(1), method, params)
+	if err != nil {
+		return err
+	}
+	response, err := c.transport.Call(ctx, request)
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return fmt.Errorf("no response to %%s", method)
+	}
+	return response.DecodeResult(result)
+}
+
+// notify sends a notification calling method with params, which are omitted when nil
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	request, err := New%[1]s(nil, method, params)
+	if err != nil {
+		return err
+	}
+	_, err = c.transport.Call(ctx, request)
+	return err
+}
+
+`
+
+// generateRPCClient writes the Client of an OpenRPC document, with one method per OpenRPC
+// method taking its Params and returning its Result, over the Transport interface and its
+// HTTP and message connection implementations. Methods without result are sent as
+// notifications. Methods whose Params or Result definition was filtered out are skipped.
+func generateRPCClient(out *bytes.Buffer, methods []rpcMethod, definitions map[string]any) error {
+	names := rpcEnvelopeNames(definitions)
+	var b strings.Builder
+	fmt.Fprintf(&b, rpcTransports, names.request, names.response)
+
+	for _, method := range methods {
+		if !method.generated(definitions) {
+			continue
+		}
+		signature, params := method.signature(), "nil"
+		if method.params != "" {
+			params = "params"
+		}
+
+		fmt.Fprintf(&b, "// %s calls %s", method.goName, method.name)
+		if method.result == "" {
+			b.WriteString(", sent as a notification")
+		}
+		b.WriteString("\n")
+		if method.summary != "" {
+			fmt.Fprintf(&b, "// %s\n", strings.Join(strings.Fields(method.summary), " "))
+		}
+		if len(method.errors) > 0 {
+			typeNames := make([]string, len(method.errors))
+			for i, failure := range method.errors {
+				typeNames[i] = "*" + failure.typeName(definitions, names.err)
+			}
+			b.WriteString("//\n")
+			writeWrapped(&b, fmt.Sprintf("Errors are returned as *%s, or as the typed errors the method declares: %s.", names.err, strings.Join(typeNames, ", ")))
+		}
+		if method.deprecated {
+			b.WriteString("//\n// Deprecated: the method is deprecated.\n")
+		}
+
+		if method.result == "" {
+			fmt.Fprintf(&b, "func (c *Client) %s(%s) error {\n", method.goName, signature)
+			fmt.Fprintf(&b, "\treturn c.notify(ctx, Method%s, %s)\n}\n\n", method.goName, params)
+			continue
+		}
+		fmt.Fprintf(&b, "func (c *Client) %s(%s) (*%s, error) {\n", method.goName, signature, method.result)
+		fmt.Fprintf(&b, "\tresult := new(%s)\n", method.result)
+		fmt.Fprintf(&b, "\tif err := c.call(ctx, Method%s, %s, result); err != nil {\n\t\treturn nil, err\n\t}\n", method.goName, params)
+		b.WriteString("\treturn result, nil\n}\n\n")
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// writeWrapped writes text as // comment lines wrapped to the default comment width
+func writeWrapped(b *strings.Builder, text string) {
+	for _, line := range wrapCommentLine(text, defaultCommentWidth) {
+		b.WriteString("// " + line + "\n")
+	}
+}
@@ -0,0 +1,282 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rpcServerImports are the packages used by the server of an OpenRPC document
+var rpcServerImports = []string{"bytes", "context", "encoding/json", "errors", "io", "net/http"}
+
+// rpcDispatcher is the Dispatcher routing requests to a ServerInterface, written with the
+// server of an OpenRPC document, except for its dispatch method. %[1]s, %[2]s and %[3]s are the
+// names of the Request, Response and Error envelope types.
+const rpcDispatcher = `// Dispatcher answers JSON-RPC requests, single or batched, with the handlers of a
+// ServerInterface. It serves HTTP POST requests as an http.Handler; HandleMessage answers the
+// messages of other transports, such as WebSockets.
+type Dispatcher struct {
+	server ServerInterface
+}
+
+// NewDispatcher returns a dispatcher calling the handlers of server
+func NewDispatcher(server ServerInterface) *Dispatcher {
+	return &Dispatcher{server: server}
+}
+
+// ServeHTTP implements http.Handler. Requests made only of notifications are answered with
+// 204 No Content.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "JSON-RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	answer := d.HandleMessage(r.Context(), data)
+	if answer == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(answer)
+}
+
+// HandleMessage answers a JSON-RPC message, a request or a batch of requests, and returns the
+// encoded response, or nil when the message only holds notifications
+func (d *Dispatcher) HandleMessage(ctx context.Context, data []byte) []byte {
+	var answer any
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			answer = New%[3]s%[2]s(nil, &%[3]s{Code: %[3]sCodeParseError, Message: err.Error()})
+		} else if len(messages) == 0 {
+			answer = New%[3]s%[2]s(nil, &%[3]s{Code: %[3]sCodeInvalidRequest, Message: "empty batch"})
+		} else {
+			var responses []*%[2]s
+			for _, message := range messages {
+				if response := d.handleRequest(ctx, message); response != nil {
+					responses = append(responses, response)
+				}
+			}
+			if len(responses) == 0 {
+				return nil
+			}
+			answer = responses
+		}
+	} else if response := d.handleRequest(ctx, data); response != nil {
+		answer = response
+	} else {
+		return nil
+	}
+
+	encoded, err := json.Marshal(answer)
+	if err != nil {
+		encoded, _ = json.Marshal(New%[3]s%[2]s(nil, &%[3]s{Code: %[3]sCodeInternalError, Message: "failed to encode the response"}))
+	}
+	return encoded
+}
+
+// handleRequest decodes and answers a single request
+func (d *Dispatcher) handleRequest(ctx context.Context, data []byte) *%[2]s {
+	var request %[1]s
+	if err := json.Unmarshal(data, &request); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return New%[3]s%[2]s(nil, &%[3]s{Code: %[3]sCodeParseError, Message: err.Error()})
+		}
+		return New%[3]s%[2]s(nil, &%[3]s{Code: %[3]sCodeInvalidRequest, Message: err.Error()})
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return New%[3]s%[2]s(request.ID, &%[3]s{Code: %[3]sCodeInvalidRequest, Message: "not a JSON-RPC 2.0 request"})
+	}
+	response := d.Handle(ctx, &request)
+	if request.ID == nil {
+		return nil
+	}
+	return response
+}
+
+// respond returns the response to request carrying the result or error of its handler, or
+// nil for notifications. Errors that do not convert into an *%[3]s are answered as internal
+// errors, without exposing their message.
+func respond(request *%[1]s, result any, err error) *%[2]s {
+	if request.ID == nil {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *%[3]s
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &%[3]s{Code: %[3]sCodeInternalError, Message: "internal error"}
+		}
+		return New%[3]s%[2]s(request.ID, rpcErr)
+	}
+	response, err := New%[2]s(request.ID, result)
+	if err != nil {
+		return New%[3]s%[2]s(request.ID, &%[3]s{Code: %[3]sCodeInternalError, Message: err.Error()})
+	}
+	return response
+}
+
+// decodeParams decodes the params of request into params, checking that they are given as the
+// paramStructure of the method allows ("by-name", "by-position" or "either"), that the
+// required parameters are present, by name or at their position in order, and that params
+// are valid when they have a Validate method
+func decodeParams(request *%[1]s, params any, structure string, required []string, order []string) error {
+	trimmed := bytes.TrimSpace(request.Params)
+	byPosition := len(trimmed) > 0 && trimmed[0] == '['
+	if byPosition && structure == "by-name" {
+		return &%[3]s{Code: %[3]sCodeInvalidParams, Message: "the parameters must be given by name"}
+	}
+	if len(trimmed) > 0 && !byPosition && structure == "by-position" {
+		return &%[3]s{Code: %[3]sCodeInvalidParams, Message: "the parameters must be given by position"}
+	}
+
+	var present func(name string) bool
+	if byPosition {
+		var values []json.RawMessage
+		if err := json.Unmarshal(request.Params, &values); err != nil {
+			return &%[3]s{Code: %[3]sCodeInvalidParams, Message: err.Error()}
+		}
+		present = func(name string) bool {
+			for i, param := range order {
+				if param == name {
+					return i < len(values) && string(bytes.TrimSpace(values[i])) != "null"
+				}
+			}
+			return false
+		}
+	} else {
+		var values map[string]json.RawMessage
+		if len(request.Params) > 0 {
+			if err := json.Unmarshal(request.Params, &values); err != nil {
+				return &%[3]s{Code: %[3]sCodeInvalidParams, Message: err.Error()}
+			}
+		}
+		present = func(name string) bool {
+			_, ok := values[name]
+			return ok
+		}
+	}
+	for _, name := range required {
+		if !present(name) {
+			return &%[3]s{Code: %[3]sCodeInvalidParams, Message: "missing required parameter " + name}
+		}
+	}
+
+	if err := request.DecodeParams(params); err != nil {
+		return err
+	}
+	if validator, ok := params.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return &%[3]s{Code: %[3]sCodeInvalidParams, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+`
+
+// generateRPCServer writes the server side of an OpenRPC document: a ServerInterface with one
+// handler per method, and the Dispatcher decoding requests, validating their params, routing
+// them to the handlers by method name and encoding their results and errors. Methods whose
+// Params or Result definition was filtered out are answered as unknown methods.
+func generateRPCServer(out *bytes.Buffer, methods []rpcMethod, definitions map[string]any) error {
+	names := rpcEnvelopeNames(definitions)
+	var b strings.Builder
+
+	b.WriteString("// ServerInterface is implemented by the handlers of the JSON-RPC methods. Handlers return\n")
+	fmt.Fprintf(&b, "// a typed error or an *%s to answer with a JSON-RPC error; other errors are answered as\n// internal errors.\n", names.err)
+	b.WriteString("type ServerInterface interface {\n")
+	for _, method := range methods {
+		if !method.generated(definitions) {
+			continue
+		}
+		fmt.Fprintf(&b, "\t// %s handles %s", method.goName, method.name)
+		if method.result == "" {
+			b.WriteString(", a notification")
+		}
+		b.WriteString("\n")
+		if method.summary != "" {
+			fmt.Fprintf(&b, "\t// %s\n", strings.Join(strings.Fields(method.summary), " "))
+		}
+		if method.deprecated {
+			b.WriteString("\t//\n\t// Deprecated: the method is deprecated.\n")
+		}
+		if method.result == "" {
+			fmt.Fprintf(&b, "\t%s(%s) error\n", method.goName, method.signature())
+		} else {
+			fmt.Fprintf(&b, "\t%s(%s) (*%s, error)\n", method.goName, method.signature(), method.result)
+		}
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, rpcDispatcher, names.request, names.response, names.err)
+
+	b.WriteString("// Handle calls the handler of request and returns its response, or nil for notifications\n")
+	fmt.Fprintf(&b, "func (d *Dispatcher) Handle(ctx context.Context, request *%s) *%s {\n", names.request, names.response)
+	b.WriteString("\tswitch request.Method {\n")
+	for _, method := range methods {
+		if !method.generated(definitions) {
+			continue
+		}
+		fmt.Fprintf(&b, "\tcase Method%s:\n", method.goName)
+		arguments := "ctx"
+		if method.params != "" {
+			arguments = "ctx, params"
+			required, order := rpcParamNames(definitions[method.params])
+			fmt.Fprintf(&b, "\t\tvar params %s\n", method.params)
+			structure := strconv.Quote(paramStructure(definitions[method.params]))
+			fmt.Fprintf(&b, "\t\tif err := decodeParams(request, &params, %s, %s, %s); err != nil {\n", structure, stringSlice(required), stringSlice(order))
+			b.WriteString("\t\t\treturn respond(request, nil, err)\n\t\t}\n")
+		}
+		if method.result == "" {
+			fmt.Fprintf(&b, "\t\treturn respond(request, nil, d.server.%s(%s))\n", method.goName, arguments)
+			continue
+		}
+		fmt.Fprintf(&b, "\t\tresult, err := d.server.%s(%s)\n", method.goName, arguments)
+		b.WriteString("\t\treturn respond(request, result, err)\n")
+	}
+	b.WriteString("\tdefault:\n")
+	fmt.Fprintf(&b, "\t\treturn respond(request, nil, &%s{Code: %sCodeMethodNotFound, Message: \"method not found: \" + request.Method})\n", names.err, names.err)
+	b.WriteString("\t}\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// rpcParamNames returns the required parameters of a Params definition, and the order of its
+// parameters when the method accepts them by position
+func rpcParamNames(definition any) (required []string, order []string) {
+	defMap, _ := definition.(map[string]any)
+	names, _ := defMap["required"].([]any)
+	for _, name := range names {
+		if name, ok := name.(string); ok {
+			required = append(required, name)
+		}
+	}
+	positions, _ := defMap[paramOrderKey].([]any)
+	for _, name := range positions {
+		if name, ok := name.(string); ok {
+			order = append(order, name)
+		}
+	}
+	return required, order
+}
+
+// stringSlice returns the Go literal of a string slice, nil when it is empty
+func stringSlice(values []string) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
@@ -0,0 +1,366 @@
+package jrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SampleOptions selects the values SampleValue builds
+type SampleOptions struct {
+	Examples bool // Whether the example and examples of the schemas are used where they fit
+	Response bool // Whether writeOnly properties are left out, as in response bodies
+
+	// OmitProperties are the names of the properties left out of objects, or null when
+	// required, unless Examples is set and their schema has an example (e.g. the next page
+	// cursors of a mock response, which would otherwise always point to another page)
+	OmitProperties []string
+}
+
+// sampleStrings are the sample values of strings, by format. The formats generated as
+// time.Time all get an RFC 3339 timestamp, the only encoding time.Time decodes.
+var sampleStrings = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01T00:00:00Z",
+	"time":      "2024-01-01T00:00:00Z",
+	"duration":  "PT1H",
+	"email":     "user@example.com",
+	"uuid":      "123e4567-e89b-12d3-a456-426614174000",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c2FtcGxl",
+	"binary":    "c2FtcGxl",
+	"decimal":   "0",
+}
+
+// SampleValue returns a JSON value of a schema that the Go type generated for it decodes:
+// its default, const or first enum value when it has one (preceded by its examples with
+// options.Examples), and otherwise a value built from its type and constraints, with sample
+// strings for the well-known formats and every property of objects. $refs are resolved
+// against definitions; recursive references are left out of objects. The same schema always
+// gives the same value.
+func SampleValue(schema map[string]any, definitions map[string]any, options SampleOptions) any {
+	return sampleValue(schema, definitions, options, map[string]bool{})
+}
+
+// sampleValue implements SampleValue. seen holds the definitions being built.
+func sampleValue(schema map[string]any, definitions map[string]any, options SampleOptions, seen map[string]bool) any {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		target, ok := definitions[name].(map[string]any)
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return sampleValue(target, definitions, options, seen)
+	}
+
+	if options.Examples {
+		if example, ok := schema["example"]; ok && sampleFits(example, schema, definitions, map[string]bool{}) {
+			return example
+		}
+		if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 && sampleFits(examples[0], schema, definitions, map[string]bool{}) {
+			return examples[0]
+		}
+	}
+	for _, keyword := range []string{"default", "const"} {
+		if value, ok := schema[keyword]; ok && sampleFits(value, schema, definitions, map[string]bool{}) {
+			return value
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if _, overridden := goTypeOverride(schema); overridden && !isDurationSchema(schema) && !isDecimalSchema(schema) {
+		return nil // The encoding of an existing type is unknown
+	}
+
+	if members, ok := schema["allOf"].([]any); ok {
+		merged := make(map[string]any)
+		for _, member := range members {
+			memberMap, _ := member.(map[string]any)
+			if object, ok := sampleValue(memberMap, definitions, options, seen).(map[string]any); ok {
+				for name, value := range object {
+					merged[name] = value
+				}
+			}
+		}
+		return merged
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[keyword].([]any); ok && !isConstraintOnly(members) {
+			if nonNull := nonNullMembers(members); len(nonNull) > 0 {
+				memberMap, _ := nonNull[0].(map[string]any)
+				return sampleValue(memberMap, definitions, options, seen)
+			}
+			return nil
+		}
+	}
+
+	if items, ok := tupleItems(schema); ok {
+		values := make([]any, len(items))
+		for i, item := range items {
+			itemMap, _ := item.(map[string]any)
+			values[i] = sampleValue(itemMap, definitions, options, seen)
+		}
+		return values
+	}
+
+	switch sampleType(schema) {
+	case "string":
+		format, _ := schema["format"].(string)
+		value, ok := sampleStrings[format]
+		if !ok {
+			value = "string"
+		}
+		if _, fixed := formatPackages[format]; fixed || format == "byte" || format == "binary" {
+			return value
+		}
+		if length, ok := schema["minLength"].(float64); ok && len(value) < int(length) {
+			value += strings.Repeat("x", int(length)-len(value))
+		}
+		if length, ok := schema["maxLength"].(float64); ok && len(value) > int(length) {
+			value = value[:int(length)]
+		}
+		return value
+	case "integer", "number":
+		value := 0.0
+		if minimum, ok := schema["minimum"].(float64); ok {
+			value = minimum
+			if schema["exclusiveMinimum"] == true {
+				value++
+			}
+		} else if minimum, ok := schema["exclusiveMinimum"].(float64); ok {
+			value = minimum + 1
+		} else if maximum, ok := schema["maximum"].(float64); ok && maximum < 0 {
+			value = maximum
+		}
+		if sampleType(schema) == "integer" {
+			value = math.Ceil(value)
+		}
+		return value
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		count := 1
+		if minimum, ok := schema["minItems"].(float64); ok && int(minimum) > count {
+			count = int(minimum)
+		}
+		values := make([]any, 0, count)
+		for len(values) < count && items != nil {
+			value := sampleValue(items, definitions, options, seen)
+			if value == nil {
+				break // Null items would decode as zero values, which may not encode validly
+			}
+			values = append(values, value)
+		}
+		return values
+	case "object":
+		object := make(map[string]any)
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, _ := properties[name].(map[string]any)
+			if options.Response && property["writeOnly"] == true {
+				continue
+			}
+			if slices.Contains(options.OmitProperties, name) && !(options.Examples && hasExample(property)) {
+				if slices.Contains(required, any(name)) {
+					object[name] = nil
+				}
+				continue
+			}
+			if value := sampleValue(property, definitions, options, seen); value != nil || slices.Contains(required, any(name)) {
+				object[name] = value
+			}
+		}
+		return object
+	}
+	return nil
+}
+
+// hasExample reports whether a schema has an example or examples
+func hasExample(schema map[string]any) bool {
+	_, example := schema["example"]
+	_, examples := schema["examples"]
+	return example || examples
+}
+
+// sampleType returns the type of a schema: its first non-null type, or the type its keywords
+// imply when it has none
+func sampleType(schema map[string]any) string {
+	switch types := schema["type"].(type) {
+	case string:
+		return types
+	case []any:
+		for _, name := range types {
+			if name, ok := name.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	switch {
+	case schema["properties"] != nil || schema["additionalProperties"] != nil:
+		return "object"
+	case schema["items"] != nil:
+		return "array"
+	}
+	return ""
+}
+
+// sampleFits reports whether a value given by a schema (an example, a default, ...) fits the
+// schema closely enough for its generated Go type to decode it: the types match, enums and
+// consts hold, and the values of the formats generated as time.Time and []byte parse
+func sampleFits(value any, schema map[string]any, definitions map[string]any, seen map[string]bool) bool {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		target, ok := definitions[name].(map[string]any)
+		if !ok || seen[name] {
+			return true
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return sampleFits(value, target, definitions, seen)
+	}
+	if value == nil {
+		return true // Decoding null leaves the Go value unset
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(allowed any) bool { return reflect.DeepEqual(allowed, value) }) {
+		return false
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		return false
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[keyword].([]any); ok && !isConstraintOnly(members) {
+			return slices.ContainsFunc(members, func(member any) bool {
+				memberMap, _ := member.(map[string]any)
+				return sampleFits(value, memberMap, definitions, seen)
+			})
+		}
+	}
+	if members, ok := schema["allOf"].([]any); ok {
+		for _, member := range members {
+			if memberMap, _ := member.(map[string]any); !sampleFits(value, memberMap, definitions, seen) {
+				return false
+			}
+		}
+	}
+
+	switch sampleType(schema) {
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return false
+		}
+		switch format, _ := schema["format"].(string); {
+		case formatPackages[format] == "time":
+			_, err := time.Parse(time.RFC3339, text)
+			return err == nil
+		case format == "byte" || format == "binary":
+			_, err := base64.StdEncoding.DecodeString(text)
+			return err == nil
+		}
+		return true
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		values, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		if items, ok := tupleItems(schema); ok {
+			return len(values) == len(items)
+		}
+		items, _ := schema["items"].(map[string]any)
+		return items == nil || !slices.ContainsFunc(values, func(item any) bool { return !sampleFits(item, items, definitions, seen) })
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range object {
+			if propertyMap, ok := properties[name].(map[string]any); ok && !sampleFits(property, propertyMap, definitions, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// generateExampleFactories writes ExampleX and FakeX, returning a struct populated with the
+// sample values of its schema, with and without its examples. The samples are embedded as
+// JSON, which the struct's own decoding turns into Go values; those of a union are the samples
+// of its first variant, unless the union has examples of its own.
+func generateExampleFactories(out *bytes.Buffer, typeName string, definitions map[string]any) error {
+	factories := []struct {
+		name    string
+		comment string
+		options SampleOptions
+	}{
+		{"Example", "the examples of its schema, falling back to defaults, first enum values and\n// sample values", SampleOptions{Examples: true}},
+		{"Fake", "sample values derived from its schema: defaults, first enum values and\n// values of its types and formats", SampleOptions{}},
+	}
+
+	var b strings.Builder
+	for _, factory := range factories {
+		if _, taken := definitions[factory.name+typeName]; taken {
+			continue
+		}
+		// Sampling a reference to the definition leaves its recursive references out
+		data, err := json.Marshal(SampleValue(map[string]any{"$ref": typeName}, definitions, factory.options))
+		if err != nil {
+			return fmt.Errorf("failed to encode the sample of %s: %w", typeName, err)
+		}
+		fmt.Fprintf(&b, "// %s%s returns a %s populated with %s\n", factory.name, typeName, typeName, factory.comment)
+		fmt.Fprintf(&b, "func %s%s() *%s {\n", factory.name, typeName, typeName)
+		fmt.Fprintf(&b, "\tx := new(%s)\n", typeName)
+		fmt.Fprintf(&b, "\tdecodeSample(%s, x)\n", strconv.Quote(string(data)))
+		b.WriteString("\treturn x\n}\n\n")
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateSampleHelpers writes the function the example factories decode their samples with
+func generateSampleHelpers(out *bytes.Buffer) error {
+	helper := `// decodeSample decodes the JSON sample of an example factory into x
+func decodeSample(sample string, x any) {
+	if err := json.Unmarshal([]byte(sample), x); err != nil {
+		panic(fmt.Sprintf("invalid sample: %v", err))
+	}
+}
+
+`
+	_, err := out.WriteString(helper)
+	return err
+}
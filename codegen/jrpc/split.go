@@ -0,0 +1,311 @@
+package jrpc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Accepted values of GeneratorOptions.SplitMode
+const (
+	splitByKind = "kind" // enums.go, models.go and helpers.go
+	splitByType = "type" // One file per exported type, plus helpers.go
+)
+
+// helpersFileName is the file holding declarations that do not belong to an exported type
+const helpersFileName = "helpers.go"
+
+// buildConstraintSuffixes are file name suffixes the go tool treats as build constraints or
+// test files, which generated file names must not end with
+var buildConstraintSuffixes = map[string]bool{
+	"test": true,
+
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+	"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+// majorVersionPattern matches the major version element of a module path (v2, v3, ...)
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// splitImport is an import of the generated file together with the name it is referred by
+type splitImport struct {
+	spec string // Import spec of the form "path" or "name path"
+	name string // Package name used in qualified identifiers
+}
+
+// splitOutput distributes the declarations of a generated file over several files in
+// outputDir according to GeneratorOptions.SplitMode. Every file gets the header of the generated file and the
+// imports its declarations use.
+func splitOutput(source []byte, outputDir string, options *GeneratorOptions) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	header := string(source[:fset.Position(file.Name.End()).Offset])
+
+	types := make(map[string]bool)
+	enums := make(map[string]bool)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				types[spec.Name.Name] = true
+			case *ast.ValueSpec:
+				if ident, ok := spec.Type.(*ast.Ident); ok && genDecl.Tok == token.CONST {
+					enums[ident.Name] = true
+				}
+			}
+		}
+	}
+
+	imports := splitImports(file)
+	known := make(map[string]bool, len(imports))
+	for _, imported := range imports {
+		known[imported.name] = true
+	}
+	files := make(map[string][]ast.Decl)
+	var order []string
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			continue
+		}
+
+		fileName := helpersFileName
+		if owner := declarationOwner(decl, types); owner != "" && ast.IsExported(owner) {
+			switch {
+			case options.SplitMode == splitByType:
+				fileName = typeFileName(owner)
+			case enums[owner]:
+				fileName = "enums.go"
+			default:
+				fileName = "models.go"
+			}
+		}
+
+		if _, exists := files[fileName]; !exists {
+			order = append(order, fileName)
+		}
+		files[fileName] = append(files[fileName], decl)
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, fileName := range order {
+		decls := files[fileName]
+
+		used := newImportManager()
+		for _, imported := range imports {
+			// Imports whose package name differs from their path are kept wherever an
+			// unknown package is referenced
+			unmatched := !usesPackage(file.Decls, imported.name) && hasUnknownQualifier(decls, known)
+			if usesPackage(decls, imported.name) || unmatched {
+				used.add(imported.spec)
+			}
+		}
+
+		var b strings.Builder
+		b.WriteString(header + "\n\n")
+		b.WriteString(used.render())
+		for _, decl := range decls {
+			b.Write(declarationSource(fset, source, decl))
+			b.WriteString("\n\n")
+		}
+
+		path := filepath.Join(outputDir, fileName)
+		content, err := formatSource(path, []byte(b.String()), options)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fileName, err)
+		}
+	}
+
+	return nil
+}
+
+// splitImports returns the imports of the generated file with the package names they are
+// referred by: the explicit name, or one derived from the import path
+func splitImports(file *ast.File) []splitImport {
+	var imports []splitImport
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			imports = append(imports, splitImport{spec: spec.Name.Name + " " + path, name: spec.Name.Name})
+			continue
+		}
+		imports = append(imports, splitImport{spec: path, name: packageNameOf(path)})
+	}
+	return imports
+}
+
+// packageNameOf derives the package name of an import path from its last element, skipping
+// major version elements and version suffixes (gopkg.in/yaml.v3 → yaml)
+func packageNameOf(path string) string {
+	elements := strings.Split(path, "/")
+	name := elements[len(elements)-1]
+	if majorVersionPattern.MatchString(name) && len(elements) > 1 {
+		name = elements[len(elements)-2]
+	}
+	if base, _, ok := strings.Cut(name, "."); ok {
+		name = base
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// declarationOwner returns the type a top-level declaration belongs to: the declared type,
+// the receiver of a method, the result type of a constructor-like function, the type of a
+// constant block, or the type whose name prefixes the declared name
+func declarationOwner(decl ast.Decl, types map[string]bool) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			return baseTypeName(decl.Recv.List[0].Type)
+		}
+		if decl.Type.Results != nil && ast.IsExported(decl.Name.Name) {
+			for _, result := range decl.Type.Results.List {
+				if name := baseTypeName(result.Type); types[name] {
+					return name
+				}
+			}
+		}
+		if ast.IsExported(decl.Name.Name) {
+			return prefixOwner(decl.Name.Name, types)
+		}
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				return spec.Name.Name
+			case *ast.ValueSpec:
+				if name := baseTypeName(spec.Type); types[name] {
+					return name
+				}
+				if len(spec.Names) > 0 {
+					return prefixOwner(spec.Names[0].Name, types)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// baseTypeName returns the name of a local type expression, looking through pointers,
+// slices and maps to their element type
+func baseTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return baseTypeName(expr.X)
+	case *ast.ArrayType:
+		return baseTypeName(expr.Elt)
+	case *ast.MapType:
+		return baseTypeName(expr.Value)
+	}
+	return ""
+}
+
+// prefixOwner returns the longest type name that prefixes name, comparing the first letter
+// case-insensitively so that unexported tables like taskStateNames match TaskState
+func prefixOwner(name string, types map[string]bool) string {
+	owner := ""
+	for typeName := range types {
+		lowered := string(unicode.ToLower(rune(typeName[0]))) + typeName[1:]
+		if (strings.HasPrefix(name, typeName) || strings.HasPrefix(name, lowered)) && len(typeName) > len(owner) {
+			owner = typeName
+		}
+	}
+	return owner
+}
+
+// typeFileName returns the file name a type is generated in, in snake case. Names ending in
+// a suffix the go tool interprets (e.g. _test or _linux) get a _type suffix.
+func typeFileName(typeName string) string {
+	words := nameWords(typeName)
+	if buildConstraintSuffixes[words[len(words)-1]] || strings.Join(words, "_")+".go" == helpersFileName {
+		words = append(words, "type")
+	}
+	return strings.Join(words, "_") + ".go"
+}
+
+// usesPackage reports whether declarations refer to the package imported under name
+func usesPackage(decls []ast.Decl, name string) bool {
+	used := false
+	for _, decl := range decls {
+		ast.Inspect(decl, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == name && ident.Obj == nil {
+					used = true
+				}
+			}
+			return !used
+		})
+		if used {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnknownQualifier reports whether declarations qualify identifiers with a package name
+// that none of the known imports is referred by
+func hasUnknownQualifier(decls []ast.Decl, known map[string]bool) bool {
+	found := false
+	for _, decl := range decls {
+		ast.Inspect(decl, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil && !known[ident.Name] {
+					found = true
+				}
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// declarationSource returns the source of a top-level declaration including its doc comment
+func declarationSource(fset *token.FileSet, source []byte, decl ast.Decl) []byte {
+	start := decl.Pos()
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	}
+	return source[fset.Position(start).Offset:fset.Position(decl.End()).Offset]
+}
@@ -0,0 +1,280 @@
+package jrpc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Accepted values of GeneratorOptions.OmitMode
+const (
+	omitEmpty = "omitempty" // Tag optional fields with omitempty
+	omitZero  = "omitzero"  // Tag optional fields with omitzero (Go 1.24+)
+	omitBoth  = "both"      // Tag optional fields with both omitempty and omitzero
+)
+
+// omitFlags returns the json tag options written for optional fields
+func omitFlags(options *GeneratorOptions) []string {
+	switch options.OmitMode {
+	case omitZero:
+		return []string{"omitzero"}
+	case omitBoth:
+		return []string{"omitempty", "omitzero"}
+	}
+	return []string{"omitempty"}
+}
+
+// omitsZero reports whether optional fields are tagged omitzero, which lets optional struct
+// fields be stored by value
+func omitsZero(options *GeneratorOptions) bool {
+	return options.OmitMode == omitZero || options.OmitMode == omitBoth
+}
+
+// jsonTag returns the json tag of a struct field
+func jsonTag(propName string, optional bool, options *GeneratorOptions) string {
+	tag := propName
+	if optional {
+		tag += "," + strings.Join(omitFlags(options), ",")
+	}
+	return fmt.Sprintf("json:%q", tag)
+}
+
+// unomittedTags are the extra tag keys written without an omit option, since their libraries
+// only use the name to match keys when decoding
+var unomittedTags = map[string]bool{
+	"avro":         true,
+	"mapstructure": true,
+}
+
+// validateTagKeys checks that the extra tag keys requested through GeneratorOptions.Tags can
+// be written as struct tag keys without clashing with each other or the validate tag
+func validateTagKeys(keys []string) error {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		switch {
+		case key == "" || strings.ContainsAny(key, " \t\n:\"`"):
+			return fmt.Errorf("invalid struct tag key %q", key)
+		case key == "validate":
+			return fmt.Errorf("struct tag key %q is reserved for ValidateTags", key)
+		case seen[key]:
+			return fmt.Errorf("duplicate struct tag key %q", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// tagTemplateData is the field description GeneratorOptions.TagTemplates are executed with
+type tagTemplateData struct {
+	Name      string // Property name in the schema
+	GoName    string // Go field name
+	SnakeName string // Property name in snake_case
+	CamelName string // Property name in camelCase
+	KebabName string // Property name in kebab-case
+	Type      string // Go type of the field
+	Required  bool   // Whether the property is required
+}
+
+// renderedTagPattern matches the single `key:"value"` tag a tag template must render
+var renderedTagPattern = regexp.MustCompile(`^[^\s:"]+:"(?:[^"\\\n]|\\.)*"$`)
+
+// parseTagTemplates parses the tag templates of GeneratorOptions.TagTemplates
+func parseTagTemplates(sources []string) ([]*template.Template, error) {
+	templates := make([]*template.Template, 0, len(sources))
+	for _, source := range sources {
+		tmpl, err := template.New("tag").Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag template %q: %w", source, err)
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+// fieldTags returns the json tag of a struct field followed by the tags requested through
+// GeneratorOptions.Tags, which use the same name and omit optional fields with omitempty,
+// and the tags rendered from GeneratorOptions.TagTemplates. Templates rendering to an empty
+// string add no tag.
+func fieldTags(data tagTemplateData, optional bool, options *GeneratorOptions) ([]string, error) {
+	tags := []string{jsonTag(data.Name, optional, options)}
+	for _, key := range options.Tags {
+		if key == "json" {
+			continue
+		}
+		value := data.Name
+		if optional && !unomittedTags[key] {
+			value += ",omitempty"
+		}
+		tags = append(tags, fmt.Sprintf("%s:%q", key, value))
+	}
+
+	templates, err := parseTagTemplates(options.TagTemplates)
+	if err != nil {
+		return nil, err
+	}
+	words := nameWords(data.Name)
+	data.SnakeName = strings.Join(words, "_")
+	data.KebabName = strings.Join(words, "-")
+	data.CamelName = camelCase(words)
+	for _, tmpl := range templates {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("failed to render tag template for field %s: %w", data.GoName, err)
+		}
+		tag := strings.TrimSpace(b.String())
+		if tag == "" {
+			continue
+		}
+		if !renderedTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("tag template rendered invalid struct tag %q for field %s", tag, data.GoName)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// nameWords splits a property name into lowercase words at separators and case changes,
+// keeping acronyms together (HTTPServer → http, server)
+func nameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1])
+			acronymEnd := unicode.IsUpper(runes[i]) && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+// camelCase joins lowercase words into a camelCase name
+func camelCase(words []string) string {
+	var b strings.Builder
+	for i, word := range words {
+		if i > 0 && word != "" {
+			runes := []rune(word)
+			word = string(unicode.ToUpper(runes[0])) + string(runes[1:])
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// ignoredFieldTags returns the tags of a struct field that is never encoded directly, such
+// as the AdditionalProperties overflow map
+func ignoredFieldTags(options *GeneratorOptions) []string {
+	tags := []string{`json:"-"`}
+	for _, key := range options.Tags {
+		if key != "json" {
+			tags = append(tags, key+`:"-"`)
+		}
+	}
+	return tags
+}
+
+// optionalValueStruct reports whether an optional property of typeName referencing a struct
+// definition is stored by value instead of through a pointer. This requires omitzero, and is
+// only done when the referenced struct cannot lead back to typeName through any reference,
+// since optional fields are otherwise assumed to provide indirection.
+func optionalValueStruct(typeName string, propMap map[string]any, definitions map[string]any, options *GeneratorOptions) bool {
+	if !omitsZero(options) {
+		return false
+	}
+	ref, ok := propMap["$ref"].(string)
+	if !ok {
+		return false
+	}
+	if _, overridden := goTypeOverride(propMap); overridden {
+		return false
+	}
+	target, ok := definitions[refName(ref)].(map[string]any)
+	if !ok || !isStructDefinition(target, definitions) {
+		return false
+	}
+	return !referencesDefinition(refName(ref), typeName, definitions, map[string]bool{})
+}
+
+// referencesDefinition reports whether definition from is, or refers to definition to through
+// any chain of references
+func referencesDefinition(from string, to string, definitions map[string]any, visited map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	refs := make(map[string]bool)
+	collectRefNames(definitions[from], refs)
+	for ref := range refs {
+		if referencesDefinition(ref, to, definitions, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroGuard wraps statements operating on an optional struct field stored by value so that
+// they only run when the field is set, matching how omitzero decides to leave it out
+func zeroGuard(fieldName string, statements string) string {
+	return fmt.Sprintf("\tif !reflect.ValueOf(x.%s).IsZero() {\n%s\t}\n", fieldName, indentLines(statements, "\t"))
+}
+
+// zeroGuardImports returns the packages used by zeroGuard when an optional struct field stored
+// by value gets nested ApplyDefaults or Validate calls
+func zeroGuardImports(definitions map[string]any, options *GeneratorOptions) []string {
+	if !omitsZero(options) || (!options.GenerateDefaults && !options.GenerateValidate) {
+		return nil
+	}
+
+	for typeName, definition := range definitions {
+		defMap, ok := definition.(map[string]any)
+		if !ok || !isStructDefinition(defMap, definitions) {
+			continue
+		}
+		required := structRequired(defMap, definitions)
+		for propName, propDef := range structProperties(defMap, definitions) {
+			propMap, ok := propDef.(map[string]any)
+			if !ok || required[propName] || hasDefaultValue(propMap) || !optionalValueStruct(typeName, propMap, definitions, options) {
+				continue
+			}
+			if options.GenerateValidate {
+				return []string{"reflect"}
+			}
+			if target, ok := definitions[refName(propMap["$ref"].(string))].(map[string]any); ok && hasDefaults(target, definitions, map[string]bool{}) {
+				return []string{"reflect"}
+			}
+		}
+	}
+	return nil
+}
+
+// structRequired returns the required property names of a struct definition, taking allOf
+// merging into account
+func structRequired(defMap map[string]any, definitions map[string]any) map[string]bool {
+	required, _ := defMap["required"].([]any)
+	if _, hasAllOf := defMap["allOf"]; hasAllOf {
+		if merged, ok := mergeAllOf(defMap, definitions); ok {
+			required = merged.required
+		}
+	}
+
+	fields := make(map[string]bool, len(required))
+	for _, field := range required {
+		if fieldName, ok := field.(string); ok {
+			fields[fieldName] = true
+		}
+	}
+	return fields
+}
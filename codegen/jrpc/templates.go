@@ -0,0 +1,191 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Names of the overridable construct templates. A GeneratorOptions.TemplateDir file named
+// <name>.tmpl replaces the default template of the same name.
+const (
+	headerTemplate = "header" // Executed with HeaderTemplateData
+	enumTemplate   = "enum"   // Executed with EnumTemplateData
+	structTemplate = "struct" // Executed with StructTemplateData
+	aliasTemplate  = "alias"  // Executed with AliasTemplateData
+)
+
+// defaultTemplates are the built-in construct templates, producing the generator's standard
+// output
+var defaultTemplates = map[string]string{
+	headerTemplate: `{{ if .License }}{{ comment .License }}
+
+{{ end }}// {{ .Notice }}
+{{ if .BuildConstraint }}
+//go:build {{ .BuildConstraint }}
+
+{{ end }}package {{ .PackageName }}
+
+{{ .Imports }}`,
+
+	enumTemplate: `{{ .Comment }}type {{ .Name }} {{ .Type }}
+
+// {{ .Name }} enum values
+const (
+{{ range .Values }}	{{ .Name }}{{ if .Value }} {{ $.Name }} = {{ .Value }}{{ end }}
+{{ end }})
+
+`,
+
+	structTemplate: `{{ .Comment }}type {{ .Name }} struct {
+{{ range .Embedded }}	{{ . }}
+{{ end }}{{ range .Fields }}{{ .Comment }}	{{ .Name }} {{ .Type }} ` + "`{{ .Tag }}`" + `
+{{ end }}}
+
+`,
+
+	aliasTemplate: `{{ .Comment }}type {{ .Name }}{{ if .Alias }} ={{ end }} {{ .Type }}
+
+`,
+}
+
+// HeaderTemplateData is the data the "header" template is executed with. The template
+// renders everything before the first declaration of a generated file.
+type HeaderTemplateData struct {
+	PackageName     string // Target Go package name
+	License         string // GeneratorOptions.LicenseHeader without trailing newlines
+	Notice          string // Generated code notice without the leading "//", e.g. "Code generated from JSON schema. DO NOT EDIT."
+	BuildConstraint string // GeneratorOptions.BuildConstraint
+	Schema          string // Path of the schema file, empty when generating from memory
+	SchemaHash      string // Hex-encoded SHA-256 hash of the schema contents
+	Imports         string // Rendered import declaration, empty when nothing is imported
+}
+
+// EnumTemplateData is the data the "enum" template is executed with
+type EnumTemplateData struct {
+	Name        string              // Go type name
+	Type        string              // Underlying Go type: string, int, float64 or bool
+	Comment     string              // Rendered doc comment including its trailing newline, or empty
+	Description string              // Raw schema description
+	Deprecated  bool                // Whether the schema is marked deprecated
+	Extensions  map[string]any      // Vendor extensions (x-*) the generator does not interpret, nil when there are none
+	Values      []EnumTemplateValue // Constants in declaration order
+}
+
+// EnumTemplateValue is a constant of an enum type
+type EnumTemplateValue struct {
+	Name  string // Go constant name
+	Value string // Go expression of the value (e.g. `"active"`, `2` or `iota + 1`), empty for implicit iota repetition
+}
+
+// StructTemplateData is the data the "struct" template is executed with. Methods generated
+// for the struct (ApplyDefaults, Validate, marshalers, ...) refer to the fields by Name, so
+// templates must keep the field names and types.
+type StructTemplateData struct {
+	Name        string                // Go type name
+	Comment     string                // Rendered doc comment including its trailing newline, or empty
+	Description string                // Raw schema description
+	Deprecated  bool                  // Whether the schema is marked deprecated
+	Extensions  map[string]any        // Vendor extensions (x-*) the generator does not interpret, nil when there are none
+	Embedded    []string              // Embedded types from allOf members
+	Fields      []StructTemplateField // Fields in generation order
+}
+
+// StructTemplateField is a field of a generated struct
+type StructTemplateField struct {
+	Name        string         // Go field name
+	Type        string         // Go type
+	Tag         string         // Struct tag contents without the backquotes
+	JSONName    string         // Property name in the schema, empty for AdditionalProperties
+	Required    bool           // Whether the property is required
+	Comment     string         // Rendered doc comment lines, tab-indented and including their trailing newline, or empty
+	Description string         // Raw property description
+	Extensions  map[string]any // Vendor extensions (x-*) of the property the generator does not interpret, nil when there are none
+}
+
+// AliasTemplateData is the data the "alias" template is executed with, for definitions that
+// name another type
+type AliasTemplateData struct {
+	Name        string         // Go type name
+	Type        string         // Go type the definition stands for
+	Alias       bool           // Whether to declare an alias (type X = Y) rather than a defined type (type X Y)
+	Comment     string         // Rendered doc comment including its trailing newline, or empty
+	Description string         // Raw schema description
+	Deprecated  bool           // Whether the schema is marked deprecated
+	Extensions  map[string]any // Vendor extensions (x-*) the generator does not interpret, nil when there are none
+}
+
+// templateFuncs are the functions available to construct templates
+var templateFuncs = template.FuncMap{
+	"comment": commentLines,
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"snake": func(name string) string {
+		return strings.Join(nameWords(name), "_")
+	},
+}
+
+// commentLines turns text into // comment lines. Lines that already are comments are kept.
+func commentLines(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "//"):
+		case strings.TrimSpace(line) == "":
+			lines[i] = "//"
+		default:
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadTemplates returns the construct templates: the defaults, overridden by the <name>.tmpl
+// files of dir. Other .tmpl files in dir are parsed too, so that overrides can share
+// {{ define }}d templates.
+func loadTemplates(dir string) (*template.Template, error) {
+	templates := template.New("").Funcs(templateFuncs).Option("missingkey=error")
+	sources := make(map[string]string, len(defaultTemplates))
+	for name, source := range defaultTemplates {
+		sources[name] = source
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list templates: %w", err)
+		}
+		for _, file := range files {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template: %w", err)
+			}
+			sources[strings.TrimSuffix(filepath.Base(file), ".tmpl")] = string(source)
+		}
+	}
+
+	for name, source := range sources {
+		if _, err := templates.New(name).Parse(source); err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", name, err)
+		}
+	}
+	return templates, nil
+}
+
+// executeTemplate renders the named construct template into out
+func executeTemplate(out *bytes.Buffer, templates *template.Template, name string, data any) error {
+	if err := templates.ExecuteTemplate(out, name, data); err != nil {
+		return fmt.Errorf("failed to execute %s template: %w", name, err)
+	}
+	return nil
+}
+
+// schemaDescription returns the description of a schema, or an empty string when it has none
+func schemaDescription(schema map[string]any) string {
+	description, _ := schema["description"].(string)
+	return description
+}
@@ -0,0 +1,127 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// tupleItems returns the positional item schemas of a tuple-style array, declared either with
+// an `items` array (draft 4-2019-09) or with `prefixItems` (2020-12)
+func tupleItems(schema map[string]any) ([]any, bool) {
+	if items, ok := schema["prefixItems"].([]any); ok && len(items) > 0 {
+		return items, true
+	}
+	if items, ok := schema["items"].([]any); ok && len(items) > 0 {
+		return items, true
+	}
+	return nil, false
+}
+
+// isTupleSchema reports whether a schema describes a positional tuple
+func isTupleSchema(schema map[string]any) bool {
+	if _, overridden := goTypeOverride(schema); overridden {
+		return false
+	}
+	_, ok := tupleItems(schema)
+	return ok
+}
+
+// containsTupleType reports whether any definition is generated as a tuple struct
+func containsTupleType(definitions map[string]any) bool {
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && isTupleSchema(defMap) {
+			return true
+		}
+	}
+	return false
+}
+
+// tupleField describes one positional field of a tuple struct
+type tupleField struct {
+	name     string
+	goType   string
+	optional bool
+}
+
+// generateTupleType generates a struct with one field per tuple position, encoded to and
+// decoded from a JSON array. Positions at or beyond minItems are optional pointer fields.
+func generateTupleType(out *bytes.Buffer, typeName string, defMap map[string]any, definitions map[string]any, spellings map[string]string) error {
+	items, _ := tupleItems(defMap)
+
+	required := len(items)
+	if minItems, ok := defMap["minItems"].(float64); ok && int(minItems) < required {
+		required = int(minItems)
+	} else if minItems, ok := defMap["minItems"].(int); ok && minItems < required {
+		required = minItems
+	}
+
+	fields := make([]tupleField, 0, len(items))
+	for i, item := range items {
+		itemMap, _ := item.(map[string]any)
+		if itemMap == nil {
+			itemMap = map[string]any{}
+		}
+
+		name := fmt.Sprintf("Item%d", i)
+		if pinned, ok := goNameOverride(itemMap); ok {
+			name = pinned
+		} else if title, ok := itemMap["title"].(string); ok && convertToGoFieldName(title, spellings) != "" {
+			name = convertToGoFieldName(title, spellings)
+		}
+
+		field := tupleField{name: name, goType: determineGoType(itemMap, definitions), optional: i >= required}
+		if field.optional && !strings.HasPrefix(field.goType, "*") && !strings.HasPrefix(field.goType, "[]") && !strings.HasPrefix(field.goType, "map[") {
+			field.goType = "*" + field.goType
+		}
+		fields = append(fields, field)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+	for _, field := range fields {
+		fmt.Fprintf(&b, "\t%s %s\n", field.name, field.goType)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// MarshalJSON encodes %s as a JSON array\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) MarshalJSON() ([]byte, error) {\n", typeName)
+	b.WriteString("\titems := []any{")
+	for i := 0; i < required; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "x.%s", fields[i].name)
+	}
+	b.WriteString("}\n")
+	for _, field := range fields[required:] {
+		fmt.Fprintf(&b, "\tif x.%s == nil {\n\t\treturn json.Marshal(items)\n\t}\n", field.name)
+		fmt.Fprintf(&b, "\titems = append(items, x.%s)\n", field.name)
+	}
+	b.WriteString("\treturn json.Marshal(items)\n}\n\n")
+
+	fmt.Fprintf(&b, "// UnmarshalJSON decodes %s from a JSON array\n", typeName)
+	fmt.Fprintf(&b, "func (x *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+	b.WriteString("\tvar items []json.RawMessage\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &items); err != nil {\n\t\treturn err\n\t}\n")
+	if required > 0 {
+		fmt.Fprintf(&b, "\tif len(items) < %d {\n", required)
+		fmt.Fprintf(&b, "\t\treturn fmt.Errorf(\"%s: expected at least %d items, got %%d\", len(items))\n\t}\n", typeName, required)
+	}
+	fmt.Fprintf(&b, "\n\t*x = %s{}\n", typeName)
+	for i, field := range fields {
+		if field.optional {
+			fmt.Fprintf(&b, "\tif len(items) > %d {\n", i)
+			fmt.Fprintf(&b, "\t\tif err := json.Unmarshal(items[%d], &x.%s); err != nil {\n", i, field.name)
+			fmt.Fprintf(&b, "\t\t\treturn fmt.Errorf(\"%s item %d: %%w\", err)\n\t\t}\n\t}\n", typeName, i)
+			continue
+		}
+		fmt.Fprintf(&b, "\tif err := json.Unmarshal(items[%d], &x.%s); err != nil {\n", i, field.name)
+		fmt.Fprintf(&b, "\t\treturn fmt.Errorf(\"%s item %d: %%w\", err)\n\t}\n", typeName, i)
+	}
+	b.WriteString("\n\treturn nil\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,709 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// oneOfVariant describes a single Go type that can be held by a oneOf union
+type oneOfVariant struct {
+	typeName            string   // Go type implementing the union's variant interface
+	refName             string   // Definition name when the variant is a $ref
+	wrapOf              string   // Underlying Go type when a wrapper type must be generated
+	discriminatorValues []string // Discriminator values selecting this variant
+}
+
+// oneOfPlan describes how a oneOf definition is generated as a sum type
+type oneOfPlan struct {
+	variants      []oneOfVariant
+	discriminator string // JSON property used to select the variant, empty for structural matching
+}
+
+// hoistInlineUnionMembers moves inline object members of oneOf compositions, as well as
+// properties and array items declared as inline oneOf compositions, into named definitions
+// so they can be generated as regular types and referenced by the union
+func hoistInlineUnionMembers(definitions map[string]any, spellings map[string]string) {
+	for _, defName := range sortedDefinitionNames(definitions) {
+		defMap, ok := definitions[defName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for _, properties := range propertyMaps(defMap) {
+			propNames := make([]string, 0, len(properties))
+			for propName := range properties {
+				propNames = append(propNames, propName)
+			}
+			sort.Strings(propNames)
+
+			for _, propName := range propNames {
+				propMap, ok := properties[propName].(map[string]any)
+				if !ok {
+					continue
+				}
+
+				target, suffix := propMap, ""
+				if items, ok := propMap["items"].(map[string]any); ok {
+					target, suffix = items, "Item"
+				}
+
+				if !isUnionSchema(target) {
+					continue
+				}
+
+				unionName, pinned := goNameOverride(target)
+				if !pinned {
+					unionName = defName + goFieldName(propName, propMap, spellings) + suffix
+				}
+				unionName = uniqueDefinitionName(definitions, unionName)
+				definitions[unionName] = copySchema(target)
+				for key := range target {
+					if key != "description" {
+						delete(target, key)
+					}
+				}
+				target["$ref"] = "#/definitions/" + unionName
+			}
+		}
+	}
+
+	for _, defName := range sortedDefinitionNames(definitions) {
+		defMap, ok := definitions[defName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for _, key := range []string{"oneOf", "anyOf"} {
+			members, ok := defMap[key].([]any)
+			if !ok {
+				continue
+			}
+
+			for i, member := range members {
+				memberMap, ok := member.(map[string]any)
+				if !ok || !isInlineObject(memberMap) {
+					continue
+				}
+
+				variantName := deriveVariantName(defName, i, memberMap, definitions, spellings)
+				definitions[variantName] = memberMap
+				members[i] = map[string]any{"$ref": "#/definitions/" + variantName}
+			}
+		}
+	}
+}
+
+// isUnionSchema reports whether a schema is a oneOf or anyOf composition with at least
+// two non-null members that describe types
+func isUnionSchema(schema map[string]any) bool {
+	if _, overridden := goTypeOverride(schema); overridden {
+		return false
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok && !isConstraintOnly(members) && len(nonNullMembers(members)) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNullMembers returns the composition members that are not `{"type": "null"}`, which
+// only mark the composition as nullable
+func nonNullMembers(members []any) []any {
+	result := make([]any, 0, len(members))
+	for _, member := range members {
+		if memberMap, ok := member.(map[string]any); ok {
+			if memberType, _ := memberMap["type"].(string); memberType == "null" && len(memberMap) == 1 {
+				continue
+			}
+		}
+		result = append(result, member)
+	}
+	return result
+}
+
+// isInlineObject reports whether an inline schema describes an object that should become a struct
+func isInlineObject(schema map[string]any) bool {
+	if _, isRef := schema["$ref"]; isRef {
+		return false
+	}
+	if _, hasProperties := schema["properties"]; hasProperties {
+		return true
+	}
+	if _, hasAllOf := schema["allOf"]; hasAllOf {
+		return true
+	}
+	return false
+}
+
+// deriveVariantName names a hoisted union member from its x-go-name or title, from a constant property
+// value (e.g. `type: {const: "text"}` -> "ContentText"), or from its position in the union
+func deriveVariantName(unionName string, index int, memberMap map[string]any, definitions map[string]any, spellings map[string]string) string {
+	if name, ok := goNameOverride(memberMap); ok {
+		return uniqueDefinitionName(definitions, name)
+	}
+
+	if title, ok := memberMap["title"].(string); ok {
+		if name := convertToGoFieldName(title, spellings); name != "" {
+			if _, exists := definitions[name]; !exists {
+				return name
+			}
+		}
+	}
+
+	if properties, ok := memberMap["properties"].(map[string]any); ok {
+		propNames := make([]string, 0, len(properties))
+		for propName := range properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+
+		for _, propName := range propNames {
+			if propMap, ok := properties[propName].(map[string]any); ok {
+				if value, ok := constantString(propMap); ok {
+					name := unionName + convertToGoFieldName(value, spellings)
+					if _, exists := definitions[name]; !exists {
+						return name
+					}
+				}
+			}
+		}
+	}
+
+	return unionName + "Option" + strconv.Itoa(index+1)
+}
+
+// constantString returns the single string value a property can take, declared either
+// with `const` or with a one-element `enum`
+func constantString(propMap map[string]any) (string, bool) {
+	if value, ok := propMap["const"].(string); ok {
+		return value, true
+	}
+	if enum, ok := propMap["enum"].([]any); ok && len(enum) == 1 {
+		if value, ok := enum[0].(string); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// planOneOf decides how a oneOf definition is generated. It returns false when one of the
+// members cannot be represented as a variant type, in which case the union falls back to any.
+func planOneOf(typeName string, defMap map[string]any, definitions map[string]any, spellings map[string]string, visiting map[string]bool) (oneOfPlan, bool) {
+	var plan oneOfPlan
+
+	members, ok := defMap["oneOf"].([]any)
+	if !ok || len(members) == 0 || isConstraintOnly(members) {
+		return plan, false
+	}
+	members = nonNullMembers(members)
+
+	visiting[typeName] = true
+	defer delete(visiting, typeName)
+
+	seen := make(map[string]bool)
+	for _, member := range members {
+		memberMap, ok := member.(map[string]any)
+		if !ok {
+			return plan, false
+		}
+
+		var variant oneOfVariant
+		if ref, ok := memberMap["$ref"].(string); ok {
+			name := refName(ref)
+			target, ok := definitions[name].(map[string]any)
+			if !ok || visiting[name] {
+				return plan, false
+			}
+
+			variant.refName = name
+			switch {
+			case isStructDefinition(target, definitions), isEnumDefinition(target), isConstDefinition(target):
+				variant.typeName = name
+			case target["oneOf"] != nil:
+				if _, ok := planOneOf(name, target, definitions, spellings, visiting); !ok {
+					return plan, false
+				}
+				variant.typeName = name
+			default:
+				if goType := determineGoType(target, definitions); goType == "any" || strings.HasPrefix(goType, "*") {
+					return plan, false
+				}
+				variant.typeName = typeName + name
+				variant.wrapOf = determineGoType(memberMap, definitions)
+			}
+		} else {
+			goType := strings.TrimPrefix(determineGoType(memberMap, definitions), "*")
+			if goType == "any" {
+				return plan, false
+			}
+			variant.typeName = typeName + goTypeLabel(goType, spellings)
+			variant.wrapOf = goType
+		}
+
+		if seen[variant.typeName] {
+			return plan, false
+		}
+		seen[variant.typeName] = true
+		plan.variants = append(plan.variants, variant)
+	}
+
+	plan.discriminator = assignDiscriminator(plan.variants, defMap, definitions)
+	return plan, true
+}
+
+// assignDiscriminator determines the discriminator property of a union and the values that
+// select each variant. An explicit OpenAPI discriminator is preferred; otherwise a property
+// that holds a distinct constant in every variant is used. It returns an empty string when
+// variants have to be matched structurally.
+func assignDiscriminator(variants []oneOfVariant, defMap map[string]any, definitions map[string]any) string {
+	if discriminator, ok := defMap["discriminator"].(map[string]any); ok {
+		propertyName, _ := discriminator["propertyName"].(string)
+		if propertyName != "" {
+			if mapping, ok := discriminator["mapping"].(map[string]any); ok && len(mapping) > 0 {
+				values := make([]string, 0, len(mapping))
+				for value := range mapping {
+					values = append(values, value)
+				}
+				sort.Strings(values)
+
+				for _, value := range values {
+					ref, _ := mapping[value].(string)
+					for i := range variants {
+						if variants[i].refName == refName(ref) {
+							variants[i].discriminatorValues = append(variants[i].discriminatorValues, value)
+						}
+					}
+				}
+			} else {
+				// Without a mapping, a variant is selected by the value its discriminator
+				// property carries, or else by its schema name
+				for i := range variants {
+					if variants[i].refName == "" {
+						continue
+					}
+					target, _ := definitions[variants[i].refName].(map[string]any)
+					propMap, _ := collectProperties(target)[propertyName].(map[string]any)
+					if value, ok := constantString(propMap); ok {
+						variants[i].discriminatorValues = []string{value}
+					} else {
+						variants[i].discriminatorValues = []string{variants[i].refName}
+					}
+				}
+			}
+
+			if allVariantsDiscriminated(variants) {
+				return propertyName
+			}
+			clearDiscriminatorValues(variants)
+		}
+	}
+
+	var candidates []string
+	for i, variant := range variants {
+		if variant.wrapOf != "" || variant.refName == "" {
+			return ""
+		}
+
+		target, _ := definitions[variant.refName].(map[string]any)
+		properties := collectProperties(target)
+
+		var names []string
+		for propName, propDef := range properties {
+			if propMap, ok := propDef.(map[string]any); ok {
+				if _, ok := constantString(propMap); ok {
+					names = append(names, propName)
+				}
+			}
+		}
+
+		if i == 0 {
+			candidates = names
+			continue
+		}
+
+		var remaining []string
+		for _, candidate := range candidates {
+			for _, name := range names {
+				if candidate == name {
+					remaining = append(remaining, candidate)
+				}
+			}
+		}
+		candidates = remaining
+	}
+	sort.Strings(candidates)
+
+	for _, candidate := range candidates {
+		used := make(map[string]bool)
+		distinct := true
+		for i, variant := range variants {
+			target, _ := definitions[variant.refName].(map[string]any)
+			propMap, _ := collectProperties(target)[candidate].(map[string]any)
+			value, _ := constantString(propMap)
+			if used[value] {
+				distinct = false
+				break
+			}
+			used[value] = true
+			variants[i].discriminatorValues = []string{value}
+		}
+
+		if distinct {
+			return candidate
+		}
+		clearDiscriminatorValues(variants)
+	}
+
+	return ""
+}
+
+// allVariantsDiscriminated reports whether every variant is selected by at least one value
+func allVariantsDiscriminated(variants []oneOfVariant) bool {
+	for _, variant := range variants {
+		if len(variant.discriminatorValues) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// clearDiscriminatorValues resets discriminator values after a candidate was rejected
+func clearDiscriminatorValues(variants []oneOfVariant) {
+	for i := range variants {
+		variants[i].discriminatorValues = nil
+	}
+}
+
+// isConstraintOnly reports whether composition members only add constraints (e.g. alternative
+// required lists or documented const values) without describing types of their own
+func isConstraintOnly(members []any) bool {
+	for _, member := range members {
+		memberMap, ok := member.(map[string]any)
+		if !ok {
+			return false
+		}
+		for _, key := range []string{"$ref", "type", "properties", "items", "oneOf", "anyOf", "allOf"} {
+			if _, ok := memberMap[key]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isEnumDefinition reports whether a definition is generated as an enum type
+func isEnumDefinition(defMap map[string]any) bool {
+	enum, ok := defMap["enum"].([]any)
+	return ok && len(enum) > 0
+}
+
+// goTypeLabel turns a Go type expression into an identifier suffix (e.g. "[]string" -> "StringList")
+func goTypeLabel(goType string, spellings map[string]string) string {
+	if strings.HasPrefix(goType, "map[") {
+		return "Map"
+	}
+
+	lists := 0
+	for strings.HasPrefix(goType, "[]") {
+		goType = strings.TrimPrefix(goType, "[]")
+		lists++
+	}
+
+	if dot := strings.LastIndex(goType, "."); dot >= 0 {
+		goType = goType[dot+1:]
+	}
+
+	return convertToGoFieldName(goType, spellings) + strings.Repeat("List", lists)
+}
+
+// anyOfField describes one optional variant field of an anyOf wrapper struct
+type anyOfField struct {
+	name   string // Go field name
+	goType string // Go type of the variant (the field holds a pointer to it)
+}
+
+// planAnyOf decides how an anyOf definition is generated. It returns false when the members
+// cannot be mapped to distinct fields, in which case the union falls back to any.
+func planAnyOf(defMap map[string]any, definitions map[string]any, spellings map[string]string) ([]anyOfField, bool) {
+	members, ok := defMap["anyOf"].([]any)
+	if !ok || len(members) == 0 || isConstraintOnly(members) {
+		return nil, false
+	}
+
+	var fields []anyOfField
+	seen := make(map[string]bool)
+	for _, member := range nonNullMembers(members) {
+		memberMap, ok := member.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		goType := strings.TrimPrefix(determineGoType(memberMap, definitions), "*")
+		if goType == "any" {
+			return nil, false
+		}
+
+		name := goTypeLabel(goType, spellings)
+		if _, isRef := memberMap["$ref"]; isRef {
+			name = goType
+		}
+
+		if seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		fields = append(fields, anyOfField{name: name, goType: goType})
+	}
+
+	return fields, len(fields) > 1
+}
+
+// containsUnionType reports whether any definition is generated as a oneOf or anyOf union
+func containsUnionType(definitions map[string]any, spellings map[string]string) bool {
+	for typeName, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && isUnionDefinition(typeName, defMap, definitions, spellings) {
+			return true
+		}
+	}
+	return false
+}
+
+// unionHelpers records which of the helpers written by generateUnionHelpers generated unions
+// call
+type unionHelpers struct {
+	variants bool // decodeUnionVariant, for oneOf unions without a discriminator
+	anyOf    bool // decodeAnyOfVariants and mergeUnionObjects, for anyOf unions
+}
+
+// usedUnionHelpers returns the union helpers the generated oneOf and anyOf unions call
+func usedUnionHelpers(definitions map[string]any, spellings map[string]string) unionHelpers {
+	var helpers unionHelpers
+	for typeName, definition := range definitions {
+		defMap, ok := definition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if plan, ok := planOneOf(typeName, defMap, definitions, spellings, map[string]bool{}); ok {
+			helpers.variants = helpers.variants || plan.discriminator == ""
+		} else if _, ok := planAnyOf(defMap, definitions, spellings); ok {
+			helpers.variants = true
+			helpers.anyOf = true
+		}
+	}
+	return helpers
+}
+
+// isUnionDefinition reports whether a definition is generated as a oneOf sum type or an anyOf
+// wrapper, rather than falling back to any
+func isUnionDefinition(typeName string, defMap map[string]any, definitions map[string]any, spellings map[string]string) bool {
+	if _, ok := planOneOf(typeName, defMap, definitions, spellings, map[string]bool{}); ok {
+		return true
+	}
+	_, ok := planAnyOf(defMap, definitions, spellings)
+	return ok
+}
+
+// generateAnyOfType generates a wrapper struct for an anyOf definition with one optional field
+// per variant. Unmarshaling populates every variant the data matches; marshaling merges the
+// populated object variants.
+func generateAnyOfType(out *bytes.Buffer, typeName string, fields []anyOfField) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+	for _, field := range fields {
+		fmt.Fprintf(&b, "\t%s *%s\n", field.name, field.goType)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// MarshalJSON encodes the populated variants of %s, merging object variants\n", typeName)
+	fmt.Fprintf(&b, "func (u %s) MarshalJSON() ([]byte, error) {\n", typeName)
+	b.WriteString("\tvar parts [][]byte\n")
+	for _, field := range fields {
+		fmt.Fprintf(&b, "\tif u.%s != nil {\n", field.name)
+		fmt.Fprintf(&b, "\t\tdata, err := json.Marshal(u.%s)\n", field.name)
+		b.WriteString("\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+		b.WriteString("\t\tparts = append(parts, data)\n\t}\n")
+	}
+	b.WriteString("\treturn mergeUnionObjects(parts)\n}\n\n")
+
+	fmt.Fprintf(&b, "// UnmarshalJSON decodes %s into every variant the data matches\n", typeName)
+	fmt.Fprintf(&b, "func (u *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+	targets := make([]string, 0, len(fields))
+	for i, field := range fields {
+		fmt.Fprintf(&b, "\tvar value%d %s\n", i, field.goType)
+		targets = append(targets, fmt.Sprintf("&value%d", i))
+	}
+	fmt.Fprintf(&b, "\tmatched, err := decodeAnyOfVariants(data, %s)\n", strings.Join(targets, ", "))
+	fmt.Fprintf(&b, "\tif err != nil {\n\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t}\n\n", typeName)
+	fmt.Fprintf(&b, "\t*u = %s{}\n", typeName)
+	for i, field := range fields {
+		fmt.Fprintf(&b, "\tif matched[%d] {\n\t\tu.%s = &value%d\n\t}\n", i, field.name, i)
+	}
+	b.WriteString("\n\treturn nil\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateOneOfType generates a sum type for a oneOf definition: a struct holding the active
+// variant, an interface implemented by every variant, and JSON (un)marshaling methods
+func generateOneOfType(out *bytes.Buffer, typeName string, plan oneOfPlan) error {
+	variantInterface := typeName + "Variant"
+	marker := "is" + variantInterface
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "type %s struct {\n\tValue %s\n}\n\n", typeName, variantInterface)
+	fmt.Fprintf(&b, "// %s is implemented by every variant of %s\n", variantInterface, typeName)
+	fmt.Fprintf(&b, "type %s interface {\n\t%s()\n}\n\n", variantInterface, marker)
+
+	for _, variant := range plan.variants {
+		if variant.wrapOf != "" {
+			fmt.Fprintf(&b, "// %s is the %s variant of %s\n", variant.typeName, variant.wrapOf, typeName)
+			fmt.Fprintf(&b, "type %s %s\n\n", variant.typeName, variant.wrapOf)
+		}
+	}
+
+	for _, variant := range plan.variants {
+		fmt.Fprintf(&b, "func (%s) %s() {}\n", variant.typeName, marker)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "// MarshalJSON encodes the active variant of %s\n", typeName)
+	fmt.Fprintf(&b, "func (u %s) MarshalJSON() ([]byte, error) {\n\treturn json.Marshal(u.Value)\n}\n\n", typeName)
+
+	if plan.discriminator != "" {
+		fmt.Fprintf(&b, "// UnmarshalJSON decodes %s using the %q discriminator\n", typeName, plan.discriminator)
+		fmt.Fprintf(&b, "func (u *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+		fmt.Fprintf(&b, "\tvar discriminator struct {\n\t\tValue string `json:\"%s\"`\n\t}\n", plan.discriminator)
+		b.WriteString("\tif err := json.Unmarshal(data, &discriminator); err != nil {\n\t\treturn err\n\t}\n\n")
+		b.WriteString("\tswitch discriminator.Value {\n")
+		for _, variant := range plan.variants {
+			quoted := make([]string, 0, len(variant.discriminatorValues))
+			for _, value := range variant.discriminatorValues {
+				quoted = append(quoted, strconv.Quote(value))
+			}
+			fmt.Fprintf(&b, "\tcase %s:\n", strings.Join(quoted, ", "))
+			fmt.Fprintf(&b, "\t\tvar value %s\n", variant.typeName)
+			b.WriteString("\t\tif err := json.Unmarshal(data, &value); err != nil {\n\t\t\treturn err\n\t\t}\n")
+			b.WriteString("\t\tu.Value = value\n")
+		}
+		b.WriteString("\tdefault:\n")
+		fmt.Fprintf(&b, "\t\treturn fmt.Errorf(\"unknown %s %s %%q\", discriminator.Value)\n", typeName, plan.discriminator)
+		b.WriteString("\t}\n\n\treturn nil\n}\n\n")
+	} else {
+		fmt.Fprintf(&b, "// UnmarshalJSON decodes %s into the first variant that matches the data exactly\n", typeName)
+		fmt.Fprintf(&b, "func (u *%s) UnmarshalJSON(data []byte) error {\n", typeName)
+		for i, variant := range plan.variants {
+			fmt.Fprintf(&b, "\tvar value%d %s\n", i, variant.typeName)
+			fmt.Fprintf(&b, "\tif err := decodeUnionVariant(data, &value%d); err == nil {\n", i)
+			fmt.Fprintf(&b, "\t\tu.Value = value%d\n\t\treturn nil\n\t}\n\n", i)
+		}
+		fmt.Fprintf(&b, "\treturn fmt.Errorf(\"data does not match any variant of %s\")\n}\n\n", typeName)
+	}
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateUnionHelpers writes the decoding and encoding helpers shared by generated unions,
+// those the unions do not call left out
+func generateUnionHelpers(out *bytes.Buffer, helpers unionHelpers) error {
+	if helpers.variants {
+		if _, err := out.WriteString(unionVariantHelper); err != nil {
+			return err
+		}
+	}
+	if helpers.anyOf {
+		if _, err := out.WriteString(anyOfHelpers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unionVariantHelper decodes the variants of structural oneOf and anyOf unions
+const unionVariantHelper = `// decodeUnionVariant decodes data into v, rejecting unknown fields so that
+// only the variant matching the payload exactly is selected
+func decodeUnionVariant(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+`
+
+// anyOfHelpers decodes and encodes anyOf unions, with unionVariantHelper
+const anyOfHelpers = `// decodeAnyOfVariants decodes data into every target it matches exactly, falling back to a
+// lenient decode when no target matches exactly, and reports which targets were populated
+func decodeAnyOfVariants(data []byte, targets ...any) ([]bool, error) {
+	matched := make([]bool, len(targets))
+	found := false
+	for i, target := range targets {
+		if decodeUnionVariant(data, target) == nil {
+			matched[i] = true
+			found = true
+		}
+	}
+
+	if !found {
+		for i, target := range targets {
+			if json.Unmarshal(data, target) == nil {
+				matched[i] = true
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("data does not match any variant")
+	}
+	return matched, nil
+}
+
+// mergeUnionObjects combines the encoded variants of an anyOf union into a single value
+func mergeUnionObjects(parts [][]byte) ([]byte, error) {
+	switch len(parts) {
+	case 0:
+		return []byte("null"), nil
+	case 1:
+		return parts[0], nil
+	}
+
+	merged := make(map[string]json.RawMessage)
+	for _, part := range parts {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(part, &fields); err != nil {
+			return nil, fmt.Errorf("cannot merge non-object union variants: %w", err)
+		}
+		for key, value := range fields {
+			merged[key] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+`
+
+// copySchema returns a shallow copy of a schema map
+func copySchema(schema map[string]any) map[string]any {
+	copied := make(map[string]any, len(schema))
+	for key, value := range schema {
+		copied[key] = value
+	}
+	return copied
+}
+
+// sortedDefinitionNames returns the definition names in alphabetical order
+func sortedDefinitionNames(definitions map[string]any) []string {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
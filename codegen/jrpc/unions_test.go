@@ -0,0 +1,46 @@
+package jrpc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+func TestUnionHelpers(t *testing.T) {
+	const cat = `"Cat": {"type": "object", "properties": {"kind": {"const": "cat"}, "lives": {"type": "integer"}}, "required": ["kind"]}`
+	const dog = `"Dog": {"type": "object", "properties": {"kind": {"const": "dog"}, "bark": {"type": "string"}}, "required": ["kind"]}`
+	tests := []struct {
+		name    string
+		union   string
+		helpers []string // Helpers the generated code declares, of decodeUnionVariant, decodeAnyOfVariants and mergeUnionObjects
+	}{
+		{"no union", `"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}`, nil},
+		{"discriminated oneOf", `"Pet": {"oneOf": [{"$ref": "#/definitions/Cat"}, {"$ref": "#/definitions/Dog"}], "discriminator": {"propertyName": "kind"}}`, nil},
+		{"structural oneOf", `"Pet": {"oneOf": [{"type": "string"}, {"$ref": "#/definitions/Cat"}]}`, []string{"decodeUnionVariant"}},
+		{"anyOf", `"Pet": {"anyOf": [{"$ref": "#/definitions/Cat"}, {"$ref": "#/definitions/Dog"}]}`, []string{"decodeUnionVariant", "decodeAnyOfVariants", "mergeUnionObjects"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			schema := `{"definitions": {` + cat + `, ` + dog + `, ` + test.union + `}}`
+			if err := GenerateTypesTo(&out, []byte(schema), &GeneratorOptions{PackageName: "generated", FormatOutput: true}); err != nil {
+				t.Fatal(err)
+			}
+			source := out.String()
+
+			for _, helper := range []string{"decodeUnionVariant", "decodeAnyOfVariants", "mergeUnionObjects"} {
+				declared := strings.Contains(source, "func "+helper+"(")
+				want := false
+				for _, name := range test.helpers {
+					want = want || name == helper
+				}
+				if declared != want {
+					t.Errorf("%s declared = %t, want %t", helper, declared, want)
+				}
+			}
+			gentest.Vet(t, map[string]string{"generated.go": source})
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package jrpc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// validatorFormats maps string formats to go-playground/validator tags
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"uri":      "uri",
+	"url":      "url",
+	"uuid":     "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname_rfc1123",
+}
+
+// constraintSchema returns the schema holding the constraints of a property: the property
+// itself, or the referenced definition when the property is a $ref to a non-struct type
+func constraintSchema(propMap map[string]any, definitions map[string]any) map[string]any {
+	ref, ok := propMap["$ref"].(string)
+	if !ok {
+		return propMap
+	}
+	target, ok := definitions[refName(ref)].(map[string]any)
+	if !ok || isStructDefinition(target, definitions) || isOverriddenDefinition(target) {
+		return propMap
+	}
+	return target
+}
+
+// validateRules translates the JSON Schema constraints of a property into a
+// go-playground/validator tag value. Optional fields get `omitempty` so that their
+// constraints only apply when a value is present. `pattern` has no built-in validator
+// equivalent and is not translated.
+func validateRules(propMap map[string]any, goType string, required bool, definitions map[string]any) string {
+	schema := constraintSchema(propMap, definitions)
+
+	var rules []string
+	if required {
+		if strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || scalarKind(schema, definitions, map[string]bool{}) == "string" {
+			rules = append(rules, "required")
+		}
+	} else {
+		rules = append(rules, "omitempty")
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		if value, ok := schemaNumber(schema, "minLength"); ok {
+			rules = append(rules, "min="+value)
+		}
+		if value, ok := schemaNumber(schema, "maxLength"); ok {
+			rules = append(rules, "max="+value)
+		}
+		if format, ok := schema["format"].(string); ok {
+			if tag, ok := validatorFormats[format]; ok {
+				rules = append(rules, tag)
+			}
+		}
+	case "integer", "number":
+		rules = append(rules, numericRules(schema)...)
+	case "array":
+		if !strings.HasPrefix(goType, "[]") {
+			break
+		}
+		if value, ok := schemaNumber(schema, "minItems"); ok {
+			rules = append(rules, "min="+value)
+		}
+		if value, ok := schemaNumber(schema, "maxItems"); ok {
+			rules = append(rules, "max="+value)
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+			rules = append(rules, "unique")
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		if values, ok := oneOfValues(enum); ok {
+			rules = append(rules, "oneof="+values)
+		}
+	}
+
+	if len(rules) == 0 || (len(rules) == 1 && rules[0] == "omitempty") {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// numericRules returns the validator rules for minimum, maximum and their exclusive forms,
+// accepting both the draft 4 boolean and the draft 6+ numeric exclusive keywords
+func numericRules(schema map[string]any) []string {
+	var rules []string
+
+	if value, ok := schemaNumber(schema, "minimum"); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+			rules = append(rules, "gt="+value)
+		} else {
+			rules = append(rules, "gte="+value)
+		}
+	}
+	if value, ok := schemaNumber(schema, "exclusiveMinimum"); ok {
+		rules = append(rules, "gt="+value)
+	}
+
+	if value, ok := schemaNumber(schema, "maximum"); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive {
+			rules = append(rules, "lt="+value)
+		} else {
+			rules = append(rules, "lte="+value)
+		}
+	}
+	if value, ok := schemaNumber(schema, "exclusiveMaximum"); ok {
+		rules = append(rules, "lt="+value)
+	}
+
+	return rules
+}
+
+// schemaNumber returns a numeric keyword of a schema formatted for use in a tag
+func schemaNumber(schema map[string]any, keyword string) (string, bool) {
+	switch value := schema[keyword].(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(value), true
+	}
+	return "", false
+}
+
+// oneOfValues renders enum values for the validator `oneof` rule, which separates values
+// with spaces and therefore cannot express values containing whitespace or tag syntax
+func oneOfValues(enum []any) (string, bool) {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		var rendered string
+		switch v := value.(type) {
+		case string:
+			rendered = v
+		case float64:
+			rendered = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			rendered = strconv.Itoa(v)
+		default:
+			return "", false
+		}
+		if rendered == "" || strings.ContainsAny(rendered, " \t\n,|`\"\\'") {
+			return "", false
+		}
+		values = append(values, rendered)
+	}
+	return strings.Join(values, " "), true
+}
@@ -0,0 +1,354 @@
+package jrpc
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fieldValidation describes the checks generated for one struct field in its Validate method
+type fieldValidation struct {
+	path      string // JSON name of the field, used in error messages
+	fieldName string // Go field name
+	fieldType string // Go field type
+	nullable  bool   // Whether a nil pointer is a valid value
+	required  bool   // Whether the property is required
+}
+
+// containsStruct reports whether any definition is generated as a struct, and therefore gets
+// the functions generated for structs (Validate methods, example factories)
+func containsStruct(definitions map[string]any) bool {
+	for _, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && isStructDefinition(defMap, definitions) {
+			return true
+		}
+	}
+	return false
+}
+
+// validationImports returns the packages used by the generated Validate methods and helpers
+func validationImports(definitions map[string]any) []string {
+	imports := []string{"errors", "fmt"}
+	needsRegexp, needsUTF8 := false, false
+
+	for _, definition := range definitions {
+		defMap, ok := definition.(map[string]any)
+		if !ok || !isStructDefinition(defMap, definitions) {
+			continue
+		}
+		for _, propDef := range structProperties(defMap, definitions) {
+			propMap, ok := propDef.(map[string]any)
+			if !ok {
+				continue
+			}
+			schema := constraintSchema(propMap, definitions)
+			if _, ok := compiledPattern(schema, definitions); ok {
+				needsRegexp = true
+			}
+			if _, _, ok := stringLengthBounds(schema, definitions); ok {
+				needsUTF8 = true
+			}
+		}
+	}
+
+	if needsRegexp {
+		imports = append(imports, "regexp")
+	}
+	if needsUTF8 {
+		imports = append(imports, "unicode/utf8")
+	}
+	return imports
+}
+
+// compiledPattern returns the `pattern` of a string schema when it compiles as a Go regular
+// expression; ECMAScript-only syntax such as lookarounds is not checked
+func compiledPattern(schema map[string]any, definitions map[string]any) (string, bool) {
+	pattern, ok := schema["pattern"].(string)
+	if !ok || scalarKind(schema, definitions, map[string]bool{}) != "string" {
+		return "", false
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", false
+	}
+	return pattern, true
+}
+
+// stringLengthBounds returns the minLength and maxLength of a string schema (-1 when unset)
+func stringLengthBounds(schema map[string]any, definitions map[string]any) (int, int, bool) {
+	if scalarKind(schema, definitions, map[string]bool{}) != "string" {
+		return 0, 0, false
+	}
+	minLength, maxLength := -1, -1
+	if value, ok := schemaNumber(schema, "minLength"); ok {
+		minLength, _ = strconv.Atoi(value)
+	}
+	if value, ok := schemaNumber(schema, "maxLength"); ok {
+		maxLength, _ = strconv.Atoi(value)
+	}
+	return minLength, maxLength, minLength >= 0 || maxLength >= 0
+}
+
+// validationStatements renders the checks of a struct field on the receiver x. Checks on the
+// field value of pointer fields only run when the pointer is set.
+func validationStatements(typeName string, field fieldValidation, propMap map[string]any, definitions map[string]any) (string, string) {
+	schema := constraintSchema(propMap, definitions)
+	isPointer := strings.HasPrefix(field.fieldType, "*")
+	value := "x." + field.fieldName
+	if isPointer {
+		value = "*x." + field.fieldName
+	}
+
+	var checks []string
+	var declarations string
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		kind := scalarKind(schema, definitions, map[string]bool{})
+		var literals, labels []string
+		for _, member := range enum {
+			valueType, literal, ok := constLiteral(member)
+			if !ok || !literalMatchesKind(valueType, kind) {
+				continue
+			}
+			literals = append(literals, literal)
+			labels = append(labels, fmt.Sprint(member))
+		}
+		if len(literals) > 0 {
+			message := strings.ReplaceAll("must be one of "+strings.Join(labels, ", "), "%", "%%")
+			checks = append(checks, fmt.Sprintf("switch %s {\ncase %s:\ndefault:\n\terrs.add(%q, %q)\n}\n",
+				value, strings.Join(literals, ", "), field.path, message))
+		}
+	}
+
+	if minLength, maxLength, ok := stringLengthBounds(schema, definitions); ok {
+		text := value
+		if strings.TrimPrefix(field.fieldType, "*") != "string" {
+			text = fmt.Sprintf("string(%s)", value)
+		}
+		length := fmt.Sprintf("utf8.RuneCountInString(%s)", text)
+		if minLength >= 0 {
+			checks = append(checks, fmt.Sprintf("if %s < %d {\n\terrs.add(%q, \"length must be at least %d\")\n}\n", length, minLength, field.path, minLength))
+		}
+		if maxLength >= 0 {
+			checks = append(checks, fmt.Sprintf("if %s > %d {\n\terrs.add(%q, \"length must be at most %d\")\n}\n", length, maxLength, field.path, maxLength))
+		}
+	}
+
+	if pattern, ok := compiledPattern(schema, definitions); ok {
+		patternVar := patternVariableName(typeName, field.fieldName)
+		declarations = fmt.Sprintf("var %s = regexp.MustCompile(%s)\n", patternVar, strconv.Quote(pattern))
+		text := value
+		if strings.TrimPrefix(field.fieldType, "*") != "string" {
+			text = fmt.Sprintf("string(%s)", value)
+		}
+		checks = append(checks, fmt.Sprintf("if !%s.MatchString(%s) {\n\terrs.add(%q, \"must match pattern %%q\", %s.String())\n}\n",
+			patternVar, text, field.path, patternVar))
+	}
+
+	if kind := scalarKind(schema, definitions, map[string]bool{}); kind == "integer" || kind == "number" {
+		checks = append(checks, numericChecks(schema, kind, value, field.path)...)
+	}
+
+	var b strings.Builder
+	if !field.nullable && field.required && (isPointer || strings.HasPrefix(field.fieldType, "[]") || strings.HasPrefix(field.fieldType, "map[")) {
+		fmt.Fprintf(&b, "\tif x.%s == nil {\n\t\terrs.add(%q, \"is required\")\n\t}\n", field.fieldName, field.path)
+	}
+
+	if len(checks) > 0 {
+		indent := "\t"
+		if isPointer {
+			fmt.Fprintf(&b, "\tif x.%s != nil {\n", field.fieldName)
+			indent = "\t\t"
+		}
+		for _, check := range checks {
+			b.WriteString(indentLines(check, indent))
+		}
+		if isPointer {
+			b.WriteString("\t}\n")
+		}
+	}
+
+	if schemaType(schema) == "array" && strings.HasPrefix(field.fieldType, "[]") {
+		if minItems, ok := schemaNumber(schema, "minItems"); ok {
+			// An optional array left out is valid, as with the omitempty of its validate tag
+			guard := ""
+			if !field.required {
+				guard = fmt.Sprintf("len(x.%s) > 0 && ", field.fieldName)
+			}
+			fmt.Fprintf(&b, "\tif %slen(x.%s) < %s {\n\t\terrs.add(%q, \"must contain at least %s items\")\n\t}\n", guard, field.fieldName, minItems, field.path, minItems)
+		}
+		if maxItems, ok := schemaNumber(schema, "maxItems"); ok {
+			fmt.Fprintf(&b, "\tif len(x.%s) > %s {\n\t\terrs.add(%q, \"must contain at most %s items\")\n\t}\n", field.fieldName, maxItems, field.path, maxItems)
+		}
+	}
+
+	b.WriteString(nestedValidation(field, propMap, definitions))
+
+	return b.String(), declarations
+}
+
+// literalMatchesKind reports whether a constant of the given Go type can be compared with
+// values of a schema of the given JSON type
+func literalMatchesKind(valueType string, kind string) bool {
+	switch kind {
+	case "string":
+		return valueType == "string"
+	case "boolean":
+		return valueType == "bool"
+	case "integer":
+		return valueType == "int"
+	case "number":
+		return valueType == "int" || valueType == "float64"
+	}
+	return false
+}
+
+// numericChecks renders the minimum/maximum checks of a numeric value, converting integer
+// fields to float64 when a bound is fractional
+func numericChecks(schema map[string]any, kind string, value string, path string) []string {
+	type bound struct {
+		keyword, operator, message string
+	}
+	bounds := []bound{
+		{"minimum", "<", "must be at least"},
+		{"maximum", ">", "must be at most"},
+		{"exclusiveMinimum", "<=", "must be greater than"},
+		{"exclusiveMaximum", ">=", "must be less than"},
+	}
+	if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+		bounds[0] = bound{"minimum", "<=", "must be greater than"}
+	}
+	if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive {
+		bounds[1] = bound{"maximum", ">=", "must be less than"}
+	}
+
+	var checks []string
+	for _, b := range bounds {
+		limit, ok := schemaNumber(schema, b.keyword)
+		if !ok {
+			continue
+		}
+		operand := value
+		if parsed, _ := strconv.ParseFloat(limit, 64); kind == "integer" && parsed != math.Trunc(parsed) {
+			operand = fmt.Sprintf("float64(%s)", value)
+		}
+		checks = append(checks, fmt.Sprintf("if %s %s %s {\n\terrs.add(%q, \"%s %s\")\n}\n", operand, b.operator, limit, path, b.message, limit))
+	}
+	return checks
+}
+
+// nestedValidation renders the call to the Validate method of a struct-typed field, through
+// pointers and slices
+func nestedValidation(field fieldValidation, propMap map[string]any, definitions map[string]any) string {
+	target := propMap
+	if items, ok := propMap["items"].(map[string]any); ok && strings.HasPrefix(field.fieldType, "[]") {
+		target = items
+	}
+
+	ref, ok := target["$ref"].(string)
+	if !ok {
+		return ""
+	}
+	if _, overridden := goTypeOverride(target); overridden {
+		return ""
+	}
+	refDef, ok := definitions[refName(ref)].(map[string]any)
+	if !ok || !isStructDefinition(refDef, definitions) {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(field.fieldType, "[]*"):
+		return fmt.Sprintf("\tfor i, item := range x.%s {\n\t\tif item != nil {\n\t\t\terrs.nested(fmt.Sprintf(\"%s[%%d]\", i), item.Validate())\n\t\t}\n\t}\n", field.fieldName, field.path)
+	case strings.HasPrefix(field.fieldType, "[]"):
+		return fmt.Sprintf("\tfor i := range x.%s {\n\t\terrs.nested(fmt.Sprintf(\"%s[%%d]\", i), x.%s[i].Validate())\n\t}\n", field.fieldName, field.path, field.fieldName)
+	case strings.HasPrefix(field.fieldType, "*"):
+		return fmt.Sprintf("\tif x.%s != nil {\n\t\terrs.nested(%q, x.%s.Validate())\n\t}\n", field.fieldName, field.path, field.fieldName)
+	}
+	return fmt.Sprintf("\terrs.nested(%q, x.%s.Validate())\n", field.path, field.fieldName)
+}
+
+// patternVariableName returns the name of the package-level regexp holding a field pattern
+func patternVariableName(typeName string, fieldName string) string {
+	return strings.ToLower(typeName[:1]) + typeName[1:] + fieldName + "Pattern"
+}
+
+// indentLines prefixes every non-empty line of a block with indent
+func indentLines(block string, indent string) string {
+	lines := strings.SplitAfter(block, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// generateValidateMethod writes a Validate method that checks the schema constraints of a
+// struct, reporting every violation, after validating its embedded types
+func generateValidateMethod(out *bytes.Buffer, typeName string, embedded []string, statements []string, declarations []string) error {
+	var b strings.Builder
+
+	for _, declaration := range declarations {
+		b.WriteString(declaration)
+	}
+	if len(declarations) > 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "// Validate checks that %s satisfies the constraints of its schema\n", typeName)
+	fmt.Fprintf(&b, "func (x %s) Validate() error {\n", typeName)
+	b.WriteString("\tvar errs validationErrors\n")
+	for _, embeddedType := range embedded {
+		fmt.Fprintf(&b, "\terrs.merge(x.%s.Validate())\n", embeddedType)
+	}
+	for _, statement := range statements {
+		b.WriteString(statement)
+	}
+	b.WriteString("\treturn errs.err()\n}\n\n")
+
+	_, err := out.WriteString(b.String())
+	return err
+}
+
+// generateValidationHelpers writes the error collector used by the generated Validate methods
+func generateValidationHelpers(out *bytes.Buffer) error {
+	helper := `// validationErrors collects the constraint violations found by Validate methods
+type validationErrors []error
+
+// add records a violation of the field at path
+func (e *validationErrors) add(path string, format string, args ...any) {
+	*e = append(*e, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// nested records the violations of a nested value at path
+func (e *validationErrors) nested(path string, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, inner := range joined.Unwrap() {
+			*e = append(*e, fmt.Errorf("%s: %w", path, inner))
+		}
+		return
+	}
+	if err != nil {
+		*e = append(*e, fmt.Errorf("%s: %w", path, err))
+	}
+}
+
+// merge records the violations of an embedded value
+func (e *validationErrors) merge(err error) {
+	if err != nil {
+		*e = append(*e, err)
+	}
+}
+
+// err returns the collected violations as a single error, or nil when there are none
+func (e validationErrors) err() error {
+	return errors.Join(e...)
+}
+
+`
+	_, err := out.WriteString(helper)
+	return err
+}
@@ -0,0 +1,156 @@
+package jrpc
+
+import "fmt"
+
+// accessVariant describes a model variant generated for definitions that use readOnly or
+// writeOnly properties
+type accessVariant struct {
+	suffix  string // Type name suffix, e.g. "Create"
+	omit    string // Keyword whose properties are left out of the variant
+	purpose string // Doc comment fragment describing the variant
+}
+
+// accessVariants are the variants generated when GeneratorOptions.ReadWriteVariants is set
+var accessVariants = []accessVariant{
+	{suffix: "Create", omit: "readOnly", purpose: "sent in requests, without read-only properties"},
+	{suffix: "Read", omit: "writeOnly", purpose: "returned in responses, without write-only properties"},
+}
+
+// addAccessVariants adds an XCreate and an XRead definition for every definition X that
+// declares readOnly or writeOnly properties, or references such a definition. The variants
+// leave out read-only and write-only properties respectively and reference the matching
+// variants of other definitions.
+func addAccessVariants(definitions map[string]any) {
+	marked := make(map[string]bool)
+	for name, definition := range definitions {
+		if defMap, ok := definition.(map[string]any); ok && hasAccessMarkers(defMap) {
+			marked[name] = true
+		}
+	}
+	if len(marked) == 0 {
+		return
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for name, definition := range definitions {
+			if _, ok := definition.(map[string]any); !ok || marked[name] {
+				continue
+			}
+			refs := make(map[string]bool)
+			collectRefNames(definition, refs)
+			for ref := range refs {
+				if marked[ref] {
+					marked[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(marked))
+	for _, name := range sortedDefinitionNames(definitions) {
+		if marked[name] {
+			names = append(names, name)
+		}
+	}
+
+	for _, variant := range accessVariants {
+		renames := make(map[string]string, len(names))
+		for _, name := range names {
+			renames[name] = uniqueDefinitionName(definitions, name+variant.suffix)
+		}
+
+		for _, name := range names {
+			variantDef := deepCopySchema(definitions[name]).(map[string]any)
+			omitAccessProperties(variantDef, variant.omit)
+			rewriteRefs(variantDef, renames)
+			variantDef["description"] = fmt.Sprintf("%s is the representation of %s %s.", renames[name], name, variant.purpose)
+			delete(variantDef, "x-go-name")
+			definitions[renames[name]] = variantDef
+		}
+	}
+}
+
+// hasAccessMarkers reports whether a definition declares readOnly or writeOnly properties
+func hasAccessMarkers(defMap map[string]any) bool {
+	for _, properties := range propertyMaps(defMap) {
+		for _, propDef := range properties {
+			if propMap, ok := propDef.(map[string]any); ok {
+				if propMap["readOnly"] == true || propMap["writeOnly"] == true {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// omitAccessProperties removes the properties marked with keyword (and their required
+// entries) from a schema and its inline allOf members
+func omitAccessProperties(schema map[string]any, keyword string) {
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		omitted := make(map[string]bool)
+		for propName, propDef := range properties {
+			if propMap, ok := propDef.(map[string]any); ok && propMap[keyword] == true {
+				delete(properties, propName)
+				omitted[propName] = true
+			}
+		}
+
+		if required, ok := schema["required"].([]any); ok && len(omitted) > 0 {
+			kept := make([]any, 0, len(required))
+			for _, field := range required {
+				if name, ok := field.(string); !ok || !omitted[name] {
+					kept = append(kept, field)
+				}
+			}
+			schema["required"] = kept
+		}
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, member := range allOf {
+			if memberMap, ok := member.(map[string]any); ok {
+				omitAccessProperties(memberMap, keyword)
+			}
+		}
+	}
+}
+
+// collectRefNames records the definition names of every $ref inside a schema
+func collectRefNames(node any, refs map[string]bool) {
+	switch value := node.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			refs[refName(ref)] = true
+		}
+		for _, child := range value {
+			collectRefNames(child, refs)
+		}
+	case []any:
+		for _, child := range value {
+			collectRefNames(child, refs)
+		}
+	}
+}
+
+// deepCopySchema returns a copy of a schema that shares no maps or slices with the original
+func deepCopySchema(node any) any {
+	switch value := node.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, child := range value {
+			copied[key] = deepCopySchema(child)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, child := range value {
+			copied[i] = deepCopySchema(child)
+		}
+		return copied
+	}
+	return node
+}
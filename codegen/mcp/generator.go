@@ -0,0 +1,164 @@
+// Package mcp provides a Go code generator for the JSON Schema of the Model Context Protocol
+// (MCP)
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"golang.org/x/tools/imports"
+)
+
+// MCPGenerator implements the Generator interface for the MCP schema
+type MCPGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *MCPGenerator) Name() string {
+	return "mcp"
+}
+
+// Description returns a human-readable description
+func (g *MCPGenerator) Description() string {
+	return "Generates Go types and a typed server scaffold from the MCP (Model Context Protocol) JSON Schema"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *MCPGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// Options for the MCP generator
+type Options struct {
+	// GeneratorOptions are the options of the types, generated by the JSON-RPC generator
+	*jrpc.GeneratorOptions
+}
+
+// Generate processes the MCP schema and generates the types followed by the server scaffold
+func (g *MCPGenerator) Generate(config codegen.GenerateConfig) error {
+	var options *Options
+
+	if config.Options != nil {
+		if opts, ok := config.Options.(*Options); ok {
+			options = opts
+		}
+	}
+
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{
+			IncludeComments: true,
+			FormatOutput:    true,
+		}
+	}
+
+	typesOptions := *options.GeneratorOptions
+	typesOptions.PackageName = config.PackageName
+	if typesOptions.PackageName == "" {
+		typesOptions.PackageName = "mcp"
+	}
+	typesOptions.CustomAcronyms = map[string]bool{"mcp": true}
+	maps.Copy(typesOptions.CustomAcronyms, options.CustomAcronyms)
+
+	document, err := jrpc.Bundle(config.SchemaPath, &typesOptions)
+	if err != nil {
+		return err
+	}
+	protocol := newProtocol(document, &typesOptions)
+
+	// Split mode writes the types into a directory, which the server scaffold joins as a file
+	// of its own
+	if typesOptions.SplitMode != "" {
+		if err := jrpc.GenerateTypes(config.OutputPath, config.SchemaPath, &typesOptions); err != nil {
+			return err
+		}
+		files, err := filepath.Glob(filepath.Join(config.OutputPath, "*.go"))
+		if err != nil {
+			return err
+		}
+		var types []byte
+		for _, file := range files {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read generated file: %w", err)
+			}
+			types = append(types, source...)
+		}
+
+		out := new(bytes.Buffer)
+		fmt.Fprintf(out, "// Code generated from JSON schema. DO NOT EDIT.\n\npackage %s\n\n", typesOptions.PackageName)
+		if !protocol.generate(out, types) {
+			return nil
+		}
+		return writeSource(filepath.Join(config.OutputPath, "server.go"), out.Bytes())
+	}
+
+	schema, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schema, &typesOptions); err != nil {
+		return err
+	}
+	types := bytes.Clone(out.Bytes())
+	if !protocol.generate(out, types) {
+		if err := os.WriteFile(config.OutputPath, out.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+	return writeSource(config.OutputPath, out.Bytes())
+}
+
+// writeSource adds the imports of the server scaffold to source, formats it and writes it to
+// path
+func writeSource(path string, source []byte) error {
+	source, err := imports.Process(path, source, nil)
+	if err != nil {
+		return &codegen.FormatError{Name: "generated code", Err: err}
+	}
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema defines the requests of MCP clients and the
+// initialize handshake
+func (g *MCPGenerator) ValidateSchema(schemaPath string) error {
+	if err := jrpc.ValidateSchema(schemaPath); err != nil {
+		return err
+	}
+	document, err := jrpc.Bundle(schemaPath, nil)
+	if err != nil {
+		return err
+	}
+	definitions := schemaDefinitions(document)
+	for _, name := range requiredDefinitions {
+		if _, ok := definitions[name]; !ok {
+			return fmt.Errorf("not an MCP schema: the %s definition is missing", name)
+		}
+	}
+	return nil
+}
+
+// NewMCPGenerator creates a new instance of the MCP generator
+func NewMCPGenerator() *MCPGenerator {
+	return &MCPGenerator{}
+}
+
+// Register automatically registers the MCP generator with the default registry
+func init() {
+	generator := NewMCPGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register MCP generator: %v", err))
+	}
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+	}{
+		{"server", nil},
+		{"server_validate", &Options{GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, GenerateValidate: true}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "mcp.go")
+			err := NewMCPGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", "mcp.json"),
+				OutputPath:  output,
+				PackageName: "generated",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".go.golden"), source)
+			gentest.Vet(t, map[string]string{"mcp.go": string(source)})
+		})
+	}
+}
+
+func TestGenerateSplit(t *testing.T) {
+	output := t.TempDir()
+	err := NewMCPGenerator().Generate(codegen.GenerateConfig{
+		SchemaPath:  filepath.Join("testdata", "mcp.json"),
+		OutputPath:  output,
+		PackageName: "generated",
+		Options:     &Options{GeneratorOptions: &jrpc.GeneratorOptions{IncludeComments: true, FormatOutput: true, SplitMode: "kind"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := gentest.Files(t, output)
+	for name, source := range files {
+		gentest.Golden(t, filepath.Join("testdata", "split", name+".golden"), []byte(source))
+	}
+	gentest.Vet(t, files)
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"mcp.json", true},
+		{"tools.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewMCPGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
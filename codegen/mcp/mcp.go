@@ -0,0 +1,479 @@
+package mcp
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// requiredDefinitions are the definitions the server scaffold is built on: the union of the
+// requests of clients, and the types of the initialize handshake
+var requiredDefinitions = []string{"ClientRequest", "InitializeRequest", "Implementation", "ClientCapabilities", "ServerCapabilities"}
+
+// builtinMethods are the client requests the Server answers itself rather than a handler
+var builtinMethods = []string{"initialize", "ping"}
+
+// protocol holds the definitions of an MCP schema the server scaffold is generated from
+type protocol struct {
+	definitions map[string]any
+	options     *jrpc.GeneratorOptions
+}
+
+// request is a client request routed to a handler
+type request struct {
+	method     string // JSON-RPC method, e.g. tools/call
+	name       string // Go method of the handler, e.g. CallTool
+	paramsType string // type of the params, empty when the request has none
+	resultType string // type of the result, empty when the request answers an empty result
+}
+
+// handler is a handler interface of the server, answering the requests of a method prefix
+type handler struct {
+	prefix     string // method prefix, e.g. tools
+	typeName   string // interface name, e.g. ToolHandler
+	field      string // Server field holding the handler, e.g. Tools
+	capability string // ServerCapabilities property advertised when it is set, if any
+	requests   []request
+}
+
+// newProtocol returns the protocol of a bundled MCP schema document
+func newProtocol(document map[string]any, options *jrpc.GeneratorOptions) *protocol {
+	return &protocol{definitions: schemaDefinitions(document), options: options}
+}
+
+// schemaDefinitions returns the definitions of a JSON Schema document, under definitions or
+// $defs
+func schemaDefinitions(document map[string]any) map[string]any {
+	for _, key := range []string{"definitions", "$defs"} {
+		if definitions, ok := document[key].(map[string]any); ok {
+			return definitions
+		}
+	}
+	return nil
+}
+
+// typeName returns the Go name of a definition, or an empty string when types does not
+// declare it, e.g. because it was filtered out
+func (p *protocol) typeName(definition string, types []byte) string {
+	defMap, ok := p.definitions[definition].(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := defMap["x-go-name"].(string)
+	if name == "" {
+		name = jrpc.GoIdentifier(definition, p.options)
+	}
+	if !declares(types, name) {
+		return ""
+	}
+	return name
+}
+
+// declares reports whether types declares the type name
+func declares(types []byte, name string) bool {
+	return regexp.MustCompile(`(?m)^type ` + regexp.QuoteMeta(name) + `\b`).Match(types)
+}
+
+// clientRequests returns the definitions of the ClientRequest union, in their order
+func (p *protocol) clientRequests() []string {
+	defMap, _ := p.definitions["ClientRequest"].(map[string]any)
+	var names []string
+	for _, key := range []string{"anyOf", "oneOf"} {
+		variants, _ := defMap[key].([]any)
+		for _, variant := range variants {
+			variantMap, _ := variant.(map[string]any)
+			ref, _ := variantMap["$ref"].(string)
+			if name, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+				names = append(names, name)
+			} else if name, ok := strings.CutPrefix(ref, "#/$defs/"); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// requestMethod returns the const of the method property of a request definition
+func requestMethod(defMap map[string]any) string {
+	properties, _ := defMap["properties"].(map[string]any)
+	methodMap, _ := properties["method"].(map[string]any)
+	method, _ := methodMap["const"].(string)
+	if enum, _ := methodMap["enum"].([]any); method == "" && len(enum) == 1 {
+		method, _ = enum[0].(string)
+	}
+	return method
+}
+
+// handlers groups the client requests by the prefix of their method into handler
+// interfaces, leaving out the requests the Server answers itself and those whose types
+// are not declared
+func (p *protocol) handlers(types []byte) []*handler {
+	capabilities, _ := p.definitions["ServerCapabilities"].(map[string]any)
+	capabilityProperties, _ := capabilities["properties"].(map[string]any)
+
+	var handlers []*handler
+	for _, definition := range p.clientRequests() {
+		defMap, _ := p.definitions[definition].(map[string]any)
+		method := requestMethod(defMap)
+		prefix, _, ok := strings.Cut(method, "/")
+		if !ok || slices.Contains(builtinMethods, method) {
+			continue
+		}
+		typeName := p.typeName(definition, types)
+		if typeName == "" {
+			continue
+		}
+
+		r := request{
+			method:     method,
+			name:       strings.TrimSuffix(typeName, "Request"),
+			resultType: p.typeName(strings.TrimSuffix(definition, "Request")+"Result", types),
+		}
+		properties, _ := defMap["properties"].(map[string]any)
+		if params, ok := properties["params"].(map[string]any); ok {
+			if ref, _ := params["$ref"].(string); ref != "" {
+				r.paramsType = p.typeName(ref[strings.LastIndex(ref, "/")+1:], types)
+			} else if name := typeName + jrpc.GoIdentifier("params", p.options); declares(types, name) {
+				r.paramsType = name
+			}
+		}
+
+		index := slices.IndexFunc(handlers, func(h *handler) bool { return h.prefix == prefix })
+		if index < 0 {
+			h := &handler{
+				prefix:   prefix,
+				typeName: jrpc.GoIdentifier(strings.TrimSuffix(prefix, "s"), p.options) + "Handler",
+				field:    jrpc.GoIdentifier(prefix, p.options),
+			}
+			for _, key := range []string{prefix, prefix + "s"} {
+				if _, ok := capabilityProperties[key]; ok {
+					h.capability = key
+					h.field = jrpc.GoIdentifier(key, p.options)
+					break
+				}
+			}
+			handlers = append(handlers, h)
+			index = len(handlers) - 1
+		}
+		handlers[index].requests = append(handlers[index].requests, r)
+	}
+	return handlers
+}
+
+// generate writes the server scaffold of the schema after types, the generated types, and
+// reports whether it wrote it. Nothing is written when the types of the initialize
+// handshake were filtered out.
+func (p *protocol) generate(out *bytes.Buffer, types []byte) bool {
+	implementation := p.typeName("Implementation", types)
+	clientCapabilities := p.typeName("ClientCapabilities", types)
+	serverCapabilities := p.typeName("ServerCapabilities", types)
+	if implementation == "" || clientCapabilities == "" || serverCapabilities == "" {
+		return false
+	}
+	handlers := p.handlers(types)
+
+	for _, h := range handlers {
+		methods := make([]string, len(h.requests))
+		for i, r := range h.requests {
+			methods[i] = r.method
+		}
+		fmt.Fprintf(out, "// %s answers the %s requests of MCP clients\n", h.typeName, joinAll(methods))
+		fmt.Fprintf(out, "type %s interface {\n", h.typeName)
+		for _, r := range h.requests {
+			params := "ctx context.Context"
+			if r.paramsType != "" {
+				params += ", params *" + r.paramsType
+			}
+			results := "error"
+			if r.resultType != "" {
+				results = "(*" + r.resultType + ", error)"
+			}
+			fmt.Fprintf(out, "\t// %s answers %s\n", r.name, r.method)
+			fmt.Fprintf(out, "\t%s(%s) %s\n", r.name, params, results)
+		}
+		out.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(out, serverTypes, implementation, clientCapabilities, serverCapabilities)
+	out.WriteString("\t// OnInitialize, when set, is called with the outcome of the initialize handshake before\n")
+	out.WriteString("\t// the server answers it; an error rejects the handshake\n")
+	out.WriteString("\tOnInitialize func(ctx context.Context, negotiation *Negotiation) error\n")
+	for _, h := range handlers {
+		out.WriteString("\n")
+		if h.capability != "" {
+			fmt.Fprintf(out, "\t// %s answers the %s/ requests, advertising the %s capability when set\n", h.field, h.prefix, h.capability)
+		} else {
+			fmt.Fprintf(out, "\t// %s answers the %s/ requests\n", h.field, h.prefix)
+		}
+		fmt.Fprintf(out, "\t%s %s\n", h.field, h.typeName)
+	}
+	out.WriteString("}\n\n")
+
+	p.generateCapabilities(out, handlers, serverCapabilities)
+	fmt.Fprintf(out, serverInitialize, clientCapabilities, implementation, serverCapabilities)
+	generateRouting(out, handlers)
+	out.WriteString(serverRuntime)
+	return true
+}
+
+// generateCapabilities writes Server.Capabilities, advertising the capabilities of the
+// handlers set. The capabilities are encoded as JSON first, since the Go types of their
+// properties depend on the schema.
+func (p *protocol) generateCapabilities(out *bytes.Buffer, handlers []*handler, serverCapabilities string) {
+	out.WriteString("// Capabilities returns the capabilities of the handlers set, answered to initialize\n")
+	fmt.Fprintf(out, "func (s *Server) Capabilities() %s {\n", serverCapabilities)
+	out.WriteString("\tcapabilities := map[string]any{}\n")
+	for _, h := range handlers {
+		if h.capability == "" {
+			continue
+		}
+		options := "map[string]any{}"
+		if slices.ContainsFunc(h.requests, func(r request) bool { return r.method == h.prefix+"/subscribe" }) {
+			options = `map[string]any{"subscribe": true}`
+		}
+		fmt.Fprintf(out, "\tif s.%s != nil {\n\t\tcapabilities[%q] = %s\n\t}\n", h.field, h.capability, options)
+	}
+	fmt.Fprintf(out, "\tvar result %s\n", serverCapabilities)
+	out.WriteString("\tif data, err := json.Marshal(capabilities); err == nil {\n\t\t_ = json.Unmarshal(data, &result)\n\t}\n")
+	out.WriteString("\treturn result\n}\n\n")
+}
+
+// generateRouting writes Server.handle, routing each request to the handler of its method
+func generateRouting(out *bytes.Buffer, handlers []*handler) {
+	out.WriteString("// handle answers a request with the result of the handler of its method\n")
+	out.WriteString("func (s *Server) handle(ctx context.Context, method string, params json.RawMessage) (any, error) {\n")
+	out.WriteString("\tswitch method {\n")
+	out.WriteString("\tcase \"initialize\":\n\t\treturn s.initialize(ctx, params)\n")
+	out.WriteString("\tcase \"ping\":\n\t\treturn struct{}{}, nil\n")
+	for _, h := range handlers {
+		for _, r := range h.requests {
+			fmt.Fprintf(out, "\tcase %q:\n", r.method)
+			fmt.Fprintf(out, "\t\tif s.%s == nil {\n\t\t\tbreak\n\t\t}\n", h.field)
+			args := "ctx"
+			if r.paramsType != "" {
+				fmt.Fprintf(out, "\t\tvar request %s\n", r.paramsType)
+				out.WriteString("\t\tif err := decodeMCPParams(params, &request); err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+				args += ", &request"
+			}
+			if r.resultType != "" {
+				fmt.Fprintf(out, "\t\treturn s.%s.%s(%s)\n", h.field, r.name, args)
+			} else {
+				fmt.Fprintf(out, "\t\treturn struct{}{}, s.%s.%s(%s)\n", h.field, r.name, args)
+			}
+		}
+	}
+	out.WriteString("\t}\n")
+	out.WriteString("\treturn nil, &RPCError{Code: CodeMethodNotFound, Message: \"method not found: \" + method}\n}\n\n")
+}
+
+// serverTypes are the JSON-RPC errors of the server, the outcome of the initialize handshake
+// and the head of the Server type. %[1]s, %[2]s and %[3]s are the names of the
+// Implementation, ClientCapabilities and ServerCapabilities types.
+const serverTypes = `// JSON-RPC error codes answered by the Server
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// RPCError is a JSON-RPC error. Handlers return it to answer an error of their own; other
+// errors are answered as internal errors.
+type RPCError struct {
+	Code    int    ` + "`json:\"code\"`" + `
+	Message string ` + "`json:\"message\"`" + `
+	Data    any    ` + "`json:\"data,omitempty\"`" + `
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("MCP error %%d: %%s", e.Code, e.Message)
+}
+
+// Negotiation is the outcome of the initialize handshake: the protocol version agreed on,
+// the client and the capabilities of both sides
+type Negotiation struct {
+	ProtocolVersion    string
+	ClientInfo         %[1]s
+	ClientCapabilities %[2]s
+	ServerCapabilities %[3]s
+}
+
+// Server answers the requests of MCP clients, negotiating the capabilities of its handlers
+// and routing each request to the handler of its method. It serves the stdio transport with
+// Serve and the Streamable HTTP transport, without streaming, as an http.Handler.
+type Server struct {
+	// Info is the name and version of the server, answered to initialize
+	Info %[1]s
+
+	// Instructions tell clients how to use the server, answered to initialize when set
+	Instructions string
+
+	// ProtocolVersion is the protocol version answered to initialize (default: the version
+	// requested by the client)
+	ProtocolVersion string
+
+`
+
+// serverInitialize is the initialize handshake of the Server. %[1]s, %[2]s and %[3]s are
+// the names of the ClientCapabilities, Implementation and ServerCapabilities types.
+const serverInitialize = `// initialize answers the initialize handshake with the server capabilities
+func (s *Server) initialize(ctx context.Context, data json.RawMessage) (any, error) {
+	var params struct {
+		ProtocolVersion string ` + "`json:\"protocolVersion\"`" + `
+		Capabilities    %[1]s ` + "`json:\"capabilities\"`" + `
+		ClientInfo      %[2]s ` + "`json:\"clientInfo\"`" + `
+	}
+	if err := decodeMCPParams(data, &params); err != nil {
+		return nil, err
+	}
+	negotiation := &Negotiation{
+		ProtocolVersion:    s.ProtocolVersion,
+		ClientInfo:         params.ClientInfo,
+		ClientCapabilities: params.Capabilities,
+		ServerCapabilities: s.Capabilities(),
+	}
+	if negotiation.ProtocolVersion == "" {
+		negotiation.ProtocolVersion = params.ProtocolVersion
+	}
+	if s.OnInitialize != nil {
+		if err := s.OnInitialize(ctx, negotiation); err != nil {
+			return nil, err
+		}
+	}
+	return struct {
+		ProtocolVersion string ` + "`json:\"protocolVersion\"`" + `
+		Capabilities    %[3]s ` + "`json:\"capabilities\"`" + `
+		ServerInfo      %[2]s ` + "`json:\"serverInfo\"`" + `
+		Instructions    string ` + "`json:\"instructions,omitempty\"`" + `
+	}{negotiation.ProtocolVersion, negotiation.ServerCapabilities, s.Info, s.Instructions}, nil
+}
+
+`
+
+// serverRuntime decodes the JSON-RPC messages of the Server, encodes its responses and
+// serves its transports
+const serverRuntime = `// HandleMessage answers a JSON-RPC message of an MCP client, returning the encoded response,
+// or nil for notifications
+func (s *Server) HandleMessage(ctx context.Context, data []byte) []byte {
+	var request struct {
+		JSONRPC string          ` + "`json:\"jsonrpc\"`" + `
+		ID      json.RawMessage ` + "`json:\"id,omitempty\"`" + `
+		Method  string          ` + "`json:\"method\"`" + `
+		Params  json.RawMessage ` + "`json:\"params,omitempty\"`" + `
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return encodeMCPResponse(nil, nil, &RPCError{Code: CodeParseError, Message: err.Error()})
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return encodeMCPResponse(request.ID, nil, &RPCError{Code: CodeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+	}
+	result, err := s.handle(ctx, request.Method, request.Params)
+	if request.ID == nil {
+		return nil
+	}
+	return encodeMCPResponse(request.ID, result, err)
+}
+
+// Serve answers the newline-delimited JSON-RPC messages read from in, as on the stdio
+// transport, writing the responses to out until in ends or ctx is done
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if response := s.HandleMessage(ctx, line); response != nil {
+			if _, err := out.Write(append(response, '\n')); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeHTTP answers a JSON-RPC message POSTed by an MCP client with a JSON response, and
+// notifications with 202 Accepted
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	response := s.HandleMessage(r.Context(), data)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(response)
+}
+
+// decodeMCPParams decodes the params of a request into params, leaving it empty when they
+// are missing
+func decodeMCPParams(data json.RawMessage, params any) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, params); err != nil {
+		return &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// encodeMCPResponse encodes the response to the request id, answering err as a JSON-RPC
+// error when it is not nil
+func encodeMCPResponse(id json.RawMessage, result any, err error) []byte {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	response := struct {
+		JSONRPC string          ` + "`json:\"jsonrpc\"`" + `
+		ID      json.RawMessage ` + "`json:\"id\"`" + `
+		Result  any             ` + "`json:\"result,omitempty\"`" + `
+		Error   *RPCError       ` + "`json:\"error,omitempty\"`" + `
+	}{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		if !errors.As(err, &response.Error) {
+			response.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		}
+	} else if result == nil {
+		response.Result = struct{}{}
+	} else {
+		response.Result = result
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		data, _ = json.Marshal(struct {
+			JSONRPC string          ` + "`json:\"jsonrpc\"`" + `
+			ID      json.RawMessage ` + "`json:\"id\"`" + `
+			Error   *RPCError       ` + "`json:\"error\"`" + `
+		}{"2.0", id, &RPCError{Code: CodeInternalError, Message: err.Error()}})
+	}
+	return data
+}
+
+`
+
+// joinAll joins values as an English list ("a, b and c")
+func joinAll(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " and " + values[len(values)-1]
+}
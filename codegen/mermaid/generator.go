@@ -0,0 +1,117 @@
+// Package mermaid provides a generator of Mermaid class and entity relationship diagrams of
+// the definitions of the same schemas as the Go types and of their $ref relationships
+package mermaid
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Diagrams the generator writes
+const (
+	// DiagramClass writes a classDiagram of the objects, enums and unions
+	DiagramClass = "class"
+	// DiagramER writes an erDiagram of the objects
+	DiagramER = "er"
+)
+
+// MermaidGenerator implements the Generator interface for Mermaid diagrams
+type MermaidGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *MermaidGenerator) Name() string {
+	return "mermaid"
+}
+
+// Description returns a human-readable description
+func (g *MermaidGenerator) Description() string {
+	return "Generates Mermaid class or ER diagrams of the definitions of JSON Schema, OpenAPI and OpenRPC documents and their relationships"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *MermaidGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes: Mermaid files, or Markdown
+// files with a mermaid code block
+func (g *MermaidGenerator) OutputFormats() []string {
+	return []string{".mmd", ".mermaid", ".md"}
+}
+
+// Options for the Mermaid generator
+type Options struct {
+	// GeneratorOptions select and name the definitions as for the Go types: IncludeTypes,
+	// ExcludeTypes, CustomAcronyms, Naming, PreserveOrder, ReadWriteVariants and the remote
+	// refs options. The options of the Go code do not apply.
+	*jrpc.GeneratorOptions
+
+	// Diagram is the diagram written: DiagramClass (default) or DiagramER
+	Diagram string
+}
+
+// Generate writes the diagram of the definitions of the schema, in a mermaid code block for
+// Markdown output files
+func (g *MermaidGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{}
+	}
+	switch options.Diagram {
+	case "":
+		options.Diagram = DiagramClass
+	case DiagramClass, DiagramER:
+	default:
+		return fmt.Errorf("unknown diagram %q: must be %s or %s", options.Diagram, DiagramClass, DiagramER)
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported by the mermaid generator", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	d := newDiagram(definitions, options)
+	var source string
+	if options.Diagram == DiagramER {
+		source = d.entityRelationship()
+	} else {
+		source = d.class()
+	}
+	if strings.EqualFold(filepath.Ext(config.OutputPath), ".md") {
+		source = "```mermaid\n" + source + "```\n"
+	}
+
+	if err := os.WriteFile(config.OutputPath, []byte(source), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema declares definitions
+func (g *MermaidGenerator) ValidateSchema(schemaPath string) error {
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// NewMermaidGenerator creates a new instance of the Mermaid generator
+func NewMermaidGenerator() *MermaidGenerator {
+	return &MermaidGenerator{}
+}
+
+// Register automatically registers the Mermaid generator with the default registry
+func init() {
+	generator := NewMermaidGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register mermaid generator: %v", err))
+	}
+}
@@ -0,0 +1,510 @@
+package mermaid
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Kinds of the definitions
+const (
+	kindObject = "object" // classes and entities
+	kindEnum   = "enum"   // enumeration classes, and attribute types of entities
+	kindUnion  = "union"  // union classes, and the entities of their members
+	kindAlias  = "alias"  // inlined where they are used
+)
+
+// invalidERChars matches the characters the attribute names and types of ER diagrams may not
+// contain
+var invalidERChars = regexp.MustCompile(`[^A-Za-z0-9_\-\[\]]`)
+
+// invalidMemberChars matches the characters Mermaid reads as method parentheses, class bodies
+// or generics in the members of classes
+var invalidMemberChars = regexp.MustCompile(`[(){}~]`)
+
+// diagram holds the definitions drawn and their names
+type diagram struct {
+	definitions map[string]any
+	options     *Options
+	order       []string          // definitions in alphabetical order
+	names       map[string]string // names of the definitions, as their Go types
+	kinds       map[string]string
+}
+
+// target is a definition a schema refers to, and whether it refers to many of them
+type target struct {
+	definition string
+	many       bool
+}
+
+// newDiagram names and classifies the definitions
+func newDiagram(definitions map[string]any, options *Options) *diagram {
+	d := &diagram{
+		definitions: definitions,
+		options:     options,
+		names:       map[string]string{},
+		kinds:       map[string]string{},
+	}
+	for name := range definitions {
+		d.order = append(d.order, name)
+	}
+	sort.Strings(d.order)
+	for _, name := range d.order {
+		schema, _ := definitions[name].(map[string]any)
+		d.names[name] = jrpc.GoIdentifier(name, options.GeneratorOptions)
+		d.kinds[name] = kindOf(schema)
+	}
+	return d
+}
+
+// class returns the classDiagram of the definitions: a class per object, enum and union, the
+// inheritance of allOf members, the realization of unions by their members, and an
+// association per property referring to other classes
+func (d *diagram) class() string {
+	var b strings.Builder
+	b.WriteString("classDiagram\n")
+	var edges []string
+	for _, name := range d.order {
+		schema, _ := d.definitions[name].(map[string]any)
+		class := d.names[name]
+		switch d.kinds[name] {
+		case kindObject:
+			fmt.Fprintf(&b, "  class %s {\n", class)
+			for _, p := range d.properties(schema, false) {
+				fmt.Fprintf(&b, "    +%s %s\n", d.label(p.schema, false), memberText(p.name))
+				for _, t := range d.targets(p.schema, false, map[string]bool{}) {
+					edges = append(edges, association(class, d.names[t.definition], t.many, p.name))
+				}
+			}
+			b.WriteString("  }\n")
+			allOf, _ := schema["allOf"].([]any)
+			for _, member := range allOf {
+				member, _ := member.(map[string]any)
+				if ref, ok := member["$ref"].(string); ok && d.kinds[refName(ref)] == kindObject {
+					edges = append(edges, fmt.Sprintf("%s <|-- %s", d.names[refName(ref)], class))
+				}
+			}
+		case kindEnum:
+			fmt.Fprintf(&b, "  class %s {\n    <<enumeration>>\n", class)
+			for _, value := range schema["enum"].([]any) {
+				if value, ok := value.(string); ok && strings.TrimSpace(value) != "" {
+					fmt.Fprintf(&b, "    %s\n", memberText(value))
+				}
+			}
+			b.WriteString("  }\n")
+		case kindUnion:
+			fmt.Fprintf(&b, "  class %s {\n    <<union>>\n  }\n", class)
+			for _, t := range d.targets(schema, false, map[string]bool{name: true}) {
+				edges = append(edges, fmt.Sprintf("%s <|.. %s", class, d.names[t.definition]))
+			}
+		}
+	}
+	writeEdges(&b, edges)
+	return b.String()
+}
+
+// entityRelationship returns the erDiagram of the definitions: an entity per object, with an
+// attribute per property, and a relationship per property referring to other entities, with
+// the cardinality of the property
+func (d *diagram) entityRelationship() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	var edges []string
+	for _, name := range d.order {
+		if d.kinds[name] != kindObject {
+			continue
+		}
+		schema, _ := d.definitions[name].(map[string]any)
+		entity := d.names[name]
+		properties := d.properties(schema, true)
+		if len(properties) == 0 {
+			fmt.Fprintf(&b, "  %s\n", entity)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s {\n", entity)
+		for _, p := range properties {
+			attribute := fmt.Sprintf("    %s %s", erText(d.label(p.schema, true)), erText(p.name))
+			if p.name == "id" {
+				attribute += " PK"
+			}
+			b.WriteString(attribute + "\n")
+			for _, t := range d.targets(p.schema, true, map[string]bool{}) {
+				cardinality := "o|"
+				switch {
+				case t.many && minItems(p.schema) > 0:
+					cardinality = "|{"
+				case t.many:
+					cardinality = "o{"
+				case p.required && !isNullable(p.schema):
+					cardinality = "||"
+				}
+				edges = append(edges, fmt.Sprintf("%s }o--%s %s : %q", entity, cardinality, d.names[t.definition], p.name))
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	writeEdges(&b, edges)
+	return b.String()
+}
+
+// property is a property of an object
+type property struct {
+	name     string
+	schema   map[string]any
+	required bool
+}
+
+// properties returns the properties of an object: its own, those of its inline allOf members
+// and, when inherited is set, those of the definitions of its allOf
+func (d *diagram) properties(schema map[string]any, inherited bool) []property {
+	var properties []property
+	seen := map[string]bool{}
+	var collect func(schema map[string]any, depth int)
+	collect = func(schema map[string]any, depth int) {
+		if schema == nil || depth > 16 {
+			return
+		}
+		allOf, _ := schema["allOf"].([]any)
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); !ok {
+				collect(member, depth+1)
+			} else if inherited {
+				target, _ := d.definitions[refName(ref)].(map[string]any)
+				collect(target, depth+1)
+			}
+		}
+		required := stringList(schema["required"])
+		all, _ := schema["properties"].(map[string]any)
+		for _, name := range jrpc.PropertyNames(schema) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			propertySchema, _ := all[name].(map[string]any)
+			properties = append(properties, property{name, propertySchema, slices.Contains(required, name)})
+		}
+	}
+	collect(schema, 0)
+	return properties
+}
+
+// targets returns the definitions drawn that a schema refers to, through its items, values,
+// unions and aliases: classes, or the entities of an ER diagram (objects, and the members of
+// unions)
+func (d *diagram) targets(schema map[string]any, entities bool, visiting map[string]bool) []target {
+	if schema == nil {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		kind, ok := d.kinds[name]
+		switch {
+		case !ok || visiting[name]:
+			return nil
+		case kind == kindObject, kind != kindAlias && !entities:
+			return []target{{definition: name}}
+		case kind == kindEnum:
+			return nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		target, _ := d.definitions[name].(map[string]any)
+		return d.targets(target, entities, visiting)
+	}
+
+	var targets []target
+	add := func(found []target, many bool) {
+		for _, t := range found {
+			t.many = t.many || many
+			if !slices.Contains(targets, t) {
+				targets = append(targets, t)
+			}
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			member, _ := member.(map[string]any)
+			add(d.targets(member, entities, visiting), false)
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		add(d.targets(items, entities, visiting), true)
+	}
+	if items, ok := tupleItems(schema); ok {
+		for _, item := range items {
+			item, _ := item.(map[string]any)
+			add(d.targets(item, entities, visiting), true)
+		}
+	}
+	if values, ok := schema["additionalProperties"].(map[string]any); ok {
+		add(d.targets(values, entities, visiting), true)
+	}
+	return targets
+}
+
+// label returns the type of a property: the name of the definition it refers to, its format
+// or type, with [] for arrays. ER diagrams, whose types are restricted, label maps and unions
+// map and oneOf.
+func (d *diagram) label(schema map[string]any, er bool) string {
+	return d.labelOf(schema, er, map[string]bool{})
+}
+
+// labelOf returns the label of a schema, inlining the aliases not in visiting
+func (d *diagram) labelOf(schema map[string]any, er bool, visiting map[string]bool) string {
+	if schema == nil {
+		return "any"
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		if d.kinds[name] != kindAlias || visiting[name] {
+			if _, ok := d.names[name]; ok {
+				return d.names[name]
+			}
+			return "any"
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		target, _ := d.definitions[name].(map[string]any)
+		return d.labelOf(target, er, visiting)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok {
+			var labels []string
+			for _, member := range members {
+				member, _ := member.(map[string]any)
+				if member["type"] == "null" {
+					continue
+				}
+				if label := d.labelOf(member, er, visiting); !slices.Contains(labels, label) {
+					labels = append(labels, label)
+				}
+			}
+			if len(labels) == 1 {
+				return labels[0]
+			}
+			if er {
+				return "oneOf"
+			}
+			return strings.Join(labels, " | ")
+		}
+	}
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) == 1 {
+		member, _ := allOf[0].(map[string]any)
+		return d.labelOf(member, er, visiting)
+	}
+
+	types := schemaTypes(schema)
+	if kind, ok := constKind(schema); ok && len(types) == 0 {
+		types = []string{kind}
+	}
+	if len(types) != 1 {
+		return "any"
+	}
+	switch types[0] {
+	case "string", "integer", "number":
+		if format, ok := schema["format"].(string); ok && format != "" {
+			return format
+		}
+		return types[0]
+	case "array":
+		if _, ok := tupleItems(schema); ok {
+			return "tuple"
+		}
+		items, _ := schema["items"].(map[string]any)
+		return d.labelOf(items, er, visiting) + "[]"
+	case "object":
+		values, ok := schema["additionalProperties"].(map[string]any)
+		if !ok || len(values) == 0 {
+			return "object"
+		}
+		if er {
+			return "map"
+		}
+		return "Map~" + d.labelOf(values, er, visiting) + "~"
+	}
+	return types[0]
+}
+
+// association returns the association of a class with another through a property
+func association(class, other string, many bool, property string) string {
+	if many {
+		return fmt.Sprintf("%s --> \"*\" %s : %s", class, other, memberText(property))
+	}
+	return fmt.Sprintf("%s --> %s : %s", class, other, memberText(property))
+}
+
+// writeEdges writes the edges of a diagram once each, in their order
+func writeEdges(b *strings.Builder, edges []string) {
+	seen := map[string]bool{}
+	for _, edge := range edges {
+		if !seen[edge] {
+			seen[edge] = true
+			fmt.Fprintf(b, "  %s\n", edge)
+		}
+	}
+}
+
+// kindOf returns the kind of a definition
+func kindOf(schema map[string]any) string {
+	if isStringEnum(schema) {
+		return kindEnum
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		refs := 0
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["$ref"] != nil {
+				refs++
+			}
+		}
+		if refs > 1 {
+			return kindUnion
+		}
+	}
+	if schema["oneOf"] != nil || schema["anyOf"] != nil {
+		return kindAlias
+	}
+	if allOf, ok := schema["allOf"].([]any); ok && (len(allOf) > 1 || schema["properties"] != nil) {
+		return kindObject
+	}
+	if _, ok := schema["properties"]; ok && len(schemaTypes(schema)) <= 1 {
+		return kindObject
+	}
+	return kindAlias
+}
+
+// memberText returns text without the characters Mermaid interprets in the members of classes
+func memberText(text string) string {
+	return invalidMemberChars.ReplaceAllString(text, "_")
+}
+
+// erText returns text with the characters ER diagrams do not accept in attributes replaced
+// by underscores
+func erText(text string) string {
+	text = invalidERChars.ReplaceAllString(text, "_")
+	if text == "" || !(text[0] == '_' || text[0] >= 'A' && text[0] <= 'Z' || text[0] >= 'a' && text[0] <= 'z') {
+		text = "_" + text
+	}
+	return text
+}
+
+// minItems returns the minItems of an array schema
+func minItems(schema map[string]any) float64 {
+	switch value := schema["minItems"].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	}
+	return 0
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// constKind returns the JSON Schema type of the const of a schema, for consts without a type
+func constKind(schema map[string]any) (string, bool) {
+	switch value := schema["const"].(type) {
+	case string:
+		return "string", true
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer", true
+		}
+		return "number", true
+	case bool:
+		return "boolean", true
+	case map[string]any:
+		return "object", true
+	case []any:
+		return "array", true
+	}
+	return "", false
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// isStringEnum reports whether a schema is an enum of strings
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tupleItems returns the positional items of a tuple schema: 2020-12 prefixItems or an
+// items array
+func tupleItems(schema map[string]any) ([]any, bool) {
+	if items, ok := schema["prefixItems"].([]any); ok {
+		return items, true
+	}
+	items, ok := schema["items"].([]any)
+	return items, ok
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
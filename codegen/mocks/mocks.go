@@ -0,0 +1,360 @@
+// Package mocks writes hand-rolled mocks of the interfaces of generated Go code (server
+// interfaces, JSON-RPC transports, handlers, publishers and subscribers), so that tests of the
+// code using them need no separate mocking step
+package mocks
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+)
+
+// versionSuffix matches the major version element ending module paths, which is not the
+// package name
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// reservedNames are the identifiers of the mock methods' bodies, which parameters must not
+// shadow
+var reservedNames = map[string]bool{"mock": true, "errors": true}
+
+// file is a parsed source file of the package
+type file struct {
+	fset *token.FileSet
+	ast  *ast.File
+}
+
+// method is a method of an interface, with the names given to its parameters and results
+type method struct {
+	name     string
+	params   []param
+	results  []param
+	variadic bool
+}
+
+// param is a parameter or result of a method
+type param struct {
+	name string
+	typ  string
+}
+
+// mock is an interface to write the mock of
+type mock struct {
+	name    string
+	methods []method
+}
+
+// Generate returns a Go file of the package of sources, the files of a generated package,
+// declaring for every exported interface of only exported methods (not the sealed marker
+// interfaces of unions) a MockX struct implementing it. Every method of the mock counts its
+// call and calls the function field of its name with a Func suffix, or returns zero values,
+// and an error for methods returning one, when the field is nil. Generate returns nil when
+// the sources declare no such interface.
+func Generate(sources ...[]byte) ([]byte, error) {
+	var files []file
+	declared := map[string]bool{}
+	imports := map[string]string{} // Import specs, by package name
+	packageName := ""
+	for _, source := range sources {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", source, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse generated code: %w", err)
+		}
+		if packageName == "" {
+			packageName = f.Name.Name
+		} else if f.Name.Name != packageName {
+			return nil, fmt.Errorf("generated code declares packages %s and %s", packageName, f.Name.Name)
+		}
+		for _, spec := range f.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := importName(importPath)
+			specText := spec.Path.Value
+			if spec.Name != nil {
+				name = spec.Name.Name
+				specText = name + " " + specText
+			}
+			imports[name] = specText
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					declared[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						declared[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							declared[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+		files = append(files, file{fset: fset, ast: f})
+	}
+
+	var mocks []mock
+	used := map[string]bool{"sync": true}
+	for _, f := range files {
+		for _, decl := range f.ast.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				iface, ok := spec.Type.(*ast.InterfaceType)
+				if !ok || !spec.Name.IsExported() || spec.TypeParams != nil || declared["Mock"+spec.Name.Name] {
+					continue
+				}
+				if m, ok := newMock(f, spec.Name.Name, iface, used); ok {
+					mocks = append(mocks, m)
+				}
+			}
+		}
+	}
+	if len(mocks) == 0 {
+		return nil, nil
+	}
+	sort.Slice(mocks, func(i, j int) bool { return mocks[i].name < mocks[j].name })
+	for _, m := range mocks {
+		for _, meth := range m.methods {
+			if returnsError(meth) {
+				used["errors"] = true
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated from JSON schema. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", packageName)
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec, ok := imports[name]
+		if !ok {
+			spec = strconv.Quote(name)
+		}
+		fmt.Fprintf(&b, "\t%s\n", spec)
+	}
+	b.WriteString(")\n")
+	for _, m := range mocks {
+		m.write(&b)
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, &codegen.FormatError{Name: "mocks", Err: err}
+	}
+	return source, nil
+}
+
+// newMock returns the mock of an interface, unless it embeds other interfaces, has unexported
+// methods or methods the mock could not declare next to its function fields, adding the
+// packages its methods refer to to used
+func newMock(f file, name string, iface *ast.InterfaceType, used map[string]bool) (mock, bool) {
+	m := mock{name: name}
+	methods := map[string]bool{}
+	packages := map[string]bool{}
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 || !field.Names[0].IsExported() {
+			return mock{}, false
+		}
+		meth := method{name: field.Names[0].Name}
+		taken := map[string]bool{}
+		var results []param
+		if fn.Results != nil {
+			for i, typ := range expand(fn.Results) {
+				results = append(results, param{name: fmt.Sprintf("r%d", i), typ: f.text(typ, packages)})
+				taken[fmt.Sprintf("r%d", i)] = true
+			}
+		}
+		for i, field := range fn.Params.List {
+			names := field.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{nil}
+			}
+			typ := field.Type
+			if ellipsis, ok := typ.(*ast.Ellipsis); ok && i == len(fn.Params.List)-1 {
+				meth.variadic = true
+				typ = ellipsis.Elt
+			}
+			for _, ident := range names {
+				paramName := fmt.Sprintf("arg%d", len(meth.params))
+				if ident != nil && ident.Name != "_" && !reservedNames[ident.Name] && !taken[ident.Name] {
+					paramName = ident.Name
+				}
+				taken[paramName] = true
+				meth.params = append(meth.params, param{name: paramName, typ: f.text(typ, packages)})
+			}
+		}
+		meth.results = results
+		methods[meth.name] = true
+		m.methods = append(m.methods, meth)
+	}
+	if len(m.methods) == 0 || methods["Calls"] {
+		return mock{}, false
+	}
+	for _, meth := range m.methods {
+		if methods[meth.name+"Func"] {
+			return mock{}, false
+		}
+	}
+	for pkg := range packages {
+		used[pkg] = true
+	}
+	return m, true
+}
+
+// expand returns the type of every parameter of a list, repeating the types shared by
+// several names
+func expand(list *ast.FieldList) []ast.Expr {
+	var types []ast.Expr
+	for _, field := range list.List {
+		for range max(len(field.Names), 1) {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// text returns the source of a type, adding the packages it refers to to packages
+func (f file) text(typ ast.Expr, packages map[string]bool) string {
+	ast.Inspect(typ, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				packages[pkg.Name] = true
+			}
+		}
+		return true
+	})
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, f.fset, typ)
+	return b.String()
+}
+
+// importName returns the name of the package imported from a path without a name
+func importName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && versionSuffix.MatchString(name) {
+		name = elements[len(elements)-2]
+	}
+	name, _, _ = strings.Cut(path.Base(name), ".")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// returnsError reports whether the last result of a method is an error
+func returnsError(meth method) bool {
+	return len(meth.results) > 0 && meth.results[len(meth.results)-1].typ == "error"
+}
+
+// signature returns the parameters and results of a method
+func (meth method) signature(named bool) string {
+	params := make([]string, len(meth.params))
+	for i, p := range meth.params {
+		typ := p.typ
+		if meth.variadic && i == len(meth.params)-1 {
+			typ = "..." + typ
+		}
+		params[i] = p.name + " " + typ
+	}
+	results := make([]string, len(meth.results))
+	for i, r := range meth.results {
+		results[i] = r.typ
+		if named {
+			results[i] = r.name + " " + r.typ
+		}
+	}
+	s := "(" + strings.Join(params, ", ") + ")"
+	switch {
+	case len(results) == 1 && !named:
+		s += " " + results[0]
+	case len(results) > 0:
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return s
+}
+
+// write writes the mock struct, its methods and the assertion that it implements the
+// interface
+func (m mock) write(b *bytes.Buffer) {
+	name := "Mock" + m.name
+	b.WriteString("\n")
+	b.WriteString(comment(fmt.Sprintf("%s is a mock of %s: its methods call the function fields of their name with a Func suffix, or return zero values (and an error, for the methods returning one) when they are nil. Calls returns the number of calls of a method.", name, m.name)))
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, meth := range m.methods {
+		fmt.Fprintf(b, "\t// %sFunc implements %s\n", meth.name, meth.name)
+		fmt.Fprintf(b, "\t%sFunc func%s\n\n", meth.name, meth.signature(false))
+	}
+	b.WriteString("\tmu    sync.Mutex\n\tcalls map[string]int\n}\n\n")
+	fmt.Fprintf(b, "var _ %s = (*%s)(nil)\n", m.name, name)
+
+	for _, meth := range m.methods {
+		args := make([]string, len(meth.params))
+		for i, p := range meth.params {
+			args[i] = p.name
+		}
+		call := fmt.Sprintf("mock.%sFunc(%s", meth.name, strings.Join(args, ", "))
+		if meth.variadic {
+			call += "..."
+		}
+		call += ")"
+
+		fmt.Fprintf(b, "\n// %s calls %sFunc\n", meth.name, meth.name)
+		fmt.Fprintf(b, "func (mock *%s) %s%s {\n", name, meth.name, meth.signature(true))
+		fmt.Fprintf(b, "\tmock.called(%q)\n", meth.name)
+		fmt.Fprintf(b, "\tif mock.%sFunc == nil {\n", meth.name)
+		if returnsError(meth) {
+			fmt.Fprintf(b, "\t\t%s = errors.New(\"%s.%s is not implemented\")\n", meth.results[len(meth.results)-1].name, name, meth.name)
+		}
+		b.WriteString("\t\treturn\n\t}\n")
+		if len(meth.results) > 0 {
+			fmt.Fprintf(b, "\treturn %s\n}\n", call)
+		} else {
+			fmt.Fprintf(b, "\t%s\n}\n", call)
+		}
+	}
+
+	fmt.Fprintf(b, "\n// Calls returns the number of calls of a method of the mock\n")
+	fmt.Fprintf(b, "func (mock *%s) Calls(method string) int {\n", name)
+	b.WriteString("\tmock.mu.Lock()\n\tdefer mock.mu.Unlock()\n\treturn mock.calls[method]\n}\n")
+	fmt.Fprintf(b, "\nfunc (mock *%s) called(method string) {\n", name)
+	b.WriteString("\tmock.mu.Lock()\n\tdefer mock.mu.Unlock()\n")
+	b.WriteString("\tif mock.calls == nil {\n\t\tmock.calls = map[string]int{}\n\t}\n")
+	b.WriteString("\tmock.calls[method]++\n}\n")
+}
+
+// comment returns text as a comment wrapped at 92 columns
+func comment(text string) string {
+	var b strings.Builder
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line) > 2 && len(line)+1+len(word) > 92 {
+			b.WriteString(line + "\n")
+			line = "//"
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
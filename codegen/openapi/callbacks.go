@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// generateCallbacks writes both sides of the callbacks the operations declare: the
+// CallbackInterface implemented by the receiver of the callbacks with the
+// CallbackInterfaceWrapper decoding their requests, and for the API implementation
+// New<Callback>Request and Send<Callback> sending them to the URL their runtime expression
+// evaluates to. Callback URLs are chosen by API clients, so the wrapper methods are not routed.
+func generateCallbacks(out *bytes.Buffer, operations []*operation, options *Options) {
+	callbacks := callbackOperations(operations)
+	if len(callbacks) == 0 {
+		return
+	}
+
+	out.WriteString("// CallbackInterface is implemented by the handlers of the callbacks the API sends back to\n")
+	out.WriteString("// the URLs given to the operations declaring them. Parameters and JSON request bodies are\n")
+	out.WriteString("// decoded before the handlers are called.\n")
+	out.WriteString("type CallbackInterface interface {\n")
+	for _, cb := range callbacks {
+		out.WriteString(operationComment(cb, "\t", "handles", options))
+		fmt.Fprintf(out, "\t%s(%s)\n", cb.name, handlerSignature(cb))
+	}
+	out.WriteString("}\n\n")
+
+	out.WriteString(`// CallbackInterfaceWrapper decodes the parameters and JSON bodies of callback requests and
+// calls the matching CallbackInterface method. Its methods are http.HandlerFuncs, to be served
+// at the callback URLs given to the API.
+type CallbackInterfaceWrapper struct {
+	Handler          CallbackInterface
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error) // Answers requests that cannot be decoded (default: 400 Bad Request)
+}
+
+// handleError answers a callback request that cannot be decoded
+func (ciw *CallbackInterfaceWrapper) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ciw.ErrorHandlerFunc != nil {
+		ciw.ErrorHandlerFunc(w, r, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+`)
+	for _, cb := range callbacks {
+		fmt.Fprintf(out, "// %s decodes a request of the %s callback of %s and calls Handler.%s\n", cb.name, cb.Method, cb.callbackOf, cb.name)
+		fmt.Fprintf(out, "func (ciw *CallbackInterfaceWrapper) %s(w http.ResponseWriter, r *http.Request) {\n", cb.name)
+		if len(cb.params) > 0 {
+			fmt.Fprintf(out, "\tparams, err := Decode%s(r)\n", cb.paramsType())
+			out.WriteString("\tif err != nil {\n\t\tciw.handleError(w, r, err)\n\t\treturn\n\t}\n")
+		}
+		if cb.body != nil {
+			writeBodyDecoding(out, cb.body, "ciw")
+		}
+		fmt.Fprintf(out, "\tciw.Handler.%s(%s)\n}\n\n", cb.name, handlerArguments(cb))
+	}
+
+	for _, cb := range callbacks {
+		generateRequestBuilder(out, cb)
+
+		signature := strings.Replace(clientSignature(cb), "ctx context.Context", "ctx context.Context, client *http.Client, callbackURL string", 1)
+		fmt.Fprintf(out, "// Send%s sends the %s callback of %s to callbackURL with client, or with\n", cb.name, cb.Method, cb.callbackOf)
+		out.WriteString("// http.DefaultClient when client is nil\n")
+		fmt.Fprintf(out, "func Send%s(%s) (*http.Response, error) {\n", cb.name, signature)
+		fmt.Fprintf(out, "\treq, err := New%sRequest(%s)\n", cb.name, strings.Replace(requestArguments(cb), "c.Server", "callbackURL", 1))
+		out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		out.WriteString("\tif client == nil {\n\t\tclient = http.DefaultClient\n\t}\n")
+		out.WriteString("\treturn client.Do(req.WithContext(ctx))\n}\n\n")
+	}
+}
@@ -0,0 +1,687 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// generateClient writes the client side of the API: the Client with one method per operation,
+// New<Op>Request building the request of each operation, and a ClientOption per security
+// scheme of the document applying its credentials through a RequestEditorFn
+func generateClient(out *bytes.Buffer, document *Document, operations []*operation, options *Options) {
+	out.WriteString(clientHelpers)
+	for _, op := range operations {
+		generateRequestBuilder(out, op)
+	}
+	for _, op := range operations {
+		out.WriteString(operationComment(op, "", "sends", options))
+		fmt.Fprintf(out, "func (c *Client) %s(%s, options ...CallOption) (*http.Response, error) {\n", op.name, clientSignature(op))
+		fmt.Fprintf(out, "\treq, err := New%sRequest(%s)\n", op.name, requestArguments(op))
+		out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		security := "nil"
+		if schemes := operationSchemes(document, op); len(schemes) > 0 {
+			security = "[]string{" + quotedList(schemes) + "}"
+		}
+		if len(op.errors) == 0 {
+			fmt.Fprintf(out, "\treturn c.do(ctx, req, %s, options)\n}\n\n", security)
+		} else {
+			fmt.Fprintf(out, "\tresp, err := c.do(ctx, req, %s, options)\n", security)
+			out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+			fmt.Fprintf(out, "\tif err := decode%sError(resp); err != nil {\n\t\treturn nil, err\n\t}\n", op.name)
+			out.WriteString("\treturn resp, nil\n}\n\n")
+		}
+
+		if op.stream != nil {
+			generateStreamMethod(out, op, security)
+		}
+		if op.pager != nil {
+			generatePager(out, op, security)
+		}
+	}
+	generateErrorTypes(out, operations)
+	generateSecurityOptions(out, document)
+	generateStreamHelpers(out, operations)
+	generatePagerHelpers(out, operations)
+}
+
+// generateErrorTypes writes the error types of the error responses of the operations, which
+// all implement APIError, and the decode<Op>Error functions returning them. The ResponseError
+// they embed is also written for the streaming methods, which return it for the error statuses
+// the operation does not declare.
+func generateErrorTypes(out *bytes.Buffer, operations []*operation) {
+	declared := false
+	for _, op := range operations {
+		declared = declared || len(op.errors) > 0 || op.stream != nil
+	}
+	if !declared {
+		return
+	}
+	out.WriteString(errorHelpers)
+
+	for _, op := range operations {
+		if len(op.errors) == 0 {
+			continue
+		}
+		for _, failure := range op.errors {
+			fmt.Fprintf(out, "// %s is returned by %s for its %s response\n", failure.typeName, op.name, failure.statusCode)
+			fmt.Fprintf(out, "type %s struct {\n\tResponseError\n", failure.typeName)
+			if failure.bodyType != "" {
+				fmt.Fprintf(out, "\tBody %s // Decoded JSON body, the zero value when the response has none\n", failure.bodyType)
+			}
+			out.WriteString("}\n\n")
+		}
+
+		fmt.Fprintf(out, "// decode%sError returns the error of a %s response with an error status the\n", op.name, op.name)
+		out.WriteString("// operation declares, reading and closing its body, or nil for other responses\n")
+		fmt.Fprintf(out, "func decode%sError(resp *http.Response) error {\n\tswitch {\n", op.name)
+		for _, failure := range op.errors {
+			switch code := failure.statusCode; {
+			case code == "default":
+				out.WriteString("\tcase resp.StatusCode >= 400:\n")
+			case strings.HasSuffix(strings.ToUpper(code), "XX"):
+				fmt.Fprintf(out, "\tcase resp.StatusCode >= %c00 && resp.StatusCode <= %c99:\n", code[0], code[0])
+			default:
+				fmt.Fprintf(out, "\tcase resp.StatusCode == %s:\n", code)
+			}
+			body := "nil"
+			if failure.bodyType != "" {
+				body = "&err.Body"
+			}
+			fmt.Fprintf(out, "\t\terr := &%s{}\n", failure.typeName)
+			fmt.Fprintf(out, "\t\terr.ResponseError = readResponseError(%q, resp, %s)\n\t\treturn err\n", op.name, body)
+		}
+		out.WriteString("\t}\n\treturn nil\n}\n\n")
+	}
+}
+
+// generateStreamMethod writes <Op>Stream, which sends the request of an operation with a
+// streaming response and iterates over its events as they arrive
+func generateStreamMethod(out *bytes.Buffer, op *operation, security string) {
+	eventType, reader := op.stream.goType, "readJSONLines"
+	if op.stream.sse {
+		eventType, reader = "ServerSentEvent["+op.stream.goType+"]", "readServerSentEvents"
+	}
+	fmt.Fprintf(out, "// %sStream sends %s %s and iterates over the events of its %s\n", op.name, op.Method, op.Path, op.stream.contentType)
+	out.WriteString("// response as they arrive. The response body is closed when the iteration ends, which it\n")
+	out.WriteString("// does with the context's error when ctx is canceled. An error status is returned as an\n")
+	out.WriteString("// APIError, like the other Client methods do.\n")
+	fmt.Fprintf(out, "func (c *Client) %sStream(%s, options ...CallOption) (iter.Seq2[%s, error], error) {\n", op.name, clientSignature(op), eventType)
+	fmt.Fprintf(out, "\treq, err := New%sRequest(%s)\n", op.name, requestArguments(op))
+	out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	fmt.Fprintf(out, "\treq.Header.Set(\"Accept\", %q)\n", op.stream.contentType)
+	fmt.Fprintf(out, "\tresp, err := c.do(ctx, req, %s, options)\n", security)
+	out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	if len(op.errors) > 0 {
+		fmt.Fprintf(out, "\tif err := decode%sError(resp); err != nil {\n\t\treturn nil, err\n\t}\n", op.name)
+	}
+	out.WriteString("\tif resp.StatusCode < 200 || resp.StatusCode > 299 {\n")
+	fmt.Fprintf(out, "\t\terr := readResponseError(%q, resp, nil)\n\t\treturn nil, &err\n\t}\n", op.name)
+	fmt.Fprintf(out, "\treturn %s[%s](ctx, resp.Body), nil\n}\n\n", reader, op.stream.goType)
+}
+
+// generateStreamHelpers writes the readers of the streaming media types the operations use
+func generateStreamHelpers(out *bytes.Buffer, operations []*operation) {
+	sse, jsonLines := false, false
+	for _, op := range operations {
+		if op.stream != nil {
+			sse = sse || op.stream.sse
+			jsonLines = jsonLines || !op.stream.sse
+		}
+	}
+	if sse || jsonLines {
+		out.WriteString(streamHelpers)
+	}
+	if sse {
+		out.WriteString(serverSentEventsHelper)
+	}
+	if jsonLines {
+		out.WriteString(jsonLinesHelper)
+	}
+}
+
+// clientSignature returns the parameters of the Client method of an operation. Request
+// bodies that are not JSON are passed as a reader with their content type.
+func clientSignature(op *operation) string {
+	signature := "ctx context.Context"
+	if len(op.params) > 0 {
+		signature += ", params " + op.paramsType()
+	}
+	switch {
+	case op.body != nil && op.body.required:
+		signature += ", body " + op.body.goType
+	case op.body != nil:
+		signature += ", body *" + op.body.goType
+	case op.RequestBody != nil:
+		signature += ", contentType string, body io.Reader"
+	}
+	return signature
+}
+
+// requestArguments returns the arguments a Client method passes to New<Op>Request
+func requestArguments(op *operation) string {
+	arguments := "c.Server"
+	if len(op.params) > 0 {
+		arguments += ", params"
+	}
+	switch {
+	case op.body != nil:
+		arguments += ", body"
+	case op.RequestBody != nil:
+		arguments += ", contentType, body"
+	}
+	return arguments
+}
+
+// generateRequestBuilder writes New<Op>Request
+func generateRequestBuilder(out *bytes.Buffer, op *operation) {
+	if op.callbackOf != "" {
+		fmt.Fprintf(out, "// New%sRequest builds a %s request of the %s callback to callbackURL, the URL\n", op.name, op.Method, op.callbackOf)
+		fmt.Fprintf(out, "// %s evaluates to\n", op.Path)
+		fmt.Fprintf(out, "func New%sRequest(%s) (*http.Request, error) {\n", op.name, strings.Replace(clientSignature(op), "ctx context.Context", "callbackURL string", 1))
+	} else {
+		fmt.Fprintf(out, "// New%sRequest builds a %s %s request to the API at server\n", op.name, op.Method, op.Path)
+		fmt.Fprintf(out, "func New%sRequest(%s) (*http.Request, error) {\n", op.name, strings.Replace(clientSignature(op), "ctx context.Context", "server string", 1))
+	}
+
+	hasPathParams, hasRequestParams := false, false
+	for _, param := range op.params {
+		if param.In == "path" {
+			hasPathParams = true
+		} else {
+			hasRequestParams = true
+		}
+	}
+	url := "strings.TrimSuffix(server, \"/\")+path"
+	switch {
+	case op.callbackOf != "":
+		url = "callbackURL"
+	case hasPathParams:
+		fmt.Fprintf(out, "\tpath, err := %sPath(params)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", op.name)
+	default:
+		fmt.Fprintf(out, "\tpath := %q\n", op.Path)
+	}
+
+	reader := "nil"
+	switch {
+	case op.body != nil && op.body.required:
+		reader = "bytes.NewReader(data)"
+		out.WriteString("\tdata, err := json.Marshal(body)\n")
+		out.WriteString("\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"failed to encode request body: %w\", err)\n\t}\n")
+	case op.body != nil:
+		reader = "reader"
+		out.WriteString("\tvar reader io.Reader\n\tif body != nil {\n")
+		out.WriteString("\t\tdata, err := json.Marshal(body)\n")
+		out.WriteString("\t\tif err != nil {\n\t\t\treturn nil, fmt.Errorf(\"failed to encode request body: %w\", err)\n\t\t}\n")
+		out.WriteString("\t\treader = bytes.NewReader(data)\n\t}\n")
+	case op.RequestBody != nil:
+		reader = "body"
+	}
+	fmt.Fprintf(out, "\treq, err := http.NewRequest(%q, %s, %s)\n", op.Method, url, reader)
+	out.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	switch {
+	case op.body != nil && op.body.required:
+		fmt.Fprintf(out, "\treq.Header.Set(\"Content-Type\", %q)\n", op.body.contentType)
+	case op.body != nil:
+		fmt.Fprintf(out, "\tif body != nil {\n\t\treq.Header.Set(\"Content-Type\", %q)\n\t}\n", op.body.contentType)
+	case op.RequestBody != nil:
+		out.WriteString("\treq.Header.Set(\"Content-Type\", contentType)\n")
+	}
+	if hasRequestParams {
+		fmt.Fprintf(out, "\tif err := Encode%s(req, params); err != nil {\n\t\treturn nil, err\n\t}\n", op.paramsType())
+	}
+	out.WriteString("\treturn req, nil\n}\n\n")
+}
+
+// operationSchemes returns the names of the security schemes accepted by an operation, sorted:
+// those of its security requirements, or of the document's when it declares none
+func operationSchemes(document *Document, op *operation) []string {
+	requirements := document.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+	seen := make(map[string]bool)
+	var schemes []string
+	for _, requirement := range requirements {
+		for scheme := range requirement {
+			if !seen[scheme] {
+				seen[scheme] = true
+				schemes = append(schemes, scheme)
+			}
+		}
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// generateSecurityOptions writes a ClientOption per security scheme of the document, applying
+// its credentials to the requests of the operations accepting it. Mutual TLS is configured on
+// the http.Client, so it has no option.
+func generateSecurityOptions(out *bytes.Buffer, document *Document) {
+	clientCredentialsFlow := false
+	for _, name := range sortedKeys(document.Components.SecuritySchemes) {
+		scheme := document.Components.SecuritySchemes[name]
+		option := "With" + strings.TrimSuffix(jrpc.GoIdentifier(name, nil), "Auth")
+		var description, signature, apply string
+		switch {
+		case scheme.Type == "apiKey":
+			description = fmt.Sprintf("sends key in the %s header", scheme.Name)
+			signature = "key string"
+			switch scheme.In {
+			case "query":
+				description = fmt.Sprintf("sends key in the %s query parameter", scheme.Name)
+				apply = fmt.Sprintf("query := req.URL.Query()\n\t\tquery.Set(%q, key)\n\t\treq.URL.RawQuery = query.Encode()", scheme.Name)
+			case "cookie":
+				description = fmt.Sprintf("sends key in the %s cookie", scheme.Name)
+				apply = fmt.Sprintf("req.AddCookie(&http.Cookie{Name: %q, Value: key})", scheme.Name)
+			default:
+				apply = fmt.Sprintf("req.Header.Set(%q, key)", scheme.Name)
+			}
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			description = "sends username and password with HTTP basic authentication"
+			signature = "username string, password string"
+			apply = "req.SetBasicAuth(username, password)"
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+			description = "sends token as a bearer token"
+			signature = "token string"
+			apply = "req.Header.Set(\"Authorization\", \"Bearer \"+token)"
+		case scheme.Type == "http":
+			description = fmt.Sprintf("sends credentials with the %s HTTP authentication scheme", scheme.Scheme)
+			signature = "credentials string"
+			apply = fmt.Sprintf("req.Header.Set(\"Authorization\", %q+credentials)", scheme.Scheme+" ")
+		case scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+			description = "sends the access tokens returned by token as bearer tokens. token is called for\n// every request, so it can refresh expired tokens"
+			signature = "token func(ctx context.Context) (string, error)"
+			apply = "accessToken, err := token(ctx)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\treq.Header.Set(\"Authorization\", \"Bearer \"+accessToken)"
+		default:
+			continue
+		}
+
+		fmt.Fprintf(out, "// %sAuth authenticates the operations accepting the %s security scheme: it\n// %s\n", option, name, description)
+		fmt.Fprintf(out, "func %sAuth(%s) ClientOption {\n", option, signature)
+		fmt.Fprintf(out, "\treturn WithRequestEditorFn(securityEditor(%q, func(ctx context.Context, req *http.Request) error {\n", name)
+		fmt.Fprintf(out, "\t\t%s\n\t\treturn nil\n\t}))\n}\n\n", apply)
+
+		if scheme.Type == "oauth2" && scheme.Flows != nil && scheme.Flows.ClientCredentials != nil {
+			fmt.Fprintf(out, "// %sClientCredentials authenticates the operations accepting the %s security scheme\n", option, name)
+			out.WriteString("// with access tokens of the OAuth 2.0 client credentials flow, fetched when needed and\n// cached until they expire\n")
+			fmt.Fprintf(out, "func %sClientCredentials(clientID string, clientSecret string, scopes ...string) ClientOption {\n", option)
+			out.WriteString("\treturn func(c *Client) error {\n")
+			fmt.Fprintf(out, "\t\tcredentials := &clientCredentials{client: c, tokenURL: %q, clientID: clientID, clientSecret: clientSecret, scopes: scopes}\n", scheme.Flows.ClientCredentials.TokenURL)
+			fmt.Fprintf(out, "\t\treturn %sAuth(credentials.Token)(c)\n\t}\n}\n\n", option)
+			clientCredentialsFlow = true
+		}
+	}
+	if clientCredentialsFlow {
+		out.WriteString(clientCredentialsHelper)
+	}
+}
+
+// clientHelpers are the Client type and the helpers its methods and options share
+const clientHelpers = `// HTTPRequestDoer sends HTTP requests. *http.Client implements it.
+type HTTPRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RequestEditorFn edits a request before the client sends it, for example to add
+// credentials or tracing headers
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// ResponseHook runs after a request was sent, with its response or error, and returns the
+// response and error the call ends with. Hooks can log, turn responses into errors or retry:
+// requests built by the client can be sent again, as their GetBody is set.
+type ResponseHook func(ctx context.Context, req *http.Request, resp *http.Response, err error) (*http.Response, error)
+
+// CallOption customizes a single call of a Client method: a RequestEditorFn or a
+// ResponseHook, which run after those of the client
+type CallOption interface {
+	applyCall(call *callOptions)
+}
+
+// callOptions are the request editors and response hooks of a call
+type callOptions struct {
+	editors []RequestEditorFn
+	hooks   []ResponseHook
+}
+
+// applyCall adds fn to the request editors of a call
+func (fn RequestEditorFn) applyCall(call *callOptions) {
+	call.editors = append(call.editors, fn)
+}
+
+// applyCall adds hook to the response hooks of a call
+func (hook ResponseHook) applyCall(call *callOptions) {
+	call.hooks = append(call.hooks, hook)
+}
+
+// Client calls the operations of the API
+type Client struct {
+	Server         string            // Base URL of the API (e.g. "https://api.example.com/v1")
+	Client         HTTPRequestDoer   // Sends the requests (default: http.DefaultClient)
+	RequestEditors []RequestEditorFn // Applied in order to every request before it is sent
+	ResponseHooks  []ResponseHook    // Applied in order to the response of every request
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client) error
+
+// NewClient returns a Client for the API at server
+func NewClient(server string, options ...ClientOption) (*Client, error) {
+	client := &Client{Server: server}
+	for _, option := range options {
+		if err := option(client); err != nil {
+			return nil, err
+		}
+	}
+	if client.Client == nil {
+		client.Client = http.DefaultClient
+	}
+	return client, nil
+}
+
+// WithHTTPClient sends the requests of the client with doer
+func WithHTTPClient(doer HTTPRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn adds fn to the editors applied to every request of the client
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// WithResponseHook adds hook to the hooks applied to the response of every request of the
+// client
+func WithResponseHook(hook ResponseHook) ClientOption {
+	return func(c *Client) error {
+		c.ResponseHooks = append(c.ResponseHooks, hook)
+		return nil
+	}
+}
+
+// securityContextKey is the context key of the security schemes accepted by the operation of
+// a request
+type securityContextKey struct{}
+
+// OperationSecurity returns the names of the security schemes accepted by the operation a
+// request context belongs to, as seen by request editors; nil when it needs no credentials
+func OperationSecurity(ctx context.Context) []string {
+	schemes, _ := ctx.Value(securityContextKey{}).([]string)
+	return schemes
+}
+
+// securityEditor returns a RequestEditorFn applying credentials to the requests of the
+// operations accepting scheme
+func securityEditor(scheme string, apply RequestEditorFn) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		if !slices.Contains(OperationSecurity(ctx), scheme) {
+			return nil
+		}
+		return apply(ctx, req)
+	}
+}
+
+// do applies the request editors of the client and of the call to req, sends it and applies
+// the response hooks to the result. security lists the security schemes accepted by the
+// operation.
+func (c *Client) do(ctx context.Context, req *http.Request, security []string, options []CallOption) (*http.Response, error) {
+	call := callOptions{editors: slices.Clip(c.RequestEditors), hooks: slices.Clip(c.ResponseHooks)}
+	for _, option := range options {
+		option.applyCall(&call)
+	}
+
+	ctx = context.WithValue(ctx, securityContextKey{}, security)
+	req = req.WithContext(ctx)
+	for _, edit := range call.editors {
+		if err := edit(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.Client.Do(req)
+	for _, hook := range call.hooks {
+		resp, err = hook(ctx, req, resp, err)
+	}
+	return resp, err
+}
+`
+
+// errorHelpers are the APIError interface and the ResponseError its implementations embed
+const errorHelpers = `// APIError is implemented by the errors Client methods return for the error responses the
+// API declares, one type per operation and status code, and by the *ResponseError streaming
+// methods return for the other error statuses. Use errors.As with a pointer to one of the
+// types for its typed body, or with an APIError for any of them.
+type APIError interface {
+	error
+	StatusCode() int // Status code of the response
+	RawBody() []byte // Body of the response as received
+}
+
+// ResponseError is the error response returned by a Client method
+type ResponseError struct {
+	Operation string         // Go name of the operation
+	Response  *http.Response // Response, whose Body has been read and closed
+	Raw       []byte         // Body of the response
+}
+
+// StatusCode returns the status code of the response
+func (e *ResponseError) StatusCode() int {
+	return e.Response.StatusCode
+}
+
+// RawBody returns the body of the response
+func (e *ResponseError) RawBody() []byte {
+	return e.Raw
+}
+
+// Error describes the response, with its body when it is short text
+func (e *ResponseError) Error() string {
+	message := e.Operation + ": " + e.Response.Status
+	if text := strings.TrimSpace(string(e.Raw)); text != "" && len(text) <= 512 && utf8.ValidString(text) {
+		message += ": " + text
+	}
+	return message
+}
+
+// readResponseError reads and closes the body of an error response, decoding it into body
+// when body is not nil and the response is JSON
+func readResponseError(operation string, resp *http.Response, body any) ResponseError {
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if body != nil && len(raw) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			_ = json.Unmarshal(raw, body)
+		}
+	}
+	return ResponseError{Operation: operation, Response: resp, Raw: raw}
+}
+`
+
+// clientCredentialsHelper fetches the access tokens of OAuth 2.0 client credentials flows
+const clientCredentialsHelper = `// clientCredentials fetches access tokens with the OAuth 2.0 client credentials grant and
+// caches them until they expire
+type clientCredentials struct {
+	client       *Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns the cached access token, fetching a new one when it is missing or about to
+// expire
+func (cc *clientCredentials) Token(ctx context.Context) (string, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.token != "" && time.Now().Before(cc.expires) {
+		return cc.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cc.scopes) > 0 {
+		form.Set("scope", strings.Join(cc.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(cc.clientID), url.QueryEscape(cc.clientSecret))
+
+	resp, err := cc.client.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch access token: %s", resp.Status)
+	}
+	var token map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+	accessToken, _ := token["access_token"].(string)
+	if accessToken == "" {
+		return "", errors.New("failed to fetch access token: the response has no access_token")
+	}
+
+	// Tokens are renewed a little before they expire, and on every request when the lifetime
+	// is unknown
+	cc.token, cc.expires = accessToken, time.Now()
+	if expiresIn, ok := token["expires_in"].(float64); ok && expiresIn > 30 {
+		cc.expires = cc.expires.Add(time.Duration(expiresIn-30) * time.Second)
+	}
+	return cc.token, nil
+}
+
+`
+
+// serverSentEventsHelper reads text/event-stream responses
+const serverSentEventsHelper = `// ServerSentEvent is an event of a text/event-stream response
+type ServerSentEvent[T any] struct {
+	Event string // Event type, empty for the default "message" type
+	ID    string
+	Data  T // The data lines of the event, decoded as JSON unless T is a string
+}
+
+// readServerSentEvents iterates over the events of a text/event-stream body and closes it when
+// the iteration ends. A "[DONE]" data line, which OpenAI-compatible APIs send last, ends the
+// stream.
+func readServerSentEvents[T any](ctx context.Context, body io.ReadCloser) iter.Seq2[ServerSentEvent[T], error] {
+	return func(yield func(ServerSentEvent[T], error) bool) {
+		defer body.Close()
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		var event ServerSentEvent[T]
+		var data []string
+		// dispatch yields the pending event, if it has data, and reports whether to read on
+		dispatch := func() bool {
+			if len(data) == 0 {
+				return true
+			}
+			text := strings.Join(data, "\n")
+			if text == "[DONE]" {
+				return false
+			}
+			err := decodeStreamData(text, &event.Data)
+			return yield(event, err)
+		}
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				if !dispatch() {
+					return
+				}
+				event, data = ServerSentEvent[T]{}, nil
+				continue
+			}
+
+			// Lines starting with a colon are comments, and retry hints are left to callers
+			// reconnecting
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event.Event = value
+			case "id":
+				event.ID = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+		if err := streamError(ctx, scanner.Err()); err != nil {
+			yield(ServerSentEvent[T]{}, err)
+			return
+		}
+
+		// The last event may end with the body instead of a blank line
+		dispatch()
+	}
+}
+
+`
+
+// jsonLinesHelper reads application/x-ndjson and application/jsonl responses
+const jsonLinesHelper = `// readJSONLines iterates over the values of a newline-delimited JSON body and closes it when
+// the iteration ends
+func readJSONLines[T any](ctx context.Context, body io.ReadCloser) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer body.Close()
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var value T
+			err := decodeStreamData(line, &value)
+			if !yield(value, err) {
+				return
+			}
+		}
+		if err := streamError(ctx, scanner.Err()); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+`
+
+// streamHelpers are shared by the readers of streaming responses
+const streamHelpers = `// decodeStreamData decodes an event of a streaming response as JSON, or keeps its text when
+// the event type is a string
+func decodeStreamData(text string, target any) error {
+	if value, ok := target.(*string); ok {
+		*value = text
+		return nil
+	}
+	if err := json.Unmarshal([]byte(text), target); err != nil {
+		return fmt.Errorf("failed to decode stream event: %w", err)
+	}
+	return nil
+}
+
+// streamError returns the error a stream ended with: the context's error when it was
+// canceled, which interrupts the read of the body
+func streamError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+`
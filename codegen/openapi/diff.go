@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Diff compares two versions of an OpenAPI document: the operations, their parameters,
+// request bodies and responses, and the component schemas (see jrpc.DiffDefinitions).
+// Removed operations, parameters, media types and responses, and parameters or request
+// bodies that became required, are breaking changes. Parameters are addressed by location and
+// name in the change paths (".../get/parameters/query/limit").
+func Diff(oldDocument *Document, newDocument *Document) []jrpc.Change {
+	var changes []jrpc.Change
+
+	newOperations := make(map[string]*Operation)
+	for _, op := range newDocument.Operations() {
+		newOperations[op.Method+" "+op.Path] = op
+	}
+	oldOperations := make(map[string]bool)
+	for _, oldOp := range oldDocument.Operations() {
+		oldOperations[oldOp.Method+" "+oldOp.Path] = true
+		pointer := operationPointer(oldOp)
+		newOp, ok := newOperations[oldOp.Method+" "+oldOp.Path]
+		if !ok {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRemoved, Path: pointer, Breaking: true, Message: fmt.Sprintf("operation %s %s was removed", oldOp.Method, oldOp.Path)})
+			continue
+		}
+		changes = append(changes, diffParameters(pointer, oldOp.Parameters, newOp.Parameters)...)
+		changes = append(changes, diffRequestBody(pointer+"/requestBody", oldOp.RequestBody, newOp.RequestBody)...)
+		changes = append(changes, diffResponses(pointer+"/responses", oldOp.Responses, newOp.Responses)...)
+	}
+	for _, newOp := range newDocument.Operations() {
+		if !oldOperations[newOp.Method+" "+newOp.Path] {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeAdded, Path: operationPointer(newOp), Message: fmt.Sprintf("operation %s %s was added", newOp.Method, newOp.Path)})
+		}
+	}
+
+	return append(changes, jrpc.DiffDefinitions("/components/schemas/", oldDocument.Components.Schemas, newDocument.Components.Schemas)...)
+}
+
+// operationPointer returns the JSON pointer of an operation
+func operationPointer(op *Operation) string {
+	return "/paths/" + escapePointer(op.Path) + "/" + strings.ToLower(op.Method)
+}
+
+// diffParameters compares the parameters of two versions of an operation
+func diffParameters(pointer string, oldParameters []*Parameter, newParameters []*Parameter) []jrpc.Change {
+	var changes []jrpc.Change
+	find := func(parameters []*Parameter, in string, name string) *Parameter {
+		for _, parameter := range parameters {
+			if parameter.In == in && parameter.Name == name {
+				return parameter
+			}
+		}
+		return nil
+	}
+
+	for _, oldParameter := range oldParameters {
+		parameterPointer := pointer + "/parameters/" + oldParameter.In + "/" + escapePointer(oldParameter.Name)
+		newParameter := find(newParameters, oldParameter.In, oldParameter.Name)
+		switch {
+		case newParameter == nil:
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRemoved, Path: parameterPointer, Breaking: true, Message: fmt.Sprintf("%s parameter %s was removed", oldParameter.In, oldParameter.Name)})
+			continue
+		case newParameter.Required && !oldParameter.Required:
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRequiredAdded, Path: parameterPointer, Breaking: true, Message: fmt.Sprintf("%s parameter %s became required", oldParameter.In, oldParameter.Name)})
+		case oldParameter.Required && !newParameter.Required:
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRequiredRemoved, Path: parameterPointer, Message: fmt.Sprintf("%s parameter %s is no longer required", oldParameter.In, oldParameter.Name)})
+		}
+		changes = append(changes, jrpc.DiffSchema(parameterPointer+"/schema", oldParameter.Schema, newParameter.Schema)...)
+	}
+	for _, newParameter := range newParameters {
+		if find(oldParameters, newParameter.In, newParameter.Name) != nil {
+			continue
+		}
+		parameterPointer := pointer + "/parameters/" + newParameter.In + "/" + escapePointer(newParameter.Name)
+		if newParameter.Required {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRequiredAdded, Path: parameterPointer, Breaking: true, Message: fmt.Sprintf("required %s parameter %s was added", newParameter.In, newParameter.Name)})
+		} else {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeAdded, Path: parameterPointer, Message: fmt.Sprintf("optional %s parameter %s was added", newParameter.In, newParameter.Name)})
+		}
+	}
+	return changes
+}
+
+// diffRequestBody compares the request bodies of two versions of an operation
+func diffRequestBody(pointer string, oldBody *RequestBody, newBody *RequestBody) []jrpc.Change {
+	switch {
+	case oldBody == nil && newBody == nil:
+		return nil
+	case oldBody == nil:
+		if newBody.Required {
+			return []jrpc.Change{{Kind: jrpc.ChangeRequiredAdded, Path: pointer, Breaking: true, Message: "a required request body was added"}}
+		}
+		return []jrpc.Change{{Kind: jrpc.ChangeAdded, Path: pointer, Message: "an optional request body was added"}}
+	case newBody == nil:
+		return []jrpc.Change{{Kind: jrpc.ChangeRemoved, Path: pointer, Breaking: true, Message: "the request body was removed"}}
+	}
+
+	var changes []jrpc.Change
+	if newBody.Required && !oldBody.Required {
+		changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRequiredAdded, Path: pointer, Breaking: true, Message: "the request body became required"})
+	}
+	return append(changes, diffContent(pointer+"/content", oldBody.Content, newBody.Content)...)
+}
+
+// diffResponses compares the responses of two versions of an operation
+func diffResponses(pointer string, oldResponses map[string]*Response, newResponses map[string]*Response) []jrpc.Change {
+	var changes []jrpc.Change
+	for _, code := range sortedKeys(oldResponses) {
+		responsePointer := pointer + "/" + code
+		newResponse, ok := newResponses[code]
+		if !ok {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRemoved, Path: responsePointer, Breaking: true, Message: fmt.Sprintf("response %s was removed", code)})
+			continue
+		}
+		changes = append(changes, diffContent(responsePointer+"/content", oldResponses[code].Content, newResponse.Content)...)
+	}
+	for _, code := range sortedKeys(newResponses) {
+		if _, ok := oldResponses[code]; !ok {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeAdded, Path: pointer + "/" + code, Message: fmt.Sprintf("response %s was added", code)})
+		}
+	}
+	return changes
+}
+
+// diffContent compares the media types of two versions of a request body or response
+func diffContent(pointer string, oldContent map[string]*MediaType, newContent map[string]*MediaType) []jrpc.Change {
+	var changes []jrpc.Change
+	for _, mediaType := range sortedKeys(oldContent) {
+		mediaPointer := pointer + "/" + escapePointer(mediaType)
+		newMedia, ok := newContent[mediaType]
+		if !ok {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeRemoved, Path: mediaPointer, Breaking: true, Message: fmt.Sprintf("media type %s was removed", mediaType)})
+			continue
+		}
+		changes = append(changes, jrpc.DiffSchema(mediaPointer+"/schema", oldContent[mediaType].Schema, newMedia.Schema)...)
+	}
+	for _, mediaType := range sortedKeys(newContent) {
+		if _, ok := oldContent[mediaType]; !ok {
+			changes = append(changes, jrpc.Change{Kind: jrpc.ChangeAdded, Path: pointer + "/" + escapePointer(mediaType), Message: fmt.Sprintf("media type %s was added", mediaType)})
+		}
+	}
+	return changes
+}
+
+// escapePointer escapes a JSON pointer token (~ is ~0, / is ~1)
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,409 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI 3.x document. Schemas are kept as decoded JSON values
+// (map[string]any), the representation the jrpc generator works on; every other object is
+// modeled. LoadDocument and ParseDocument resolve the $refs between components, so that
+// operations can be walked without looking components up.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths,omitempty"`
+	Webhooks   map[string]*PathItem  `json:"webhooks,omitempty"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+
+	operations []*Operation
+}
+
+// Info is the metadata of the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the API is served from
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable is a placeholder of a Server URL
+type ServerVariable struct {
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// SecurityRequirement maps the names of security schemes to the scopes an operation needs
+type SecurityRequirement map[string][]string
+
+// Components holds the reusable objects of the document, by name
+type Components struct {
+	Schemas         map[string]any             `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	Examples        map[string]*Example        `json:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	Callbacks       map[string]*Callback       `json:"callbacks,omitempty"`
+	PathItems       map[string]*PathItem       `json:"pathItems,omitempty"`
+}
+
+// PathItem describes the operations available on a path
+type PathItem struct {
+	Ref         string       `json:"$ref,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Get         *Operation   `json:"get,omitempty"`
+	Put         *Operation   `json:"put,omitempty"`
+	Post        *Operation   `json:"post,omitempty"`
+	Delete      *Operation   `json:"delete,omitempty"`
+	Options     *Operation   `json:"options,omitempty"`
+	Head        *Operation   `json:"head,omitempty"`
+	Patch       *Operation   `json:"patch,omitempty"`
+	Trace       *Operation   `json:"trace,omitempty"`
+	Servers     []Server     `json:"servers,omitempty"`
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+}
+
+// Operation is a single API operation on a path. Method, Path and the merged parameters are
+// filled in when the document is resolved.
+type Operation struct {
+	Tags        []string               `json:"tags,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	OperationID string                 `json:"operationId,omitempty"`
+	Parameters  []*Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses,omitempty"`
+	Callbacks   map[string]*Callback   `json:"callbacks,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"` // nil inherits Document.Security, empty disables it
+	Servers     []Server               `json:"servers,omitempty"`
+	Pagination  *Pagination            `json:"x-pagination,omitempty"`
+	Extensions  map[string]any         `json:"-"` // Vendor extensions (x-*) the generator does not interpret
+	Method      string                 `json:"-"` // HTTP method in upper case (e.g. "GET")
+	Path        string                 `json:"-"` // Path template (e.g. "/tasks/{id}"), or the webhook name
+}
+
+// UnmarshalJSON decodes an operation, collecting the vendor extensions the generator does not
+// interpret into Extensions
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type plain Operation
+	if err := json.Unmarshal(data, (*plain)(o)); err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	o.Extensions = jrpc.VendorExtensions(fields)
+	return nil
+}
+
+// Pagination is the x-pagination extension of a list operation, describing how its pages are
+// followed where the naming conventions do not tell. Paths into the response body are
+// dot-separated property names (e.g. "meta.next_cursor"). x-pagination: false disables it.
+type Pagination struct {
+	Disabled    bool   `json:"-"`
+	CursorParam string `json:"cursorParam,omitempty"` // Query parameter carrying the cursor
+	Cursor      string `json:"cursor,omitempty"`      // Path of the next cursor in the response body
+	PageParam   string `json:"pageParam,omitempty"`   // Query parameter carrying the page number
+	Items       string `json:"items,omitempty"`       // Path of the items in the response body
+	HasMore     string `json:"hasMore,omitempty"`     // Path of the boolean telling whether more pages follow
+	Link        bool   `json:"link,omitempty"`        // Follow the rel="next" links of the Link header
+}
+
+// UnmarshalJSON decodes the x-pagination extension, an object or false
+func (p *Pagination) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "false" {
+		p.Disabled = true
+		return nil
+	}
+	type plain Pagination
+	return json.Unmarshal(data, (*plain)(p))
+}
+
+// MarshalJSON encodes the x-pagination extension
+func (p Pagination) MarshalJSON() ([]byte, error) {
+	if p.Disabled {
+		return []byte("false"), nil
+	}
+	type plain Pagination
+	return json.Marshal(plain(p))
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation
+type Parameter struct {
+	Ref             string                `json:"$ref,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	In              string                `json:"in,omitempty"` // "path", "query", "header" or "cookie"
+	Description     string                `json:"description,omitempty"`
+	Required        bool                  `json:"required,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty"`
+	Style           string                `json:"style,omitempty"`
+	Explode         *bool                 `json:"explode,omitempty"`
+	Schema          map[string]any        `json:"schema,omitempty"`
+	Content         map[string]*MediaType `json:"content,omitempty"`
+	Example         any                   `json:"example,omitempty"`
+	Examples        map[string]*Example   `json:"examples,omitempty"`
+}
+
+// Header is a response header. It has the fields of a Parameter except Name and In.
+type Header struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Style       string                `json:"style,omitempty"`
+	Explode     *bool                 `json:"explode,omitempty"`
+	Schema      map[string]any        `json:"schema,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Example     any                   `json:"example,omitempty"`
+	Examples    map[string]*Example   `json:"examples,omitempty"`
+}
+
+// RequestBody is the body of a request, by media type
+type RequestBody struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Response is a response of an operation, keyed by status code ("200", "4XX" or "default")
+// in Operation.Responses
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema and examples of a body in one media type
+type MediaType struct {
+	Schema     map[string]any       `json:"schema,omitempty"`
+	ItemSchema map[string]any       `json:"itemSchema,omitempty"` // Schema of each item of sequential media types such as text/event-stream (OpenAPI 3.2)
+	Example    any                  `json:"example,omitempty"`
+	Examples   map[string]*Example  `json:"examples,omitempty"`
+	Encoding   map[string]*Encoding `json:"encoding,omitempty"`
+}
+
+// Encoding describes how a property of a multipart or form body is serialized
+type Encoding struct {
+	ContentType   string             `json:"contentType,omitempty"`
+	Headers       map[string]*Header `json:"headers,omitempty"`
+	Style         string             `json:"style,omitempty"`
+	Explode       *bool              `json:"explode,omitempty"`
+	AllowReserved bool               `json:"allowReserved,omitempty"`
+}
+
+// Example is a named example value
+type Example struct {
+	Ref           string `json:"$ref,omitempty"`
+	Summary       string `json:"summary,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Value         any    `json:"value,omitempty"`
+	ExternalValue string `json:"externalValue,omitempty"`
+}
+
+// SecurityScheme is a way of authenticating requests
+type SecurityScheme struct {
+	Ref              string      `json:"$ref,omitempty"`
+	Type             string      `json:"type"` // "apiKey", "http", "mutualTLS", "oauth2" or "openIdConnect"
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"` // Header, query or cookie name of an apiKey
+	In               string      `json:"in,omitempty"`   // Location of an apiKey: "header", "query" or "cookie"
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlows are the OAuth 2.0 flows supported by an oauth2 security scheme
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow is the configuration of an OAuth 2.0 flow
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// Callback maps runtime expressions (e.g. "{$request.body#/callbackUrl}") to the path items
+// of the requests the API may send back
+type Callback struct {
+	Ref       string
+	PathItems map[string]*PathItem
+}
+
+// UnmarshalJSON decodes a callback, which is either a $ref or a map of path items
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	if err := json.Unmarshal(data, &ref); err == nil && ref.Ref != "" {
+		c.Ref = ref.Ref
+		return nil
+	}
+	return json.Unmarshal(data, &c.PathItems)
+}
+
+// MarshalJSON encodes a callback as a $ref or a map of path items
+func (c Callback) MarshalJSON() ([]byte, error) {
+	if c.Ref != "" {
+		return json.Marshal(map[string]string{"$ref": c.Ref})
+	}
+	return json.Marshal(c.PathItems)
+}
+
+// LoadDocument reads and resolves an OpenAPI 3.x document from a .json, .yaml or .yml file
+func LoadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	return decodeDocument(path, data)
+}
+
+// LoadDocumentFS is LoadDocument for a document file of fsys, such as the embed.FS of
+// go:embed'ed documents
+func LoadDocumentFS(fsys fs.FS, path string) (*Document, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	return decodeDocument(path, data)
+}
+
+// decodeDocument parses and resolves the contents of a document file in the format of its
+// extension, locating schema errors in the file
+func decodeDocument(path string, data []byte) (*Document, error) {
+	var document *Document
+	var err error
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		document, err = parseDocument(data, false)
+	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
+		document, err = parseDocument(data, true)
+	default:
+		return nil, fmt.Errorf("unsupported schema format: must be .json, .yaml, or .yml")
+	}
+	return document, codegen.LocateData(err, path, data)
+}
+
+// ParseDocument parses and resolves an OpenAPI 3.x or Swagger 2.0 document in JSON or YAML
+func ParseDocument(data []byte) (*Document, error) {
+	if json.Valid(data) {
+		return parseDocument(data, false)
+	}
+	return parseDocument(data, true)
+}
+
+// parseDocument decodes a document, converting YAML to JSON first so that the model is
+// only described by json tags, and Swagger 2.0 to OpenAPI 3.0, then resolves it
+func parseDocument(data []byte, isYAML bool) (*Document, error) {
+	if isYAML {
+		var value any
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML document: %w", codegen.DecodeError("", data, err))
+		}
+		converted, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML document: %w", err)
+		}
+		data = converted
+	}
+
+	var version struct {
+		Swagger string `json:"swagger"`
+	}
+	if err := json.Unmarshal(data, &version); err == nil && version.Swagger != "" {
+		if version.Swagger != "2.0" {
+			return nil, fmt.Errorf("unsupported Swagger version %q: only 2.0 documents are supported", version.Swagger)
+		}
+		var value map[string]any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse Swagger document: %w", err)
+		}
+		value, err := convertSwagger(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert Swagger document: %w", err)
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("failed to convert Swagger document: %w", err)
+		}
+	}
+
+	// The offsets of the JSON decoding errors are those of the source of JSON documents only
+	var document Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		if !isYAML {
+			err = codegen.DecodeError("", data, err)
+		}
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(document.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: only 3.x documents are supported", document.OpenAPI)
+	}
+	if err := document.resolve(); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// jsonValue converts a decoded YAML value to one encoding/json can marshal: mapping keys
+// that are not strings (such as response codes written as 200) become strings
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			value[key] = jsonValue(child)
+		}
+		return value
+	case map[any]any:
+		converted := make(map[string]any, len(value))
+		for key, child := range value {
+			converted[fmt.Sprint(key)] = jsonValue(child)
+		}
+		return converted
+	case []any:
+		for i, child := range value {
+			value[i] = jsonValue(child)
+		}
+		return value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// filtered reports whether the options select a subset of the operations
+func (o *Options) filtered() bool {
+	return len(o.IncludeTags) > 0 || len(o.IncludeOperations) > 0 || len(o.ExcludePaths) > 0
+}
+
+// filterOperations keeps the operations of a document the options select: those with one of
+// IncludeTags or an operationId matching IncludeOperations (all of them when neither is set),
+// minus those on a path matching ExcludePaths. The component schemas are pruned to those the
+// kept operations reference, directly or through other schemas.
+func filterOperations(document *Document, options *Options) error {
+	for _, pattern := range slices.Concat(options.IncludeOperations, options.ExcludePaths) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var kept []*Operation
+	for _, op := range document.operations {
+		included := len(options.IncludeTags) == 0 && len(options.IncludeOperations) == 0
+		for _, tag := range op.Tags {
+			included = included || slices.Contains(options.IncludeTags, tag)
+		}
+		for _, pattern := range options.IncludeOperations {
+			matched, _ := path.Match(pattern, op.OperationID)
+			included = included || op.OperationID != "" && matched
+		}
+		for _, pattern := range options.ExcludePaths {
+			if matchesPath(pattern, op.Path) {
+				included = false
+			}
+		}
+		if included {
+			kept = append(kept, op)
+		}
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("no operation matches the include and exclude options")
+	}
+	document.operations = kept
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return fmt.Errorf("failed to encode operations: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to decode operations: %w", err)
+	}
+	referenced := make(map[string]any)
+	collectSchemaRefs(value, document.Components.Schemas, referenced)
+	document.Components.Schemas = referenced
+	return nil
+}
+
+// matchesPath reports whether a path template matches an ExcludePaths pattern: a glob whose
+// wildcards match within a path segment, which also matches the paths below the ones it
+// matches
+func matchesPath(pattern string, operationPath string) bool {
+	for candidate := operationPath; candidate != "/" && candidate != "."; candidate = path.Dir(candidate) {
+		if matched, _ := path.Match(strings.TrimSuffix(pattern, "/"), candidate); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// collectSchemaRefs adds the component schemas value refers to, and those they refer to, to
+// referenced
+func collectSchemaRefs(value any, schemas map[string]any, referenced map[string]any) {
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/schemas/") {
+			name := unescapePointer(strings.SplitN(strings.TrimPrefix(ref, "#/components/schemas/"), "/", 2)[0])
+			if schema, ok := schemas[name]; ok && referenced[name] == nil {
+				referenced[name] = schema
+				collectSchemaRefs(schema, schemas, referenced)
+			}
+		}
+		for _, child := range value {
+			collectSchemaRefs(child, schemas, referenced)
+		}
+	case []any:
+		for _, child := range value {
+			collectSchemaRefs(child, schemas, referenced)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Accepted values of Options.ServerFramework
+const (
+	frameworkStdlib = "stdlib" // http.ServeMux only
+	frameworkChi    = "chi"
+	frameworkGin    = "gin"
+	frameworkEcho   = "echo"
+)
+
+// frameworkImports are the packages the router adapters of each framework import
+var frameworkImports = map[string]string{
+	frameworkChi:  "github.com/go-chi/chi/v5",
+	frameworkGin:  "github.com/gin-gonic/gin",
+	frameworkEcho: "github.com/labstack/echo/v4",
+}
+
+// validateServerFramework checks that Options.ServerFramework names a supported framework
+func validateServerFramework(framework string) error {
+	switch framework {
+	case "", frameworkStdlib, frameworkChi, frameworkGin, frameworkEcho:
+		return nil
+	}
+	return fmt.Errorf("unsupported server framework %q: must be stdlib, chi, gin or echo", framework)
+}
+
+// generateFrameworkAdapter writes the functions registering the operations on the router of
+// Options.ServerFramework. The adapters copy the path parameters of the framework into the
+// request with SetPathValue, so that the ServerInterfaceWrapper decodes them as it does for
+// http.ServeMux.
+func generateFrameworkAdapter(out *bytes.Buffer, operations []*operation, framework string) {
+	switch framework {
+	case frameworkChi:
+		generateChiAdapter(out, operations)
+	case frameworkGin:
+		generateGinAdapter(out, operations)
+	case frameworkEcho:
+		generateEchoAdapter(out, operations)
+	}
+}
+
+// pathWildcards returns the ServeMux wildcard names of the parameters of a path template
+func pathWildcards(path string) []string {
+	var names []string
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, serveMuxWildcard(match[1]))
+	}
+	return names
+}
+
+// colonPath converts a path template to the :name syntax of gin and echo routes
+func colonPath(path string) string {
+	return pathParameterPattern.ReplaceAllStringFunc(path, func(placeholder string) string {
+		return ":" + serveMuxWildcard(strings.Trim(placeholder, "{}"))
+	})
+}
+
+// quotedList returns names as a comma-separated list of Go string literals
+func quotedList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// generateChiAdapter writes RegisterChiHandlers for github.com/go-chi/chi/v5 routers
+func generateChiAdapter(out *bytes.Buffer, operations []*operation) {
+	out.WriteString(`// RegisterChiHandlers registers the operations of si on a chi router
+func RegisterChiHandlers(router chi.Router, si ServerInterface) {
+	RegisterChiHandlersWithOptions(router, si, HandlerOptions{})
+}
+
+// RegisterChiHandlersWithOptions registers the operations of si on a chi router
+func RegisterChiHandlersWithOptions(router chi.Router, si ServerInterface, options HandlerOptions) {
+	wrapper := &ServerInterfaceWrapper{Handler: si, ErrorHandlerFunc: options.ErrorHandlerFunc}
+`)
+	for _, op := range operations {
+		fmt.Fprintf(out, "\trouter.Method(%q, options.BaseURL+%q, chiHandler(wrapper.%s", op.Method, strings.TrimSuffix(serveMuxPattern(op.Path), "{$}"), op.name)
+		if names := pathWildcards(op.Path); len(names) > 0 {
+			out.WriteString(", " + quotedList(names))
+		}
+		out.WriteString("))\n")
+	}
+	out.WriteString(`}
+
+// chiHandler makes the chi URL parameters of a request available to handler as path values
+func chiHandler(handler http.HandlerFunc, names ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range names {
+			r.SetPathValue(name, chi.URLParam(r, name))
+		}
+		handler(w, r)
+	}
+}
+
+`)
+}
+
+// generateGinAdapter writes RegisterGinHandlers for github.com/gin-gonic/gin routers
+func generateGinAdapter(out *bytes.Buffer, operations []*operation) {
+	out.WriteString(`// RegisterGinHandlers registers the operations of si on a gin router
+func RegisterGinHandlers(router gin.IRoutes, si ServerInterface) {
+	RegisterGinHandlersWithOptions(router, si, HandlerOptions{})
+}
+
+// RegisterGinHandlersWithOptions registers the operations of si on a gin router
+func RegisterGinHandlersWithOptions(router gin.IRoutes, si ServerInterface, options HandlerOptions) {
+	wrapper := &ServerInterfaceWrapper{Handler: si, ErrorHandlerFunc: options.ErrorHandlerFunc}
+`)
+	for _, op := range operations {
+		fmt.Fprintf(out, "\trouter.Handle(%q, options.BaseURL+%q, ginHandler(wrapper.%s", op.Method, colonPath(op.Path), op.name)
+		if names := pathWildcards(op.Path); len(names) > 0 {
+			out.WriteString(", " + quotedList(names))
+		}
+		out.WriteString("))\n")
+	}
+	out.WriteString(`}
+
+// ginHandler adapts handler to gin, making the gin path parameters available as path values
+func ginHandler(handler http.HandlerFunc, names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range names {
+			c.Request.SetPathValue(name, c.Param(name))
+		}
+		handler(c.Writer, c.Request)
+	}
+}
+
+`)
+}
+
+// generateEchoAdapter writes RegisterEchoHandlers for github.com/labstack/echo/v4 routers
+func generateEchoAdapter(out *bytes.Buffer, operations []*operation) {
+	out.WriteString(`// EchoRouter is the part of *echo.Echo and *echo.Group the operations are registered on
+type EchoRouter interface {
+	Add(method string, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterEchoHandlers registers the operations of si on an echo router
+func RegisterEchoHandlers(router EchoRouter, si ServerInterface) {
+	RegisterEchoHandlersWithOptions(router, si, HandlerOptions{})
+}
+
+// RegisterEchoHandlersWithOptions registers the operations of si on an echo router
+func RegisterEchoHandlersWithOptions(router EchoRouter, si ServerInterface, options HandlerOptions) {
+	wrapper := &ServerInterfaceWrapper{Handler: si, ErrorHandlerFunc: options.ErrorHandlerFunc}
+`)
+	for _, op := range operations {
+		fmt.Fprintf(out, "\trouter.Add(%q, options.BaseURL+%q, echoHandler(wrapper.%s", op.Method, colonPath(op.Path), op.name)
+		if names := pathWildcards(op.Path); len(names) > 0 {
+			out.WriteString(", " + quotedList(names))
+		}
+		out.WriteString("))\n")
+	}
+	out.WriteString(`}
+
+// echoHandler adapts handler to echo, making the echo path parameters available as path values
+func echoHandler(handler http.HandlerFunc, names ...string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		for _, name := range names {
+			c.Request().SetPathValue(name, c.Param(name))
+		}
+		handler(c.Response(), c.Request())
+		return nil
+	}
+}
+
+`)
+}
+
+// addImports adds import paths to the import declaration of a Go source file. goimports
+// cannot be relied on to resolve third-party packages, which may not be in the module cache.
+func addImports(source []byte, paths ...string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	for _, path := range paths {
+		astutil.AddImport(fset, file, path)
+	}
+
+	var out bytes.Buffer
+	if err := printer.Fprint(&out, fset, file); err != nil {
+		return nil, fmt.Errorf("failed to print generated code: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -2,12 +2,16 @@
 package openapi
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"strings"
 
 	"github.com/inference-gateway/tools/codegen"
 	"github.com/inference-gateway/tools/codegen/jrpc"
+	"golang.org/x/tools/imports"
 )
 
 // OpenAPIGenerator implements the Generator interface for OpenAPI schemas
@@ -36,29 +40,151 @@ type Options struct {
 	// IncludeComments determines whether to generate comments from descriptions
 	IncludeComments bool
 
-	// FormatOutput determines whether to run go fmt on the output
+	// FormatOutput determines whether to gofmt the output
 	FormatOutput bool
 
+	// ExtensionComments determines whether to write the vendor extensions (x-*) the generator
+	// does not interpret, on schemas, properties and operations, as comments
+	// (// x-rate-limit: 100)
+	ExtensionComments bool
+
 	// GenerateModels determines whether to generate model structs
 	GenerateModels bool
 
-	// GenerateClient determines whether to generate client code (future feature)
+	// ExampleFactories determines whether to generate ExampleX and FakeX functions returning
+	// the models populated with sample data
+	ExampleFactories bool
+
+	// ContractTests receives a _test.go file checking every model against the examples,
+	// required properties, enums and formats of its schema, when set
+	ContractTests io.Writer
+
+	// FuzzTests receives a _test.go file with a FuzzXUnmarshal target per model with an
+	// UnmarshalJSON method, when set and there is one
+	FuzzTests io.Writer
+
+	// BenchmarkTypes are glob patterns of the models whose encoding and decoding
+	// BenchmarkTests receives the BenchmarkMarshalX and BenchmarkUnmarshalX benchmarks of
+	BenchmarkTypes []string
+	BenchmarkTests io.Writer
+
+	// GenerateClient determines whether to generate a Client with one method per operation,
+	// and ClientOptions applying the credentials of the document's security schemes
 	GenerateClient bool
+
+	// GenerateServer determines whether to generate a ServerInterface with net/http handlers
+	// for the operations
+	GenerateServer bool
+
+	// ServerFramework is the router the server operations are registered on in addition to
+	// http.ServeMux: "stdlib" (default), "chi", "gin" or "echo". Setting it implies
+	// GenerateServer.
+	ServerFramework string
+
+	// StrictServer determines whether to generate a StrictServerInterface, whose handlers
+	// return one of the typed responses declared for their operation, and the shim serving it
+	// as a ServerInterface. Setting it implies GenerateServer.
+	StrictServer bool
+
+	// ValidationMiddleware determines whether to generate ValidateRequests, a net/http
+	// middleware checking the parameters and JSON request bodies of the operations against
+	// their schemas and answering invalid requests with RFC 7807 problems. Setting it implies
+	// GenerateServer.
+	ValidationMiddleware bool
+
+	// MockServer determines whether to generate MockServer, a ServerInterface answering the
+	// operations with the examples of their responses or data generated from their schemas,
+	// with per-operation hooks, and StartMockServer serving it in process for integration
+	// tests. Setting it implies GenerateServer.
+	MockServer bool
+
+	// IncludeTags and IncludeOperations select the operations code is generated for: those
+	// with one of the tags, or whose operationId matches one of the glob patterns (default:
+	// all operations)
+	IncludeTags       []string
+	IncludeOperations []string
+
+	// ExcludePaths are glob patterns of the paths whose operations are skipped, together with
+	// the paths below them (e.g. "/admin" or "/v1/*/internal")
+	ExcludePaths []string
 }
 
 // Generate processes the OpenAPI schema and generates Go code
 func (g *OpenAPIGenerator) Generate(config codegen.GenerateConfig) error {
-	var options *Options
+	options, _ := config.Options.(*Options)
+	options, err := prepareOptions(options, config.PackageName)
+	if err != nil {
+		return err
+	}
+
+	document, err := LoadDocument(config.SchemaPath)
+	if err != nil {
+		return err
+	}
 
-	if config.Options != nil {
-		if opts, ok := config.Options.(*Options); ok {
-			options = opts
+	jrpcOptions := options.modelOptions()
+	if !options.filtered() && !options.GenerateServer && !options.GenerateClient {
+		return jrpc.GenerateTypes(config.OutputPath, config.SchemaPath, jrpcOptions)
+	}
+
+	source, err := generateSource(config.OutputPath, document, options, jrpcOptions)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(config.OutputPath, source, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// GenerateTo generates the Go code of the contents of an OpenAPI document, in JSON or YAML,
+// and writes it to w, so that code can be generated in memory, such as from a go:embed'ed
+// document. A nil options generates the models only.
+func GenerateTo(w io.Writer, schema []byte, options *Options) error {
+	options, err := prepareOptions(options, "")
+	if err != nil {
+		return err
+	}
+
+	document, err := ParseDocument(schema)
+	if err != nil {
+		return err
+	}
+
+	jrpcOptions := options.modelOptions()
+	var source []byte
+	if !options.filtered() && !options.GenerateServer && !options.GenerateClient {
+		out := new(bytes.Buffer)
+		if err := jrpc.GenerateTypesTo(out, schema, jrpcOptions); err != nil {
+			return err
 		}
+		source = out.Bytes()
+	} else if source, err = generateSource(options.PackageName+".go", document, options, jrpcOptions); err != nil {
+		return err
 	}
+	if _, err := w.Write(source); err != nil {
+		return fmt.Errorf("failed to write generated code: %w", err)
+	}
+	return nil
+}
 
+// GenerateFS is GenerateTo for an OpenAPI document file of fsys, such as the embed.FS of
+// go:embed'ed documents. Schema errors are located in the file.
+func GenerateFS(w io.Writer, fsys fs.FS, name string, options *Options) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+	return codegen.LocateData(GenerateTo(w, data, options), name, data)
+}
+
+// prepareOptions fills in the defaults of options, naming the package packageName when given,
+// applies the options implying GenerateServer, and validates them. A nil options generates
+// the models only.
+func prepareOptions(options *Options, packageName string) (*Options, error) {
 	if options == nil {
 		options = &Options{
-			PackageName:     config.PackageName,
+			PackageName:     packageName,
 			IncludeComments: true,
 			FormatOutput:    true,
 			GenerateModels:  true,
@@ -66,36 +192,146 @@ func (g *OpenAPIGenerator) Generate(config codegen.GenerateConfig) error {
 		}
 	}
 
-	if config.PackageName != "" {
-		options.PackageName = config.PackageName
+	if packageName != "" {
+		options.PackageName = packageName
+	}
+
+	if err := validateServerFramework(options.ServerFramework); err != nil {
+		return nil, err
+	}
+	if options.ServerFramework != "" && options.ServerFramework != frameworkStdlib {
+		options.GenerateServer = true
+	}
+	if options.StrictServer || options.ValidationMiddleware || options.MockServer {
+		options.GenerateServer = true
+	}
+	return options, nil
+}
+
+// modelOptions returns the options of the JSON-RPC generator, which generates the models
+// from components/schemas, since they are JSON Schema
+func (o *Options) modelOptions() *jrpc.GeneratorOptions {
+	return &jrpc.GeneratorOptions{
+		PackageName:       o.PackageName,
+		IncludeComments:   o.IncludeComments,
+		FormatOutput:      o.FormatOutput,
+		ExampleFactories:  o.ExampleFactories,
+		ContractTests:     o.ContractTests,
+		FuzzTests:         o.FuzzTests,
+		BenchmarkTypes:    o.BenchmarkTypes,
+		BenchmarkTests:    o.BenchmarkTests,
+		ExtensionComments: o.ExtensionComments,
+	}
+}
+
+// generateSource returns the Go code of the models, operations, client and server of a
+// document, filtered by options. filename is the name of the generated file goimports resolves
+// the imports for.
+func generateSource(filename string, document *Document, options *Options, jrpcOptions *jrpc.GeneratorOptions) ([]byte, error) {
+	// Filtering the operations also leaves out the models they do not use
+	if options.filtered() {
+		if err := filterOperations(document, options); err != nil {
+			return nil, err
+		}
+		if !options.GenerateServer && !options.GenerateClient {
+			return generateModels(document, jrpcOptions)
+		}
+	}
+
+	operations, err := newOperations(document, jrpcOptions, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The models include the schemas hoisted from the operations
+	schemas, err := json.Marshal(map[string]any{"components": map[string]any{"schemas": document.Components.Schemas}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schemas: %w", err)
+	}
+	jrpcOptions.FormatOutput = false
+	jrpcOptions.ReservedNames = append(jrpcOptions.ReservedNames, helperNames(operations, options)...)
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schemas, jrpcOptions); err != nil {
+		return nil, err
 	}
 
-	// For now, delegate to the JSONRPC generator since OpenAPI schemas
-	// are compatible with JSON Schema for the components/schemas section
-	jrpcOptions := &jrpc.GeneratorOptions{
-		PackageName:     options.PackageName,
-		IncludeComments: options.IncludeComments,
-		FormatOutput:    options.FormatOutput,
+	withCallbacks := append(operations, callbackOperations(operations)...)
+	generateParamsStructs(out, withCallbacks, options)
+	generateParamsCodecs(out, withCallbacks)
+	if options.GenerateServer {
+		if err := generateServer(out, document, operations, options); err != nil {
+			return nil, err
+		}
 	}
+	if options.GenerateClient {
+		generateClient(out, document, operations, options)
+	}
+	generateCallbacks(out, operations, options)
+	out.WriteString(parameterHelpers)
 
-	return jrpc.GenerateTypes(config.OutputPath, config.SchemaPath, jrpcOptions)
+	source := out.Bytes()
+	if path, ok := frameworkImports[options.ServerFramework]; ok {
+		if source, err = addImports(source, path); err != nil {
+			return nil, err
+		}
+	}
+
+	// goimports adds the standard library packages the operations code uses to the import
+	// declaration of the models
+	source, err = imports.Process(filename, source, nil)
+	if err != nil {
+		return nil, &codegen.FormatError{Name: "generated code", Err: err}
+	}
+	return source, nil
 }
 
-// ValidateSchema validates the OpenAPI schema
-func (g *OpenAPIGenerator) ValidateSchema(schemaPath string) error {
-	data, err := os.ReadFile(schemaPath)
+// helperNames returns the unexported package-level identifiers of the operations code, which
+// the models are renamed away from: its helpers and the package of the server framework
+func helperNames(operations []*operation, options *Options) []string {
+	names := jrpc.DeclaredNames(parameterHelpers + clientHelpers + errorHelpers + clientCredentialsHelper +
+		serverSentEventsHelper + jsonLinesHelper + streamHelpers + pagerHelpers + linkPagerHelper + validationHelpers)
+	names = append(names, "chiHandler", "echoHandler", "ginHandler", "requestSchemaDocument", "strictHandler", "writeMockResponse")
+	for _, op := range operations {
+		names = append(names, "decode"+op.name+"Error")
+	}
+	if _, ok := frameworkImports[options.ServerFramework]; ok {
+		names = append(names, options.ServerFramework)
+	}
+	return names
+}
+
+// generateModels returns the models of the component schemas of a document
+func generateModels(document *Document, options *jrpc.GeneratorOptions) ([]byte, error) {
+	schemas, err := json.Marshal(map[string]any{"components": map[string]any{"schemas": document.Components.Schemas}})
 	if err != nil {
-		return fmt.Errorf("failed to read schema file: %w", err)
+		return nil, fmt.Errorf("failed to encode schemas: %w", err)
+	}
+	out := new(bytes.Buffer)
+	if err := jrpc.GenerateTypesTo(out, schemas, options); err != nil {
+		return nil, err
 	}
+	return out.Bytes(), nil
+}
 
-	content := string(data)
-	if !strings.Contains(content, "openapi") && !strings.Contains(content, "swagger") {
-		return fmt.Errorf("file does not appear to be an OpenAPI specification")
+// ValidateSchema validates the OpenAPI schema
+func (g *OpenAPIGenerator) ValidateSchema(schemaPath string) error {
+	if _, err := LoadDocument(schemaPath); err != nil {
+		return err
 	}
 
 	return jrpc.ValidateSchema(schemaPath)
 }
 
+// ValidateSchemaFS is ValidateSchema for an OpenAPI document file of fsys, such as the
+// embed.FS of go:embed'ed documents
+func ValidateSchemaFS(fsys fs.FS, name string) error {
+	if _, err := LoadDocumentFS(fsys, name); err != nil {
+		return err
+	}
+
+	return jrpc.ValidateSchemaFS(fsys, name)
+}
+
 // NewOpenAPIGenerator creates a new instance of the OpenAPI generator
 func NewOpenAPIGenerator() *OpenAPIGenerator {
 	return &OpenAPIGenerator{}
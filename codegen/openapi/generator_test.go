@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		options *Options
+	}{
+		{"models", "tasks.yaml", &Options{FormatOutput: true}},
+		{"client_server", "tasks.yaml", &Options{GenerateClient: true, GenerateServer: true}},
+		{"strict_server", "tasks.yaml", &Options{StrictServer: true}},
+		{"mock_server", "tasks.yaml", &Options{GenerateClient: true, MockServer: true}},
+		{"validation_middleware", "tasks.yaml", &Options{ValidationMiddleware: true}},
+		{"filtered", "tasks.yaml", &Options{GenerateClient: true, IncludeOperations: []string{"getTask"}}},
+		{"params", "params.yaml", &Options{GenerateClient: true, GenerateServer: true}},
+		{"stream", "stream.yaml", &Options{GenerateClient: true, GenerateServer: true}},
+		{"swagger", "swagger.yaml", &Options{GenerateClient: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "api.go")
+			err := NewOpenAPIGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", test.schema),
+				OutputPath:  output,
+				PackageName: "generated",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".go.golden"), source)
+			gentest.Vet(t, map[string]string{"api.go": string(source)})
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		options  *Options
+		err      string
+	}{
+		{"not a document", `[1, 2]`, nil, "cannot unmarshal"},
+		{"unknown framework", "openapi: 3.1.0\ninfo: {title: t, version: '1'}\npaths: {}\n", &Options{ServerFramework: "martini"}, "martini"},
+		{"missing reference", "openapi: 3.1.0\ninfo: {title: t, version: '1'}\npaths:\n  /a:\n    get:\n      responses:\n        '200': {$ref: '#/components/responses/Missing'}\n", &Options{GenerateClient: true}, "Missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := GenerateTo(new(strings.Builder), []byte(test.document), test.options)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("error = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"tasks.yaml", true},
+		{"swagger.yaml", true},
+		{"jsonschema.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewOpenAPIGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
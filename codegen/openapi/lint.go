@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Lint checks an OpenAPI document for issues that degrade the generated code: operations
+// without operationId or description, inline object schemas in parameters, request bodies and
+// responses, and the issues of the component schemas (see jrpc.LintDefinitions). The findings
+// have the default severities of jrpc.LintSeverities.
+func Lint(document *Document) []jrpc.Finding {
+	var findings []jrpc.Finding
+	for _, op := range document.Operations() {
+		pointer := operationPointer(op)
+		name := operationName(op, nil)
+		if op.OperationID == "" {
+			findings = append(findings, lintFinding(jrpc.RuleMissingOperationID, pointer, "operation %s %s has no operationId and is named %s after its method and path", op.Method, op.Path, name))
+		}
+		if op.Summary == "" && op.Description == "" {
+			findings = append(findings, lintFinding(jrpc.RuleMissingDescription, pointer, "operation %s has no summary or description", name))
+		}
+
+		for _, parameter := range op.Parameters {
+			findings = append(findings, lintInlineSchema(pointer+"/parameters/"+parameter.In+"/"+escapePointer(parameter.Name), name+jrpc.GoIdentifier(parameter.Name, nil), parameter.Schema)...)
+		}
+		if op.RequestBody != nil {
+			for _, mediaType := range sortedKeys(op.RequestBody.Content) {
+				findings = append(findings, lintInlineSchema(pointer+"/requestBody/content/"+escapePointer(mediaType)+"/schema", name+"RequestBody", op.RequestBody.Content[mediaType].Schema)...)
+			}
+		}
+		for _, code := range sortedKeys(op.Responses) {
+			for _, mediaType := range sortedKeys(op.Responses[code].Content) {
+				findings = append(findings, lintInlineSchema(pointer+"/responses/"+code+"/content/"+escapePointer(mediaType)+"/schema", name+"Response", op.Responses[code].Content[mediaType].Schema)...)
+			}
+		}
+	}
+
+	return append(findings, jrpc.LintDefinitions("/components/schemas/", document.Components.Schemas)...)
+}
+
+// lintInlineSchema checks the schema of a parameter, request body or response, reporting it
+// when it is an inline object, which is generated as a type named after the operation
+func lintInlineSchema(pointer string, name string, schema map[string]any) []jrpc.Finding {
+	if schema == nil {
+		return nil
+	}
+	var findings []jrpc.Finding
+	if _, isRef := schema["$ref"]; !isRef && schema["properties"] != nil {
+		findings = append(findings, lintFinding(jrpc.RuleAnonymousObject, pointer, "inline object is generated as a type named after the operation; declare it as a component schema to name it"))
+	}
+	return append(findings, jrpc.LintSchema(pointer, name, schema)...)
+}
+
+// lintFinding returns a finding of a rule, with the rule's default severity
+func lintFinding(rule string, path string, format string, args ...any) jrpc.Finding {
+	return jrpc.Finding{Rule: rule, Severity: jrpc.LintSeverities[rule], Path: path, Message: fmt.Sprintf(format, args...)}
+}
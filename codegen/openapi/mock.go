@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// generateMockServer writes MockServer, a ServerInterface answering each operation with the
+// example of its first success response, or with data generated from the response schema when
+// there is none, and StartMockServer serving it in process. The responses are computed when
+// generating, so mock answers are the same on every request; a response offering a stream
+// besides JSON answers with the stream when the request accepts it.
+func generateMockServer(out *bytes.Buffer, document *Document, operations []*operation) error {
+	out.WriteString("// MockServer implements ServerInterface with canned responses: each operation answers with\n")
+	out.WriteString("// the example of its first success response, or with data generated from its schema. Set the\n")
+	out.WriteString("// <Operation>Func hooks to answer operations differently.\n")
+	out.WriteString("type MockServer struct {\n")
+	for _, op := range operations {
+		fmt.Fprintf(out, "\t%sFunc func(%s) // Answers %s %s instead of the canned response when set\n", op.name, handlerSignature(op), op.Method, op.Path)
+	}
+	out.WriteString("}\n\n")
+
+	for _, op := range operations {
+		status, contentType, body, err := mockResponse(op, document.Components.Schemas)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+		fmt.Fprintf(out, "// %s answers %s %s with %sFunc, or with a %d response\n", op.name, op.Method, op.Path, op.name, status)
+		fmt.Fprintf(out, "func (m *MockServer) %s(%s) {\n", op.name, handlerSignature(op))
+		fmt.Fprintf(out, "\tif m.%sFunc != nil {\n\t\tm.%sFunc(%s)\n\t\treturn\n\t}\n", op.name, op.name, handlerArguments(op))
+		streamType, streamBody, err := mockStreamResponse(op, document.Components.Schemas)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+		if streamType != "" {
+			fmt.Fprintf(out, "\tif strings.Contains(r.Header.Get(\"Accept\"), %q) {\n", strings.TrimSpace(strings.SplitN(streamType, ";", 2)[0]))
+			fmt.Fprintf(out, "\t\twriteMockResponse(w, %d, %q, %s)\n\t\treturn\n\t}\n", status, streamType, strconv.Quote(streamBody))
+		}
+		fmt.Fprintf(out, "\twriteMockResponse(w, %d, %q, %s)\n}\n\n", status, contentType, strconv.Quote(body))
+	}
+
+	out.WriteString(`// StartMockServer starts an HTTP server serving mock on a local address, for integration
+// tests: its URL is the Server of a Client. Close it when done.
+func StartMockServer(mock *MockServer) *httptest.Server {
+	mux := http.NewServeMux()
+	RegisterHandlers(mux, mock)
+	return httptest.NewServer(mux)
+}
+
+// writeMockResponse writes a canned response
+func writeMockResponse(w http.ResponseWriter, status int, contentType string, body string) {
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	io.WriteString(w, body)
+}
+
+`)
+	return nil
+}
+
+// mockResponse returns the status code, media type and body of the canned response of an
+// operation: its first success response (2xx, 2XX or default), in JSON when it has a JSON
+// media type. Streaming responses are framed as a single event, server-sent or a JSON line.
+// Binary bodies are left empty. Next page cursors are left out of sampled bodies, so that
+// the pagers of a client stop after the first page.
+func mockResponse(op *operation, schemas map[string]any) (int, string, string, error) {
+	status, definition := mockStatus(op)
+	if definition == nil {
+		return status, "", "", nil
+	}
+	contentType, media := jsonMediaType(definition.Content)
+	if media == nil {
+		keys := sortedKeys(definition.Content)
+		if len(keys) == 0 {
+			return status, "", "", nil
+		}
+		contentType, media = keys[0], definition.Content[keys[0]]
+	}
+	contentType, body, err := mockBody(contentType, media, schemas)
+	return status, contentType, body, err
+}
+
+// mockStreamResponse returns the streaming media type of the canned response of an operation
+// and its body, when the response offers one besides the media type of mockResponse, for the
+// clients asking for it in their Accept header. It returns "" otherwise.
+func mockStreamResponse(op *operation, schemas map[string]any) (string, string, error) {
+	_, definition := mockStatus(op)
+	if definition == nil {
+		return "", "", nil
+	}
+	if _, media := jsonMediaType(definition.Content); media == nil {
+		return "", "", nil
+	}
+	for _, contentType := range sortedKeys(definition.Content) {
+		if _, ok := streamMediaTypes[strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])]; ok {
+			return mockBody(contentType, definition.Content[contentType], schemas)
+		}
+	}
+	return "", "", nil
+}
+
+// mockStatus returns the first success response of an operation (2xx, 2XX or default), or its
+// first response, with its status code; the response is nil when the operation has none
+func mockStatus(op *operation) (int, *Response) {
+	codes := op.SortedStatusCodes()
+	if len(codes) == 0 {
+		return 200, nil
+	}
+	code := codes[0]
+	for _, candidate := range codes {
+		if strings.HasPrefix(candidate, "2") || candidate == "default" {
+			code = candidate
+			break
+		}
+	}
+	status := 200
+	if number, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(code), "XX", "00")); err == nil {
+		status = number
+	}
+	return status, op.Responses[code]
+}
+
+// mockBody returns the media type and body of a canned response of a media type
+func mockBody(contentType string, media *MediaType, schemas map[string]any) (string, string, error) {
+	value := mockMediaValue(media, schemas)
+	if strings.Contains(contentType, "*") {
+		contentType = "application/octet-stream"
+	}
+
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	if sse, ok := streamMediaTypes[mediaType]; ok {
+		if value == nil && sse {
+			// Events without a schema are strings
+			value = jrpc.SampleValue(map[string]any{"type": "string"}, schemas, jrpc.SampleOptions{})
+		}
+		event, err := mockStreamEvent(value)
+		if err != nil {
+			return "", "", err
+		}
+		if sse {
+			return contentType, "data: " + strings.ReplaceAll(event, "\n", "\ndata: ") + "\n\n", nil
+		}
+		return contentType, event + "\n", nil
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode the mock response: %w", err)
+		}
+		return contentType, string(data), nil
+	case strings.HasPrefix(mediaType, "text/"):
+		if text, ok := value.(string); ok {
+			return contentType, text, nil
+		}
+		if value == nil {
+			return contentType, "", nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode the mock response: %w", err)
+		}
+		return contentType, string(data), nil
+	}
+	return contentType, "", nil
+}
+
+// mockStreamEvent returns the text of the single event of a mock streaming response: a
+// string as it is, as the generated clients keep the text of string events, or else JSON
+func mockStreamEvent(value any) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the mock response: %w", err)
+	}
+	return string(data), nil
+}
+
+// mockMediaValue returns the example of a media type, the first of its named examples, or a
+// value generated from its item schema, for the events of a stream, or its schema
+func mockMediaValue(media *MediaType, schemas map[string]any) any {
+	if media.Example != nil {
+		return media.Example
+	}
+	for _, name := range sortedKeys(media.Examples) {
+		if example := media.Examples[name]; example != nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	schema := media.ItemSchema
+	if schema == nil {
+		schema = media.Schema
+	}
+	if schema == nil {
+		return nil
+	}
+	return jrpc.SampleValue(schema, schemas, jrpc.SampleOptions{Examples: true, Response: true, OmitProperties: cursorProperties})
+}
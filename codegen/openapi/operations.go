@@ -0,0 +1,568 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// operation is an API operation together with the Go names and types code is generated with
+type operation struct {
+	*Operation
+	name   string      // Go name (e.g. "ListTasks")
+	params []parameter // Path, query, header and cookie parameters, in declaration order
+	body   *body       // JSON request body, nil when the operation takes none
+
+	responses []response      // Response variants, only collected for strict servers
+	stream    *stream         // Streaming success response, only collected for clients
+	errors    []errorResponse // Error responses, only collected for clients
+	pager     *pager          // Pagination of list operations, only collected for clients
+
+	callbacks  []*operation // Requests the API sends back, with the runtime expression of their URL as Path
+	callbackOf string       // Go name of the operation declaring the callback, empty for API operations
+}
+
+// parameter is a path, query, header or cookie parameter of an operation
+type parameter struct {
+	*Parameter
+	fieldName    string         // Go field name in the operation's Params struct
+	goType       string         // Go type of the value
+	itemType     string         // Go type of the elements of array parameters, empty otherwise
+	schema       map[string]any // Schema of the value
+	style        string         // Serialization style, defaulted from the location
+	explode      bool
+	defaultValue string // Go literal of the schema default, empty when there is none
+}
+
+// body is the JSON request body of an operation
+type body struct {
+	goType      string         // Go type the body is decoded into
+	contentType string         // Media type of the body (e.g. "application/json")
+	schema      map[string]any // Schema of the body
+	required    bool
+}
+
+// response is one variant of the responses of an operation: a status code with one of its
+// media types, or with no body
+type response struct {
+	typeName    string           // Go type (e.g. "GetTask200JSONResponse")
+	statusCode  string           // "200", "4XX" or "default"
+	contentType string           // Media type of the body, empty when the response has none
+	kind        string           // Body encoding: "json", "text" or "binary"; empty without body
+	bodyType    string           // Go type of the body
+	headers     []responseHeader // Headers of the status code, shared by its media types
+	headersType string           // Go type of the struct holding the headers
+}
+
+// responseHeader is a header of a response
+type responseHeader struct {
+	name      string
+	fieldName string // Go field name in the headers struct
+	goType    string // Go type of the value
+	itemType  string // Go type of the elements of array headers, empty otherwise
+	required  bool
+}
+
+// errorResponse is an error response of an operation, which clients return as an error of
+// its own type
+type errorResponse struct {
+	typeName   string // Go type (e.g. "GetTaskNotFoundError")
+	statusCode string // "404", "4XX" or "default"
+	bodyType   string // Go type of the JSON body, empty when the response has none
+}
+
+// stream is a success response of an operation streaming a sequence of events
+type stream struct {
+	goType      string // Go type of the events
+	contentType string // Media type of the response (e.g. "text/event-stream")
+	sse         bool   // Server-sent events, rather than newline-delimited JSON
+}
+
+// streamMediaTypes are the streaming media types, by whether they are server-sent events
+var streamMediaTypes = map[string]bool{
+	"text/event-stream":    true,
+	"application/x-ndjson": false,
+	"application/jsonl":    false,
+}
+
+// fixedStatus reports whether the response has a single status code, as opposed to a range
+// (4XX) or "default", whose code is chosen by the handler
+func (r response) fixedStatus() bool {
+	_, err := strconv.Atoi(r.statusCode)
+	return err == nil
+}
+
+// ignoredHeaders are the header parameters OpenAPI ignores, as other fields of the document
+// describe them
+var ignoredHeaders = map[string]bool{"Accept": true, "Content-Type": true, "Authorization": true}
+
+// parameterStyle returns the serialization style of a parameter and whether it explodes,
+// applying the defaults of its location: form for query and cookie parameters, which explode
+// by default, simple for path and header parameters
+func parameterStyle(param *Parameter) (string, bool) {
+	style := param.Style
+	if style == "" {
+		style = "simple"
+		if param.In == "query" || param.In == "cookie" {
+			style = "form"
+		}
+	}
+	if param.Explode != nil {
+		return style, *param.Explode
+	}
+	return style, style == "form"
+}
+
+// paramsType returns the name of the struct holding the parameters of the operation
+func (o *operation) paramsType() string {
+	return o.name + "Params"
+}
+
+// typeMapper gives Go types to the schemas of operations. Schemas that need a named type
+// (inline objects, unions, ...) are hoisted into the document's components/schemas, so that
+// the models generated from them include a type for them.
+type typeMapper struct {
+	schemas map[string]any
+	options *jrpc.GeneratorOptions
+}
+
+// newOperations returns the operations of a document with their Go names and types,
+// hoisting the inline schemas they need named types for into the document's components
+// plus the response variants of strict servers and the streaming responses of clients when
+// the generator options ask for them
+func newOperations(document *Document, options *jrpc.GeneratorOptions, generator *Options) ([]*operation, error) {
+	if document.Components.Schemas == nil {
+		document.Components.Schemas = make(map[string]any)
+	}
+	mapper := &typeMapper{schemas: document.Components.Schemas, options: options}
+
+	var operations []*operation
+	names := make(map[string]string)
+	for _, op := range document.Operations() {
+		name := operationName(op, options)
+		if other, taken := names[name]; taken {
+			return nil, fmt.Errorf("%s %s and %s are both named %s: set distinct operationIds", op.Method, op.Path, other, name)
+		}
+		names[name] = op.Method + " " + op.Path
+
+		generated := mapper.newOperation(op, name, false)
+		if generator.StrictServer {
+			generated.responses = mapper.responses(op, name)
+		}
+		if generator.GenerateClient {
+			generated.stream = mapper.stream(op, name)
+			generated.errors = mapper.errorResponses(op, name, generated.responses)
+			generated.pager = mapper.pager(generated)
+		}
+		operations = append(operations, generated)
+	}
+
+	for _, generated := range operations {
+		for _, callbackName := range sortedKeys(generated.Callbacks) {
+			callback := generated.Callbacks[callbackName]
+			var callbackOperations []*Operation
+			for _, expression := range sortedKeys(callback.PathItems) {
+				for _, method := range httpMethods {
+					if op := callback.PathItems[expression].Operation(method); op != nil {
+						callbackOperations = append(callbackOperations, op)
+					}
+				}
+			}
+			for _, op := range callbackOperations {
+				name := jrpc.GoIdentifier(generated.name+"_"+callbackName, options)
+				switch {
+				case op.OperationID != "":
+					name = jrpc.GoIdentifier(op.OperationID, options)
+				case len(callbackOperations) > 1:
+					name += jrpc.GoIdentifier(strings.ToLower(op.Method), options)
+				}
+				if other, taken := names[name]; taken {
+					return nil, fmt.Errorf("%s %s callback of %s and %s are both named %s: set distinct operationIds", op.Method, op.Path, generated.name, other, name)
+				}
+				names[name] = op.Method + " " + op.Path
+
+				callbackOperation := mapper.newOperation(op, name, true)
+				callbackOperation.callbackOf = generated.name
+				generated.callbacks = append(generated.callbacks, callbackOperation)
+			}
+		}
+	}
+	return operations, nil
+}
+
+// newOperation returns an operation named name with the Go types of its parameters and JSON
+// request body. Callback operations have no path parameters, as their whole URL is given.
+func (m *typeMapper) newOperation(op *Operation, name string, callback bool) *operation {
+	generated := &operation{Operation: op, name: name}
+	fieldNames := make(map[string]bool)
+	for _, param := range op.Parameters {
+		if param.In == "header" && ignoredHeaders[http.CanonicalHeaderKey(param.Name)] || param.In == "path" && callback {
+			continue
+		}
+		fieldName := jrpc.GoIdentifier(param.Name, m.options)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = jrpc.GoIdentifier(param.Name, m.options) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		schema := param.Schema
+		if schema == nil {
+			if _, media := jsonMediaType(param.Content); media != nil && media.Schema != nil {
+				schema = media.Schema
+			} else {
+				schema = map[string]any{"type": "string"}
+			}
+		}
+		hint := name + fieldName + "Param"
+		p := parameter{Parameter: param, fieldName: fieldName, goType: strings.TrimPrefix(m.goType(schema, hint), "*"), schema: schema}
+		if strings.HasPrefix(p.goType, "[]") {
+			p.itemType = strings.TrimPrefix(p.goType, "[]")
+		}
+		p.style, p.explode = parameterStyle(param)
+		if !param.Required {
+			p.defaultValue = m.defaultLiteral(schema, p.goType)
+		}
+		generated.params = append(generated.params, p)
+	}
+
+	if op.RequestBody != nil {
+		if contentType, media := jsonMediaType(op.RequestBody.Content); media != nil {
+			schema := media.Schema
+			if schema == nil {
+				schema = map[string]any{}
+			}
+			generated.body = &body{
+				goType:      m.goType(schema, name+"RequestBody"),
+				contentType: contentType,
+				schema:      schema,
+				required:    op.RequestBody.Required,
+			}
+		}
+	}
+	return generated
+}
+
+// callbackOperations returns the callback operations of operations, in order
+func callbackOperations(operations []*operation) []*operation {
+	var callbacks []*operation
+	for _, op := range operations {
+		callbacks = append(callbacks, op.callbacks...)
+	}
+	return callbacks
+}
+
+// responses returns the response variants of an operation, by status code and media type
+func (m *typeMapper) responses(op *Operation, name string) []response {
+	var responses []response
+	for _, code := range op.SortedStatusCodes() {
+		definition := op.Responses[code]
+		prefix := name + strings.ToUpper(code)
+		if code == "default" {
+			prefix = name + "Default"
+		}
+
+		var headers []responseHeader
+		for _, header := range sortedKeys(definition.Headers) {
+			if strings.EqualFold(header, "Content-Type") {
+				continue // Ignored by OpenAPI: it comes from the media type
+			}
+			schema := definition.Headers[header].Schema
+			if schema == nil {
+				schema = map[string]any{"type": "string"}
+			}
+			fieldName := jrpc.GoIdentifier(header, m.options)
+			h := responseHeader{
+				name:      header,
+				fieldName: fieldName,
+				goType:    strings.TrimPrefix(m.goType(schema, prefix+fieldName+"Header"), "*"),
+				required:  definition.Headers[header].Required,
+			}
+			if strings.HasPrefix(h.goType, "[]") {
+				h.itemType = strings.TrimPrefix(h.goType, "[]")
+			}
+			headers = append(headers, h)
+		}
+		variant := response{statusCode: code, headers: headers}
+		if len(headers) > 0 {
+			variant.headersType = prefix + "ResponseHeaders"
+		}
+
+		if len(definition.Content) == 0 {
+			variant.typeName = prefix + "Response"
+			responses = append(responses, variant)
+			continue
+		}
+		for _, contentType := range sortedKeys(definition.Content) {
+			variant := variant
+			variant.contentType = contentType
+			variant.typeName = prefix + mediaTypeName(contentType) + "Response"
+			switch mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]); {
+			case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+				variant.kind = "json"
+				schema := definition.Content[contentType].Schema
+				if schema == nil {
+					schema = map[string]any{}
+				}
+				variant.bodyType = m.goType(schema, prefix+mediaTypeName(contentType)+"ResponseBody")
+			case strings.HasPrefix(mediaType, "text/"):
+				variant.kind = "text"
+				variant.bodyType = "string"
+			default:
+				variant.kind = "binary"
+				variant.bodyType = "io.Reader"
+			}
+			responses = append(responses, variant)
+		}
+	}
+	return responses
+}
+
+// errorResponses returns the error responses of an operation: those of 4xx and 5xx status
+// codes and ranges, and the default response. Their JSON bodies share the types of the strict
+// server responses when those were collected.
+func (m *typeMapper) errorResponses(op *Operation, name string, responses []response) []errorResponse {
+	var errors []errorResponse
+	for _, code := range op.SortedStatusCodes() {
+		status, err := strconv.Atoi(code)
+		var typeName string
+		switch {
+		case code == "default":
+			typeName = name + "DefaultError"
+		case strings.HasPrefix(code, "4"), strings.HasPrefix(code, "5"):
+			typeName = name + strings.ToUpper(code) + "Error"
+			if text := http.StatusText(status); err == nil && text != "" {
+				typeName = name + jrpc.GoIdentifier(strings.ReplaceAll(text, "'", ""), m.options) + "Error"
+			}
+		default:
+			continue
+		}
+
+		failure := errorResponse{typeName: typeName, statusCode: code}
+		if contentType, media := jsonMediaType(op.Responses[code].Content); media != nil {
+			for _, variant := range responses {
+				if variant.statusCode == code && variant.contentType == contentType {
+					failure.bodyType = variant.bodyType
+				}
+			}
+			if failure.bodyType == "" {
+				schema := media.Schema
+				if schema == nil {
+					schema = map[string]any{}
+				}
+				prefix := name + strings.ToUpper(code)
+				if code == "default" {
+					prefix = name + "Default"
+				}
+				failure.bodyType = m.goType(schema, prefix+mediaTypeName(contentType)+"ResponseBody")
+			}
+		}
+		errors = append(errors, failure)
+	}
+	return errors
+}
+
+// stream returns the first success response of an operation with a streaming media type, or
+// nil when it has none. Events are typed by the itemSchema of the media type, or its schema;
+// server-sent events default to strings and JSON lines to any.
+func (m *typeMapper) stream(op *Operation, name string) *stream {
+	for _, code := range op.SortedStatusCodes() {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		content := op.Responses[code].Content
+		for _, contentType := range sortedKeys(content) {
+			sse, ok := streamMediaTypes[strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])]
+			if !ok {
+				continue
+			}
+			schema := content[contentType].ItemSchema
+			if schema == nil {
+				schema = content[contentType].Schema
+			}
+			goType := "any"
+			switch {
+			case schema != nil:
+				goType = m.goType(schema, name+"StreamEvent")
+			case sse:
+				goType = "string"
+			}
+			return &stream{goType: goType, contentType: contentType, sse: sse}
+		}
+	}
+	return nil
+}
+
+// mediaTypeName returns the part of the Go name of a response type naming its media type
+// (application/json → JSON, text/plain → Text, application/problem+json → ApplicationProblemJSON)
+func mediaTypeName(contentType string) string {
+	switch contentType {
+	case "application/json":
+		return "JSON"
+	case "text/plain":
+		return "Text"
+	}
+	words := strings.FieldsFunc(strings.ReplaceAll(contentType, "*", "any"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return jrpc.GoIdentifier(strings.Join(words, "_"), nil)
+}
+
+// operationName returns the Go name of an operation: its operationId, or its method and path
+// (GET /tasks/{id} → GetTasksID)
+func operationName(op *Operation, options *jrpc.GeneratorOptions) string {
+	if op.OperationID != "" {
+		return jrpc.GoIdentifier(op.OperationID, options)
+	}
+	words := []string{strings.ToLower(op.Method)}
+	for _, segment := range strings.Split(op.Path, "/") {
+		words = append(words, strings.Trim(segment, "{}"))
+	}
+	return jrpc.GoIdentifier(strings.Join(words, "_"), options)
+}
+
+// jsonMediaType returns the JSON media type of a content map (application/json, or a
+// +json type such as application/problem+json), or nil when it has none
+func jsonMediaType(content map[string]*MediaType) (string, *MediaType) {
+	if media, ok := content["application/json"]; ok {
+		return "application/json", media
+	}
+	for _, contentType := range sortedKeys(content) {
+		if strings.HasSuffix(strings.SplitN(contentType, ";", 2)[0], "+json") {
+			return contentType, content[contentType]
+		}
+	}
+	return "", nil
+}
+
+// goType returns the Go type of a schema. $refs name the referenced component (honoring
+// x-go-name), arrays and primitives map to Go types directly, and any other schema is
+// hoisted into components/schemas under hint.
+func (m *typeMapper) goType(schema map[string]any, hint string) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return m.refType(ref)
+	}
+	if goType, ok := schema["x-go-type"].(string); ok {
+		return goType
+	}
+
+	switch schemaType(schema) {
+	case "array":
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return "[]any"
+		}
+		return "[]" + m.goType(items, hint+"Item")
+	case "string", "integer", "number", "boolean":
+		if _, composed := schema["allOf"]; !composed {
+			return jrpc.SchemaGoType(schema, m.schemas)
+		}
+	case "":
+		if len(schema) == 0 {
+			return "any"
+		}
+	}
+
+	name := hint
+	for i := 2; m.schemas[name] != nil; i++ {
+		name = hint + strconv.Itoa(i)
+	}
+	m.schemas[name] = schema
+	return name
+}
+
+// defaultLiteral returns the Go literal of the default of a scalar or scalar array schema, or
+// "" when it has none or the default cannot be written as a constant of goType
+func (m *typeMapper) defaultLiteral(schema map[string]any, goType string) string {
+	value, ok := schema["default"]
+	if !ok {
+		if value, ok = m.resolve(schema)["default"]; !ok {
+			return ""
+		}
+	}
+	if items, ok := value.([]any); ok {
+		itemSchema, _ := m.resolve(schema)["items"].(map[string]any)
+		if itemSchema == nil || !strings.HasPrefix(goType, "[]") {
+			return ""
+		}
+		literals := make([]string, len(items))
+		for i, item := range items {
+			if literals[i] = m.scalarLiteral(itemSchema, strings.TrimPrefix(goType, "[]"), item); literals[i] == "" {
+				return ""
+			}
+		}
+		return goType + "{" + strings.Join(literals, ", ") + "}"
+	}
+	return m.scalarLiteral(schema, goType, value)
+}
+
+// scalarLiteral returns the untyped constant of a scalar default, or "" when goType is not a
+// string, number or boolean type the constant can be assigned to
+func (m *typeMapper) scalarLiteral(schema map[string]any, goType string, value any) string {
+	if strings.ContainsAny(goType, ".[]*") {
+		return ""
+	}
+	switch value := value.(type) {
+	case string:
+		if schemaType(m.resolve(schema)) == "string" {
+			return strconv.Quote(value)
+		}
+	case bool:
+		if schemaType(m.resolve(schema)) == "boolean" {
+			return strconv.FormatBool(value)
+		}
+	case float64:
+		switch schemaType(m.resolve(schema)) {
+		case "integer":
+			if value == math.Trunc(value) {
+				return strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		case "number":
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// resolve returns the component schema a $ref points at, or the schema itself
+func (m *typeMapper) resolve(schema map[string]any) map[string]any {
+	if ref, ok := schema["$ref"].(string); ok {
+		if component, ok := m.schemas[unescapePointer(ref[strings.LastIndex(ref, "/")+1:])].(map[string]any); ok {
+			return component
+		}
+	}
+	return schema
+}
+
+// refType returns the Go type of a $ref to a component schema
+func (m *typeMapper) refType(ref string) string {
+	name := unescapePointer(ref[strings.LastIndex(ref, "/")+1:])
+	if component, ok := m.schemas[name].(map[string]any); ok {
+		if goType, ok := component["x-go-type"].(string); ok {
+			return goType
+		}
+		if goName, ok := component["x-go-name"].(string); ok {
+			return goName
+		}
+	}
+	return name
+}
+
+// schemaType returns the type keyword of a schema, ignoring "null" in type arrays
+func schemaType(schema map[string]any) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []any:
+		for _, member := range value {
+			if name, ok := member.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,419 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Names of the query parameters and response properties of list operations, by convention
+var (
+	cursorParams     = []string{"cursor", "after", "starting_after", "page_token", "pageToken", "next_token", "nextToken", "continuation_token", "continuationToken"}
+	pageParams       = []string{"page", "page_number", "pageNumber"}
+	cursorProperties = []string{"next_cursor", "nextCursor", "next_page_token", "nextPageToken", "next_token", "nextToken", "next", "last_id", "lastId"}
+	itemsProperties  = []string{"data", "items", "results", "records", "entries"}
+	hasMoreFlags     = []string{"has_more", "hasMore"}
+)
+
+// pager is how the pages of a list operation are followed by its <Op>Pager
+type pager struct {
+	itemType  string     // Go type of the items
+	items     []string   // Path of the items in the response body, empty when the body is the array
+	param     *parameter // Query parameter carrying the cursor or the page number, nil for links
+	next      []string   // Path of the next cursor in the response body, empty for page numbers
+	hasMore   []string   // Path of the boolean telling whether more pages follow, if any
+	link      bool       // Follows the rel="next" links of the Link header
+	firstPage string     // Go literal of the first page number
+}
+
+// pager returns the pagination of a list operation, or nil when it has none. Its x-pagination
+// extension names the parameters and response properties involved; otherwise they are
+// recognized by name: a cursor parameter (cursor, after, page_token, ...) with a next cursor
+// property (next_cursor, next_page_token, ...), or a page parameter, with the items in a data,
+// items or results array or the only array of the response. A Link response header, or
+// x-pagination link, follows rel="next" links instead.
+func (m *typeMapper) pager(op *operation) *pager {
+	pagination := op.Pagination
+	if pagination == nil {
+		pagination = &Pagination{}
+	}
+	if pagination.Disabled {
+		return nil
+	}
+	schema, linked := m.pageSchema(op.Operation)
+	if schema == nil {
+		return nil
+	}
+	p := &pager{link: pagination.Link || linked}
+
+	if schemaType(schema) == "array" {
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			items = map[string]any{}
+		}
+		p.itemType = m.goType(items, op.name+"Item")
+	} else {
+		p.items = splitPath(pagination.Items)
+		if p.items == nil {
+			p.items = m.conventionalProperty(schema, itemsProperties, "array")
+		}
+		if p.items == nil {
+			var arrays []string
+			for _, name := range sortedKeys(m.properties(schema)) {
+				if schemaType(m.property(schema, []string{name})) == "array" {
+					arrays = append(arrays, name)
+				}
+			}
+			if len(arrays) == 1 {
+				p.items = arrays
+			}
+		}
+		items := m.property(schema, p.items)
+		if p.items == nil || schemaType(items) != "array" {
+			return nil
+		}
+		itemSchema, _ := items["items"].(map[string]any)
+		if itemSchema == nil {
+			itemSchema = map[string]any{}
+		}
+		p.itemType = m.goType(itemSchema, op.name+"Item")
+
+		p.hasMore = splitPath(pagination.HasMore)
+		if p.hasMore == nil {
+			p.hasMore = m.conventionalProperty(schema, hasMoreFlags, "boolean")
+		}
+	}
+	if p.link {
+		return p
+	}
+
+	if param := queryParameter(op, pagination.CursorParam, cursorParams); param != nil {
+		next := splitPath(pagination.Cursor)
+		if next == nil {
+			next = m.conventionalProperty(schema, cursorProperties, schemaType(m.resolve(param.Schema)))
+		}
+		if next != nil && schemaType(m.property(schema, next)) == schemaType(m.resolve(param.Schema)) {
+			p.param, p.next = param, next
+			return p
+		}
+	}
+	if param := queryParameter(op, pagination.PageParam, pageParams); param != nil && schemaType(m.resolve(param.Schema)) == "integer" {
+		p.param, p.firstPage = param, "1"
+		if literal := m.scalarLiteral(param.Schema, param.goType, m.resolve(param.Schema)["minimum"]); literal != "" {
+			p.firstPage = literal
+		}
+		if param.defaultValue != "" {
+			p.firstPage = param.defaultValue
+		}
+		return p
+	}
+	return nil
+}
+
+// pageSchema returns the resolved JSON schema of the first success response of an operation,
+// and whether the response declares a Link header
+func (m *typeMapper) pageSchema(op *Operation) (map[string]any, bool) {
+	for _, code := range op.SortedStatusCodes() {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		response := op.Responses[code]
+		_, media := jsonMediaType(response.Content)
+		if media == nil || media.Schema == nil {
+			return nil, false
+		}
+		linked := false
+		for header := range response.Headers {
+			linked = linked || http.CanonicalHeaderKey(header) == "Link"
+		}
+		return m.resolve(media.Schema), linked
+	}
+	return nil, false
+}
+
+// queryParameter returns the scalar query parameter of an operation named name, or with the
+// first of the conventional names when name is empty
+func queryParameter(op *operation, name string, conventional []string) *parameter {
+	names := conventional
+	if name != "" {
+		names = []string{name}
+	}
+	for _, name := range names {
+		for i, param := range op.params {
+			if param.In == "query" && param.Name == name && (param.goType == "string" || strings.HasPrefix(param.goType, "int")) {
+				return &op.params[i]
+			}
+		}
+	}
+	return nil
+}
+
+// conventionalProperty returns the path of the first top-level property of schema among names
+// with the given type, or nil
+func (m *typeMapper) conventionalProperty(schema map[string]any, names []string, propertyType string) []string {
+	for _, name := range names {
+		if property := m.property(schema, []string{name}); property != nil && schemaType(property) == propertyType {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// property returns the resolved schema of the property of schema at path, or nil
+func (m *typeMapper) property(schema map[string]any, path []string) map[string]any {
+	for _, name := range path {
+		property, _ := m.properties(schema)[name].(map[string]any)
+		if property == nil {
+			return nil
+		}
+		schema = m.resolve(property)
+	}
+	return schema
+}
+
+// properties returns the properties of a schema, resolving its $ref
+func (m *typeMapper) properties(schema map[string]any) map[string]any {
+	properties, _ := m.resolve(schema)["properties"].(map[string]any)
+	return properties
+}
+
+// splitPath splits a dot-separated path, returning nil for an empty one
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// generatePager writes <Op>Pager, which iterates over the items of all the pages of a list
+// operation
+func generatePager(out *bytes.Buffer, op *operation, security string) {
+	p := op.pager
+	var fields []pageField
+	if p.items != nil {
+		fields = append(fields, pageField{p.items, "[]" + p.itemType})
+	}
+	if p.next != nil {
+		fields = append(fields, pageField{p.next, "*" + p.param.goType})
+	}
+	if p.hasMore != nil {
+		fields = append(fields, pageField{p.hasMore, "*bool"})
+	}
+	pageType, items := "[]"+p.itemType, "page"
+	if p.items != nil {
+		pageType, items = pageStruct(fields, "\t\t\t"), pageAccess(p.items)
+	}
+
+	fmt.Fprintf(out, "// %sPager iterates over the items of all the pages of %s.\n", op.name, op.name)
+	switch {
+	case p.link:
+		out.WriteString("// It follows the rel=\"next\" links of the Link header of the responses.\n")
+	case p.next != nil:
+		fmt.Fprintf(out, "// It passes the %s of each response as the %s of the next request.\n", strings.Join(p.next, "."), p.param.Name)
+	default:
+		fmt.Fprintf(out, "// It increments the %s parameter until a page is empty.\n", p.param.Name)
+	}
+	switch {
+	case p.link:
+		out.WriteString("// A next link followed before is yielded as an error rather than requested again.\n")
+	case p.next != nil:
+		fmt.Fprintf(out, "// A %s returned before is yielded as an error rather than requested again.\n", strings.Join(p.next, "."))
+	}
+	out.WriteString("// The iteration stops at the first error, which it yields.\n")
+	fmt.Fprintf(out, "func (c *Client) %sPager(%s, options ...CallOption) iter.Seq2[%s, error] {\n", op.name, clientSignature(op), p.itemType)
+	fmt.Fprintf(out, "\treturn func(yield func(%s, error) bool) {\n", p.itemType)
+
+	var field string
+	if p.param != nil {
+		field = "params." + p.param.fieldName
+	}
+	if p.firstPage != "" {
+		if p.param.pointer() {
+			first := p.firstPage
+			if p.param.goType != "int" {
+				first = p.param.goType + "(" + first + ")"
+			}
+			fmt.Fprintf(out, "\t\tif %s == nil {\n\t\t\tfirst := %s\n\t\t\t%s = &first\n\t\t}\n", field, first, field)
+		} else if p.firstPage != "0" {
+			fmt.Fprintf(out, "\t\tif %s == 0 {\n\t\t\t%s = %s\n\t\t}\n", field, field, p.firstPage)
+		}
+	}
+	call := strings.Replace(requestArguments(op), "c.Server", "ctx", 1)
+	switch {
+	case p.link:
+		out.WriteString("\t\tseen := make(map[string]bool)\n")
+	case p.next != nil:
+		fmt.Fprintf(out, "\t\tseen := make(map[%s]bool)\n", p.param.goType)
+	}
+	if p.link {
+		fmt.Fprintf(out, "\t\tresp, err := c.%s(%s, options...)\n", op.name, call)
+		out.WriteString("\t\tfor {\n")
+	} else {
+		out.WriteString("\t\tfor {\n")
+		fmt.Fprintf(out, "\t\t\tresp, err := c.%s(%s, options...)\n", op.name, call)
+	}
+	fmt.Fprintf(out, "\t\t\tvar page %s\n", pageType)
+	if p.link {
+		out.WriteString("\t\t\tvar next string\n\t\t\tif err == nil {\n\t\t\t\tnext, err = readPage(resp, &page)\n\t\t\t}\n")
+	} else {
+		out.WriteString("\t\t\tif err == nil {\n\t\t\t\t_, err = readPage(resp, &page)\n\t\t\t}\n")
+	}
+	fmt.Fprintf(out, "\t\t\tif err != nil {\n\t\t\t\tyield(*new(%s), err)\n\t\t\t\treturn\n\t\t\t}\n", p.itemType)
+	fmt.Fprintf(out, "\t\t\tfor _, item := range %s {\n\t\t\t\tif !yield(item, nil) {\n\t\t\t\t\treturn\n\t\t\t\t}\n\t\t\t}\n", items)
+
+	stop := []string{"len(" + items + ") == 0"}
+	if p.hasMore != nil {
+		hasMore := pageAccess(p.hasMore)
+		stop = append(stop, hasMore+" != nil && !*"+hasMore)
+	}
+	switch {
+	case p.link:
+		stop = append(stop, `next == ""`)
+	case p.next != nil:
+		next, zero := pageAccess(p.next), "0"
+		if p.param.goType == "string" {
+			zero = `""`
+		}
+		stop = append(stop, next+" == nil", "*"+next+" == "+zero)
+	}
+	fmt.Fprintf(out, "\t\t\tif %s {\n\t\t\t\treturn\n\t\t\t}\n", strings.Join(stop, " || "))
+
+	// A server repeating a cursor or a link would otherwise be paged forever
+	repeated, kind := "next", "next link %s"
+	if p.next != nil {
+		repeated, kind = "*"+pageAccess(p.next), strings.Join(p.next, ".")+" %v"
+	}
+	if p.link || p.next != nil {
+		fmt.Fprintf(out, "\t\t\tif seen[%s] {\n", repeated)
+		fmt.Fprintf(out, "\t\t\t\tyield(*new(%s), fmt.Errorf(\"%s returned the %s again\", %s))\n", p.itemType, op.name, kind, repeated)
+		fmt.Fprintf(out, "\t\t\t\treturn\n\t\t\t}\n\t\t\tseen[%s] = true\n", repeated)
+	}
+
+	switch {
+	case p.link:
+		fmt.Fprintf(out, "\t\t\tresp, err = c.getPage(ctx, next, %s, options)\n", security)
+		if len(op.errors) > 0 {
+			fmt.Fprintf(out, "\t\t\tif err == nil {\n\t\t\t\terr = decode%sError(resp)\n\t\t\t}\n", op.name)
+		}
+	case p.next != nil && p.param.pointer():
+		fmt.Fprintf(out, "\t\t\t%s = %s\n", field, pageAccess(p.next))
+	case p.next != nil:
+		fmt.Fprintf(out, "\t\t\t%s = *%s\n", field, pageAccess(p.next))
+	case p.param.pointer():
+		fmt.Fprintf(out, "\t\t\tnextPage := *%s + 1\n\t\t\t%s = &nextPage\n", field, field)
+	default:
+		fmt.Fprintf(out, "\t\t\t%s++\n", field)
+	}
+	out.WriteString("\t\t}\n\t}\n}\n\n")
+}
+
+// pageField is a field of the struct pages are decoded into, at a path of the response body
+type pageField struct {
+	path   []string
+	goType string
+}
+
+// pageStruct returns the anonymous struct type decoding the given fields of a response body,
+// with a nested struct per path prefix
+func pageStruct(fields []pageField, indent string) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	var done []string
+	for _, field := range fields {
+		name := field.path[0]
+		if slices.Contains(done, name) {
+			continue
+		}
+		done = append(done, name)
+		fieldType := field.goType
+		if len(field.path) > 1 {
+			var nested []pageField
+			for _, other := range fields {
+				if other.path[0] == name && len(other.path) > 1 {
+					nested = append(nested, pageField{other.path[1:], other.goType})
+				}
+			}
+			fieldType = pageStruct(nested, indent+"\t")
+		}
+		fmt.Fprintf(&b, "%s\t%s %s `json:\"%s\"`\n", indent, jrpc.GoIdentifier(name, nil), fieldType, name)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// pageAccess returns the expression of the field of the page at path
+func pageAccess(path []string) string {
+	expression := "page"
+	for _, name := range path {
+		expression += "." + jrpc.GoIdentifier(name, nil)
+	}
+	return expression
+}
+
+// generatePagerHelpers writes the helpers of the pagers of the operations
+func generatePagerHelpers(out *bytes.Buffer, operations []*operation) {
+	paged, linked := false, false
+	for _, op := range operations {
+		if op.pager != nil {
+			paged = true
+			linked = linked || op.pager.link
+		}
+	}
+	if paged {
+		out.WriteString(pagerHelpers)
+	}
+	if linked {
+		out.WriteString(linkPagerHelper)
+	}
+}
+
+// pagerHelpers decode the pages of list operations
+const pagerHelpers = `// readPage decodes the JSON body of a page of a list operation into page and closes it. It
+// returns the URL of the next page when the Link header of the response has a rel="next"
+// link.
+func readPage(resp *http.Response, page any) (string, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return "", fmt.Errorf("failed to decode page: %w", err)
+	}
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			target, attributes, _ := strings.Cut(link, ";")
+			for _, attribute := range strings.Split(attributes, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(attribute), "=")
+				if !strings.EqualFold(key, "rel") || !slices.Contains(strings.Fields(strings.Trim(value, "\"")), "next") {
+					continue
+				}
+				next, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+				if err != nil {
+					return "", fmt.Errorf("invalid next link: %w", err)
+				}
+				if resp.Request != nil {
+					next = resp.Request.URL.ResolveReference(next)
+				}
+				return next.String(), nil
+			}
+		}
+	}
+	return "", nil
+}
+`
+
+// linkPagerHelper requests the pages Link headers point at
+const linkPagerHelper = `// getPage sends a GET request to the URL of the next page of a list operation. security lists
+// the security schemes accepted by the operation.
+func (c *Client) getPage(ctx context.Context, url string, security []string, options []CallOption) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, security, options)
+}
+`
@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+// pagedDocument has a list operation per kind of pagination: cursors, Link headers and page
+// numbers
+const pagedDocument = `
+openapi: 3.1.0
+info: {title: Pages, version: "1"}
+paths:
+  /models:
+    get:
+      operationId: listModels
+      parameters: [{name: cursor, in: query, schema: {type: string}}]
+      responses:
+        "200":
+          description: models
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data: {type: array, items: {type: string}}
+                  next_cursor: {type: string}
+  /events:
+    get:
+      operationId: listEvents
+      responses:
+        "200":
+          description: events
+          headers: {Link: {schema: {type: string}}}
+          content: {application/json: {schema: {type: array, items: {type: string}}}}
+  /files:
+    get:
+      operationId: listFiles
+      parameters: [{name: page, in: query, schema: {type: integer, minimum: 1}}]
+      responses:
+        "200":
+          description: files
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  results: {type: array, items: {type: string}}
+components:
+  schemas:
+    Page:
+      type: object
+      properties:
+        next_cursor: {type: string}
+`
+
+// pagerTest is the template of a test of the generated code paging through the responses of
+// a fake server: %[1]s is the name of the test, %[2]s the handler of the server, %[3]s the
+// pager call, %[4]q the items it yields, joined with commas, and %[5]t whether it yields an
+// error
+const pagerTest = `
+func Test%[1]s(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(%[2]s))
+	defer server.Close()
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []string
+	var pagerErr error
+	for item, err := range client.%[3]s {
+		if err != nil {
+			pagerErr = err
+			break
+		}
+		items = append(items, item)
+		if len(items) > 100 {
+			t.Fatal("the pager does not stop")
+		}
+	}
+	if got := strings.Join(items, ","); got != %[4]q {
+		t.Errorf("items = %%q, want %%q", got, %[4]q)
+	}
+	if (pagerErr != nil) != %[5]t {
+		t.Errorf("error = %%v, want an error: %[5]t", pagerErr)
+	}
+}
+`
+
+func TestPagers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler string
+		pager   string
+		items   string
+		err     bool
+	}{
+		{
+			name: "Cursor",
+			handler: `func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("cursor") {
+				case "":
+					fmt.Fprint(w, ` + "`" + `{"data": ["a", "b"], "next_cursor": "c1"}` + "`" + `)
+				case "c1":
+					fmt.Fprint(w, ` + "`" + `{"data": ["c"], "next_cursor": ""}` + "`" + `)
+				}
+			}`,
+			pager: "ListModelsPager(context.Background(), ListModelsParams{})",
+			items: "a,b,c",
+		},
+		{
+			name: "RepeatedCursor",
+			handler: `func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, ` + "`" + `{"data": ["a"], "next_cursor": "string"}` + "`" + `)
+			}`,
+			pager: "ListModelsPager(context.Background(), ListModelsParams{})",
+			items: "a,a",
+			err:   true,
+		},
+		{
+			name: "Link",
+			handler: `func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "" {
+					w.Header().Set("Link", ` + "`" + `</events?page=2>; rel="next"` + "`" + `)
+					fmt.Fprint(w, ` + "`" + `["a"]` + "`" + `)
+					return
+				}
+				fmt.Fprint(w, ` + "`" + `["b"]` + "`" + `)
+			}`,
+			pager: "ListEventsPager(context.Background())",
+			items: "a,b",
+		},
+		{
+			name: "RepeatedLink",
+			handler: `func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Link", ` + "`" + `</events?page=2>; rel="next"` + "`" + `)
+				fmt.Fprint(w, ` + "`" + `["a"]` + "`" + `)
+			}`,
+			pager: "ListEventsPager(context.Background())",
+			items: "a,a",
+			err:   true,
+		},
+		{
+			name: "PageNumber",
+			handler: `func(w http.ResponseWriter, r *http.Request) {
+				switch page := r.URL.Query().Get("page"); page {
+				case "1", "2":
+					fmt.Fprintf(w, ` + "`" + `{"results": ["p%s"]}` + "`" + `, page)
+				default:
+					fmt.Fprint(w, ` + "`" + `{"results": []}` + "`" + `)
+				}
+			}`,
+			pager: "ListFilesPager(context.Background(), ListFilesParams{})",
+			items: "p1,p2",
+		},
+	}
+
+	var source bytes.Buffer
+	if err := GenerateTo(&source, []byte(pagedDocument), &Options{PackageName: "generated", GenerateClient: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var generatedTests strings.Builder
+	generatedTests.WriteString("package generated\n\nimport (\n\t\"context\"\n\t\"fmt\"\n\t\"net/http\"\n\t\"net/http/httptest\"\n\t\"strings\"\n\t\"testing\"\n)\n")
+	for _, test := range tests {
+		fmt.Fprintf(&generatedTests, pagerTest, test.name, test.handler, test.pager, test.items, test.err)
+	}
+
+	gentest.Run(t, map[string]string{
+		"client.go":      source.String(),
+		"client_test.go": generatedTests.String(),
+	}, "test", "./...")
+}
+
+// TestPagersAgainstMockServer checks that the canned responses of the mock server, which
+// leave the next cursors out, end the pagers after the first page
+func TestPagersAgainstMockServer(t *testing.T) {
+	var source bytes.Buffer
+	if err := GenerateTo(&source, []byte(pagedDocument), &Options{PackageName: "generated", GenerateClient: true, MockServer: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	gentest.Run(t, map[string]string{
+		"client.go": source.String(),
+		"client_test.go": `package generated
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMockPager(t *testing.T) {
+	server := StartMockServer(&MockServer{})
+	defer server.Close()
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, err := range client.ListModelsPager(context.Background(), ListModelsParams{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count++; count > 100 {
+			t.Fatal("the pager does not stop")
+		}
+	}
+	if count != 1 {
+		t.Errorf("got %d items, want the item of the canned response", count)
+	}
+}
+`,
+	}, "test", "./...")
+}
@@ -0,0 +1,623 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// generateParamsStructs writes the structs holding the path, query, header and cookie
+// parameters of the operations that have any. Optional scalar parameters without a default
+// are pointers.
+func generateParamsStructs(out *bytes.Buffer, operations []*operation, options *Options) {
+	for _, op := range operations {
+		if len(op.params) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "// %s are the parameters of %s\n", op.paramsType(), op.name)
+		fmt.Fprintf(out, "type %s struct {\n", op.paramsType())
+		for _, param := range op.params {
+			if options.IncludeComments && param.Description != "" {
+				for _, line := range strings.Split(strings.TrimSpace(param.Description), "\n") {
+					fmt.Fprintf(out, "\t// %s\n", strings.TrimSpace(line))
+				}
+			}
+			tag := param.Name
+			if !param.Required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(out, "\t%s %s `json:\"%s\"`\n", param.fieldName, param.fieldType(), tag)
+		}
+		out.WriteString("}\n\n")
+	}
+}
+
+// fieldType returns the type of the parameter's field in the Params struct
+func (p parameter) fieldType() string {
+	if !p.pointer() {
+		return p.goType
+	}
+	return "*" + p.goType
+}
+
+// pointer reports whether the parameter's field is a pointer, nil when the parameter is absent
+func (p parameter) pointer() bool {
+	return !p.Required && p.In != "path" && p.defaultValue == "" && !p.nillable()
+}
+
+// nillable reports whether the Go type of the parameter is nil when the parameter is absent
+// without being a pointer
+func (p parameter) nillable() bool {
+	return p.itemType != "" || strings.HasPrefix(p.goType, "map[")
+}
+
+// zeroLiteral returns the constant of the zero value of an optional parameter with a scalar
+// default, or "" for the other parameters
+func (p parameter) zeroLiteral() string {
+	switch {
+	case p.Required || p.In == "path" || p.defaultValue == "" || p.nillable():
+		return ""
+	case strings.HasPrefix(p.defaultValue, `"`):
+		return `""`
+	case p.defaultValue == "true" || p.defaultValue == "false":
+		return "false"
+	}
+	return "0"
+}
+
+// generateParamsCodecs writes, for every Params struct, Decode<Op>Params reading the
+// parameters from a server request, and the client side helpers: <Op>Path expanding the path
+// template with the path parameters and Encode<Op>Params adding the others to a request
+func generateParamsCodecs(out *bytes.Buffer, operations []*operation) {
+	for _, op := range operations {
+		if len(op.params) == 0 {
+			continue
+		}
+		generateParamsDecoder(out, op)
+
+		var pathParams, requestParams []parameter
+		for _, param := range op.params {
+			if param.In == "path" {
+				pathParams = append(pathParams, param)
+			} else {
+				requestParams = append(requestParams, param)
+			}
+		}
+		if len(pathParams) > 0 {
+			generatePathEncoder(out, op, pathParams)
+		}
+		if len(requestParams) > 0 {
+			generateParamsEncoder(out, op, requestParams)
+		}
+	}
+}
+
+// generateParamsDecoder writes Decode<Op>Params
+func generateParamsDecoder(out *bytes.Buffer, op *operation) {
+	fmt.Fprintf(out, "// Decode%s decodes the parameters of %s from a request, applying their\n", op.paramsType(), op.name)
+	out.WriteString("// defaults and checking that the required ones are present\n")
+	fmt.Fprintf(out, "func Decode%s(r *http.Request) (%s, error) {\n", op.paramsType(), op.paramsType())
+	fmt.Fprintf(out, "\tvar params %s\n", op.paramsType())
+	for _, param := range op.params {
+		if param.In == "query" {
+			out.WriteString("\tquery := r.URL.Query()\n")
+			break
+		}
+	}
+
+	for _, param := range op.params {
+		var source string
+		switch param.In {
+		case "path":
+			source = fmt.Sprintf("decodePath[%s](r, %q, %t, %q, %q)", param.goType, param.style, param.explode, param.Name, serveMuxWildcard(param.Name))
+		case "query":
+			source = fmt.Sprintf("decodeQuery[%s](query, %q, %t, %q)", param.goType, param.style, param.explode, param.Name)
+		case "header":
+			source = fmt.Sprintf("decodeHeader[%s](r.Header, %q, %t, %q)", param.goType, param.style, param.explode, param.Name)
+		case "cookie":
+			source = fmt.Sprintf("decodeCookie[%s](r, %q, %t, %q)", param.goType, param.style, param.explode, param.Name)
+		}
+		field := "params." + param.fieldName
+		fmt.Fprintf(out, "\tif value, found, err := %s; err != nil {\n\t\treturn params, err\n", source)
+		if param.pointer() {
+			fmt.Fprintf(out, "\t} else if found {\n\t\t%s = &value\n", field)
+		} else {
+			fmt.Fprintf(out, "\t} else if found {\n\t\t%s = value\n", field)
+		}
+		switch {
+		case param.Required || param.In == "path":
+			fmt.Fprintf(out, "\t} else {\n\t\treturn params, errors.New(%q)\n", param.In+" parameter "+param.Name+" is required")
+		case param.defaultValue != "":
+			fmt.Fprintf(out, "\t} else {\n\t\t%s = %s\n", field, param.defaultValue)
+		}
+		out.WriteString("\t}\n")
+	}
+	out.WriteString("\treturn params, nil\n}\n\n")
+}
+
+// generatePathEncoder writes <Op>Path
+func generatePathEncoder(out *bytes.Buffer, op *operation, params []parameter) {
+	fmt.Fprintf(out, "// %sPath returns the path of %s with the path parameters of params\n", op.name, op.name)
+	fmt.Fprintf(out, "func %sPath(params %s) (string, error) {\n", op.name, op.paramsType())
+	fmt.Fprintf(out, "\tpath := %q\n\tvar err error\n", op.Path)
+	for _, param := range params {
+		fmt.Fprintf(out, "\tif path, err = encodePath(path, %q, %t, %q, params.%s); err != nil {\n", param.style, param.explode, param.Name, param.fieldName)
+		out.WriteString("\t\treturn \"\", err\n\t}\n")
+	}
+	out.WriteString("\treturn path, nil\n}\n\n")
+}
+
+// generateParamsEncoder writes Encode<Op>Params. Absent optional parameters are not sent, nor
+// are optional parameters with a default left at their zero value, which would override the
+// default the server applies.
+func generateParamsEncoder(out *bytes.Buffer, op *operation, params []parameter) {
+	fmt.Fprintf(out, "// Encode%s adds the query, header and cookie parameters of params to a\n", op.paramsType())
+	fmt.Fprintf(out, "// %s request\n", op.name)
+	fmt.Fprintf(out, "func Encode%s(req *http.Request, params %s) error {\n", op.paramsType(), op.paramsType())
+	hasQuery := false
+	for _, param := range params {
+		if param.In == "query" {
+			hasQuery = true
+		}
+	}
+	if hasQuery {
+		out.WriteString("\tquery := req.URL.Query()\n")
+	}
+
+	for _, param := range params {
+		value := "params." + param.fieldName
+		indent := "\t"
+		if param.pointer() || param.nillable() && !param.Required {
+			fmt.Fprintf(out, "\tif %s != nil {\n", value)
+			indent = "\t\t"
+		} else if zero := param.zeroLiteral(); zero != "" {
+			fmt.Fprintf(out, "\tif %s != %s {\n", value, zero)
+			indent = "\t\t"
+		}
+		if param.pointer() {
+			value = "*" + value
+		}
+		switch param.In {
+		case "query":
+			fmt.Fprintf(out, "%sif err := encodeQuery(query, %q, %t, %q, %s); err != nil {\n", indent, param.style, param.explode, param.Name, value)
+		case "header":
+			fmt.Fprintf(out, "%sif err := encodeHeader(req.Header, %q, %t, %q, %s); err != nil {\n", indent, param.style, param.explode, param.Name, value)
+		case "cookie":
+			fmt.Fprintf(out, "%sif err := encodeCookie(req, %q, %t, %q, %s); err != nil {\n", indent, param.style, param.explode, param.Name, value)
+		}
+		fmt.Fprintf(out, "%s\treturn err\n%s}\n", indent, indent)
+		if indent != "\t" {
+			out.WriteString("\t}\n")
+		}
+	}
+	if hasQuery {
+		out.WriteString("\treq.URL.RawQuery = query.Encode()\n")
+	}
+	out.WriteString("\treturn nil\n}\n\n")
+}
+
+// parameterHelpers decode and encode parameters following their OpenAPI style: path and header
+// parameters are simple (or label and matrix for paths), query parameters form,
+// spaceDelimited, pipeDelimited or deepObject, and cookies form. Array items and object
+// properties are converted one by one, objects through their JSON property names.
+const parameterHelpers = `// decodePath converts the path parameter of a request matched by a ServeMux wildcard
+func decodePath[T any](r *http.Request, style string, explode bool, name string, wildcard string) (T, bool, error) {
+	var value T
+	text := r.PathValue(wildcard)
+	if text == "" {
+		return value, false, nil
+	}
+	err := decodeStyled(reflect.ValueOf(&value).Elem(), style, explode, name, text)
+	return value, true, err
+}
+
+// decodeQuery converts a query parameter. Exploded form arrays are repeated keys, exploded
+// form objects one key per property and deepObject objects name[property] keys.
+func decodeQuery[T any](query url.Values, style string, explode bool, name string) (T, bool, error) {
+	var value T
+	target := reflect.ValueOf(&value).Elem()
+	switch shape := parameterShape(target.Type()); {
+	case shape == "array" && style == "form" && explode:
+		texts, ok := query[name]
+		if !ok {
+			return value, false, nil
+		}
+		return value, true, decodeItems(target, name, texts)
+	case shape == "object" && (style == "deepObject" || style == "form" && explode):
+		members := make(map[string]string)
+		for key, texts := range query {
+			if style == "form" {
+				members[key] = texts[0]
+			} else if property, ok := strings.CutPrefix(key, name+"["); ok && strings.HasSuffix(property, "]") {
+				members[strings.TrimSuffix(property, "]")] = texts[0]
+			}
+		}
+		found, err := decodeObject(target, name, members)
+		return value, found, err
+	}
+	if !query.Has(name) {
+		return value, false, nil
+	}
+	return value, true, decodeStyled(target, style, explode, name, query.Get(name))
+}
+
+// decodeHeader converts a header parameter, joining the values of repeated headers
+func decodeHeader[T any](header http.Header, style string, explode bool, name string) (T, bool, error) {
+	var value T
+	texts := header.Values(name)
+	if len(texts) == 0 {
+		return value, false, nil
+	}
+	err := decodeStyled(reflect.ValueOf(&value).Elem(), style, explode, name, strings.Join(texts, ","))
+	return value, true, err
+}
+
+// decodeCookie converts a cookie parameter
+func decodeCookie[T any](r *http.Request, style string, explode bool, name string) (T, bool, error) {
+	var value T
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return value, false, nil
+	}
+	err = decodeStyled(reflect.ValueOf(&value).Elem(), style, explode, name, cookie.Value)
+	return value, true, err
+}
+
+// parameterShape returns how a parameter of type t is serialized: "array", "object" or
+// "primitive". Types implementing encoding.TextUnmarshaler are primitives.
+func parameterShape(t reflect.Type) string {
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return "primitive"
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return "array"
+		}
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return "primitive"
+}
+
+// styledText strips the prefix of label (.) and matrix (;name=) parameters
+func styledText(style string, name string, text string) string {
+	switch style {
+	case "label":
+		return strings.TrimPrefix(text, ".")
+	case "matrix":
+		return strings.TrimPrefix(strings.TrimPrefix(text, ";"), name+"=")
+	}
+	return text
+}
+
+// styledParts splits the text of an array or object parameter into its parts
+func styledParts(style string, explode bool, name string, text string) []string {
+	text = styledText(style, name, text)
+	if text == "" {
+		return nil
+	}
+	separator := ","
+	switch {
+	case style == "label" && explode:
+		separator = "."
+	case style == "matrix" && explode:
+		separator = ";"
+	case style == "spaceDelimited":
+		separator = " "
+	case style == "pipeDelimited":
+		separator = "|"
+	}
+	parts := strings.Split(text, separator)
+	if style == "matrix" && explode {
+		for i, part := range parts {
+			parts[i] = strings.TrimPrefix(part, name+"=")
+		}
+	}
+	return parts
+}
+
+// decodeStyled converts the text of a parameter serialized as a single value to target.
+// Exploded object properties are name=value parts, other objects alternate names and values.
+func decodeStyled(target reflect.Value, style string, explode bool, name string, text string) error {
+	switch parameterShape(target.Type()) {
+	case "array":
+		return decodeItems(target, name, styledParts(style, explode, name, text))
+	case "object":
+		parts := styledParts(style, explode, name, text)
+		members := make(map[string]string)
+		if explode && style != "form" {
+			for _, part := range parts {
+				property, value, _ := strings.Cut(part, "=")
+				members[property] = value
+			}
+		} else {
+			if len(parts)%2 != 0 {
+				return fmt.Errorf("invalid parameter %s: object properties and values do not pair up", name)
+			}
+			for i := 0; i < len(parts); i += 2 {
+				members[parts[i]] = parts[i+1]
+			}
+		}
+		_, err := decodeObject(target, name, members)
+		return err
+	}
+	return parseValue(target, name, styledText(style, name, text))
+}
+
+// decodeItems converts the texts of the items of an array parameter to the slice target
+func decodeItems(target reflect.Value, name string, texts []string) error {
+	items := reflect.MakeSlice(target.Type(), len(texts), len(texts))
+	for i, text := range texts {
+		if err := parseValue(items.Index(i), name, text); err != nil {
+			return err
+		}
+	}
+	target.Set(items)
+	return nil
+}
+
+// decodeObject sets the properties of the struct or map target from the texts of members,
+// matching struct fields by JSON name. It reports whether any member was used.
+func decodeObject(target reflect.Value, name string, members map[string]string) (bool, error) {
+	if target.Kind() == reflect.Map {
+		object := reflect.MakeMapWithSize(target.Type(), len(members))
+		for property, text := range members {
+			value := reflect.New(target.Type().Elem()).Elem()
+			if err := parseValue(value, name+"."+property, text); err != nil {
+				return true, err
+			}
+			object.SetMapIndex(reflect.ValueOf(property).Convert(target.Type().Key()), value)
+		}
+		target.Set(object)
+		return len(members) > 0, nil
+	}
+
+	found := false
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		property, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if property == "" {
+			property = field.Name
+		}
+		text, ok := members[property]
+		if !ok || !field.IsExported() {
+			continue
+		}
+		found = true
+		if err := parseValue(target.Field(i), name+"."+property, text); err != nil {
+			return true, err
+		}
+	}
+	return found, nil
+}
+
+// parseValue converts the text of a primitive parameter to target: strings, booleans and
+// numbers are parsed, encoding.TextUnmarshaler implementations unmarshal themselves and other
+// types are decoded as JSON
+func parseValue(target reflect.Value, name string, text string) error {
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+	if unmarshaler, ok := target.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("invalid parameter %s: %w", name, err)
+		}
+		return nil
+	}
+
+	var err error
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(text)
+	case reflect.Bool:
+		var parsed bool
+		if parsed, err = strconv.ParseBool(text); err == nil {
+			target.SetBool(parsed)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+		if parsed, err = strconv.ParseInt(text, 10, target.Type().Bits()); err == nil {
+			target.SetInt(parsed)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var parsed uint64
+		if parsed, err = strconv.ParseUint(text, 10, target.Type().Bits()); err == nil {
+			target.SetUint(parsed)
+		}
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		if parsed, err = strconv.ParseFloat(text, target.Type().Bits()); err == nil {
+			target.SetFloat(parsed)
+		}
+	default:
+		err = json.Unmarshal([]byte(text), target.Addr().Interface())
+	}
+	if err != nil {
+		return fmt.Errorf("invalid parameter %s: %w", name, err)
+	}
+	return nil
+}
+
+// encodePath replaces the {name} placeholder of a path template with a path parameter
+func encodePath(path string, style string, explode bool, name string, value any) (string, error) {
+	text, err := styleParameter(style, explode, name, value)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(text)), nil
+}
+
+// encodeQuery adds a query parameter to query, as decodeQuery reads it
+func encodeQuery(query url.Values, style string, explode bool, name string, value any) error {
+	object := reflect.ValueOf(value)
+	switch shape := parameterShape(object.Type()); {
+	case shape == "array" && style == "form" && explode:
+		for i := 0; i < object.Len(); i++ {
+			text, err := formatValue(object.Index(i))
+			if err != nil {
+				return fmt.Errorf("invalid parameter %s: %w", name, err)
+			}
+			query.Add(name, text)
+		}
+		return nil
+	case shape == "object" && (style == "deepObject" || style == "form" && explode):
+		properties, texts, err := objectMembers(object)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %w", name, err)
+		}
+		for i, property := range properties {
+			if style == "deepObject" {
+				property = name + "[" + property + "]"
+			}
+			query.Add(property, texts[i])
+		}
+		return nil
+	}
+	text, err := styleParameter(style, explode, name, value)
+	if err != nil {
+		return err
+	}
+	query.Add(name, text)
+	return nil
+}
+
+// encodeHeader sets a header parameter
+func encodeHeader(header http.Header, style string, explode bool, name string, value any) error {
+	text, err := styleParameter(style, explode, name, value)
+	if err != nil {
+		return err
+	}
+	header.Set(name, text)
+	return nil
+}
+
+// encodeCookie adds a cookie parameter to a request
+func encodeCookie(req *http.Request, style string, explode bool, name string, value any) error {
+	text, err := styleParameter(style, explode, name, value)
+	if err != nil {
+		return err
+	}
+	req.AddCookie(&http.Cookie{Name: name, Value: text})
+	return nil
+}
+
+// styleParameter serializes a parameter value as a single text in the given style: the
+// inverse of decodeStyled
+func styleParameter(style string, explode bool, name string, value any) (string, error) {
+	object := reflect.ValueOf(value)
+	shape := parameterShape(object.Type())
+	var parts []string
+	separator := ","
+	switch shape {
+	case "array":
+		for i := 0; i < object.Len(); i++ {
+			text, err := formatValue(object.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("invalid parameter %s: %w", name, err)
+			}
+			parts = append(parts, text)
+		}
+		if explode && style == "matrix" {
+			separator = ";" + name + "="
+		}
+	case "object":
+		properties, texts, err := objectMembers(object)
+		if err != nil {
+			return "", fmt.Errorf("invalid parameter %s: %w", name, err)
+		}
+		for i, property := range properties {
+			if explode && style != "form" {
+				parts = append(parts, property+"="+texts[i])
+			} else {
+				parts = append(parts, property, texts[i])
+			}
+		}
+		if explode && style == "matrix" {
+			separator = ";"
+		}
+	default:
+		text, err := formatValue(object)
+		if err != nil {
+			return "", fmt.Errorf("invalid parameter %s: %w", name, err)
+		}
+		parts = []string{text}
+	}
+	switch {
+	case style == "label" && explode:
+		separator = "."
+	case style == "spaceDelimited":
+		separator = " "
+	case style == "pipeDelimited":
+		separator = "|"
+	}
+
+	text := strings.Join(parts, separator)
+	switch {
+	case style == "label":
+		return "." + text, nil
+	case style == "matrix" && explode && shape == "object":
+		return ";" + text, nil
+	case style == "matrix":
+		return ";" + name + "=" + text, nil
+	}
+	return text, nil
+}
+
+// objectMembers returns the JSON property names of an object parameter value, sorted, and
+// the texts of their values
+func objectMembers(object reflect.Value) ([]string, []string, error) {
+	data, err := json.Marshal(object.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, nil, err
+	}
+	properties := make([]string, 0, len(members))
+	for property, value := range members {
+		if string(value) != "null" {
+			properties = append(properties, property)
+		}
+	}
+	sort.Strings(properties)
+	texts := make([]string, len(properties))
+	for i, property := range properties {
+		if err := json.Unmarshal(members[property], &texts[i]); err != nil {
+			texts[i] = string(members[property])
+		}
+	}
+	return properties, texts, nil
+}
+
+// formatValue formats a primitive parameter value: the inverse of parseValue
+func formatValue(value reflect.Value) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	}
+	data, err := json.Marshal(value.Interface())
+	return string(data), err
+}
+`
@@ -0,0 +1,380 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// httpMethods are the HTTP methods of the operations a path item can declare, in the order
+// operations are listed
+var httpMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// parameterLocations are the valid values of Parameter.In
+var parameterLocations = map[string]bool{"path": true, "query": true, "header": true, "cookie": true}
+
+// pathParameterPattern matches the {name} placeholders of a path template
+var pathParameterPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Operation returns the operation of the path item for an HTTP method, or nil
+func (p *PathItem) Operation(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	case "HEAD":
+		return p.Head
+	case "PATCH":
+		return p.Patch
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+// Operations returns the operations of the document's paths sorted by path, then by HTTP
+// method. Webhook operations are not included.
+func (d *Document) Operations() []*Operation {
+	return d.operations
+}
+
+// SortedStatusCodes returns the status codes of the operation's responses in ascending order,
+// with ranges (2XX) after the codes they contain and "default" last
+func (o *Operation) SortedStatusCodes() []string {
+	codes := make([]string, 0, len(o.Responses))
+	for code := range o.Responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return statusCodeKey(codes[i]) < statusCodeKey(codes[j])
+	})
+	return codes
+}
+
+// statusCodeKey orders status codes: "404" before "4XX" before "500", and "default" last
+func statusCodeKey(code string) string {
+	if code == "default" {
+		return "9"
+	}
+	return strings.ReplaceAll(strings.ToUpper(code), "X", "Z")
+}
+
+// resolve replaces the $refs to components by the components they point at, merges the
+// parameters of path items into their operations and validates the operations
+func (d *Document) resolve() error {
+	for name, item := range d.Components.PathItems {
+		resolved, err := resolveRef(item, item.Ref, "pathItems", d.Components.PathItems, func(p *PathItem) string { return p.Ref })
+		if err != nil {
+			return fmt.Errorf("path item %s: %w", name, err)
+		}
+		d.Components.PathItems[name] = resolved
+	}
+
+	operationIDs := make(map[string]string)
+	for _, path := range sortedKeys(d.Paths) {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path %q must start with /", path)
+		}
+		operations, err := d.resolvePathItem(d.Paths, path)
+		if err != nil {
+			return fmt.Errorf("path %s: %w", path, err)
+		}
+		for _, operation := range operations {
+			if err := validatePathParameters(operation); err != nil {
+				return fmt.Errorf("%s %s: %w", operation.Method, path, err)
+			}
+			if operation.OperationID != "" {
+				if other, taken := operationIDs[operation.OperationID]; taken {
+					return fmt.Errorf("operationId %q of %s %s is already used by %s", operation.OperationID, operation.Method, path, other)
+				}
+				operationIDs[operation.OperationID] = operation.Method + " " + path
+			}
+		}
+		d.operations = append(d.operations, operations...)
+	}
+
+	for _, name := range sortedKeys(d.Webhooks) {
+		if _, err := d.resolvePathItem(d.Webhooks, name); err != nil {
+			return fmt.Errorf("webhook %s: %w", name, err)
+		}
+	}
+
+	for name, response := range d.Components.Responses {
+		resolved, err := d.resolveResponse(response)
+		if err != nil {
+			return fmt.Errorf("response %s: %w", name, err)
+		}
+		d.Components.Responses[name] = resolved
+	}
+	for name, body := range d.Components.RequestBodies {
+		resolved, err := d.resolveRequestBody(body)
+		if err != nil {
+			return fmt.Errorf("request body %s: %w", name, err)
+		}
+		d.Components.RequestBodies[name] = resolved
+	}
+	for name, scheme := range d.Components.SecuritySchemes {
+		resolved, err := resolveRef(scheme, scheme.Ref, "securitySchemes", d.Components.SecuritySchemes, func(s *SecurityScheme) string { return s.Ref })
+		if err != nil {
+			return fmt.Errorf("security scheme %s: %w", name, err)
+		}
+		d.Components.SecuritySchemes[name] = resolved
+	}
+	return nil
+}
+
+// resolvePathItem resolves the path item items[key] and returns its operations, with their
+// Method and Path set and the path item parameters merged into theirs
+func (d *Document) resolvePathItem(items map[string]*PathItem, key string) ([]*Operation, error) {
+	if items[key] == nil {
+		items[key] = &PathItem{}
+	}
+	item := items[key]
+	if item.Ref != "" {
+		target, err := resolveRef(item, item.Ref, "pathItems", d.Components.PathItems, func(p *PathItem) string { return p.Ref })
+		if err != nil {
+			return nil, err
+		}
+		// Every path referencing the component gets its own operations, with their own Path
+		if item, err = target.clone(); err != nil {
+			return nil, err
+		}
+		items[key] = item
+	}
+
+	shared, err := d.resolveParameters(item.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	item.Parameters = shared
+
+	var operations []*Operation
+	for _, method := range httpMethods {
+		operation := item.Operation(method)
+		if operation == nil {
+			continue
+		}
+		operation.Method, operation.Path = method, key
+		if err := d.resolveOperation(operation, shared); err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// clone returns a deep copy of a path item
+func (p *PathItem) clone() (*PathItem, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy path item: %w", err)
+	}
+	var clone PathItem
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to copy path item: %w", err)
+	}
+	return &clone, nil
+}
+
+// resolveOperation resolves the parameters, request body, responses and callbacks of an
+// operation. Path item parameters come first, replaced by operation parameters of the same
+// name and location.
+func (d *Document) resolveOperation(operation *Operation, shared []*Parameter) error {
+	own, err := d.resolveParameters(operation.Parameters)
+	if err != nil {
+		return err
+	}
+
+	parameters := append([]*Parameter(nil), shared...)
+	for _, parameter := range own {
+		overridden := false
+		for i, inherited := range parameters {
+			if inherited.Name == parameter.Name && inherited.In == parameter.In {
+				parameters[i], overridden = parameter, true
+			}
+		}
+		if !overridden {
+			parameters = append(parameters, parameter)
+		}
+	}
+	operation.Parameters = parameters
+
+	if operation.RequestBody != nil {
+		if operation.RequestBody, err = d.resolveRequestBody(operation.RequestBody); err != nil {
+			return err
+		}
+	}
+
+	for code, response := range operation.Responses {
+		if response == nil {
+			response = &Response{}
+		}
+		if operation.Responses[code], err = d.resolveResponse(response); err != nil {
+			return fmt.Errorf("response %s: %w", code, err)
+		}
+	}
+
+	for name, callback := range operation.Callbacks {
+		if callback.Ref != "" {
+			resolved, err := resolveRef(callback, callback.Ref, "callbacks", d.Components.Callbacks, func(c *Callback) string { return c.Ref })
+			if err != nil {
+				return fmt.Errorf("callback %s: %w", name, err)
+			}
+			operation.Callbacks[name] = resolved
+			callback = resolved
+		}
+		for _, expression := range sortedKeys(callback.PathItems) {
+			if _, err := d.resolvePathItem(callback.PathItems, expression); err != nil {
+				return fmt.Errorf("callback %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveParameters resolves and validates a list of parameters
+func (d *Document) resolveParameters(parameters []*Parameter) ([]*Parameter, error) {
+	resolved := make([]*Parameter, len(parameters))
+	for i, parameter := range parameters {
+		target, err := resolveRef(parameter, parameter.Ref, "parameters", d.Components.Parameters, func(p *Parameter) string { return p.Ref })
+		if err != nil {
+			return nil, err
+		}
+		if target.Name == "" {
+			return nil, fmt.Errorf("parameter without a name")
+		}
+		if !parameterLocations[target.In] {
+			return nil, fmt.Errorf("parameter %s: invalid location %q", target.Name, target.In)
+		}
+		if target.In == "path" && !target.Required {
+			return nil, fmt.Errorf("path parameter %s must be required", target.Name)
+		}
+		if err := d.resolveMediaTypes(target.Content); err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", target.Name, err)
+		}
+		resolved[i] = target
+	}
+	return resolved, nil
+}
+
+// resolveRequestBody resolves a request body and the media types of its content
+func (d *Document) resolveRequestBody(body *RequestBody) (*RequestBody, error) {
+	resolved, err := resolveRef(body, body.Ref, "requestBodies", d.Components.RequestBodies, func(b *RequestBody) string { return b.Ref })
+	if err != nil {
+		return nil, err
+	}
+	if err := d.resolveMediaTypes(resolved.Content); err != nil {
+		return nil, fmt.Errorf("request body: %w", err)
+	}
+	return resolved, nil
+}
+
+// resolveResponse resolves a response, its headers and the media types of its content
+func (d *Document) resolveResponse(response *Response) (*Response, error) {
+	resolved, err := resolveRef(response, response.Ref, "responses", d.Components.Responses, func(r *Response) string { return r.Ref })
+	if err != nil {
+		return nil, err
+	}
+	for name, header := range resolved.Headers {
+		target, err := resolveRef(header, header.Ref, "headers", d.Components.Headers, func(h *Header) string { return h.Ref })
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+		resolved.Headers[name] = target
+	}
+	if err := d.resolveMediaTypes(resolved.Content); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// resolveMediaTypes resolves the examples of media types
+func (d *Document) resolveMediaTypes(content map[string]*MediaType) error {
+	for mediaType, media := range content {
+		if media == nil {
+			content[mediaType] = &MediaType{}
+			continue
+		}
+		for name, example := range media.Examples {
+			target, err := resolveRef(example, example.Ref, "examples", d.Components.Examples, func(e *Example) string { return e.Ref })
+			if err != nil {
+				return fmt.Errorf("%s example %s: %w", mediaType, name, err)
+			}
+			media.Examples[name] = target
+		}
+	}
+	return nil
+}
+
+// resolveRef follows ref, a local reference of the form #/components/<kind>/<name>, through
+// chains of references to the component it ends at. value is returned when ref is empty.
+func resolveRef[T any](value *T, ref string, kind string, components map[string]*T, refOf func(*T) string) (*T, error) {
+	seen := make(map[string]bool)
+	for ref != "" {
+		prefix := "#/components/" + kind + "/"
+		if !strings.HasPrefix(ref, prefix) {
+			return nil, fmt.Errorf("unsupported $ref %q: must point at %s<name>", ref, prefix)
+		}
+		name := unescapePointer(strings.TrimPrefix(ref, prefix))
+		if seen[name] {
+			return nil, fmt.Errorf("circular $ref %q", ref)
+		}
+		seen[name] = true
+
+		target, ok := components[name]
+		if !ok || target == nil {
+			return nil, fmt.Errorf("$ref %q does not match any component", ref)
+		}
+		value, ref = target, refOf(target)
+	}
+	return value, nil
+}
+
+// unescapePointer decodes a JSON pointer token (~1 is /, ~0 is ~)
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// validatePathParameters checks that the placeholders of the operation's path template and
+// its path parameters match
+func validatePathParameters(operation *Operation) error {
+	declared := make(map[string]bool)
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "path" {
+			declared[parameter.Name] = true
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(operation.Path, -1) {
+		used[match[1]] = true
+		if !declared[match[1]] {
+			return fmt.Errorf("path parameter {%s} is not declared", match[1])
+		}
+	}
+	for name := range declared {
+		if !used[name] {
+			return fmt.Errorf("path parameter %s does not appear in the path", name)
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
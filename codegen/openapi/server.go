@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// generateServer writes the server side of the API: the ServerInterface implemented by the
+// handlers, the ServerInterfaceWrapper decoding requests and RegisterHandlers routing them with
+// a Go 1.22 http.ServeMux, plus the router adapter of Options.ServerFramework and the strict
+// server of Options.StrictServer, the middleware of Options.ValidationMiddleware and the mock
+// server of Options.MockServer. The Params structs it decodes are written by the caller, as the
+// client shares them.
+func generateServer(out *bytes.Buffer, document *Document, operations []*operation, options *Options) error {
+	for _, op := range operations {
+		if err := validateServeMuxPath(op.Path); err != nil {
+			return fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+	}
+
+	generateServerInterface(out, operations, options)
+	generateUnimplemented(out, operations)
+	generateServerWrapper(out, operations)
+	generateRegisterHandlers(out, operations)
+	generateFrameworkAdapter(out, operations, options.ServerFramework)
+	if options.StrictServer {
+		generateStrictServer(out, operations, options)
+	}
+	if options.ValidationMiddleware {
+		if err := generateValidationMiddleware(out, document, operations); err != nil {
+			return err
+		}
+	}
+	if options.MockServer {
+		return generateMockServer(out, document, operations)
+	}
+	return nil
+}
+
+// handlerSignature returns the parameters of the ServerInterface method of an operation
+func handlerSignature(op *operation) string {
+	signature := "w http.ResponseWriter, r *http.Request"
+	if len(op.params) > 0 {
+		signature += ", params " + op.paramsType()
+	}
+	if op.body != nil {
+		if op.body.required {
+			signature += ", body " + op.body.goType
+		} else {
+			signature += ", body *" + op.body.goType
+		}
+	}
+	return signature
+}
+
+// handlerArguments returns the arguments the wrapper passes to a ServerInterface method
+func handlerArguments(op *operation) string {
+	arguments := "w, r"
+	if len(op.params) > 0 {
+		arguments += ", params"
+	}
+	if op.body != nil {
+		arguments += ", body"
+	}
+	return arguments
+}
+
+// operationComment returns the doc comment lines of the method handling (or, for clients,
+// sending) an operation, indented by indent: its summary, its vendor extensions when
+// extension comments are enabled, and a deprecation notice
+func operationComment(op *operation, indent string, verb string, options *Options) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s// %s %s %s %s\n", indent, op.name, verb, op.Method, op.Path)
+	if options.IncludeComments && op.Summary != "" {
+		for _, line := range strings.Split(strings.TrimSpace(op.Summary), "\n") {
+			fmt.Fprintf(&b, "%s// %s\n", indent, strings.TrimSpace(line))
+		}
+	}
+	if extensions := jrpc.ExtensionComments(op.Extensions); options.ExtensionComments && len(extensions) > 0 {
+		fmt.Fprintf(&b, "%s//\n", indent)
+		for _, extension := range extensions {
+			fmt.Fprintf(&b, "%s// %s\n", indent, extension)
+		}
+	}
+	if op.Deprecated {
+		fmt.Fprintf(&b, "%s//\n%s// Deprecated: the operation is deprecated.\n", indent, indent)
+	}
+	return b.String()
+}
+
+// generateServerInterface writes the interface with one method per operation
+func generateServerInterface(out *bytes.Buffer, operations []*operation, options *Options) {
+	out.WriteString("// ServerInterface is implemented by the handlers of the API operations. Parameters and\n")
+	out.WriteString("// JSON request bodies are decoded before the handlers are called.\n")
+	out.WriteString("type ServerInterface interface {\n")
+	for _, op := range operations {
+		out.WriteString(operationComment(op, "\t", "handles", options))
+		fmt.Fprintf(out, "\t%s(%s)\n", op.name, handlerSignature(op))
+	}
+	out.WriteString("}\n\n")
+}
+
+// generateUnimplemented writes a ServerInterface implementation answering every operation
+// with 501 Not Implemented
+func generateUnimplemented(out *bytes.Buffer, operations []*operation) {
+	out.WriteString("// Unimplemented answers every operation with 501 Not Implemented. Embed it in a\n")
+	out.WriteString("// ServerInterface implementation to implement the operations one at a time.\n")
+	out.WriteString("type Unimplemented struct{}\n\n")
+	for _, op := range operations {
+		fmt.Fprintf(out, "// %s answers %s %s with 501 Not Implemented\n", op.name, op.Method, op.Path)
+		fmt.Fprintf(out, "func (Unimplemented) %s(%s) {\n", op.name, handlerSignature(op))
+		out.WriteString("\tw.WriteHeader(http.StatusNotImplemented)\n}\n\n")
+	}
+}
+
+// generateServerWrapper writes the ServerInterfaceWrapper, with one http.HandlerFunc per
+// operation decoding the parameters and body of the request
+func generateServerWrapper(out *bytes.Buffer, operations []*operation) {
+	out.WriteString(`// ServerInterfaceWrapper decodes the parameters and JSON bodies of requests and calls the
+// matching ServerInterface method
+type ServerInterfaceWrapper struct {
+	Handler          ServerInterface
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error) // Answers requests that cannot be decoded (default: 400 Bad Request)
+}
+
+// handleError answers a request that cannot be decoded
+func (siw *ServerInterfaceWrapper) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if siw.ErrorHandlerFunc != nil {
+		siw.ErrorHandlerFunc(w, r, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+`)
+
+	for _, op := range operations {
+		fmt.Fprintf(out, "// %s decodes a %s %s request and calls Handler.%s\n", op.name, op.Method, op.Path, op.name)
+		fmt.Fprintf(out, "func (siw *ServerInterfaceWrapper) %s(w http.ResponseWriter, r *http.Request) {\n", op.name)
+		if len(op.params) > 0 {
+			fmt.Fprintf(out, "\tparams, err := Decode%s(r)\n", op.paramsType())
+			out.WriteString("\tif err != nil {\n\t\tsiw.handleError(w, r, err)\n\t\treturn\n\t}\n")
+		}
+		if op.body != nil {
+			writeBodyDecoding(out, op.body, "siw")
+		}
+		fmt.Fprintf(out, "\tsiw.Handler.%s(%s)\n}\n\n", op.name, handlerArguments(op))
+	}
+}
+
+// writeBodyDecoding writes the statements decoding the JSON request body in a method of the
+// wrapper named receiver. An empty body decodes to nil when the body is optional.
+func writeBodyDecoding(out *bytes.Buffer, body *body, receiver string) {
+	if body.required {
+		fmt.Fprintf(out, "\tvar body %s\n", body.goType)
+		out.WriteString("\tif err := json.NewDecoder(r.Body).Decode(&body); err != nil {\n")
+		out.WriteString("\t\tif errors.Is(err, io.EOF) {\n\t\t\terr = errors.New(\"request body is required\")\n\t\t}\n")
+	} else {
+		fmt.Fprintf(out, "\tvar body *%s\n", body.goType)
+		out.WriteString("\tif err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {\n")
+	}
+	fmt.Fprintf(out, "\t\t%s.handleError(w, r, fmt.Errorf(\"invalid request body: %%w\", err))\n\t\treturn\n\t}\n", receiver)
+}
+
+// generateRegisterHandlers writes the functions registering the operations on a ServeMux
+func generateRegisterHandlers(out *bytes.Buffer, operations []*operation) {
+	out.WriteString(`// HandlerOptions configures the handlers registered by RegisterHandlersWithOptions
+type HandlerOptions struct {
+	BaseURL          string                                                  // Path prefix of the operations (e.g. "/v1")
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error) // Answers requests that cannot be decoded (default: 400 Bad Request)
+}
+
+// RegisterHandlers registers the operations of si on mux
+func RegisterHandlers(mux *http.ServeMux, si ServerInterface) {
+	RegisterHandlersWithOptions(mux, si, HandlerOptions{})
+}
+
+// RegisterHandlersWithOptions registers the operations of si on mux, with method and path
+// patterns
+func RegisterHandlersWithOptions(mux *http.ServeMux, si ServerInterface, options HandlerOptions) {
+	wrapper := &ServerInterfaceWrapper{Handler: si, ErrorHandlerFunc: options.ErrorHandlerFunc}
+`)
+	for _, op := range operations {
+		fmt.Fprintf(out, "\tmux.HandleFunc(%q+options.BaseURL+%q, wrapper.%s)\n", op.Method+" ", serveMuxPattern(op.Path), op.name)
+	}
+	out.WriteString("}\n\n")
+}
+
+// validateServeMuxPath checks that a path template can be routed by http.ServeMux, whose
+// wildcards must span whole path segments
+func validateServeMuxPath(path string) error {
+	for _, segment := range strings.Split(path, "/") {
+		whole := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && strings.Count(segment, "{") == 1
+		if strings.ContainsAny(segment, "{}") && !whole {
+			return fmt.Errorf("segment %q: http.ServeMux only routes parameters spanning whole path segments", segment)
+		}
+	}
+	return nil
+}
+
+// serveMuxPattern converts a path template to a ServeMux pattern: parameter names become valid
+// wildcard names, and a trailing slash only matches itself rather than the whole subtree
+func serveMuxPattern(path string) string {
+	pattern := pathParameterPattern.ReplaceAllStringFunc(path, func(placeholder string) string {
+		return "{" + serveMuxWildcard(strings.Trim(placeholder, "{}")) + "}"
+	})
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+	return pattern
+}
+
+// serveMuxWildcard returns the ServeMux wildcard name of a path parameter, which must be a Go
+// identifier
+func serveMuxWildcard(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
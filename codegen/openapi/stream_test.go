@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+// streamTest is the template of a test of a generated streaming method reading the response
+// of a fake server: %[1]s is the name of the test, %[2]q the status and %[3]q the body of the
+// response, %[4]q the contents of the events the method yields, joined with commas, and %[5]q
+// the error it returns, if any
+const streamTest = `
+func Test%[1]s(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(%[2]d)
+		fmt.Fprint(w, %[3]q)
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := client.CreateChatCompletionStream(context.Background(), Request{})
+	if %[5]q != "" {
+		var apiErr APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode() != %[2]d || err.Error() != %[5]q {
+			t.Fatalf("error = %%v, want an APIError %%q", err, %[5]q)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for event, err := range events {
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, *event.Data.Content)
+	}
+	if got := strings.Join(contents, ","); got != %[4]q {
+		t.Errorf("events = %%q, want %%q", got, %[4]q)
+	}
+}
+`
+
+// typedStreamErrorTest checks that a stream method returns the typed error of a declared error
+// response, with its decoded body
+const typedStreamErrorTest = `
+func TestTypedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, ` + "`" + `{"message": "slow down"}` + "`" + `)
+	}))
+	defer server.Close()
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateChatCompletionStream(context.Background(), Request{})
+	var tooMany *CreateChatCompletionTooManyRequestsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("error = %v, want a CreateChatCompletionTooManyRequestsError", err)
+	}
+	if tooMany.Body.Message == nil || *tooMany.Body.Message != "slow down" {
+		t.Errorf("body = %+v, want the decoded message", tooMany.Body)
+	}
+}
+`
+
+func TestStreams(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		events string
+		err    string
+	}{
+		{"Events", 200, "data: {\"content\": \"a\"}\n\nevent: chunk\ndata: {\"content\": \"b\"}\n\n", "a,b", ""},
+		{"LastEventAtEOF", 200, "data: {\"content\": \"a\"}\n\ndata: {\"content\": \"b\"}", "a,b", ""},
+		{"Done", 200, "data: {\"content\": \"a\"}\n\ndata: [DONE]\n\ndata: {\"content\": \"b\"}\n\n", "a", ""},
+		{"ErrorStatus", 503, "overloaded", "", "CreateChatCompletion: 503 Service Unavailable: overloaded"},
+	}
+
+	document, err := os.ReadFile(filepath.Join("testdata", "stream.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := GenerateTo(&source, document, &Options{PackageName: "generated", GenerateClient: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var generatedTests strings.Builder
+	generatedTests.WriteString("package generated\n\nimport (\n\t\"context\"\n\t\"errors\"\n\t\"fmt\"\n\t\"net/http\"\n\t\"net/http/httptest\"\n\t\"strings\"\n\t\"testing\"\n)\n")
+	for _, test := range tests {
+		fmt.Fprintf(&generatedTests, streamTest, test.name, test.status, test.body, test.events, test.err)
+	}
+	generatedTests.WriteString(typedStreamErrorTest)
+
+	gentest.Run(t, map[string]string{
+		"client.go":      source.String(),
+		"client_test.go": generatedTests.String(),
+	}, "test", "./...")
+}
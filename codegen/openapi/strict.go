@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// generateStrictServer writes the strict server: the StrictServerInterface whose handlers take
+// a request object and return a response object, one type per declared status code and media
+// type writing itself, and NewStrictHandler serving a StrictServerInterface as a
+// ServerInterface. Handlers cannot return responses the document does not declare, as only
+// the generated response types implement the response object interfaces.
+func generateStrictServer(out *bytes.Buffer, operations []*operation, options *Options) {
+	for _, op := range operations {
+		generateRequestObject(out, op)
+		generateResponseObjects(out, op)
+	}
+
+	out.WriteString("// StrictServerInterface is implemented by handlers returning the typed responses of their\n")
+	out.WriteString("// operation. NewStrictHandler serves it as a ServerInterface.\n")
+	out.WriteString("type StrictServerInterface interface {\n")
+	for _, op := range operations {
+		out.WriteString(operationComment(op, "\t", "handles", options))
+		fmt.Fprintf(out, "\t%s(ctx context.Context, request %sRequestObject) (%sResponseObject, error)\n", op.name, op.name, op.name)
+	}
+	out.WriteString("}\n\n")
+
+	out.WriteString(`// StrictHandlerOptions configures the ServerInterface returned by NewStrictHandlerWithOptions
+type StrictHandlerOptions struct {
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error) // Answers requests whose handler failed (default: 500 Internal Server Error)
+}
+
+// NewStrictHandler returns a ServerInterface calling the handlers of ssi and writing the
+// responses they return
+func NewStrictHandler(ssi StrictServerInterface) ServerInterface {
+	return NewStrictHandlerWithOptions(ssi, StrictHandlerOptions{})
+}
+
+// NewStrictHandlerWithOptions returns a ServerInterface calling the handlers of ssi and
+// writing the responses they return
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, options StrictHandlerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, options: options}
+}
+
+// strictHandler serves a StrictServerInterface as a ServerInterface
+type strictHandler struct {
+	ssi     StrictServerInterface
+	options StrictHandlerOptions
+}
+
+// handleError answers a request whose handler returned an error or no response, or whose
+// response could not be written
+func (sh *strictHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if sh.options.ResponseErrorHandlerFunc != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+`)
+	for _, op := range operations {
+		fmt.Fprintf(out, "// %s calls StrictServerInterface.%s and writes its response\n", op.name, op.name)
+		fmt.Fprintf(out, "func (sh *strictHandler) %s(%s) {\n", op.name, handlerSignature(op))
+		fmt.Fprintf(out, "\trequest := %sRequestObject{", op.name)
+		var fields []string
+		if len(op.params) > 0 {
+			fields = append(fields, "Params: params")
+		}
+		if op.body != nil {
+			fields = append(fields, "Body: body")
+		} else if op.RequestBody != nil {
+			fields = append(fields, "Body: r.Body")
+		}
+		out.WriteString(strings.Join(fields, ", ") + "}\n")
+		fmt.Fprintf(out, "\tresponse, err := sh.ssi.%s(r.Context(), request)\n", op.name)
+		fmt.Fprintf(out, "\tif err == nil && response == nil {\n\t\terr = errors.New(\"%s returned no response\")\n\t}\n", op.name)
+		fmt.Fprintf(out, "\tif err == nil {\n\t\terr = response.Visit%sResponse(w)\n\t}\n", op.name)
+		out.WriteString("\tif err != nil {\n\t\tsh.handleError(w, r, err)\n\t}\n}\n\n")
+	}
+}
+
+// generateRequestObject writes the struct passing the decoded request of an operation to its
+// strict handler. Request bodies that are not JSON are passed as the unread io.Reader.
+func generateRequestObject(out *bytes.Buffer, op *operation) {
+	fmt.Fprintf(out, "// %sRequestObject is the decoded request of %s\n", op.name, op.name)
+	fmt.Fprintf(out, "type %sRequestObject struct {\n", op.name)
+	if len(op.params) > 0 {
+		fmt.Fprintf(out, "\tParams %s\n", op.paramsType())
+	}
+	switch {
+	case op.body != nil && op.body.required:
+		fmt.Fprintf(out, "\tBody %s\n", op.body.goType)
+	case op.body != nil:
+		fmt.Fprintf(out, "\tBody *%s\n", op.body.goType)
+	case op.RequestBody != nil:
+		out.WriteString("\tBody io.Reader\n")
+	}
+	out.WriteString("}\n\n")
+}
+
+// generateResponseObjects writes the response object interface of an operation and the
+// response types implementing it
+func generateResponseObjects(out *bytes.Buffer, op *operation) {
+	visit := "Visit" + op.name + "Response"
+	fmt.Fprintf(out, "// %sResponseObject is a response of %s, one of the %s...Response types\n", op.name, op.name, op.name)
+	fmt.Fprintf(out, "type %sResponseObject interface {\n", op.name)
+	fmt.Fprintf(out, "\t%s(w http.ResponseWriter) error\n}\n\n", visit)
+
+	headerTypes := make(map[string]bool)
+	for _, response := range op.responses {
+		if response.headersType != "" && !headerTypes[response.headersType] {
+			headerTypes[response.headersType] = true
+			generateResponseHeaders(out, op, response)
+		}
+
+		fmt.Fprintf(out, "// %s is the %s response of %s", response.typeName, response.statusCode, op.name)
+		if response.contentType != "" {
+			fmt.Fprintf(out, ", in %s", response.contentType)
+		}
+		var fields []string
+		if response.kind != "" {
+			fields = append(fields, "Body "+response.bodyType)
+		}
+		if response.headersType != "" {
+			fields = append(fields, "Headers "+response.headersType)
+		}
+		if strings.Contains(response.contentType, "*") {
+			fields = append(fields, "ContentType string // Media type of the body, matching "+response.contentType)
+		}
+		if !response.fixedStatus() {
+			fields = append(fields, "StatusCode int")
+		}
+		if len(fields) == 0 {
+			fmt.Fprintf(out, "\ntype %s struct{}\n\n", response.typeName)
+		} else {
+			fmt.Fprintf(out, "\ntype %s struct {\n\t%s\n}\n\n", response.typeName, strings.Join(fields, "\n\t"))
+		}
+
+		fmt.Fprintf(out, "// %s writes the response\n", visit)
+		fmt.Fprintf(out, "func (response %s) %s(w http.ResponseWriter) error {\n", response.typeName, visit)
+		for _, header := range response.headers {
+			writeResponseHeader(out, header)
+		}
+		switch {
+		case strings.Contains(response.contentType, "*"):
+			out.WriteString("\tw.Header().Set(\"Content-Type\", response.ContentType)\n")
+		case response.contentType != "":
+			fmt.Fprintf(out, "\tw.Header().Set(\"Content-Type\", %q)\n", response.contentType)
+		}
+		if response.fixedStatus() {
+			fmt.Fprintf(out, "\tw.WriteHeader(%s)\n", response.statusCode)
+		} else {
+			out.WriteString("\tw.WriteHeader(response.StatusCode)\n")
+		}
+		switch response.kind {
+		case "json":
+			out.WriteString("\treturn json.NewEncoder(w).Encode(response.Body)\n")
+		case "text":
+			out.WriteString("\t_, err := io.WriteString(w, response.Body)\n\treturn err\n")
+		case "binary":
+			out.WriteString("\tif response.Body == nil {\n\t\treturn nil\n\t}\n")
+			out.WriteString("\t_, err := io.Copy(w, response.Body)\n\treturn err\n")
+		default:
+			out.WriteString("\treturn nil\n")
+		}
+		out.WriteString("}\n\n")
+	}
+}
+
+// generateResponseHeaders writes the struct holding the headers of a response. Optional
+// scalar headers are pointers and are only written when set.
+func generateResponseHeaders(out *bytes.Buffer, op *operation, response response) {
+	fmt.Fprintf(out, "// %s are the headers of the %s response of %s\n", response.headersType, response.statusCode, op.name)
+	fmt.Fprintf(out, "type %s struct {\n", response.headersType)
+	for _, header := range response.headers {
+		fieldType := header.goType
+		if !header.required && header.itemType == "" {
+			fieldType = "*" + fieldType
+		}
+		fmt.Fprintf(out, "\t%s %s // %s\n", header.fieldName, fieldType, header.name)
+	}
+	out.WriteString("}\n\n")
+}
+
+// writeResponseHeader writes the statements setting a header of a response
+func writeResponseHeader(out *bytes.Buffer, header responseHeader) {
+	field := "response.Headers." + header.fieldName
+	switch {
+	case header.itemType != "":
+		fmt.Fprintf(out, "\tfor _, value := range %s {\n\t\tw.Header().Add(%q, fmt.Sprint(value))\n\t}\n", field, header.name)
+	case header.required:
+		fmt.Fprintf(out, "\tw.Header().Set(%q, fmt.Sprint(%s))\n", header.name, field)
+	default:
+		fmt.Fprintf(out, "\tif %s != nil {\n\t\tw.Header().Set(%q, fmt.Sprint(*%s))\n\t}\n", field, header.name, field)
+	}
+}
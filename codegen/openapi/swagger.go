@@ -0,0 +1,435 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// swaggerSchemaKeywords are the keywords of Swagger 2.0 non-body parameters and headers that
+// describe their value, and move into their schema in OpenAPI 3
+var swaggerSchemaKeywords = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum",
+	"exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems",
+	"uniqueItems", "enum", "multipleOf",
+}
+
+// swaggerOAuthFlows maps the Swagger 2.0 OAuth 2.0 flows to their OpenAPI 3 names
+var swaggerOAuthFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// swaggerConverter converts a decoded Swagger 2.0 document to OpenAPI 3.0
+type swaggerConverter struct {
+	document map[string]any
+	consumes []any // Global request media types
+	produces []any // Global response media types
+}
+
+// convertSwagger converts a Swagger 2.0 document to the OpenAPI 3.0 document it describes:
+// definitions become component schemas, body and formData parameters request bodies, and
+// collectionFormat a parameter style. The $refs are rewritten to their new locations.
+func convertSwagger(document map[string]any) (map[string]any, error) {
+	c := &swaggerConverter{
+		document: document,
+		consumes: mediaTypesOf(document["consumes"]),
+		produces: mediaTypesOf(document["produces"]),
+	}
+
+	converted := map[string]any{"openapi": "3.0.3", "info": document["info"]}
+	for _, key := range []string{"security", "tags", "externalDocs"} {
+		if value, ok := document[key]; ok {
+			converted[key] = value
+		}
+	}
+	if servers := c.servers(); len(servers) > 0 {
+		converted["servers"] = servers
+	}
+
+	components := make(map[string]any)
+	if definitions, ok := document["definitions"].(map[string]any); ok {
+		components["schemas"] = definitions
+	}
+	parameters, requestBodies := make(map[string]any), make(map[string]any)
+	for name, value := range asMap(document["parameters"]) {
+		parameter := asMap(value)
+		switch parameter["in"] {
+		case "body":
+			requestBodies[name] = c.requestBody([]map[string]any{parameter}, c.consumes)
+		case "formData":
+			return nil, fmt.Errorf("parameter %s: formData parameters must be declared on operations", name)
+		default:
+			parameters[name] = c.parameter(parameter)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+	responses := make(map[string]any)
+	for name, value := range asMap(document["responses"]) {
+		responses[name] = c.response(asMap(value), c.produces)
+	}
+	if len(responses) > 0 {
+		components["responses"] = responses
+	}
+	if schemes := c.securitySchemes(); len(schemes) > 0 {
+		components["securitySchemes"] = schemes
+	}
+	converted["components"] = components
+
+	paths := make(map[string]any)
+	for path, value := range asMap(document["paths"]) {
+		item, err := c.pathItem(asMap(value))
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		paths[path] = item
+	}
+	converted["paths"] = paths
+
+	rewriteSwaggerSchemas(converted)
+	return converted, nil
+}
+
+// servers returns the server URLs of host, basePath and schemes
+func (c *swaggerConverter) servers() []any {
+	host, _ := c.document["host"].(string)
+	basePath, _ := c.document["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	schemes := mediaTypesOf(c.document["schemes"])
+	if len(schemes) == 0 {
+		schemes = []any{"https"}
+	}
+	var servers []any
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{"url": fmt.Sprintf("%s://%s%s", scheme, host, basePath)})
+	}
+	return servers
+}
+
+// pathItem converts a path item: the body and formData parameters of the path item and of
+// each operation become the operation's request body
+func (c *swaggerConverter) pathItem(item map[string]any) (map[string]any, error) {
+	converted := make(map[string]any)
+	shared, sharedBody := c.splitParameters(item["parameters"])
+	if len(shared) > 0 {
+		converted["parameters"] = shared
+	}
+	for key, value := range item {
+		switch key {
+		case "parameters":
+		case "get", "put", "post", "delete", "options", "head", "patch":
+			operation, err := c.operation(asMap(value), sharedBody)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			converted[key] = operation
+		default:
+			converted[key] = value
+		}
+	}
+	return converted, nil
+}
+
+// operation converts an operation. Its consumes and produces replace the global ones.
+func (c *swaggerConverter) operation(operation map[string]any, sharedBody []map[string]any) (map[string]any, error) {
+	consumes, produces := c.consumes, c.produces
+	if value, ok := operation["consumes"]; ok {
+		consumes = mediaTypesOf(value)
+	}
+	if value, ok := operation["produces"]; ok {
+		produces = mediaTypesOf(value)
+	}
+
+	converted := make(map[string]any)
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "schemes", "parameters":
+		case "responses":
+			responses := make(map[string]any)
+			for code, response := range asMap(value) {
+				responses[code] = c.response(asMap(response), produces)
+			}
+			converted[key] = responses
+		default:
+			converted[key] = value
+		}
+	}
+
+	parameters, body := c.splitParameters(operation["parameters"])
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+	// Operation body parameters override the path item's, as other parameters do
+	if len(body) == 0 {
+		body = sharedBody
+	}
+	if len(body) > 0 {
+		if ref, ok := body[0]["$ref"].(string); ok && len(body) == 1 {
+			converted["requestBody"] = map[string]any{"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/")}
+		} else {
+			converted["requestBody"] = c.requestBody(body, consumes)
+		}
+	}
+	return converted, nil
+}
+
+// splitParameters converts a parameter list, separating the body and formData parameters,
+// which become a request body
+func (c *swaggerConverter) splitParameters(value any) ([]any, []map[string]any) {
+	var parameters []any
+	var body []map[string]any
+	for _, item := range asSlice(value) {
+		parameter := asMap(item)
+		if ref, ok := parameter["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/parameters/")
+			if target := asMap(asMap(c.document["parameters"])[name]); target["in"] == "body" || target["in"] == "formData" {
+				if target["in"] == "formData" {
+					body = append(body, target)
+				} else {
+					body = append(body, parameter)
+				}
+				continue
+			}
+			parameters = append(parameters, map[string]any{"$ref": "#/components/parameters/" + name})
+			continue
+		}
+		if parameter["in"] == "body" || parameter["in"] == "formData" {
+			body = append(body, parameter)
+			continue
+		}
+		parameters = append(parameters, c.parameter(parameter))
+	}
+	return parameters, body
+}
+
+// parameter converts a path, query or header parameter: its type keywords move into a schema
+// and collectionFormat becomes a style
+func (c *swaggerConverter) parameter(parameter map[string]any) map[string]any {
+	converted := map[string]any{"schema": valueSchema(parameter)}
+	for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if value, ok := parameter[key]; ok {
+			converted[key] = value
+		}
+	}
+	for key, value := range parameter {
+		if strings.HasPrefix(key, "x-") {
+			converted[key] = value
+		}
+	}
+
+	if parameter["type"] == "array" {
+		format, _ := parameter["collectionFormat"].(string)
+		switch format {
+		case "multi":
+			converted["style"], converted["explode"] = "form", true
+		case "ssv":
+			converted["style"], converted["explode"] = "spaceDelimited", false
+		case "pipes":
+			converted["style"], converted["explode"] = "pipeDelimited", false
+		default: // csv, and tsv which OpenAPI 3 cannot express
+			if parameter["in"] == "query" {
+				converted["style"], converted["explode"] = "form", false
+			}
+		}
+	}
+	return converted
+}
+
+// requestBody converts body or formData parameters to a request body. formData parameters are
+// the properties of a multipart/form-data body when they include a file, and of a URL-encoded
+// form otherwise, unless consumes names the form media type.
+func (c *swaggerConverter) requestBody(parameters []map[string]any, consumes []any) map[string]any {
+	if parameters[0]["in"] == "body" {
+		body := map[string]any{}
+		if description, ok := parameters[0]["description"]; ok {
+			body["description"] = description
+		}
+		if required, ok := parameters[0]["required"]; ok {
+			body["required"] = required
+		}
+		if len(consumes) == 0 {
+			consumes = []any{"application/json"}
+		}
+		content := make(map[string]any)
+		for _, mediaType := range consumes {
+			content[fmt.Sprint(mediaType)] = map[string]any{"schema": parameters[0]["schema"]}
+		}
+		body["content"] = content
+		return body
+	}
+
+	properties := make(map[string]any)
+	var required []any
+	mediaType := "application/x-www-form-urlencoded"
+	for _, parameter := range parameters {
+		name := fmt.Sprint(parameter["name"])
+		schema := valueSchema(parameter)
+		if parameter["type"] == "file" {
+			mediaType = "multipart/form-data"
+		}
+		if description, ok := parameter["description"]; ok {
+			schema["description"] = description
+		}
+		properties[name] = schema
+		if parameter["required"] == true {
+			required = append(required, name)
+		}
+	}
+	for _, consumed := range consumes {
+		if consumed == "multipart/form-data" {
+			mediaType = "multipart/form-data"
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return map[string]any{
+		"required": len(required) > 0,
+		"content":  map[string]any{mediaType: map[string]any{"schema": schema}},
+	}
+}
+
+// response converts a response: its schema is served in every produced media type, and its
+// headers get a schema
+func (c *swaggerConverter) response(response map[string]any, produces []any) map[string]any {
+	if ref, ok := response["$ref"].(string); ok {
+		return map[string]any{"$ref": "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")}
+	}
+
+	converted := map[string]any{"description": response["description"]}
+	if converted["description"] == nil {
+		converted["description"] = ""
+	}
+	if schema, ok := response["schema"]; ok {
+		if len(produces) == 0 {
+			produces = []any{"application/json"}
+		}
+		content := make(map[string]any)
+		for _, mediaType := range produces {
+			content[fmt.Sprint(mediaType)] = map[string]any{"schema": schema}
+		}
+		converted["content"] = content
+	}
+	if headers := asMap(response["headers"]); len(headers) > 0 {
+		convertedHeaders := make(map[string]any)
+		for name, value := range headers {
+			header := asMap(value)
+			convertedHeader := map[string]any{"schema": valueSchema(header)}
+			if description, ok := header["description"]; ok {
+				convertedHeader["description"] = description
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+	return converted
+}
+
+// securitySchemes converts the securityDefinitions of the document
+func (c *swaggerConverter) securitySchemes() map[string]any {
+	schemes := make(map[string]any)
+	for name, value := range asMap(c.document["securityDefinitions"]) {
+		definition := asMap(value)
+		scheme := make(map[string]any)
+		if description, ok := definition["description"]; ok {
+			scheme["description"] = description
+		}
+		switch definition["type"] {
+		case "basic":
+			scheme["type"], scheme["scheme"] = "http", "basic"
+		case "apiKey":
+			scheme["type"], scheme["name"], scheme["in"] = "apiKey", definition["name"], definition["in"]
+		case "oauth2":
+			flow := map[string]any{"scopes": definition["scopes"]}
+			if flow["scopes"] == nil {
+				flow["scopes"] = map[string]any{}
+			}
+			if url, ok := definition["authorizationUrl"]; ok {
+				flow["authorizationUrl"] = url
+			}
+			if url, ok := definition["tokenUrl"]; ok {
+				flow["tokenUrl"] = url
+			}
+			scheme["type"] = "oauth2"
+			scheme["flows"] = map[string]any{swaggerOAuthFlows[fmt.Sprint(definition["flow"])]: flow}
+		default:
+			continue
+		}
+		schemes[name] = scheme
+	}
+	return schemes
+}
+
+// valueSchema returns the schema of a non-body parameter or header, made of its type keywords
+func valueSchema(value map[string]any) map[string]any {
+	schema := make(map[string]any)
+	for _, key := range swaggerSchemaKeywords {
+		if keyword, ok := value[key]; ok {
+			schema[key] = keyword
+		}
+	}
+	if schema["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+	return schema
+}
+
+// rewriteSwaggerSchemas rewrites the $refs of a converted document to the OpenAPI 3 component
+// locations, and the Swagger 2.0 schema extensions to their OpenAPI 3 keywords
+func rewriteSwaggerSchemas(value any) {
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := value["$ref"].(string); ok {
+			for from, to := range map[string]string{"#/definitions/": "#/components/schemas/", "#/responses/": "#/components/responses/", "#/parameters/": "#/components/parameters/"} {
+				if strings.HasPrefix(ref, from) {
+					value["$ref"] = to + strings.TrimPrefix(ref, from)
+				}
+			}
+		}
+		if nullable, ok := value["x-nullable"]; ok {
+			value["nullable"] = nullable
+			delete(value, "x-nullable")
+		}
+		if value["type"] == "file" {
+			value["type"], value["format"] = "string", "binary"
+		}
+		for _, child := range value {
+			rewriteSwaggerSchemas(child)
+		}
+	case []any:
+		for _, child := range value {
+			rewriteSwaggerSchemas(child)
+		}
+	}
+}
+
+// mediaTypesOf returns a consumes, produces or schemes list
+func mediaTypesOf(value any) []any {
+	return asSlice(value)
+}
+
+// asMap returns value as a JSON object, or nil
+func asMap(value any) map[string]any {
+	object, _ := value.(map[string]any)
+	return object
+}
+
+// asSlice returns value as a JSON array, or nil
+func asSlice(value any) []any {
+	array, _ := value.([]any)
+	return array
+}
@@ -0,0 +1,545 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// schemaAnnotations are the schema keywords that do not constrain values, left out of the
+// schemas embedded for the validation middleware
+var schemaAnnotations = map[string]bool{
+	"title": true, "description": true, "default": true, "example": true, "examples": true,
+	"externalDocs": true, "xml": true, "deprecated": true, "discriminator": true,
+}
+
+// generateValidationMiddleware writes ValidateRequests, the net/http middleware checking the
+// parameters and JSON request bodies of the operations against their schemas before the
+// handlers see them. The schemas are embedded in the generated code, together with the
+// component schemas they reference, and checked by a validator written with them.
+func generateValidationMiddleware(out *bytes.Buffer, document *Document, operations []*operation) error {
+	embedded := make(map[string]any)
+	referenced := make(map[string]any)
+	for _, op := range operations {
+		parameters := make(map[string]any)
+		for _, param := range op.params {
+			in, ok := parameters[param.In].(map[string]any)
+			if !ok {
+				in = make(map[string]any)
+				parameters[param.In] = in
+			}
+			in[param.Name] = schemaAssertions(param.schema)
+			collectSchemaRefs(param.schema, document.Components.Schemas, referenced)
+		}
+		schemas := map[string]any{"parameters": parameters}
+		if op.body != nil {
+			schemas["body"] = schemaAssertions(op.body.schema)
+			collectSchemaRefs(op.body.schema, document.Components.Schemas, referenced)
+		}
+		embedded[op.name] = schemas
+	}
+	components := make(map[string]any, len(referenced))
+	for name, schema := range referenced {
+		components[name] = schemaAssertions(schema)
+	}
+	data, err := json.Marshal(map[string]any{
+		"components": map[string]any{"schemas": components},
+		"operations": embedded,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request schemas: %w", err)
+	}
+
+	out.WriteString("// requestSchemaDocument holds the schemas of the parameters and JSON request bodies of the\n")
+	out.WriteString("// operations, with the component schemas they reference, checked by ValidateRequests\n")
+	fmt.Fprintf(out, "const requestSchemaDocument = %s\n\n", strconv.Quote(string(data)))
+
+	out.WriteString(`// ValidationOptions configures the middleware returned by ValidateRequestsWithOptions
+type ValidationOptions struct {
+	BaseURL          string                                                             // Path prefix of the operations (e.g. "/v1")
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, problem RequestProblem) // Answers invalid requests (default: the problem as application/problem+json)
+}
+
+// reject answers a request violating the API description
+func (o ValidationOptions) reject(w http.ResponseWriter, r *http.Request, violations []RequestViolation) {
+	problem := RequestProblem{
+		Type:       "about:blank",
+		Title:      http.StatusText(http.StatusBadRequest),
+		Status:     http.StatusBadRequest,
+		Detail:     "The request does not conform to the API description.",
+		Instance:   r.URL.Path,
+		Violations: violations,
+	}
+	if o.ErrorHandlerFunc != nil {
+		o.ErrorHandlerFunc(w, r, problem)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// ValidateRequests returns a middleware checking the parameters and JSON bodies of the
+// requests of the operations against the API description before calling next. Invalid
+// requests are answered with a 400 RFC 7807 problem; the others, including those of no
+// operation, are passed to next.
+func ValidateRequests(next http.Handler) http.Handler {
+	return ValidateRequestsWithOptions(next, ValidationOptions{})
+}
+
+// ValidateRequestsWithOptions returns the middleware of ValidateRequests, with options
+func ValidateRequestsWithOptions(next http.Handler, options ValidationOptions) http.Handler {
+	mux := http.NewServeMux()
+`)
+	for _, op := range operations {
+		if len(op.params) == 0 && op.body == nil {
+			continue
+		}
+		fmt.Fprintf(out, "\tmux.HandleFunc(%q+options.BaseURL+%q, func(w http.ResponseWriter, r *http.Request) {\n", op.Method+" ", serveMuxPattern(op.Path))
+		if len(op.params) > 0 {
+			fmt.Fprintf(out, "\t\tparams, err := Decode%s(r)\n", op.paramsType())
+			out.WriteString("\t\tif err != nil {\n\t\t\toptions.reject(w, r, []RequestViolation{{Detail: err.Error()}})\n\t\t\treturn\n\t\t}\n")
+		}
+		out.WriteString("\t\tvar violations []RequestViolation\n")
+		for _, param := range op.params {
+			field := "params." + param.fieldName
+			check := func(value string) string {
+				return fmt.Sprintf("violations = append(violations, validateParameter(%q, %q, %q, %s)...)\n", op.name, param.In, param.Name, value)
+			}
+			switch {
+			case param.pointer():
+				fmt.Fprintf(out, "\t\tif %s != nil {\n\t\t\t%s\t\t}\n", field, check("*"+field))
+			case param.nillable() && !param.Required:
+				fmt.Fprintf(out, "\t\tif %s != nil {\n\t\t\t%s\t\t}\n", field, check(field))
+			default:
+				out.WriteString("\t\t" + check(field))
+			}
+		}
+		if op.body != nil {
+			fmt.Fprintf(out, "\t\tviolations = append(violations, validateRequestBody(r, %q, %t)...)\n", op.name, op.body.required)
+		}
+		out.WriteString("\t\tif len(violations) > 0 {\n\t\t\toptions.reject(w, r, violations)\n\t\t\treturn\n\t\t}\n")
+		out.WriteString("\t\tnext.ServeHTTP(w, r)\n\t})\n")
+	}
+	out.WriteString(`	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+`)
+	out.WriteString(validationHelpers)
+	return nil
+}
+
+// schemaAssertions returns a copy of a schema without its annotations, keeping the names of
+// its properties and the values of enum, const and required as they are
+func schemaAssertions(schema any) any {
+	switch schema := schema.(type) {
+	case map[string]any:
+		assertions := make(map[string]any, len(schema))
+		for key, value := range schema {
+			switch {
+			case schemaAnnotations[key] || strings.HasPrefix(key, "x-"):
+			case key == "enum" || key == "const" || key == "required":
+				assertions[key] = value
+			case key == "properties" || key == "patternProperties" || key == "$defs":
+				properties, _ := value.(map[string]any)
+				kept := make(map[string]any, len(properties))
+				for name, property := range properties {
+					kept[name] = schemaAssertions(property)
+				}
+				assertions[key] = kept
+			default:
+				assertions[key] = schemaAssertions(value)
+			}
+		}
+		return assertions
+	case []any:
+		assertions := make([]any, len(schema))
+		for i, value := range schema {
+			assertions[i] = schemaAssertions(value)
+		}
+		return assertions
+	}
+	return schema
+}
+
+// validationHelpers are the types and functions of the validation middleware that do not
+// depend on the operations
+const validationHelpers = `// RequestProblem is the RFC 7807 problem answering a request that violates the API
+// description, with the violations found
+type RequestProblem struct {
+	Type       string             ` + "`json:\"type\"`" + `
+	Title      string             ` + "`json:\"title\"`" + `
+	Status     int                ` + "`json:\"status\"`" + `
+	Detail     string             ` + "`json:\"detail,omitempty\"`" + `
+	Instance   string             ` + "`json:\"instance,omitempty\"`" + `
+	Violations []RequestViolation ` + "`json:\"violations,omitempty\"`" + `
+}
+
+// RequestViolation is a part of a request violating the API description
+type RequestViolation struct {
+	In      string ` + "`json:\"in,omitempty\"`" + `      // Location of the parameter ("path", "query", "header" or "cookie"), or "body"
+	Name    string ` + "`json:\"name,omitempty\"`" + `    // Name of the parameter
+	Pointer string ` + "`json:\"pointer,omitempty\"`" + ` // JSON pointer to the invalid value within the parameter or body
+	Detail  string ` + "`json:\"detail\"`" + `
+}
+
+// requestSchemas is the decoded requestSchemaDocument
+var requestSchemas = sync.OnceValue(func() map[string]any {
+	var document map[string]any
+	decoder := json.NewDecoder(strings.NewReader(requestSchemaDocument))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		panic(fmt.Sprintf("invalid request schemas: %v", err))
+	}
+	return document
+})
+
+// validateParameter checks the decoded value of a parameter of an operation against its
+// schema
+func validateParameter(operation string, in string, name string, value any) []RequestViolation {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return []RequestViolation{{In: in, Name: name, Detail: err.Error()}}
+	}
+	instance, err := decodeInstance(data)
+	if err != nil {
+		return []RequestViolation{{In: in, Name: name, Detail: err.Error()}}
+	}
+	schema := resolveSchemaPointer("/operations/" + escapeSchemaPointer(operation) + "/parameters/" + in + "/" + escapeSchemaPointer(name))
+	violations := validateSchema(schema, instance, "")
+	for i := range violations {
+		violations[i].In, violations[i].Name = in, name
+	}
+	return violations
+}
+
+// validateRequestBody checks the JSON body of a request of an operation against its schema,
+// leaving the body to be read again by the handler
+func validateRequestBody(r *http.Request, operation string, required bool) []RequestViolation {
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return []RequestViolation{{In: "body", Detail: err.Error()}}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		if required {
+			return []RequestViolation{{In: "body", Detail: "request body is required"}}
+		}
+		return nil
+	}
+	instance, err := decodeInstance(data)
+	if err != nil {
+		return []RequestViolation{{In: "body", Detail: "invalid JSON: " + err.Error()}}
+	}
+	violations := validateSchema(resolveSchemaPointer("/operations/"+escapeSchemaPointer(operation)+"/body"), instance, "")
+	for i := range violations {
+		violations[i].In = "body"
+	}
+	return violations
+}
+
+// decodeInstance decodes a JSON value, keeping numbers as json.Number
+func decodeInstance(data []byte) (any, error) {
+	var instance any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return instance, nil
+}
+
+// resolveSchemaPointer returns the value of requestSchemaDocument a JSON pointer refers to, nil
+// when there is none
+func resolveSchemaPointer(pointer string) any {
+	var value any = requestSchemas()
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")]
+	}
+	return value
+}
+
+// escapeSchemaPointer escapes a JSON pointer token (~ is ~0, / is ~1)
+func escapeSchemaPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// schemaPatterns caches the compiled pattern keywords of the schemas
+var schemaPatterns sync.Map
+
+// validateSchema checks a JSON value against a schema, returning the violations found with the
+// JSON pointer of the values they concern. Unknown keywords and formats are ignored.
+func validateSchema(schema any, value any, pointer string) []RequestViolation {
+	if schema == false {
+		return []RequestViolation{{Pointer: pointer, Detail: "no value is allowed"}}
+	}
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if ref, ok := s["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+		return validateSchema(resolveSchemaPointer(strings.TrimPrefix(ref, "#")), value, pointer)
+	}
+	violation := func(format string, args ...any) []RequestViolation {
+		return []RequestViolation{{Pointer: pointer, Detail: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil && s["nullable"] == true {
+		return nil
+	}
+	if types, ok := s["type"]; ok && !matchesSchemaType(types, value) {
+		return violation("must be of type %v", types)
+	}
+	if values, ok := s["enum"].([]any); ok && !slices.ContainsFunc(values, func(allowed any) bool { return equalJSON(allowed, value) }) {
+		return violation("must be one of %v", values)
+	}
+	if constant, ok := s["const"]; ok && !equalJSON(constant, value) {
+		return violation("must be %v", constant)
+	}
+
+	var violations []RequestViolation
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if limit, ok := schemaNumber(s, "minLength"); ok && float64(length) < limit {
+			violations = append(violations, violation("must be at least %v characters long", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "maxLength"); ok && float64(length) > limit {
+			violations = append(violations, violation("must be at most %v characters long", limit)...)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			compiled, cached := schemaPatterns.Load(pattern)
+			if !cached {
+				compiled, _ = regexp.Compile(pattern)
+				schemaPatterns.Store(pattern, compiled)
+			}
+			if re := compiled.(*regexp.Regexp); re != nil && !re.MatchString(value) {
+				violations = append(violations, violation("must match the pattern %s", pattern)...)
+			}
+		}
+		if format, ok := s["format"].(string); ok && !matchesSchemaFormat(format, value) {
+			violations = append(violations, violation("must be a valid %s", format)...)
+		}
+	case json.Number:
+		number, _ := value.Float64()
+		// OpenAPI 3.0 exclusiveMinimum and exclusiveMaximum are booleans applying to minimum
+		// and maximum, 3.1 ones are bounds of their own
+		if limit, ok := schemaNumber(s, "minimum"); ok && s["exclusiveMinimum"] == true && number <= limit {
+			violations = append(violations, violation("must be greater than %v", limit)...)
+		} else if ok && number < limit {
+			violations = append(violations, violation("must be at least %v", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "maximum"); ok && s["exclusiveMaximum"] == true && number >= limit {
+			violations = append(violations, violation("must be less than %v", limit)...)
+		} else if ok && number > limit {
+			violations = append(violations, violation("must be at most %v", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "exclusiveMinimum"); ok && number <= limit {
+			violations = append(violations, violation("must be greater than %v", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "exclusiveMaximum"); ok && number >= limit {
+			violations = append(violations, violation("must be less than %v", limit)...)
+		}
+		if divisor, ok := schemaNumber(s, "multipleOf"); ok && divisor > 0 {
+			if quotient := number / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				violations = append(violations, violation("must be a multiple of %v", divisor)...)
+			}
+		}
+	case []any:
+		if limit, ok := schemaNumber(s, "minItems"); ok && float64(len(value)) < limit {
+			violations = append(violations, violation("must have at least %v items", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "maxItems"); ok && float64(len(value)) > limit {
+			violations = append(violations, violation("must have at most %v items", limit)...)
+		}
+		if s["uniqueItems"] == true {
+			for i := range value {
+				if slices.ContainsFunc(value[:i], func(other any) bool { return equalJSON(other, value[i]) }) {
+					violations = append(violations, violation("must not contain duplicate items")...)
+					break
+				}
+			}
+		}
+		if items, ok := s["items"]; ok {
+			for i, item := range value {
+				violations = append(violations, validateSchema(items, item, pointer+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case map[string]any:
+		properties, _ := s["properties"].(map[string]any)
+		if required, ok := s["required"].([]any); ok {
+			for _, name := range required {
+				name, _ := name.(string)
+				if _, present := value[name]; present {
+					continue
+				}
+				// Read-only properties are only required in responses
+				if property, _ := properties[name].(map[string]any); property["readOnly"] == true {
+					continue
+				}
+				violations = append(violations, violation("missing required property %q", name)...)
+			}
+		}
+		if limit, ok := schemaNumber(s, "minProperties"); ok && float64(len(value)) < limit {
+			violations = append(violations, violation("must have at least %v properties", limit)...)
+		}
+		if limit, ok := schemaNumber(s, "maxProperties"); ok && float64(len(value)) > limit {
+			violations = append(violations, violation("must have at most %v properties", limit)...)
+		}
+		patternProperties, _ := s["patternProperties"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			property := pointer + "/" + escapeSchemaPointer(name)
+			matched := false
+			if schema, ok := properties[name]; ok {
+				matched = true
+				violations = append(violations, validateSchema(schema, value[name], property)...)
+			}
+			for pattern, schema := range patternProperties {
+				if re, err := regexp.Compile(pattern); err == nil && re.MatchString(name) {
+					matched = true
+					violations = append(violations, validateSchema(schema, value[name], property)...)
+				}
+			}
+			if additional, ok := s["additionalProperties"]; ok && !matched {
+				if additional == false {
+					violations = append(violations, violation("unexpected property %q", name)...)
+				} else {
+					violations = append(violations, validateSchema(additional, value[name], property)...)
+				}
+			}
+		}
+	}
+
+	if schemas, ok := s["allOf"].([]any); ok {
+		for _, schema := range schemas {
+			violations = append(violations, validateSchema(schema, value, pointer)...)
+		}
+	}
+	if schemas, ok := s["anyOf"].([]any); ok && !slices.ContainsFunc(schemas, func(schema any) bool { return len(validateSchema(schema, value, pointer)) == 0 }) {
+		violations = append(violations, violation("must match at least one of the anyOf schemas")...)
+	}
+	if schemas, ok := s["oneOf"].([]any); ok {
+		matches := 0
+		for _, schema := range schemas {
+			if len(validateSchema(schema, value, pointer)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			violations = append(violations, violation("must match exactly one of the oneOf schemas, matches %d", matches)...)
+		}
+	}
+	if schema, ok := s["not"]; ok && len(validateSchema(schema, value, pointer)) == 0 {
+		violations = append(violations, violation("must not match the not schema")...)
+	}
+	return violations
+}
+
+// matchesSchemaType reports whether a JSON value is of one of the types of a type keyword
+func matchesSchemaType(types any, value any) bool {
+	names, ok := types.([]any)
+	if !ok {
+		names = []any{types}
+	}
+	for _, name := range names {
+		switch value := value.(type) {
+		case nil:
+			ok = name == "null"
+		case bool:
+			ok = name == "boolean"
+		case string:
+			ok = name == "string"
+		case json.Number:
+			number, err := value.Float64()
+			ok = name == "number" || name == "integer" && err == nil && number == math.Trunc(number)
+		case []any:
+			ok = name == "array"
+		case map[string]any:
+			ok = name == "object"
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// uuidPattern matches the textual representation of UUIDs
+var uuidPattern = regexp.MustCompile(` + "`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`" + `)
+
+// matchesSchemaFormat reports whether a string is valid in a format. Unknown formats accept
+// any string.
+func matchesSchemaFormat(format string, value string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.IsAbs()
+	case "ipv4":
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is4()
+	case "ipv6":
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is6()
+	}
+	return true
+}
+
+// schemaNumber returns the value of a numeric keyword of a schema
+func schemaNumber(schema map[string]any, keyword string) (float64, bool) {
+	number, ok := schema[keyword].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := number.Float64()
+	return value, err == nil
+}
+
+// equalJSON reports whether two decoded JSON values are equal, comparing numbers by value
+func equalJSON(a any, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equalJSON)
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		return ok && maps.EqualFunc(a, b, equalJSON)
+	}
+	return a == b
+}
+
+`
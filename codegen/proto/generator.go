@@ -0,0 +1,130 @@
+// Package proto provides a generator converting the schemas of a JSON Schema, OpenAPI or
+// OpenRPC document into the proto3 messages and enums of a .proto file
+package proto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// ProtoGenerator implements the Generator interface for .proto files
+type ProtoGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *ProtoGenerator) Name() string {
+	return "proto"
+}
+
+// Description returns a human-readable description
+func (g *ProtoGenerator) Description() string {
+	return "Generates proto3 messages and enums from the schemas of JSON Schema, OpenAPI or OpenRPC documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *ProtoGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes
+func (g *ProtoGenerator) OutputFormats() []string {
+	return []string{".proto"}
+}
+
+// Options for the proto generator
+type Options struct {
+	// IncludeComments writes the descriptions of the schemas as comments
+	IncludeComments bool
+
+	// GoPackage is written as the go_package option of the file (e.g.,
+	// "github.com/org/api/gen;apiv1")
+	GoPackage string
+
+	// Numbering is the field numbering strategy: NumberingDeclaration (default),
+	// NumberingAlphabetical or NumberingHash. A property pins the number of its field with
+	// x-proto-number.
+	Numbering string
+
+	// Report is the path the mapping report is written to, as JSON when it ends in .json and
+	// as Markdown otherwise: the message, enum or inlined type of every schema, the field of
+	// every property and what the conversion loses
+	Report string
+}
+
+// Generate converts the schemas of the document into a .proto file whose package is the
+// package name. Object schemas and unions become messages and string enums enums; the other
+// definitions are inlined where they are used.
+func (g *ProtoGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{IncludeComments: true}
+	}
+	if err := checkNumbering(options.Numbering); err != nil {
+		return err
+	}
+
+	c, err := load(config.SchemaPath, options)
+	if err != nil {
+		return err
+	}
+	if err := c.convert(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(config.OutputPath, []byte(c.render(config.PackageName)), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if options.Report == "" {
+		return nil
+	}
+	report := reportMarkdown(filepath.Base(config.SchemaPath), c.report)
+	if strings.HasSuffix(options.Report, ".json") {
+		if report, err = reportJSON(c.report); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(options.Report, report, 0o644); err != nil {
+		return fmt.Errorf("failed to write mapping report: %w", err)
+	}
+	return nil
+}
+
+// load bundles the document, recording the declaration order of the properties for
+// NumberingDeclaration, and returns its converter
+func load(schemaPath string, options *Options) (*converter, error) {
+	document, err := jrpc.Bundle(schemaPath, &jrpc.GeneratorOptions{PreserveOrder: true})
+	if err != nil {
+		return nil, err
+	}
+	rootName := strings.TrimSuffix(filepath.Base(schemaPath), filepath.Ext(schemaPath))
+	return newConverter(document, rootName, options), nil
+}
+
+// ValidateSchema checks that the document has schemas to convert
+func (g *ProtoGenerator) ValidateSchema(schemaPath string) error {
+	c, err := load(schemaPath, &Options{})
+	if err != nil {
+		return err
+	}
+	if len(c.definitions) == 0 {
+		return fmt.Errorf("no schema definitions found (components.schemas, definitions or $defs)")
+	}
+	return nil
+}
+
+// NewProtoGenerator creates a new instance of the proto generator
+func NewProtoGenerator() *ProtoGenerator {
+	return &ProtoGenerator{}
+}
+
+// Register automatically registers the proto generator with the default registry
+func init() {
+	generator := NewProtoGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register proto generator: %v", err))
+	}
+}
@@ -0,0 +1,62 @@
+package proto
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/internal/gentest"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		options *Options
+	}{
+		{"store", "store.json", nil},
+		{"store_alphabetical", "store.json", &Options{IncludeComments: true, Numbering: NumberingAlphabetical}},
+		{"store_go_package", "store.json", &Options{IncludeComments: true, GoPackage: "example.com/store;store"}},
+		{"tasks", "tasks.yaml", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), test.name+".proto")
+			err := NewProtoGenerator().Generate(codegen.GenerateConfig{
+				SchemaPath:  filepath.Join("testdata", test.schema),
+				OutputPath:  output,
+				PackageName: "store",
+				Options:     test.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gentest.Golden(t, filepath.Join("testdata", test.name+".proto.golden"), source)
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{"store.json", true},
+		{"tasks.yaml", true},
+		{"missing.json", false},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			err := NewProtoGenerator().ValidateSchema(filepath.Join("testdata", test.schema))
+			if (err == nil) != test.valid {
+				t.Errorf("error = %v, want valid %t", err, test.valid)
+			}
+		})
+	}
+}
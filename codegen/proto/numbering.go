@@ -0,0 +1,108 @@
+package proto
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// Field numbering strategies of Options.Numbering
+const (
+	// NumberingDeclaration numbers the fields 1, 2, 3, ... in the order the schema declares
+	// the properties
+	NumberingDeclaration = "declaration"
+
+	// NumberingAlphabetical numbers the fields 1, 2, 3, ... in the alphabetical order of the
+	// properties
+	NumberingAlphabetical = "alphabetical"
+
+	// NumberingHash derives the number of a field from a hash of its property name, so that
+	// adding, removing or reordering properties keeps the numbers of the others. The numbers
+	// are large and take up to 5 bytes per field on the wire.
+	NumberingHash = "hash"
+)
+
+// Field number range of proto3, and the numbers reserved for the protobuf implementation
+const (
+	maxFieldNumber = 1<<29 - 1
+	reservedFirst  = 19000
+	reservedLast   = 19999
+)
+
+// validNumber reports whether a field may use a number
+func validNumber(number int) bool {
+	return number >= 1 && number <= maxFieldNumber && (number < reservedFirst || number > reservedLast)
+}
+
+// assignNumbers numbers the fields of a message following strategy, except those pinned with
+// x-proto-number, and returns the notes of the fields whose hash collided
+func assignNumbers(fields []*field, strategy string) (map[*field][]string, error) {
+	used := map[int]string{}
+	var unpinned []*field
+	for _, f := range fields {
+		if f.number == 0 {
+			unpinned = append(unpinned, f)
+			continue
+		}
+		if !validNumber(f.number) {
+			return nil, fmt.Errorf("x-proto-number %d of property %s is outside 1-%d or in the reserved range %d-%d",
+				f.number, f.property, maxFieldNumber, reservedFirst, reservedLast)
+		}
+		if other, ok := used[f.number]; ok {
+			return nil, fmt.Errorf("properties %s and %s have the same x-proto-number %d", other, f.property, f.number)
+		}
+		used[f.number] = f.property
+	}
+
+	notes := map[*field][]string{}
+	switch strategy {
+	case NumberingDeclaration, "":
+	case NumberingAlphabetical:
+		slices.SortStableFunc(unpinned, func(a, b *field) int {
+			return strings.Compare(a.property, b.property)
+		})
+	case NumberingHash:
+		for _, f := range unpinned {
+			number := hashNumber(f.property)
+			for !validNumber(number) || used[number] != "" {
+				if other := used[number]; other != "" {
+					notes[f] = append(notes[f], fmt.Sprintf("hash collides with property %s", other))
+				}
+				number = number%maxFieldNumber + 1
+			}
+			f.number = number
+			used[number] = f.property
+		}
+		return notes, nil
+	default:
+		return nil, checkNumbering(strategy)
+	}
+
+	next := 1
+	for _, f := range unpinned {
+		for !validNumber(next) || used[next] != "" {
+			next++
+		}
+		f.number = next
+		used[next] = f.property
+	}
+	return notes, nil
+}
+
+// checkNumbering returns an error for an unknown numbering strategy
+func checkNumbering(strategy string) error {
+	switch strategy {
+	case NumberingDeclaration, NumberingAlphabetical, NumberingHash, "":
+		return nil
+	}
+	return fmt.Errorf("unsupported numbering %q: must be %s, %s or %s",
+		strategy, NumberingDeclaration, NumberingAlphabetical, NumberingHash)
+}
+
+// hashNumber returns the field number of a property under NumberingHash
+func hashNumber(property string) int {
+	h := fnv.New32a()
+	h.Write([]byte(property))
+	return int(h.Sum32()%maxFieldNumber) + 1
+}
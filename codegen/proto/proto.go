@@ -0,0 +1,931 @@
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Well-known types the conversion falls back to
+const (
+	timestampType = "google.protobuf.Timestamp"
+	valueType     = "google.protobuf.Value"
+	structType    = "google.protobuf.Struct"
+	listValueType = "google.protobuf.ListValue"
+)
+
+// wellKnownImports are the files declaring the well-known types
+var wellKnownImports = map[string]string{
+	timestampType: "google/protobuf/timestamp.proto",
+	valueType:     "google/protobuf/struct.proto",
+	structType:    "google/protobuf/struct.proto",
+	listValueType: "google/protobuf/struct.proto",
+}
+
+// int64Note explains why 64-bit integers change their JSON encoding
+const int64Note = "encoded as a JSON string by the proto3 JSON mapping"
+
+// fieldType is the proto type of a field
+type fieldType struct {
+	name     string   // scalar, enum or message type; the value type of a map
+	repeated bool     // repeated field
+	isMap    bool     // map<string, name> field
+	message  bool     // whether name is a message, which tracks presence without optional
+	enum     bool     // whether name is an enum the converter declared
+	notes    []string // what the schema loses in the conversion
+}
+
+// String returns the type as written in the proto file
+func (t fieldType) String() string {
+	switch {
+	case t.isMap:
+		return "map<string, " + t.name + ">"
+	case t.repeated:
+		return "repeated " + t.name
+	}
+	return t.name
+}
+
+// with returns the type with notes appended
+func (t fieldType) with(notes ...string) fieldType {
+	t.notes = append(append([]string(nil), t.notes...), notes...)
+	return t
+}
+
+// element is a message or enum declaration of the proto file
+type element interface {
+	write(b *strings.Builder, indent string, comments bool)
+}
+
+// message is a proto message. The fields of a union all belong to the oneof.
+type message struct {
+	name       string
+	parent     *message
+	comment    string
+	deprecated bool
+	oneof      string
+	fields     []*field
+	nested     []element
+}
+
+// fullName returns the name of the message qualified with its parents
+func (m *message) fullName() string {
+	if m.parent == nil {
+		return m.name
+	}
+	return m.parent.fullName() + "." + m.name
+}
+
+// field is a field of a message
+type field struct {
+	property   string // schema property
+	name       string
+	typ        fieldType
+	optional   bool
+	number     int
+	comment    string
+	deprecated bool
+}
+
+// enum is a proto enum; the first value is the zero value
+type enum struct {
+	name    string
+	comment string
+	values  []enumValue
+}
+
+// enumValue is a value of an enum and the schema value it stands for
+type enumValue struct {
+	value  string
+	name   string
+	number int
+}
+
+// property is a property of an object schema, including the properties it inherits from allOf
+type property struct {
+	name     string
+	schema   map[string]any
+	required bool
+	pointer  string
+}
+
+// converter maps the definitions of a schema document to the messages and enums of a proto
+// file, recording the mapping of every schema for the report
+type converter struct {
+	document    map[string]any
+	definitions map[string]any
+	prefix      string // $ref prefix of the definitions, e.g. #/components/schemas/
+	options     *Options
+
+	names     map[string]string // definition: name of its message or enum
+	declared  map[string]bool   // names declared at the top level of the file
+	aliases   map[string]*fieldType
+	resolving map[string]bool // aliases being resolved, which a recursive alias refers back to
+	elements  []element
+	report    []*mapping
+}
+
+// newConverter returns the converter of a bundled document, whose definitions are
+// components.schemas, definitions or $defs, or else the document itself, named after its
+// title or rootName
+func newConverter(document map[string]any, rootName string, options *Options) *converter {
+	c := &converter{
+		document:  document,
+		options:   options,
+		names:     map[string]string{},
+		declared:  map[string]bool{},
+		aliases:   map[string]*fieldType{},
+		resolving: map[string]bool{},
+	}
+	if components, ok := document["components"].(map[string]any); ok {
+		if schemas, ok := components["schemas"].(map[string]any); ok {
+			c.definitions, c.prefix = schemas, "#/components/schemas/"
+		}
+	}
+	for _, key := range []string{"definitions", "$defs"} {
+		if definitions, ok := document[key].(map[string]any); ok && c.definitions == nil {
+			c.definitions, c.prefix = definitions, "#/"+key+"/"
+		}
+	}
+	if c.definitions == nil && document["properties"] != nil {
+		if title, ok := document["title"].(string); ok && title != "" {
+			rootName = title
+		}
+		c.definitions, c.prefix = map[string]any{rootName: document}, "#"
+	}
+	return c
+}
+
+// convert declares a message or enum per object or string enum definition, in alphabetical
+// order; the other definitions are inlined where they are used
+func (c *converter) convert() error {
+	if len(c.definitions) == 0 {
+		return fmt.Errorf("no schema definitions found (components.schemas, definitions or $defs)")
+	}
+	names := make([]string, 0, len(c.definitions))
+	for name := range c.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if schema, ok := c.definitions[name].(map[string]any); ok && definitionKind(schema) != "" {
+			c.names[name] = c.declare(definitionTypeName(name, schema))
+		}
+	}
+
+	for _, name := range names {
+		schema, _ := c.definitions[name].(map[string]any)
+		pointer := c.pointer(name)
+		switch definitionKind(schema) {
+		case "enum":
+			e, m := c.enum(c.names[name], schema, pointer)
+			c.elements = append(c.elements, e)
+			c.report = append(c.report, m)
+		case "message":
+			if _, err := c.message(nil, c.names[name], schema, pointer); err != nil {
+				return err
+			}
+		default:
+			t, err := c.aliasType(name)
+			if err != nil {
+				return err
+			}
+			c.report = append(c.report, &mapping{Schema: pointer, Kind: "inlined", Proto: t.String(), Notes: t.notes})
+		}
+	}
+	return nil
+}
+
+// pointer returns the JSON pointer of a definition
+func (c *converter) pointer(name string) string {
+	if c.prefix == "#" {
+		return "#"
+	}
+	return c.prefix + escapePointer(name)
+}
+
+// declare reserves a unique top-level name
+func (c *converter) declare(name string) string {
+	unique := name
+	for i := 2; c.declared[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	c.declared[unique] = true
+	return unique
+}
+
+// definitionKind returns "enum" for the string enums, "message" for the objects and unions,
+// and an empty string for the definitions inlined where they are used
+func definitionKind(schema map[string]any) string {
+	if schema == nil {
+		return ""
+	}
+	if isStringEnum(schema) {
+		return "enum"
+	}
+	if _, ok := schema["properties"]; ok {
+		return "message"
+	}
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) > 1 {
+		return "message"
+	}
+	if _, variants := unionVariants(schema); len(variants) > 1 {
+		return "message"
+	}
+	return ""
+}
+
+// message declares the message of an object or union schema, nested in parent or at the top
+// level of the file, and returns its type
+func (c *converter) message(parent *message, name string, schema map[string]any, pointer string) (fieldType, error) {
+	m := &message{name: name, parent: parent, deprecated: schema["deprecated"] == true}
+	m.comment, _ = schema["description"].(string)
+	if parent == nil {
+		c.elements = append(c.elements, m)
+	} else {
+		parent.nested = append(parent.nested, m)
+	}
+	mapping := &mapping{Schema: pointer, Kind: "message", Proto: m.fullName()}
+	c.report = append(c.report, mapping)
+
+	var err error
+	if key, variants := unionVariants(schema); len(variants) > 1 && schema["properties"] == nil {
+		err = c.unionFields(m, key, variants, pointer, mapping)
+	} else {
+		err = c.objectFields(m, schema, pointer, mapping)
+	}
+	return fieldType{name: m.name, message: true}, err
+}
+
+// objectFields adds a field per property of an object schema, numbered following
+// options.Numbering unless the property pins its number with x-proto-number
+func (c *converter) objectFields(m *message, schema map[string]any, pointer string, mapping *mapping) error {
+	if _, variants := unionVariants(schema); len(variants) > 0 {
+		mapping.Notes = append(mapping.Notes, "the oneOf/anyOf next to the properties is not represented")
+	}
+
+	taken := map[string]bool{}
+	for _, property := range c.properties(schema, pointer) {
+		t, err := c.typeOf(property.schema, m, jrpc.GoIdentifier(property.name, nil), property.pointer)
+		if err != nil {
+			return err
+		}
+		f := &field{
+			property:   property.name,
+			name:       uniqueName(fieldName(property.name), taken),
+			typ:        t,
+			optional:   !property.required && !t.repeated && !t.isMap && !t.message,
+			deprecated: property.schema["deprecated"] == true,
+		}
+		f.comment, _ = property.schema["description"].(string)
+		if value, ok := property.schema["x-proto-number"]; ok {
+			number, ok := integer(value)
+			if !ok {
+				return fmt.Errorf("%s: x-proto-number must be an integer", property.pointer)
+			}
+			f.number = number
+		}
+		m.fields = append(m.fields, f)
+	}
+
+	notes, err := assignNumbers(m.fields, c.options.Numbering)
+	if err != nil {
+		return fmt.Errorf("message %s: %w", m.fullName(), err)
+	}
+	for _, f := range m.fields {
+		mapping.Fields = append(mapping.Fields, fieldMapping{
+			Property: f.property,
+			Field:    f.name,
+			Number:   f.number,
+			Type:     f.label() + f.typ.String(),
+			Notes:    append(f.typ.notes, notes[f]...),
+		})
+	}
+	return nil
+}
+
+// unionFields adds a field per variant of a oneOf or anyOf schema, in a oneof named after the
+// message. Arrays and maps cannot be oneof fields and become a ListValue or Struct.
+func (c *converter) unionFields(m *message, key string, variants []variant, pointer string, mapping *mapping) error {
+	m.oneof = fieldName(m.name)
+	mapping.Notes = append(mapping.Notes, "the proto3 JSON mapping wraps the value in an object keyed by the name of its oneof field")
+	if key == "anyOf" {
+		mapping.Notes = append(mapping.Notes, "anyOf is mapped to a oneof, which holds a single variant")
+	}
+
+	taken := map[string]bool{m.oneof: true}
+	for i, variant := range variants {
+		hint, named := "Option"+strconv.Itoa(i+1), false
+		if title, ok := variant.schema["title"].(string); ok && title != "" {
+			hint, named = jrpc.GoIdentifier(title, nil), true
+		}
+		if name := c.definitionName(variant.schema); name != "" {
+			hint, named = jrpc.GoIdentifier(name, nil), true
+		}
+		t, err := c.typeOf(variant.schema, m, hint, pointer+variant.pointer)
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.isMap:
+			t = c.wellKnown(structType).with("a map cannot be a oneof field")
+		case t.repeated:
+			t = c.wellKnown(listValueType).with("an array cannot be a oneof field")
+		}
+
+		// Unnamed variants are named after their type, unless it is a message or enum declared
+		// for the variant
+		name := fieldName(hint)
+		if !named && (strings.Contains(t.name, ".") || !t.message && !t.enum) {
+			name = fieldName(t.name[strings.LastIndex(t.name, ".")+1:]) + "_value"
+		}
+		f := &field{property: variant.pointer, name: uniqueName(name, taken), typ: t, number: i + 1}
+		f.comment, _ = variant.schema["description"].(string)
+		m.fields = append(m.fields, f)
+		mapping.Fields = append(mapping.Fields, fieldMapping{
+			Property: variant.pointer,
+			Field:    f.name,
+			Number:   f.number,
+			Type:     t.String(),
+			Notes:    t.notes,
+		})
+	}
+	return nil
+}
+
+// properties returns the properties of an object schema in declaration order, after those of
+// the members of its allOf; a property declared again replaces the earlier one in place
+func (c *converter) properties(schema map[string]any, pointer string) []property {
+	var properties []property
+	index := map[string]int{}
+	required := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var collect func(schema map[string]any, pointer string)
+	collect = func(schema map[string]any, pointer string) {
+		for _, name := range stringList(schema["required"]) {
+			required[name] = true
+		}
+		if allOf, ok := schema["allOf"].([]any); ok {
+			for i, member := range allOf {
+				member, _ := member.(map[string]any)
+				memberPointer := pointer + "/allOf/" + strconv.Itoa(i)
+				if ref, ok := member["$ref"].(string); ok {
+					if visiting[ref] {
+						continue
+					}
+					visiting[ref] = true
+					member, _ = c.resolve(ref)
+					memberPointer = ref
+				}
+				if member != nil {
+					collect(member, memberPointer)
+				}
+			}
+		}
+		definitions, _ := schema["properties"].(map[string]any)
+		for _, name := range jrpc.PropertyNames(schema) {
+			propertySchema, _ := definitions[name].(map[string]any)
+			if propertySchema == nil {
+				propertySchema = map[string]any{}
+			}
+			p := property{name: name, schema: propertySchema, pointer: pointer + "/properties/" + escapePointer(name)}
+			if i, ok := index[name]; ok {
+				properties[i] = p
+				continue
+			}
+			index[name] = len(properties)
+			properties = append(properties, p)
+		}
+	}
+	collect(schema, pointer)
+
+	for i := range properties {
+		properties[i].required = required[properties[i].name]
+	}
+	return properties
+}
+
+// typeOf returns the proto type of a schema. Inline objects, unions and string enums are
+// declared as a message or enum named hint, nested in scope or at the top level of the file.
+func (c *converter) typeOf(schema map[string]any, scope *message, hint string, pointer string) (fieldType, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return c.refType(ref, scope, hint)
+	}
+	if _, variants := unionVariants(schema); len(variants) == 1 {
+		return c.typeOf(variants[0].schema, scope, hint, pointer+variants[0].pointer)
+	}
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) == 1 && schema["properties"] == nil {
+		member, _ := allOf[0].(map[string]any)
+		return c.typeOf(member, scope, hint, pointer+"/allOf/0")
+	}
+	if definitionKind(schema) == "message" {
+		return c.message(scope, c.nestedName(scope, hint), schema, pointer)
+	}
+
+	if values, ok := schema["enum"].([]any); ok {
+		if isStringEnum(schema) {
+			e, m := c.enum(c.nestedName(scope, hint), schema, pointer)
+			if scope == nil {
+				c.elements = append(c.elements, e)
+			} else {
+				scope.nested = append(scope.nested, e)
+				m.Proto = scope.fullName() + "." + e.name
+			}
+			c.report = append(c.report, m)
+			return fieldType{name: e.name, enum: true}, nil
+		}
+		note := "enum values are not enforced: " + renderValues(values)
+		if kind := valueKind(values); kind != "" {
+			schema = map[string]any{"type": kind, "format": schema["format"]}
+			t, err := c.typeOf(schema, scope, hint, pointer)
+			return t.with(note), err
+		}
+		return c.wellKnown(valueType).with(note), nil
+	}
+	if value, ok := schema["const"]; ok {
+		note := "const " + renderValues([]any{value}) + " is not enforced"
+		if kind := valueKind([]any{value}); kind != "" {
+			t, err := c.typeOf(map[string]any{"type": kind}, scope, hint, pointer)
+			return t.with(note), err
+		}
+		return c.wellKnown(valueType).with(note), nil
+	}
+
+	types := schemaTypes(schema)
+	if len(types) > 1 {
+		return c.wellKnown(valueType).with("multiple types: " + strings.Join(types, ", ")), nil
+	}
+	if len(types) == 0 {
+		return c.wellKnown(valueType), nil
+	}
+
+	format, _ := schema["format"].(string)
+	switch types[0] {
+	case "string":
+		switch format {
+		case "date-time":
+			return c.wellKnown(timestampType), nil
+		case "byte", "binary":
+			t := fieldType{name: "bytes"}
+			if format == "binary" {
+				t = t.with("the proto3 JSON mapping encodes bytes as base64")
+			}
+			return t, nil
+		case "":
+			return fieldType{name: "string"}, nil
+		}
+		return fieldType{name: "string"}.with("format " + format + " is not enforced"), nil
+	case "integer":
+		switch format {
+		case "int32":
+			return fieldType{name: "int32"}, nil
+		case "uint32":
+			return fieldType{name: "uint32"}, nil
+		case "uint64":
+			return fieldType{name: "uint64"}.with(int64Note), nil
+		}
+		return fieldType{name: "int64"}.with(int64Note), nil
+	case "number":
+		if format == "float" {
+			return fieldType{name: "float"}, nil
+		}
+		return fieldType{name: "double"}, nil
+	case "boolean":
+		return fieldType{name: "bool"}, nil
+	case "null":
+		return c.wellKnown(valueType).with("null is mapped to google.protobuf.Value"), nil
+	case "array":
+		return c.arrayType(schema, scope, hint, pointer)
+	case "object":
+		return c.mapType(schema, scope, hint, pointer)
+	}
+	return c.wellKnown(valueType).with("unknown type " + types[0]), nil
+}
+
+// arrayType returns the repeated type of an array schema. Arrays of arrays or maps, and
+// tuples, are ListValues, since repeated fields cannot nest.
+func (c *converter) arrayType(schema map[string]any, scope *message, hint string, pointer string) (fieldType, error) {
+	if _, ok := schema["prefixItems"]; ok {
+		return c.wellKnown(listValueType).with("tuple items are not typed"), nil
+	}
+	items, ok := schema["items"].(map[string]any)
+	if !ok {
+		if _, tuple := schema["items"].([]any); tuple {
+			return c.wellKnown(listValueType).with("tuple items are not typed"), nil
+		}
+		t := c.wellKnown(valueType)
+		t.repeated = true
+		return t, nil
+	}
+	item, err := c.typeOf(items, scope, hint+"Item", pointer+"/items")
+	if err != nil {
+		return fieldType{}, err
+	}
+	if item.repeated || item.isMap {
+		return c.wellKnown(listValueType).with("repeated fields cannot hold " + item.String()), nil
+	}
+	item.repeated = true
+	return item, nil
+}
+
+// mapType returns the type of an object schema without properties: a map of the
+// additionalProperties schema, or a Struct for free-form objects
+func (c *converter) mapType(schema map[string]any, scope *message, hint string, pointer string) (fieldType, error) {
+	values, ok := schema["additionalProperties"].(map[string]any)
+	if !ok || len(values) == 0 {
+		return c.wellKnown(structType), nil
+	}
+	value, err := c.typeOf(values, scope, hint+"Value", pointer+"/additionalProperties")
+	if err != nil {
+		return fieldType{}, err
+	}
+	if value.repeated || value.isMap {
+		t := c.wellKnown(valueType).with("map values cannot be " + value.String())
+		t.isMap, t.message = true, false
+		return t, nil
+	}
+	value.isMap, value.message = true, false
+	return value, nil
+}
+
+// refType returns the type of the target of a $ref: the message or enum of a definition, or
+// the type of the schema a definition is an alias of
+func (c *converter) refType(ref string, scope *message, hint string) (fieldType, error) {
+	schema, name := c.resolve(ref)
+	if schema == nil {
+		return c.wellKnown(valueType).with("unresolved $ref " + ref), nil
+	}
+	if name == "" {
+		return c.typeOf(schema, scope, hint, ref)
+	}
+	if protoName, ok := c.names[name]; ok {
+		kind := definitionKind(schema)
+		return fieldType{name: protoName, message: kind == "message", enum: kind == "enum"}, nil
+	}
+	return c.aliasType(name)
+}
+
+// definitionTypeName returns the name of the message or enum of a definition: its x-go-name,
+// which names its type in every target, or the Go identifier of its name
+func definitionTypeName(name string, schema map[string]any) string {
+	if goName, ok := schema["x-go-name"].(string); ok && goName != "" {
+		return goName
+	}
+	return jrpc.GoIdentifier(name, nil)
+}
+
+// aliasType returns the type of a definition inlined where it is used, computed once so
+// that the messages it declares are declared once
+func (c *converter) aliasType(name string) (fieldType, error) {
+	if t, ok := c.aliases[name]; ok {
+		return *t, nil
+	}
+	if c.resolving[name] {
+		return c.wellKnown(valueType).with("recursive definition " + name), nil
+	}
+	c.resolving[name] = true
+	defer delete(c.resolving, name)
+
+	schema, _ := c.definitions[name].(map[string]any)
+	t, err := c.typeOf(schema, nil, definitionTypeName(name, schema), c.pointer(name))
+	if err != nil {
+		return fieldType{}, err
+	}
+	c.aliases[name] = &t
+	return t, nil
+}
+
+// resolve returns the schema a $ref points at and, for a definition, its name
+func (c *converter) resolve(ref string) (map[string]any, string) {
+	if c.prefix == "#" && ref == "#" {
+		for name, definition := range c.definitions {
+			schema, _ := definition.(map[string]any)
+			return schema, name
+		}
+	}
+	if rest, ok := strings.CutPrefix(ref, c.prefix); ok && c.prefix != "#" && !strings.Contains(rest, "/") {
+		name := unescapePointer(rest)
+		schema, _ := c.definitions[name].(map[string]any)
+		return schema, name
+	}
+
+	var current any = c.document
+	fragment, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, ""
+	}
+	for _, token := range strings.Split(fragment, "/") {
+		token = unescapePointer(token)
+		switch node := current.(type) {
+		case map[string]any:
+			current = node[token]
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, ""
+			}
+			current = node[index]
+		default:
+			return nil, ""
+		}
+	}
+	schema, _ := current.(map[string]any)
+	return schema, ""
+}
+
+// definitionName returns the definition a schema refers to, if any
+func (c *converter) definitionName(schema map[string]any) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		_, name := c.resolve(ref)
+		return name
+	}
+	return ""
+}
+
+// nestedName returns a name for a message or enum declared in scope that differs from the
+// other declarations of scope and from the top-level names, which it would otherwise shadow
+func (c *converter) nestedName(scope *message, hint string) string {
+	if scope == nil {
+		return c.declare(hint)
+	}
+	taken := map[string]bool{}
+	for _, nested := range scope.nested {
+		switch nested := nested.(type) {
+		case *message:
+			taken[nested.name] = true
+		case *enum:
+			taken[nested.name] = true
+		}
+	}
+	name := hint
+	for i := 2; taken[name] || c.declared[name] || name == scope.name; i++ {
+		name = hint + strconv.Itoa(i)
+	}
+	return name
+}
+
+// wellKnown returns a well-known message type, whose file is imported once a field uses it
+func (c *converter) wellKnown(name string) fieldType {
+	return fieldType{name: name, message: true}
+}
+
+// enum declares the enum of a string enum schema. Its zero value is <ENUM>_UNSPECIFIED and
+// its values are prefixed with the enum name, since enum values share the scope of the enum.
+func (c *converter) enum(name string, schema map[string]any, pointer string) (*enum, *mapping) {
+	e := &enum{name: name}
+	e.comment, _ = schema["description"].(string)
+	prefix := strings.ToUpper(fieldName(name))
+	taken := map[string]bool{}
+	e.values = append(e.values, enumValue{name: uniqueName(prefix+"_UNSPECIFIED", taken)})
+
+	m := &mapping{
+		Schema: pointer,
+		Kind:   "enum",
+		Proto:  name,
+		Notes:  []string{"the proto3 JSON mapping encodes the values by their name, not as the schema strings"},
+	}
+	values, _ := schema["enum"].([]any)
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		valueName := strings.ToUpper(fieldName(text))
+		if text == "" {
+			valueName = "EMPTY"
+		}
+		v := enumValue{value: text, name: uniqueName(prefix+"_"+valueName, taken), number: len(e.values)}
+		e.values = append(e.values, v)
+		m.Fields = append(m.Fields, fieldMapping{Property: text, Field: v.name, Number: v.number})
+	}
+	return e, m
+}
+
+// variant is a member of a oneOf or anyOf
+type variant struct {
+	schema  map[string]any
+	pointer string // relative to the union, e.g. /oneOf/0
+}
+
+// unionVariants returns the keyword and the members of the oneOf or anyOf of a schema,
+// without the null ones
+func unionVariants(schema map[string]any) (string, []variant) {
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, ok := schema[key].([]any)
+		if !ok {
+			continue
+		}
+		var variants []variant
+		for i, member := range members {
+			member, _ := member.(map[string]any)
+			if member == nil || member["type"] == "null" {
+				continue
+			}
+			variants = append(variants, variant{schema: member, pointer: "/" + key + "/" + strconv.Itoa(i)})
+		}
+		return key, variants
+	}
+	return "", nil
+}
+
+// isStringEnum reports whether a schema is an enum of strings (and null)
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	count := 0
+	for _, value := range values {
+		switch value.(type) {
+		case string:
+			count++
+		case nil:
+		default:
+			return false
+		}
+	}
+	return count > 0
+}
+
+// valueKind returns the JSON Schema type shared by values, or an empty string
+func valueKind(values []any) string {
+	kind := ""
+	for _, value := range values {
+		var k string
+		switch value := value.(type) {
+		case nil:
+			continue
+		case string:
+			k = "string"
+		case bool:
+			k = "boolean"
+		case float64:
+			k = "number"
+			if value == float64(int64(value)) {
+				k = "integer"
+			}
+		case int, int64:
+			k = "integer"
+		default:
+			return ""
+		}
+		if kind != "" && kind != k {
+			if kind+k == "integernumber" || kind+k == "numberinteger" {
+				k = "number"
+			} else {
+				return ""
+			}
+		}
+		kind = k
+	}
+	return kind
+}
+
+// schemaTypes returns the non-null types of a schema; an object with properties or
+// additionalProperties but no type is an object
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = append(types, value)
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) == 0 {
+		if schema["additionalProperties"] != nil {
+			return []string{"object"}
+		}
+		if schema["items"] != nil {
+			return []string{"array"}
+		}
+	}
+	var nonNull []string
+	for _, t := range types {
+		if t != "null" {
+			nonNull = append(nonNull, t)
+		}
+	}
+	if len(nonNull) == 0 && len(types) > 0 {
+		return types
+	}
+	return nonNull
+}
+
+// fieldName returns the lower_snake_case proto field name of a schema name, splitting
+// camelCase words and keeping acronyms together (userID: user_id). Latin letters with
+// diacritics are transliterated (ürl: url), as for the other targets; the letters of other
+// scripts, which proto identifiers cannot hold, are dropped.
+func fieldName(name string) string {
+	runes := []rune(jrpc.Transliterate(name))
+	var b strings.Builder
+	for i, r := range runes {
+		if r >= utf8.RuneSelf || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteByte('_')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	words := strings.FieldsFunc(b.String(), func(r rune) bool { return r == '_' })
+	snake := strings.Join(words, "_")
+	switch {
+	case snake == "":
+		return "field"
+	case unicode.IsDigit(rune(snake[0])):
+		return "field_" + snake
+	}
+	return snake
+}
+
+// jsonName returns the JSON name protoc derives from a field name: lowerCamelCase
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// uniqueName returns name, or name followed by a number when taken, and takes it
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// integer returns the value of an integer decoded from JSON or YAML
+func integer(value any) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		return int(value), value == float64(int(value))
+	}
+	return 0, false
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// renderValues renders schema values for the notes of the report
+func renderValues(values []any) string {
+	rendered := make([]string, len(values))
+	for i, value := range values {
+		if text, ok := value.(string); ok {
+			rendered[i] = strconv.Quote(text)
+		} else {
+			rendered[i] = fmt.Sprint(value)
+		}
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// escapePointer escapes a JSON pointer token
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescapePointer unescapes a JSON pointer token
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
@@ -0,0 +1,147 @@
+package proto
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// render returns the proto file of the converted definitions
+func (c *converter) render(packageName string) string {
+	var b strings.Builder
+	b.WriteString("// Code generated from JSON schema. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n")
+	if packageName != "" {
+		fmt.Fprintf(&b, "\npackage %s;\n", packageName)
+	}
+
+	used := map[string]bool{}
+	for _, e := range c.elements {
+		if m, ok := e.(*message); ok {
+			m.collectImports(used)
+		}
+	}
+	imports := make([]string, 0, len(used))
+	for file := range used {
+		imports = append(imports, file)
+	}
+	slices.Sort(imports)
+	if len(imports) > 0 {
+		b.WriteString("\n")
+	}
+	for _, file := range imports {
+		fmt.Fprintf(&b, "import %q;\n", file)
+	}
+	if c.options.GoPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", c.options.GoPackage)
+	}
+
+	for _, e := range c.elements {
+		b.WriteString("\n")
+		e.write(&b, "", c.options.IncludeComments)
+	}
+	return b.String()
+}
+
+// collectImports records the files declaring the well-known types the fields of a message and
+// of its nested messages use
+func (m *message) collectImports(imports map[string]bool) {
+	for _, f := range m.fields {
+		if file, ok := wellKnownImports[f.typ.name]; ok {
+			imports[file] = true
+		}
+	}
+	for _, nested := range m.nested {
+		if nested, ok := nested.(*message); ok {
+			nested.collectImports(imports)
+		}
+	}
+}
+
+// write writes the declaration of a message, its nested declarations first
+func (m *message) write(b *strings.Builder, indent string, comments bool) {
+	if comments {
+		writeComment(b, indent, m.comment)
+	}
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.name)
+	inner := indent + "  "
+	if m.deprecated {
+		fmt.Fprintf(b, "%soption deprecated = true;\n", inner)
+	}
+	for i, nested := range m.nested {
+		if i > 0 || m.deprecated {
+			b.WriteString("\n")
+		}
+		nested.write(b, inner, comments)
+	}
+	if len(m.nested) > 0 && len(m.fields) > 0 {
+		b.WriteString("\n")
+	}
+
+	fieldIndent := inner
+	if m.oneof != "" {
+		fmt.Fprintf(b, "%soneof %s {\n", inner, m.oneof)
+		fieldIndent += "  "
+	}
+	for _, f := range m.fields {
+		if comments {
+			writeComment(b, fieldIndent, f.comment)
+		}
+		fmt.Fprintf(b, "%s%s%s %s = %d%s;\n", fieldIndent, f.label(), f.typ, f.name, f.number, f.options())
+	}
+	if m.oneof != "" {
+		fmt.Fprintf(b, "%s}\n", inner)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// label returns the label written before the type of a field
+func (f *field) label() string {
+	if f.optional {
+		return "optional "
+	}
+	return ""
+}
+
+// options returns the field options: the JSON name of a property whose name protoc would not
+// derive from the field name, and deprecation
+func (f *field) options() string {
+	var options []string
+	if f.property != "" && !strings.HasPrefix(f.property, "/") && jsonName(f.name) != f.property {
+		options = append(options, fmt.Sprintf("json_name = %q", f.property))
+	}
+	if f.deprecated {
+		options = append(options, "deprecated = true")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(options, ", ") + "]"
+}
+
+// write writes the declaration of an enum
+func (e *enum) write(b *strings.Builder, indent string, comments bool) {
+	if comments {
+		writeComment(b, indent, e.comment)
+	}
+	fmt.Fprintf(b, "%senum %s {\n", indent, e.name)
+	for _, value := range e.values {
+		fmt.Fprintf(b, "%s  %s = %d;\n", indent, value.name, value.number)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// writeComment writes a description as // comment lines
+func writeComment(b *strings.Builder, indent string, description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(description, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s// %s\n", indent, line)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// mapping records how a schema of the document maps to the proto file: the message or enum
+// declared for it, or the type it is inlined as
+type mapping struct {
+	Schema string         `json:"schema"`
+	Kind   string         `json:"kind"`
+	Proto  string         `json:"proto"`
+	Fields []fieldMapping `json:"fields,omitempty"`
+	Notes  []string       `json:"notes,omitempty"`
+}
+
+// fieldMapping records the field of a property or union variant, or the value of an enum
+type fieldMapping struct {
+	Property string   `json:"property"`
+	Field    string   `json:"field"`
+	Number   int      `json:"number"`
+	Type     string   `json:"type,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// reportJSON returns the mappings as a JSON array
+func reportJSON(mappings []*mapping) ([]byte, error) {
+	data, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mapping report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// reportMarkdown returns the mappings as a Markdown document, with a table of the fields of
+// every message and of the values of every enum
+func reportMarkdown(source string, mappings []*mapping) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Proto mapping of %s\n", source)
+	for _, m := range mappings {
+		switch m.Kind {
+		case "inlined":
+			fmt.Fprintf(&b, "\n## %s\n\n", m.Schema)
+			fmt.Fprintf(&b, "`%s` is inlined as `%s`.\n", m.Schema, m.Proto)
+		default:
+			fmt.Fprintf(&b, "\n## %s\n\n", m.Proto)
+			fmt.Fprintf(&b, "`%s` is declared as %s `%s`.\n", m.Schema, m.Kind, m.Proto)
+		}
+		if len(m.Notes) > 0 {
+			b.WriteString("\n")
+		}
+		for _, note := range m.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+		if len(m.Fields) == 0 {
+			continue
+		}
+
+		if m.Kind == "enum" {
+			b.WriteString("\n| Value | Name | Number |\n| --- | --- | --- |\n")
+			for _, f := range m.Fields {
+				fmt.Fprintf(&b, "| %s | `%s` | %d |\n", cell(strconv.Quote(f.Property)), f.Field, f.Number)
+			}
+			continue
+		}
+		b.WriteString("\n| Property | Field | Number | Type | Notes |\n| --- | --- | --- | --- | --- |\n")
+		for _, f := range m.Fields {
+			fmt.Fprintf(&b, "| `%s` | `%s` | %d | `%s` | %s |\n",
+				cell(f.Property), f.Field, f.Number, cell(f.Type), cell(strings.Join(f.Notes, "; ")))
+		}
+	}
+	return []byte(b.String())
+}
+
+// cell escapes the pipes of a table cell
+func cell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,88 @@
+// Package python provides a generator of Pydantic v2 models, declaring the same types as the Go
+// generators from the same schemas
+package python
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// PythonGenerator implements the Generator interface for Pydantic models
+type PythonGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *PythonGenerator) Name() string {
+	return "python"
+}
+
+// Description returns a human-readable description
+func (g *PythonGenerator) Description() string {
+	return "Generates Pydantic v2 models and enums from JSON Schema, OpenAPI and OpenRPC documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *PythonGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes
+func (g *PythonGenerator) OutputFormats() []string {
+	return []string{".py"}
+}
+
+// Options for the Python generator
+type Options struct {
+	// GeneratorOptions select and name the definitions as for the Go types: IncludeTypes,
+	// ExcludeTypes, CustomAcronyms, Naming, PreserveOrder, ReadWriteVariants, the remote
+	// refs options and IncludeComments. The options of the Go code do not apply.
+	*jrpc.GeneratorOptions
+}
+
+// Generate writes the Pydantic models of the definitions of the schema: models for objects,
+// enums for string enums, and root models for the others
+func (g *PythonGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{IncludeComments: true}
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported by the python generator", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	m := &module{definitions: definitions, options: options}
+	m.write()
+
+	if err := os.WriteFile(config.OutputPath, []byte(m.source()), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema declares definitions
+func (g *PythonGenerator) ValidateSchema(schemaPath string) error {
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// NewPythonGenerator creates a new instance of the Python generator
+func NewPythonGenerator() *PythonGenerator {
+	return &PythonGenerator{}
+}
+
+// Register automatically registers the Python generator with the default registry
+func init() {
+	generator := NewPythonGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register python generator: %v", err))
+	}
+}
@@ -0,0 +1,701 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// identifierPattern matches the names usable as Python identifiers as they are
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// keywords are the Python keywords and the builtins the models refer to, which cannot be
+// declared as classes or fields
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+	"bool": true, "float": true, "int": true, "str": true,
+}
+
+// importedNames are the names the module imports, which definitions must not shadow
+var importedNames = map[string]bool{
+	"annotations": true, "datetime": true, "Enum": true, "Any": true, "Dict": true,
+	"List": true, "Literal": true, "Optional": true, "Tuple": true, "Union": true,
+	"BaseModel": true, "ConfigDict": true, "Field": true, "RootModel": true,
+}
+
+// modelAttributes are the attributes of BaseModel that fields must not shadow
+var modelAttributes = map[string]bool{
+	"construct": true, "copy": true, "datetime": true, "dict": true, "from_orm": true,
+	"json": true, "model_config": true, "parse_file": true, "parse_obj": true, "parse_raw": true,
+	"schema": true, "schema_json": true, "update_forward_refs": true, "validate": true,
+}
+
+// dateTypes are the datetime types of the string formats, parsed as the Go types parse them
+// into time.Time
+var dateTypes = map[string]string{
+	"date-time": "datetime.datetime",
+	"date":      "datetime.date",
+	"time":      "datetime.time",
+}
+
+// module writes the Pydantic models of the definitions of a schema
+type module struct {
+	definitions map[string]any
+	options     *Options
+
+	classNames map[string]string // class name of each definition
+	classes    map[string]bool   // the class names, which fields must not shadow
+	declared   map[string]bool   // definitions whose class is written
+	visiting   map[string]bool   // definitions whose bases are being written
+	forward    bool              // whether the class being written refers to a later class
+	rebuilds   []string          // classes to rebuild once all are declared
+
+	typing   map[string]bool // names imported from typing
+	pydantic map[string]bool // names imported from pydantic
+	enum     bool
+	datetime bool
+	out      strings.Builder
+}
+
+// write writes a class per definition, in alphabetical order except for the base classes of a
+// model, which come before it
+func (m *module) write() {
+	names := make([]string, 0, len(m.definitions))
+	for name, definition := range m.definitions {
+		if _, ok := definition.(map[string]any); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	m.classNames = map[string]string{}
+	m.declared = map[string]bool{}
+	m.visiting = map[string]bool{}
+	m.typing = map[string]bool{}
+	m.pydantic = map[string]bool{}
+	m.classes = map[string]bool{}
+	for _, name := range names {
+		m.classNames[name] = className(name, m.options)
+		m.classes[m.classNames[name]] = true
+	}
+	for _, name := range names {
+		m.declare(name)
+	}
+}
+
+// declare writes the class of a definition, after its base classes
+func (m *module) declare(name string) {
+	if m.declared[name] || m.visiting[name] {
+		return
+	}
+	schema := m.definitions[name].(map[string]any)
+	m.visiting[name] = true
+	for _, base := range m.bases(schema) {
+		if _, ok := m.definitions[base].(map[string]any); ok {
+			m.declare(base)
+		}
+	}
+	delete(m.visiting, name)
+
+	m.forward = false
+	m.out.WriteString("\n\n")
+	class := m.classNames[name]
+	switch {
+	case isStringEnum(schema):
+		m.writeEnum(class, schema)
+	case m.isModel(schema):
+		m.writeModel(name, class, schema)
+	default:
+		m.pydantic["RootModel"] = true
+		fmt.Fprintf(&m.out, "class %s(RootModel):\n", class)
+		if m.docstring(schema) {
+			m.out.WriteString("\n")
+		}
+		fmt.Fprintf(&m.out, "    root: %s\n", m.typeOf(schema, name))
+	}
+	m.declared[name] = true
+	if m.forward {
+		m.rebuilds = append(m.rebuilds, class)
+	}
+}
+
+// source returns the module: its imports, the classes, and the rebuilds of the models
+// referring to classes declared after them
+func (m *module) source() string {
+	var b strings.Builder
+	b.WriteString("# Code generated from JSON schema. DO NOT EDIT.\n\n")
+	b.WriteString("from __future__ import annotations\n\n")
+	if m.datetime {
+		b.WriteString("import datetime\n")
+	}
+	if m.enum {
+		b.WriteString("from enum import Enum\n")
+	}
+	if len(m.typing) > 0 {
+		fmt.Fprintf(&b, "from typing import %s\n", strings.Join(sortedKeys(m.typing), ", "))
+	}
+	if len(m.pydantic) > 0 {
+		if m.datetime || m.enum || len(m.typing) > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "from pydantic import %s\n", strings.Join(sortedKeys(m.pydantic), ", "))
+	}
+	b.WriteString(m.out.String())
+	if len(m.rebuilds) > 0 {
+		b.WriteString("\n\n")
+		for _, class := range m.rebuilds {
+			fmt.Fprintf(&b, "%s.model_rebuild()\n", class)
+		}
+	}
+	return b.String()
+}
+
+// className returns the Python class name of a definition: its name, as for its Go type, or the
+// Go identifier of names that are not identifiers. Keywords and imported names get a trailing
+// underscore, as Go keywords do.
+func className(name string, options *Options) string {
+	if !identifierPattern.MatchString(name) {
+		name = jrpc.GoIdentifier(name, options.GeneratorOptions)
+	}
+	if keywords[name] || importedNames[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// bases returns the definitions a model extends: the $refs of its allOf
+func (m *module) bases(schema map[string]any) []string {
+	var bases []string
+	allOf, _ := schema["allOf"].([]any)
+	for _, member := range allOf {
+		member, _ := member.(map[string]any)
+		if ref, ok := member["$ref"].(string); ok {
+			bases = append(bases, refName(ref))
+		}
+	}
+	return bases
+}
+
+// isModel reports whether a definition is declared as a BaseModel: an object with properties,
+// or an allOf of such objects and of models
+func (m *module) isModel(schema map[string]any) bool {
+	if isNullable(schema) || schema["oneOf"] != nil || schema["anyOf"] != nil {
+		return false
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				target, _ := m.definitions[refName(ref)].(map[string]any)
+				if target == nil || !m.isModel(target) {
+					return false
+				}
+			} else if member == nil || member["properties"] == nil || len(schemaTypes(member)) > 1 {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok := schema["properties"]
+	return ok && len(schemaTypes(schema)) <= 1
+}
+
+// writeModel declares a model extending the models of its allOf, with a field per property of
+// the schema and of its inline allOf members
+func (m *module) writeModel(name, class string, schema map[string]any) {
+	var bases []string
+	for _, base := range m.bases(schema) {
+		bases = append(bases, m.classNames[base])
+	}
+	if len(bases) == 0 {
+		m.pydantic["BaseModel"] = true
+		bases = []string{"BaseModel"}
+	}
+	fmt.Fprintf(&m.out, "class %s(%s):\n", class, strings.Join(bases, ", "))
+	hasDocstring := m.docstring(schema)
+
+	members := []map[string]any{schema}
+	allOf, _ := schema["allOf"].([]any)
+	for _, member := range allOf {
+		if member, ok := member.(map[string]any); ok && member["$ref"] == nil {
+			members = append(members, member)
+		}
+	}
+	required := map[string]bool{}
+	for _, member := range members {
+		for _, property := range stringList(member["required"]) {
+			required[property] = true
+		}
+	}
+
+	var fields strings.Builder
+	var aliased, protected bool
+	taken := map[string]bool{}
+	for _, member := range members {
+		properties, _ := member["properties"].(map[string]any)
+		for _, property := range jrpc.PropertyNames(member) {
+			propertySchema, _ := properties[property].(map[string]any)
+			field := m.fieldName(property, taken)
+			aliased = aliased || field != property
+			protected = protected || strings.HasPrefix(field, "model_")
+			fields.WriteString("    " + m.field(field, property, propertySchema, required[property], name) + "\n")
+		}
+	}
+
+	var config []string
+	if aliased {
+		config = append(config, "populate_by_name=True")
+	}
+	if protected {
+		config = append(config, "protected_namespaces=()")
+	}
+	if additional := schema["additionalProperties"]; additional != nil && additional != false {
+		config = append(config, `extra="allow"`)
+	}
+	if len(config) > 0 {
+		m.pydantic["ConfigDict"] = true
+		if hasDocstring {
+			m.out.WriteString("\n")
+		}
+		fmt.Fprintf(&m.out, "    model_config = ConfigDict(%s)\n", strings.Join(config, ", "))
+		if fields.Len() > 0 {
+			m.out.WriteString("\n")
+		}
+	} else if hasDocstring && fields.Len() > 0 {
+		m.out.WriteString("\n")
+	}
+	m.out.WriteString(fields.String())
+	if !hasDocstring && len(config) == 0 && fields.Len() == 0 {
+		m.out.WriteString("    pass\n")
+	}
+}
+
+// field returns the declaration of the field of a property. Optional properties default to
+// None, or to their default value; properties whose field name differs get an alias.
+func (m *module) field(field, property string, schema map[string]any, required bool, definition string) string {
+	t := m.typeOf(schema, definition)
+	var arguments []string
+	if !required {
+		value, ok := defaultValue(schema)
+		if !ok {
+			value = "None"
+			if !strings.HasPrefix(t, "Optional[") && t != "Any" && t != "None" {
+				m.typing["Optional"] = true
+				t = "Optional[" + t + "]"
+			}
+		}
+		arguments = append(arguments, "default="+value)
+	}
+	if field != property {
+		arguments = append(arguments, "alias="+pyString(property))
+	}
+	if m.options.IncludeComments {
+		if description, ok := schema["description"].(string); ok && strings.TrimSpace(description) != "" {
+			arguments = append(arguments, "description="+pyString(strings.TrimSpace(description)))
+		}
+	}
+	if schema["deprecated"] == true {
+		arguments = append(arguments, "deprecated=True")
+	}
+
+	switch {
+	case len(arguments) == 0:
+		return fmt.Sprintf("%s: %s", field, t)
+	case len(arguments) == 1 && !required:
+		return fmt.Sprintf("%s: %s = %s", field, t, strings.TrimPrefix(arguments[0], "default="))
+	}
+	m.pydantic["Field"] = true
+	return fmt.Sprintf("%s: %s = Field(%s)", field, t, strings.Join(arguments, ", "))
+}
+
+// fieldName returns the snake_case field name of a property, unique among taken. Keywords,
+// model attributes and class names get a trailing underscore.
+func (m *module) fieldName(property string, taken map[string]bool) string {
+	name := snakeCase(jrpc.GoIdentifier(property, m.options.GeneratorOptions))
+	if name == "" {
+		name = "field"
+	}
+	if keywords[name] || modelAttributes[name] || m.classes[name] {
+		name += "_"
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// writeEnum declares a string enum as a str Enum, with a member per value named like its Go
+// constant in UPPER_SNAKE_CASE
+func (m *module) writeEnum(class string, schema map[string]any) {
+	m.enum = true
+	fmt.Fprintf(&m.out, "class %s(str, Enum):\n", class)
+	if m.docstring(schema) {
+		m.out.WriteString("\n")
+	}
+	taken := map[string]bool{}
+	for _, value := range schema["enum"].([]any) {
+		text := value.(string)
+		member := strings.ToUpper(snakeCase(jrpc.GoIdentifier(text, m.options.GeneratorOptions)))
+		if member == "" {
+			member = "VALUE"
+		}
+		unique := member
+		for i := 2; taken[unique]; i++ {
+			unique = member + "_" + strconv.Itoa(i)
+		}
+		taken[unique] = true
+		fmt.Fprintf(&m.out, "    %s = %s\n", unique, pyString(text))
+	}
+}
+
+// typeOf returns the Python type of a schema, referenced from the class of a definition
+func (m *module) typeOf(schema map[string]any, definition string) string {
+	t := m.nonNullType(schema, definition)
+	if isNullable(schema) && t != "Any" && t != "None" {
+		m.typing["Optional"] = true
+		return "Optional[" + t + "]"
+	}
+	return t
+}
+
+// nonNullType returns the Python type of a schema, without its None
+func (m *module) nonNullType(schema map[string]any, definition string) string {
+	if schema == nil {
+		return m.any()
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		class, ok := m.classNames[name]
+		if !ok {
+			return m.any()
+		}
+		if !m.declared[name] || name == definition {
+			m.forward = true
+		}
+		return class
+	}
+	if value, ok := schema["const"]; ok {
+		if literal, ok := pyLiteral(value); ok {
+			m.typing["Literal"] = true
+			return "Literal[" + literal + "]"
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		var literals []string
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			literal, ok := pyLiteral(value)
+			if !ok {
+				literals = nil
+				break
+			}
+			literals = append(literals, literal)
+		}
+		if len(literals) > 0 {
+			m.typing["Literal"] = true
+			return "Literal[" + strings.Join(literals, ", ") + "]"
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok {
+			var types []string
+			for _, member := range members {
+				member, _ := member.(map[string]any)
+				if member["type"] == "null" {
+					continue
+				}
+				if t := m.typeOf(member, definition); !slices.Contains(types, t) {
+					types = append(types, t)
+				}
+			}
+			return m.union(types)
+		}
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		if member, _ := allOf[0].(map[string]any); len(allOf) == 1 {
+			return m.typeOf(member, definition)
+		}
+		return m.any()
+	}
+
+	var types []string
+	for _, kind := range schemaTypes(schema) {
+		types = append(types, m.typeOfKind(kind, schema, definition))
+	}
+	return m.union(types)
+}
+
+// typeOfKind returns the Python type of a schema of a JSON Schema type
+func (m *module) typeOfKind(kind string, schema map[string]any, definition string) string {
+	switch kind {
+	case "string":
+		format, _ := schema["format"].(string)
+		if t, ok := dateTypes[format]; ok {
+			m.datetime = true
+			return t
+		}
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "null":
+		return "None"
+	case "array":
+		if items, ok := tupleItems(schema); ok {
+			var types []string
+			for _, item := range items {
+				item, _ := item.(map[string]any)
+				types = append(types, m.typeOf(item, definition))
+			}
+			m.typing["Tuple"] = true
+			return "Tuple[" + strings.Join(types, ", ") + "]"
+		}
+		items, _ := schema["items"].(map[string]any)
+		m.typing["List"] = true
+		return "List[" + m.typeOf(items, definition) + "]"
+	case "object":
+		m.typing["Dict"] = true
+		if values, ok := schema["additionalProperties"].(map[string]any); ok && len(values) > 0 {
+			return "Dict[str, " + m.typeOf(values, definition) + "]"
+		}
+		return "Dict[str, " + m.any() + "]"
+	}
+	return m.any()
+}
+
+// union returns the union of types, Any when there are none
+func (m *module) union(types []string) string {
+	switch len(types) {
+	case 0:
+		return m.any()
+	case 1:
+		return types[0]
+	}
+	m.typing["Union"] = true
+	return "Union[" + strings.Join(types, ", ") + "]"
+}
+
+// any returns the Any type
+func (m *module) any() string {
+	m.typing["Any"] = true
+	return "Any"
+}
+
+// docstring writes the description of a schema, and its deprecation, as the docstring of a
+// class, and reports whether it wrote one
+func (m *module) docstring(schema map[string]any) bool {
+	if !m.options.IncludeComments {
+		return false
+	}
+	var lines []string
+	if description, ok := schema["description"].(string); ok && strings.TrimSpace(description) != "" {
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			line = strings.ReplaceAll(strings.TrimRight(line, " \t\r"), `\`, `\\`)
+			lines = append(lines, strings.ReplaceAll(line, `"""`, `\"\"\"`))
+		}
+	}
+	if schema["deprecated"] == true {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Deprecated.")
+	}
+	switch len(lines) {
+	case 0:
+		return false
+	case 1:
+		if strings.HasSuffix(lines[0], `"`) {
+			lines[0] += " "
+		}
+		fmt.Fprintf(&m.out, "    \"\"\"%s\"\"\"\n", lines[0])
+		return true
+	}
+	m.out.WriteString("    \"\"\"")
+	for i, line := range lines {
+		if i > 0 && line != "" {
+			m.out.WriteString("    ")
+		}
+		m.out.WriteString(line + "\n")
+	}
+	m.out.WriteString("    \"\"\"\n")
+	return true
+}
+
+// defaultValue returns the default of a property of a scalar type as a Python literal
+func defaultValue(schema map[string]any) (string, bool) {
+	value, ok := schema["default"]
+	if !ok || value == nil || schema["$ref"] != nil {
+		return "", false
+	}
+	switch value.(type) {
+	case string, bool, float64:
+		return pyLiteral(value)
+	}
+	return "", false
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping acronyms whole (UserID is user_id)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '_' {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && !strings.HasSuffix(b.String(), "_") {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// pyLiteral returns a JSON scalar as a Python literal, usable in Literal[...] unless it is a
+// non-integer number
+func pyLiteral(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return pyString(value), true
+	case bool:
+		if value {
+			return "True", true
+		}
+		return "False", true
+	case float64:
+		if value != float64(int64(value)) {
+			return "", false
+		}
+		return strconv.FormatInt(int64(value), 10), true
+	case int:
+		return strconv.Itoa(value), true
+	}
+	return "", false
+}
+
+// pyString returns a string as a double-quoted Python string literal
+func pyString(value string) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return `""`
+	}
+	return string(data)
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// isStringEnum reports whether a schema is an enum of strings
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tupleItems returns the positional items of a tuple schema: 2020-12 prefixItems or an
+// items array
+func tupleItems(schema map[string]any) ([]any, bool) {
+	if items, ok := schema["prefixItems"].([]any); ok {
+		return items, true
+	}
+	items, ok := schema["items"].([]any)
+	return items, ok
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// sortedKeys returns the keys of a set in alphabetical order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+// Package sql provides an experimental generator of SQL DDL: a CREATE TABLE statement per
+// object definition of the same schemas as the Go types, for persisting the generated models
+package sql
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Dialects of the generated SQL
+const (
+	// DialectPostgres writes PostgreSQL DDL
+	DialectPostgres = "postgres"
+)
+
+// SQLGenerator implements the Generator interface for SQL DDL
+type SQLGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *SQLGenerator) Name() string {
+	return "sql"
+}
+
+// Description returns a human-readable description
+func (g *SQLGenerator) Description() string {
+	return "Generates CREATE TABLE statements (PostgreSQL) from JSON Schema, OpenAPI and OpenRPC documents (experimental)"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *SQLGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes
+func (g *SQLGenerator) OutputFormats() []string {
+	return []string{".sql"}
+}
+
+// Options for the SQL generator
+type Options struct {
+	// GeneratorOptions select and name the definitions as for the Go types: IncludeTypes,
+	// ExcludeTypes, CustomAcronyms, Naming, PreserveOrder, the remote refs options and
+	// IncludeComments. The options of the Go code do not apply.
+	*jrpc.GeneratorOptions
+
+	// Dialect is the SQL dialect written: DialectPostgres (default)
+	Dialect string
+
+	// TypeMappings override the column types of the JSON Schema formats (e.g. "uuid") and
+	// types (e.g. "string"), formats taking precedence: {"date-time": "TIMESTAMP"}
+	TypeMappings map[string]string
+}
+
+// Generate writes a CREATE TABLE statement per object definition of the schema, followed by
+// their foreign keys and indexes
+func (g *SQLGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{IncludeComments: true}
+	}
+	switch options.Dialect {
+	case "":
+		options.Dialect = DialectPostgres
+	case DialectPostgres:
+	default:
+		return fmt.Errorf("unsupported SQL dialect %q: must be %s", options.Dialect, DialectPostgres)
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported by the sql generator", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	s, err := newSchema(definitions, options)
+	if err != nil {
+		return err
+	}
+	if len(s.tables) == 0 {
+		return fmt.Errorf("the schema declares no objects to write as tables")
+	}
+
+	if err := os.WriteFile(config.OutputPath, []byte(s.render()), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema declares definitions
+func (g *SQLGenerator) ValidateSchema(schemaPath string) error {
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// NewSQLGenerator creates a new instance of the SQL generator
+func NewSQLGenerator() *SQLGenerator {
+	return &SQLGenerator{}
+}
+
+// Register automatically registers the SQL generator with the default registry
+func init() {
+	generator := NewSQLGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register SQL generator: %v", err))
+	}
+}
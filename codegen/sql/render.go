@@ -0,0 +1,126 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// plainIdentifier matches the identifiers written without quotes
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedWords are the PostgreSQL keywords that cannot be table or column names unquoted
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true,
+	"as": true, "asc": true, "asymmetric": true, "authorization": true, "binary": true,
+	"both": true, "case": true, "cast": true, "check": true, "collate": true, "collation": true,
+	"column": true, "concurrently": true, "constraint": true, "create": true, "cross": true,
+	"current_catalog": true, "current_date": true, "current_role": true, "current_schema": true,
+	"current_time": true, "current_timestamp": true, "current_user": true, "default": true,
+	"deferrable": true, "desc": true, "distinct": true, "do": true, "else": true, "end": true,
+	"except": true, "false": true, "fetch": true, "for": true, "foreign": true, "freeze": true,
+	"from": true, "full": true, "grant": true, "group": true, "having": true, "ilike": true,
+	"in": true, "initially": true, "inner": true, "intersect": true, "into": true, "is": true,
+	"isnull": true, "join": true, "lateral": true, "leading": true, "left": true, "like": true,
+	"limit": true, "localtime": true, "localtimestamp": true, "natural": true, "not": true,
+	"notnull": true, "null": true, "offset": true, "on": true, "only": true, "or": true,
+	"order": true, "outer": true, "overlaps": true, "placing": true, "primary": true,
+	"references": true, "returning": true, "right": true, "select": true, "session_user": true,
+	"similar": true, "some": true, "symmetric": true, "system_user": true, "table": true,
+	"tablesample": true, "then": true, "to": true, "trailing": true, "true": true, "union": true,
+	"unique": true, "user": true, "using": true, "variadic": true, "verbose": true, "when": true,
+	"where": true, "window": true, "with": true,
+}
+
+// render returns the DDL of the tables: their CREATE TABLE statements and comments in
+// alphabetical order, then their foreign keys, added once every table exists, and indexes
+func (s *schema) render() string {
+	var b strings.Builder
+	b.WriteString("-- Code generated from JSON schema. DO NOT EDIT.\n")
+	for _, t := range s.tables {
+		b.WriteString("\n")
+		t.write(&b)
+	}
+
+	var constraints, indexes []string
+	for _, t := range s.tables {
+		for _, fk := range t.foreignKeys {
+			constraints = append(constraints, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);\n",
+				quote(t.name), quote(t.name+"_"+fk.column+"_fkey"), quote(fk.column), quote(fk.table), quote(fk.reference)))
+		}
+		for _, idx := range t.indexes {
+			unique := ""
+			if idx.unique {
+				unique = "UNIQUE "
+			}
+			indexes = append(indexes, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);\n",
+				unique, quote(idx.name), quote(t.name), quoteAll(idx.columns)))
+		}
+	}
+	for _, statements := range [][]string{constraints, indexes} {
+		if len(statements) > 0 {
+			b.WriteString("\n")
+		}
+		for _, statement := range statements {
+			b.WriteString(statement)
+		}
+	}
+	return b.String()
+}
+
+// write writes the CREATE TABLE statement of a table, and the comments of the table and its
+// columns
+func (t *table) write(b *strings.Builder) {
+	lines := make([]string, 0, len(t.columns)+1)
+	for _, c := range t.columns {
+		line := quote(c.name) + " " + c.typ
+		if c.notNull {
+			line += " NOT NULL"
+		}
+		if c.defaultValue != "" {
+			line += " DEFAULT " + c.defaultValue
+		}
+		if c.unique {
+			line += " UNIQUE"
+		}
+		if len(c.check) > 0 {
+			line += fmt.Sprintf(" CHECK (%s IN (%s))", quote(c.name), strings.Join(c.check, ", "))
+		}
+		lines = append(lines, line)
+	}
+	if len(t.primaryKey) > 0 {
+		lines = append(lines, "PRIMARY KEY ("+quoteAll(t.primaryKey)+")")
+	}
+	fmt.Fprintf(b, "CREATE TABLE %s (\n  %s\n);\n", quote(t.name), strings.Join(lines, ",\n  "))
+
+	if t.description != "" {
+		fmt.Fprintf(b, "COMMENT ON TABLE %s IS %s;\n", quote(t.name), text(t.description))
+	}
+	for _, c := range t.columns {
+		if c.description != "" {
+			fmt.Fprintf(b, "COMMENT ON COLUMN %s.%s IS %s;\n", quote(t.name), quote(c.name), text(c.description))
+		}
+	}
+}
+
+// quote returns an identifier, quoted when it is not lowercase or is a reserved word
+func quote(name string) string {
+	if plainIdentifier.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteAll returns identifiers separated by commas
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// text returns a string literal
+func text(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
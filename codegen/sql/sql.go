@@ -0,0 +1,604 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// referencePattern matches the x-sql-references of a column: a definition or table, with the
+// referenced column in parentheses
+var referencePattern = regexp.MustCompile(`^\s*([^()\s]+)\s*(?:\(\s*([^()\s]+)\s*\))?\s*$`)
+
+// formatTypes are the PostgreSQL column types of the JSON Schema formats
+var formatTypes = map[string]string{
+	"date-time": "TIMESTAMPTZ",
+	"date":      "DATE",
+	"time":      "TIME",
+	"uuid":      "UUID",
+	"byte":      "BYTEA",
+	"binary":    "BYTEA",
+	"decimal":   "NUMERIC",
+	"ipv4":      "INET",
+	"ipv6":      "INET",
+	"int8":      "SMALLINT",
+	"int16":     "SMALLINT",
+	"uint8":     "SMALLINT",
+	"int32":     "INTEGER",
+	"uint16":    "INTEGER",
+	"int64":     "BIGINT",
+	"float":     "REAL",
+	"double":    "DOUBLE PRECISION",
+}
+
+// kindTypes are the PostgreSQL column types of the JSON Schema types
+var kindTypes = map[string]string{
+	"string":  "TEXT",
+	"integer": "BIGINT",
+	"number":  "DOUBLE PRECISION",
+	"boolean": "BOOLEAN",
+	"object":  "JSONB",
+	"array":   "JSONB",
+}
+
+// schema holds the tables of the object definitions
+type schema struct {
+	definitions map[string]any
+	options     *Options
+	tables      []*table
+	byName      map[string]*table // tables by definition
+	aliasing    map[string]bool   // alias definitions being resolved, against cycles
+}
+
+// table is the table of an object definition
+type table struct {
+	name        string
+	definition  string
+	description string
+	columns     []*column
+	primaryKey  []string
+	foreignKeys []foreignKey
+	indexes     []index
+}
+
+// column is a column of a table, for a property of its definition
+type column struct {
+	name         string
+	property     string
+	typ          string
+	description  string
+	notNull      bool
+	unique       bool
+	defaultValue string
+	check        []string // values the column is restricted to, as SQL literals
+}
+
+// foreignKey is a column referring to a column of another table
+type foreignKey struct {
+	column           string
+	table, reference string
+}
+
+// index is an index of a table
+type index struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+// newSchema declares a table per object definition not marked x-sql-skip, named after the
+// snake_case of its Go type unless x-sql-table names it
+func newSchema(definitions map[string]any, options *Options) (*schema, error) {
+	s := &schema{
+		definitions: definitions,
+		options:     options,
+		byName:      map[string]*table{},
+		aliasing:    map[string]bool{},
+	}
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	taken := map[string]string{}
+	for _, name := range names {
+		defMap, _ := definitions[name].(map[string]any)
+		if !isTable(defMap) {
+			continue
+		}
+		t := &table{definition: name, description: s.description(defMap)}
+		if pinned, ok := defMap["x-sql-table"].(string); ok && pinned != "" {
+			t.name = pinned
+		} else {
+			t.name = snakeCase(jrpc.GoIdentifier(name, options.GeneratorOptions))
+		}
+		if other, ok := taken[t.name]; ok {
+			return nil, fmt.Errorf("definitions %s and %s both have table %s: name one with x-sql-table", other, name, t.name)
+		}
+		taken[t.name] = name
+		s.tables = append(s.tables, t)
+		s.byName[name] = t
+	}
+	sort.Slice(s.tables, func(i, j int) bool { return s.tables[i].name < s.tables[j].name })
+
+	for _, t := range s.tables {
+		if err := s.declareColumns(t); err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.name, err)
+		}
+	}
+	return s, nil
+}
+
+// declareColumns declares the columns, keys and indexes of a table
+func (s *schema) declareColumns(t *table) error {
+	defMap, _ := s.definitions[t.definition].(map[string]any)
+	properties, required := s.properties(defMap)
+	byProperty := map[string]*column{}
+	taken := map[string]bool{}
+	var keyed []string
+	for _, p := range properties {
+		c := &column{property: p.name, description: s.description(p.schema)}
+		if pinned, ok := p.schema["x-sql-column"].(string); ok && pinned != "" {
+			c.name = pinned
+		} else {
+			c.name = snakeCase(jrpc.GoIdentifier(p.name, s.options.GeneratorOptions))
+		}
+		if taken[c.name] {
+			return fmt.Errorf("properties %s and %s both have column %s: name one with x-sql-column", columnOf(t.columns, nil, c.name).property, p.name, c.name)
+		}
+		taken[c.name] = true
+
+		if pinned, ok := p.schema["x-sql-type"].(string); ok && pinned != "" {
+			c.typ = pinned
+		} else {
+			c.typ, c.check = s.columnType(p.schema)
+		}
+		c.notNull = required[p.name] && !isNullable(p.schema)
+		c.unique = p.schema["x-sql-unique"] == true
+		if value, ok := p.schema["default"]; ok && value != nil && !strings.HasSuffix(c.typ, "[]") && c.typ != "JSONB" {
+			c.defaultValue, _ = literal(value)
+		}
+		if p.schema["x-sql-primary-key"] == true {
+			keyed = append(keyed, c.name)
+		}
+		if p.schema["x-sql-index"] == true {
+			t.indexes = append(t.indexes, index{columns: []string{c.name}})
+		}
+		if reference, ok := p.schema["x-sql-references"].(string); ok {
+			fk, err := s.foreignKey(c.name, reference)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", c.name, err)
+			}
+			t.foreignKeys = append(t.foreignKeys, fk)
+		}
+		t.columns = append(t.columns, c)
+		byProperty[p.name] = c
+	}
+
+	// The primary key is that of the definition, or else its columns marked as such, or else
+	// its id column
+	switch key := defMap["x-sql-primary-key"].(type) {
+	case string:
+		keyed = []string{key}
+	case []any:
+		keyed = nil
+		for _, name := range key {
+			if name, ok := name.(string); ok {
+				keyed = append(keyed, name)
+			}
+		}
+	}
+	if len(keyed) == 0 && byProperty["id"] != nil {
+		keyed = []string{byProperty["id"].name}
+	}
+	for _, name := range keyed {
+		c := columnOf(t.columns, byProperty, name)
+		if c == nil {
+			return fmt.Errorf("primary key column %s is not a property", name)
+		}
+		c.notNull = true
+		t.primaryKey = append(t.primaryKey, c.name)
+	}
+
+	indexes, _ := defMap["x-sql-indexes"].([]any)
+	for i, entry := range indexes {
+		entryMap, _ := entry.(map[string]any)
+		idx := index{unique: entryMap["unique"] == true}
+		idx.name, _ = entryMap["name"].(string)
+		for _, name := range stringList(entryMap["columns"]) {
+			c := columnOf(t.columns, byProperty, name)
+			if c == nil {
+				return fmt.Errorf("column %s of index %d is not a property", name, i+1)
+			}
+			idx.columns = append(idx.columns, c.name)
+		}
+		if len(idx.columns) == 0 {
+			return fmt.Errorf("index %d has no columns", i+1)
+		}
+		t.indexes = append(t.indexes, idx)
+	}
+	for i := range t.indexes {
+		if t.indexes[i].name == "" {
+			suffix := "idx"
+			if t.indexes[i].unique {
+				suffix = "key"
+			}
+			t.indexes[i].name = t.name + "_" + strings.Join(t.indexes[i].columns, "_") + "_" + suffix
+		}
+	}
+	return nil
+}
+
+// foreignKey returns the foreign key of a column from its x-sql-references: the table of a
+// definition, or a table, and the referenced column, which defaults to the primary key of the
+// table of a definition, or else id
+func (s *schema) foreignKey(name, reference string) (foreignKey, error) {
+	match := referencePattern.FindStringSubmatch(reference)
+	if match == nil {
+		return foreignKey{}, fmt.Errorf("invalid x-sql-references %q: must be <table> or <table>(<column>)", reference)
+	}
+	fk := foreignKey{column: name, table: match[1], reference: match[2]}
+	if target, ok := s.byName[match[1]]; ok {
+		fk.table = target.name
+		if fk.reference == "" {
+			defMap, _ := s.definitions[target.definition].(map[string]any)
+			properties, _ := s.properties(defMap)
+			for _, p := range properties {
+				if p.name == "id" || p.schema["x-sql-primary-key"] == true {
+					fk.reference = snakeCase(jrpc.GoIdentifier(p.name, s.options.GeneratorOptions))
+					if pinned, ok := p.schema["x-sql-column"].(string); ok && pinned != "" {
+						fk.reference = pinned
+					}
+					break
+				}
+			}
+		}
+	}
+	if fk.reference == "" {
+		fk.reference = "id"
+	}
+	return fk, nil
+}
+
+// columnType returns the column type of a schema, and the values an enum restricts it to
+func (s *schema) columnType(schema map[string]any) (string, []string) {
+	if schema == nil {
+		return s.mapped("", "object"), nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name := refName(ref)
+		target, _ := s.definitions[name].(map[string]any)
+		if _, isTable := s.byName[name]; isTable || target == nil || s.aliasing[name] {
+			return s.mapped("", "object"), nil
+		}
+		s.aliasing[name] = true
+		defer delete(s.aliasing, name)
+		return s.columnType(target)
+	}
+	if pinned, ok := schema["x-sql-type"].(string); ok && pinned != "" {
+		return pinned, nil
+	}
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) == 1 {
+		member, _ := allOf[0].(map[string]any)
+		return s.columnType(member)
+	}
+	// Unions of null and another schema are the column of that schema, others JSON
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok {
+			var others []map[string]any
+			for _, member := range members {
+				if member, _ := member.(map[string]any); member["type"] != "null" {
+					others = append(others, member)
+				}
+			}
+			if len(others) == 1 {
+				return s.columnType(others[0])
+			}
+			return s.mapped("", "object"), nil
+		}
+	}
+	if schema["allOf"] != nil {
+		return s.mapped("", "object"), nil
+	}
+
+	// A const is an enum of one value
+	values, hasValues := schema["enum"].([]any)
+	if value, ok := schema["const"]; ok && value != nil {
+		values, hasValues = []any{value}, true
+	}
+	kinds := schemaTypes(schema)
+	if len(kinds) != 1 {
+		if len(kinds) == 0 && hasValues {
+			kinds = []string{enumKind(values)}
+		} else {
+			return s.mapped("", "object"), nil
+		}
+	}
+	format, _ := schema["format"].(string)
+	kind := kinds[0]
+	switch kind {
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if _, ok := s.options.TypeMappings["array"]; ok || items == nil {
+			return s.mapped("", "array"), nil
+		}
+		itemType, _ := s.columnType(items)
+		if itemType == "JSONB" || strings.HasSuffix(itemType, "[]") {
+			return "JSONB", nil
+		}
+		return itemType + "[]", nil
+	case "object":
+		return s.mapped("", "object"), nil
+	}
+
+	typ := s.mapped(format, kind)
+	if typ == "TEXT" && format == "" {
+		if maxLength, ok := positiveInt(schema["maxLength"]); ok {
+			typ = "VARCHAR(" + strconv.Itoa(maxLength) + ")"
+		}
+	}
+	var check []string
+	for _, value := range values {
+		if value != nil {
+			if sql, ok := literal(value); ok {
+				check = append(check, sql)
+			}
+		}
+	}
+	return typ, check
+}
+
+// mapped returns the column type of a format of a JSON Schema type: that of the TypeMappings
+// of the format or type, or else the PostgreSQL type of the format or type. Formats of other
+// types, such as int32 strings, are ignored.
+func (s *schema) mapped(format, kind string) string {
+	if typ, ok := s.options.TypeMappings[format]; ok && format != "" {
+		return typ
+	}
+	if typ, ok := s.options.TypeMappings[kind]; ok {
+		return typ
+	}
+	if typ, ok := formatTypes[format]; ok && formatKind(format) == kind {
+		return typ
+	}
+	if typ, ok := kindTypes[kind]; ok {
+		return typ
+	}
+	return "JSONB"
+}
+
+// formatKind returns the JSON Schema type of the formats of formatTypes
+func formatKind(format string) string {
+	switch format {
+	case "int8", "int16", "uint8", "int32", "uint16", "int64":
+		return "integer"
+	case "float", "double":
+		return "number"
+	}
+	return "string"
+}
+
+// description returns the description of a schema, unless comments are left out
+func (s *schema) description(schema map[string]any) string {
+	if !s.options.IncludeComments {
+		return ""
+	}
+	description, _ := schema["description"].(string)
+	return strings.TrimSpace(description)
+}
+
+// property is a property of a definition
+type property struct {
+	name   string
+	schema map[string]any
+}
+
+// properties returns the properties of a schema, after those of its allOf members, and the
+// names of the required ones
+func (s *schema) properties(schema map[string]any) ([]property, map[string]bool) {
+	var properties []property
+	required := map[string]bool{}
+	seen := map[string]bool{}
+	var collect func(schema map[string]any, depth int)
+	collect = func(schema map[string]any, depth int) {
+		if schema == nil || depth > 16 {
+			return
+		}
+		allOf, _ := schema["allOf"].([]any)
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				target, _ := s.definitions[refName(ref)].(map[string]any)
+				collect(target, depth+1)
+			} else {
+				collect(member, depth+1)
+			}
+		}
+		for _, name := range stringList(schema["required"]) {
+			required[name] = true
+		}
+		all, _ := schema["properties"].(map[string]any)
+		for _, name := range jrpc.PropertyNames(schema) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			propertySchema, _ := all[name].(map[string]any)
+			properties = append(properties, property{name, propertySchema})
+		}
+	}
+	collect(schema, 0)
+	return properties, required
+}
+
+// isTable reports whether a definition is written as a table: an object with properties, or
+// an allOf with several members, unless x-sql-skip is set
+func isTable(schema map[string]any) bool {
+	if schema["x-sql-skip"] == true || schema["oneOf"] != nil || schema["anyOf"] != nil {
+		return false
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		return len(allOf) > 1 || schema["properties"] != nil
+	}
+	_, ok := schema["properties"]
+	return ok && len(schemaTypes(schema)) <= 1
+}
+
+// columnOf returns the column of a property, or the column with that name
+func columnOf(columns []*column, byProperty map[string]*column, name string) *column {
+	if c, ok := byProperty[name]; ok {
+		return c
+	}
+	for _, c := range columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// literal returns a JSON scalar other than null as an SQL literal
+func literal(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", true
+	case bool:
+		if value {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(value), true
+	case int64:
+		return strconv.FormatInt(value, 10), true
+	case uint64:
+		return strconv.FormatUint(value, 10), true
+	}
+	return "", false
+}
+
+// positiveInt returns a JSON number as an int, when it is a positive integer
+func positiveInt(value any) (int, bool) {
+	switch value := value.(type) {
+	case float64:
+		return int(value), value > 0 && value == float64(int(value))
+	case int:
+		return value, value > 0
+	}
+	return 0, false
+}
+
+// enumKind returns the JSON Schema type of the values of an enum or const without a type
+func enumKind(values []any) string {
+	for _, value := range values {
+		switch value := value.(type) {
+		case map[string]any:
+			return "object"
+		case []any:
+			return "array"
+		case float64:
+			if value == float64(int64(value)) {
+				return "integer"
+			}
+			return "number"
+		case int, int64, uint64:
+			return "integer"
+		case bool:
+			return "boolean"
+		case string:
+			return "string"
+		}
+	}
+	return "string"
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping acronyms whole (UserID is user_id)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '_' {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && !strings.HasSuffix(b.String(), "_") {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,103 @@
+// Package typescript provides a generator of TypeScript type definitions, declaring the same
+// types as the Go generators from the same schemas
+package typescript
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen"
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// TypeScriptGenerator implements the Generator interface for TypeScript type definitions
+type TypeScriptGenerator struct{}
+
+// Name returns the unique identifier for this generator
+func (g *TypeScriptGenerator) Name() string {
+	return "typescript"
+}
+
+// Description returns a human-readable description
+func (g *TypeScriptGenerator) Description() string {
+	return "Generates TypeScript interfaces, enums and types (.d.ts) from JSON Schema, OpenAPI and OpenRPC documents"
+}
+
+// SupportedFormats returns the file extensions this generator can process
+func (g *TypeScriptGenerator) SupportedFormats() []string {
+	return []string{".json", ".yaml", ".yml"}
+}
+
+// OutputFormats returns the file extensions this generator writes: .d.ts or .ts files
+func (g *TypeScriptGenerator) OutputFormats() []string {
+	return []string{".ts"}
+}
+
+// Options for the TypeScript generator
+type Options struct {
+	// GeneratorOptions select and name the definitions as for the Go types: IncludeTypes,
+	// ExcludeTypes, CustomAcronyms, Naming, PreserveOrder, ReadWriteVariants, the remote
+	// refs options and IncludeComments. The options of the Go code do not apply.
+	*jrpc.GeneratorOptions
+
+	// EnumStyle declares string enums as EnumStyleUnion (default) or EnumStyleEnum types
+	EnumStyle string
+}
+
+// Generate writes the TypeScript declarations of the definitions of the schema: interfaces
+// for objects, enums or union types for string enums, and type aliases for the others
+func (g *TypeScriptGenerator) Generate(config codegen.GenerateConfig) error {
+	options, _ := config.Options.(*Options)
+	if options == nil {
+		options = &Options{}
+	}
+	if options.GeneratorOptions == nil {
+		options.GeneratorOptions = &jrpc.GeneratorOptions{IncludeComments: true}
+	}
+	switch options.EnumStyle {
+	case "":
+		options.EnumStyle = EnumStyleUnion
+	case EnumStyleUnion, EnumStyleEnum:
+	default:
+		return fmt.Errorf("unsupported enum style %q: must be %s or %s", options.EnumStyle, EnumStyleUnion, EnumStyleEnum)
+	}
+	if options.SplitMode != "" {
+		return fmt.Errorf("split mode %q is not supported by the typescript generator", options.SplitMode)
+	}
+
+	definitions, err := jrpc.LoadDefinitions(config.SchemaPath, options.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	d := &declarations{
+		definitions: definitions,
+		options:     options,
+		constEnums:  strings.HasSuffix(config.OutputPath, ".d.ts"),
+	}
+	d.write()
+
+	if err := os.WriteFile(config.OutputPath, []byte(d.out.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// ValidateSchema checks that the schema declares definitions
+func (g *TypeScriptGenerator) ValidateSchema(schemaPath string) error {
+	_, err := jrpc.LoadDefinitions(schemaPath, nil)
+	return err
+}
+
+// NewTypeScriptGenerator creates a new instance of the TypeScript generator
+func NewTypeScriptGenerator() *TypeScriptGenerator {
+	return &TypeScriptGenerator{}
+}
+
+// Register automatically registers the TypeScript generator with the default registry
+func init() {
+	generator := NewTypeScriptGenerator()
+	if err := codegen.Register(generator); err != nil {
+		panic(fmt.Sprintf("Failed to register typescript generator: %v", err))
+	}
+}
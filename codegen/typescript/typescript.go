@@ -0,0 +1,458 @@
+package typescript
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-gateway/tools/codegen/jrpc"
+)
+
+// Enum declaration styles of EnumStyle
+const (
+	// EnumStyleUnion declares string enums as union types of their values:
+	// type Status = "active" | "archived"
+	EnumStyleUnion = "union"
+
+	// EnumStyleEnum declares string enums as TypeScript enums: const enums in .d.ts files,
+	// which have no runtime object for a regular enum, and regular enums in .ts files
+	EnumStyleEnum = "enum"
+)
+
+// identifierPattern matches the property names written without quotes
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// declarations writes the TypeScript declarations of the definitions of a schema
+type declarations struct {
+	definitions map[string]any
+	options     *Options
+	constEnums  bool // whether enums are declared const, as in .d.ts files
+	out         strings.Builder
+}
+
+// write writes a declaration per definition, in alphabetical order
+func (d *declarations) write() {
+	names := make([]string, 0, len(d.definitions))
+	for name := range d.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d.out.WriteString("// Code generated from JSON schema. DO NOT EDIT.\n")
+	for _, name := range names {
+		schema, ok := d.definitions[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		d.out.WriteString("\n")
+		d.comment("", schema)
+		typeName := d.typeName(name)
+		switch {
+		case d.options.EnumStyle == EnumStyleEnum && isStringEnum(schema):
+			d.enum(typeName, schema)
+		case d.isInterface(schema):
+			d.writeInterface(typeName, schema)
+		default:
+			fmt.Fprintf(&d.out, "export type %s = %s;\n", typeName, d.typeOf(schema))
+		}
+	}
+}
+
+// reservedTypeNames are the predefined types of TypeScript, which cannot be declared
+var reservedTypeNames = map[string]bool{
+	"any": true, "bigint": true, "boolean": true, "never": true, "null": true, "number": true,
+	"object": true, "string": true, "symbol": true, "undefined": true, "unknown": true, "void": true,
+}
+
+// typeName returns the TypeScript name of a definition: its name, as for its Go type, or the
+// Go identifier of names that are not identifiers. Predefined type names get a trailing
+// underscore, as Go keywords do.
+func (d *declarations) typeName(name string) string {
+	if !identifierPattern.MatchString(name) {
+		name = jrpc.GoIdentifier(name, d.options.GeneratorOptions)
+	}
+	if reservedTypeNames[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// isInterface reports whether a definition is declared as an interface: an object with
+// properties, or an allOf of such objects and of interfaces
+func (d *declarations) isInterface(schema map[string]any) bool {
+	if isNullable(schema) || schema["oneOf"] != nil || schema["anyOf"] != nil {
+		return false
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				target, _ := d.definitions[refName(ref)].(map[string]any)
+				if target == nil || !d.isInterface(target) {
+					return false
+				}
+			} else if member == nil || member["properties"] == nil || len(schemaTypes(member)) > 1 {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok := schema["properties"]
+	return ok && len(schemaTypes(schema)) <= 1
+}
+
+// writeInterface declares an interface extending the interfaces of its allOf, with the
+// properties of the schema and of its inline allOf members
+func (d *declarations) writeInterface(typeName string, schema map[string]any) {
+	var extends []string
+	members := []map[string]any{schema}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			if ref, ok := member["$ref"].(string); ok {
+				extends = append(extends, d.typeName(refName(ref)))
+			} else {
+				members = append(members, member)
+			}
+		}
+	}
+
+	fmt.Fprintf(&d.out, "export interface %s ", typeName)
+	if len(extends) > 0 {
+		fmt.Fprintf(&d.out, "extends %s ", strings.Join(extends, ", "))
+	}
+	required := map[string]bool{}
+	for _, member := range members {
+		for _, name := range stringList(member["required"]) {
+			required[name] = true
+		}
+	}
+	body := &declarations{definitions: d.definitions, options: d.options}
+	for _, member := range members {
+		properties, _ := member["properties"].(map[string]any)
+		for _, name := range jrpc.PropertyNames(member) {
+			property, _ := properties[name].(map[string]any)
+			body.comment("  ", property)
+			body.out.WriteString("  ")
+			if property["readOnly"] == true {
+				body.out.WriteString("readonly ")
+			}
+			body.out.WriteString(propertyKey(name))
+			if !required[name] {
+				body.out.WriteString("?")
+			}
+			fmt.Fprintf(&body.out, ": %s;\n", d.typeOf(property))
+		}
+	}
+	if additional := schema["additionalProperties"]; additional != nil && additional != false {
+		body.out.WriteString("  [key: string]: unknown;\n")
+	}
+	if body.out.Len() == 0 {
+		d.out.WriteString("{}\n")
+		return
+	}
+	fmt.Fprintf(&d.out, "{\n%s}\n", body.out.String())
+}
+
+// enum declares a string enum as a TypeScript enum, with a member per value named like its
+// Go constant
+func (d *declarations) enum(typeName string, schema map[string]any) {
+	keyword := "enum"
+	if d.constEnums {
+		keyword = "const enum"
+	}
+	fmt.Fprintf(&d.out, "export %s %s {\n", keyword, typeName)
+	taken := map[string]bool{}
+	for _, value := range schema["enum"].([]any) {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		member := jrpc.GoIdentifier(text, d.options.GeneratorOptions)
+		if member == "" || !identifierPattern.MatchString(member) {
+			member = "Value" + member
+		}
+		unique := member
+		for i := 2; taken[unique]; i++ {
+			unique = member + strconv.Itoa(i)
+		}
+		taken[unique] = true
+		fmt.Fprintf(&d.out, "  %s = %s,\n", unique, literal(text))
+	}
+	d.out.WriteString("}\n")
+}
+
+// typeOf returns the TypeScript type of a schema
+func (d *declarations) typeOf(schema map[string]any) string {
+	t := d.nonNullType(schema)
+	if isNullable(schema) && t != "unknown" && t != "null" {
+		return t + " | null"
+	}
+	return t
+}
+
+// nonNullType returns the TypeScript type of a schema, without its null
+func (d *declarations) nonNullType(schema map[string]any) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		return d.typeName(refName(ref))
+	}
+	if value, ok := schema["const"]; ok {
+		return literal(value)
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		var literals []string
+		for _, value := range values {
+			if value != nil {
+				literals = append(literals, literal(value))
+			}
+		}
+		return strings.Join(literals, " | ")
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if members, ok := schema[key].([]any); ok {
+			var types []string
+			for _, member := range members {
+				member, _ := member.(map[string]any)
+				if member["type"] == "null" {
+					continue
+				}
+				if t := d.typeOf(member); !slices.Contains(types, t) {
+					types = append(types, t)
+				}
+			}
+			if len(types) == 0 {
+				return "unknown"
+			}
+			return strings.Join(types, " | ")
+		}
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		var types []string
+		for _, member := range allOf {
+			member, _ := member.(map[string]any)
+			types = append(types, parenthesize(d.typeOf(member)))
+		}
+		if len(schemaTypes(schema)) == 1 && schema["properties"] != nil {
+			types = append(types, d.objectLiteral(schema))
+		}
+		return strings.Join(types, " & ")
+	}
+
+	types := schemaTypes(schema)
+	if len(types) == 0 {
+		if schema["properties"] != nil {
+			return d.objectLiteral(schema)
+		}
+		return "unknown"
+	}
+	var rendered []string
+	for _, t := range types {
+		rendered = append(rendered, d.typeOfKind(t, schema))
+	}
+	return strings.Join(rendered, " | ")
+}
+
+// typeOfKind returns the TypeScript type of a schema of a JSON Schema type
+func (d *declarations) typeOfKind(kind string, schema map[string]any) string {
+	switch kind {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		if items, ok := tupleItems(schema); ok {
+			var types []string
+			for _, item := range items {
+				item, _ := item.(map[string]any)
+				types = append(types, d.typeOf(item))
+			}
+			return "[" + strings.Join(types, ", ") + "]"
+		}
+		items, _ := schema["items"].(map[string]any)
+		return parenthesize(d.typeOf(items)) + "[]"
+	case "object":
+		if schema["properties"] != nil {
+			return d.objectLiteral(schema)
+		}
+		if values, ok := schema["additionalProperties"].(map[string]any); ok && len(values) > 0 {
+			return "Record<string, " + d.typeOf(values) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// objectLiteral returns the object type of an inline object schema
+func (d *declarations) objectLiteral(schema map[string]any) string {
+	properties, _ := schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	for _, name := range stringList(schema["required"]) {
+		required[name] = true
+	}
+	var members []string
+	for _, name := range jrpc.PropertyNames(schema) {
+		property, _ := properties[name].(map[string]any)
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		members = append(members, fmt.Sprintf("%s%s: %s", propertyKey(name), optional, d.typeOf(property)))
+	}
+	if len(members) == 0 {
+		return "Record<string, never>"
+	}
+	return "{ " + strings.Join(members, "; ") + " }"
+}
+
+// comment writes the description of a schema, and its deprecation, as a JSDoc comment
+func (d *declarations) comment(indent string, schema map[string]any) {
+	if !d.options.IncludeComments || schema == nil {
+		return
+	}
+	var lines []string
+	if description, ok := schema["description"].(string); ok {
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			lines = append(lines, strings.ReplaceAll(strings.TrimRight(line, " \t\r"), "*/", "*\\/"))
+		}
+	}
+	if schema["deprecated"] == true {
+		lines = append(lines, "@deprecated")
+	}
+	if len(lines) == 0 || len(lines) == 1 && lines[0] == "" {
+		return
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(&d.out, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(&d.out, "%s/**\n", indent)
+	for _, line := range lines {
+		if line == "" {
+			fmt.Fprintf(&d.out, "%s *\n", indent)
+		} else {
+			fmt.Fprintf(&d.out, "%s * %s\n", indent, line)
+		}
+	}
+	fmt.Fprintf(&d.out, "%s */\n", indent)
+}
+
+// schemaTypes returns the types of a schema, without null
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = []string{value}
+	case []any:
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			}
+		}
+	}
+	if len(types) > 1 {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	}
+	return types
+}
+
+// isNullable reports whether a schema accepts null next to its other values: a type array
+// with null, OpenAPI 3.0 nullable, a null oneOf or anyOf member, or a null enum value
+func isNullable(schema map[string]any) bool {
+	if schema["nullable"] == true {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok && len(types) > 1 && slices.Contains(types, any("null")) {
+		return true
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members, _ := schema[key].([]any)
+		for _, member := range members {
+			if member, ok := member.(map[string]any); ok && member["type"] == "null" {
+				return true
+			}
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		return slices.Contains(values, nil)
+	}
+	return false
+}
+
+// isStringEnum reports whether a schema is an enum of strings
+func isStringEnum(schema map[string]any) bool {
+	values, ok := schema["enum"].([]any)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tupleItems returns the positional items of a tuple schema: 2020-12 prefixItems or an
+// items array
+func tupleItems(schema map[string]any) ([]any, bool) {
+	if items, ok := schema["prefixItems"].([]any); ok {
+		return items, true
+	}
+	items, ok := schema["items"].([]any)
+	return items, ok
+}
+
+// parenthesize wraps union and intersection types, so that they can be followed by []
+// or intersected
+func parenthesize(t string) string {
+	if strings.Contains(t, " | ") || strings.Contains(t, " & ") {
+		return "(" + t + ")"
+	}
+	return t
+}
+
+// propertyKey returns a property name as an object type key, quoted unless it is an
+// identifier
+func propertyKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return literal(name)
+}
+
+// literal returns a JSON value as a TypeScript literal type
+func literal(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "unknown"
+	}
+	return string(data)
+}
+
+// refName returns the definition a $ref names in its last segment
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var list []string
+	for _, item := range items {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}